@@ -17,10 +17,11 @@ CREATE TABLE IF NOT EXISTS usuarios (
     apellido VARCHAR(100) NOT NULL,
     username VARCHAR(50) UNIQUE NOT NULL,
     email VARCHAR(100) UNIQUE NOT NULL,
-    password VARCHAR(255) NOT NULL COMMENT 'SHA-256 hash',
+    password VARCHAR(255) NOT NULL COMMENT 'argon2id (PHC) o SHA-256 legado',
     is_admin TINYINT(1) NOT NULL DEFAULT 0,
     tipo VARCHAR(20) NOT NULL DEFAULT 'cliente' COMMENT 'cliente o admin',
     sucursal_origen_id INT NULL,
+    email_verificado_en DATETIME NULL COMMENT 'NULL = email sin verificar',
     fecha_registro TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
@@ -28,22 +29,140 @@ CREATE TABLE IF NOT EXISTS usuarios (
     INDEX idx_email (email),
     INDEX idx_username (username),
     INDEX idx_tipo (tipo),
-    INDEX idx_sucursal_origen (sucursal_origen_id)
+    INDEX idx_sucursal_origen (sucursal_origen_id),
+    INDEX idx_fecha_registro (fecha_registro),
+    INDEX idx_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: refresh_tokens
+-- Refresh tokens persistidos (solo el hash SHA-256)
+-- Rotan en cada uso; reutilizar uno revoca toda su familia
+-- =====================================================
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id_refresh_token INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE COMMENT 'SHA-256 hex del refresh token',
+    familia_id CHAR(32) NOT NULL COMMENT 'Cadena de rotación (login -> refresh -> refresh...)',
+    access_jti CHAR(32) NOT NULL COMMENT 'jti del access token emitido junto con este refresh token',
+    access_expira_en DATETIME NOT NULL,
+    expira_en DATETIME NOT NULL,
+    usado_en DATETIME NULL,
+    revocado_en DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_usuario (usuario_id),
+    INDEX idx_familia (familia_id),
+    INDEX idx_access_jti (access_jti),
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id_usuario) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: tokens_revocados
+-- Access tokens (por jti) revocados antes de expirar
+-- Los demás microservicios la consultan vía GET /token/revocations
+-- =====================================================
+CREATE TABLE IF NOT EXISTS tokens_revocados (
+    jti CHAR(32) PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    expira_en DATETIME NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_expira (expira_en)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: tokens_cuenta
+-- Tokens de un solo uso enviados por email (solo el hash SHA-256)
+-- tipo: password_reset | email_verification | two_factor_login (challenge del login con 2FA)
+-- =====================================================
+CREATE TABLE IF NOT EXISTS tokens_cuenta (
+    id_token_cuenta INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    tipo VARCHAR(30) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE COMMENT 'SHA-256 hex del token',
+    expira_en DATETIME NOT NULL,
+    usado_en DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_usuario (usuario_id),
+    INDEX idx_expira (expira_en),
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id_usuario) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: intentos_login
+-- Logins fallidos por cuenta y por IP (protección contra fuerza bruta)
+-- Compartida entre réplicas de users-api: el bloqueo sobrevive reinicios
+-- =====================================================
+CREATE TABLE IF NOT EXISTS intentos_login (
+    id_intento_login INT AUTO_INCREMENT PRIMARY KEY,
+    tipo VARCHAR(10) NOT NULL COMMENT 'usuario | ip',
+    clave VARCHAR(64) NOT NULL COMMENT 'ID del usuario o IP del cliente',
+    fallos INT NOT NULL DEFAULT 0,
+    ultimo_fallo DATETIME NOT NULL,
+    bloqueado_hasta DATETIME NULL,
+    UNIQUE KEY uk_tipo_clave (tipo, clave),
+    INDEX idx_ultimo_fallo (ultimo_fallo)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: roles_usuario
+-- Roles de staff por sucursal: instructor | receptionist | branch_manager
+-- member lo tienen todos los usuarios y owner se guarda en usuarios.is_admin
+-- =====================================================
+CREATE TABLE IF NOT EXISTS roles_usuario (
+    id_rol_usuario INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    rol VARCHAR(30) NOT NULL COMMENT 'instructor | receptionist | branch_manager',
+    sucursal_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_usuario_rol_sucursal (usuario_id, rol, sucursal_id),
+    INDEX idx_sucursal (sucursal_id),
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id_usuario) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: dos_factores
+-- Segundo factor TOTP (RFC 6238), una fila por usuario
+-- activado_en NULL = inscripción pendiente de confirmar con un código
+-- =====================================================
+CREATE TABLE IF NOT EXISTS dos_factores (
+    usuario_id INT PRIMARY KEY,
+    secreto VARCHAR(64) NOT NULL COMMENT 'Secreto base32',
+    activado_en DATETIME NULL,
+    ultimo_paso BIGINT NOT NULL DEFAULT 0 COMMENT 'Último paso TOTP aceptado (anti-replay)',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id_usuario) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: codigos_recuperacion
+-- Códigos de recuperación del segundo factor, de un solo uso (solo el hash SHA-256)
+-- =====================================================
+CREATE TABLE IF NOT EXISTS codigos_recuperacion (
+    id_codigo_recuperacion INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    codigo_hash CHAR(64) NOT NULL UNIQUE COMMENT 'SHA-256 hex del código',
+    usado_en DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_usuario (usuario_id),
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id_usuario) ON DELETE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- =====================================================
 -- DATOS INICIALES: Usuarios
--- Password: admin123 (SHA-256) para admin
--- Password: password123 (SHA-256) para testuser
+-- Password: admin123 (SHA-256 legado) para admin
+-- Password: password123 (SHA-256 legado) para testuser
+-- Los hashes legados se migran a argon2id en el primer login
+-- Ambos usuarios tienen el email verificado
 -- =====================================================
-INSERT INTO usuarios (id_usuario, nombre, apellido, username, email, password, tipo, is_admin)
+INSERT INTO usuarios (id_usuario, nombre, apellido, username, email, password, tipo, is_admin, email_verificado_en)
 VALUES
     (1, 'Admin', 'Sistema', 'admin', 'admin@gym.com',
      '240be518fabd2724ddb6f04eeb1da5967448d7e831c08c8fa822809f74c720a9',
-     'admin', 1),
+     'admin', 1, NOW()),
     (5, 'Test', 'User', 'testuser', 'testuser@test.com',
      'ef92b778bafe771e89245b89ecbc08a44a4e166c06659911881f383d4473e94f',
-     'cliente', 0)
+     'cliente', 0, NOW())
 ON DUPLICATE KEY UPDATE username=username;
 
 -- =====================================================
@@ -20,12 +20,93 @@ CREATE TABLE IF NOT EXISTS sucursales (
     codigo_postal VARCHAR(10),
     horario_apertura TIME,
     horario_cierre TIME,
+    zona_horaria VARCHAR(64) NOT NULL DEFAULT 'America/Argentina/Buenos_Aires' COMMENT 'Zona IANA',
+    latitud DECIMAL(9,6) NULL,
+    longitud DECIMAL(9,6) NULL,
     activa BOOLEAN DEFAULT TRUE,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     INDEX idx_activa (activa)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- =====================================================
+-- TABLA: sucursal_horarios
+-- Horario de apertura de cada sucursal por día de la semana
+-- Un rango por día; los días sin fila la sucursal está cerrada
+-- =====================================================
+CREATE TABLE IF NOT EXISTS sucursal_horarios (
+    id_horario INT AUTO_INCREMENT PRIMARY KEY,
+    sucursal_id INT NOT NULL,
+    dia ENUM('Lunes', 'Martes', 'Miercoles', 'Jueves', 'Viernes', 'Sabado', 'Domingo') NOT NULL,
+    apertura TIME NOT NULL,
+    cierre TIME NOT NULL,
+    FOREIGN KEY (sucursal_id) REFERENCES sucursales(id_sucursal) ON DELETE CASCADE,
+    UNIQUE KEY unique_sucursal_dia (sucursal_id, dia)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: instructores
+-- Perfil de los instructores; las actividades los referencian por ID
+-- usuario_id vincula la cuenta de users-api (rol instructor) que ve los inscriptos de sus clases
+-- =====================================================
+CREATE TABLE IF NOT EXISTS instructores (
+    id_instructor INT AUTO_INCREMENT PRIMARY KEY,
+    nombre VARCHAR(100) NOT NULL,
+    bio TEXT,
+    foto_url VARCHAR(511),
+    especialidades JSON NULL COMMENT 'Lista de especialidades (yoga, spinning, ...)',
+    usuario_id INT NULL COMMENT 'Cuenta del instructor en users-api',
+    activo BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_usuario (usuario_id),
+    INDEX idx_activo (activo)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: instructor_sucursales
+-- Sucursales en las que puede dar clases cada instructor (sin filas = todas)
+-- =====================================================
+CREATE TABLE IF NOT EXISTS instructor_sucursales (
+    instructor_id INT NOT NULL,
+    sucursal_id INT NOT NULL,
+    PRIMARY KEY (instructor_id, sucursal_id),
+    FOREIGN KEY (instructor_id) REFERENCES instructores(id_instructor) ON DELETE CASCADE,
+    FOREIGN KEY (sucursal_id) REFERENCES sucursales(id_sucursal) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: salas
+-- Salas de cada sucursal; la capacidad limita el cupo de las actividades que se dictan ahí
+-- =====================================================
+CREATE TABLE IF NOT EXISTS salas (
+    id_sala INT AUTO_INCREMENT PRIMARY KEY,
+    sucursal_id INT NOT NULL,
+    nombre VARCHAR(60) NOT NULL,
+    capacidad INT NOT NULL,
+    activa BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (sucursal_id) REFERENCES sucursales(id_sucursal) ON DELETE CASCADE,
+    UNIQUE KEY uk_sucursal_nombre (sucursal_id, nombre)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: equipos
+-- Equipos reservables de una sala (ej: bicicleta 7 de la sala de spinning)
+-- =====================================================
+CREATE TABLE IF NOT EXISTS equipos (
+    id_equipo INT AUTO_INCREMENT PRIMARY KEY,
+    sala_id INT NOT NULL,
+    tipo VARCHAR(40) NOT NULL COMMENT 'bicicleta, reformer, ...',
+    numero INT NOT NULL,
+    activo BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (sala_id) REFERENCES salas(id_sala) ON DELETE CASCADE,
+    UNIQUE KEY uk_sala_tipo_numero (sala_id, tipo, numero)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 -- =====================================================
 -- TABLA: actividades
 -- Gestiona las actividades ofrecidas en cada sucursal
@@ -40,41 +121,312 @@ CREATE TABLE IF NOT EXISTS actividades (
     horario_final DATETIME NOT NULL,
     foto_url VARCHAR(255),
     instructor VARCHAR(100),
+    instructor_id INT NULL COMMENT 'ID del usuario instructor en users-api',
+    instructor_perfil_id INT NULL COMMENT 'Instructor (tabla instructores) que dicta la clase',
     categoria VARCHAR(50) COMMENT 'yoga, spinning, funcional, etc.',
     sucursal_id INT,
+    sala_id INT NULL COMMENT 'Sala donde se dicta (NULL = sin sala asignada)',
     activa BOOLEAN DEFAULT TRUE,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     deleted_at TIMESTAMP NULL COMMENT 'Soft delete timestamp',
     FOREIGN KEY (sucursal_id) REFERENCES sucursales(id_sucursal) ON DELETE SET NULL,
+    FOREIGN KEY (instructor_perfil_id) REFERENCES instructores(id_instructor) ON DELETE SET NULL,
+    FOREIGN KEY (sala_id) REFERENCES salas(id_sala) ON DELETE SET NULL,
+    INDEX idx_instructor_perfil_dia (instructor_perfil_id, dia),
+    INDEX idx_sala_dia (sala_id, dia),
     INDEX idx_categoria (categoria),
     INDEX idx_dia (dia),
     INDEX idx_sucursal (sucursal_id),
+    INDEX idx_instructor (instructor_id),
     INDEX idx_activa (activa),
     INDEX idx_deleted_at (deleted_at)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- =====================================================
+-- TABLA: sesiones
+-- Ocurrencias fechadas de cada actividad (ej: el spinning del martes 14)
+-- activities-api las genera para los próximos días a partir de dia/horario
+-- =====================================================
+CREATE TABLE IF NOT EXISTS sesiones (
+    id_sesion INT AUTO_INCREMENT PRIMARY KEY,
+    actividad_id INT NOT NULL,
+    fecha DATE NOT NULL,
+    horario_inicio DATETIME NOT NULL,
+    horario_final DATETIME NOT NULL,
+    cupo INT NOT NULL,
+    instructor VARCHAR(100),
+    instructor_id INT NULL COMMENT 'Reemplazo puntual o el instructor de la actividad',
+    estado ENUM('programada', 'cancelada') NOT NULL DEFAULT 'programada',
+    motivo_cancelacion VARCHAR(255) NULL,
+    personalizada BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Cambiada a mano: la generación no la pisa',
+    fecha_original DATE NULL COMMENT 'Fecha que le tocaba si se reprogramó (la generación no la vuelve a crear)',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (actividad_id) REFERENCES actividades(id_actividad) ON DELETE CASCADE,
+    UNIQUE KEY uk_actividad_fecha (actividad_id, fecha),
+    INDEX idx_fecha (fecha),
+    INDEX idx_actividad_fecha_original (actividad_id, fecha_original),
+    INDEX idx_instructor (instructor_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
 -- =====================================================
 -- TABLA: inscripciones
 -- Gestiona las inscripciones de usuarios a actividades
+-- sesion_id NULL = inscripción fija semanal; si no, reserva de una sola sesión
+-- sesion_clave (0 para las fijas) permite que la clave única cubra también las fijas:
+-- en MySQL dos NULL no chocan en un UNIQUE
 -- =====================================================
 CREATE TABLE IF NOT EXISTS inscripciones (
     id_inscripcion INT AUTO_INCREMENT PRIMARY KEY,
     usuario_id INT NOT NULL,
     actividad_id INT NOT NULL,
+    sesion_id INT NULL COMMENT 'Reserva de una sesión puntual',
+    sesion_clave INT AS (COALESCE(sesion_id, 0)) STORED COMMENT 'sesion_id o 0 para la clave única',
     suscripcion_id VARCHAR(50) NULL COMMENT 'ID de suscripción de MongoDB',
+    plan_id VARCHAR(50) NULL COMMENT 'Plan de la suscripción al inscribirse (elige la política de cancelación)',
     is_activa BOOLEAN DEFAULT TRUE,
     fecha_inscripcion TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     deleted_at TIMESTAMP NULL COMMENT 'Soft delete timestamp',
     FOREIGN KEY (actividad_id) REFERENCES actividades(id_actividad) ON DELETE CASCADE,
+    FOREIGN KEY (sesion_id) REFERENCES sesiones(id_sesion) ON DELETE CASCADE,
     INDEX idx_usuario (usuario_id),
     INDEX idx_actividad (actividad_id),
+    INDEX idx_sesion (sesion_id),
     INDEX idx_activa (is_activa),
     INDEX idx_suscripcion (suscripcion_id),
     INDEX idx_deleted_at (deleted_at),
-    UNIQUE KEY unique_usuario_actividad (usuario_id, actividad_id)
+    UNIQUE KEY unique_usuario_actividad_sesion (usuario_id, actividad_id, sesion_clave)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: reservas_equipo
+-- Equipo elegido por un inscripto para una sesión (ej: bicicleta 7 del martes 14)
+-- Un equipo por sesión y un equipo por usuario en cada sesión
+-- =====================================================
+CREATE TABLE IF NOT EXISTS reservas_equipo (
+    id_reserva INT AUTO_INCREMENT PRIMARY KEY,
+    equipo_id INT NOT NULL,
+    sesion_id INT NOT NULL,
+    usuario_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (equipo_id) REFERENCES equipos(id_equipo) ON DELETE CASCADE,
+    FOREIGN KEY (sesion_id) REFERENCES sesiones(id_sesion) ON DELETE CASCADE,
+    UNIQUE KEY uk_equipo_sesion (equipo_id, sesion_id),
+    UNIQUE KEY uk_usuario_sesion (sesion_id, usuario_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: lista_espera
+-- Cola de usuarios esperando lugar en una actividad llena (o en una sesión)
+-- Al liberarse un lugar se inscribe al primero elegible y queda "ofrecida" hasta que confirme
+-- =====================================================
+CREATE TABLE IF NOT EXISTS lista_espera (
+    id_lista_espera INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    actividad_id INT NOT NULL,
+    sesion_id INT NULL COMMENT 'Espera lugar en una sesión puntual (NULL = inscripción fija)',
+    suscripcion_id VARCHAR(50) NULL COMMENT 'ID de suscripción de MongoDB',
+    limite_semanal INT NOT NULL DEFAULT 0 COMMENT 'Límite semanal del plan al anotarse (0 = sin límite)',
+    estado ENUM('esperando', 'ofrecida', 'confirmada', 'vencida', 'omitida', 'cancelada') NOT NULL DEFAULT 'esperando',
+    ofrecida_en DATETIME NULL,
+    vence_en DATETIME NULL COMMENT 'Límite para confirmar el lugar ofrecido',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (actividad_id) REFERENCES actividades(id_actividad) ON DELETE CASCADE,
+    FOREIGN KEY (sesion_id) REFERENCES sesiones(id_sesion) ON DELETE CASCADE,
+    INDEX idx_actividad_sesion_estado (actividad_id, sesion_id, estado),
+    INDEX idx_usuario (usuario_id),
+    INDEX idx_vence_en (vence_en)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: asistencias
+-- Check-in de los socios con su QR: a una sesión (tipo clase) o a sala libre (tipo libre)
+-- El job de ausentes agrega estado 'ausente' para los inscriptos sin check-in
+-- La clave única permite un registro por sesión y uno de sala libre por día
+-- =====================================================
+CREATE TABLE IF NOT EXISTS asistencias (
+    id_asistencia INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    actividad_id INT NULL COMMENT 'NULL = sala libre',
+    sesion_id INT NULL COMMENT 'NULL = sala libre',
+    sesion_clave INT AS (COALESCE(sesion_id, 0)) STORED COMMENT 'sesion_id o 0 para la clave única',
+    sucursal_id INT NULL,
+    tipo ENUM('clase', 'libre') NOT NULL,
+    estado ENUM('presente', 'ausente') NOT NULL DEFAULT 'presente',
+    registrada_por INT NULL COMMENT 'Staff que escaneó el QR (NULL = marcada por el job de ausentes)',
+    fecha DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (actividad_id) REFERENCES actividades(id_actividad) ON DELETE CASCADE,
+    FOREIGN KEY (sesion_id) REFERENCES sesiones(id_sesion) ON DELETE CASCADE,
+    FOREIGN KEY (sucursal_id) REFERENCES sucursales(id_sucursal) ON DELETE SET NULL,
+    INDEX idx_actividad (actividad_id),
+    INDEX idx_sesion (sesion_id),
+    INDEX idx_usuario_fecha (usuario_id, fecha),
+    UNIQUE KEY unique_usuario_sesion_fecha (usuario_id, sesion_clave, fecha)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: politicas_cancelacion
+-- Anticipación mínima para cancelar sin penalización y bloqueo por strikes
+-- Una política por plan, una por categoría y una general (valor vacío);
+-- se aplica la del plan, si no la de la categoría y si no la general
+-- =====================================================
+CREATE TABLE IF NOT EXISTS politicas_cancelacion (
+    id_politica INT AUTO_INCREMENT PRIMARY KEY,
+    alcance ENUM('plan', 'categoria', 'general') NOT NULL,
+    valor VARCHAR(100) NOT NULL DEFAULT '' COMMENT 'ID del plan o categoría (vacío en la general)',
+    minutos_limite INT NOT NULL DEFAULT 0 COMMENT 'Cancelar con menos anticipación suma un strike (0 = nunca)',
+    penalizar_ausentes BOOLEAN NOT NULL DEFAULT TRUE,
+    strikes_bloqueo INT NOT NULL DEFAULT 0 COMMENT 'Strikes que bloquean las inscripciones (0 = no bloquea)',
+    dias_bloqueo INT NOT NULL DEFAULT 0,
+    dias_vigencia_strike INT NOT NULL DEFAULT 30 COMMENT 'Los strikes más viejos no cuentan para el bloqueo (0 = no vencen)',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_alcance_valor (alcance, valor)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: bloqueos_reserva
+-- Bloqueo temporal de inscripciones al juntar strikes_bloqueo strikes
+-- =====================================================
+CREATE TABLE IF NOT EXISTS bloqueos_reserva (
+    id_bloqueo INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    hasta DATETIME NOT NULL,
+    levantado BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Un admin anuló uno de sus strikes',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_usuario_hasta (usuario_id, hasta)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: strikes
+-- Cancelaciones tardías y ausencias penalizadas; bloqueo_id marca los strikes
+-- que ya dispararon un bloqueo. Un strike por usuario, sesión y tipo
+-- =====================================================
+CREATE TABLE IF NOT EXISTS strikes (
+    id_strike INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    tipo ENUM('cancelacion_tardia', 'ausente') NOT NULL,
+    actividad_id INT NOT NULL,
+    sesion_id INT NOT NULL,
+    politica_id INT NULL,
+    bloqueo_id INT NULL,
+    anulado BOOLEAN NOT NULL DEFAULT FALSE,
+    anulado_por INT NULL COMMENT 'Admin que anuló el strike',
+    motivo_anulacion VARCHAR(255) NULL,
+    anulado_en DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (actividad_id) REFERENCES actividades(id_actividad) ON DELETE CASCADE,
+    FOREIGN KEY (sesion_id) REFERENCES sesiones(id_sesion) ON DELETE CASCADE,
+    FOREIGN KEY (politica_id) REFERENCES politicas_cancelacion(id_politica) ON DELETE SET NULL,
+    FOREIGN KEY (bloqueo_id) REFERENCES bloqueos_reserva(id_bloqueo) ON DELETE SET NULL,
+    INDEX idx_usuario_created (usuario_id, created_at),
+    INDEX idx_bloqueo (bloqueo_id),
+    UNIQUE KEY uk_usuario_sesion_tipo (usuario_id, tipo, sesion_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: calendario_tokens
+-- Token secreto de la URL del calendario .ics de cada socio
+-- (regenerarlo reemplaza el anterior)
+-- =====================================================
+CREATE TABLE IF NOT EXISTS calendario_tokens (
+    usuario_id INT PRIMARY KEY,
+    token VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_token (token)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLAS: planes y suscripciones
+-- Copia local de subscriptions-api (eventos subscription.* y plan.*)
+-- evento_en es el momento del último evento aplicado: descarta los que llegan tarde
+-- =====================================================
+CREATE TABLE IF NOT EXISTS planes (
+    id_plan VARCHAR(24) PRIMARY KEY,
+    nombre VARCHAR(100) NOT NULL DEFAULT '',
+    tipo_acceso VARCHAR(20) NOT NULL DEFAULT '',
+    actividades_permitidas JSON NULL,
+    actividades_por_semana INT NOT NULL DEFAULT 0,
+    cupo_clases INT NOT NULL DEFAULT 0 COMMENT 'Clases por período (0 = usa actividades_por_semana)',
+    cupo_periodo VARCHAR(10) NOT NULL DEFAULT '' COMMENT 'diario | semanal | mensual',
+    activo BOOLEAN NOT NULL DEFAULT TRUE,
+    evento_en DATETIME(6) NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS suscripciones (
+    id_suscripcion VARCHAR(24) PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    plan_id VARCHAR(24) NOT NULL DEFAULT '',
+    estado VARCHAR(30) NOT NULL,
+    fecha_inicio DATETIME NULL,
+    fecha_vencimiento DATETIME NULL,
+    evento_en DATETIME(6) NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_usuario (usuario_id),
+    INDEX idx_estado (estado)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: consumos_cupo
+-- Clases descontadas del cupo del plan (una por inscripción y sesión)
+-- El período (día, semana ISO o mes) sale de la fecha de la clase, no de la inscripción
+-- =====================================================
+CREATE TABLE IF NOT EXISTS consumos_cupo (
+    id_consumo INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    suscripcion_id VARCHAR(24) NOT NULL DEFAULT '',
+    inscripcion_id INT NOT NULL,
+    actividad_id INT NOT NULL,
+    sesion_id INT NOT NULL,
+    fecha_clase DATE NOT NULL,
+    periodo VARCHAR(10) NOT NULL,
+    periodo_clave VARCHAR(10) NOT NULL COMMENT '2025-03-10 | 2025-W11 | 2025-03',
+    estado ENUM('usado', 'reintegrado') NOT NULL DEFAULT 'usado',
+    motivo_reintegro VARCHAR(30) NULL,
+    reintegrado_en DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_inscripcion_sesion (inscripcion_id, sesion_id),
+    INDEX idx_usuario_fecha (usuario_id, fecha_clase),
+    INDEX idx_sesion (sesion_id),
+    INDEX idx_suscripcion (suscripcion_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =====================================================
+-- TABLA: calificaciones
+-- Puntaje (1 a 5) y comentario de un socio sobre una sesión a la que asistió o estaba inscripto.
+-- Una por socio y sesión; las ocultas por moderación no cuentan en los promedios
+-- =====================================================
+CREATE TABLE IF NOT EXISTS calificaciones (
+    id_calificacion INT AUTO_INCREMENT PRIMARY KEY,
+    sesion_id INT NOT NULL,
+    actividad_id INT NOT NULL,
+    usuario_id INT NOT NULL,
+    instructor_perfil_id INT NULL COMMENT 'Instructor que dictó la sesión (NULL = reemplazo sin perfil)',
+    puntaje TINYINT NOT NULL COMMENT '1 a 5',
+    comentario VARCHAR(1000) NULL,
+    oculta BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Oculta por moderación: no cuenta en los promedios',
+    motivo_ocultamiento VARCHAR(255) NULL,
+    marcada BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Marcada para revisar',
+    motivo_marca VARCHAR(255) NULL,
+    moderada_por INT NULL COMMENT 'Admin de la última moderación',
+    moderada_en DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (sesion_id) REFERENCES sesiones(id_sesion) ON DELETE CASCADE,
+    FOREIGN KEY (actividad_id) REFERENCES actividades(id_actividad) ON DELETE CASCADE,
+    FOREIGN KEY (instructor_perfil_id) REFERENCES instructores(id_instructor) ON DELETE SET NULL,
+    UNIQUE KEY uk_sesion_usuario (sesion_id, usuario_id),
+    INDEX idx_actividad_oculta (actividad_id, oculta),
+    INDEX idx_instructor_oculta (instructor_perfil_id, oculta),
+    INDEX idx_marcada (marcada)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- =====================================================
@@ -87,6 +439,16 @@ VALUES
     ('Sucursal Palermo', 'Av. Santa Fe 3500', '+54 11 4567-8902', 'Buenos Aires', '1425', '06:30:00', '22:30:00')
 ON DUPLICATE KEY UPDATE nombre=nombre;
 
+-- Horario semanal de las sucursales iniciales (mismo rango todos los días)
+INSERT IGNORE INTO sucursal_horarios (sucursal_id, dia, apertura, cierre)
+SELECT s.id_sucursal, d.dia, s.horario_apertura, s.horario_cierre
+FROM sucursales s
+CROSS JOIN (
+    SELECT 'Lunes' AS dia UNION ALL SELECT 'Martes' UNION ALL SELECT 'Miercoles' UNION ALL SELECT 'Jueves'
+    UNION ALL SELECT 'Viernes' UNION ALL SELECT 'Sabado' UNION ALL SELECT 'Domingo'
+) d
+WHERE s.horario_apertura IS NOT NULL AND s.horario_cierre IS NOT NULL;
+
 -- =====================================================
 -- DATOS INICIALES: Actividades de ejemplo
 -- =====================================================
@@ -117,11 +479,60 @@ VALUES
     ('Zumba', 'Baile fitness con ritmos latinos. Quema calorias mientras te diviertes.', 30, 'Sabado', '2024-01-01 11:00:00', '2024-01-01 12:00:00', 'Sofia Fernandez', 'baile', 3, 'https://images.unsplash.com/photo-1518310383802-640c2de311b2')
 ON DUPLICATE KEY UPDATE titulo=titulo;
 
+-- Un instructor por cada nombre de las actividades de ejemplo, vinculado a sus actividades
+INSERT INTO instructores (nombre, especialidades)
+SELECT a.instructor, CAST(CONCAT('["', GROUP_CONCAT(DISTINCT a.categoria SEPARATOR '","'), '"]') AS JSON)
+FROM actividades a
+WHERE a.instructor IS NOT NULL AND a.instructor <> ''
+  AND NOT EXISTS (SELECT 1 FROM instructores i WHERE i.nombre = a.instructor)
+GROUP BY a.instructor;
+
+UPDATE actividades a
+JOIN instructores i ON i.nombre = a.instructor
+SET a.instructor_perfil_id = i.id_instructor
+WHERE a.instructor_perfil_id IS NULL;
+
+INSERT IGNORE INTO instructor_sucursales (instructor_id, sucursal_id)
+SELECT DISTINCT a.instructor_perfil_id, a.sucursal_id
+FROM actividades a
+WHERE a.instructor_perfil_id IS NOT NULL AND a.sucursal_id IS NOT NULL;
+
+-- Salas de ejemplo: una sala de spinning con 15 bicicletas y un estudio de pilates con 12 reformers
+INSERT IGNORE INTO salas (sucursal_id, nombre, capacidad)
+SELECT DISTINCT a.sucursal_id, 'Sala de spinning', 15
+FROM actividades a
+WHERE a.categoria = 'spinning' AND a.sucursal_id IS NOT NULL;
+
+INSERT IGNORE INTO salas (sucursal_id, nombre, capacidad)
+SELECT DISTINCT a.sucursal_id, 'Estudio de pilates', 12
+FROM actividades a
+WHERE a.titulo = 'Pilates Reformer' AND a.sucursal_id IS NOT NULL;
+
+INSERT IGNORE INTO equipos (sala_id, tipo, numero)
+WITH RECURSIVE numeros (n) AS (
+    SELECT 1
+    UNION ALL
+    SELECT n + 1 FROM numeros WHERE n < 15
+)
+SELECT s.id_sala, IF(s.nombre = 'Sala de spinning', 'bicicleta', 'reformer'), numeros.n
+FROM salas s
+JOIN numeros ON numeros.n <= s.capacidad
+WHERE s.nombre IN ('Sala de spinning', 'Estudio de pilates');
+
+UPDATE actividades a
+JOIN salas s ON s.sucursal_id = a.sucursal_id
+SET a.sala_id = s.id_sala
+WHERE a.sala_id IS NULL
+  AND ((a.categoria = 'spinning' AND s.nombre = 'Sala de spinning')
+       OR (a.titulo = 'Pilates Reformer' AND s.nombre = 'Estudio de pilates'));
+
 -- =====================================================
 -- VISTA: actividades_lugares
 -- Calcula los lugares disponibles para cada actividad
--- Formula: cupo - cantidad de inscripciones activas
+-- Formula: cupo - cantidad de inscripciones fijas activas
+-- (las reservas de una sesión ocupan lugar solo en sesiones_lugares)
 -- Incluye JOIN con sucursales para obtener el nombre
+-- y los promedios de calificaciones visibles de la actividad y de su instructor
 -- =====================================================
 CREATE OR REPLACE VIEW actividades_lugares AS
 SELECT
@@ -134,8 +545,11 @@ SELECT
     a.horario_final,
     a.foto_url,
     a.instructor,
+    a.instructor_id,
+    a.instructor_perfil_id,
     a.categoria,
     a.sucursal_id,
+    a.sala_id,
     COALESCE(s.nombre, '') AS sucursal_nombre,
     a.activa,
     a.created_at,
@@ -144,14 +558,66 @@ SELECT
         (SELECT COUNT(*)
          FROM inscripciones i
          WHERE i.actividad_id = a.id_actividad
+           AND i.sesion_id IS NULL
            AND i.is_activa = TRUE
            AND i.deleted_at IS NULL
         ), 0)
-    ) AS lugares
+    ) AS lugares,
+    COALESCE((SELECT ROUND(AVG(c.puntaje), 2) FROM calificaciones c
+              WHERE c.actividad_id = a.id_actividad AND c.oculta = FALSE), 0) AS calificacion_promedio,
+    (SELECT COUNT(*) FROM calificaciones c
+     WHERE c.actividad_id = a.id_actividad AND c.oculta = FALSE) AS calificaciones,
+    COALESCE((SELECT ROUND(AVG(c.puntaje), 2) FROM calificaciones c
+              WHERE c.instructor_perfil_id = a.instructor_perfil_id AND c.oculta = FALSE), 0) AS instructor_calificacion_promedio,
+    (SELECT COUNT(*) FROM calificaciones c
+     WHERE c.instructor_perfil_id = a.instructor_perfil_id AND c.oculta = FALSE) AS instructor_calificaciones
 FROM actividades a
 LEFT JOIN sucursales s ON a.sucursal_id = s.id_sucursal
 WHERE a.deleted_at IS NULL;
 
+-- =====================================================
+-- VISTA: sesiones_lugares
+-- Calcula los lugares disponibles de cada sesión
+-- Formula: cupo de la sesión - inscripciones fijas de la actividad - reservas de la sesión
+-- Una sesión cancelada no tiene lugares
+-- =====================================================
+CREATE OR REPLACE VIEW sesiones_lugares AS
+SELECT
+    x.*,
+    CASE
+        WHEN x.estado = 'cancelada' THEN 0
+        ELSE GREATEST(x.cupo - x.ocupados, 0)
+    END AS lugares
+FROM (
+    SELECT
+        se.id_sesion,
+        se.actividad_id,
+        a.titulo,
+        a.sucursal_id,
+        se.fecha,
+        se.horario_inicio,
+        se.horario_final,
+        se.cupo,
+        se.instructor,
+        se.instructor_id,
+        se.estado,
+        se.motivo_cancelacion,
+        se.personalizada,
+        se.fecha_original,
+        se.created_at,
+        se.updated_at,
+        (SELECT COUNT(*)
+         FROM inscripciones i
+         WHERE i.is_activa = TRUE
+           AND i.deleted_at IS NULL
+           AND ((i.actividad_id = se.actividad_id AND i.sesion_id IS NULL)
+                OR i.sesion_id = se.id_sesion)
+        ) AS ocupados
+    FROM sesiones se
+    JOIN actividades a ON se.actividad_id = a.id_actividad
+    WHERE a.deleted_at IS NULL
+) x;
+
 -- =====================================================
 -- MENSAJE DE CONFIRMACIÓN
 -- =====================================================
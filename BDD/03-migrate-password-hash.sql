@@ -0,0 +1,15 @@
+-- =====================================================
+-- MIGRACIÓN: hashes de contraseña argon2id
+-- Las tablas creadas con GORM tenían password CHAR(64) (SHA-256 hex).
+-- Los hashes argon2id en formato PHC ocupan ~100 caracteres.
+-- Los hashes SHA-256 existentes se siguen aceptando y se
+-- re-hashean con argon2id en el próximo login exitoso.
+-- =====================================================
+
+USE gym_users;
+
+ALTER TABLE usuarios
+    MODIFY password VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL
+    COMMENT 'argon2id (PHC) o SHA-256 legado';
+
+SELECT '✅ Columna usuarios.password migrada a VARCHAR(255)' AS Status;
@@ -0,0 +1,39 @@
+-- =====================================================
+-- MIGRACIÓN: verificación de email y reset de contraseña
+-- Agrega usuarios.email_verificado_en y la tabla tokens_cuenta.
+-- Los usuarios existentes quedan verificados (se registraron
+-- antes de que existiera la verificación).
+-- Idempotente: en una base nueva 01-init-users.sql ya los crea.
+-- =====================================================
+
+USE gym_users;
+
+SET @tiene_columna := (
+    SELECT COUNT(*) FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = 'gym_users' AND TABLE_NAME = 'usuarios' AND COLUMN_NAME = 'email_verificado_en'
+);
+
+SET @sql := IF(@tiene_columna = 0,
+    'ALTER TABLE usuarios ADD COLUMN email_verificado_en DATETIME NULL COMMENT ''NULL = email sin verificar'' AFTER sucursal_origen_id',
+    'SELECT 1');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+UPDATE usuarios SET email_verificado_en = fecha_registro
+WHERE @tiene_columna = 0 AND email_verificado_en IS NULL;
+
+CREATE TABLE IF NOT EXISTS tokens_cuenta (
+    id_token_cuenta INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    tipo VARCHAR(30) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE COMMENT 'SHA-256 hex del token',
+    expira_en DATETIME NOT NULL,
+    usado_en DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_usuario (usuario_id),
+    INDEX idx_expira (expira_en),
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id_usuario) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SELECT '✅ Verificación de email y tokens_cuenta migrados' AS Status;
@@ -0,0 +1,20 @@
+-- =====================================================
+-- MIGRACIÓN: protección contra fuerza bruta en el login
+-- Agrega la tabla intentos_login (fallos por cuenta y por IP).
+-- Idempotente: en una base nueva 01-init-users.sql ya la crea.
+-- =====================================================
+
+USE gym_users;
+
+CREATE TABLE IF NOT EXISTS intentos_login (
+    id_intento_login INT AUTO_INCREMENT PRIMARY KEY,
+    tipo VARCHAR(10) NOT NULL COMMENT 'usuario | ip',
+    clave VARCHAR(64) NOT NULL COMMENT 'ID del usuario o IP del cliente',
+    fallos INT NOT NULL DEFAULT 0,
+    ultimo_fallo DATETIME NOT NULL,
+    bloqueado_hasta DATETIME NULL,
+    UNIQUE KEY uk_tipo_clave (tipo, clave),
+    INDEX idx_ultimo_fallo (ultimo_fallo)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SELECT '✅ Tabla intentos_login migrada' AS Status;
@@ -0,0 +1,31 @@
+-- =====================================================
+-- MIGRACIÓN: índices para filtrar y ordenar GET /users
+-- Agrega índices sobre usuarios.fecha_registro y usuarios.deleted_at.
+-- Idempotente: en una base nueva 01-init-users.sql ya los crea.
+-- =====================================================
+
+USE gym_users;
+
+SET @tiene_indice := (
+    SELECT COUNT(*) FROM information_schema.STATISTICS
+    WHERE TABLE_SCHEMA = 'gym_users' AND TABLE_NAME = 'usuarios' AND INDEX_NAME = 'idx_fecha_registro'
+);
+SET @sql := IF(@tiene_indice = 0,
+    'ALTER TABLE usuarios ADD INDEX idx_fecha_registro (fecha_registro)',
+    'SELECT 1');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @tiene_indice := (
+    SELECT COUNT(*) FROM information_schema.STATISTICS
+    WHERE TABLE_SCHEMA = 'gym_users' AND TABLE_NAME = 'usuarios' AND INDEX_NAME = 'idx_deleted_at'
+);
+SET @sql := IF(@tiene_indice = 0,
+    'ALTER TABLE usuarios ADD INDEX idx_deleted_at (deleted_at)',
+    'SELECT 1');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SELECT '✅ Índices de usuarios para GET /users migrados' AS Status;
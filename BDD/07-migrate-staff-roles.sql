@@ -0,0 +1,65 @@
+-- =====================================================
+-- MIGRACIÓN: roles de staff por sucursal
+-- Crea gym_users.roles_usuario (instructor, receptionist, branch_manager por sucursal)
+-- y agrega actividades.instructor_id para que los instructores vean sus clases.
+-- Idempotente: en una base nueva 01 y 02 ya crean todo.
+-- =====================================================
+
+USE gym_users;
+
+CREATE TABLE IF NOT EXISTS roles_usuario (
+    id_rol_usuario INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    rol VARCHAR(30) NOT NULL COMMENT 'instructor | receptionist | branch_manager',
+    sucursal_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_usuario_rol_sucursal (usuario_id, rol, sucursal_id),
+    INDEX idx_sucursal (sucursal_id),
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id_usuario) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+USE gym_activities;
+
+SET @tiene_columna := (
+    SELECT COUNT(*) FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = 'gym_activities' AND TABLE_NAME = 'actividades' AND COLUMN_NAME = 'instructor_id'
+);
+SET @sql := IF(@tiene_columna = 0,
+    'ALTER TABLE actividades ADD COLUMN instructor_id INT NULL COMMENT ''ID del usuario instructor en users-api'' AFTER instructor, ADD INDEX idx_instructor (instructor_id)',
+    'SELECT 1');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+-- La vista lista las columnas explícitamente: se recrea para incluir instructor_id
+CREATE OR REPLACE VIEW actividades_lugares AS
+SELECT
+    a.id_actividad,
+    a.titulo,
+    a.descripcion,
+    a.cupo,
+    a.dia,
+    a.horario_inicio,
+    a.horario_final,
+    a.foto_url,
+    a.instructor,
+    a.instructor_id,
+    a.categoria,
+    a.sucursal_id,
+    COALESCE(s.nombre, '') AS sucursal_nombre,
+    a.activa,
+    a.created_at,
+    a.updated_at,
+    (a.cupo - COALESCE(
+        (SELECT COUNT(*)
+         FROM inscripciones i
+         WHERE i.actividad_id = a.id_actividad
+           AND i.is_activa = TRUE
+           AND i.deleted_at IS NULL
+        ), 0)
+    ) AS lugares
+FROM actividades a
+LEFT JOIN sucursales s ON a.sucursal_id = s.id_sucursal
+WHERE a.deleted_at IS NULL;
+
+SELECT '✅ Roles de staff por sucursal migrados' AS Status;
@@ -0,0 +1,29 @@
+-- =====================================================
+-- MIGRACIÓN: segundo factor (TOTP) y códigos de recuperación
+-- Crea gym_users.dos_factores y gym_users.codigos_recuperacion.
+-- Idempotente: en una base nueva 01-init-users.sql ya las crea.
+-- =====================================================
+
+USE gym_users;
+
+CREATE TABLE IF NOT EXISTS dos_factores (
+    usuario_id INT PRIMARY KEY,
+    secreto VARCHAR(64) NOT NULL COMMENT 'Secreto base32',
+    activado_en DATETIME NULL,
+    ultimo_paso BIGINT NOT NULL DEFAULT 0 COMMENT 'Último paso TOTP aceptado (anti-replay)',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id_usuario) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS codigos_recuperacion (
+    id_codigo_recuperacion INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    codigo_hash CHAR(64) NOT NULL UNIQUE COMMENT 'SHA-256 hex del código',
+    usado_en DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_usuario (usuario_id),
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id_usuario) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SELECT '✅ Tablas de segundo factor migradas' AS Status;
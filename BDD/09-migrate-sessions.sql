@@ -0,0 +1,122 @@
+-- =====================================================
+-- MIGRACIÓN: sesiones fechadas de las actividades
+-- Crea gym_activities.sesiones, agrega inscripciones.sesion_id (reservas de una sesión),
+-- cambia la clave única de inscripciones a (usuario_id, actividad_id, sesion_id)
+-- y recrea las vistas actividades_lugares y sesiones_lugares.
+-- Idempotente: en una base nueva 02-init-activities.sql ya crea todo.
+-- =====================================================
+
+USE gym_activities;
+
+CREATE TABLE IF NOT EXISTS sesiones (
+    id_sesion INT AUTO_INCREMENT PRIMARY KEY,
+    actividad_id INT NOT NULL,
+    fecha DATE NOT NULL,
+    horario_inicio DATETIME NOT NULL,
+    horario_final DATETIME NOT NULL,
+    cupo INT NOT NULL,
+    instructor VARCHAR(100),
+    instructor_id INT NULL COMMENT 'Reemplazo puntual o el instructor de la actividad',
+    estado ENUM('programada', 'cancelada') NOT NULL DEFAULT 'programada',
+    motivo_cancelacion VARCHAR(255) NULL,
+    personalizada BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Cambiada a mano: la generación no la pisa',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (actividad_id) REFERENCES actividades(id_actividad) ON DELETE CASCADE,
+    UNIQUE KEY uk_actividad_fecha (actividad_id, fecha),
+    INDEX idx_fecha (fecha),
+    INDEX idx_instructor (instructor_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET @tiene_columna := (
+    SELECT COUNT(*) FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = 'gym_activities' AND TABLE_NAME = 'inscripciones' AND COLUMN_NAME = 'sesion_id'
+);
+SET @sql := IF(@tiene_columna = 0,
+    'ALTER TABLE inscripciones ADD COLUMN sesion_id INT NULL COMMENT ''Reserva de una sesión puntual'' AFTER actividad_id, ADD INDEX idx_sesion (sesion_id), ADD FOREIGN KEY (sesion_id) REFERENCES sesiones(id_sesion) ON DELETE CASCADE',
+    'SELECT 1');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+-- Un usuario puede reservar varias sesiones de la misma actividad
+SET @tiene_indice := (
+    SELECT COUNT(*) FROM information_schema.STATISTICS
+    WHERE TABLE_SCHEMA = 'gym_activities' AND TABLE_NAME = 'inscripciones' AND INDEX_NAME = 'unique_usuario_actividad'
+);
+SET @sql := IF(@tiene_indice > 0,
+    'ALTER TABLE inscripciones DROP INDEX unique_usuario_actividad, ADD UNIQUE KEY unique_usuario_actividad_sesion (usuario_id, actividad_id, sesion_id)',
+    'SELECT 1');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+-- Las reservas de una sesión no ocupan lugar en la actividad
+CREATE OR REPLACE VIEW actividades_lugares AS
+SELECT
+    a.id_actividad,
+    a.titulo,
+    a.descripcion,
+    a.cupo,
+    a.dia,
+    a.horario_inicio,
+    a.horario_final,
+    a.foto_url,
+    a.instructor,
+    a.instructor_id,
+    a.categoria,
+    a.sucursal_id,
+    COALESCE(s.nombre, '') AS sucursal_nombre,
+    a.activa,
+    a.created_at,
+    a.updated_at,
+    (a.cupo - COALESCE(
+        (SELECT COUNT(*)
+         FROM inscripciones i
+         WHERE i.actividad_id = a.id_actividad
+           AND i.sesion_id IS NULL
+           AND i.is_activa = TRUE
+           AND i.deleted_at IS NULL
+        ), 0)
+    ) AS lugares
+FROM actividades a
+LEFT JOIN sucursales s ON a.sucursal_id = s.id_sucursal
+WHERE a.deleted_at IS NULL;
+
+CREATE OR REPLACE VIEW sesiones_lugares AS
+SELECT
+    x.*,
+    CASE
+        WHEN x.estado = 'cancelada' THEN 0
+        ELSE GREATEST(x.cupo - x.ocupados, 0)
+    END AS lugares
+FROM (
+    SELECT
+        se.id_sesion,
+        se.actividad_id,
+        a.titulo,
+        a.sucursal_id,
+        se.fecha,
+        se.horario_inicio,
+        se.horario_final,
+        se.cupo,
+        se.instructor,
+        se.instructor_id,
+        se.estado,
+        se.motivo_cancelacion,
+        se.personalizada,
+        se.created_at,
+        se.updated_at,
+        (SELECT COUNT(*)
+         FROM inscripciones i
+         WHERE i.is_activa = TRUE
+           AND i.deleted_at IS NULL
+           AND ((i.actividad_id = se.actividad_id AND i.sesion_id IS NULL)
+                OR i.sesion_id = se.id_sesion)
+        ) AS ocupados
+    FROM sesiones se
+    JOIN actividades a ON se.actividad_id = a.id_actividad
+    WHERE a.deleted_at IS NULL
+) x;
+
+SELECT '✅ Sesiones fechadas migradas' AS Status;
@@ -0,0 +1,28 @@
+-- =====================================================
+-- MIGRACIÓN: lista de espera de las actividades
+-- Crea gym_activities.lista_espera (cola por actividad o sesión con ofertas que vencen).
+-- Idempotente: en una base nueva 02-init-activities.sql ya crea la tabla.
+-- =====================================================
+
+USE gym_activities;
+
+CREATE TABLE IF NOT EXISTS lista_espera (
+    id_lista_espera INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    actividad_id INT NOT NULL,
+    sesion_id INT NULL COMMENT 'Espera lugar en una sesión puntual (NULL = inscripción fija)',
+    suscripcion_id VARCHAR(50) NULL COMMENT 'ID de suscripción de MongoDB',
+    limite_semanal INT NOT NULL DEFAULT 0 COMMENT 'Límite semanal del plan al anotarse (0 = sin límite)',
+    estado ENUM('esperando', 'ofrecida', 'confirmada', 'vencida', 'omitida', 'cancelada') NOT NULL DEFAULT 'esperando',
+    ofrecida_en DATETIME NULL,
+    vence_en DATETIME NULL COMMENT 'Límite para confirmar el lugar ofrecido',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (actividad_id) REFERENCES actividades(id_actividad) ON DELETE CASCADE,
+    FOREIGN KEY (sesion_id) REFERENCES sesiones(id_sesion) ON DELETE CASCADE,
+    INDEX idx_actividad_sesion_estado (actividad_id, sesion_id, estado),
+    INDEX idx_usuario (usuario_id),
+    INDEX idx_vence_en (vence_en)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SELECT '✅ Lista de espera migrada' AS Status;
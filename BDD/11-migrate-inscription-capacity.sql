@@ -0,0 +1,49 @@
+-- =====================================================
+-- MIGRACIÓN: clave única de inscripciones que cubre las inscripciones fijas
+-- Agrega inscripciones.sesion_clave (sesion_id o 0) y rehace unique_usuario_actividad_sesion
+-- sobre (usuario_id, actividad_id, sesion_clave): con sesion_id NULL la clave anterior
+-- dejaba duplicar la inscripción fija de un usuario.
+-- Antes borra los duplicados que haya dejado la carrera de cupos (conserva la activa más vieja).
+-- Idempotente: en una base nueva 02-init-activities.sql ya crea la columna y la clave.
+-- =====================================================
+
+USE gym_activities;
+
+SET @tiene_columna := (
+    SELECT COUNT(*) FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = 'gym_activities' AND TABLE_NAME = 'inscripciones' AND COLUMN_NAME = 'sesion_clave'
+);
+
+SET @sql := IF(@tiene_columna = 0,
+    'DELETE i1 FROM inscripciones i1
+     JOIN inscripciones i2
+       ON i1.usuario_id = i2.usuario_id
+      AND i1.actividad_id = i2.actividad_id
+      AND i1.sesion_id <=> i2.sesion_id
+      AND (i1.is_activa < i2.is_activa
+           OR (i1.is_activa = i2.is_activa AND i1.id_inscripcion > i2.id_inscripcion))',
+    'SELECT 1');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @sql := IF(@tiene_columna = 0,
+    'ALTER TABLE inscripciones ADD COLUMN sesion_clave INT AS (COALESCE(sesion_id, 0)) STORED COMMENT ''sesion_id o 0 para la clave única'' AFTER sesion_id',
+    'SELECT 1');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @tiene_indice := (
+    SELECT COUNT(*) FROM information_schema.STATISTICS
+    WHERE TABLE_SCHEMA = 'gym_activities' AND TABLE_NAME = 'inscripciones'
+      AND INDEX_NAME = 'unique_usuario_actividad_sesion' AND COLUMN_NAME = 'sesion_id'
+);
+SET @sql := IF(@tiene_indice > 0,
+    'ALTER TABLE inscripciones DROP INDEX unique_usuario_actividad_sesion, ADD UNIQUE KEY unique_usuario_actividad_sesion (usuario_id, actividad_id, sesion_clave)',
+    'SELECT 1');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SELECT '✅ Clave única de inscripciones migrada' AS Status;
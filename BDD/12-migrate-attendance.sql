@@ -0,0 +1,30 @@
+-- =====================================================
+-- MIGRACIÓN: asistencias (check-in con QR y ausentes)
+-- Crea gym_activities.asistencias.
+-- Idempotente: en una base nueva 02-init-activities.sql ya crea la tabla.
+-- =====================================================
+
+USE gym_activities;
+
+CREATE TABLE IF NOT EXISTS asistencias (
+    id_asistencia INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    actividad_id INT NULL COMMENT 'NULL = sala libre',
+    sesion_id INT NULL COMMENT 'NULL = sala libre',
+    sesion_clave INT AS (COALESCE(sesion_id, 0)) STORED COMMENT 'sesion_id o 0 para la clave única',
+    sucursal_id INT NULL,
+    tipo ENUM('clase', 'libre') NOT NULL,
+    estado ENUM('presente', 'ausente') NOT NULL DEFAULT 'presente',
+    registrada_por INT NULL COMMENT 'Staff que escaneó el QR (NULL = marcada por el job de ausentes)',
+    fecha DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (actividad_id) REFERENCES actividades(id_actividad) ON DELETE CASCADE,
+    FOREIGN KEY (sesion_id) REFERENCES sesiones(id_sesion) ON DELETE CASCADE,
+    FOREIGN KEY (sucursal_id) REFERENCES sucursales(id_sucursal) ON DELETE SET NULL,
+    INDEX idx_actividad (actividad_id),
+    INDEX idx_sesion (sesion_id),
+    INDEX idx_usuario_fecha (usuario_id, fecha),
+    UNIQUE KEY unique_usuario_sesion_fecha (usuario_id, sesion_clave, fecha)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SELECT '✅ Asistencias migradas' AS Status;
@@ -0,0 +1,45 @@
+-- =====================================================
+-- MIGRACIÓN: gestión de sucursales
+-- Agrega sucursales.zona_horaria, latitud y longitud, y crea sucursal_horarios
+-- (horario por día de la semana). Copia horario_apertura/horario_cierre a los
+-- siete días de las sucursales que todavía no tienen horario semanal.
+-- Idempotente: en una base nueva 02-init-activities.sql ya crea columnas y tabla.
+-- =====================================================
+
+USE gym_activities;
+
+SET @tiene_columna := (
+    SELECT COUNT(*) FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = 'gym_activities' AND TABLE_NAME = 'sucursales' AND COLUMN_NAME = 'zona_horaria'
+);
+SET @sql := IF(@tiene_columna = 0,
+    'ALTER TABLE sucursales
+        ADD COLUMN zona_horaria VARCHAR(64) NOT NULL DEFAULT ''America/Argentina/Buenos_Aires'' COMMENT ''Zona IANA'' AFTER horario_cierre,
+        ADD COLUMN latitud DECIMAL(9,6) NULL AFTER zona_horaria,
+        ADD COLUMN longitud DECIMAL(9,6) NULL AFTER latitud',
+    'SELECT 1');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+CREATE TABLE IF NOT EXISTS sucursal_horarios (
+    id_horario INT AUTO_INCREMENT PRIMARY KEY,
+    sucursal_id INT NOT NULL,
+    dia ENUM('Lunes', 'Martes', 'Miercoles', 'Jueves', 'Viernes', 'Sabado', 'Domingo') NOT NULL,
+    apertura TIME NOT NULL,
+    cierre TIME NOT NULL,
+    FOREIGN KEY (sucursal_id) REFERENCES sucursales(id_sucursal) ON DELETE CASCADE,
+    UNIQUE KEY unique_sucursal_dia (sucursal_id, dia)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT IGNORE INTO sucursal_horarios (sucursal_id, dia, apertura, cierre)
+SELECT s.id_sucursal, d.dia, s.horario_apertura, s.horario_cierre
+FROM sucursales s
+CROSS JOIN (
+    SELECT 'Lunes' AS dia UNION ALL SELECT 'Martes' UNION ALL SELECT 'Miercoles' UNION ALL SELECT 'Jueves'
+    UNION ALL SELECT 'Viernes' UNION ALL SELECT 'Sabado' UNION ALL SELECT 'Domingo'
+) d
+WHERE s.horario_apertura IS NOT NULL AND s.horario_cierre IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM sucursal_horarios h WHERE h.sucursal_id = s.id_sucursal);
+
+SELECT '✅ Sucursales migradas' AS Status;
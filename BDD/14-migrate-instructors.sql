@@ -0,0 +1,103 @@
+-- =====================================================
+-- MIGRACIÓN: instructores
+-- Crea gym_activities.instructores e instructor_sucursales, y agrega
+-- actividades.instructor_perfil_id (FK a instructores).
+-- Crea un instructor por cada nombre distinto de actividades.instructor (con el
+-- instructor_id de users-api si alguna actividad lo tenía) y vincula las actividades.
+-- Los nombres escritos distinto ("Juan Pérez" / "Juan Perez") quedan como dos
+-- instructores: unificarlos reasignando las actividades y dando de baja el sobrante.
+-- Idempotente: en una base nueva 02-init-activities.sql ya crea tablas, columna y vista.
+-- =====================================================
+
+USE gym_activities;
+
+CREATE TABLE IF NOT EXISTS instructores (
+    id_instructor INT AUTO_INCREMENT PRIMARY KEY,
+    nombre VARCHAR(100) NOT NULL,
+    bio TEXT,
+    foto_url VARCHAR(511),
+    especialidades JSON NULL COMMENT 'Lista de especialidades (yoga, spinning, ...)',
+    usuario_id INT NULL COMMENT 'Cuenta del instructor en users-api',
+    activo BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_usuario (usuario_id),
+    INDEX idx_activo (activo)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS instructor_sucursales (
+    instructor_id INT NOT NULL,
+    sucursal_id INT NOT NULL,
+    PRIMARY KEY (instructor_id, sucursal_id),
+    FOREIGN KEY (instructor_id) REFERENCES instructores(id_instructor) ON DELETE CASCADE,
+    FOREIGN KEY (sucursal_id) REFERENCES sucursales(id_sucursal) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET @tiene_columna := (
+    SELECT COUNT(*) FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = 'gym_activities' AND TABLE_NAME = 'actividades' AND COLUMN_NAME = 'instructor_perfil_id'
+);
+SET @sql := IF(@tiene_columna = 0,
+    'ALTER TABLE actividades
+        ADD COLUMN instructor_perfil_id INT NULL COMMENT ''Instructor (tabla instructores) que dicta la clase'' AFTER instructor_id,
+        ADD INDEX idx_instructor_perfil_dia (instructor_perfil_id, dia),
+        ADD FOREIGN KEY (instructor_perfil_id) REFERENCES instructores(id_instructor) ON DELETE SET NULL',
+    'SELECT 1');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+-- Un instructor por nombre; INSERT IGNORE saltea una cuenta de users-api repetida en dos nombres
+INSERT IGNORE INTO instructores (nombre, usuario_id, especialidades)
+SELECT a.instructor,
+       MAX(a.instructor_id),
+       CAST(CONCAT('["', GROUP_CONCAT(DISTINCT a.categoria SEPARATOR '","'), '"]') AS JSON)
+FROM actividades a
+WHERE a.instructor IS NOT NULL AND a.instructor <> '' AND a.deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM instructores i WHERE i.nombre = a.instructor)
+GROUP BY a.instructor;
+
+UPDATE actividades a
+JOIN instructores i ON i.nombre = a.instructor
+SET a.instructor_perfil_id = i.id_instructor
+WHERE a.instructor_perfil_id IS NULL;
+
+INSERT IGNORE INTO instructor_sucursales (instructor_id, sucursal_id)
+SELECT DISTINCT a.instructor_perfil_id, a.sucursal_id
+FROM actividades a
+WHERE a.instructor_perfil_id IS NOT NULL AND a.sucursal_id IS NOT NULL AND a.deleted_at IS NULL;
+
+-- La vista lista las columnas explícitamente: se recrea para incluir instructor_perfil_id
+CREATE OR REPLACE VIEW actividades_lugares AS
+SELECT
+    a.id_actividad,
+    a.titulo,
+    a.descripcion,
+    a.cupo,
+    a.dia,
+    a.horario_inicio,
+    a.horario_final,
+    a.foto_url,
+    a.instructor,
+    a.instructor_id,
+    a.instructor_perfil_id,
+    a.categoria,
+    a.sucursal_id,
+    COALESCE(s.nombre, '') AS sucursal_nombre,
+    a.activa,
+    a.created_at,
+    a.updated_at,
+    (a.cupo - COALESCE(
+        (SELECT COUNT(*)
+         FROM inscripciones i
+         WHERE i.actividad_id = a.id_actividad
+           AND i.sesion_id IS NULL
+           AND i.is_activa = TRUE
+           AND i.deleted_at IS NULL
+        ), 0)
+    ) AS lugares
+FROM actividades a
+LEFT JOIN sucursales s ON a.sucursal_id = s.id_sucursal
+WHERE a.deleted_at IS NULL;
+
+SELECT '✅ Instructores migrados' AS Status;
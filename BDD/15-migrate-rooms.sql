@@ -0,0 +1,95 @@
+-- =====================================================
+-- MIGRACIÓN: salas y equipos
+-- Crea gym_activities.salas, equipos y reservas_equipo, y agrega
+-- actividades.sala_id (FK a salas). Las actividades existentes quedan sin sala:
+-- asignarlas desde la API, que valida capacidad y superposición de horarios.
+-- Idempotente: en una base nueva 02-init-activities.sql ya crea tablas, columna y vista.
+-- =====================================================
+
+USE gym_activities;
+
+CREATE TABLE IF NOT EXISTS salas (
+    id_sala INT AUTO_INCREMENT PRIMARY KEY,
+    sucursal_id INT NOT NULL,
+    nombre VARCHAR(60) NOT NULL,
+    capacidad INT NOT NULL,
+    activa BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (sucursal_id) REFERENCES sucursales(id_sucursal) ON DELETE CASCADE,
+    UNIQUE KEY uk_sucursal_nombre (sucursal_id, nombre)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS equipos (
+    id_equipo INT AUTO_INCREMENT PRIMARY KEY,
+    sala_id INT NOT NULL,
+    tipo VARCHAR(40) NOT NULL COMMENT 'bicicleta, reformer, ...',
+    numero INT NOT NULL,
+    activo BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (sala_id) REFERENCES salas(id_sala) ON DELETE CASCADE,
+    UNIQUE KEY uk_sala_tipo_numero (sala_id, tipo, numero)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET @tiene_columna := (
+    SELECT COUNT(*) FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = 'gym_activities' AND TABLE_NAME = 'actividades' AND COLUMN_NAME = 'sala_id'
+);
+SET @sql := IF(@tiene_columna = 0,
+    'ALTER TABLE actividades
+        ADD COLUMN sala_id INT NULL COMMENT ''Sala donde se dicta (NULL = sin sala asignada)'' AFTER sucursal_id,
+        ADD INDEX idx_sala_dia (sala_id, dia),
+        ADD FOREIGN KEY (sala_id) REFERENCES salas(id_sala) ON DELETE SET NULL',
+    'SELECT 1');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+CREATE TABLE IF NOT EXISTS reservas_equipo (
+    id_reserva INT AUTO_INCREMENT PRIMARY KEY,
+    equipo_id INT NOT NULL,
+    sesion_id INT NOT NULL,
+    usuario_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (equipo_id) REFERENCES equipos(id_equipo) ON DELETE CASCADE,
+    FOREIGN KEY (sesion_id) REFERENCES sesiones(id_sesion) ON DELETE CASCADE,
+    UNIQUE KEY uk_equipo_sesion (equipo_id, sesion_id),
+    UNIQUE KEY uk_usuario_sesion (sesion_id, usuario_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- La vista lista las columnas explícitamente: se recrea para incluir sala_id
+CREATE OR REPLACE VIEW actividades_lugares AS
+SELECT
+    a.id_actividad,
+    a.titulo,
+    a.descripcion,
+    a.cupo,
+    a.dia,
+    a.horario_inicio,
+    a.horario_final,
+    a.foto_url,
+    a.instructor,
+    a.instructor_id,
+    a.instructor_perfil_id,
+    a.categoria,
+    a.sucursal_id,
+    a.sala_id,
+    COALESCE(s.nombre, '') AS sucursal_nombre,
+    a.activa,
+    a.created_at,
+    a.updated_at,
+    (a.cupo - COALESCE(
+        (SELECT COUNT(*)
+         FROM inscripciones i
+         WHERE i.actividad_id = a.id_actividad
+           AND i.sesion_id IS NULL
+           AND i.is_activa = TRUE
+           AND i.deleted_at IS NULL
+        ), 0)
+    ) AS lugares
+FROM actividades a
+LEFT JOIN sucursales s ON a.sucursal_id = s.id_sucursal
+WHERE a.deleted_at IS NULL;
+
+SELECT '✅ Salas y equipos migrados' AS Status;
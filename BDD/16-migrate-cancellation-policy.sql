@@ -0,0 +1,68 @@
+-- =====================================================
+-- MIGRACIÓN: políticas de cancelación y strikes
+-- Crea gym_activities.politicas_cancelacion, bloqueos_reserva y strikes, y agrega
+-- inscripciones.plan_id. Las inscripciones existentes quedan sin plan y usan la
+-- política de su categoría o la general.
+-- Idempotente: en una base nueva 02-init-activities.sql ya crea tablas y columna.
+-- =====================================================
+
+USE gym_activities;
+
+CREATE TABLE IF NOT EXISTS politicas_cancelacion (
+    id_politica INT AUTO_INCREMENT PRIMARY KEY,
+    alcance ENUM('plan', 'categoria', 'general') NOT NULL,
+    valor VARCHAR(100) NOT NULL DEFAULT '' COMMENT 'ID del plan o categoría (vacío en la general)',
+    minutos_limite INT NOT NULL DEFAULT 0 COMMENT 'Cancelar con menos anticipación suma un strike (0 = nunca)',
+    penalizar_ausentes BOOLEAN NOT NULL DEFAULT TRUE,
+    strikes_bloqueo INT NOT NULL DEFAULT 0 COMMENT 'Strikes que bloquean las inscripciones (0 = no bloquea)',
+    dias_bloqueo INT NOT NULL DEFAULT 0,
+    dias_vigencia_strike INT NOT NULL DEFAULT 30 COMMENT 'Los strikes más viejos no cuentan para el bloqueo (0 = no vencen)',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_alcance_valor (alcance, valor)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS bloqueos_reserva (
+    id_bloqueo INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    hasta DATETIME NOT NULL,
+    levantado BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Un admin anuló uno de sus strikes',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_usuario_hasta (usuario_id, hasta)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS strikes (
+    id_strike INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    tipo ENUM('cancelacion_tardia', 'ausente') NOT NULL,
+    actividad_id INT NOT NULL,
+    sesion_id INT NOT NULL,
+    politica_id INT NULL,
+    bloqueo_id INT NULL,
+    anulado BOOLEAN NOT NULL DEFAULT FALSE,
+    anulado_por INT NULL COMMENT 'Admin que anuló el strike',
+    motivo_anulacion VARCHAR(255) NULL,
+    anulado_en DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (actividad_id) REFERENCES actividades(id_actividad) ON DELETE CASCADE,
+    FOREIGN KEY (sesion_id) REFERENCES sesiones(id_sesion) ON DELETE CASCADE,
+    FOREIGN KEY (politica_id) REFERENCES politicas_cancelacion(id_politica) ON DELETE SET NULL,
+    FOREIGN KEY (bloqueo_id) REFERENCES bloqueos_reserva(id_bloqueo) ON DELETE SET NULL,
+    INDEX idx_usuario_created (usuario_id, created_at),
+    INDEX idx_bloqueo (bloqueo_id),
+    UNIQUE KEY uk_usuario_sesion_tipo (usuario_id, tipo, sesion_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SET @tiene_columna := (
+    SELECT COUNT(*) FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = 'gym_activities' AND TABLE_NAME = 'inscripciones' AND COLUMN_NAME = 'plan_id'
+);
+SET @sql := IF(@tiene_columna = 0,
+    'ALTER TABLE inscripciones
+        ADD COLUMN plan_id VARCHAR(50) NULL COMMENT ''Plan de la suscripción al inscribirse (elige la política de cancelación)'' AFTER suscripcion_id',
+    'SELECT 1');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SELECT '✅ Políticas de cancelación migradas' AS Status;
@@ -0,0 +1,61 @@
+-- =====================================================
+-- MIGRACIÓN: reprogramación de sesiones
+-- Agrega sesiones.fecha_original (la fecha que le tocaba a una sesión reprogramada,
+-- para que la generación no la vuelva a crear) y recrea la vista sesiones_lugares.
+-- Idempotente: en una base nueva 02-init-activities.sql ya crea columna y vista.
+-- =====================================================
+
+USE gym_activities;
+
+SET @tiene_columna := (
+    SELECT COUNT(*) FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = 'gym_activities' AND TABLE_NAME = 'sesiones' AND COLUMN_NAME = 'fecha_original'
+);
+SET @sql := IF(@tiene_columna = 0,
+    'ALTER TABLE sesiones
+        ADD COLUMN fecha_original DATE NULL COMMENT ''Fecha que le tocaba si se reprogramó (la generación no la vuelve a crear)'' AFTER personalizada,
+        ADD INDEX idx_actividad_fecha_original (actividad_id, fecha_original)',
+    'SELECT 1');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+-- La vista lista las columnas explícitamente: se recrea para incluir fecha_original
+CREATE OR REPLACE VIEW sesiones_lugares AS
+SELECT
+    x.*,
+    CASE
+        WHEN x.estado = 'cancelada' THEN 0
+        ELSE GREATEST(x.cupo - x.ocupados, 0)
+    END AS lugares
+FROM (
+    SELECT
+        se.id_sesion,
+        se.actividad_id,
+        a.titulo,
+        a.sucursal_id,
+        se.fecha,
+        se.horario_inicio,
+        se.horario_final,
+        se.cupo,
+        se.instructor,
+        se.instructor_id,
+        se.estado,
+        se.motivo_cancelacion,
+        se.personalizada,
+        se.fecha_original,
+        se.created_at,
+        se.updated_at,
+        (SELECT COUNT(*)
+         FROM inscripciones i
+         WHERE i.is_activa = TRUE
+           AND i.deleted_at IS NULL
+           AND ((i.actividad_id = se.actividad_id AND i.sesion_id IS NULL)
+                OR i.sesion_id = se.id_sesion)
+        ) AS ocupados
+    FROM sesiones se
+    JOIN actividades a ON se.actividad_id = a.id_actividad
+    WHERE a.deleted_at IS NULL
+) x;
+
+SELECT '✅ Reprogramación de sesiones migrada' AS Status;
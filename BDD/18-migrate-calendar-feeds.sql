@@ -0,0 +1,18 @@
+-- =====================================================
+-- MIGRACIÓN: calendarios iCalendar
+-- Crea gym_activities.calendario_tokens (token de la URL secreta del calendario
+-- .ics de cada socio). Los tokens se crean la primera vez que el socio pide su URL.
+-- Idempotente: en una base nueva 02-init-activities.sql ya crea la tabla.
+-- =====================================================
+
+USE gym_activities;
+
+CREATE TABLE IF NOT EXISTS calendario_tokens (
+    usuario_id INT PRIMARY KEY,
+    token VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_token (token)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SELECT '✅ Calendarios iCalendar migrados' AS Status;
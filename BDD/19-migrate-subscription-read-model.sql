@@ -0,0 +1,37 @@
+-- =====================================================
+-- MIGRACIÓN: copia local de suscripciones y planes
+-- Crea gym_activities.planes y gym_activities.suscripciones, que activities-api
+-- mantiene con los eventos subscription.* y plan.* de gym_events para validar las
+-- inscripciones sin llamar a subscriptions-api. Las suscripciones existentes se
+-- cargan con POST /suscripciones/sincronizar (admin) o a medida que los socios
+-- reservan (fallback HTTP); los planes se copian al arrancar el servicio.
+-- Idempotente: en una base nueva 02-init-activities.sql ya crea las tablas.
+-- =====================================================
+
+USE gym_activities;
+
+CREATE TABLE IF NOT EXISTS planes (
+    id_plan VARCHAR(24) PRIMARY KEY,
+    nombre VARCHAR(100) NOT NULL DEFAULT '',
+    tipo_acceso VARCHAR(20) NOT NULL DEFAULT '',
+    actividades_permitidas JSON NULL,
+    actividades_por_semana INT NOT NULL DEFAULT 0,
+    activo BOOLEAN NOT NULL DEFAULT TRUE,
+    evento_en DATETIME(6) NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS suscripciones (
+    id_suscripcion VARCHAR(24) PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    plan_id VARCHAR(24) NOT NULL DEFAULT '',
+    estado VARCHAR(30) NOT NULL,
+    fecha_inicio DATETIME NULL,
+    fecha_vencimiento DATETIME NULL,
+    evento_en DATETIME(6) NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_usuario (usuario_id),
+    INDEX idx_estado (estado)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SELECT '✅ Copia local de suscripciones y planes migrada' AS Status;
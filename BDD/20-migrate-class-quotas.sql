@@ -0,0 +1,48 @@
+-- =====================================================
+-- MIGRACIÓN: cupo de clases por plan
+-- Agrega planes.cupo_clases y planes.cupo_periodo (clases por día, semana ISO o mes)
+-- y crea gym_activities.consumos_cupo, el registro de las clases descontadas del cupo:
+-- una fila por inscripción y sesión, con el período de la fecha de la clase.
+-- Las bajas, las sesiones canceladas por el gimnasio y los reembolsos la reintegran.
+-- Los planes existentes conservan su límite semanal (actividades_por_semana) hasta
+-- que llegue el próximo evento plan.* o se sincronicen.
+-- Idempotente: en una base nueva 02-init-activities.sql ya crea columnas y tabla.
+-- =====================================================
+
+USE gym_activities;
+
+SET @tiene_columna := (
+    SELECT COUNT(*) FROM information_schema.COLUMNS
+    WHERE TABLE_SCHEMA = 'gym_activities' AND TABLE_NAME = 'planes' AND COLUMN_NAME = 'cupo_clases'
+);
+SET @sql := IF(@tiene_columna = 0,
+    'ALTER TABLE planes
+        ADD COLUMN cupo_clases INT NOT NULL DEFAULT 0 COMMENT ''Clases por período (0 = usa actividades_por_semana)'' AFTER actividades_por_semana,
+        ADD COLUMN cupo_periodo VARCHAR(10) NOT NULL DEFAULT '''' COMMENT ''diario | semanal | mensual'' AFTER cupo_clases',
+    'SELECT 1');
+PREPARE stmt FROM @sql;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+CREATE TABLE IF NOT EXISTS consumos_cupo (
+    id_consumo INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    suscripcion_id VARCHAR(24) NOT NULL DEFAULT '',
+    inscripcion_id INT NOT NULL,
+    actividad_id INT NOT NULL,
+    sesion_id INT NOT NULL,
+    fecha_clase DATE NOT NULL,
+    periodo VARCHAR(10) NOT NULL,
+    periodo_clave VARCHAR(10) NOT NULL COMMENT '2025-03-10 | 2025-W11 | 2025-03',
+    estado ENUM('usado', 'reintegrado') NOT NULL DEFAULT 'usado',
+    motivo_reintegro VARCHAR(30) NULL,
+    reintegrado_en DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_inscripcion_sesion (inscripcion_id, sesion_id),
+    INDEX idx_usuario_fecha (usuario_id, fecha_clase),
+    INDEX idx_sesion (sesion_id),
+    INDEX idx_suscripcion (suscripcion_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SELECT '✅ Cupo de clases por plan migrado' AS Status;
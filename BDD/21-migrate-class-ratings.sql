@@ -0,0 +1,79 @@
+-- =====================================================
+-- MIGRACIÓN: calificaciones de clases
+-- Crea gym_activities.calificaciones (puntaje 1 a 5 y comentario opcional, una por socio
+-- y sesión, con moderación: ocultar y marcar para revisar) y recrea la vista
+-- actividades_lugares con los promedios de la actividad y de su instructor,
+-- que viajan en los eventos activity.update.
+-- Idempotente: en una base nueva 02-init-activities.sql ya crea tabla y vista.
+-- =====================================================
+
+USE gym_activities;
+
+CREATE TABLE IF NOT EXISTS calificaciones (
+    id_calificacion INT AUTO_INCREMENT PRIMARY KEY,
+    sesion_id INT NOT NULL,
+    actividad_id INT NOT NULL,
+    usuario_id INT NOT NULL,
+    instructor_perfil_id INT NULL COMMENT 'Instructor que dictó la sesión (NULL = reemplazo sin perfil)',
+    puntaje TINYINT NOT NULL COMMENT '1 a 5',
+    comentario VARCHAR(1000) NULL,
+    oculta BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Oculta por moderación: no cuenta en los promedios',
+    motivo_ocultamiento VARCHAR(255) NULL,
+    marcada BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Marcada para revisar',
+    motivo_marca VARCHAR(255) NULL,
+    moderada_por INT NULL COMMENT 'Admin de la última moderación',
+    moderada_en DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (sesion_id) REFERENCES sesiones(id_sesion) ON DELETE CASCADE,
+    FOREIGN KEY (actividad_id) REFERENCES actividades(id_actividad) ON DELETE CASCADE,
+    FOREIGN KEY (instructor_perfil_id) REFERENCES instructores(id_instructor) ON DELETE SET NULL,
+    UNIQUE KEY uk_sesion_usuario (sesion_id, usuario_id),
+    INDEX idx_actividad_oculta (actividad_id, oculta),
+    INDEX idx_instructor_oculta (instructor_perfil_id, oculta),
+    INDEX idx_marcada (marcada)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- La vista lista las columnas explícitamente: se recrea para incluir los promedios
+CREATE OR REPLACE VIEW actividades_lugares AS
+SELECT
+    a.id_actividad,
+    a.titulo,
+    a.descripcion,
+    a.cupo,
+    a.dia,
+    a.horario_inicio,
+    a.horario_final,
+    a.foto_url,
+    a.instructor,
+    a.instructor_id,
+    a.instructor_perfil_id,
+    a.categoria,
+    a.sucursal_id,
+    a.sala_id,
+    COALESCE(s.nombre, '') AS sucursal_nombre,
+    a.activa,
+    a.created_at,
+    a.updated_at,
+    (a.cupo - COALESCE(
+        (SELECT COUNT(*)
+         FROM inscripciones i
+         WHERE i.actividad_id = a.id_actividad
+           AND i.sesion_id IS NULL
+           AND i.is_activa = TRUE
+           AND i.deleted_at IS NULL
+        ), 0)
+    ) AS lugares,
+    COALESCE((SELECT ROUND(AVG(c.puntaje), 2) FROM calificaciones c
+              WHERE c.actividad_id = a.id_actividad AND c.oculta = FALSE), 0) AS calificacion_promedio,
+    (SELECT COUNT(*) FROM calificaciones c
+     WHERE c.actividad_id = a.id_actividad AND c.oculta = FALSE) AS calificaciones,
+    COALESCE((SELECT ROUND(AVG(c.puntaje), 2) FROM calificaciones c
+              WHERE c.instructor_perfil_id = a.instructor_perfil_id AND c.oculta = FALSE), 0) AS instructor_calificacion_promedio,
+    (SELECT COUNT(*) FROM calificaciones c
+     WHERE c.instructor_perfil_id = a.instructor_perfil_id AND c.oculta = FALSE) AS instructor_calificaciones
+FROM actividades a
+LEFT JOIN sucursales s ON a.sucursal_id = s.id_sucursal
+WHERE a.deleted_at IS NULL;
+
+SELECT '✅ Calificaciones de clases migradas' AS Status;
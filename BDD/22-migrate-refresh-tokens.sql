@@ -0,0 +1,36 @@
+-- =====================================================
+-- MIGRACIÓN: refresh tokens y revocación de access tokens
+-- Crea gym_users.refresh_tokens (solo el hash SHA-256, rotan en cada uso
+-- y reutilizar uno revoca toda su familia) y gym_users.tokens_revocados
+-- (jti de access tokens revocados antes de expirar).
+-- Idempotente: en una base nueva 01-init-users.sql ya las crea.
+-- =====================================================
+
+USE gym_users;
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id_refresh_token INT AUTO_INCREMENT PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE COMMENT 'SHA-256 hex del refresh token',
+    familia_id CHAR(32) NOT NULL COMMENT 'Cadena de rotación (login -> refresh -> refresh...)',
+    access_jti CHAR(32) NOT NULL COMMENT 'jti del access token emitido junto con este refresh token',
+    access_expira_en DATETIME NOT NULL,
+    expira_en DATETIME NOT NULL,
+    usado_en DATETIME NULL,
+    revocado_en DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_usuario (usuario_id),
+    INDEX idx_familia (familia_id),
+    INDEX idx_access_jti (access_jti),
+    FOREIGN KEY (usuario_id) REFERENCES usuarios(id_usuario) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS tokens_revocados (
+    jti CHAR(32) PRIMARY KEY,
+    usuario_id INT NOT NULL,
+    expira_en DATETIME NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_expira (expira_en)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+SELECT '✅ Refresh tokens y tokens revocados migrados' AS Status;
@@ -8,11 +8,15 @@ import (
 	"activities-api/internal/middleware"
 	"activities-api/internal/repository"
 	"activities-api/internal/services"
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gym-management/shared/auth"
+	"github.com/yourusername/gym-management/shared/httpclient"
 )
 
 // Variables globales para health check
@@ -35,8 +39,38 @@ func main() {
 	// Crear repositorio de inscripciones (comparte la misma DB)
 	inscripcionesRepo := repository.NewMySQLInscripcionesRepository(actividadesRepo.GetDB())
 
-	// TODO: Cuando el equipo implemente Sucursales:
-	// sucursalesRepo := repository.NewMySQLSucursalesRepository(actividadesRepo.GetDB())
+	// Crear repositorio de sesiones fechadas (comparte la misma DB)
+	sesionesRepo := repository.NewMySQLSesionesRepository(actividadesRepo.GetDB())
+
+	// Crear repositorio de la lista de espera (comparte la misma DB)
+	listaEsperaRepo := repository.NewMySQLListaEsperaRepository(actividadesRepo.GetDB())
+
+	// Crear repositorio de asistencias (comparte la misma DB)
+	asistenciasRepo := repository.NewMySQLAsistenciasRepository(actividadesRepo.GetDB())
+
+	// Crear repositorio de sucursales (comparte la misma DB)
+	sucursalesRepo := repository.NewMySQLSucursalesRepository(actividadesRepo.GetDB())
+
+	// Crear repositorio de instructores (comparte la misma DB)
+	instructoresRepo := repository.NewMySQLInstructoresRepository(actividadesRepo.GetDB())
+
+	// Crear repositorio de salas y equipos (comparte la misma DB)
+	salasRepo := repository.NewMySQLSalasRepository(actividadesRepo.GetDB())
+
+	// Crear repositorio de políticas de cancelación, strikes y bloqueos (comparte la misma DB)
+	penalizacionesRepo := repository.NewMySQLPenalizacionesRepository(actividadesRepo.GetDB())
+
+	// Crear repositorio de calificaciones de clases (comparte la misma DB)
+	calificacionesRepo := repository.NewMySQLCalificacionesRepository(actividadesRepo.GetDB())
+
+	// Crear repositorio de tokens de los calendarios .ics (comparte la misma DB)
+	calendarioRepo := repository.NewMySQLCalendarioRepository(actividadesRepo.GetDB())
+
+	// Crear repositorio de la copia local de suscripciones y planes (comparte la misma DB)
+	suscripcionesRepo := repository.NewMySQLSuscripcionesRepository(actividadesRepo.GetDB())
+
+	// Crear repositorio del cupo de clases de los planes (comparte la misma DB)
+	cuposRepo := repository.NewMySQLCuposRepository(actividadesRepo.GetDB())
 
 	// ========== RABBITMQ EVENT PUBLISHER ==========
 	// Inicializar RabbitMQ con fallback a NullEventPublisher
@@ -53,15 +87,29 @@ func main() {
 		log.Println("✅ RabbitMQ Publisher inicializado correctamente")
 	}
 
+	// Cliente HTTP de subscriptions-api (timeouts, reintentos, circuit breaker y bulkhead)
+	subscriptionsAPI := httpclient.New("subscriptions-api", cfg.Suscripciones.API)
+
 	// ========== CAPA DE NEGOCIO (SERVICES) ==========
 	// Crear servicios con dependency injection (incluyendo eventPublisher)
-	actividadesService := services.NewActividadesService(actividadesRepo, eventPublisher)
-	inscripcionesService := services.NewInscripcionesService(inscripcionesRepo, actividadesRepo, eventPublisher)
-	// TODO: sucursalesService := services.NewSucursalesService(sucursalesRepo)
+	actividadesService := services.NewActividadesService(actividadesRepo, sucursalesRepo, instructoresRepo, salasRepo, eventPublisher)
+	penalizacionesService := services.NewPenalizacionesService(penalizacionesRepo)
+	suscripcionesService := services.NewSuscripcionesService(suscripcionesRepo, subscriptionsAPI, cfg.Suscripciones.FallbackHTTP)
+	cuposService := services.NewCuposService(cuposRepo, inscripcionesRepo, actividadesRepo, sesionesRepo, sucursalesRepo, suscripcionesService)
+	inscripcionesService := services.NewInscripcionesService(inscripcionesRepo, actividadesRepo, sesionesRepo, listaEsperaRepo, suscripcionesService, penalizacionesService, cuposService, eventPublisher, time.Duration(cfg.ListaEspera.MinutosConfirmacion)*time.Minute)
+	sesionesService := services.NewSesionesService(sesionesRepo, actividadesRepo, salasRepo, inscripcionesRepo, listaEsperaRepo, cuposService, eventPublisher, cfg.Sesiones.HorizonteDias)
+	codigoQR := services.NewCodigoQRSigner(cfg.Asistencias.QRSecret, time.Duration(cfg.Asistencias.QRPeriodoSegundos)*time.Second)
+	asistenciasService := services.NewAsistenciasService(asistenciasRepo, inscripcionesRepo, sesionesRepo, actividadesRepo, suscripcionesService, penalizacionesService, codigoQR, eventPublisher, cfg.Asistencias.DiasAusentes)
+	sucursalesService := services.NewSucursalesService(sucursalesRepo, eventPublisher)
+	instructoresService := services.NewInstructoresService(instructoresRepo, sucursalesRepo, sesionesRepo, eventPublisher)
+	salasService := services.NewSalasService(salasRepo, sucursalesRepo, sesionesRepo, actividadesRepo, inscripcionesRepo)
+	calendarioService := services.NewCalendarioService(calendarioRepo, actividadesRepo, sesionesRepo, inscripcionesRepo, sucursalesRepo)
+	calificacionesService := services.NewCalificacionesService(calificacionesRepo, sesionesRepo, actividadesRepo, instructoresRepo, eventPublisher)
 
 	// ========== RABBITMQ SUBSCRIPTION CONSUMER ==========
-	// Escuchar eventos de suscripciones canceladas para desinscribir usuarios
-	subscriptionHandler := handlers.NewSubscriptionEventHandler(inscripcionesService)
+	// Escuchar eventos de suscripciones y planes: mantienen la copia local y
+	// las cancelaciones desinscriben al usuario (los reembolsos reintegran el cupo de clases)
+	subscriptionHandler := handlers.NewSubscriptionEventHandler(inscripcionesService, suscripcionesService, cuposService)
 	subscriptionConsumer, err := clients.NewRabbitMQSubscriptionConsumer(cfg.RabbitMQURL, cfg.RabbitMQExchange, subscriptionHandler)
 	if err != nil {
 		log.Printf("⚠️ Warning: No se pudo inicializar consumer de suscripciones: %v", err)
@@ -70,15 +118,73 @@ func main() {
 			log.Printf("⚠️ Warning: Error iniciando consumer de suscripciones: %v", err)
 		} else {
 			defer subscriptionConsumer.Close()
-			log.Println("✅ Subscription Consumer iniciado - Escuchando eventos de cancelación de suscripciones")
+			log.Println("✅ Subscription Consumer iniciado - Escuchando eventos de suscripciones y planes")
 		}
 	}
 
+	// Copia inicial de los planes (las suscripciones llegan por eventos, el fallback HTTP
+	// o POST /suscripciones/sincronizar)
+	go func() {
+		planes, err := suscripcionesService.SincronizarPlanes(context.Background())
+		if err != nil {
+			log.Printf("⚠️ Warning: No se pudieron sincronizar los planes desde subscriptions-api: %v", err)
+			return
+		}
+		log.Printf("✅ Planes sincronizados desde subscriptions-api: %d", planes)
+	}()
+	if !cfg.Suscripciones.FallbackHTTP {
+		log.Println("ℹ️ Fallback HTTP a subscriptions-api desactivado: las inscripciones solo usan la copia local")
+	}
+
+	// ========== GENERACIÓN DE SESIONES ==========
+	// Materializa las sesiones fechadas de cada actividad para los próximos días
+	sesionesGenerator := services.NewSesionesGenerator(sesionesService, time.Duration(cfg.Sesiones.IntervaloMinutos)*time.Minute)
+	sesionesGenerator.Start()
+	defer sesionesGenerator.Stop()
+	log.Printf("✅ Generador de sesiones iniciado - Horizonte: %d días", cfg.Sesiones.HorizonteDias)
+
+	// ========== LISTA DE ESPERA ==========
+	// Pasa al siguiente de la cola los lugares ofrecidos que no se confirmaron a tiempo
+	listaEsperaExpirer := services.NewListaEsperaExpirer(inscripcionesService, time.Minute)
+	listaEsperaExpirer.Start()
+	defer listaEsperaExpirer.Stop()
+	log.Printf("✅ Vencimiento de ofertas de lista de espera iniciado - Ventana: %d minutos", cfg.ListaEspera.MinutosConfirmacion)
+
+	// ========== AUSENTES ==========
+	// Marca como ausentes a los inscriptos sin check-in de las sesiones de los días cerrados
+	// (y les suma un strike si su política de cancelación penaliza las ausencias)
+	ausentesJob := services.NewAusentesJob(asistenciasService, time.Hour)
+	ausentesJob.Start()
+	defer ausentesJob.Stop()
+	log.Printf("✅ Job de ausentes iniciado - Revisa los últimos %d días", cfg.Asistencias.DiasAusentes)
+
+	// ========== LISTA DE TOKENS REVOCADOS ==========
+	// Se sincroniza periódicamente desde users-api (logout / sesiones comprometidas)
+	// Cliente hacia users-api (también para el JWKS): timeouts, reintentos y circuit breaker de httpclient
+	usersAPI := httpclient.New("users-api", cfg.UsersAPI)
+	revocations := auth.NewRevocationList(usersAPI, 15*time.Second)
+	revocations.Start()
+	defer revocations.Stop()
+
+	// ========== CLAVES PÚBLICAS DE JWT (JWKS) ==========
+	// Los tokens se verifican con las claves publicadas por users-api (sin secreto compartido)
+	jwtKeys := auth.NewRemoteKeySet(usersAPI, auth.JWKSPath, 10*time.Minute)
+	tokenVerifier := auth.NewVerifier(jwtKeys, revocations)
+
 	// ========== CAPA DE PRESENTACIÓN (CONTROLLERS) ==========
 	// Crear controllers con dependency injection
 	actividadesController := controllers.NewActividadesController(actividadesService)
 	inscripcionesController := controllers.NewInscripcionesController(inscripcionesService)
-	// TODO: sucursalesController := controllers.NewSucursalesController(sucursalesService)
+	sesionesController := controllers.NewSesionesController(sesionesService)
+	asistenciasController := controllers.NewAsistenciasController(asistenciasService)
+	sucursalesController := controllers.NewSucursalesController(sucursalesService)
+	instructoresController := controllers.NewInstructoresController(instructoresService)
+	salasController := controllers.NewSalasController(salasService)
+	penalizacionesController := controllers.NewPenalizacionesController(penalizacionesService)
+	cuposController := controllers.NewCuposController(cuposService)
+	calendarioController := controllers.NewCalendarioController(calendarioService, cfg.URLPublica)
+	suscripcionesController := controllers.NewSuscripcionesController(suscripcionesService)
+	calificacionesController := controllers.NewCalificacionesController(calificacionesService)
 
 	// ========== CONFIGURACIÓN DE GIN ==========
 	router := gin.Default()
@@ -86,40 +192,149 @@ func main() {
 
 	// Health check
 	router.GET("/healthz", healthCheckHandler)
+	// Métricas de los clientes HTTP hacia otros servicios (llamadas, reintentos, estado de los circuitos)
+	router.GET("/metrics/clients", gin.WrapH(httpclient.MetricsHandler()))
 
 	// ========== RUTAS PÚBLICAS ==========
 	// Actividades (solo lectura sin auth)
 	router.GET("/actividades", actividadesController.List)
 	router.GET("/actividades/buscar", actividadesController.Search)
 	router.GET("/actividades/:id", actividadesController.GetByID)
+	router.GET("/actividades/:id/sesiones", sesionesController.ListByActividad)
+	router.GET("/sesiones/:id", sesionesController.GetByID)
+
+	// Sucursales (solo lectura sin auth)
+	router.GET("/sucursales", sucursalesController.List)
+	router.GET("/sucursales/:id", sucursalesController.GetByID)
+	router.GET("/sucursales/:id/calendar.ics", calendarioController.CalendarioSucursal)
+
+	// Calendario del socio: sin JWT porque lo piden Google/Apple Calendar, lo identifica el token de la URL
+	router.GET("/inscripciones/calendar.ics", calendarioController.CalendarioUsuario)
+
+	// Salas de cada sucursal con sus equipos (solo lectura sin auth)
+	router.GET("/sucursales/:id/salas", salasController.ListBySucursal)
+	router.GET("/salas/:id", salasController.GetByID)
 
-	// TODO: Sucursales (solo lectura sin auth)
-	// router.GET("/sucursales", sucursalesController.List)
-	// router.GET("/sucursales/:id", sucursalesController.GetByID)
+	// Instructores (perfiles y agenda, solo lectura sin auth)
+	router.GET("/instructores", instructoresController.List)
+	router.GET("/instructores/:id", instructoresController.GetByID)
+	router.GET("/instructores/:id/agenda", instructoresController.Agenda)
+
+	// Calificaciones visibles con su promedio (solo lectura sin auth)
+	router.GET("/actividades/:id/calificaciones", calificacionesController.ListByActividad)
+	router.GET("/instructores/:id/calificaciones", calificacionesController.ListByInstructor)
 
 	// ========== RUTAS PROTEGIDAS (REQUIEREN JWT) ==========
 	protected := router.Group("/")
-	protected.Use(middleware.JWTAuthMiddleware(cfg.JWT.Secret))
+	protected.Use(middleware.JWTAuthMiddleware(tokenVerifier))
 	{
 		// Inscripciones (requieren autenticación)
 		protected.GET("/inscripciones", inscripcionesController.List)
 		protected.POST("/inscripciones", inscripcionesController.Create)
 		protected.DELETE("/inscripciones", inscripcionesController.Deactivate)
+
+		// Lista de espera (posición, confirmar el lugar ofrecido, salir de la cola)
+		protected.GET("/inscripciones/lista-espera", inscripcionesController.ListListaEspera)
+		protected.POST("/inscripciones/lista-espera/:id/confirmar", inscripcionesController.ConfirmarListaEspera)
+		protected.DELETE("/inscripciones/lista-espera/:id", inscripcionesController.SalirListaEspera)
+
+		// Strikes por cancelaciones tardías y ausencias, y bloqueo vigente
+		protected.GET("/inscripciones/penalizaciones", penalizacionesController.MisPenalizaciones)
+
+		// Clases usadas del cupo del plan en el día, la semana o el mes
+		protected.GET("/inscripciones/cupo", cuposController.MiCupo)
+
+		// URL secreta del calendario .ics del usuario
+		protected.GET("/inscripciones/calendario", calendarioController.MiCalendario)
+		protected.POST("/inscripciones/calendario/regenerar", calendarioController.RegenerarCalendario)
+
+		// Asistencias del socio (QR rotativo para el check-in e historial)
+		protected.GET("/asistencias/qr", asistenciasController.GetQR)
+		protected.GET("/asistencias", asistenciasController.List)
+
+		// Equipos de la clase (disponibilidad y reserva del equipo propio, ej: bicicleta 7)
+		protected.GET("/sesiones/:id/equipos", salasController.ListEquiposSesion)
+		protected.POST("/sesiones/:id/equipos", salasController.ReservarEquipo)
+		protected.DELETE("/sesiones/:id/equipos", salasController.CancelarReservaEquipo)
+
+		// Calificación de una clase terminada (una por sesión, solo si asistió o estaba inscripto)
+		protected.POST("/sesiones/:id/calificacion", calificacionesController.Calificar)
 	}
 
+	// ========== RUTAS DE STAFF (REQUIEREN JWT + PERMISO) ==========
+	// El alcance por sucursal (branch_manager) y por clase (instructor) lo validan los handlers
+	manageActividades := protected.Group("/")
+	manageActividades.Use(middleware.RequirePermission(auth.PermActivitiesManage))
+	{
+		// Actividades (CRUD: owners en todas las sucursales, branch_manager en las suyas)
+		manageActividades.POST("/actividades", actividadesController.Create)
+		manageActividades.PUT("/actividades/:id", actividadesController.Update)
+		manageActividades.DELETE("/actividades/:id", actividadesController.Delete)
+
+		// Cronograma en planilla CSV/XLSX (cada fila se valida contra la sucursal de la actividad)
+		manageActividades.POST("/actividades/importar", actividadesController.Importar)
+		manageActividades.GET("/actividades/exportar", actividadesController.Exportar)
+
+		// Sesiones puntuales (cupo, reemplazo de instructor, cancelación, reprogramación)
+		manageActividades.PUT("/sesiones/:id", sesionesController.Update)
+		manageActividades.POST("/sesiones/:id/reprogramar", sesionesController.Reprogramar)
+		manageActividades.POST("/actividades/:id/sesiones/cancelar", sesionesController.CancelarSesiones)
+
+		// Salas y equipos (owners en todas las sucursales, branch_manager en las suyas)
+		manageActividades.POST("/salas", salasController.Create)
+		manageActividades.PUT("/salas/:id", salasController.Update)
+		manageActividades.DELETE("/salas/:id", salasController.Delete)
+		manageActividades.POST("/salas/:id/equipos", salasController.CreateEquipo)
+		manageActividades.DELETE("/salas/:id/equipos/:equipo_id", salasController.DeleteEquipo)
+	}
+
+	// Inscriptos de una clase (receptionist/branch_manager por sucursal, instructor solo sus clases)
+	protected.GET("/actividades/:id/inscripciones",
+		middleware.RequirePermission(auth.PermRostersRead, auth.PermOwnRostersRead),
+		inscripcionesController.ListByActividad)
+
+	// Check-in con el QR del socio (receptionist/branch_manager en su sucursal)
+	protected.POST("/asistencias/checkin",
+		middleware.RequirePermission(auth.PermAttendanceCheckIn),
+		asistenciasController.CheckIn)
+
+	// Reporte de asistencia de una clase (branch_manager en su sucursal)
+	protected.GET("/actividades/:id/asistencias",
+		middleware.RequirePermission(auth.PermAttendanceRead),
+		asistenciasController.Reporte)
+
 	// ========== RUTAS DE ADMIN (REQUIEREN JWT + ADMIN) ==========
 	adminOnly := protected.Group("/")
 	adminOnly.Use(middleware.AdminOnlyMiddleware())
 	{
-		// Actividades (CRUD completo solo admin)
-		adminOnly.POST("/actividades", actividadesController.Create)
-		adminOnly.PUT("/actividades/:id", actividadesController.Update)
-		adminOnly.DELETE("/actividades/:id", actividadesController.Delete)
-
-		// TODO: Sucursales (CRUD completo solo admin)
-		// adminOnly.POST("/sucursales", sucursalesController.Create)
-		// adminOnly.PUT("/sucursales/:id", sucursalesController.Update)
-		// adminOnly.DELETE("/sucursales/:id", sucursalesController.Delete)
+		// Sucursales (CRUD completo solo admin; la baja se rechaza si quedan actividades activas)
+		adminOnly.POST("/sucursales", sucursalesController.Create)
+		adminOnly.PUT("/sucursales/:id", sucursalesController.Update)
+		adminOnly.DELETE("/sucursales/:id", sucursalesController.Delete)
+
+		// Instructores (la baja se rechaza si todavía dictan actividades activas)
+		adminOnly.POST("/instructores", instructoresController.Create)
+		adminOnly.PUT("/instructores/:id", instructoresController.Update)
+		adminOnly.DELETE("/instructores/:id", instructoresController.Delete)
+
+		// Políticas de cancelación (por plan, por categoría o general) y anulación de strikes
+		adminOnly.GET("/politicas-cancelacion", penalizacionesController.ListPoliticas)
+		adminOnly.POST("/politicas-cancelacion", penalizacionesController.CreatePolitica)
+		adminOnly.PUT("/politicas-cancelacion/:id", penalizacionesController.UpdatePolitica)
+		adminOnly.DELETE("/politicas-cancelacion/:id", penalizacionesController.DeletePolitica)
+		adminOnly.GET("/usuarios/:id/penalizaciones", penalizacionesController.EstadoUsuario)
+		adminOnly.POST("/strikes/:id/anular", penalizacionesController.AnularStrike)
+		adminOnly.GET("/usuarios/:id/cupo", cuposController.CupoUsuario)
+
+		// Copia local de suscripciones y planes
+		adminOnly.POST("/suscripciones/sincronizar", suscripcionesController.Sincronizar)
+
+		// Moderación de calificaciones (las ocultas no cuentan en los promedios)
+		adminOnly.GET("/calificaciones", calificacionesController.List)
+		adminOnly.POST("/calificaciones/:id/ocultar", calificacionesController.Ocultar)
+		adminOnly.POST("/calificaciones/:id/mostrar", calificacionesController.Mostrar)
+		adminOnly.POST("/calificaciones/:id/marcar", calificacionesController.Marcar)
+		adminOnly.POST("/calificaciones/:id/desmarcar", calificacionesController.Desmarcar)
 	}
 
 	// ========== INICIAR SERVIDOR ==========
@@ -127,15 +342,71 @@ func main() {
 	log.Printf("🚀 Activities API running on port %s", port)
 	log.Printf("📋 Endpoints disponibles:")
 	log.Printf("   GET    /healthz")
+	log.Printf("   GET    /metrics/clients")
 	log.Printf("   GET    /actividades")
 	log.Printf("   GET    /actividades/buscar?id=&titulo=&horario=&categoria=")
 	log.Printf("   GET    /actividades/:id")
-	log.Printf("   POST   /actividades (admin)")
-	log.Printf("   PUT    /actividades/:id (admin)")
-	log.Printf("   DELETE /actividades/:id (admin)")
+	log.Printf("   GET    /actividades/:id/sesiones?desde=&hasta=")
+	log.Printf("   GET    /sesiones/:id")
+	log.Printf("   GET    /sucursales")
+	log.Printf("   GET    /sucursales/:id")
+	log.Printf("   GET    /sucursales/:id/calendar.ics")
+	log.Printf("   GET    /inscripciones/calendar.ics?token=")
+	log.Printf("   POST   /sucursales (admin)")
+	log.Printf("   PUT    /sucursales/:id (admin)")
+	log.Printf("   DELETE /sucursales/:id (admin)")
+	log.Printf("   GET    /sucursales/:id/salas")
+	log.Printf("   GET    /salas/:id")
+	log.Printf("   GET    /instructores")
+	log.Printf("   GET    /instructores/:id")
+	log.Printf("   GET    /instructores/:id/agenda?desde=&hasta=")
+	log.Printf("   GET    /actividades/:id/calificaciones")
+	log.Printf("   GET    /instructores/:id/calificaciones")
+	log.Printf("   POST   /instructores (admin)")
+	log.Printf("   PUT    /instructores/:id (admin)")
+	log.Printf("   DELETE /instructores/:id (admin)")
+	log.Printf("   GET    /politicas-cancelacion (admin)")
+	log.Printf("   POST   /politicas-cancelacion (admin)")
+	log.Printf("   PUT    /politicas-cancelacion/:id (admin)")
+	log.Printf("   DELETE /politicas-cancelacion/:id (admin)")
+	log.Printf("   GET    /usuarios/:id/penalizaciones (admin)")
+	log.Printf("   POST   /strikes/:id/anular (admin)")
+	log.Printf("   GET    /usuarios/:id/cupo?fecha=&sucursal_id= (admin)")
+	log.Printf("   POST   /suscripciones/sincronizar (admin)")
+	log.Printf("   GET    /calificaciones?marcadas=&ocultas=&actividad_id=&instructor_id= (admin)")
+	log.Printf("   POST   /calificaciones/:id/ocultar|mostrar|marcar|desmarcar (admin)")
+	log.Printf("   PUT    /sesiones/:id (activities:manage)")
+	log.Printf("   POST   /sesiones/:id/reprogramar (activities:manage)")
+	log.Printf("   POST   /actividades/:id/sesiones/cancelar (activities:manage)")
+	log.Printf("   POST   /salas (activities:manage)")
+	log.Printf("   PUT    /salas/:id (activities:manage)")
+	log.Printf("   DELETE /salas/:id (activities:manage)")
+	log.Printf("   POST   /salas/:id/equipos (activities:manage)")
+	log.Printf("   DELETE /salas/:id/equipos/:equipo_id (activities:manage)")
+	log.Printf("   POST   /actividades (activities:manage)")
+	log.Printf("   PUT    /actividades/:id (activities:manage)")
+	log.Printf("   DELETE /actividades/:id (activities:manage)")
+	log.Printf("   POST   /actividades/importar?dry_run=&formato=csv|xlsx (activities:manage)")
+	log.Printf("   GET    /actividades/exportar?formato=csv|xlsx&sucursal_id= (activities:manage)")
+	log.Printf("   GET    /actividades/:id/inscripciones (rosters:read | rosters:read_own)")
+	log.Printf("   GET    /actividades/:id/asistencias?desde=&hasta= (attendance:read)")
+	log.Printf("   POST   /asistencias/checkin (attendance:check_in)")
 	log.Printf("   GET    /inscripciones (auth)")
 	log.Printf("   POST   /inscripciones (auth)")
 	log.Printf("   DELETE /inscripciones (auth)")
+	log.Printf("   GET    /inscripciones/lista-espera (auth)")
+	log.Printf("   POST   /inscripciones/lista-espera/:id/confirmar (auth)")
+	log.Printf("   DELETE /inscripciones/lista-espera/:id (auth)")
+	log.Printf("   GET    /inscripciones/penalizaciones (auth)")
+	log.Printf("   GET    /inscripciones/cupo?fecha=&sucursal_id= (auth)")
+	log.Printf("   GET    /inscripciones/calendario (auth)")
+	log.Printf("   POST   /inscripciones/calendario/regenerar (auth)")
+	log.Printf("   GET    /asistencias (auth)")
+	log.Printf("   GET    /asistencias/qr (auth)")
+	log.Printf("   GET    /sesiones/:id/equipos (auth)")
+	log.Printf("   POST   /sesiones/:id/equipos (auth)")
+	log.Printf("   DELETE /sesiones/:id/equipos (auth)")
+	log.Printf("   POST   /sesiones/:id/calificacion (auth)")
 
 	if err := router.Run(fmt.Sprintf(":%s", port)); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
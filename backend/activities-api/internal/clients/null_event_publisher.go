@@ -22,3 +22,15 @@ func (n *NullEventPublisher) PublishInscriptionEvent(action, inscriptionID strin
 	log.Printf("⚠️  [NullEventPublisher] Evento no publicado: inscription.%s (ID: %s)", action, inscriptionID)
 	return nil
 }
+
+// PublishAttendanceEvent - Implementa la interface EventPublisher sin hacer nada
+func (n *NullEventPublisher) PublishAttendanceEvent(action, attendanceID string, data map[string]interface{}) error {
+	log.Printf("⚠️  [NullEventPublisher] Evento no publicado: attendance.%s (ID: %s)", action, attendanceID)
+	return nil
+}
+
+// PublishBranchEvent - Implementa la interface EventPublisher sin hacer nada
+func (n *NullEventPublisher) PublishBranchEvent(action, branchID string, data map[string]interface{}) error {
+	log.Printf("⚠️  [NullEventPublisher] Evento no publicado: branch.%s (ID: %s)", action, branchID)
+	return nil
+}
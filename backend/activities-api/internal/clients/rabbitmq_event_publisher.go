@@ -138,6 +138,82 @@ func (r *RabbitMQEventPublisher) PublishInscriptionEvent(action, inscriptionID s
 	return nil
 }
 
+// PublishAttendanceEvent - Publica eventos de asistencia (check-in)
+func (r *RabbitMQEventPublisher) PublishAttendanceEvent(action, attendanceID string, data map[string]interface{}) error {
+	event := rabbitMQEvent{
+		Action:    action,
+		Type:      "attendance",
+		ID:        attendanceID,
+		Timestamp: time.Now(),
+		Data:      data,
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error serializando evento: %w", err)
+	}
+
+	// Routing key: attendance.{action}
+	routingKey := fmt.Sprintf("attendance.%s", action)
+
+	err = r.channel.Publish(
+		r.exchange, // exchange
+		routingKey, // routing key
+		false,      // mandatory
+		false,      // immediate
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		log.Printf("❌ Error publicando evento: %v\n", err)
+		return fmt.Errorf("error publicando evento: %w", err)
+	}
+
+	log.Printf("📤 Evento publicado: %s (ID: %s)\n", routingKey, attendanceID)
+	return nil
+}
+
+// PublishBranchEvent - Publica eventos de sucursal (alta, cambios, baja)
+func (r *RabbitMQEventPublisher) PublishBranchEvent(action, branchID string, data map[string]interface{}) error {
+	event := rabbitMQEvent{
+		Action:    action,
+		Type:      "branch",
+		ID:        branchID,
+		Timestamp: time.Now(),
+		Data:      data,
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error serializando evento: %w", err)
+	}
+
+	// Routing key: branch.{action}
+	routingKey := fmt.Sprintf("branch.%s", action)
+
+	err = r.channel.Publish(
+		r.exchange, // exchange
+		routingKey, // routing key
+		false,      // mandatory
+		false,      // immediate
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		log.Printf("❌ Error publicando evento: %v\n", err)
+		return fmt.Errorf("error publicando evento: %w", err)
+	}
+
+	log.Printf("📤 Evento publicado: %s (ID: %s)\n", routingKey, branchID)
+	return nil
+}
+
 // Close - Cierra la conexión
 func (r *RabbitMQEventPublisher) Close() error {
 	if r.channel != nil {
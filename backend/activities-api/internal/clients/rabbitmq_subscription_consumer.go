@@ -6,15 +6,22 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/streadway/amqp"
 )
 
-// SubscriptionEventHandler define la interfaz para manejar eventos de suscripciones
+// SubscriptionEventHandler define la interfaz para manejar eventos de suscripciones y planes
 type SubscriptionEventHandler interface {
+	// HandleSubscriptionEvent y HandlePlanEvent mantienen la copia local de suscripciones y planes
+	HandleSubscriptionEvent(ctx context.Context, action, subscriptionID string, occurredAt time.Time, data map[string]interface{}) error
+	HandlePlanEvent(ctx context.Context, action, planID string, occurredAt time.Time, data map[string]interface{}) error
 	HandleSubscriptionCancelled(ctx context.Context, usuarioID uint) error
 }
 
+// Routing keys que escucha la queue: todos los eventos de suscripciones y de planes
+var subscriptionRoutingKeys = []string{"subscription.*", "plan.*"}
+
 // RabbitMQSubscriptionConsumer consume eventos de suscripciones desde RabbitMQ
 type RabbitMQSubscriptionConsumer struct {
 	conn     *amqp.Connection
@@ -78,22 +85,24 @@ func NewRabbitMQSubscriptionConsumer(url, exchange string, handler SubscriptionE
 		return nil, fmt.Errorf("error declarando queue: %w", err)
 	}
 
-	// Bind queue a eventos de suscripción cancelada
-	routingKey := "subscription.cancelled"
-	err = channel.QueueBind(
-		queue.Name, // queue name
-		routingKey, // routing key
-		exchange,   // exchange
-		false,
-		nil,
-	)
-	if err != nil {
-		channel.Close()
-		conn.Close()
-		return nil, fmt.Errorf("error bindeando queue: %w", err)
+	// Bind queue a los eventos de suscripciones y planes
+	// (la queue existente ya tenía subscription.cancelled: un mensaje que matchea dos bindings llega una sola vez)
+	for _, routingKey := range subscriptionRoutingKeys {
+		err = channel.QueueBind(
+			queue.Name, // queue name
+			routingKey, // routing key
+			exchange,   // exchange
+			false,
+			nil,
+		)
+		if err != nil {
+			channel.Close()
+			conn.Close()
+			return nil, fmt.Errorf("error bindeando queue (%s): %w", routingKey, err)
+		}
 	}
 
-	log.Printf("✅ RabbitMQ Subscription Consumer conectado (Exchange: %s, Queue: %s, Routing: %s)\n", exchange, queueName, routingKey)
+	log.Printf("✅ RabbitMQ Subscription Consumer conectado (Exchange: %s, Queue: %s, Routing: %v)\n", exchange, queueName, subscriptionRoutingKeys)
 
 	return &RabbitMQSubscriptionConsumer{
 		conn:    conn,
@@ -119,7 +128,7 @@ func (c *RabbitMQSubscriptionConsumer) Start() error {
 		return fmt.Errorf("error consumiendo mensajes: %w", err)
 	}
 
-	log.Println("🎧 Activities-API escuchando eventos de suscripciones canceladas...")
+	log.Println("🎧 Activities-API escuchando eventos de suscripciones y planes...")
 
 	go func() {
 		for {
@@ -151,9 +160,29 @@ func (c *RabbitMQSubscriptionConsumer) processMessage(msg amqp.Delivery) {
 		return
 	}
 
-	// Verificar que sea un evento de cancelación
+	ctx := context.Background()
+	occurredAt := event.occurredAt()
+
+	// Eventos de planes: solo actualizan la copia local
+	if event.Type == "plan" {
+		if err := c.handler.HandlePlanEvent(ctx, event.Action, event.ID, occurredAt, event.Data); err != nil {
+			log.Printf("❌ [SubscriptionConsumer] Error aplicando plan.%s: %v\n", event.Action, err)
+			msg.Nack(false, true) // Requeue para reintentar
+			return
+		}
+		msg.Ack(false)
+		return
+	}
+
+	// Todo evento de suscripción actualiza la copia local
+	if err := c.handler.HandleSubscriptionEvent(ctx, event.Action, event.ID, occurredAt, event.Data); err != nil {
+		log.Printf("❌ [SubscriptionConsumer] Error aplicando subscription.%s: %v\n", event.Action, err)
+		msg.Nack(false, true) // Requeue para reintentar
+		return
+	}
+
+	// La cancelación además desinscribe al usuario de sus actividades
 	if event.Action != "cancelled" {
-		log.Printf("⚠️ [SubscriptionConsumer] Evento ignorado (action: %s)\n", event.Action)
 		msg.Ack(false)
 		return
 	}
@@ -188,7 +217,6 @@ func (c *RabbitMQSubscriptionConsumer) processMessage(msg amqp.Delivery) {
 	log.Printf("🔄 [SubscriptionConsumer] Procesando cancelación de suscripción para usuario %d\n", usuarioID)
 
 	// Llamar al handler para desinscribir al usuario
-	ctx := context.Background()
 	if err := c.handler.HandleSubscriptionCancelled(ctx, usuarioID); err != nil {
 		log.Printf("❌ [SubscriptionConsumer] Error manejando evento: %v\n", err)
 		msg.Nack(false, true) // Requeue para reintentar
@@ -199,6 +227,14 @@ func (c *RabbitMQSubscriptionConsumer) processMessage(msg amqp.Delivery) {
 	msg.Ack(false)
 }
 
+// occurredAt devuelve el momento del evento (el timestamp del publicador, o ahora si no se puede leer)
+func (e SubscriptionEvent) occurredAt() time.Time {
+	if t, err := time.Parse(time.RFC3339Nano, e.Timestamp); err == nil {
+		return t
+	}
+	return time.Now()
+}
+
 // Stop detiene el consumer
 func (c *RabbitMQSubscriptionConsumer) Stop() {
 	c.done <- true
@@ -2,12 +2,19 @@ package controllers
 
 import (
 	"activities-api/internal/domain"
+	"activities-api/internal/middleware"
 	"activities-api/internal/services"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gym-management/shared/auth"
 )
 
 // ActividadesController maneja las peticiones HTTP relacionadas con actividades
@@ -116,7 +123,7 @@ func (c *ActividadesController) GetByID(ctx *gin.Context) {
 }
 
 // Create crea una nueva actividad
-// POST /actividades (admin only)
+// POST /actividades (activities:manage en la sucursal de la actividad)
 // Migrado de backend/controllers/actividad/actividad_controller.go:56
 func (c *ActividadesController) Create(ctx *gin.Context) {
 	var actividadCreate domain.ActividadCreate
@@ -125,8 +132,23 @@ func (c *ActividadesController) Create(ctx *gin.Context) {
 		return
 	}
 
+	if !canManageActividad(ctx, actividadCreate.SucursalID) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "No podés gestionar actividades de esta sucursal"})
+		return
+	}
+
 	createdActividad, err := c.service.Create(ctx.Request.Context(), actividadCreate)
 	if err != nil {
+		if errors.Is(err, services.ErrSucursalInexistente) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if respondInstructorError(ctx, err) {
+			return
+		}
+		if respondSalaError(ctx, err) {
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear la actividad", "details": err.Error()})
 		return
 	}
@@ -135,7 +157,7 @@ func (c *ActividadesController) Create(ctx *gin.Context) {
 }
 
 // Update actualiza una actividad existente
-// PUT /actividades/:id (admin only)
+// PUT /actividades/:id (activities:manage en la sucursal actual y en la nueva)
 // Migrado de backend/controllers/actividad/actividad_controller.go:73
 func (c *ActividadesController) Update(ctx *gin.Context) {
 	idActividad, err := strconv.Atoi(ctx.Param("id"))
@@ -150,13 +172,23 @@ func (c *ActividadesController) Update(ctx *gin.Context) {
 		return
 	}
 
+	if !c.canManageExisting(ctx, uint(idActividad)) {
+		return
+	}
+	if !canManageActividad(ctx, actividadUpdate.SucursalID) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "No podés gestionar actividades de esta sucursal"})
+		return
+	}
+
 	updatedActividad, err := c.service.Update(ctx.Request.Context(), uint(idActividad), actividadUpdate)
 	if err != nil {
 		errString := err.Error()
 
 		// Detectar errores específicos del hook BeforeUpdate
-		if strings.Contains(errString, "inscripciones activas que superan el nuevo límite") {
+		if strings.Contains(errString, "inscripciones activas que superan el nuevo límite") || errors.Is(err, services.ErrSucursalInexistente) {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else if respondInstructorError(ctx, err) || respondSalaError(ctx, err) {
+			return
 		} else if strings.Contains(errString, "not found") {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "Actividad no encontrada"})
 		} else {
@@ -169,7 +201,7 @@ func (c *ActividadesController) Update(ctx *gin.Context) {
 }
 
 // Delete elimina una actividad
-// DELETE /actividades/:id (admin only)
+// DELETE /actividades/:id (activities:manage en la sucursal de la actividad)
 // Migrado de backend/controllers/actividad/actividad_controller.go:109
 func (c *ActividadesController) Delete(ctx *gin.Context) {
 	idActividad, err := strconv.Atoi(ctx.Param("id"))
@@ -178,6 +210,10 @@ func (c *ActividadesController) Delete(ctx *gin.Context) {
 		return
 	}
 
+	if !c.canManageExisting(ctx, uint(idActividad)) {
+		return
+	}
+
 	err = c.service.Delete(ctx.Request.Context(), uint(idActividad))
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
@@ -190,3 +226,162 @@ func (c *ActividadesController) Delete(ctx *gin.Context) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// maxTamanoPlanilla limita el archivo de la importación de horarios
+const maxTamanoPlanilla = 5 << 20
+
+// contentTypeXLSX es el tipo MIME de las planillas de Excel
+const contentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// Importar carga el cronograma desde una planilla CSV o XLSX: filas sin id crean actividades, con id las modifican
+// POST /actividades/importar?dry_run=true&formato=csv (multipart con el campo "archivo" o el archivo como body)
+// Cada fila se valida como POST/PUT /actividades y se informa su error; las válidas se guardan en una transacción
+func (c *ActividadesController) Importar(ctx *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "dry_run debe ser true o false"})
+		return
+	}
+
+	data, formato, err := leerArchivoPlanilla(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filas, err := services.LeerPlanilla(data, formato)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resultado, err := c.service.Importar(ctx.Request.Context(), *claims, filas, dryRun)
+	if err != nil {
+		// Falló el guardado: la transacción se revirtió y no se importó ninguna fila
+		if strings.Contains(err.Error(), "inscripciones activas que superan el nuevo límite") {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "No se importó ninguna fila", "details": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se importó ninguna fila", "details": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resultado)
+}
+
+// leerArchivoPlanilla obtiene el archivo (multipart o body) y su formato:
+// ?formato=, si no la extensión del archivo, si no el Content-Type (por defecto CSV)
+func leerArchivoPlanilla(ctx *gin.Context) ([]byte, string, error) {
+	var origen io.Reader = ctx.Request.Body
+	nombre, contentType := "", ctx.ContentType()
+
+	if strings.HasPrefix(contentType, "multipart/") {
+		archivo, err := ctx.FormFile("archivo")
+		if err != nil {
+			return nil, "", errors.New("falta el archivo (campo \"archivo\")")
+		}
+		f, err := archivo.Open()
+		if err != nil {
+			return nil, "", fmt.Errorf("no se pudo leer el archivo: %w", err)
+		}
+		defer f.Close()
+		origen, nombre, contentType = f, archivo.Filename, archivo.Header.Get("Content-Type")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(origen, maxTamanoPlanilla+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("no se pudo leer el archivo: %w", err)
+	}
+	if len(data) > maxTamanoPlanilla {
+		return nil, "", fmt.Errorf("el archivo supera los %d MB", maxTamanoPlanilla>>20)
+	}
+
+	formato := strings.ToLower(ctx.Query("formato"))
+	switch {
+	case formato != "":
+	case strings.EqualFold(filepath.Ext(nombre), ".xlsx"), contentType == contentTypeXLSX:
+		formato = services.FormatoXLSX
+	default:
+		formato = services.FormatoCSV
+	}
+
+	return data, formato, nil
+}
+
+// Exportar descarga el cronograma en el mismo formato que acepta la importación
+// GET /actividades/exportar?formato=csv|xlsx&sucursal_id=1
+func (c *ActividadesController) Exportar(ctx *gin.Context) {
+	var sucursalID *uint
+	if param := ctx.Query("sucursal_id"); param != "" {
+		id, err := strconv.Atoi(param)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "sucursal_id debe ser un número"})
+			return
+		}
+		sucursal := uint(id)
+		sucursalID = &sucursal
+	}
+
+	var contentType string
+	formato := strings.ToLower(ctx.DefaultQuery("formato", services.FormatoCSV))
+	switch formato {
+	case services.FormatoCSV:
+		contentType = "text/csv; charset=utf-8"
+	case services.FormatoXLSX:
+		contentType = contentTypeXLSX
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": services.ErrFormatoPlanilla.Error()})
+		return
+	}
+
+	actividades, err := c.service.Exportar(ctx.Request.Context(), sucursalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al exportar el cronograma", "details": err.Error()})
+		return
+	}
+
+	var planilla bytes.Buffer
+	if err := services.EscribirPlanilla(&planilla, formato, actividades); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al exportar el cronograma", "details": err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "horarios."+formato))
+	ctx.Data(http.StatusOK, contentType, planilla.Bytes())
+}
+
+// canManageExisting valida que el usuario pueda gestionar la actividad guardada
+// Si no puede (o no existe) ya responde el error
+func (c *ActividadesController) canManageExisting(ctx *gin.Context, id uint) bool {
+	actividad, err := c.service.GetByID(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Actividad no encontrada"})
+		return false
+	}
+
+	if !canManageActividad(ctx, actividad.SucursalID) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "No podés gestionar actividades de esta sucursal"})
+		return false
+	}
+
+	return true
+}
+
+// canManageActividad indica si el usuario tiene activities:manage en la sucursal
+// Las actividades sin sucursal solo las gestiona un owner
+func canManageActividad(ctx *gin.Context, sucursalID *uint) bool {
+	claims, ok := middleware.ClaimsFromContext(ctx)
+	if !ok {
+		return false
+	}
+	if sucursalID == nil {
+		return claims.HasGlobalPermission(auth.PermActivitiesManage)
+	}
+	return claims.CanInBranch(auth.PermActivitiesManage, *sucursalID)
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/middleware"
+	"activities-api/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AsistenciasController maneja el QR de los socios, el check-in y los reportes de asistencia
+type AsistenciasController struct {
+	service services.AsistenciasService
+}
+
+// NewAsistenciasController crea una nueva instancia del controller
+func NewAsistenciasController(service services.AsistenciasService) *AsistenciasController {
+	return &AsistenciasController{
+		service: service,
+	}
+}
+
+// GetQR devuelve el QR vigente del usuario autenticado (la app lo renueva al llegar a expira_en)
+// GET /asistencias/qr (requiere JWT)
+func (c *AsistenciasController) GetQR(ctx *gin.Context) {
+	userID, exists := ctx.Get("id_usuario")
+	if !exists {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.service.GenerarQR(userID.(uint)))
+}
+
+// List obtiene las últimas asistencias del usuario autenticado
+// GET /asistencias (requiere JWT)
+func (c *AsistenciasController) List(ctx *gin.Context) {
+	userID, exists := ctx.Get("id_usuario")
+	if !exists {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	asistencias, err := c.service.ListByUser(ctx.Request.Context(), userID.(uint))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al procesar la consulta"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, asistencias)
+}
+
+// CheckIn registra la asistencia del socio a partir de su QR
+// POST /asistencias/checkin (attendance:check_in en la sucursal de la clase o de la sala libre)
+func (c *AsistenciasController) CheckIn(ctx *gin.Context) {
+	claims, ok := middleware.ClaimsFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	var req domain.CheckInRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos con formato incorrecto", "details": err.Error()})
+		return
+	}
+
+	asistencia, err := c.service.CheckIn(ctx.Request.Context(), *claims, req, ctx.GetHeader("Authorization"))
+	if err != nil {
+		if respondAsistenciaError(ctx, err) || respondSesionError(ctx, err) {
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Sesión no encontrada"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al registrar la asistencia", "details": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, asistencia)
+}
+
+// Reporte devuelve presentes, ausentes y tasa de asistencia por sesión de una actividad
+// GET /actividades/:id/asistencias?desde=2025-01-01&hasta=2025-01-31 (attendance:read en la sucursal de la actividad)
+func (c *AsistenciasController) Reporte(ctx *gin.Context) {
+	idActividad, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	claims, ok := middleware.ClaimsFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	reporte, err := c.service.Reporte(ctx.Request.Context(), uint(idActividad), *claims, ctx.Query("desde"), ctx.Query("hasta"))
+	if err != nil {
+		if respondAsistenciaError(ctx, err) || respondSesionError(ctx, err) {
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "La actividad no existe"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al armar el reporte de asistencia"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reporte)
+}
+
+// respondAsistenciaError responde los errores tipados de asistencias
+// Devuelve false si err no es uno de ellos
+func respondAsistenciaError(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrQRInvalido),
+		errors.Is(err, services.ErrQRVencido),
+		errors.Is(err, services.ErrSucursalRequerida):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCheckInForbidden),
+		errors.Is(err, services.ErrReporteAsistenciaForbidden):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSinPlanActivo):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": services.ErrSinPlanActivo.Error()})
+	case errors.Is(err, domain.ErrAsistenciaYaRegistrada),
+		errors.Is(err, services.ErrSesionFueraDeHorario),
+		errors.Is(err, services.ErrNoInscriptoEnSesion):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/services"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarioController maneja los calendarios iCalendar (.ics) de socios y sucursales
+type CalendarioController struct {
+	service    services.CalendarioService
+	urlPublica string // Base de las URLs de los calendarios; vacía = la del request
+}
+
+// NewCalendarioController crea una nueva instancia del controller
+func NewCalendarioController(service services.CalendarioService, urlPublica string) *CalendarioController {
+	return &CalendarioController{
+		service:    service,
+		urlPublica: strings.TrimRight(urlPublica, "/"),
+	}
+}
+
+// MiCalendario devuelve la URL secreta del calendario del usuario para suscribirse desde Google/Apple Calendar
+// GET /inscripciones/calendario (requiere JWT)
+func (c *CalendarioController) MiCalendario(ctx *gin.Context) {
+	userID, exists := ctx.Get("id_usuario")
+	if !exists {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	token, err := c.service.Token(ctx.Request.Context(), userID.(uint))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el calendario"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.feed(ctx, token))
+}
+
+// RegenerarCalendario cambia la URL secreta del calendario (la anterior deja de funcionar)
+// POST /inscripciones/calendario/regenerar (requiere JWT)
+func (c *CalendarioController) RegenerarCalendario(ctx *gin.Context) {
+	userID, exists := ctx.Get("id_usuario")
+	if !exists {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	token, err := c.service.RegenerarToken(ctx.Request.Context(), userID.(uint))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al regenerar el calendario"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.feed(ctx, token))
+}
+
+// CalendarioUsuario sirve el calendario de las clases del socio
+// GET /inscripciones/calendar.ics?token= (sin JWT: el token de la URL identifica al socio)
+func (c *CalendarioController) CalendarioUsuario(ctx *gin.Context) {
+	ics, err := c.service.CalendarioUsuario(ctx.Request.Context(), ctx.Query("token"))
+	if err != nil {
+		if errors.Is(err, domain.ErrCalendarioTokenInvalido) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al armar el calendario"})
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, max-age=900")
+	respondICS(ctx, "mis-clases.ics", ics)
+}
+
+// CalendarioSucursal sirve el calendario semanal de las clases de la sucursal
+// GET /sucursales/:id/calendar.ics
+func (c *CalendarioController) CalendarioSucursal(ctx *gin.Context) {
+	idSucursal, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	ics, err := c.service.CalendarioSucursal(ctx.Request.Context(), uint(idSucursal))
+	if err != nil {
+		if respondSucursalError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al armar el calendario"})
+		return
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=900")
+	respondICS(ctx, fmt.Sprintf("sucursal-%d.ics", idSucursal), ics)
+}
+
+// feed arma la URL del calendario con el token
+func (c *CalendarioController) feed(ctx *gin.Context, token string) domain.CalendarioFeed {
+	base := c.urlPublica
+	if base == "" {
+		// Detrás del proxy se usa el host y el esquema que vio el cliente
+		scheme := ctx.GetHeader("X-Forwarded-Proto")
+		if scheme == "" {
+			scheme = "http"
+			if ctx.Request.TLS != nil {
+				scheme = "https"
+			}
+		}
+		host := ctx.GetHeader("X-Forwarded-Host")
+		if host == "" {
+			host = ctx.Request.Host
+		}
+		base = scheme + "://" + host
+	}
+
+	return domain.CalendarioFeed{
+		Token: token,
+		URL:   base + "/inscripciones/calendar.ics?token=" + url.QueryEscape(token),
+	}
+}
+
+func respondICS(ctx *gin.Context, archivo string, ics []byte) {
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", archivo))
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", ics)
+}
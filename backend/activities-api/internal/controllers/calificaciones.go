@@ -0,0 +1,232 @@
+package controllers
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// limiteCalificacionesAdmin es la cantidad máxima de calificaciones del listado de moderación
+const limiteCalificacionesAdmin = 200
+
+// CalificacionesController maneja las peticiones HTTP de calificaciones de clases
+type CalificacionesController struct {
+	service services.CalificacionesService
+}
+
+// NewCalificacionesController crea una nueva instancia del controller
+func NewCalificacionesController(service services.CalificacionesService) *CalificacionesController {
+	return &CalificacionesController{
+		service: service,
+	}
+}
+
+// Calificar registra la calificación del usuario autenticado sobre una sesión terminada
+// POST /sesiones/:id/calificacion {"puntaje": 1-5, "comentario": "..."} (requiere JWT)
+func (c *CalificacionesController) Calificar(ctx *gin.Context) {
+	userID, exists := ctx.Get("id_usuario")
+	if !exists {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	idSesion, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	var input domain.CalificacionCreate
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos: el puntaje debe ser de 1 a 5", "details": err.Error()})
+		return
+	}
+
+	calificacion, err := c.service.Calificar(ctx.Request.Context(), userID.(uint), uint(idSesion), input)
+	if err != nil {
+		if respondCalificacionError(ctx, err) {
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "La sesión no existe"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al guardar la calificación"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, calificacion)
+}
+
+// ListByActividad obtiene el promedio y las últimas calificaciones visibles de una actividad
+// GET /actividades/:id/calificaciones
+func (c *CalificacionesController) ListByActividad(ctx *gin.Context) {
+	idActividad, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	calificaciones, err := c.service.ListByActividad(ctx.Request.Context(), uint(idActividad))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "La actividad no existe"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar las calificaciones"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, calificaciones)
+}
+
+// ListByInstructor obtiene el promedio y las últimas calificaciones visibles de un instructor
+// GET /instructores/:id/calificaciones
+func (c *CalificacionesController) ListByInstructor(ctx *gin.Context) {
+	idInstructor, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	calificaciones, err := c.service.ListByInstructor(ctx.Request.Context(), uint(idInstructor))
+	if err != nil {
+		if errors.Is(err, domain.ErrInstructorNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "El instructor no existe"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar las calificaciones"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, calificaciones)
+}
+
+// List obtiene las calificaciones para moderar (también las ocultas)
+// GET /calificaciones?marcadas=true&ocultas=false&actividad_id=&instructor_id= (admin)
+func (c *CalificacionesController) List(ctx *gin.Context) {
+	filtro := domain.FiltroCalificaciones{Limit: limiteCalificacionesAdmin}
+
+	for param, destino := range map[string]**bool{"marcadas": &filtro.Marcada, "ocultas": &filtro.Oculta} {
+		if valor := ctx.Query(param); valor != "" {
+			b, err := strconv.ParseBool(valor)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": param + " debe ser true o false"})
+				return
+			}
+			*destino = &b
+		}
+	}
+	for param, destino := range map[string]**uint{"actividad_id": &filtro.ActividadID, "instructor_id": &filtro.InstructorPerfilID} {
+		if valor := ctx.Query(param); valor != "" {
+			id, err := strconv.Atoi(valor)
+			if err != nil || id <= 0 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": param + " debe ser un número"})
+				return
+			}
+			u := uint(id)
+			*destino = &u
+		}
+	}
+
+	calificaciones, err := c.service.List(ctx.Request.Context(), filtro)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar las calificaciones"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, calificaciones)
+}
+
+// Ocultar saca una calificación de los listados y de los promedios
+// POST /calificaciones/:id/ocultar {"motivo": "..."} (admin)
+func (c *CalificacionesController) Ocultar(ctx *gin.Context) {
+	c.moderar(ctx, func(id, adminID uint, motivo string) (domain.Calificacion, error) {
+		return c.service.Ocultar(ctx.Request.Context(), id, adminID, motivo)
+	})
+}
+
+// Mostrar vuelve a publicar una calificación oculta
+// POST /calificaciones/:id/mostrar (admin)
+func (c *CalificacionesController) Mostrar(ctx *gin.Context) {
+	c.moderar(ctx, func(id, adminID uint, _ string) (domain.Calificacion, error) {
+		return c.service.Mostrar(ctx.Request.Context(), id, adminID)
+	})
+}
+
+// Marcar deja una calificación para revisar
+// POST /calificaciones/:id/marcar {"motivo": "..."} (admin)
+func (c *CalificacionesController) Marcar(ctx *gin.Context) {
+	c.moderar(ctx, func(id, adminID uint, motivo string) (domain.Calificacion, error) {
+		return c.service.Marcar(ctx.Request.Context(), id, adminID, motivo)
+	})
+}
+
+// Desmarcar da por revisada una calificación marcada
+// POST /calificaciones/:id/desmarcar (admin)
+func (c *CalificacionesController) Desmarcar(ctx *gin.Context) {
+	c.moderar(ctx, func(id, adminID uint, _ string) (domain.Calificacion, error) {
+		return c.service.Desmarcar(ctx.Request.Context(), id, adminID)
+	})
+}
+
+// moderar lee el id, el admin y el motivo opcional y aplica la acción de moderación
+func (c *CalificacionesController) moderar(ctx *gin.Context, accion func(id, adminID uint, motivo string) (domain.Calificacion, error)) {
+	adminID, exists := ctx.Get("id_usuario")
+	if !exists {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	idCalificacion, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	// El motivo es opcional: el body puede venir vacío
+	var input domain.CalificacionModeracion
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+			return
+		}
+	}
+
+	calificacion, err := accion(uint(idCalificacion), adminID.(uint), input.Motivo)
+	if err != nil {
+		if respondCalificacionError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al moderar la calificación"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, calificacion)
+}
+
+// respondCalificacionError responde los errores tipados de calificaciones
+// Devuelve false si err no es uno de ellos
+func respondCalificacionError(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrCalificacionNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "La calificación no existe"})
+	case errors.Is(err, domain.ErrCalificacionDuplicada):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCalificacionSinParticipar):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCalificacionPuntaje):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCalificacionSesionCancel),
+		errors.Is(err, services.ErrCalificacionAntesDeClase):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
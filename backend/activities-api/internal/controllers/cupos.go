@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CuposController maneja las peticiones HTTP del cupo de clases de los planes
+type CuposController struct {
+	service services.CuposService
+}
+
+// NewCuposController crea una nueva instancia del controller
+func NewCuposController(service services.CuposService) *CuposController {
+	return &CuposController{
+		service: service,
+	}
+}
+
+// MiCupo obtiene las clases usadas del plan del usuario autenticado ("3 de 4 clases usadas esta semana")
+// GET /inscripciones/cupo?fecha=YYYY-MM-DD&sucursal_id=1 (requiere JWT)
+func (c *CuposController) MiCupo(ctx *gin.Context) {
+	userID, exists := ctx.Get("id_usuario")
+	if !exists {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	c.responderUso(ctx, userID.(uint))
+}
+
+// CupoUsuario obtiene las clases usadas del plan de un socio
+// GET /usuarios/:id/cupo?fecha=YYYY-MM-DD&sucursal_id=1 (admin)
+func (c *CuposController) CupoUsuario(ctx *gin.Context) {
+	idUsuario, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	c.responderUso(ctx, uint(idUsuario))
+}
+
+func (c *CuposController) responderUso(ctx *gin.Context, usuarioID uint) {
+	var sucursalID *uint
+	if param := ctx.Query("sucursal_id"); param != "" {
+		id, err := strconv.Atoi(param)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "sucursal_id debe ser un número"})
+			return
+		}
+		sucursal := uint(id)
+		sucursalID = &sucursal
+	}
+
+	uso, err := c.service.Uso(ctx.Request.Context(), usuarioID, ctx.Query("fecha"), sucursalID, ctx.GetHeader("Authorization"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrFechaInvalida):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrSucursalNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "La sucursal no existe"})
+		case errors.Is(err, services.ErrSinSuscripcionActiva):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "No hay un plan activo"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al calcular el cupo de clases", "details": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, uso)
+}
@@ -1,9 +1,13 @@
 package controllers
 
 import (
+	"activities-api/internal/domain"
+	"activities-api/internal/middleware"
 	"activities-api/internal/services"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -42,8 +46,40 @@ func (c *InscripcionesController) List(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, inscripciones)
 }
 
+// ListByActividad obtiene los inscriptos de una actividad
+// GET /actividades/:id/inscripciones (rosters:read en la sucursal o instructor de la clase)
+func (c *InscripcionesController) ListByActividad(ctx *gin.Context) {
+	idActividad, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	claims, ok := middleware.ClaimsFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	inscripciones, err := c.service.ListByActividad(ctx.Request.Context(), uint(idActividad), *claims)
+	if err != nil {
+		if errors.Is(err, services.ErrRosterForbidden) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		} else if strings.Contains(err.Error(), "not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "La actividad no existe"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al procesar la consulta"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, inscripciones)
+}
+
 // Create inscribe al usuario autenticado en una actividad
 // POST /inscripciones {"actividad_id": 1} (requiere JWT)
+// Con "sesion_id" reserva solo esa sesión en lugar de inscribirse todas las semanas
+// Con "lista_espera": true, si la clase está llena anota al usuario en la cola (202 con su posición)
 // Migrado de backend/controllers/inscripcion/incripcion_controller.go:31
 func (c *InscripcionesController) Create(ctx *gin.Context) {
 	// Obtener el ID del usuario del contexto (seteado por middleware JWT)
@@ -55,7 +91,9 @@ func (c *InscripcionesController) Create(ctx *gin.Context) {
 
 	// Parsear el body
 	var inscripcionCreate struct {
-		ActividadID uint `json:"actividad_id" binding:"required"`
+		ActividadID uint  `json:"actividad_id" binding:"required"`
+		SesionID    *uint `json:"sesion_id"`
+		ListaEspera bool  `json:"lista_espera"`
 	}
 	if err := ctx.ShouldBindJSON(&inscripcionCreate); err != nil {
 		// Log del error para debuggear
@@ -69,15 +107,47 @@ func (c *InscripcionesController) Create(ctx *gin.Context) {
 	// Obtener el token de autorización
 	authHeader := ctx.GetHeader("Authorization")
 
-	createdInscripcion, err := c.service.Create(ctx.Request.Context(), userID.(uint), inscripcionCreate.ActividadID, authHeader)
+	createdInscripcion, err := c.service.Create(ctx.Request.Context(), userID.(uint), inscripcionCreate.ActividadID, inscripcionCreate.SesionID, inscripcionCreate.ListaEspera, authHeader)
 	if err != nil {
+		var listaEsperaErr *services.ListaEsperaError
+		if errors.As(err, &listaEsperaErr) {
+			ctx.JSON(http.StatusAccepted, gin.H{
+				"message":      "La clase está llena, quedaste en la lista de espera",
+				"lista_espera": listaEsperaErr.Entrada,
+			})
+			return
+		}
+		// Bloqueado por acumular strikes (cancelaciones tardías o ausencias)
+		if errors.Is(err, services.ErrReservasBloqueadas) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrYaEnListaEspera) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		// Errores de la transacción de inscripción (cupo y duplicados)
+		if errors.Is(err, domain.ErrCupoAlcanzado) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "No se puede inscribir, el cupo de la actividad ha sido alcanzado", "details": err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrYaInscripto) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "El usuario ya está inscripto a esta actividad"})
+			return
+		}
+		if errors.Is(err, domain.ErrCupoAgotado) {
+			// Cupo de clases del período alcanzado - devolver mensaje directo
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if respondSesionError(ctx, err) {
+			return
+		}
+
 		errString := strings.ToLower(err.Error())
 
-		// Detectar errores específicos del hook BeforeCreate
-		if strings.Contains(errString, "ya está inscripto") || strings.Contains(errString, "ya esta inscripto") {
-			ctx.JSON(http.StatusConflict, gin.H{"error": "El usuario ya está inscripto a esta actividad"})
-		} else if strings.Contains(errString, "cupo de la actividad ha sido alcanzado") {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "No se puede inscribir, el cupo de la actividad ha sido alcanzado"})
+		if strings.Contains(errString, "sesión no encontrada") || strings.Contains(errString, "sesion not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "La sesión no existe"})
 		} else if strings.Contains(errString, "actividad no encontrada") || strings.Contains(errString, "not found") {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "La actividad no existe"})
 		} else if strings.Contains(errString, "tu plan") && strings.Contains(errString, "no incluye") {
@@ -86,9 +156,6 @@ func (c *InscripcionesController) Create(ctx *gin.Context) {
 		} else if strings.Contains(errString, "actualiza tu plan") || strings.Contains(errString, "upgrade") {
 			// Restricción de plan - mensaje de upgrade
 			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
-		} else if strings.Contains(errString, "has alcanzado el") && strings.Contains(errString, "actividades semanales") {
-			// Límite semanal alcanzado - devolver mensaje directo
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		} else if strings.Contains(errString, "debe tener un plan para inscribirse") || strings.Contains(errString, "no tiene suscripción activa") || strings.Contains(errString, "no se encontró suscripción") {
 			ctx.JSON(http.StatusForbidden, gin.H{"error": "No tenés un plan activo para esta actividad"})
 		} else if strings.Contains(errString, "requiere plan premium") {
@@ -104,6 +171,7 @@ func (c *InscripcionesController) Create(ctx *gin.Context) {
 
 // Deactivate desinscribe al usuario autenticado de una actividad
 // DELETE /inscripciones {"actividad_id": 1} (requiere JWT)
+// Con "sesion_id" cancela solo la reserva de esa sesión
 // Migrado de backend/controllers/inscripcion/incripcion_controller.go:66
 func (c *InscripcionesController) Deactivate(ctx *gin.Context) {
 	// Obtener el ID del usuario del contexto (seteado por middleware JWT)
@@ -115,14 +183,15 @@ func (c *InscripcionesController) Deactivate(ctx *gin.Context) {
 
 	// Parsear el body
 	var deactivateRequest struct {
-		ActividadID uint `json:"actividad_id" binding:"required"`
+		ActividadID uint  `json:"actividad_id" binding:"required"`
+		SesionID    *uint `json:"sesion_id"`
 	}
 	if err := ctx.ShouldBindJSON(&deactivateRequest); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos con formato incorrecto", "details": err.Error()})
 		return
 	}
 
-	err := c.service.Deactivate(ctx.Request.Context(), userID.(uint), deactivateRequest.ActividadID)
+	err := c.service.Deactivate(ctx.Request.Context(), userID.(uint), deactivateRequest.ActividadID, deactivateRequest.SesionID)
 	if err != nil {
 		errString := strings.ToLower(err.Error())
 
@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InstructoresController maneja las peticiones HTTP relacionadas con instructores
+type InstructoresController struct {
+	service services.InstructoresService
+}
+
+// NewInstructoresController crea una nueva instancia del controller
+func NewInstructoresController(service services.InstructoresService) *InstructoresController {
+	return &InstructoresController{
+		service: service,
+	}
+}
+
+// List obtiene los instructores activos
+// GET /instructores
+func (c *InstructoresController) List(ctx *gin.Context) {
+	instructores, err := c.service.List(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar instructores"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, instructores)
+}
+
+// GetByID obtiene un instructor por ID
+// GET /instructores/:id
+func (c *InstructoresController) GetByID(ctx *gin.Context) {
+	idInstructor, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	instructor, err := c.service.GetByID(ctx.Request.Context(), uint(idInstructor))
+	if err != nil {
+		if respondInstructorError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar el instructor"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, instructor)
+}
+
+// Agenda obtiene las clases del instructor y sus sesiones en un rango de fechas
+// GET /instructores/:id/agenda?desde=YYYY-MM-DD&hasta=YYYY-MM-DD
+func (c *InstructoresController) Agenda(ctx *gin.Context) {
+	idInstructor, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	agenda, err := c.service.Agenda(ctx.Request.Context(), uint(idInstructor), ctx.Query("desde"), ctx.Query("hasta"))
+	if err != nil {
+		if respondInstructorError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar la agenda del instructor"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, agenda)
+}
+
+// Create crea un nuevo instructor
+// POST /instructores (admin)
+func (c *InstructoresController) Create(ctx *gin.Context) {
+	var instructorCreate domain.InstructorCreate
+	if err := ctx.ShouldBindJSON(&instructorCreate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos con formato incorrecto", "details": err.Error()})
+		return
+	}
+
+	created, err := c.service.Create(ctx.Request.Context(), instructorCreate)
+	if err != nil {
+		if respondInstructorError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear el instructor", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// Update actualiza un instructor (reemplaza también sus sucursales)
+// PUT /instructores/:id (admin)
+func (c *InstructoresController) Update(ctx *gin.Context) {
+	idInstructor, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	var instructorUpdate domain.InstructorUpdate
+	if err := ctx.ShouldBindJSON(&instructorUpdate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos con formato incorrecto", "details": err.Error()})
+		return
+	}
+
+	updated, err := c.service.Update(ctx.Request.Context(), uint(idInstructor), instructorUpdate)
+	if err != nil {
+		if respondInstructorError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar el instructor", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// Delete da de baja un instructor sin actividades activas
+// DELETE /instructores/:id (admin)
+func (c *InstructoresController) Delete(ctx *gin.Context) {
+	idInstructor, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	if err := c.service.Delete(ctx.Request.Context(), uint(idInstructor)); err != nil {
+		if respondInstructorError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar el instructor"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// respondInstructorError responde los errores tipados de instructores
+// También la usa ActividadesController para los errores al asignar el instructor de una actividad
+// Devuelve false si err no es uno de ellos
+func respondInstructorError(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrInstructorNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "El instructor no existe"})
+	case errors.Is(err, domain.ErrInstructorUsuarioDuplicado),
+		errors.Is(err, services.ErrInstructorConActividades),
+		errors.Is(err, services.ErrInstructorSuperpuesto):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInstructorInvalido),
+		errors.Is(err, services.ErrInstructorInexistente),
+		errors.Is(err, services.ErrInstructorOtraSucursal),
+		errors.Is(err, services.ErrInstructorSinAsignar),
+		errors.Is(err, services.ErrFechaInvalida),
+		errors.Is(err, services.ErrRangoSesiones):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
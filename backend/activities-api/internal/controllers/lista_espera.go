@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"activities-api/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListListaEspera lista las listas de espera del usuario autenticado
+// GET /inscripciones/lista-espera (requiere JWT)
+// Las que esperan incluyen "posicion"; las que tienen un lugar ofrecido, "vence_en"
+func (c *InscripcionesController) ListListaEspera(ctx *gin.Context) {
+	userID, exists := ctx.Get("id_usuario")
+	if !exists {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	entradas, err := c.service.ListListaEspera(ctx.Request.Context(), userID.(uint))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al procesar la consulta"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entradas)
+}
+
+// ConfirmarListaEspera acepta el lugar ofrecido desde la lista de espera
+// POST /inscripciones/lista-espera/:id/confirmar (requiere JWT)
+func (c *InscripcionesController) ConfirmarListaEspera(ctx *gin.Context) {
+	userID, exists := ctx.Get("id_usuario")
+	if !exists {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	idEntrada, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	entrada, err := c.service.ConfirmarListaEspera(ctx.Request.Context(), userID.(uint), uint(idEntrada), ctx.GetHeader("Authorization"))
+	if err != nil {
+		if respondListaEsperaError(ctx, err) {
+			return
+		}
+		if strings.Contains(err.Error(), "debe tener un plan para inscribirse") {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "No tenés un plan activo para esta actividad"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al confirmar el lugar", "details": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entrada)
+}
+
+// SalirListaEspera saca al usuario de la lista de espera (o rechaza el lugar ofrecido)
+// DELETE /inscripciones/lista-espera/:id (requiere JWT)
+func (c *InscripcionesController) SalirListaEspera(ctx *gin.Context) {
+	userID, exists := ctx.Get("id_usuario")
+	if !exists {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	idEntrada, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	if err := c.service.SalirListaEspera(ctx.Request.Context(), userID.(uint), uint(idEntrada)); err != nil {
+		if respondListaEsperaError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al salir de la lista de espera", "details": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// respondListaEsperaError responde los errores tipados de la lista de espera
+// Devuelve false si err no es uno de ellos
+func respondListaEsperaError(ctx *gin.Context, err error) bool {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Entrada de lista de espera no encontrada"})
+	case errors.Is(err, services.ErrOfertaNoDisponible),
+		errors.Is(err, services.ErrListaEsperaInactiva):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
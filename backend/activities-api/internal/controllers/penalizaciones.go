@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PenalizacionesController maneja las peticiones HTTP de políticas de cancelación y strikes
+type PenalizacionesController struct {
+	service services.PenalizacionesService
+}
+
+// NewPenalizacionesController crea una nueva instancia del controller
+func NewPenalizacionesController(service services.PenalizacionesService) *PenalizacionesController {
+	return &PenalizacionesController{
+		service: service,
+	}
+}
+
+// ListPoliticas obtiene las políticas de cancelación
+// GET /politicas-cancelacion (admin)
+func (c *PenalizacionesController) ListPoliticas(ctx *gin.Context) {
+	politicas, err := c.service.ListPoliticas(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar las políticas de cancelación"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, politicas)
+}
+
+// CreatePolitica crea una política de cancelación
+// POST /politicas-cancelacion (admin)
+func (c *PenalizacionesController) CreatePolitica(ctx *gin.Context) {
+	var input domain.PoliticaCancelacionCreate
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	politica, err := c.service.CreatePolitica(ctx.Request.Context(), input)
+	if err != nil {
+		if respondPenalizacionError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear la política de cancelación"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, politica)
+}
+
+// UpdatePolitica reemplaza una política de cancelación
+// PUT /politicas-cancelacion/:id (admin)
+func (c *PenalizacionesController) UpdatePolitica(ctx *gin.Context) {
+	idPolitica, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	var input domain.PoliticaCancelacionCreate
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos", "details": err.Error()})
+		return
+	}
+
+	politica, err := c.service.UpdatePolitica(ctx.Request.Context(), uint(idPolitica), input)
+	if err != nil {
+		if respondPenalizacionError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar la política de cancelación"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, politica)
+}
+
+// DeletePolitica borra una política de cancelación
+// DELETE /politicas-cancelacion/:id (admin)
+func (c *PenalizacionesController) DeletePolitica(ctx *gin.Context) {
+	idPolitica, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	if err := c.service.DeletePolitica(ctx.Request.Context(), uint(idPolitica)); err != nil {
+		if respondPenalizacionError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar la política de cancelación"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// MisPenalizaciones obtiene los strikes y el bloqueo vigente del usuario autenticado
+// GET /inscripciones/penalizaciones (requiere JWT)
+func (c *PenalizacionesController) MisPenalizaciones(ctx *gin.Context) {
+	userID, exists := ctx.Get("id_usuario")
+	if !exists {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	estado, err := c.service.Estado(ctx.Request.Context(), userID.(uint))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar las penalizaciones"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, estado)
+}
+
+// EstadoUsuario obtiene los strikes y el bloqueo vigente de un socio
+// GET /usuarios/:id/penalizaciones (admin)
+func (c *PenalizacionesController) EstadoUsuario(ctx *gin.Context) {
+	idUsuario, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	estado, err := c.service.Estado(ctx.Request.Context(), uint(idUsuario))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar las penalizaciones"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, estado)
+}
+
+// AnularStrike anula un strike; si había disparado un bloqueo vigente se levanta
+// POST /strikes/:id/anular {"motivo": "..."} (admin)
+func (c *PenalizacionesController) AnularStrike(ctx *gin.Context) {
+	adminID, exists := ctx.Get("id_usuario")
+	if !exists {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	idStrike, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	var input domain.StrikeAnulacion
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Debe indicar el motivo de la anulación (motivo)"})
+		return
+	}
+
+	strike, err := c.service.AnularStrike(ctx.Request.Context(), uint(idStrike), adminID.(uint), input.Motivo)
+	if err != nil {
+		if respondPenalizacionError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al anular el strike"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, strike)
+}
+
+// respondPenalizacionError responde los errores tipados de políticas de cancelación y strikes
+// Devuelve false si err no es uno de ellos
+func respondPenalizacionError(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrPoliticaNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "La política de cancelación no existe"})
+	case errors.Is(err, domain.ErrStrikeNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "El strike no existe"})
+	case errors.Is(err, domain.ErrPoliticaDuplicada),
+		errors.Is(err, domain.ErrStrikeYaAnulado):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrPoliticaInvalida):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
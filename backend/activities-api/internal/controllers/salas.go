@@ -0,0 +1,337 @@
+package controllers
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SalasController maneja las peticiones HTTP de salas, equipos y reservas de equipos
+type SalasController struct {
+	service services.SalasService
+}
+
+// NewSalasController crea una nueva instancia del controller
+func NewSalasController(service services.SalasService) *SalasController {
+	return &SalasController{
+		service: service,
+	}
+}
+
+// ListBySucursal obtiene las salas activas de una sucursal con sus equipos
+// GET /sucursales/:id/salas
+func (c *SalasController) ListBySucursal(ctx *gin.Context) {
+	idSucursal, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	salas, err := c.service.ListBySucursal(ctx.Request.Context(), uint(idSucursal))
+	if err != nil {
+		if respondSucursalError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar salas"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, salas)
+}
+
+// GetByID obtiene una sala por ID con sus equipos
+// GET /salas/:id
+func (c *SalasController) GetByID(ctx *gin.Context) {
+	idSala, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	sala, err := c.service.GetByID(ctx.Request.Context(), uint(idSala))
+	if err != nil {
+		if respondSalaError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar la sala"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sala)
+}
+
+// Create crea una sala en una sucursal
+// POST /salas (activities:manage en la sucursal)
+func (c *SalasController) Create(ctx *gin.Context) {
+	var salaCreate domain.SalaCreate
+	if err := ctx.ShouldBindJSON(&salaCreate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos con formato incorrecto", "details": err.Error()})
+		return
+	}
+
+	if !canManageActividad(ctx, &salaCreate.SucursalID) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "No podés gestionar salas de esta sucursal"})
+		return
+	}
+
+	created, err := c.service.Create(ctx.Request.Context(), salaCreate)
+	if err != nil {
+		if respondSalaError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear la sala", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// Update cambia nombre y capacidad de una sala
+// PUT /salas/:id (activities:manage en la sucursal de la sala)
+func (c *SalasController) Update(ctx *gin.Context) {
+	idSala, ok := c.salaGestionable(ctx)
+	if !ok {
+		return
+	}
+
+	var salaUpdate domain.SalaUpdate
+	if err := ctx.ShouldBindJSON(&salaUpdate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos con formato incorrecto", "details": err.Error()})
+		return
+	}
+
+	updated, err := c.service.Update(ctx.Request.Context(), idSala, salaUpdate)
+	if err != nil {
+		if respondSalaError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar la sala", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// Delete da de baja una sala sin actividades activas
+// DELETE /salas/:id (activities:manage en la sucursal de la sala)
+func (c *SalasController) Delete(ctx *gin.Context) {
+	idSala, ok := c.salaGestionable(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.Delete(ctx.Request.Context(), idSala); err != nil {
+		if respondSalaError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar la sala"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// CreateEquipo agrega un equipo reservable a la sala
+// POST /salas/:id/equipos (activities:manage en la sucursal de la sala)
+func (c *SalasController) CreateEquipo(ctx *gin.Context) {
+	idSala, ok := c.salaGestionable(ctx)
+	if !ok {
+		return
+	}
+
+	var equipoCreate domain.EquipoCreate
+	if err := ctx.ShouldBindJSON(&equipoCreate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos con formato incorrecto", "details": err.Error()})
+		return
+	}
+
+	created, err := c.service.CreateEquipo(ctx.Request.Context(), idSala, equipoCreate)
+	if err != nil {
+		if respondSalaError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear el equipo", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// DeleteEquipo da de baja un equipo de la sala
+// DELETE /salas/:id/equipos/:equipo_id (activities:manage en la sucursal de la sala)
+func (c *SalasController) DeleteEquipo(ctx *gin.Context) {
+	idSala, ok := c.salaGestionable(ctx)
+	if !ok {
+		return
+	}
+
+	idEquipo, err := strconv.Atoi(ctx.Param("equipo_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id del equipo debe ser un número"})
+		return
+	}
+
+	if err := c.service.DeleteEquipo(ctx.Request.Context(), idSala, uint(idEquipo)); err != nil {
+		if respondSalaError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar el equipo"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListEquiposSesion lista los equipos de la sala de una sesión con su disponibilidad
+// GET /sesiones/:id/equipos (requiere JWT)
+func (c *SalasController) ListEquiposSesion(ctx *gin.Context) {
+	idSesion, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	var usuarioID *uint
+	if userID, exists := ctx.Get("id_usuario"); exists {
+		id := userID.(uint)
+		usuarioID = &id
+	}
+
+	equipos, err := c.service.ListEquiposSesion(ctx.Request.Context(), uint(idSesion), usuarioID)
+	if err != nil {
+		if respondSalaError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar los equipos de la sesión"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, equipos)
+}
+
+// ReservarEquipo reserva un equipo de la sala para el usuario autenticado
+// POST /sesiones/:id/equipos {"equipo_id": 7} (requiere JWT)
+func (c *SalasController) ReservarEquipo(ctx *gin.Context) {
+	idSesion, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	userID, exists := ctx.Get("id_usuario")
+	if !exists {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	var reservaCreate domain.ReservaEquipoCreate
+	if err := ctx.ShouldBindJSON(&reservaCreate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos con formato incorrecto", "details": err.Error()})
+		return
+	}
+
+	reserva, err := c.service.ReservarEquipo(ctx.Request.Context(), userID.(uint), uint(idSesion), reservaCreate.EquipoID)
+	if err != nil {
+		if respondSalaError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al reservar el equipo"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, reserva)
+}
+
+// CancelarReservaEquipo libera el equipo reservado por el usuario autenticado en la sesión
+// DELETE /sesiones/:id/equipos (requiere JWT)
+func (c *SalasController) CancelarReservaEquipo(ctx *gin.Context) {
+	idSesion, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	userID, exists := ctx.Get("id_usuario")
+	if !exists {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Usuario no autenticado"})
+		return
+	}
+
+	if err := c.service.CancelarReservaEquipo(ctx.Request.Context(), userID.(uint), uint(idSesion)); err != nil {
+		if respondSalaError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al cancelar la reserva del equipo"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// salaGestionable lee el id de la sala y valida que el usuario gestione su sucursal
+// Si no puede (o la sala no existe) ya responde el error
+func (c *SalasController) salaGestionable(ctx *gin.Context) (uint, bool) {
+	idSala, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return 0, false
+	}
+
+	sala, err := c.service.GetByID(ctx.Request.Context(), uint(idSala))
+	if err != nil {
+		if !respondSalaError(ctx, err) {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar la sala"})
+		}
+		return 0, false
+	}
+
+	if !canManageActividad(ctx, &sala.SucursalID) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "No podés gestionar salas de esta sucursal"})
+		return 0, false
+	}
+
+	return sala.ID, true
+}
+
+// respondSalaError responde los errores tipados de salas, equipos y reservas de equipos
+// También la usa ActividadesController para los errores al asignar la sala de una actividad
+// Devuelve false si err no es uno de ellos
+func respondSalaError(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrSalaNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "La sala no existe"})
+	case errors.Is(err, domain.ErrEquipoNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "El equipo no existe"})
+	case errors.Is(err, domain.ErrReservaEquipoNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "No tenés un equipo reservado en esta sesión"})
+	case errors.Is(err, domain.ErrSalaDuplicada),
+		errors.Is(err, domain.ErrEquipoDuplicado),
+		errors.Is(err, domain.ErrEquipoReservado),
+		errors.Is(err, domain.ErrReservaEquipoExistente),
+		errors.Is(err, services.ErrSalaOcupada),
+		errors.Is(err, services.ErrSalaConActividades),
+		errors.Is(err, services.ErrCapacidadSalaInsuficiente),
+		errors.Is(err, services.ErrSesionCancelada),
+		errors.Is(err, services.ErrSesionIniciada),
+		errors.Is(err, services.ErrNoInscriptoEnSesion):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSalaInvalida),
+		errors.Is(err, services.ErrSalaInexistente),
+		errors.Is(err, services.ErrSalaOtraSucursal),
+		errors.Is(err, services.ErrCupoSuperaSala),
+		errors.Is(err, services.ErrEquipoInvalido),
+		errors.Is(err, services.ErrEquipoOtraSala),
+		errors.Is(err, services.ErrSesionSinSala):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "sesion not found"):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Sesión no encontrada"})
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SesionesController maneja las peticiones HTTP de las sesiones fechadas de las actividades
+type SesionesController struct {
+	service services.SesionesService
+}
+
+// NewSesionesController crea una nueva instancia del controller
+func NewSesionesController(service services.SesionesService) *SesionesController {
+	return &SesionesController{
+		service: service,
+	}
+}
+
+// ListByActividad lista las sesiones de una actividad con sus lugares disponibles
+// GET /actividades/:id/sesiones?desde=2025-01-06&hasta=2025-01-31 (fechas opcionales, YYYY-MM-DD)
+func (c *SesionesController) ListByActividad(ctx *gin.Context) {
+	idActividad, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	sesiones, err := c.service.ListByActividad(ctx.Request.Context(), uint(idActividad), ctx.Query("desde"), ctx.Query("hasta"))
+	if err != nil {
+		if respondSesionError(ctx, err) {
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "La actividad no existe"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar sesiones"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sesiones)
+}
+
+// GetByID obtiene una sesión por ID
+// GET /sesiones/:id
+func (c *SesionesController) GetByID(ctx *gin.Context) {
+	idSesion, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	sesion, err := c.service.GetByID(ctx.Request.Context(), uint(idSesion))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Sesión no encontrada"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar la sesión"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sesion.ToResponse())
+}
+
+// Update cambia una sesión puntual: cupo, reemplazo de instructor o cancelación
+// PUT /sesiones/:id (activities:manage en la sucursal de la actividad)
+func (c *SesionesController) Update(ctx *gin.Context) {
+	idSesion, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	var sesionUpdate domain.SesionUpdate
+	if err := ctx.ShouldBindJSON(&sesionUpdate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos con formato incorrecto", "details": err.Error()})
+		return
+	}
+
+	sesion, err := c.service.GetByID(ctx.Request.Context(), uint(idSesion))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Sesión no encontrada"})
+		return
+	}
+	if !canManageActividad(ctx, sesion.SucursalID) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "No podés gestionar actividades de esta sucursal"})
+		return
+	}
+
+	updatedSesion, err := c.service.Update(ctx.Request.Context(), uint(idSesion), sesionUpdate)
+	if err != nil {
+		errString := err.Error()
+
+		if strings.Contains(errString, "inscripciones activas que superan el nuevo límite") || errors.Is(err, services.ErrCupoSuperaSala) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": errString})
+		} else if strings.Contains(errString, "not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Sesión no encontrada"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": errString})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updatedSesion)
+}
+
+// CancelarSesiones cancela las sesiones de una actividad en un rango de fechas y libera sus reservas
+// POST /actividades/:id/sesiones/cancelar {"desde": "2025-01-14", "hasta": "2025-01-20", "motivo": "..."}
+// (activities:manage en la sucursal de la actividad)
+func (c *SesionesController) CancelarSesiones(ctx *gin.Context) {
+	idActividad, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	var input domain.SesionesCancelacion
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos con formato incorrecto (desde y motivo son obligatorios)", "details": err.Error()})
+		return
+	}
+
+	actividad, err := c.service.GetActividad(ctx.Request.Context(), uint(idActividad))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Actividad no encontrada"})
+		return
+	}
+	if !canManageActividad(ctx, actividad.SucursalID) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "No podés gestionar actividades de esta sucursal"})
+		return
+	}
+
+	resultado, err := c.service.CancelarSesiones(ctx.Request.Context(), uint(idActividad), input)
+	if err != nil {
+		if respondSesionError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al cancelar las sesiones"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resultado)
+}
+
+// Reprogramar mueve una sesión a otra fecha u horario con sus inscriptos
+// POST /sesiones/:id/reprogramar {"fecha": "2025-01-15", "horario_inicio": "19:00", "horario_final": "20:00", "motivo": "..."}
+// (activities:manage en la sucursal de la actividad)
+func (c *SesionesController) Reprogramar(ctx *gin.Context) {
+	idSesion, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	var input domain.SesionReprogramacion
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos con formato incorrecto", "details": err.Error()})
+		return
+	}
+
+	sesion, err := c.service.GetByID(ctx.Request.Context(), uint(idSesion))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Sesión no encontrada"})
+		return
+	}
+	if !canManageActividad(ctx, sesion.SucursalID) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "No podés gestionar actividades de esta sucursal"})
+		return
+	}
+
+	reprogramada, err := c.service.Reprogramar(ctx.Request.Context(), uint(idSesion), input)
+	if err != nil {
+		if respondSesionError(ctx, err) {
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Sesión no encontrada"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al reprogramar la sesión"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reprogramada)
+}
+
+// respondSesionError responde los errores tipados de sesiones
+// Devuelve false si err no es uno de ellos
+func respondSesionError(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrSesionCancelada),
+		errors.Is(err, services.ErrSesionIniciada),
+		errors.Is(err, domain.ErrSesionDuplicada),
+		errors.Is(err, services.ErrSalaOcupada):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSesionOtraClase),
+		errors.Is(err, services.ErrFechaInvalida),
+		errors.Is(err, services.ErrRangoSesiones),
+		errors.Is(err, services.ErrHorarioSesion),
+		errors.Is(err, services.ErrSesionPasada):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
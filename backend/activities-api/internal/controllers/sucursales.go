@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SucursalesController maneja las peticiones HTTP relacionadas con sucursales
+type SucursalesController struct {
+	service services.SucursalesService
+}
+
+// NewSucursalesController crea una nueva instancia del controller
+func NewSucursalesController(service services.SucursalesService) *SucursalesController {
+	return &SucursalesController{
+		service: service,
+	}
+}
+
+// List obtiene las sucursales activas con su horario semanal
+// GET /sucursales
+func (c *SucursalesController) List(ctx *gin.Context) {
+	sucursales, err := c.service.List(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar sucursales"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucursales)
+}
+
+// GetByID obtiene una sucursal por ID
+// GET /sucursales/:id
+func (c *SucursalesController) GetByID(ctx *gin.Context) {
+	idSucursal, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	sucursal, err := c.service.GetByID(ctx.Request.Context(), uint(idSucursal))
+	if err != nil {
+		if respondSucursalError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al buscar la sucursal"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucursal)
+}
+
+// Create crea una nueva sucursal
+// POST /sucursales (admin)
+func (c *SucursalesController) Create(ctx *gin.Context) {
+	var sucursalCreate domain.SucursalCreate
+	if err := ctx.ShouldBindJSON(&sucursalCreate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos con formato incorrecto", "details": err.Error()})
+		return
+	}
+
+	created, err := c.service.Create(ctx.Request.Context(), sucursalCreate)
+	if err != nil {
+		if respondSucursalError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear la sucursal", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// Update actualiza una sucursal (reemplaza también el horario semanal)
+// PUT /sucursales/:id (admin)
+func (c *SucursalesController) Update(ctx *gin.Context) {
+	idSucursal, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	var sucursalUpdate domain.SucursalUpdate
+	if err := ctx.ShouldBindJSON(&sucursalUpdate); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos con formato incorrecto", "details": err.Error()})
+		return
+	}
+
+	updated, err := c.service.Update(ctx.Request.Context(), uint(idSucursal), sucursalUpdate)
+	if err != nil {
+		if respondSucursalError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar la sucursal", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// Delete da de baja una sucursal sin actividades activas
+// DELETE /sucursales/:id (admin)
+func (c *SucursalesController) Delete(ctx *gin.Context) {
+	idSucursal, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "El id debe ser un número"})
+		return
+	}
+
+	if err := c.service.Delete(ctx.Request.Context(), uint(idSucursal)); err != nil {
+		if respondSucursalError(ctx, err) {
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar la sucursal"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// respondSucursalError responde los errores tipados de sucursales
+// Devuelve false si err no es uno de ellos
+func respondSucursalError(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrSucursalNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "La sucursal no existe"})
+	case errors.Is(err, services.ErrSucursalInvalida):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSucursalConActividades):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"activities-api/internal/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SuscripcionesController maneja la copia local de suscripciones y planes de subscriptions-api
+type SuscripcionesController struct {
+	service services.SuscripcionesService
+}
+
+// NewSuscripcionesController crea una nueva instancia del controller
+func NewSuscripcionesController(service services.SuscripcionesService) *SuscripcionesController {
+	return &SuscripcionesController{
+		service: service,
+	}
+}
+
+// Sincronizar reconstruye la copia local con los planes y las suscripciones activas de subscriptions-api
+// POST /suscripciones/sincronizar (admin; reenvía su token a subscriptions-api)
+func (c *SuscripcionesController) Sincronizar(ctx *gin.Context) {
+	resultado, err := c.service.Sincronizar(ctx.Request.Context(), ctx.GetHeader("Authorization"))
+	if err != nil {
+		if errors.Is(err, services.ErrSincronizacionNoAutorizada) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": "Error al sincronizar con subscriptions-api", "details": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resultado)
+}
@@ -11,21 +11,24 @@ import (
 // Actividad representa el modelo de base de datos con tags de GORM
 // Migrado de backend/model/actividad.go
 type Actividad struct {
-	ID            uint           `gorm:"column:id_actividad;primaryKey;autoIncrement"`
-	Titulo        string         `gorm:"type:varchar(50);not null"`
-	Descripcion   string         `gorm:"type:varchar(255)"`
-	Cupo          uint           `gorm:"type:int;not null"`
-	Dia           string         `gorm:"type:enum('Lunes','Martes','Miercoles','Jueves','Viernes','Sabado','Domingo');not null"`
-	HorarioInicio time.Time      `gorm:"column:horario_inicio;type:time;not null"`
-	HorarioFinal  time.Time      `gorm:"column:horario_final;type:time;not null"`
-	FotoUrl       string         `gorm:"column:foto_url;type:varchar(511);not null"`
-	Instructor    string         `gorm:"type:varchar(50);not null"`
-	Categoria     string         `gorm:"type:varchar(40);not null"`
-	SucursalID    *uint          `gorm:"column:sucursal_id;index"` // TODO: Agregar FK cuando se cree Sucursal
-	Activa        bool           `gorm:"column:activa;default:true;not null"`
-	CreatedAt     time.Time      `gorm:"autoCreateTime"`
-	UpdatedAt     time.Time      `gorm:"autoUpdateTime"`
-	DeletedAt     gorm.DeletedAt `gorm:"column:deleted_at;index"`
+	ID                 uint           `gorm:"column:id_actividad;primaryKey;autoIncrement"`
+	Titulo             string         `gorm:"type:varchar(50);not null"`
+	Descripcion        string         `gorm:"type:varchar(255)"`
+	Cupo               uint           `gorm:"type:int;not null"`
+	Dia                string         `gorm:"type:enum('Lunes','Martes','Miercoles','Jueves','Viernes','Sabado','Domingo');not null"`
+	HorarioInicio      time.Time      `gorm:"column:horario_inicio;type:time;not null"`
+	HorarioFinal       time.Time      `gorm:"column:horario_final;type:time;not null"`
+	FotoUrl            string         `gorm:"column:foto_url;type:varchar(511);not null"`
+	Instructor         string         `gorm:"type:varchar(50);not null"`
+	InstructorID       *uint          `gorm:"column:instructor_id;index"`        // Usuario de users-api
+	InstructorPerfilID *uint          `gorm:"column:instructor_perfil_id;index"` // FK a instructores
+	Categoria          string         `gorm:"type:varchar(40);not null"`
+	SucursalID         *uint          `gorm:"column:sucursal_id;index"` // FK a sucursales (el servicio valida que exista y esté activa)
+	SalaID             *uint          `gorm:"column:sala_id;index"`     // FK a salas (misma sucursal)
+	Activa             bool           `gorm:"column:activa;default:true;not null"`
+	CreatedAt          time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt          time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt          gorm.DeletedAt `gorm:"column:deleted_at;index"`
 
 	// Relación con Inscripciones
 	Inscripciones []Inscripcion `gorm:"foreignKey:ActividadID;constraint:OnDelete:CASCADE"`
@@ -63,57 +66,72 @@ func (a Actividad) ToDomain() domain.Actividad {
 	}
 
 	return domain.Actividad{
-		ID:            a.ID,
-		Titulo:        a.Titulo,
-		Descripcion:   a.Descripcion,
-		Cupo:          a.Cupo,
-		Dia:           a.Dia,
-		HorarioInicio: a.HorarioInicio.Format("15:04"),
-		HorarioFinal:  a.HorarioFinal.Format("15:04"),
-		FotoUrl:       a.FotoUrl,
-		Instructor:    a.Instructor,
-		Categoria:     a.Categoria,
-		SucursalID:    a.SucursalID,
-		Activa:        a.Activa,
-		CreatedAt:     a.CreatedAt,
-		UpdatedAt:     a.UpdatedAt,
-		DeletedAt:     deletedAt,
+		ID:                 a.ID,
+		Titulo:             a.Titulo,
+		Descripcion:        a.Descripcion,
+		Cupo:               a.Cupo,
+		Dia:                a.Dia,
+		HorarioInicio:      a.HorarioInicio.Format("15:04"),
+		HorarioFinal:       a.HorarioFinal.Format("15:04"),
+		FotoUrl:            a.FotoUrl,
+		Instructor:         a.Instructor,
+		InstructorID:       a.InstructorID,
+		InstructorPerfilID: a.InstructorPerfilID,
+		Categoria:          a.Categoria,
+		SucursalID:         a.SucursalID,
+		SalaID:             a.SalaID,
+		Activa:             a.Activa,
+		CreatedAt:          a.CreatedAt,
+		UpdatedAt:          a.UpdatedAt,
+		DeletedAt:          deletedAt,
 	}
 }
 
 // FromDomain convierte de Domain (negocio) a DAO (MySQL)
 func ActividadFromDomain(domainAct domain.Actividad, horaInicio, horaFin time.Time) Actividad {
 	return Actividad{
-		ID:            domainAct.ID,
-		Titulo:        domainAct.Titulo,
-		Descripcion:   domainAct.Descripcion,
-		Cupo:          domainAct.Cupo,
-		Dia:           domainAct.Dia,
-		HorarioInicio: horaInicio,
-		HorarioFinal:  horaFin,
-		FotoUrl:       domainAct.FotoUrl,
-		Instructor:    domainAct.Instructor,
-		Categoria:     domainAct.Categoria,
-		SucursalID:    domainAct.SucursalID,
+		ID:                 domainAct.ID,
+		Titulo:             domainAct.Titulo,
+		Descripcion:        domainAct.Descripcion,
+		Cupo:               domainAct.Cupo,
+		Dia:                domainAct.Dia,
+		HorarioInicio:      horaInicio,
+		HorarioFinal:       horaFin,
+		FotoUrl:            domainAct.FotoUrl,
+		Instructor:         domainAct.Instructor,
+		InstructorID:       domainAct.InstructorID,
+		InstructorPerfilID: domainAct.InstructorPerfilID,
+		Categoria:          domainAct.Categoria,
+		SucursalID:         domainAct.SucursalID,
+		SalaID:             domainAct.SalaID,
 	}
 }
 
 // ActividadVista representa la vista MySQL con cupos calculados
 // Migrado de backend/model/actividad.go:45
 type ActividadVista struct {
-	ID             uint      `gorm:"column:id_actividad;primaryKey"`
-	Titulo         string    `gorm:"type:varchar(50)"`
-	Descripcion    string    `gorm:"type:varchar(255)"`
-	Cupo           uint      `gorm:"type:int"`
-	Dia            string    `gorm:"type:varchar(20)"`
-	HorarioInicio  time.Time `gorm:"column:horario_inicio;type:time"`
-	HorarioFinal   time.Time `gorm:"column:horario_final;type:time"`
-	FotoUrl        string    `gorm:"column:foto_url;type:varchar(511)"`
-	Instructor     string    `gorm:"type:varchar(50)"`
-	Categoria      string    `gorm:"type:varchar(40)"`
-	SucursalID     *uint     `gorm:"column:sucursal_id"`
-	SucursalNombre string    `gorm:"column:sucursal_nombre"` // JOIN con sucursales
-	Lugares        uint      `gorm:"column:lugares"`         // Campo calculado de la vista
+	ID                 uint      `gorm:"column:id_actividad;primaryKey"`
+	Titulo             string    `gorm:"type:varchar(50)"`
+	Descripcion        string    `gorm:"type:varchar(255)"`
+	Cupo               uint      `gorm:"type:int"`
+	Dia                string    `gorm:"type:varchar(20)"`
+	HorarioInicio      time.Time `gorm:"column:horario_inicio;type:time"`
+	HorarioFinal       time.Time `gorm:"column:horario_final;type:time"`
+	FotoUrl            string    `gorm:"column:foto_url;type:varchar(511)"`
+	Instructor         string    `gorm:"type:varchar(50)"`
+	InstructorID       *uint     `gorm:"column:instructor_id"`
+	InstructorPerfilID *uint     `gorm:"column:instructor_perfil_id"`
+	Categoria          string    `gorm:"type:varchar(40)"`
+	SucursalID         *uint     `gorm:"column:sucursal_id"`
+	SalaID             *uint     `gorm:"column:sala_id"`
+	SucursalNombre     string    `gorm:"column:sucursal_nombre"` // JOIN con sucursales
+	Lugares            uint      `gorm:"column:lugares"`         // Campo calculado de la vista
+
+	// Promedios de las calificaciones visibles (de la actividad y de su instructor)
+	CalificacionPromedio           float64 `gorm:"column:calificacion_promedio"`
+	Calificaciones                 uint    `gorm:"column:calificaciones"`
+	InstructorCalificacionPromedio float64 `gorm:"column:instructor_calificacion_promedio"`
+	InstructorCalificaciones       uint    `gorm:"column:instructor_calificaciones"`
 }
 
 // TableName especifica el nombre de la vista
@@ -124,19 +142,27 @@ func (ActividadVista) TableName() string {
 // ToDomain convierte vista a domain (incluye lugares disponibles y nombre de sucursal)
 func (av ActividadVista) ToDomain() domain.Actividad {
 	return domain.Actividad{
-		ID:             av.ID,
-		Titulo:         av.Titulo,
-		Descripcion:    av.Descripcion,
-		Cupo:           av.Cupo,
-		Dia:            av.Dia,
-		HorarioInicio:  av.HorarioInicio.Format("15:04"),
-		HorarioFinal:   av.HorarioFinal.Format("15:04"),
-		FotoUrl:        av.FotoUrl,
-		Instructor:     av.Instructor,
-		Categoria:      av.Categoria,
-		SucursalID:     av.SucursalID,
-		SucursalNombre: av.SucursalNombre, // Nombre de la sucursal (JOIN)
-		Lugares:        av.Lugares,        // Cupos disponibles
-		CupoDisponible: av.Lugares,        // Alias para eventos de RabbitMQ
+		ID:                 av.ID,
+		Titulo:             av.Titulo,
+		Descripcion:        av.Descripcion,
+		Cupo:               av.Cupo,
+		Dia:                av.Dia,
+		HorarioInicio:      av.HorarioInicio.Format("15:04"),
+		HorarioFinal:       av.HorarioFinal.Format("15:04"),
+		FotoUrl:            av.FotoUrl,
+		Instructor:         av.Instructor,
+		InstructorID:       av.InstructorID,
+		InstructorPerfilID: av.InstructorPerfilID,
+		Categoria:          av.Categoria,
+		SucursalID:         av.SucursalID,
+		SalaID:             av.SalaID,
+		SucursalNombre:     av.SucursalNombre, // Nombre de la sucursal (JOIN)
+		Lugares:            av.Lugares,        // Cupos disponibles
+		CupoDisponible:     av.Lugares,        // Alias para eventos de RabbitMQ
+
+		CalificacionPromedio:           av.CalificacionPromedio,
+		Calificaciones:                 av.Calificaciones,
+		InstructorCalificacionPromedio: av.InstructorCalificacionPromedio,
+		InstructorCalificaciones:       av.InstructorCalificaciones,
 	}
 }
@@ -0,0 +1,63 @@
+package dao
+
+import (
+	"activities-api/internal/domain"
+	"time"
+)
+
+// Asistencia representa un check-in (o una ausencia) en MySQL
+// La clave única (usuario_id, sesion_clave, fecha) evita registrar dos veces la misma clase o la sala libre del día
+type Asistencia struct {
+	ID            uint      `gorm:"column:id_asistencia;primaryKey;autoIncrement"`
+	UsuarioID     uint      `gorm:"column:usuario_id;not null"`
+	ActividadID   *uint     `gorm:"column:actividad_id;index"`
+	SesionID      *uint     `gorm:"column:sesion_id;index"`
+	SucursalID    *uint     `gorm:"column:sucursal_id"`
+	Tipo          string    `gorm:"type:enum('clase','libre');not null"`
+	Estado        string    `gorm:"type:enum('presente','ausente');default:presente;not null"`
+	RegistradaPor *uint     `gorm:"column:registrada_por"`
+	Fecha         time.Time `gorm:"column:fecha;type:date;not null"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (Asistencia) TableName() string {
+	return "asistencias"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (a Asistencia) ToDomain() domain.Asistencia {
+	return domain.Asistencia{
+		ID:            a.ID,
+		UsuarioID:     a.UsuarioID,
+		ActividadID:   a.ActividadID,
+		SesionID:      a.SesionID,
+		SucursalID:    a.SucursalID,
+		Tipo:          a.Tipo,
+		Estado:        a.Estado,
+		RegistradaPor: a.RegistradaPor,
+		Fecha:         a.Fecha.Format("2006-01-02"),
+		CreatedAt:     a.CreatedAt,
+	}
+}
+
+// AsistenciaFromDomain convierte de Domain (negocio) a DAO (MySQL)
+// fecha ya viene parseada por el repository
+func AsistenciaFromDomain(a domain.Asistencia, fecha time.Time) Asistencia {
+	estado := a.Estado
+	if estado == "" {
+		estado = domain.AsistenciaPresente
+	}
+
+	return Asistencia{
+		ID:            a.ID,
+		UsuarioID:     a.UsuarioID,
+		ActividadID:   a.ActividadID,
+		SesionID:      a.SesionID,
+		SucursalID:    a.SucursalID,
+		Tipo:          a.Tipo,
+		Estado:        estado,
+		RegistradaPor: a.RegistradaPor,
+		Fecha:         fecha,
+	}
+}
@@ -0,0 +1,17 @@
+package dao
+
+import "time"
+
+// CalendarioToken representa el modelo de base de datos con tags de GORM
+// Un token por usuario: regenerarlo reemplaza el anterior
+type CalendarioToken struct {
+	UsuarioID uint      `gorm:"column:usuario_id;primaryKey;autoIncrement:false"`
+	Token     string    `gorm:"type:varchar(64);not null;uniqueIndex:uk_token"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (CalendarioToken) TableName() string {
+	return "calendario_tokens"
+}
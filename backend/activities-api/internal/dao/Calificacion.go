@@ -0,0 +1,83 @@
+package dao
+
+import (
+	"activities-api/internal/domain"
+	"time"
+)
+
+// Calificacion representa el puntaje de un socio sobre una sesión en MySQL
+// La clave única (sesion_id, usuario_id) permite una sola calificación por socio y sesión
+type Calificacion struct {
+	ID                 uint       `gorm:"column:id_calificacion;primaryKey;autoIncrement"`
+	SesionID           uint       `gorm:"column:sesion_id;not null;uniqueIndex:uk_sesion_usuario"`
+	ActividadID        uint       `gorm:"column:actividad_id;not null;index:idx_actividad_oculta"`
+	UsuarioID          uint       `gorm:"column:usuario_id;not null;uniqueIndex:uk_sesion_usuario"`
+	InstructorPerfilID *uint      `gorm:"column:instructor_perfil_id;index:idx_instructor_oculta"`
+	Puntaje            int        `gorm:"column:puntaje;type:tinyint;not null"`
+	Comentario         *string    `gorm:"column:comentario;type:varchar(1000)"`
+	Oculta             bool       `gorm:"column:oculta;not null;default:false;index:idx_actividad_oculta;index:idx_instructor_oculta"`
+	MotivoOcultamiento *string    `gorm:"column:motivo_ocultamiento;type:varchar(255)"`
+	Marcada            bool       `gorm:"column:marcada;not null;default:false;index:idx_marcada"`
+	MotivoMarca        *string    `gorm:"column:motivo_marca;type:varchar(255)"`
+	ModeradaPor        *uint      `gorm:"column:moderada_por"`
+	ModeradaEn         *time.Time `gorm:"column:moderada_en"`
+	CreatedAt          time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt          time.Time  `gorm:"autoUpdateTime"`
+
+	// Fecha de la sesión (JOIN con sesiones en los listados)
+	Fecha *time.Time `gorm:"->;column:fecha"`
+}
+
+// TableName especifica el nombre de la tabla
+func (Calificacion) TableName() string {
+	return "calificaciones"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (c Calificacion) ToDomain() domain.Calificacion {
+	calificacion := domain.Calificacion{
+		ID:                 c.ID,
+		SesionID:           c.SesionID,
+		ActividadID:        c.ActividadID,
+		UsuarioID:          c.UsuarioID,
+		InstructorPerfilID: c.InstructorPerfilID,
+		Puntaje:            c.Puntaje,
+		Oculta:             c.Oculta,
+		Marcada:            c.Marcada,
+		ModeradaPor:        c.ModeradaPor,
+		ModeradaEn:         c.ModeradaEn,
+		CreatedAt:          c.CreatedAt,
+	}
+	if c.Comentario != nil {
+		calificacion.Comentario = *c.Comentario
+	}
+	if c.MotivoOcultamiento != nil {
+		calificacion.MotivoOcultamiento = *c.MotivoOcultamiento
+	}
+	if c.MotivoMarca != nil {
+		calificacion.MotivoMarca = *c.MotivoMarca
+	}
+	if c.Fecha != nil {
+		calificacion.Fecha = c.Fecha.Format("2006-01-02")
+	}
+	return calificacion
+}
+
+// CalificacionFromDomain convierte de Domain (negocio) a DAO (MySQL)
+// La moderación no se copia: se actualiza aparte
+func CalificacionFromDomain(c domain.Calificacion) Calificacion {
+	var comentario *string
+	if c.Comentario != "" {
+		comentario = &c.Comentario
+	}
+
+	return Calificacion{
+		ID:                 c.ID,
+		SesionID:           c.SesionID,
+		ActividadID:        c.ActividadID,
+		UsuarioID:          c.UsuarioID,
+		InstructorPerfilID: c.InstructorPerfilID,
+		Puntaje:            c.Puntaje,
+		Comentario:         comentario,
+	}
+}
@@ -0,0 +1,74 @@
+package dao
+
+import (
+	"activities-api/internal/domain"
+	"time"
+)
+
+// ConsumoCupo representa el modelo de base de datos con tags de GORM
+// La clave única (inscripcion_id, sesion_id) descuenta cada clase una sola vez
+type ConsumoCupo struct {
+	ID              uint       `gorm:"column:id_consumo;primaryKey;autoIncrement"`
+	UsuarioID       uint       `gorm:"column:usuario_id;not null;index:idx_usuario_fecha,priority:1"`
+	SuscripcionID   string     `gorm:"column:suscripcion_id;type:varchar(24);not null;default:'';index:idx_suscripcion"`
+	InscripcionID   uint       `gorm:"column:inscripcion_id;not null;uniqueIndex:uk_inscripcion_sesion"`
+	ActividadID     uint       `gorm:"column:actividad_id;not null"`
+	SesionID        uint       `gorm:"column:sesion_id;not null;uniqueIndex:uk_inscripcion_sesion;index:idx_sesion"`
+	FechaClase      time.Time  `gorm:"column:fecha_clase;type:date;not null;index:idx_usuario_fecha,priority:2"`
+	Periodo         string     `gorm:"type:varchar(10);not null"`
+	PeriodoClave    string     `gorm:"column:periodo_clave;type:varchar(10);not null"`
+	Estado          string     `gorm:"type:enum('usado','reintegrado');default:usado;not null"`
+	MotivoReintegro *string    `gorm:"column:motivo_reintegro;type:varchar(30)"`
+	ReintegradoEn   *time.Time `gorm:"column:reintegrado_en"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (ConsumoCupo) TableName() string {
+	return "consumos_cupo"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (c ConsumoCupo) ToDomain() domain.ConsumoCupo {
+	consumo := domain.ConsumoCupo{
+		ID:            c.ID,
+		UsuarioID:     c.UsuarioID,
+		SuscripcionID: c.SuscripcionID,
+		InscripcionID: c.InscripcionID,
+		ActividadID:   c.ActividadID,
+		SesionID:      c.SesionID,
+		FechaClase:    c.FechaClase.Format("2006-01-02"),
+		Periodo:       c.Periodo,
+		PeriodoClave:  c.PeriodoClave,
+		Estado:        c.Estado,
+		ReintegradoEn: c.ReintegradoEn,
+		CreatedAt:     c.CreatedAt,
+	}
+	if c.MotivoReintegro != nil {
+		consumo.MotivoReintegro = *c.MotivoReintegro
+	}
+	return consumo
+}
+
+// ConsumoCupoFromDomain convierte de Domain (negocio) a DAO (MySQL)
+// fecha ya viene parseada por el servicio
+func ConsumoCupoFromDomain(c domain.ConsumoCupo, fecha time.Time) ConsumoCupo {
+	estado := c.Estado
+	if estado == "" {
+		estado = domain.ConsumoUsado
+	}
+
+	return ConsumoCupo{
+		ID:            c.ID,
+		UsuarioID:     c.UsuarioID,
+		SuscripcionID: c.SuscripcionID,
+		InscripcionID: c.InscripcionID,
+		ActividadID:   c.ActividadID,
+		SesionID:      c.SesionID,
+		FechaClase:    fecha,
+		Periodo:       c.Periodo,
+		PeriodoClave:  c.PeriodoClave,
+		Estado:        estado,
+	}
+}
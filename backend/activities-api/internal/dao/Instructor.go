@@ -0,0 +1,87 @@
+package dao
+
+import (
+	"activities-api/internal/domain"
+	"time"
+)
+
+// Instructor representa el modelo de base de datos con tags de GORM
+// La baja es lógica (activo = false): las actividades históricas siguen apuntando al instructor
+type Instructor struct {
+	ID             uint      `gorm:"column:id_instructor;primaryKey;autoIncrement"`
+	Nombre         string    `gorm:"type:varchar(100);not null"`
+	Bio            string    `gorm:"type:text"`
+	FotoUrl        string    `gorm:"column:foto_url;type:varchar(511)"`
+	Especialidades []string  `gorm:"type:json;serializer:json"`
+	UsuarioID      *uint     `gorm:"column:usuario_id;uniqueIndex:unique_usuario"`
+	Activo         bool      `gorm:"default:true;not null"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+
+	// Sucursales donde da clases (tabla instructor_sucursales)
+	Sucursales []InstructorSucursal `gorm:"foreignKey:InstructorID"`
+}
+
+// TableName especifica el nombre de la tabla
+func (Instructor) TableName() string {
+	return "instructores"
+}
+
+// InstructorSucursal vincula un instructor con una sucursal donde da clases
+type InstructorSucursal struct {
+	InstructorID uint `gorm:"column:instructor_id;primaryKey"`
+	SucursalID   uint `gorm:"column:sucursal_id;primaryKey"`
+}
+
+// TableName especifica el nombre de la tabla
+func (InstructorSucursal) TableName() string {
+	return "instructor_sucursales"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (i Instructor) ToDomain() domain.Instructor {
+	sucursales := make([]uint, len(i.Sucursales))
+	for j, s := range i.Sucursales {
+		sucursales[j] = s.SucursalID
+	}
+
+	especialidades := i.Especialidades
+	if especialidades == nil {
+		especialidades = []string{}
+	}
+
+	return domain.Instructor{
+		ID:             i.ID,
+		Nombre:         i.Nombre,
+		Bio:            i.Bio,
+		FotoUrl:        i.FotoUrl,
+		Especialidades: especialidades,
+		UsuarioID:      i.UsuarioID,
+		Sucursales:     sucursales,
+		Activo:         i.Activo,
+		CreatedAt:      i.CreatedAt,
+		UpdatedAt:      i.UpdatedAt,
+	}
+}
+
+// InstructorFromDomain convierte de Domain (negocio) a DAO (MySQL)
+func InstructorFromDomain(domainInst domain.Instructor) Instructor {
+	sucursales := make([]InstructorSucursal, len(domainInst.Sucursales))
+	for i, sucursalID := range domainInst.Sucursales {
+		sucursales[i] = InstructorSucursal{
+			InstructorID: domainInst.ID,
+			SucursalID:   sucursalID,
+		}
+	}
+
+	return Instructor{
+		ID:             domainInst.ID,
+		Nombre:         domainInst.Nombre,
+		Bio:            domainInst.Bio,
+		FotoUrl:        domainInst.FotoUrl,
+		Especialidades: domainInst.Especialidades,
+		UsuarioID:      domainInst.UsuarioID,
+		Activo:         domainInst.Activo,
+		Sucursales:     sucursales,
+	}
+}
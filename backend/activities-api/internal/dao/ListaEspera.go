@@ -0,0 +1,65 @@
+package dao
+
+import (
+	"activities-api/internal/domain"
+	"time"
+)
+
+// ListaEspera representa una entrada de la lista de espera en MySQL
+type ListaEspera struct {
+	ID            uint       `gorm:"column:id_lista_espera;primaryKey;autoIncrement"`
+	UsuarioID     uint       `gorm:"column:usuario_id;not null;index"`
+	ActividadID   uint       `gorm:"column:actividad_id;not null;index:idx_actividad_sesion_estado"`
+	SesionID      *uint      `gorm:"column:sesion_id;index:idx_actividad_sesion_estado"`
+	SuscripcionID *string    `gorm:"column:suscripcion_id;type:varchar(50)"`
+	LimiteSemanal int        `gorm:"column:limite_semanal;not null;default:0"`
+	Estado        string     `gorm:"type:enum('esperando','ofrecida','confirmada','vencida','omitida','cancelada');default:esperando;not null;index:idx_actividad_sesion_estado"`
+	OfrecidaEn    *time.Time `gorm:"column:ofrecida_en"`
+	VenceEn       *time.Time `gorm:"column:vence_en;index"`
+	Posicion      int        `gorm:"column:posicion;->"` // Calculada al listar (no es columna)
+	CreatedAt     time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (ListaEspera) TableName() string {
+	return "lista_espera"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (le ListaEspera) ToDomain() domain.EntradaListaEspera {
+	return domain.EntradaListaEspera{
+		ID:            le.ID,
+		UsuarioID:     le.UsuarioID,
+		ActividadID:   le.ActividadID,
+		SesionID:      le.SesionID,
+		SuscripcionID: le.SuscripcionID,
+		LimiteSemanal: le.LimiteSemanal,
+		Estado:        le.Estado,
+		Posicion:      le.Posicion,
+		OfrecidaEn:    le.OfrecidaEn,
+		VenceEn:       le.VenceEn,
+		CreatedAt:     le.CreatedAt,
+		UpdatedAt:     le.UpdatedAt,
+	}
+}
+
+// ListaEsperaFromDomain convierte de Domain (negocio) a DAO (MySQL)
+func ListaEsperaFromDomain(e domain.EntradaListaEspera) ListaEspera {
+	estado := e.Estado
+	if estado == "" {
+		estado = domain.ListaEsperaEsperando
+	}
+
+	return ListaEspera{
+		ID:            e.ID,
+		UsuarioID:     e.UsuarioID,
+		ActividadID:   e.ActividadID,
+		SesionID:      e.SesionID,
+		SuscripcionID: e.SuscripcionID,
+		LimiteSemanal: e.LimiteSemanal,
+		Estado:        estado,
+		OfrecidaEn:    e.OfrecidaEn,
+		VenceEn:       e.VenceEn,
+	}
+}
@@ -0,0 +1,138 @@
+package dao
+
+import (
+	"activities-api/internal/domain"
+	"time"
+)
+
+// PoliticaCancelacion representa el modelo de base de datos con tags de GORM
+// La clave única (alcance, valor) permite una sola política por plan, por categoría y una general
+type PoliticaCancelacion struct {
+	ID                 uint      `gorm:"column:id_politica;primaryKey;autoIncrement"`
+	Alcance            string    `gorm:"type:enum('plan','categoria','general');not null;uniqueIndex:uk_alcance_valor"`
+	Valor              string    `gorm:"type:varchar(100);not null;default:'';uniqueIndex:uk_alcance_valor"`
+	MinutosLimite      int       `gorm:"column:minutos_limite;not null;default:0"`
+	PenalizarAusentes  bool      `gorm:"column:penalizar_ausentes;not null;default:true"`
+	StrikesBloqueo     int       `gorm:"column:strikes_bloqueo;not null;default:0"`
+	DiasBloqueo        int       `gorm:"column:dias_bloqueo;not null;default:0"`
+	DiasVigenciaStrike int       `gorm:"column:dias_vigencia_strike;not null;default:30"`
+	CreatedAt          time.Time `gorm:"autoCreateTime"`
+	UpdatedAt          time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (PoliticaCancelacion) TableName() string {
+	return "politicas_cancelacion"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (p PoliticaCancelacion) ToDomain() domain.PoliticaCancelacion {
+	return domain.PoliticaCancelacion{
+		ID:                 p.ID,
+		Alcance:            p.Alcance,
+		Valor:              p.Valor,
+		MinutosLimite:      p.MinutosLimite,
+		PenalizarAusentes:  p.PenalizarAusentes,
+		StrikesBloqueo:     p.StrikesBloqueo,
+		DiasBloqueo:        p.DiasBloqueo,
+		DiasVigenciaStrike: p.DiasVigenciaStrike,
+		CreatedAt:          p.CreatedAt,
+		UpdatedAt:          p.UpdatedAt,
+	}
+}
+
+// PoliticaCancelacionFromDomain convierte de Domain (negocio) a DAO (MySQL)
+func PoliticaCancelacionFromDomain(p domain.PoliticaCancelacion) PoliticaCancelacion {
+	return PoliticaCancelacion{
+		ID:                 p.ID,
+		Alcance:            p.Alcance,
+		Valor:              p.Valor,
+		MinutosLimite:      p.MinutosLimite,
+		PenalizarAusentes:  p.PenalizarAusentes,
+		StrikesBloqueo:     p.StrikesBloqueo,
+		DiasBloqueo:        p.DiasBloqueo,
+		DiasVigenciaStrike: p.DiasVigenciaStrike,
+	}
+}
+
+// Strike representa una cancelación tardía o una ausencia penalizada
+// La clave única (usuario_id, sesion_id, tipo) evita dos strikes del mismo tipo por la misma sesión
+type Strike struct {
+	ID              uint       `gorm:"column:id_strike;primaryKey;autoIncrement"`
+	UsuarioID       uint       `gorm:"column:usuario_id;not null;uniqueIndex:uk_usuario_sesion_tipo"`
+	Tipo            string     `gorm:"type:enum('cancelacion_tardia','ausente');not null;uniqueIndex:uk_usuario_sesion_tipo"`
+	ActividadID     uint       `gorm:"column:actividad_id;not null"`
+	SesionID        uint       `gorm:"column:sesion_id;not null;uniqueIndex:uk_usuario_sesion_tipo"`
+	PoliticaID      *uint      `gorm:"column:politica_id"`
+	BloqueoID       *uint      `gorm:"column:bloqueo_id;index"`
+	Anulado         bool       `gorm:"column:anulado;not null;default:false"`
+	AnuladoPor      *uint      `gorm:"column:anulado_por"`
+	MotivoAnulacion *string    `gorm:"column:motivo_anulacion;type:varchar(255)"`
+	AnuladoEn       *time.Time `gorm:"column:anulado_en"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (Strike) TableName() string {
+	return "strikes"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (s Strike) ToDomain() domain.Strike {
+	strike := domain.Strike{
+		ID:          s.ID,
+		UsuarioID:   s.UsuarioID,
+		Tipo:        s.Tipo,
+		ActividadID: s.ActividadID,
+		SesionID:    s.SesionID,
+		PoliticaID:  s.PoliticaID,
+		BloqueoID:   s.BloqueoID,
+		Anulado:     s.Anulado,
+		AnuladoPor:  s.AnuladoPor,
+		AnuladoEn:   s.AnuladoEn,
+		CreatedAt:   s.CreatedAt,
+	}
+	if s.MotivoAnulacion != nil {
+		strike.MotivoAnulacion = *s.MotivoAnulacion
+	}
+	return strike
+}
+
+// StrikeFromDomain convierte de Domain (negocio) a DAO (MySQL)
+func StrikeFromDomain(s domain.Strike) Strike {
+	return Strike{
+		ID:          s.ID,
+		UsuarioID:   s.UsuarioID,
+		Tipo:        s.Tipo,
+		ActividadID: s.ActividadID,
+		SesionID:    s.SesionID,
+		PoliticaID:  s.PoliticaID,
+		BloqueoID:   s.BloqueoID,
+		Anulado:     s.Anulado,
+	}
+}
+
+// BloqueoReservas representa un bloqueo temporal de inscripciones por acumular strikes
+type BloqueoReservas struct {
+	ID        uint      `gorm:"column:id_bloqueo;primaryKey;autoIncrement"`
+	UsuarioID uint      `gorm:"column:usuario_id;not null;index:idx_usuario_hasta"`
+	Hasta     time.Time `gorm:"column:hasta;not null;index:idx_usuario_hasta"`
+	Levantado bool      `gorm:"column:levantado;not null;default:false"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (BloqueoReservas) TableName() string {
+	return "bloqueos_reserva"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (b BloqueoReservas) ToDomain() domain.BloqueoReservas {
+	return domain.BloqueoReservas{
+		ID:        b.ID,
+		UsuarioID: b.UsuarioID,
+		Hasta:     b.Hasta,
+		Levantado: b.Levantado,
+		CreatedAt: b.CreatedAt,
+	}
+}
@@ -0,0 +1,112 @@
+package dao
+
+import (
+	"activities-api/internal/domain"
+	"time"
+)
+
+// Sala representa el modelo de base de datos con tags de GORM
+// La baja es lógica (activa = false): las actividades históricas siguen apuntando a la sala
+type Sala struct {
+	ID         uint      `gorm:"column:id_sala;primaryKey;autoIncrement"`
+	SucursalID uint      `gorm:"column:sucursal_id;not null;uniqueIndex:uk_sucursal_nombre"`
+	Nombre     string    `gorm:"type:varchar(60);not null;uniqueIndex:uk_sucursal_nombre"`
+	Capacidad  uint      `gorm:"type:int;not null"`
+	Activa     bool      `gorm:"default:true;not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+
+	// Equipos reservables de la sala
+	Equipos []Equipo `gorm:"foreignKey:SalaID"`
+}
+
+// TableName especifica el nombre de la tabla
+func (Sala) TableName() string {
+	return "salas"
+}
+
+// Equipo representa un equipo reservable de una sala
+type Equipo struct {
+	ID        uint      `gorm:"column:id_equipo;primaryKey;autoIncrement"`
+	SalaID    uint      `gorm:"column:sala_id;not null;uniqueIndex:uk_sala_tipo_numero"`
+	Tipo      string    `gorm:"type:varchar(40);not null;uniqueIndex:uk_sala_tipo_numero"`
+	Numero    uint      `gorm:"type:int;not null;uniqueIndex:uk_sala_tipo_numero"`
+	Activo    bool      `gorm:"default:true;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (Equipo) TableName() string {
+	return "equipos"
+}
+
+// ReservaEquipo representa la reserva de un equipo para una sesión
+// Las claves únicas impiden reservar dos veces el mismo equipo o dos equipos en la misma sesión
+type ReservaEquipo struct {
+	ID        uint      `gorm:"column:id_reserva;primaryKey;autoIncrement"`
+	EquipoID  uint      `gorm:"column:equipo_id;not null;uniqueIndex:uk_equipo_sesion"`
+	SesionID  uint      `gorm:"column:sesion_id;not null;uniqueIndex:uk_equipo_sesion;uniqueIndex:uk_usuario_sesion"`
+	UsuarioID uint      `gorm:"column:usuario_id;not null;uniqueIndex:uk_usuario_sesion"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (ReservaEquipo) TableName() string {
+	return "reservas_equipo"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+// Solo incluye los equipos activos
+func (s Sala) ToDomain() domain.Sala {
+	equipos := make([]domain.Equipo, 0, len(s.Equipos))
+	for _, e := range s.Equipos {
+		if e.Activo {
+			equipos = append(equipos, e.ToDomain())
+		}
+	}
+
+	return domain.Sala{
+		ID:         s.ID,
+		SucursalID: s.SucursalID,
+		Nombre:     s.Nombre,
+		Capacidad:  s.Capacidad,
+		Equipos:    equipos,
+		Activa:     s.Activa,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+	}
+}
+
+// SalaFromDomain convierte de Domain (negocio) a DAO (MySQL), sin los equipos
+func SalaFromDomain(domainSala domain.Sala) Sala {
+	return Sala{
+		ID:         domainSala.ID,
+		SucursalID: domainSala.SucursalID,
+		Nombre:     domainSala.Nombre,
+		Capacidad:  domainSala.Capacidad,
+		Activa:     domainSala.Activa,
+	}
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (e Equipo) ToDomain() domain.Equipo {
+	return domain.Equipo{
+		ID:     e.ID,
+		SalaID: e.SalaID,
+		Tipo:   e.Tipo,
+		Numero: e.Numero,
+		Activo: e.Activo,
+	}
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (r ReservaEquipo) ToDomain() domain.ReservaEquipo {
+	return domain.ReservaEquipo{
+		ID:        r.ID,
+		EquipoID:  r.EquipoID,
+		SesionID:  r.SesionID,
+		UsuarioID: r.UsuarioID,
+		CreatedAt: r.CreatedAt,
+	}
+}
@@ -0,0 +1,119 @@
+package dao
+
+import (
+	"activities-api/internal/domain"
+	"time"
+)
+
+// Sesion representa una ocurrencia fechada de una actividad
+type Sesion struct {
+	ID                uint       `gorm:"column:id_sesion;primaryKey;autoIncrement"`
+	ActividadID       uint       `gorm:"column:actividad_id;not null;uniqueIndex:uk_actividad_fecha"`
+	Fecha             time.Time  `gorm:"column:fecha;type:date;not null;uniqueIndex:uk_actividad_fecha"`
+	HorarioInicio     time.Time  `gorm:"column:horario_inicio;type:datetime;not null"` // Misma convención que actividades (fecha base 2024-01-01)
+	HorarioFinal      time.Time  `gorm:"column:horario_final;type:datetime;not null"`
+	Cupo              uint       `gorm:"type:int;not null"`
+	Instructor        string     `gorm:"type:varchar(50);not null"`
+	InstructorID      *uint      `gorm:"column:instructor_id;index"`
+	Estado            string     `gorm:"type:enum('programada','cancelada');default:programada;not null"`
+	MotivoCancelacion *string    `gorm:"column:motivo_cancelacion;type:varchar(255)"`
+	Personalizada     bool       `gorm:"column:personalizada;default:false;not null"`
+	FechaOriginal     *time.Time `gorm:"column:fecha_original;type:date"` // Fecha que le tocaba si se reprogramó
+	CreatedAt         time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (Sesion) TableName() string {
+	return "sesiones"
+}
+
+// SesionFromDomain convierte de Domain (negocio) a DAO (MySQL)
+// fecha, horaInicio y horaFin ya vienen parseados por el servicio
+func SesionFromDomain(s domain.Sesion, fecha, horaInicio, horaFin time.Time) Sesion {
+	var motivo *string
+	if s.MotivoCancelacion != "" {
+		motivo = &s.MotivoCancelacion
+	}
+
+	estado := s.Estado
+	if estado == "" {
+		estado = domain.SesionProgramada
+	}
+
+	return Sesion{
+		ID:                s.ID,
+		ActividadID:       s.ActividadID,
+		Fecha:             fecha,
+		HorarioInicio:     horaInicio,
+		HorarioFinal:      horaFin,
+		Cupo:              s.Cupo,
+		Instructor:        s.Instructor,
+		InstructorID:      s.InstructorID,
+		Estado:            estado,
+		MotivoCancelacion: motivo,
+		Personalizada:     s.Personalizada,
+	}
+}
+
+// SesionVista representa la vista MySQL con los lugares disponibles de cada sesión
+// Los lugares descuentan las inscripciones fijas de la actividad y las reservas de la sesión
+type SesionVista struct {
+	ID                uint       `gorm:"column:id_sesion;primaryKey"`
+	ActividadID       uint       `gorm:"column:actividad_id"`
+	Titulo            string     `gorm:"column:titulo"`
+	SucursalID        *uint      `gorm:"column:sucursal_id"`
+	Fecha             time.Time  `gorm:"column:fecha"`
+	HorarioInicio     time.Time  `gorm:"column:horario_inicio"`
+	HorarioFinal      time.Time  `gorm:"column:horario_final"`
+	Cupo              uint       `gorm:"column:cupo"`
+	Instructor        string     `gorm:"column:instructor"`
+	InstructorID      *uint      `gorm:"column:instructor_id"`
+	Estado            string     `gorm:"column:estado"`
+	MotivoCancelacion *string    `gorm:"column:motivo_cancelacion"`
+	Personalizada     bool       `gorm:"column:personalizada"`
+	FechaOriginal     *time.Time `gorm:"column:fecha_original"`
+	Ocupados          uint       `gorm:"column:ocupados"` // Campo calculado de la vista
+	Lugares           uint       `gorm:"column:lugares"`  // Campo calculado de la vista
+	CreatedAt         time.Time  `gorm:"column:created_at"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at"`
+}
+
+// TableName especifica el nombre de la vista
+func (SesionVista) TableName() string {
+	return "sesiones_lugares"
+}
+
+// ToDomain convierte vista a domain (incluye lugares disponibles)
+func (sv SesionVista) ToDomain() domain.Sesion {
+	motivo := ""
+	if sv.MotivoCancelacion != nil {
+		motivo = *sv.MotivoCancelacion
+	}
+
+	fechaOriginal := ""
+	if sv.FechaOriginal != nil {
+		fechaOriginal = sv.FechaOriginal.Format("2006-01-02")
+	}
+
+	return domain.Sesion{
+		ID:                sv.ID,
+		ActividadID:       sv.ActividadID,
+		Titulo:            sv.Titulo,
+		SucursalID:        sv.SucursalID,
+		Fecha:             sv.Fecha.Format("2006-01-02"),
+		HorarioInicio:     sv.HorarioInicio.Format("15:04"),
+		HorarioFinal:      sv.HorarioFinal.Format("15:04"),
+		Cupo:              sv.Cupo,
+		Instructor:        sv.Instructor,
+		InstructorID:      sv.InstructorID,
+		Estado:            sv.Estado,
+		MotivoCancelacion: motivo,
+		Personalizada:     sv.Personalizada,
+		FechaOriginal:     fechaOriginal,
+		Ocupados:          sv.Ocupados,
+		Lugares:           sv.Lugares,
+		CreatedAt:         sv.CreatedAt,
+		UpdatedAt:         sv.UpdatedAt,
+	}
+}
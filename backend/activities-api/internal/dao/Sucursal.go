@@ -6,15 +6,23 @@ import (
 )
 
 // Sucursal representa el modelo de base de datos con tags de GORM
-// TODO: Para que los compañeros implementen
+// La baja es lógica (activa = false): las asistencias y reportes siguen apuntando a la sucursal
 type Sucursal struct {
-	ID        uint       `gorm:"column:id_sucursal;primaryKey;autoIncrement"`
-	Nombre    string     `gorm:"type:varchar(100);not null"`
-	Direccion string     `gorm:"type:varchar(255);not null"`
-	Telefono  string     `gorm:"type:varchar(20)"`
-	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt time.Time  `gorm:"column:updated_at;autoUpdateTime"`
-	DeletedAt *time.Time `gorm:"index"` // Soft delete
+	ID           uint      `gorm:"column:id_sucursal;primaryKey;autoIncrement"`
+	Nombre       string    `gorm:"type:varchar(100);not null"`
+	Direccion    string    `gorm:"type:varchar(255);not null"`
+	Telefono     string    `gorm:"type:varchar(20)"`
+	Ciudad       string    `gorm:"type:varchar(100)"`
+	CodigoPostal string    `gorm:"column:codigo_postal;type:varchar(10)"`
+	ZonaHoraria  string    `gorm:"column:zona_horaria;type:varchar(64);not null"`
+	Latitud      *float64  `gorm:"type:decimal(9,6)"`
+	Longitud     *float64  `gorm:"type:decimal(9,6)"`
+	Activa       bool      `gorm:"default:true;not null"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime"`
+
+	// Horario semanal (tabla sucursal_horarios)
+	Horarios []SucursalHorario `gorm:"foreignKey:SucursalID"`
 
 	// Relación con Actividades
 	Actividades []Actividad `gorm:"foreignKey:SucursalID"`
@@ -25,26 +33,80 @@ func (Sucursal) TableName() string {
 	return "sucursales"
 }
 
+// SucursalHorario representa el rango de apertura de una sucursal en un día de la semana
+// apertura y cierre son columnas TIME: se leen como "HH:MM:SS" y se escriben como "HH:MM"
+type SucursalHorario struct {
+	ID         uint   `gorm:"column:id_horario;primaryKey;autoIncrement"`
+	SucursalID uint   `gorm:"column:sucursal_id;not null;uniqueIndex:unique_sucursal_dia"`
+	Dia        string `gorm:"type:enum('Lunes','Martes','Miercoles','Jueves','Viernes','Sabado','Domingo');not null;uniqueIndex:unique_sucursal_dia"`
+	Apertura   string `gorm:"type:time;not null"`
+	Cierre     string `gorm:"type:time;not null"`
+}
+
+// TableName especifica el nombre de la tabla
+func (SucursalHorario) TableName() string {
+	return "sucursal_horarios"
+}
+
 // ToDomain convierte de DAO (MySQL) a Domain (negocio)
 func (s Sucursal) ToDomain() domain.Sucursal {
+	horarios := make([]domain.HorarioSucursal, len(s.Horarios))
+	for i, h := range s.Horarios {
+		horarios[i] = domain.HorarioSucursal{
+			Dia:      h.Dia,
+			Apertura: horaMinutos(h.Apertura),
+			Cierre:   horaMinutos(h.Cierre),
+		}
+	}
+
 	return domain.Sucursal{
-		ID:        s.ID,
-		Nombre:    s.Nombre,
-		Direccion: s.Direccion,
-		Telefono:  s.Telefono,
-		CreatedAt: s.CreatedAt,
-		UpdatedAt: s.UpdatedAt,
+		ID:           s.ID,
+		Nombre:       s.Nombre,
+		Direccion:    s.Direccion,
+		Ciudad:       s.Ciudad,
+		CodigoPostal: s.CodigoPostal,
+		Telefono:     s.Telefono,
+		ZonaHoraria:  s.ZonaHoraria,
+		Latitud:      s.Latitud,
+		Longitud:     s.Longitud,
+		Horarios:     horarios,
+		Activa:       s.Activa,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
 	}
 }
 
-// FromDomain convierte de Domain (negocio) a DAO (MySQL)
+// SucursalFromDomain convierte de Domain (negocio) a DAO (MySQL)
 func SucursalFromDomain(domainSuc domain.Sucursal) Sucursal {
+	horarios := make([]SucursalHorario, len(domainSuc.Horarios))
+	for i, h := range domainSuc.Horarios {
+		horarios[i] = SucursalHorario{
+			SucursalID: domainSuc.ID,
+			Dia:        h.Dia,
+			Apertura:   h.Apertura,
+			Cierre:     h.Cierre,
+		}
+	}
+
 	return Sucursal{
-		ID:        domainSuc.ID,
-		Nombre:    domainSuc.Nombre,
-		Direccion: domainSuc.Direccion,
-		Telefono:  domainSuc.Telefono,
+		ID:           domainSuc.ID,
+		Nombre:       domainSuc.Nombre,
+		Direccion:    domainSuc.Direccion,
+		Ciudad:       domainSuc.Ciudad,
+		CodigoPostal: domainSuc.CodigoPostal,
+		Telefono:     domainSuc.Telefono,
+		ZonaHoraria:  domainSuc.ZonaHoraria,
+		Latitud:      domainSuc.Latitud,
+		Longitud:     domainSuc.Longitud,
+		Activa:       domainSuc.Activa,
+		Horarios:     horarios,
 	}
 }
 
-// TODO: Los compañeros deben crear el repository completo
+// horaMinutos recorta "HH:MM:SS" (columna TIME) a "HH:MM"
+func horaMinutos(hora string) string {
+	if len(hora) > 5 {
+		return hora[:5]
+	}
+	return hora
+}
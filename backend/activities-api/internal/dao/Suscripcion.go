@@ -0,0 +1,100 @@
+package dao
+
+import (
+	"activities-api/internal/domain"
+	"time"
+)
+
+// Suscripcion representa el modelo de base de datos con tags de GORM
+// Copia local de las suscripciones de subscriptions-api (la clave es el ObjectID de Mongo)
+type Suscripcion struct {
+	ID               string     `gorm:"column:id_suscripcion;type:varchar(24);primaryKey"`
+	UsuarioID        uint       `gorm:"column:usuario_id;not null;index:idx_usuario"`
+	PlanID           string     `gorm:"column:plan_id;type:varchar(24);not null;default:''"`
+	Estado           string     `gorm:"type:varchar(30);not null;index:idx_estado"`
+	FechaInicio      *time.Time `gorm:"column:fecha_inicio"`
+	FechaVencimiento *time.Time `gorm:"column:fecha_vencimiento"`
+	EventoEn         time.Time  `gorm:"column:evento_en;type:datetime(6);not null"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (Suscripcion) TableName() string {
+	return "suscripciones"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (s Suscripcion) ToDomain() domain.Suscripcion {
+	return domain.Suscripcion{
+		ID:               s.ID,
+		UsuarioID:        s.UsuarioID,
+		PlanID:           s.PlanID,
+		Estado:           s.Estado,
+		FechaInicio:      s.FechaInicio,
+		FechaVencimiento: s.FechaVencimiento,
+		EventoEn:         s.EventoEn,
+	}
+}
+
+// SuscripcionFromDomain convierte de Domain (negocio) a DAO (MySQL)
+func SuscripcionFromDomain(s domain.Suscripcion) Suscripcion {
+	return Suscripcion{
+		ID:               s.ID,
+		UsuarioID:        s.UsuarioID,
+		PlanID:           s.PlanID,
+		Estado:           s.Estado,
+		FechaInicio:      s.FechaInicio,
+		FechaVencimiento: s.FechaVencimiento,
+		EventoEn:         s.EventoEn,
+	}
+}
+
+// Plan representa el modelo de base de datos con tags de GORM
+// Copia local de las condiciones de los planes de subscriptions-api
+type Plan struct {
+	ID                    string    `gorm:"column:id_plan;type:varchar(24);primaryKey"`
+	Nombre                string    `gorm:"type:varchar(100);not null;default:''"`
+	TipoAcceso            string    `gorm:"column:tipo_acceso;type:varchar(20);not null;default:''"`
+	ActividadesPermitidas []string  `gorm:"column:actividades_permitidas;type:json;serializer:json"`
+	ActividadesPorSemana  int       `gorm:"column:actividades_por_semana;not null;default:0"`
+	CupoClases            int       `gorm:"column:cupo_clases;not null;default:0"`
+	CupoPeriodo           string    `gorm:"column:cupo_periodo;type:varchar(10);not null;default:''"`
+	Activo                bool      `gorm:"not null;default:true"`
+	EventoEn              time.Time `gorm:"column:evento_en;type:datetime(6);not null"`
+	UpdatedAt             time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName especifica el nombre de la tabla
+func (Plan) TableName() string {
+	return "planes"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (p Plan) ToDomain() domain.Plan {
+	return domain.Plan{
+		ID:                    p.ID,
+		Nombre:                p.Nombre,
+		TipoAcceso:            p.TipoAcceso,
+		ActividadesPermitidas: p.ActividadesPermitidas,
+		ActividadesPorSemana:  p.ActividadesPorSemana,
+		CupoClases:            p.CupoClases,
+		CupoPeriodo:           p.CupoPeriodo,
+		Activo:                p.Activo,
+		EventoEn:              p.EventoEn,
+	}
+}
+
+// PlanFromDomain convierte de Domain (negocio) a DAO (MySQL)
+func PlanFromDomain(p domain.Plan) Plan {
+	return Plan{
+		ID:                    p.ID,
+		Nombre:                p.Nombre,
+		TipoAcceso:            p.TipoAcceso,
+		ActividadesPermitidas: p.ActividadesPermitidas,
+		ActividadesPorSemana:  p.ActividadesPorSemana,
+		CupoClases:            p.CupoClases,
+		CupoPeriodo:           p.CupoPeriodo,
+		Activo:                p.Activo,
+		EventoEn:              p.EventoEn,
+	}
+}
@@ -5,84 +5,152 @@ import "time"
 // Actividad representa la entidad de negocio Actividad
 // Independiente de la base de datos
 type Actividad struct {
-	ID             uint       `json:"id"`
-	Titulo         string     `json:"titulo"`
-	Descripcion    string     `json:"descripcion"`
-	Cupo           uint       `json:"cupo"`
-	Dia            string     `json:"dia"`
-	HorarioInicio  string     `json:"horario_inicio"` // Formato "HH:MM"
-	HorarioFinal   string     `json:"horario_final"`  // Formato "HH:MM"
-	FotoUrl        string     `json:"foto_url"`
-	Instructor     string     `json:"instructor"`
-	Categoria      string     `json:"categoria"`
-	SucursalID     *uint      `json:"sucursal_id,omitempty"`
-	SucursalNombre string     `json:"sucursal_nombre,omitempty"` // Nombre de la sucursal (JOIN)
-	Lugares        uint       `json:"lugares,omitempty"`         // Campo calculado (cupos disponibles)
-	CupoDisponible uint       `json:"cupo_disponible,omitempty"` // Alias de Lugares para eventos
-	Activa         bool       `json:"activa"`
-	CreatedAt      time.Time  `json:"created_at,omitempty"`
-	UpdatedAt      time.Time  `json:"updated_at,omitempty"`
-	DeletedAt      *time.Time `json:"deleted_at,omitempty"` // Soft delete
+	ID                 uint       `json:"id"`
+	Titulo             string     `json:"titulo"`
+	Descripcion        string     `json:"descripcion"`
+	Cupo               uint       `json:"cupo"`
+	Dia                string     `json:"dia"`
+	HorarioInicio      string     `json:"horario_inicio"` // Formato "HH:MM"
+	HorarioFinal       string     `json:"horario_final"`  // Formato "HH:MM"
+	FotoUrl            string     `json:"foto_url"`
+	Instructor         string     `json:"instructor"`
+	InstructorID       *uint      `json:"instructor_id,omitempty"`        // Usuario (rol instructor) que dicta la clase
+	InstructorPerfilID *uint      `json:"instructor_perfil_id,omitempty"` // Instructor (tabla instructores) que dicta la clase
+	Categoria          string     `json:"categoria"`
+	SucursalID         *uint      `json:"sucursal_id,omitempty"`
+	SalaID             *uint      `json:"sala_id,omitempty"`         // Sala de la sucursal donde se dicta
+	SucursalNombre     string     `json:"sucursal_nombre,omitempty"` // Nombre de la sucursal (JOIN)
+	Lugares            uint       `json:"lugares,omitempty"`         // Campo calculado (cupos disponibles)
+	CupoDisponible     uint       `json:"cupo_disponible,omitempty"` // Alias de Lugares para eventos
+	Activa             bool       `json:"activa"`
+	CreatedAt          time.Time  `json:"created_at,omitempty"`
+	UpdatedAt          time.Time  `json:"updated_at,omitempty"`
+	DeletedAt          *time.Time `json:"deleted_at,omitempty"` // Soft delete
+
+	// Promedio (1 a 5, 0 = sin calificaciones) y cantidad de calificaciones visibles
+	CalificacionPromedio           float64 `json:"calificacion_promedio"`
+	Calificaciones                 uint    `json:"calificaciones"`
+	InstructorCalificacionPromedio float64 `json:"instructor_calificacion_promedio"` // Del instructor (instructor_perfil_id) en todas sus clases
+	InstructorCalificaciones       uint    `json:"instructor_calificaciones"`
 }
 
 // ActividadCreate representa los datos para crear una actividad
 type ActividadCreate struct {
-	Titulo        string `json:"titulo" binding:"required"`
-	Descripcion   string `json:"descripcion"`
-	Cupo          uint   `json:"cupo" binding:"required,min=1"`
-	Dia           string `json:"dia" binding:"required"`
-	HorarioInicio string `json:"horario_inicio" binding:"required"` // "HH:MM"
-	HorarioFinal  string `json:"horario_final" binding:"required"`  // "HH:MM"
-	FotoUrl       string `json:"foto_url"`
-	Instructor    string `json:"instructor" binding:"required"`
-	Categoria     string `json:"categoria" binding:"required"`
-	SucursalID    *uint  `json:"sucursal_id,omitempty"` // TODO: Validar que existe
+	Titulo             string `json:"titulo" binding:"required"`
+	Descripcion        string `json:"descripcion"`
+	Cupo               uint   `json:"cupo" binding:"required,min=1"`
+	Dia                string `json:"dia" binding:"required"`
+	HorarioInicio      string `json:"horario_inicio" binding:"required"` // "HH:MM"
+	HorarioFinal       string `json:"horario_final" binding:"required"`  // "HH:MM"
+	FotoUrl            string `json:"foto_url"`
+	Instructor         string `json:"instructor"`                     // Texto libre; se ignora si viene instructor_perfil_id
+	InstructorID       *uint  `json:"instructor_id,omitempty"`        // ID en users-api del instructor (ve los inscriptos de sus clases)
+	InstructorPerfilID *uint  `json:"instructor_perfil_id,omitempty"` // Instructor registrado: completa nombre e instructor_id y valida superposiciones
+	Categoria          string `json:"categoria" binding:"required"`
+	SucursalID         *uint  `json:"sucursal_id,omitempty"` // Debe existir y estar activa
+	SalaID             *uint  `json:"sala_id,omitempty"`     // Sala de la sucursal: limita el cupo y no se puede superponer
 }
 
 // ActividadUpdate representa los datos para actualizar una actividad
 type ActividadUpdate struct {
-	Titulo        string `json:"titulo" binding:"required"`
-	Descripcion   string `json:"descripcion"`
-	Cupo          uint   `json:"cupo" binding:"required,min=1"`
-	Dia           string `json:"dia" binding:"required"`
-	HorarioInicio string `json:"horario_inicio" binding:"required"`
-	HorarioFinal  string `json:"horario_final" binding:"required"`
-	FotoUrl       string `json:"foto_url"`
-	Instructor    string `json:"instructor" binding:"required"`
-	Categoria     string `json:"categoria" binding:"required"`
-	SucursalID    *uint  `json:"sucursal_id,omitempty"`
+	Titulo             string `json:"titulo" binding:"required"`
+	Descripcion        string `json:"descripcion"`
+	Cupo               uint   `json:"cupo" binding:"required,min=1"`
+	Dia                string `json:"dia" binding:"required"`
+	HorarioInicio      string `json:"horario_inicio" binding:"required"`
+	HorarioFinal       string `json:"horario_final" binding:"required"`
+	FotoUrl            string `json:"foto_url"`
+	Instructor         string `json:"instructor"`
+	InstructorID       *uint  `json:"instructor_id,omitempty"`
+	InstructorPerfilID *uint  `json:"instructor_perfil_id,omitempty"`
+	Categoria          string `json:"categoria" binding:"required"`
+	SucursalID         *uint  `json:"sucursal_id,omitempty"`
+	SalaID             *uint  `json:"sala_id,omitempty"`
 }
 
 // ActividadResponse representa la respuesta HTTP de una actividad
 type ActividadResponse struct {
-	ID            uint   `json:"id"`
-	Titulo        string `json:"titulo"`
-	Descripcion   string `json:"descripcion"`
-	Cupo          uint   `json:"cupo"`
-	Dia           string `json:"dia"`
-	HorarioInicio string `json:"horario_inicio"` // "HH:MM"
-	HorarioFinal  string `json:"horario_final"`  // "HH:MM"
-	FotoUrl       string `json:"foto_url"`
-	Instructor    string `json:"instructor"`
-	Categoria     string `json:"categoria"`
-	SucursalID    *uint  `json:"sucursal_id,omitempty"`
-	Lugares       uint   `json:"lugares"` // Campo calculado de cupos disponibles
+	ID                 uint   `json:"id"`
+	Titulo             string `json:"titulo"`
+	Descripcion        string `json:"descripcion"`
+	Cupo               uint   `json:"cupo"`
+	Dia                string `json:"dia"`
+	HorarioInicio      string `json:"horario_inicio"` // "HH:MM"
+	HorarioFinal       string `json:"horario_final"`  // "HH:MM"
+	FotoUrl            string `json:"foto_url"`
+	Instructor         string `json:"instructor"`
+	InstructorID       *uint  `json:"instructor_id,omitempty"`
+	InstructorPerfilID *uint  `json:"instructor_perfil_id,omitempty"`
+	Categoria          string `json:"categoria"`
+	SucursalID         *uint  `json:"sucursal_id,omitempty"`
+	SalaID             *uint  `json:"sala_id,omitempty"`
+	Lugares            uint   `json:"lugares"` // Campo calculado de cupos disponibles
+
+	CalificacionPromedio           float64 `json:"calificacion_promedio"`
+	Calificaciones                 uint    `json:"calificaciones"`
+	InstructorCalificacionPromedio float64 `json:"instructor_calificacion_promedio"`
+	InstructorCalificaciones       uint    `json:"instructor_calificaciones"`
 }
 
 // ToResponse convierte de Actividad a ActividadResponse
 func (a Actividad) ToResponse() ActividadResponse {
 	return ActividadResponse{
-		ID:            a.ID,
-		Titulo:        a.Titulo,
-		Descripcion:   a.Descripcion,
-		Cupo:          a.Cupo,
-		Dia:           a.Dia,
-		HorarioInicio: a.HorarioInicio,
-		HorarioFinal:  a.HorarioFinal,
-		FotoUrl:       a.FotoUrl,
-		Instructor:    a.Instructor,
-		Categoria:     a.Categoria,
-		SucursalID:    a.SucursalID,
-		Lugares:       a.Lugares,
+		ID:                 a.ID,
+		Titulo:             a.Titulo,
+		Descripcion:        a.Descripcion,
+		Cupo:               a.Cupo,
+		Dia:                a.Dia,
+		HorarioInicio:      a.HorarioInicio,
+		HorarioFinal:       a.HorarioFinal,
+		FotoUrl:            a.FotoUrl,
+		Instructor:         a.Instructor,
+		InstructorID:       a.InstructorID,
+		InstructorPerfilID: a.InstructorPerfilID,
+		Categoria:          a.Categoria,
+		SucursalID:         a.SucursalID,
+		SalaID:             a.SalaID,
+		Lugares:            a.Lugares,
+
+		CalificacionPromedio:           a.CalificacionPromedio,
+		Calificaciones:                 a.Calificaciones,
+		InstructorCalificacionPromedio: a.InstructorCalificacionPromedio,
+		InstructorCalificaciones:       a.InstructorCalificaciones,
 	}
 }
+
+// FilaPlanilla es una fila leída de la planilla de horarios (CSV/XLSX)
+type FilaPlanilla struct {
+	Fila      int             // Número de fila en la planilla (la cabecera es la 1)
+	ID        uint            // Actividad a modificar (0 = alta)
+	Actividad ActividadCreate // Mismos campos que POST /actividades
+	Error     string          // Error de formato al leer la fila (número mal escrito, etc.)
+}
+
+// ActividadImportada es una fila validada lista para guardarse
+type ActividadImportada struct {
+	Fila       int
+	ID         uint // 0 = alta
+	Actividad  Actividad
+	HoraInicio time.Time
+	HoraFin    time.Time
+}
+
+// ResultadoFilaImportacion es el resultado de una fila de la importación
+type ResultadoFilaImportacion struct {
+	Fila   int    `json:"fila"`
+	ID     uint   `json:"id,omitempty"` // Actividad modificada o creada (en dry run solo para modificaciones)
+	Accion string `json:"accion"`       // "create" o "update"
+	Titulo string `json:"titulo"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ResultadoImportacion resume una importación de la planilla de horarios
+type ResultadoImportacion struct {
+	DryRun       bool                       `json:"dry_run"`
+	Total        int                        `json:"total"`
+	Validas      int                        `json:"validas"`
+	Invalidas    int                        `json:"invalidas"`
+	Creadas      int                        `json:"creadas"`
+	Actualizadas int                        `json:"actualizadas"`
+	Filas        []ResultadoFilaImportacion `json:"filas"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrAsistenciaYaRegistrada indica que el socio ya tiene registrada esa clase (o la sala libre del día)
+var ErrAsistenciaYaRegistrada = errors.New("la asistencia ya fue registrada")
+
+// Tipos de asistencia
+const (
+	AsistenciaClase = "clase" // Check-in a una sesión en la que el socio está inscripto
+	AsistenciaLibre = "libre" // Acceso a sala libre (open gym) con la suscripción activa
+)
+
+// Estados de una asistencia
+const (
+	AsistenciaPresente = "presente"
+	AsistenciaAusente  = "ausente" // Marcada por el job nocturno: estaba inscripto y no vino
+)
+
+// Asistencia representa el registro de que un socio vino (o faltó) a una clase o a sala libre
+type Asistencia struct {
+	ID            uint      `json:"id"`
+	UsuarioID     uint      `json:"usuario_id"`
+	ActividadID   *uint     `json:"actividad_id,omitempty"` // Nil en sala libre
+	SesionID      *uint     `json:"sesion_id,omitempty"`    // Nil en sala libre
+	SucursalID    *uint     `json:"sucursal_id,omitempty"`
+	Tipo          string    `json:"tipo"`                     // "clase" | "libre"
+	Estado        string    `json:"estado"`                   // "presente" | "ausente"
+	RegistradaPor *uint     `json:"registrada_por,omitempty"` // Staff que escaneó el QR (nil si la marcó el job)
+	Fecha         string    `json:"fecha"`                    // Formato "YYYY-MM-DD"
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// CheckInRequest es lo que envía el staff al escanear el QR del socio
+// Sin sesion_id se busca la clase del socio que está por empezar o en curso;
+// si no tiene ninguna, es un acceso a sala libre en sucursal_id
+type CheckInRequest struct {
+	QR         string `json:"qr" binding:"required"`
+	SesionID   *uint  `json:"sesion_id,omitempty"`
+	SucursalID *uint  `json:"sucursal_id,omitempty"`
+}
+
+// CodigoQR es el payload firmado que muestra el socio (rota cada pocos segundos)
+type CodigoQR struct {
+	QR       string    `json:"qr"`
+	ExpiraEn time.Time `json:"expira_en"` // Momento en que conviene pedir uno nuevo
+}
+
+// AsistenciaSesion resume la asistencia de una sesión
+type AsistenciaSesion struct {
+	SesionID      uint    `json:"sesion_id"`
+	Fecha         string  `json:"fecha"`
+	HorarioInicio string  `json:"horario_inicio"`
+	Estado        string  `json:"estado"` // Estado de la sesión ("programada" | "cancelada")
+	Presentes     int     `json:"presentes"`
+	Ausentes      int     `json:"ausentes"`
+	Tasa          float64 `json:"tasa_asistencia"` // presentes / (presentes + ausentes), 0 sin registros
+}
+
+// ReporteAsistencia es el reporte de asistencia de una actividad en un rango de fechas
+type ReporteAsistencia struct {
+	ActividadID uint               `json:"actividad_id"`
+	Titulo      string             `json:"titulo"`
+	Desde       string             `json:"desde"`
+	Hasta       string             `json:"hasta"`
+	Sesiones    []AsistenciaSesion `json:"sesiones"`
+	Presentes   int                `json:"presentes"`
+	Ausentes    int                `json:"ausentes"`
+	Tasa        float64            `json:"tasa_asistencia"`
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "errors"
+
+// ErrCalendarioTokenInvalido indica que ningún socio tiene ese token de calendario (o que se regeneró)
+var ErrCalendarioTokenInvalido = errors.New("token de calendario inválido")
+
+// CalendarioFeed es la URL secreta del calendario iCalendar de un socio
+// Quien tenga la URL ve las clases del socio: se puede regenerar para invalidar la anterior
+type CalendarioFeed struct {
+	Token string `json:"token"`
+	URL   string `json:"url"` // GET /inscripciones/calendar.ics?token=...
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Errores del repositorio de calificaciones
+var (
+	ErrCalificacionNotFound  = errors.New("calificacion not found")
+	ErrCalificacionDuplicada = errors.New("ya calificaste esta clase")
+)
+
+// Rango del puntaje de una calificación
+const (
+	PuntajeMinimo = 1
+	PuntajeMaximo = 5
+)
+
+// Calificacion es el puntaje (1 a 5) y comentario opcional de un socio sobre una sesión
+// Las ocultas por moderación no se muestran ni cuentan en los promedios; las marcadas quedan para revisar
+type Calificacion struct {
+	ID                 uint       `json:"id"`
+	SesionID           uint       `json:"sesion_id"`
+	ActividadID        uint       `json:"actividad_id"`
+	UsuarioID          uint       `json:"usuario_id"`
+	InstructorPerfilID *uint      `json:"instructor_perfil_id,omitempty"` // Instructor que dictó la sesión (nil = reemplazo sin perfil)
+	Fecha              string     `json:"fecha,omitempty"`                // Fecha de la sesión "YYYY-MM-DD" (JOIN)
+	Puntaje            int        `json:"puntaje"`
+	Comentario         string     `json:"comentario,omitempty"`
+	Oculta             bool       `json:"oculta"`
+	MotivoOcultamiento string     `json:"motivo_ocultamiento,omitempty"`
+	Marcada            bool       `json:"marcada"`
+	MotivoMarca        string     `json:"motivo_marca,omitempty"`
+	ModeradaPor        *uint      `json:"moderada_por,omitempty"`
+	ModeradaEn         *time.Time `json:"moderada_en,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// CalificacionCreate representa la calificación de una sesión por parte del socio
+type CalificacionCreate struct {
+	Puntaje    int    `json:"puntaje" binding:"required,min=1,max=5"`
+	Comentario string `json:"comentario"`
+}
+
+// CalificacionModeracion es el motivo con que un admin oculta o marca una calificación
+type CalificacionModeracion struct {
+	Motivo string `json:"motivo"`
+}
+
+// FiltroCalificaciones selecciona calificaciones; los campos nil no filtran
+type FiltroCalificaciones struct {
+	ActividadID        *uint
+	InstructorPerfilID *uint
+	Oculta             *bool
+	Marcada            *bool
+	Limit              int // 0 = sin límite
+}
+
+// ResumenCalificaciones es el promedio (0 = sin calificaciones) y la cantidad de calificaciones visibles
+type ResumenCalificaciones struct {
+	Promedio float64 `json:"promedio"`
+	Cantidad int     `json:"cantidad"`
+}
+
+// CalificacionesResponse es el resumen y las últimas calificaciones visibles de una actividad o instructor
+type CalificacionesResponse struct {
+	Resumen        ResumenCalificaciones `json:"resumen"`
+	Calificaciones []Calificacion        `json:"calificaciones"`
+}
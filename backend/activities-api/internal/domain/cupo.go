@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCupoAgotado indica que el socio ya usó todas las clases del período de su plan
+var ErrCupoAgotado = errors.New("has alcanzado el cupo de clases de tu plan")
+
+// Períodos del cupo de clases de un plan
+const (
+	CupoDiario  = "diario"
+	CupoSemanal = "semanal" // Semana ISO (lunes a domingo)
+	CupoMensual = "mensual"
+)
+
+// Estados de un consumo del cupo
+const (
+	ConsumoUsado       = "usado"
+	ConsumoReintegrado = "reintegrado"
+)
+
+// Motivos de reintegro de un consumo
+const (
+	ReintegroCancelacion          = "cancelacion"           // El socio se dio de baja de la clase
+	ReintegroClaseCancelada       = "clase_cancelada"       // El gimnasio canceló la sesión
+	ReintegroSuscripcionCancelada = "suscripcion_cancelada" // Se cancelaron todas las inscripciones del socio
+	ReintegroReembolso            = "reembolso"             // Se reembolsó el pago de la suscripción
+)
+
+// ConsumoCupo es una clase descontada del cupo del plan: una reserva o una sesión de una inscripción fija
+// Se identifica por inscripción y sesión; el período sale de la fecha de la clase, no de la inscripción
+type ConsumoCupo struct {
+	ID              uint       `json:"id"`
+	UsuarioID       uint       `json:"usuario_id"`
+	SuscripcionID   string     `json:"suscripcion_id"`
+	InscripcionID   uint       `json:"inscripcion_id"`
+	ActividadID     uint       `json:"actividad_id"`
+	SesionID        uint       `json:"sesion_id"`
+	Titulo          string     `json:"titulo,omitempty"` // De la sesión
+	FechaClase      string     `json:"fecha_clase"`      // Formato "YYYY-MM-DD"
+	Periodo         string     `json:"periodo"`          // diario | semanal | mensual
+	PeriodoClave    string     `json:"periodo_clave"`    // 2025-03-10 | 2025-W11 | 2025-03
+	Estado          string     `json:"estado"`           // usado | reintegrado
+	MotivoReintegro string     `json:"motivo_reintegro,omitempty"`
+	ReintegradoEn   *time.Time `json:"reintegrado_en,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// CargoCupo es lo que descuenta una inscripción del cupo del plan
+// La transacción que crea la inscripción vuelve a contar las clases de cada período y registra los consumos
+type CargoCupo struct {
+	Limite   int           // 0 = sin límite: se registra sin validar
+	Consumos []ConsumoCupo // Sin InscripcionID: lo completa la transacción
+}
+
+// PeriodoCargo son las clases nuevas que un cargo suma a un período del cupo
+type PeriodoCargo struct {
+	Clave  string
+	Desde  time.Time // Inclusive
+	Hasta  time.Time
+	Nuevas int
+}
+
+// Periodos agrupa los consumos del cargo por período, en el orden de sus clases
+// Una inscripción fija suma clases a varios períodos: en cada uno usadas + nuevas no puede pasar el límite
+func (c CargoCupo) Periodos() []PeriodoCargo {
+	var periodos []PeriodoCargo
+	indices := map[string]int{}
+	for _, consumo := range c.Consumos {
+		fecha, err := time.Parse("2006-01-02", consumo.FechaClase)
+		if err != nil {
+			continue
+		}
+		clave, desde, hasta := PeriodoCupo(consumo.Periodo, fecha)
+		i, ok := indices[clave]
+		if !ok {
+			i = len(periodos)
+			indices[clave] = i
+			periodos = append(periodos, PeriodoCargo{Clave: clave, Desde: desde, Hasta: hasta})
+		}
+		periodos[i].Nuevas++
+	}
+	return periodos
+}
+
+// UsoCupo es el uso del cupo del plan en un período ("3 de 4 clases usadas esta semana")
+type UsoCupo struct {
+	Periodo      string        `json:"periodo"`
+	PeriodoClave string        `json:"periodo_clave"`
+	Desde        string        `json:"desde"` // Formato "YYYY-MM-DD"
+	Hasta        string        `json:"hasta"`
+	Usadas       int           `json:"usadas"`
+	Limite       int           `json:"limite"`                // 0 = sin límite
+	Disponibles  *int          `json:"disponibles,omitempty"` // Sin límite no se informa
+	Plan         string        `json:"plan"`
+	Mensaje      string        `json:"mensaje"`
+	Clases       []ConsumoCupo `json:"clases"`
+}
+
+// PeriodoCupo devuelve la clave y el rango (inclusive) del período que contiene la fecha
+// La fecha es el día de la clase en la zona de su sucursal; las semanas son ISO (lunes a domingo)
+func PeriodoCupo(periodo string, fecha time.Time) (clave string, desde, hasta time.Time) {
+	dia := time.Date(fecha.Year(), fecha.Month(), fecha.Day(), 0, 0, 0, 0, fecha.Location())
+
+	switch periodo {
+	case CupoDiario:
+		return dia.Format("2006-01-02"), dia, dia
+	case CupoMensual:
+		desde = dia.AddDate(0, 0, 1-dia.Day())
+		return desde.Format("2006-01"), desde, desde.AddDate(0, 1, -1)
+	default:
+		anio, semana := dia.ISOWeek()
+		diasDesdeLunes := (int(dia.Weekday()) + 6) % 7
+		desde = dia.AddDate(0, 0, -diasDesdeLunes)
+		return fmt.Sprintf("%04d-W%02d", anio, semana), desde, desde.AddDate(0, 0, 6)
+	}
+}
@@ -1,23 +1,35 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// Errores de inscripción (el controller los responde con 409)
+var (
+	ErrCupoAlcanzado = errors.New("no se puede inscribir, el cupo de la actividad ha sido alcanzado")
+	ErrYaInscripto   = errors.New("el usuario ya está inscripto a esta actividad")
+)
 
 // Inscripcion representa la entidad de negocio Inscripcion
 type Inscripcion struct {
 	ID               uint      `json:"id"`
 	UsuarioID        uint      `json:"usuario_id"`
 	ActividadID      uint      `json:"actividad_id"`
+	SesionID         *uint     `json:"sesion_id,omitempty"` // Nil = inscripción fija semanal, si no reserva de una sesión
 	FechaInscripcion time.Time `json:"fecha_inscripcion"`
 	IsActiva         bool      `json:"is_activa"`
 	SuscripcionID    *string   `json:"suscripcion_id,omitempty"` // TODO: Agregar cuando se implemente subscriptions-api
+	PlanID           *string   `json:"plan_id,omitempty"`        // Plan al inscribirse: elige la política de cancelación
 	CreatedAt        time.Time `json:"created_at,omitempty"`
 	UpdatedAt        time.Time `json:"updated_at,omitempty"`
 }
 
 // InscripcionCreate representa los datos para crear una inscripción
 type InscripcionCreate struct {
-	UsuarioID   uint `json:"usuario_id" binding:"required"`
-	ActividadID uint `json:"actividad_id" binding:"required"`
+	UsuarioID   uint  `json:"usuario_id" binding:"required"`
+	ActividadID uint  `json:"actividad_id" binding:"required"`
+	SesionID    *uint `json:"sesion_id,omitempty"` // Opcional: reserva solo esa sesión
 	// TODO: Agregar SuscripcionID cuando se implemente subscriptions-api
 }
 
@@ -26,6 +38,7 @@ type InscripcionResponse struct {
 	ID               uint      `json:"id"`
 	UsuarioID        uint      `json:"usuario_id"`
 	ActividadID      uint      `json:"actividad_id"`
+	SesionID         *uint     `json:"sesion_id,omitempty"`
 	FechaInscripcion time.Time `json:"fecha_inscripcion"`
 	IsActiva         bool      `json:"is_activa"`
 	SuscripcionID    *string   `json:"suscripcion_id,omitempty"`
@@ -39,6 +52,7 @@ func (i Inscripcion) ToResponse() InscripcionResponse {
 		ID:               i.ID,
 		UsuarioID:        i.UsuarioID,
 		ActividadID:      i.ActividadID,
+		SesionID:         i.SesionID,
 		FechaInscripcion: i.FechaInscripcion,
 		IsActiva:         i.IsActiva,
 		SuscripcionID:    i.SuscripcionID,
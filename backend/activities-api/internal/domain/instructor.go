@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Errores del repositorio de instructores
+var (
+	ErrInstructorNotFound         = errors.New("instructor not found")
+	ErrInstructorUsuarioDuplicado = errors.New("la cuenta de usuario ya está vinculada a otro instructor")
+)
+
+// Instructor representa el perfil de un instructor (las actividades lo referencian por ID)
+type Instructor struct {
+	ID             uint      `json:"id"`
+	Nombre         string    `json:"nombre"`
+	Bio            string    `json:"bio,omitempty"`
+	FotoUrl        string    `json:"foto_url,omitempty"`
+	Especialidades []string  `json:"especialidades"`
+	UsuarioID      *uint     `json:"usuario_id,omitempty"` // Cuenta en users-api (rol instructor): ve los inscriptos de sus clases
+	Sucursales     []uint    `json:"sucursales"`           // Sucursales donde da clases (vacía = todas)
+	Activo         bool      `json:"activo"`
+	CreatedAt      time.Time `json:"created_at,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at,omitempty"`
+}
+
+// DaClasesEn indica si el instructor puede dar clases en la sucursal
+func (i Instructor) DaClasesEn(sucursalID uint) bool {
+	if len(i.Sucursales) == 0 {
+		return true
+	}
+	for _, id := range i.Sucursales {
+		if id == sucursalID {
+			return true
+		}
+	}
+	return false
+}
+
+// InstructorCreate representa los datos para crear un instructor
+type InstructorCreate struct {
+	Nombre         string   `json:"nombre" binding:"required"`
+	Bio            string   `json:"bio"`
+	FotoUrl        string   `json:"foto_url"`
+	Especialidades []string `json:"especialidades"`
+	UsuarioID      *uint    `json:"usuario_id"`
+	Sucursales     []uint   `json:"sucursales"`
+}
+
+// InstructorUpdate representa los datos para actualizar un instructor (reemplaza todos los campos)
+type InstructorUpdate struct {
+	Nombre         string   `json:"nombre" binding:"required"`
+	Bio            string   `json:"bio"`
+	FotoUrl        string   `json:"foto_url"`
+	Especialidades []string `json:"especialidades"`
+	UsuarioID      *uint    `json:"usuario_id"`
+	Sucursales     []uint   `json:"sucursales"`
+}
+
+// AgendaInstructor reúne las clases semanales del instructor y sus sesiones fechadas en un rango
+type AgendaInstructor struct {
+	Instructor Instructor          `json:"instructor"`
+	Desde      string              `json:"desde"`
+	Hasta      string              `json:"hasta"`
+	Clases     []ActividadResponse `json:"clases"`   // Plantillas semanales que dicta
+	Sesiones   []SesionResponse    `json:"sesiones"` // Sesiones del rango (sin las que dicta un reemplazo)
+}
@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// Estados de una entrada de la lista de espera
+const (
+	ListaEsperaEsperando  = "esperando"  // En la cola, esperando que se libere un lugar
+	ListaEsperaOfrecida   = "ofrecida"   // Se le reservó el lugar y tiene que confirmarlo antes de VenceEn
+	ListaEsperaConfirmada = "confirmada" // Aceptó el lugar
+	ListaEsperaVencida    = "vencida"    // No confirmó a tiempo y el lugar pasó al siguiente
+	ListaEsperaOmitida    = "omitida"    // Al liberarse el lugar ya no era elegible (límite semanal, sesión iniciada)
+	ListaEsperaCancelada  = "cancelada"  // La dejó el usuario o se canceló su suscripción
+)
+
+// EntradaListaEspera representa a un usuario esperando lugar en una actividad (o en una sesión puntual)
+type EntradaListaEspera struct {
+	ID            uint       `json:"id"`
+	UsuarioID     uint       `json:"usuario_id"`
+	ActividadID   uint       `json:"actividad_id"`
+	SesionID      *uint      `json:"sesion_id,omitempty"` // Nil = espera lugar en la inscripción fija semanal
+	SuscripcionID *string    `json:"suscripcion_id,omitempty"`
+	LimiteSemanal int        `json:"limite_semanal"` // Límite semanal del plan al anotarse (0 = sin límite)
+	Estado        string     `json:"estado"`
+	Posicion      int        `json:"posicion,omitempty"` // Solo para las que están esperando (1 = la próxima)
+	OfrecidaEn    *time.Time `json:"ofrecida_en,omitempty"`
+	VenceEn       *time.Time `json:"vence_en,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// Activa indica si la entrada sigue en la cola o tiene un lugar ofrecido
+func (e EntradaListaEspera) Activa() bool {
+	return e.Estado == ListaEsperaEsperando || e.Estado == ListaEsperaOfrecida
+}
+
+// EntradaListaEsperaResponse representa la entrada que ve el usuario
+type EntradaListaEsperaResponse struct {
+	ID          uint       `json:"id"`
+	ActividadID uint       `json:"actividad_id"`
+	SesionID    *uint      `json:"sesion_id,omitempty"`
+	Estado      string     `json:"estado"`
+	Posicion    int        `json:"posicion,omitempty"`
+	VenceEn     *time.Time `json:"vence_en,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ToResponse convierte de EntradaListaEspera a EntradaListaEsperaResponse
+func (e EntradaListaEspera) ToResponse() EntradaListaEsperaResponse {
+	return EntradaListaEsperaResponse{
+		ID:          e.ID,
+		ActividadID: e.ActividadID,
+		SesionID:    e.SesionID,
+		Estado:      e.Estado,
+		Posicion:    e.Posicion,
+		VenceEn:     e.VenceEn,
+		CreatedAt:   e.CreatedAt,
+	}
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Errores del repositorio de penalizaciones
+var (
+	ErrPoliticaNotFound   = errors.New("politica de cancelacion not found")
+	ErrPoliticaDuplicada  = errors.New("ya existe una política para ese alcance")
+	ErrStrikeNotFound     = errors.New("strike not found")
+	ErrStrikeYaAnulado    = errors.New("el strike ya estaba anulado")
+	ErrStrikeYaRegistrado = errors.New("el strike ya estaba registrado")
+)
+
+// Alcances de una política de cancelación (de más a menos específica)
+const (
+	AlcancePlan      = "plan"      // Valor = plan_id de subscriptions-api
+	AlcanceCategoria = "categoria" // Valor = categoría de la actividad
+	AlcanceGeneral   = "general"   // Sin valor: aplica cuando no hay otra
+)
+
+// Tipos de strike
+const (
+	StrikeCancelacionTardia = "cancelacion_tardia" // Se desinscribió dentro de la ventana de la política
+	StrikeAusente           = "ausente"            // Estaba inscripto y no hizo check-in
+)
+
+// PoliticaCancelacion define cuándo una cancelación es tardía y cuántos strikes bloquean las reservas
+type PoliticaCancelacion struct {
+	ID                 uint      `json:"id"`
+	Alcance            string    `json:"alcance"`         // plan | categoria | general
+	Valor              string    `json:"valor,omitempty"` // plan_id o categoría (vacío en la general)
+	MinutosLimite      int       `json:"minutos_limite"`  // Cancelar con menos anticipación suma un strike (0 = nunca)
+	PenalizarAusentes  bool      `json:"penalizar_ausentes"`
+	StrikesBloqueo     int       `json:"strikes_bloqueo"`      // Strikes vigentes que bloquean las reservas (0 = sin bloqueo)
+	DiasBloqueo        int       `json:"dias_bloqueo"`         // Duración del bloqueo
+	DiasVigenciaStrike int       `json:"dias_vigencia_strike"` // Los strikes más viejos no cuentan para el bloqueo
+	CreatedAt          time.Time `json:"created_at,omitempty"`
+	UpdatedAt          time.Time `json:"updated_at,omitempty"`
+}
+
+// PoliticaCancelacionCreate representa los datos para crear o reemplazar una política
+type PoliticaCancelacionCreate struct {
+	Alcance            string `json:"alcance" binding:"required,oneof=plan categoria general"`
+	Valor              string `json:"valor"`
+	MinutosLimite      int    `json:"minutos_limite" binding:"min=0"`
+	PenalizarAusentes  bool   `json:"penalizar_ausentes"`
+	StrikesBloqueo     int    `json:"strikes_bloqueo" binding:"min=0"`
+	DiasBloqueo        int    `json:"dias_bloqueo" binding:"min=0"`
+	DiasVigenciaStrike int    `json:"dias_vigencia_strike" binding:"min=0"`
+}
+
+// Strike es una cancelación tardía o una ausencia que cuenta para el bloqueo de reservas
+type Strike struct {
+	ID              uint       `json:"id"`
+	UsuarioID       uint       `json:"usuario_id"`
+	Tipo            string     `json:"tipo"` // cancelacion_tardia | ausente
+	ActividadID     uint       `json:"actividad_id"`
+	SesionID        uint       `json:"sesion_id"`
+	PoliticaID      *uint      `json:"politica_id,omitempty"`
+	BloqueoID       *uint      `json:"bloqueo_id,omitempty"` // Bloqueo que disparó (ya no cuenta para otro)
+	Anulado         bool       `json:"anulado"`
+	AnuladoPor      *uint      `json:"anulado_por,omitempty"`
+	MotivoAnulacion string     `json:"motivo_anulacion,omitempty"`
+	AnuladoEn       *time.Time `json:"anulado_en,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// StrikeAnulacion representa el pedido de un admin para anular un strike
+type StrikeAnulacion struct {
+	Motivo string `json:"motivo" binding:"required"`
+}
+
+// BloqueoReservas impide inscribirse hasta "Hasta" por acumular strikes
+type BloqueoReservas struct {
+	ID        uint      `json:"id"`
+	UsuarioID uint      `json:"usuario_id"`
+	Hasta     time.Time `json:"hasta"`
+	Levantado bool      `json:"levantado"` // Se levantó al anular uno de sus strikes
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EstadoPenalizaciones resume los strikes de un socio y su bloqueo vigente
+type EstadoPenalizaciones struct {
+	UsuarioID         uint             `json:"usuario_id"`
+	StrikesPendientes int              `json:"strikes_pendientes"` // Ni anulados ni usados en un bloqueo
+	Strikes           []Strike         `json:"strikes"`
+	Bloqueo           *BloqueoReservas `json:"bloqueo,omitempty"` // Bloqueo vigente, si tiene
+}
+
+// AusenteSinStrike es una ausencia marcada por el job que todavía no tiene su strike
+type AusenteSinStrike struct {
+	UsuarioID   uint
+	ActividadID uint
+	SesionID    uint
+	Categoria   string
+	PlanID      *string // Plan de la inscripción (nil si se inscribió antes de guardarse el plan)
+}
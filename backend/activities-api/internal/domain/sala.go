@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Errores del repositorio de salas y equipos
+var (
+	ErrSalaNotFound           = errors.New("sala not found")
+	ErrSalaDuplicada          = errors.New("ya existe una sala con ese nombre en la sucursal")
+	ErrEquipoNotFound         = errors.New("equipo not found")
+	ErrEquipoDuplicado        = errors.New("ya existe un equipo de ese tipo con ese número en la sala")
+	ErrEquipoReservado        = errors.New("el equipo ya está reservado para esta sesión")
+	ErrReservaEquipoNotFound  = errors.New("reserva de equipo not found")
+	ErrReservaEquipoExistente = errors.New("ya tenés un equipo reservado en esta sesión")
+)
+
+// Sala representa un espacio de una sucursal (estudio, sala de spinning, ...)
+// Las actividades que se dictan en ella no pueden superponerse ni superar su capacidad
+type Sala struct {
+	ID         uint      `json:"id"`
+	SucursalID uint      `json:"sucursal_id"`
+	Nombre     string    `json:"nombre"`
+	Capacidad  uint      `json:"capacidad"`
+	Equipos    []Equipo  `json:"equipos"` // Equipos reservables activos
+	Activa     bool      `json:"activa"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+}
+
+// SalaCreate representa los datos para crear una sala
+type SalaCreate struct {
+	SucursalID uint   `json:"sucursal_id" binding:"required"`
+	Nombre     string `json:"nombre" binding:"required"`
+	Capacidad  uint   `json:"capacidad" binding:"required,min=1"`
+}
+
+// SalaUpdate representa los datos para actualizar una sala (no se puede mover de sucursal)
+type SalaUpdate struct {
+	Nombre    string `json:"nombre" binding:"required"`
+	Capacidad uint   `json:"capacidad" binding:"required,min=1"`
+}
+
+// Equipo representa un equipo reservable de una sala (ej: la bicicleta 7 de la sala de spinning)
+type Equipo struct {
+	ID     uint   `json:"id"`
+	SalaID uint   `json:"sala_id"`
+	Tipo   string `json:"tipo"`   // bicicleta, reformer, ...
+	Numero uint   `json:"numero"` // Número visible en el equipo, único por tipo dentro de la sala
+	Activo bool   `json:"activo"`
+}
+
+// EquipoCreate representa los datos para agregar un equipo a una sala
+type EquipoCreate struct {
+	Tipo   string `json:"tipo" binding:"required"`
+	Numero uint   `json:"numero" binding:"required,min=1"`
+}
+
+// ReservaEquipo es la reserva de un equipo por un socio para una sesión
+type ReservaEquipo struct {
+	ID        uint      `json:"id"`
+	EquipoID  uint      `json:"equipo_id"`
+	SesionID  uint      `json:"sesion_id"`
+	UsuarioID uint      `json:"usuario_id"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// ReservaEquipoCreate representa el pedido de reserva de un equipo en una sesión
+type ReservaEquipoCreate struct {
+	EquipoID uint `json:"equipo_id" binding:"required"`
+}
+
+// EquipoSesion es un equipo de la sala de una sesión con su disponibilidad
+type EquipoSesion struct {
+	Equipo
+	Disponible bool `json:"disponible"`
+	Propio     bool `json:"propio"` // Reservado por el usuario que consulta
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Estados de una sesión
+const (
+	SesionProgramada = "programada"
+	SesionCancelada  = "cancelada"
+)
+
+// ErrSesionDuplicada se devuelve al reprogramar una sesión a un día en que la actividad ya tiene otra
+var ErrSesionDuplicada = errors.New("la actividad ya tiene una sesión ese día")
+
+// Sesion representa una ocurrencia fechada de una Actividad (ej: el spinning del martes 14)
+// Se genera a partir del día y horario de la actividad; cupo e instructor se pueden cambiar por sesión
+type Sesion struct {
+	ID                uint      `json:"id"`
+	ActividadID       uint      `json:"actividad_id"`
+	Titulo            string    `json:"titulo"`                // De la actividad (JOIN)
+	SucursalID        *uint     `json:"sucursal_id,omitempty"` // De la actividad (JOIN)
+	Fecha             string    `json:"fecha"`                 // Formato "YYYY-MM-DD"
+	HorarioInicio     string    `json:"horario_inicio"`        // Formato "HH:MM"
+	HorarioFinal      string    `json:"horario_final"`         // Formato "HH:MM"
+	Cupo              uint      `json:"cupo"`
+	Instructor        string    `json:"instructor"`
+	InstructorID      *uint     `json:"instructor_id,omitempty"`
+	Estado            string    `json:"estado"` // "programada" | "cancelada"
+	MotivoCancelacion string    `json:"motivo_cancelacion,omitempty"`
+	Personalizada     bool      `json:"personalizada"`            // Cambiada a mano: la generación no la pisa
+	FechaOriginal     string    `json:"fecha_original,omitempty"` // Fecha que le tocaba si se reprogramó
+	Ocupados          uint      `json:"ocupados"`                 // Campo calculado (fijas semanales + reservas de la sesión)
+	Lugares           uint      `json:"lugares"`                  // Campo calculado (cupos disponibles)
+	CreatedAt         time.Time `json:"created_at,omitempty"`
+	UpdatedAt         time.Time `json:"updated_at,omitempty"`
+}
+
+// Cancelada indica si la sesión fue cancelada
+func (s Sesion) Cancelada() bool {
+	return s.Estado == SesionCancelada
+}
+
+// Reprogramada indica si la sesión se movió de fecha u horario
+func (s Sesion) Reprogramada() bool {
+	return s.FechaOriginal != ""
+}
+
+// SesionUpdate representa los cambios de una sesión puntual (solo se aplican los campos enviados)
+type SesionUpdate struct {
+	Cupo              *uint   `json:"cupo,omitempty" binding:"omitempty,min=1"`
+	Instructor        *string `json:"instructor,omitempty"`    // Reemplazo del instructor solo para esta sesión
+	InstructorID      *uint   `json:"instructor_id,omitempty"` // ID en users-api del reemplazo
+	Cancelada         *bool   `json:"cancelada,omitempty"`
+	MotivoCancelacion string  `json:"motivo_cancelacion"`
+}
+
+// SesionesCancelacion representa la cancelación de las sesiones de una actividad entre dos fechas (inclusive)
+// Sin hasta se cancela solo la sesión de desde
+type SesionesCancelacion struct {
+	Desde  string `json:"desde" binding:"required"` // Formato "YYYY-MM-DD"
+	Hasta  string `json:"hasta"`                    // Formato "YYYY-MM-DD"
+	Motivo string `json:"motivo" binding:"required"`
+}
+
+// SesionReprogramacion representa el nuevo día y horario de una sesión
+type SesionReprogramacion struct {
+	Fecha         string `json:"fecha" binding:"required"`          // Formato "YYYY-MM-DD"
+	HorarioInicio string `json:"horario_inicio" binding:"required"` // Formato "HH:MM"
+	HorarioFinal  string `json:"horario_final" binding:"required"`  // Formato "HH:MM"
+	Motivo        string `json:"motivo"`
+}
+
+// CancelacionSesionesResponse resume una cancelación de sesiones
+type CancelacionSesionesResponse struct {
+	Sesiones            []SesionResponse `json:"sesiones"`             // Las que se cancelaron
+	ReservasLiberadas   int              `json:"reservas_liberadas"`   // Reservas puntuales dadas de baja
+	UsuariosNotificados int              `json:"usuarios_notificados"` // Inscriptos avisados (fijas + reservas)
+}
+
+// SesionResponse representa la respuesta HTTP de una sesión
+type SesionResponse struct {
+	ID                uint   `json:"id"`
+	ActividadID       uint   `json:"actividad_id"`
+	Titulo            string `json:"titulo"`
+	SucursalID        *uint  `json:"sucursal_id,omitempty"`
+	Fecha             string `json:"fecha"`
+	HorarioInicio     string `json:"horario_inicio"`
+	HorarioFinal      string `json:"horario_final"`
+	Cupo              uint   `json:"cupo"`
+	Instructor        string `json:"instructor"`
+	InstructorID      *uint  `json:"instructor_id,omitempty"`
+	Estado            string `json:"estado"`
+	MotivoCancelacion string `json:"motivo_cancelacion,omitempty"`
+	FechaOriginal     string `json:"fecha_original,omitempty"`
+	Lugares           uint   `json:"lugares"`
+}
+
+// ToResponse convierte de Sesion a SesionResponse
+func (s Sesion) ToResponse() SesionResponse {
+	return SesionResponse{
+		ID:                s.ID,
+		ActividadID:       s.ActividadID,
+		Titulo:            s.Titulo,
+		SucursalID:        s.SucursalID,
+		Fecha:             s.Fecha,
+		HorarioInicio:     s.HorarioInicio,
+		HorarioFinal:      s.HorarioFinal,
+		Cupo:              s.Cupo,
+		Instructor:        s.Instructor,
+		InstructorID:      s.InstructorID,
+		Estado:            s.Estado,
+		MotivoCancelacion: s.MotivoCancelacion,
+		FechaOriginal:     s.FechaOriginal,
+		Lugares:           s.Lugares,
+	}
+}
@@ -1,27 +1,62 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// ErrSucursalNotFound indica que la sucursal no existe o está dada de baja
+var ErrSucursalNotFound = errors.New("sucursal not found")
+
+// ZonaHorariaDefault es la zona de las sucursales que no informan una
+const ZonaHorariaDefault = "America/Argentina/Buenos_Aires"
 
 // Sucursal representa la entidad de negocio Sucursal
-// TODO: Para que los compañeros implementen CRUD completo
 type Sucursal struct {
-	ID        uint      `json:"id"`
-	Nombre    string    `json:"nombre"`
-	Direccion string    `json:"direccion"`
-	Telefono  string    `json:"telefono"`
-	CreatedAt time.Time `json:"created_at,omitempty"`
-	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	ID           uint              `json:"id"`
+	Nombre       string            `json:"nombre"`
+	Direccion    string            `json:"direccion"`
+	Ciudad       string            `json:"ciudad,omitempty"`
+	CodigoPostal string            `json:"codigo_postal,omitempty"`
+	Telefono     string            `json:"telefono"`
+	ZonaHoraria  string            `json:"zona_horaria"`
+	Latitud      *float64          `json:"latitud,omitempty"`
+	Longitud     *float64          `json:"longitud,omitempty"`
+	Horarios     []HorarioSucursal `json:"horarios"`
+	Activa       bool              `json:"activa"`
+	CreatedAt    time.Time         `json:"created_at,omitempty"`
+	UpdatedAt    time.Time         `json:"updated_at,omitempty"`
+}
+
+// HorarioSucursal es el rango de apertura de un día de la semana (los días sin horario la sucursal está cerrada)
+type HorarioSucursal struct {
+	Dia      string `json:"dia" binding:"required"`      // Lunes, Martes, ..., Domingo (mismo enum que las actividades)
+	Apertura string `json:"apertura" binding:"required"` // HH:MM
+	Cierre   string `json:"cierre" binding:"required"`   // HH:MM
 }
 
 // SucursalCreate representa los datos para crear una sucursal
 type SucursalCreate struct {
-	Nombre    string `json:"nombre" binding:"required"`
-	Direccion string `json:"direccion" binding:"required"`
-	Telefono  string `json:"telefono" binding:"required"`
+	Nombre       string            `json:"nombre" binding:"required"`
+	Direccion    string            `json:"direccion" binding:"required"`
+	Ciudad       string            `json:"ciudad"`
+	CodigoPostal string            `json:"codigo_postal"`
+	Telefono     string            `json:"telefono" binding:"required"`
+	ZonaHoraria  string            `json:"zona_horaria"` // Vacía = ZonaHorariaDefault
+	Latitud      *float64          `json:"latitud"`
+	Longitud     *float64          `json:"longitud"`
+	Horarios     []HorarioSucursal `json:"horarios" binding:"dive"`
 }
 
-// TODO: Los compañeros deben implementar:
-// - Repository (internal/repository/sucursales_mysql.go)
-// - Service (internal/services/sucursales.go)
-// - Controller (internal/controllers/sucursales.go)
-// - Endpoints en main.go
+// SucursalUpdate representa los datos para actualizar una sucursal (reemplaza todos los campos y el horario semanal)
+type SucursalUpdate struct {
+	Nombre       string            `json:"nombre" binding:"required"`
+	Direccion    string            `json:"direccion" binding:"required"`
+	Ciudad       string            `json:"ciudad"`
+	CodigoPostal string            `json:"codigo_postal"`
+	Telefono     string            `json:"telefono" binding:"required"`
+	ZonaHoraria  string            `json:"zona_horaria"`
+	Latitud      *float64          `json:"latitud"`
+	Longitud     *float64          `json:"longitud"`
+	Horarios     []HorarioSucursal `json:"horarios" binding:"dive"`
+}
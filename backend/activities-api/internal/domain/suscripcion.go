@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Errores del repositorio de suscripciones (copia local de subscriptions-api)
+var (
+	ErrSuscripcionNotFound = errors.New("suscripcion not found")
+	ErrPlanNotFound        = errors.New("plan not found")
+)
+
+// Estados de una suscripción
+const (
+	SuscripcionActiva = "activa" // El resto (pendiente_pago, cancelada, expirada) no habilita a inscribirse
+	// SuscripcionInactiva la marca la sincronización cuando subscriptions-api ya no la lista como activa
+	SuscripcionInactiva = "inactiva"
+)
+
+// Suscripcion es la copia local de una suscripción de subscriptions-api
+// Se mantiene con los eventos subscription.* y la sincronización; EventoEn descarta los eventos viejos
+type Suscripcion struct {
+	ID               string     `json:"id"` // ObjectID de subscriptions-api
+	UsuarioID        uint       `json:"usuario_id"`
+	PlanID           string     `json:"plan_id"`
+	Estado           string     `json:"estado"`
+	FechaInicio      *time.Time `json:"fecha_inicio,omitempty"`
+	FechaVencimiento *time.Time `json:"fecha_vencimiento,omitempty"`
+	EventoEn         time.Time  `json:"evento_en"` // Momento del último evento aplicado
+}
+
+// Vigente indica si la suscripción habilita a inscribirse en el momento indicado
+func (s Suscripcion) Vigente(now time.Time) bool {
+	if s.Estado != SuscripcionActiva {
+		return false
+	}
+	return s.FechaVencimiento == nil || s.FechaVencimiento.After(now)
+}
+
+// Plan es la copia local de las condiciones de un plan de subscriptions-api (eventos plan.*)
+type Plan struct {
+	ID                    string    `json:"id"`
+	Nombre                string    `json:"nombre"`
+	TipoAcceso            string    `json:"tipo_acceso"` // limitado | completo
+	ActividadesPermitidas []string  `json:"actividades_permitidas"`
+	ActividadesPorSemana  int       `json:"actividades_por_semana"` // 0 = ilimitado
+	CupoClases            int       `json:"cupo_clases"`            // Clases por período (0 = usa ActividadesPorSemana)
+	CupoPeriodo           string    `json:"cupo_periodo"`           // diario | semanal | mensual
+	Activo                bool      `json:"activo"`
+	EventoEn              time.Time `json:"evento_en"`
+}
+
+// ResultadoSincronizacion resume una sincronización con subscriptions-api
+type ResultadoSincronizacion struct {
+	Planes        int   `json:"planes"`
+	Suscripciones int   `json:"suscripciones"`
+	Desactivadas  int64 `json:"desactivadas"` // Activas acá que subscriptions-api ya no lista
+}
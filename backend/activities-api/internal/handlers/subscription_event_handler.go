@@ -5,20 +5,52 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 )
 
-// SubscriptionEventHandler maneja eventos de suscripciones
+// SubscriptionEventHandler maneja eventos de suscripciones y planes
 type SubscriptionEventHandler struct {
 	inscripcionesService services.InscripcionesService
+	suscripcionesService services.SuscripcionesService
+	cuposService         services.CuposService
 }
 
 // NewSubscriptionEventHandler crea un nuevo handler de eventos de suscripciones
-func NewSubscriptionEventHandler(inscripcionesService services.InscripcionesService) *SubscriptionEventHandler {
+func NewSubscriptionEventHandler(inscripcionesService services.InscripcionesService, suscripcionesService services.SuscripcionesService, cuposService services.CuposService) *SubscriptionEventHandler {
 	return &SubscriptionEventHandler{
 		inscripcionesService: inscripcionesService,
+		suscripcionesService: suscripcionesService,
+		cuposService:         cuposService,
 	}
 }
 
+// HandleSubscriptionEvent aplica un evento subscription.* a la copia local de suscripciones
+// Un reembolso además reintegra al cupo las clases desde hoy descontadas con la suscripción
+func (h *SubscriptionEventHandler) HandleSubscriptionEvent(ctx context.Context, action, subscriptionID string, occurredAt time.Time, data map[string]interface{}) error {
+	if err := h.suscripcionesService.AplicarEventoSuscripcion(ctx, action, subscriptionID, occurredAt, data); err != nil {
+		return fmt.Errorf("error aplicando subscription.%s a %s: %w", action, subscriptionID, err)
+	}
+
+	if action == "cancelled_by_refund" {
+		if _, err := h.cuposService.ReintegrarSuscripcion(ctx, subscriptionID); err != nil {
+			return fmt.Errorf("error reintegrando el cupo de la suscripción %s: %w", subscriptionID, err)
+		}
+	}
+
+	log.Printf("📇 [SubscriptionEventHandler] subscription.%s aplicado a la suscripción %s\n", action, subscriptionID)
+	return nil
+}
+
+// HandlePlanEvent aplica un evento plan.* a la copia local de planes
+func (h *SubscriptionEventHandler) HandlePlanEvent(ctx context.Context, action, planID string, occurredAt time.Time, data map[string]interface{}) error {
+	if err := h.suscripcionesService.AplicarEventoPlan(ctx, action, planID, occurredAt, data); err != nil {
+		return fmt.Errorf("error aplicando plan.%s a %s: %w", action, planID, err)
+	}
+
+	log.Printf("📇 [SubscriptionEventHandler] plan.%s aplicado al plan %s\n", action, planID)
+	return nil
+}
+
 // HandleSubscriptionCancelled maneja el evento de cancelación de suscripción
 func (h *SubscriptionEventHandler) HandleSubscriptionCancelled(ctx context.Context, usuarioID uint) error {
 	log.Printf("🔔 [SubscriptionEventHandler] Suscripción cancelada para usuario %d - Desinscribiendo de actividades...\n", usuarioID)
@@ -1,22 +1,22 @@
 package middleware
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
+	"github.com/yourusername/gym-management/shared/auth"
 )
 
 // JWTAuthMiddleware valida el token JWT en el header Authorization
 // Nota: Este middleware NO valida si el usuario existe en la BD (eso lo hace users-api)
-// Solo valida que el token sea válido y extrae los claims
-func JWTAuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// La validación (firma con el JWKS de users-api, revocación por jti y contrato de claims) la hace shared/auth
+func JWTAuthMiddleware(verifier *auth.Verifier) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// Obtener header Authorization
-		auth := ctx.GetHeader("Authorization")
-		if auth == "" {
+		authHeader := ctx.GetHeader("Authorization")
+		if authHeader == "" {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"error": "Authorization header required",
 			})
@@ -24,7 +24,7 @@ func JWTAuthMiddleware(jwtSecret string) gin.HandlerFunc {
 		}
 
 		// Validar formato "Bearer <token>"
-		parts := strings.SplitN(auth, " ", 2)
+		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || parts[0] != "Bearer" {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"error": "Invalid authorization header format. Expected 'Bearer <token>'",
@@ -32,57 +32,69 @@ func JWTAuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		// Parsear y validar token
-		token, err := jwt.Parse(parts[1], func(token *jwt.Token) (interface{}, error) {
-			// Validar el algoritmo
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
+		// Validar token
+		claims, err := verifier.Verify(parts[1])
+		if errors.Is(err, auth.ErrTokenRevoked) {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error":   "Invalid or expired token",
-				"details": err.Error(),
+				"error": "Token has been revoked",
 			})
 			return
 		}
-
-		// Extraer claims
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
+		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid token claims",
+				"error":   "Invalid or expired token",
+				"details": err.Error(),
 			})
 			return
 		}
 
-		// Guardar claims en contexto
-		idUser, _ := claims["id_usuario"].(float64)
-		isAdmin, _ := claims["is_admin"].(bool)
-		username, _ := claims["username"].(string)
+		// Guardar claims en contexto (el Verifier ya validó que sub es un ID válido)
+		idUser, _ := claims.UserID()
 
-		ctx.Set("id_usuario", uint(idUser))
-		ctx.Set("is_admin", isAdmin)
-		ctx.Set("username", username)
+		ctx.Set("id_usuario", idUser)
+		ctx.Set("is_admin", claims.IsAdmin())
+		ctx.Set("roles", claims.Roles)
+		ctx.Set("branch_ids", claims.BranchIDs)
+		ctx.Set("claims", claims)
 
 		ctx.Next()
 	}
 }
 
-// AdminOnlyMiddleware verifica que el usuario sea administrador
+// RequirePermission verifica que el usuario tenga alguno de los permisos (en al menos una sucursal)
+// El alcance por sucursal lo valida cada handler con ClaimsFromContext y CanInBranch
 // Debe usarse DESPUÉS de JWTAuthMiddleware
-func AdminOnlyMiddleware() gin.HandlerFunc {
+func RequirePermission(perms ...auth.Permission) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		isAdmin, exists := ctx.Get("is_admin")
-		if !exists || !isAdmin.(bool) {
-			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-				"error": "You don't have permission to perform this action. Admin access required.",
-			})
-			return
+		claims, ok := ClaimsFromContext(ctx)
+		if ok {
+			for _, perm := range perms {
+				if claims.Can(perm) {
+					ctx.Next()
+					return
+				}
+			}
 		}
 
-		ctx.Next()
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error": "You don't have permission to perform this action.",
+		})
+	}
+}
+
+// AdminOnlyMiddleware verifica que el usuario sea administrador (owner)
+// Se mantiene por compatibilidad: equivale a RequirePermission(auth.PermAdminAccess)
+// Debe usarse DESPUÉS de JWTAuthMiddleware
+func AdminOnlyMiddleware() gin.HandlerFunc {
+	return RequirePermission(auth.PermAdminAccess)
+}
+
+// ClaimsFromContext devuelve los claims que guardó JWTAuthMiddleware
+func ClaimsFromContext(ctx *gin.Context) (*auth.Claims, bool) {
+	value, exists := ctx.Get("claims")
+	if !exists {
+		return nil, false
 	}
+	claims, ok := value.(*auth.Claims)
+	return claims, ok
 }
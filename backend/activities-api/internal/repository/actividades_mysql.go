@@ -24,6 +24,8 @@ type ActividadesRepository interface {
 	Create(ctx context.Context, actividad domain.Actividad, horaInicio, horaFin time.Time) (domain.Actividad, error)
 	Update(ctx context.Context, id uint, actividad domain.Actividad, horaInicio, horaFin time.Time) (domain.Actividad, error)
 	Delete(ctx context.Context, id uint) error
+	// Importar guarda altas y modificaciones de la planilla de horarios en una sola transacción
+	Importar(ctx context.Context, cambios []domain.ActividadImportada) ([]domain.Actividad, error)
 	// InvalidateCache permite forzar la recarga de actividades
 	// (por ejemplo, después de crear/cancelar inscripciones que afectan los cupos)
 	InvalidateCache()
@@ -91,6 +93,8 @@ func NewMySQLActividadesRepository(cfg config.MySQLConfig) *MySQLActividadesRepo
 	// }
 
 	// Crear vista actividades_lugares si no existe (incluye JOIN con sucursales)
+	// Los promedios cuentan solo las calificaciones visibles (BDD/21-migrate-class-ratings.sql)
+	// Solo las inscripciones fijas ocupan lugar en la actividad; las reservas ocupan lugar en su sesión
 	createViewSQL := `
 		CREATE OR REPLACE VIEW actividades_lugares AS
 		SELECT a.*,
@@ -98,7 +102,16 @@ func NewMySQLActividadesRepository(cfg config.MySQLConfig) *MySQLActividadesRepo
 		       a.cupo - COALESCE((SELECT COUNT(*)
 		                          FROM inscripciones i
 		                          WHERE i.actividad_id = a.id_actividad
-		                          AND i.is_activa = true), 0) AS lugares
+		                          AND i.sesion_id IS NULL
+		                          AND i.is_activa = true), 0) AS lugares,
+		       COALESCE((SELECT ROUND(AVG(c.puntaje), 2) FROM calificaciones c
+		                 WHERE c.actividad_id = a.id_actividad AND c.oculta = false), 0) AS calificacion_promedio,
+		       (SELECT COUNT(*) FROM calificaciones c
+		        WHERE c.actividad_id = a.id_actividad AND c.oculta = false) AS calificaciones,
+		       COALESCE((SELECT ROUND(AVG(c.puntaje), 2) FROM calificaciones c
+		                 WHERE c.instructor_perfil_id = a.instructor_perfil_id AND c.oculta = false), 0) AS instructor_calificacion_promedio,
+		       (SELECT COUNT(*) FROM calificaciones c
+		        WHERE c.instructor_perfil_id = a.instructor_perfil_id AND c.oculta = false) AS instructor_calificaciones
 		FROM actividades a
 		LEFT JOIN sucursales s ON a.sucursal_id = s.id_sucursal
 		WHERE a.activa = true AND a.deleted_at IS NULL
@@ -164,9 +177,14 @@ func (r *MySQLActividadesRepository) InvalidateCache() {
 
 // GetByID obtiene una actividad por ID (usando la vista)
 func (r *MySQLActividadesRepository) GetByID(ctx context.Context, id uint) (domain.Actividad, error) {
+	return getActividad(r.db.WithContext(ctx), id)
+}
+
+// getActividad lee la actividad de la vista con la conexión o transacción dada
+func getActividad(db *gorm.DB, id uint) (domain.Actividad, error) {
 	var actividadDAO dao.ActividadVista
 
-	err := r.db.WithContext(ctx).Where("id_actividad = ?", id).First(&actividadDAO).Error
+	err := db.Where("id_actividad = ?", id).First(&actividadDAO).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return domain.Actividad{}, errors.New("actividad not found")
@@ -212,40 +230,109 @@ func (r *MySQLActividadesRepository) GetByParams(ctx context.Context, params map
 
 // Create inserta una nueva actividad
 func (r *MySQLActividadesRepository) Create(ctx context.Context, actividad domain.Actividad, horaInicio, horaFin time.Time) (domain.Actividad, error) {
+	creada, err := createActividad(r.db.WithContext(ctx), actividad, horaInicio, horaFin)
+	if err != nil {
+		return domain.Actividad{}, err
+	}
+
+	// Invalidar cache después de crear
+	r.invalidateCache()
+
+	return creada, nil
+}
+
+// createActividad inserta la actividad con la conexión o transacción dada
+func createActividad(db *gorm.DB, actividad domain.Actividad, horaInicio, horaFin time.Time) (domain.Actividad, error) {
 	actividadDAO := dao.ActividadFromDomain(actividad, horaInicio, horaFin)
 	actividadDAO.CreatedAt = time.Now()
 	actividadDAO.UpdatedAt = time.Now()
 
-	if err := r.db.WithContext(ctx).Create(&actividadDAO).Error; err != nil {
+	if err := db.Create(&actividadDAO).Error; err != nil {
 		return domain.Actividad{}, fmt.Errorf("error creating actividad: %w", err)
 	}
 
-	// Invalidar cache después de crear
-	r.invalidateCache()
-
 	return actividadDAO.ToDomain(), nil
 }
 
 // Update actualiza una actividad existente
 func (r *MySQLActividadesRepository) Update(ctx context.Context, id uint, actividad domain.Actividad, horaInicio, horaFin time.Time) (domain.Actividad, error) {
+	if err := updateActividad(r.db.WithContext(ctx), id, actividad, horaInicio, horaFin); err != nil {
+		return domain.Actividad{}, err
+	}
+
+	// Invalidar cache después de actualizar
+	r.invalidateCache()
+
+	// Obtener la actividad actualizada
+	return r.GetByID(ctx, id)
+}
+
+// updateActividad actualiza la actividad con la conexión o transacción dada
+func updateActividad(db *gorm.DB, id uint, actividad domain.Actividad, horaInicio, horaFin time.Time) error {
 	actividadDAO := dao.ActividadFromDomain(actividad, horaInicio, horaFin)
 	actividadDAO.ID = id
 	actividadDAO.UpdatedAt = time.Now()
 
 	// GORM ejecutará el hook BeforeUpdate que valida cupos
-	result := r.db.WithContext(ctx).Model(&dao.Actividad{ID: id, Cupo: actividadDAO.Cupo}).Updates(&actividadDAO)
+	// Select explícito: sucursal, sala e instructores en nil también se guardan (quitan la asignación)
+	result := db.Model(&dao.Actividad{ID: id, Cupo: actividadDAO.Cupo}).
+		Select("titulo", "descripcion", "cupo", "dia", "horario_inicio", "horario_final", "foto_url",
+			"instructor", "instructor_id", "instructor_perfil_id", "categoria", "sucursal_id", "sala_id", "updated_at").
+		Updates(&actividadDAO)
 	if result.Error != nil {
-		return domain.Actividad{}, fmt.Errorf("error updating actividad: %w", result.Error)
+		return fmt.Errorf("error updating actividad: %w", result.Error)
 	}
 	if result.RowsAffected == 0 {
-		return domain.Actividad{}, errors.New("actividad not found")
+		return errors.New("actividad not found")
 	}
 
-	// Invalidar cache después de actualizar
+	// Si la clase cambió de sala, las reservas de equipos de la sala anterior dejan de valer
+	err := db.
+		Where("sesion_id IN (SELECT id_sesion FROM sesiones WHERE actividad_id = ? AND fecha >= CURDATE())", id).
+		Where("equipo_id NOT IN (SELECT id_equipo FROM equipos WHERE sala_id <=> ?)", actividadDAO.SalaID).
+		Delete(&dao.ReservaEquipo{}).Error
+	if err != nil {
+		return fmt.Errorf("error deleting reservas de equipos: %w", err)
+	}
+
+	return nil
+}
+
+// Importar aplica las filas válidas de una planilla de horarios: si alguna falla no se guarda ninguna
+// Devuelve las actividades guardadas en el mismo orden que los cambios
+func (r *MySQLActividadesRepository) Importar(ctx context.Context, cambios []domain.ActividadImportada) ([]domain.Actividad, error) {
+	guardadas := make([]domain.Actividad, 0, len(cambios))
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, cambio := range cambios {
+			if cambio.ID == 0 {
+				creada, err := createActividad(tx, cambio.Actividad, cambio.HoraInicio, cambio.HoraFin)
+				if err != nil {
+					return fmt.Errorf("fila %d: %w", cambio.Fila, err)
+				}
+				guardadas = append(guardadas, creada)
+				continue
+			}
+
+			if err := updateActividad(tx, cambio.ID, cambio.Actividad, cambio.HoraInicio, cambio.HoraFin); err != nil {
+				return fmt.Errorf("fila %d: %w", cambio.Fila, err)
+			}
+			actualizada, err := getActividad(tx, cambio.ID)
+			if err != nil {
+				return fmt.Errorf("fila %d: %w", cambio.Fila, err)
+			}
+			guardadas = append(guardadas, actualizada)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidar cache después de importar
 	r.invalidateCache()
 
-	// Obtener la actividad actualizada
-	return r.GetByID(ctx, id)
+	return guardadas, nil
 }
 
 // Delete elimina una actividad
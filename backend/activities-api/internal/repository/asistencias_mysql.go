@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"activities-api/internal/dao"
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// marcarAusentesSQL inserta una ausencia por cada inscripto sin check-in en las sesiones programadas del rango
+// Cuentan las inscripciones activas hechas antes de que empiece la sesión (fijas de la actividad o reservas de la sesión)
+// INSERT IGNORE + la clave única (usuario_id, sesion_clave, fecha) saltean a los presentes y a los ya marcados
+const marcarAusentesSQL = `INSERT IGNORE INTO asistencias (usuario_id, actividad_id, sesion_id, sucursal_id, tipo, estado, fecha)
+	SELECT i.usuario_id, s.actividad_id, s.id_sesion, a.sucursal_id, 'clase', 'ausente', s.fecha
+	FROM sesiones s
+	JOIN actividades a ON a.id_actividad = s.actividad_id
+	JOIN inscripciones i ON i.actividad_id = s.actividad_id
+		AND (i.sesion_id IS NULL OR i.sesion_id = s.id_sesion)
+		AND i.is_activa = TRUE
+		AND i.deleted_at IS NULL
+		AND i.fecha_inscripcion < TIMESTAMP(s.fecha, TIME(s.horario_inicio))
+	WHERE s.estado = 'programada' AND s.fecha >= ? AND s.fecha < ?`
+
+// AsistenciasRepository define la interfaz del repositorio de asistencias
+type AsistenciasRepository interface {
+	// Create registra la asistencia; devuelve domain.ErrAsistenciaYaRegistrada si ya estaba
+	Create(ctx context.Context, asistencia domain.Asistencia) (domain.Asistencia, error)
+	// ListByUser lista las últimas asistencias del usuario, primero las más recientes
+	ListByUser(ctx context.Context, usuarioID uint, limit int) ([]domain.Asistencia, error)
+	// ResumenPorSesion cuenta presentes y ausentes de cada sesión de la actividad entre dos fechas (inclusive)
+	ResumenPorSesion(ctx context.Context, actividadID uint, desde, hasta time.Time) ([]domain.AsistenciaSesion, error)
+	// MarcarAusentes marca como ausentes a los inscriptos sin check-in en las sesiones de [desde, hasta)
+	MarcarAusentes(ctx context.Context, desde, hasta time.Time) (int64, error)
+}
+
+// MySQLAsistenciasRepository implementa AsistenciasRepository usando MySQL/GORM
+type MySQLAsistenciasRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLAsistenciasRepository crea una nueva instancia del repository
+// Comparte la conexión DB con ActividadesRepository (tabla en BDD/02-init-activities.sql)
+func NewMySQLAsistenciasRepository(db *gorm.DB) *MySQLAsistenciasRepository {
+	return &MySQLAsistenciasRepository{
+		db: db,
+	}
+}
+
+// Create inserta la asistencia; la clave única resuelve dos escaneos simultáneos del mismo QR
+func (r *MySQLAsistenciasRepository) Create(ctx context.Context, asistencia domain.Asistencia) (domain.Asistencia, error) {
+	fecha, err := time.ParseInLocation("2006-01-02", asistencia.Fecha, time.Local)
+	if err != nil {
+		return domain.Asistencia{}, fmt.Errorf("fecha de asistencia inválida: %w", err)
+	}
+
+	asistenciaDAO := dao.AsistenciaFromDomain(asistencia, fecha)
+	if err := r.db.WithContext(ctx).Create(&asistenciaDAO).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "Duplicate entry") {
+			return domain.Asistencia{}, domain.ErrAsistenciaYaRegistrada
+		}
+		return domain.Asistencia{}, fmt.Errorf("error creating asistencia: %w", err)
+	}
+
+	return asistenciaDAO.ToDomain(), nil
+}
+
+// ListByUser obtiene las últimas asistencias del usuario
+func (r *MySQLAsistenciasRepository) ListByUser(ctx context.Context, usuarioID uint, limit int) ([]domain.Asistencia, error) {
+	var asistenciasDAO []dao.Asistencia
+
+	err := r.db.WithContext(ctx).
+		Where("usuario_id = ?", usuarioID).
+		Order("fecha DESC, id_asistencia DESC").
+		Limit(limit).
+		Find(&asistenciasDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing asistencias: %w", err)
+	}
+
+	asistencias := make([]domain.Asistencia, len(asistenciasDAO))
+	for i, asistenciaDAO := range asistenciasDAO {
+		asistencias[i] = asistenciaDAO.ToDomain()
+	}
+
+	return asistencias, nil
+}
+
+// resumenSesion es una fila de ResumenPorSesion
+type resumenSesion struct {
+	SesionID      uint      `gorm:"column:id_sesion"`
+	Fecha         time.Time `gorm:"column:fecha"`
+	HorarioInicio time.Time `gorm:"column:horario_inicio"`
+	Estado        string    `gorm:"column:estado"`
+	Presentes     int       `gorm:"column:presentes"`
+	Ausentes      int       `gorm:"column:ausentes"`
+}
+
+// ResumenPorSesion agrupa las asistencias por sesión (las sesiones sin registros salen en cero)
+func (r *MySQLAsistenciasRepository) ResumenPorSesion(ctx context.Context, actividadID uint, desde, hasta time.Time) ([]domain.AsistenciaSesion, error) {
+	var filas []resumenSesion
+
+	err := r.db.WithContext(ctx).
+		Table("sesiones s").
+		Select(`s.id_sesion, s.fecha, s.horario_inicio, s.estado,
+			COALESCE(SUM(a.estado = 'presente'), 0) AS presentes,
+			COALESCE(SUM(a.estado = 'ausente'), 0) AS ausentes`).
+		Joins("LEFT JOIN asistencias a ON a.sesion_id = s.id_sesion").
+		Where("s.actividad_id = ?", actividadID).
+		Where("s.fecha BETWEEN ? AND ?", desde.Format("2006-01-02"), hasta.Format("2006-01-02")).
+		Group("s.id_sesion, s.fecha, s.horario_inicio, s.estado").
+		Order("s.fecha ASC").
+		Scan(&filas).Error
+	if err != nil {
+		return nil, fmt.Errorf("error summarizing asistencias: %w", err)
+	}
+
+	resumen := make([]domain.AsistenciaSesion, len(filas))
+	for i, fila := range filas {
+		resumen[i] = domain.AsistenciaSesion{
+			SesionID:      fila.SesionID,
+			Fecha:         fila.Fecha.Format("2006-01-02"),
+			HorarioInicio: fila.HorarioInicio.Format("15:04"),
+			Estado:        fila.Estado,
+			Presentes:     fila.Presentes,
+			Ausentes:      fila.Ausentes,
+		}
+	}
+
+	return resumen, nil
+}
+
+// MarcarAusentes corre marcarAusentesSQL sobre las sesiones con fecha en [desde, hasta)
+func (r *MySQLAsistenciasRepository) MarcarAusentes(ctx context.Context, desde, hasta time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(marcarAusentesSQL, desde.Format("2006-01-02"), hasta.Format("2006-01-02"))
+	if result.Error != nil {
+		return 0, fmt.Errorf("error marking ausentes: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"activities-api/internal/dao"
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CalendarioRepository define la interfaz del repositorio de tokens de los calendarios iCalendar
+type CalendarioRepository interface {
+	// GetToken devuelve el token del usuario o "" si todavía no tiene
+	GetToken(ctx context.Context, usuarioID uint) (string, error)
+	// CreateToken guarda el token si el usuario no tenía uno y devuelve el que quedó guardado
+	// (si dos pedidos crean el token a la vez, los dos reciben el mismo)
+	CreateToken(ctx context.Context, usuarioID uint, token string) (string, error)
+	// ReplaceToken reemplaza el token del usuario (el anterior deja de valer)
+	ReplaceToken(ctx context.Context, usuarioID uint, token string) error
+	// GetUsuarioByToken devuelve domain.ErrCalendarioTokenInvalido si ningún usuario tiene ese token
+	GetUsuarioByToken(ctx context.Context, token string) (uint, error)
+}
+
+// MySQLCalendarioRepository implementa CalendarioRepository usando MySQL/GORM
+type MySQLCalendarioRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLCalendarioRepository crea una nueva instancia del repository
+// Comparte la conexión DB con ActividadesRepository (tabla en BDD/02-init-activities.sql)
+func NewMySQLCalendarioRepository(db *gorm.DB) *MySQLCalendarioRepository {
+	return &MySQLCalendarioRepository{
+		db: db,
+	}
+}
+
+// GetToken obtiene el token de calendario del usuario
+func (r *MySQLCalendarioRepository) GetToken(ctx context.Context, usuarioID uint) (string, error) {
+	var tokenDAO dao.CalendarioToken
+
+	err := r.db.WithContext(ctx).Where("usuario_id = ?", usuarioID).First(&tokenDAO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("error getting calendario token: %w", err)
+	}
+
+	return tokenDAO.Token, nil
+}
+
+// CreateToken inserta el token si el usuario no tiene uno
+func (r *MySQLCalendarioRepository) CreateToken(ctx context.Context, usuarioID uint, token string) (string, error) {
+	tokenDAO := dao.CalendarioToken{UsuarioID: usuarioID, Token: token}
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&tokenDAO).Error
+	if err != nil {
+		return "", fmt.Errorf("error creating calendario token: %w", err)
+	}
+
+	return r.GetToken(ctx, usuarioID)
+}
+
+// ReplaceToken crea o reemplaza el token del usuario
+func (r *MySQLCalendarioRepository) ReplaceToken(ctx context.Context, usuarioID uint, token string) error {
+	tokenDAO := dao.CalendarioToken{UsuarioID: usuarioID, Token: token}
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"token", "updated_at"})}).
+		Create(&tokenDAO).Error
+	if err != nil {
+		return fmt.Errorf("error replacing calendario token: %w", err)
+	}
+
+	return nil
+}
+
+// GetUsuarioByToken obtiene el usuario dueño del token
+func (r *MySQLCalendarioRepository) GetUsuarioByToken(ctx context.Context, token string) (uint, error) {
+	var tokenDAO dao.CalendarioToken
+
+	err := r.db.WithContext(ctx).Where("token = ?", token).First(&tokenDAO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, domain.ErrCalendarioTokenInvalido
+		}
+		return 0, fmt.Errorf("error getting calendario token: %w", err)
+	}
+
+	return tokenDAO.UsuarioID, nil
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"activities-api/internal/dao"
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CalificacionesRepository define la interfaz del repositorio de calificaciones de clases
+type CalificacionesRepository interface {
+	// Create devuelve domain.ErrCalificacionDuplicada si el socio ya calificó la sesión
+	Create(ctx context.Context, calificacion domain.Calificacion) (domain.Calificacion, error)
+	GetByID(ctx context.Context, id uint) (domain.Calificacion, error)
+	// List obtiene las calificaciones del filtro, primero las más recientes
+	List(ctx context.Context, filtro domain.FiltroCalificaciones) ([]domain.Calificacion, error)
+	// Resumen calcula promedio y cantidad de las calificaciones visibles del filtro (ignora Oculta y Limit)
+	Resumen(ctx context.Context, filtro domain.FiltroCalificaciones) (domain.ResumenCalificaciones, error)
+	// Moderar guarda oculta/marcada y su motivo; campo es "oculta" o "marcada"
+	Moderar(ctx context.Context, id uint, campo string, valor bool, motivo string, adminID uint, now time.Time) (domain.Calificacion, error)
+	// Participo indica si el socio asistió a la sesión o tenía una inscripción vigente (fija o a la sesión)
+	// cuando empezó la clase y no la dio de baja antes de que terminara
+	Participo(ctx context.Context, usuarioID uint, sesion domain.Sesion, inicio, fin time.Time) (bool, error)
+}
+
+// MySQLCalificacionesRepository implementa CalificacionesRepository usando MySQL/GORM
+type MySQLCalificacionesRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLCalificacionesRepository crea una nueva instancia del repository
+// Comparte la conexión DB con ActividadesRepository (tablas en BDD/02-init-activities.sql)
+func NewMySQLCalificacionesRepository(db *gorm.DB) *MySQLCalificacionesRepository {
+	return &MySQLCalificacionesRepository{
+		db: db,
+	}
+}
+
+// Create inserta la calificación
+func (r *MySQLCalificacionesRepository) Create(ctx context.Context, calificacion domain.Calificacion) (domain.Calificacion, error) {
+	calificacionDAO := dao.CalificacionFromDomain(calificacion)
+
+	if err := r.db.WithContext(ctx).Create(&calificacionDAO).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "Duplicate entry") {
+			return domain.Calificacion{}, domain.ErrCalificacionDuplicada
+		}
+		return domain.Calificacion{}, fmt.Errorf("error creating calificacion: %w", err)
+	}
+
+	return r.GetByID(ctx, calificacionDAO.ID)
+}
+
+// GetByID obtiene una calificación (con la fecha de su sesión)
+func (r *MySQLCalificacionesRepository) GetByID(ctx context.Context, id uint) (domain.Calificacion, error) {
+	var calificacionDAO dao.Calificacion
+
+	err := r.conSesion(ctx).
+		Where("calificaciones.id_calificacion = ?", id).
+		First(&calificacionDAO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Calificacion{}, domain.ErrCalificacionNotFound
+		}
+		return domain.Calificacion{}, fmt.Errorf("error getting calificacion: %w", err)
+	}
+
+	return calificacionDAO.ToDomain(), nil
+}
+
+// List obtiene las calificaciones del filtro
+func (r *MySQLCalificacionesRepository) List(ctx context.Context, filtro domain.FiltroCalificaciones) ([]domain.Calificacion, error) {
+	var calificacionesDAO []dao.Calificacion
+
+	query := filtrarCalificaciones(r.conSesion(ctx), filtro)
+	if filtro.Oculta != nil {
+		query = query.Where("calificaciones.oculta = ?", *filtro.Oculta)
+	}
+	if filtro.Limit > 0 {
+		query = query.Limit(filtro.Limit)
+	}
+
+	err := query.
+		Order("calificaciones.created_at DESC, calificaciones.id_calificacion DESC").
+		Find(&calificacionesDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing calificaciones: %w", err)
+	}
+
+	calificaciones := make([]domain.Calificacion, len(calificacionesDAO))
+	for i, calificacionDAO := range calificacionesDAO {
+		calificaciones[i] = calificacionDAO.ToDomain()
+	}
+
+	return calificaciones, nil
+}
+
+// Resumen calcula el promedio (redondeado a 2 decimales, como la vista actividades_lugares) y la cantidad
+func (r *MySQLCalificacionesRepository) Resumen(ctx context.Context, filtro domain.FiltroCalificaciones) (domain.ResumenCalificaciones, error) {
+	var fila struct {
+		Promedio float64
+		Cantidad int
+	}
+
+	err := filtrarCalificaciones(r.db.WithContext(ctx).Model(&dao.Calificacion{}), filtro).
+		Select("COALESCE(ROUND(AVG(calificaciones.puntaje), 2), 0) AS promedio, COUNT(*) AS cantidad").
+		Where("calificaciones.oculta = ?", false).
+		Scan(&fila).Error
+	if err != nil {
+		return domain.ResumenCalificaciones{}, fmt.Errorf("error calculando resumen de calificaciones: %w", err)
+	}
+
+	return domain.ResumenCalificaciones{Promedio: fila.Promedio, Cantidad: fila.Cantidad}, nil
+}
+
+// Moderar oculta/muestra o marca/desmarca la calificación y registra quién y cuándo
+func (r *MySQLCalificacionesRepository) Moderar(ctx context.Context, id uint, campo string, valor bool, motivo string, adminID uint, now time.Time) (domain.Calificacion, error) {
+	columnaMotivo := map[string]string{
+		"oculta":  "motivo_ocultamiento",
+		"marcada": "motivo_marca",
+	}[campo]
+	if columnaMotivo == "" {
+		return domain.Calificacion{}, fmt.Errorf("campo de moderación inválido: %s", campo)
+	}
+
+	// Al deshacer la moderación el motivo anterior ya no aplica
+	var motivoGuardado interface{}
+	if valor && motivo != "" {
+		motivoGuardado = motivo
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&dao.Calificacion{}).
+		Where("id_calificacion = ?", id).
+		Updates(map[string]interface{}{
+			campo:          valor,
+			columnaMotivo:  motivoGuardado,
+			"moderada_por": adminID,
+			"moderada_en":  now,
+		})
+	if result.Error != nil {
+		return domain.Calificacion{}, fmt.Errorf("error moderando calificacion: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.Calificacion{}, domain.ErrCalificacionNotFound
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Participo busca una asistencia presente a la sesión o una inscripción que la cubría
+// Una inscripción dada de baja cuenta si se canceló después de que terminó la clase
+func (r *MySQLCalificacionesRepository) Participo(ctx context.Context, usuarioID uint, sesion domain.Sesion, inicio, fin time.Time) (bool, error) {
+	db := r.db.WithContext(ctx)
+
+	var asistencias int64
+	err := db.Model(&dao.Asistencia{}).
+		Where("usuario_id = ? AND sesion_id = ? AND estado = ?", usuarioID, sesion.ID, domain.AsistenciaPresente).
+		Count(&asistencias).Error
+	if err != nil {
+		return false, fmt.Errorf("error verificando asistencia: %w", err)
+	}
+	if asistencias > 0 {
+		return true, nil
+	}
+
+	var inscripciones int64
+	err = db.Model(&dao.Inscripcion{}).
+		Where("usuario_id = ? AND actividad_id = ? AND (sesion_id IS NULL OR sesion_id = ?)", usuarioID, sesion.ActividadID, sesion.ID).
+		Where("fecha_inscripcion <= ?", inicio).
+		Where("((is_activa = ? AND deleted_at IS NULL) OR updated_at >= ?)", true, fin).
+		Count(&inscripciones).Error
+	if err != nil {
+		return false, fmt.Errorf("error verificando inscripcion: %w", err)
+	}
+
+	return inscripciones > 0, nil
+}
+
+// conSesion arma la consulta de calificaciones con la fecha de la sesión
+func (r *MySQLCalificacionesRepository) conSesion(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Model(&dao.Calificacion{}).
+		Select("calificaciones.*, s.fecha").
+		Joins("JOIN sesiones s ON s.id_sesion = calificaciones.sesion_id")
+}
+
+// filtrarCalificaciones aplica los filtros por actividad, instructor y marca
+func filtrarCalificaciones(query *gorm.DB, filtro domain.FiltroCalificaciones) *gorm.DB {
+	if filtro.ActividadID != nil {
+		query = query.Where("calificaciones.actividad_id = ?", *filtro.ActividadID)
+	}
+	if filtro.InstructorPerfilID != nil {
+		query = query.Where("calificaciones.instructor_perfil_id = ?", *filtro.InstructorPerfilID)
+	}
+	if filtro.Marcada != nil {
+		query = query.Where("calificaciones.marcada = ?", *filtro.Marcada)
+	}
+	return query
+}
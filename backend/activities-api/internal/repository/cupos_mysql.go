@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"activities-api/internal/dao"
+	"activities-api/internal/domain"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CuposRepository define la interfaz del registro de clases descontadas del cupo de los planes
+type CuposRepository interface {
+	// Descontar valida el cupo del plan y descuenta las clases que todavía no estaban registradas (en su transacción);
+	// las reintegradas siguen reintegradas. Devuelve domain.ErrCupoAgotado si algún período no tiene lugar
+	Descontar(ctx context.Context, inscripcion domain.Inscripcion, cargo domain.CargoCupo) error
+	// ListByUsuario devuelve los consumos (usados y reintegrados) del usuario con fecha de clase en el rango (inclusive)
+	ListByUsuario(ctx context.Context, usuarioID uint, desde, hasta time.Time) ([]domain.ConsumoCupo, error)
+	// ListByInscripcion devuelve los consumos de la inscripción con fecha de clase desde la indicada (inclusive)
+	ListByInscripcion(ctx context.Context, inscripcionID uint, desde time.Time) ([]domain.ConsumoCupo, error)
+	// Reintegrar reintegra los consumos indicados que sigan usados
+	Reintegrar(ctx context.Context, ids []uint, motivo string) (int64, error)
+	// ReintegrarSesion reintegra las clases usadas de la sesión (reservas y fijas)
+	ReintegrarSesion(ctx context.Context, sesionID uint, motivo string) (int64, error)
+	// ListBySuscripcion devuelve los consumos descontados con la suscripción con fecha de clase desde la indicada (inclusive)
+	ListBySuscripcion(ctx context.Context, suscripcionID string, desde time.Time) ([]domain.ConsumoCupo, error)
+	// MoverSesion pasa los consumos de una sesión reprogramada a la nueva fecha (y a su período)
+	MoverSesion(ctx context.Context, sesionID uint, fecha time.Time) error
+}
+
+// MySQLCuposRepository implementa CuposRepository usando MySQL/GORM
+type MySQLCuposRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLCuposRepository crea una nueva instancia del repository
+// Comparte la conexión DB con ActividadesRepository (tabla en BDD/02-init-activities.sql)
+func NewMySQLCuposRepository(db *gorm.DB) *MySQLCuposRepository {
+	return &MySQLCuposRepository{
+		db: db,
+	}
+}
+
+// Descontar registra las sesiones nuevas de una inscripción fija (las que genera la generación de sesiones)
+// con el mismo control de cupo que la inscripción. Los de la misma inscripción y sesión que ya estaban
+// no se tocan: un reintegro no se deshace
+func (r *MySQLCuposRepository) Descontar(ctx context.Context, inscripcion domain.Inscripcion, cargo domain.CargoCupo) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := validarCargo(tx, inscripcion.UsuarioID, cargo); err != nil {
+			return err
+		}
+
+		consumos := make([]domain.ConsumoCupo, len(cargo.Consumos))
+		for i, consumo := range cargo.Consumos {
+			consumo.InscripcionID = inscripcion.ID
+			consumos[i] = consumo
+		}
+		return registrarConsumos(tx, consumos, clause.OnConflict{DoNothing: true})
+	})
+}
+
+// descontarCupo valida el cupo del plan y registra los consumos dentro de la transacción de la inscripción
+func descontarCupo(tx *gorm.DB, inscripcion dao.Inscripcion, cargo domain.CargoCupo) error {
+	if err := validarCargo(tx, inscripcion.UsuarioID, cargo); err != nil {
+		return err
+	}
+
+	consumos := make([]domain.ConsumoCupo, len(cargo.Consumos))
+	for i, consumo := range cargo.Consumos {
+		consumo.InscripcionID = inscripcion.ID
+		consumos[i] = consumo
+	}
+
+	// Los de la misma inscripción y sesión que ya estaban (reintegrados porque el socio se había dado de baja)
+	// vuelven a contar con la fecha y el período actuales
+	reactivar := clause.AssignmentColumns([]string{"suscripcion_id", "fecha_clase", "periodo", "periodo_clave", "estado", "updated_at"})
+	reactivar = append(reactivar, clause.Assignments(map[string]interface{}{
+		"motivo_reintegro": nil,
+		"reintegrado_en":   nil,
+	})...)
+	return registrarConsumos(tx, consumos, clause.OnConflict{DoUpdates: reactivar})
+}
+
+// validarCargo controla que en cada período al que el cargo suma clases, las usadas más las nuevas no pasen el límite
+// Bloquea los consumos del socio en cada período (SELECT ... FOR UPDATE sobre idx_usuario_fecha, con sus huecos):
+// otra inscripción del socio a una actividad distinta espera a que esta termine, o InnoDB la aborta por deadlock
+func validarCargo(tx *gorm.DB, usuarioID uint, cargo domain.CargoCupo) error {
+	if cargo.Limite == 0 {
+		return nil
+	}
+
+	for _, periodo := range cargo.Periodos() {
+		var estados []string
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Model(&dao.ConsumoCupo{}).
+			Where("usuario_id = ? AND fecha_clase BETWEEN ? AND ?", usuarioID, periodo.Desde.Format("2006-01-02"), periodo.Hasta.Format("2006-01-02")).
+			Pluck("estado", &estados).Error
+		if err != nil {
+			return fmt.Errorf("error counting consumos: %w", err)
+		}
+
+		usadas := 0
+		for _, estado := range estados {
+			if estado == domain.ConsumoUsado {
+				usadas++
+			}
+		}
+		if usadas+periodo.Nuevas > cargo.Limite {
+			return fmt.Errorf("%w: %d usadas + %d nuevas de %d clases en %s", domain.ErrCupoAgotado, usadas, periodo.Nuevas, cargo.Limite, periodo.Clave)
+		}
+	}
+	return nil
+}
+
+// registrarConsumos inserta los consumos resolviendo la clave única (inscripcion_id, sesion_id) con conflicto
+func registrarConsumos(db *gorm.DB, consumos []domain.ConsumoCupo, conflicto clause.OnConflict) error {
+	if len(consumos) == 0 {
+		return nil
+	}
+
+	consumosDAO := make([]dao.ConsumoCupo, len(consumos))
+	for i, consumo := range consumos {
+		fecha, err := time.Parse("2006-01-02", consumo.FechaClase)
+		if err != nil {
+			return fmt.Errorf("fecha de clase inválida %q: %w", consumo.FechaClase, err)
+		}
+		consumosDAO[i] = dao.ConsumoCupoFromDomain(consumo, fecha)
+	}
+
+	if err := db.Clauses(conflicto).Create(&consumosDAO).Error; err != nil {
+		return fmt.Errorf("error registering consumos: %w", err)
+	}
+
+	return nil
+}
+
+// ListByUsuario obtiene los consumos del usuario con fecha de clase en el rango
+func (r *MySQLCuposRepository) ListByUsuario(ctx context.Context, usuarioID uint, desde, hasta time.Time) ([]domain.ConsumoCupo, error) {
+	var consumosDAO []dao.ConsumoCupo
+
+	err := r.db.WithContext(ctx).
+		Where("usuario_id = ? AND fecha_clase BETWEEN ? AND ?", usuarioID, desde.Format("2006-01-02"), hasta.Format("2006-01-02")).
+		Order("fecha_clase ASC, id_consumo ASC").
+		Find(&consumosDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing consumos: %w", err)
+	}
+
+	consumos := make([]domain.ConsumoCupo, len(consumosDAO))
+	for i, c := range consumosDAO {
+		consumos[i] = c.ToDomain()
+	}
+	return consumos, nil
+}
+
+// ListByInscripcion obtiene los consumos de la inscripción desde una fecha
+func (r *MySQLCuposRepository) ListByInscripcion(ctx context.Context, inscripcionID uint, desde time.Time) ([]domain.ConsumoCupo, error) {
+	var consumosDAO []dao.ConsumoCupo
+
+	err := r.db.WithContext(ctx).
+		Where("inscripcion_id = ? AND fecha_clase >= ?", inscripcionID, desde.Format("2006-01-02")).
+		Order("fecha_clase ASC, id_consumo ASC").
+		Find(&consumosDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing consumos: %w", err)
+	}
+
+	consumos := make([]domain.ConsumoCupo, len(consumosDAO))
+	for i, c := range consumosDAO {
+		consumos[i] = c.ToDomain()
+	}
+	return consumos, nil
+}
+
+// ListBySuscripcion obtiene los consumos de una suscripción desde una fecha (las clases de un reembolso)
+func (r *MySQLCuposRepository) ListBySuscripcion(ctx context.Context, suscripcionID string, desde time.Time) ([]domain.ConsumoCupo, error) {
+	var consumosDAO []dao.ConsumoCupo
+
+	err := r.db.WithContext(ctx).
+		Where("suscripcion_id = ? AND fecha_clase >= ?", suscripcionID, desde.Format("2006-01-02")).
+		Order("fecha_clase ASC, id_consumo ASC").
+		Find(&consumosDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing consumos: %w", err)
+	}
+
+	consumos := make([]domain.ConsumoCupo, len(consumosDAO))
+	for i, c := range consumosDAO {
+		consumos[i] = c.ToDomain()
+	}
+	return consumos, nil
+}
+
+// Reintegrar reintegra consumos puntuales (las clases que el socio liberó al darse de baja)
+func (r *MySQLCuposRepository) Reintegrar(ctx context.Context, ids []uint, motivo string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return r.reintegrar(ctx, motivo, "id_consumo IN ?", ids)
+}
+
+// ReintegrarSesion reintegra las clases de una sesión cancelada
+func (r *MySQLCuposRepository) ReintegrarSesion(ctx context.Context, sesionID uint, motivo string) (int64, error) {
+	return r.reintegrar(ctx, motivo, "sesion_id = ?", sesionID)
+}
+
+func (r *MySQLCuposRepository) reintegrar(ctx context.Context, motivo string, query string, args ...interface{}) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&dao.ConsumoCupo{}).
+		Where("estado = ?", domain.ConsumoUsado).
+		Where(query, args...).
+		Updates(map[string]interface{}{
+			"estado":           domain.ConsumoReintegrado,
+			"motivo_reintegro": motivo,
+			"reintegrado_en":   time.Now(),
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("error refunding consumos: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// MoverSesion recalcula fecha y período de los consumos de la sesión (cada uno según su período)
+func (r *MySQLCuposRepository) MoverSesion(ctx context.Context, sesionID uint, fecha time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var consumosDAO []dao.ConsumoCupo
+		if err := tx.Where("sesion_id = ?", sesionID).Find(&consumosDAO).Error; err != nil {
+			return fmt.Errorf("error listing consumos: %w", err)
+		}
+
+		for _, consumo := range consumosDAO {
+			clave, _, _ := domain.PeriodoCupo(consumo.Periodo, fecha)
+			err := tx.Model(&dao.ConsumoCupo{}).
+				Where("id_consumo = ?", consumo.ID).
+				Updates(map[string]interface{}{
+					"fecha_clase":   fecha,
+					"periodo_clave": clave,
+				}).Error
+			if err != nil {
+				return fmt.Errorf("error moving consumo %d: %w", consumo.ID, err)
+			}
+		}
+		return nil
+	})
+}
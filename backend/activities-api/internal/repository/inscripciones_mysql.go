@@ -6,17 +6,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // InscripcionesRepository define la interfaz del repositorio de inscripciones
 type InscripcionesRepository interface {
 	ListByUser(ctx context.Context, usuarioID uint) ([]domain.Inscripcion, error)
+	ListByActividad(ctx context.Context, actividadID uint) ([]domain.Inscripcion, error)
 	GetByUserAndActividad(ctx context.Context, usuarioID, actividadID uint) (domain.Inscripcion, error)
-	Create(ctx context.Context, inscripcion domain.Inscripcion) (domain.Inscripcion, error)
-	Deactivate(ctx context.Context, usuarioID, actividadID uint) error
+	// Create descuenta el cargo del cupo del plan (nil = sin cupo) en la misma transacción
+	Create(ctx context.Context, inscripcion domain.Inscripcion, cargo *domain.CargoCupo) (domain.Inscripcion, error)
+	Deactivate(ctx context.Context, usuarioID, actividadID uint, sesionID *uint) error
 }
 
 // MySQLInscripcionesRepository implementa InscripcionesRepository usando MySQL/GORM
@@ -63,7 +67,29 @@ func (r *MySQLInscripcionesRepository) ListByUser(ctx context.Context, usuarioID
 	return inscripciones, nil
 }
 
-// GetByUserAndActividad obtiene una inscripción específica
+// ListByActividad obtiene las inscripciones activas de una actividad (el listado de la clase)
+func (r *MySQLInscripcionesRepository) ListByActividad(ctx context.Context, actividadID uint) ([]domain.Inscripcion, error) {
+	var inscripcionesDAO []dao.Inscripcion
+
+	err := r.db.WithContext(ctx).
+		Where("actividad_id = ? AND is_activa = ?", actividadID, true).
+		Order("fecha_inscripcion ASC").
+		Find(&inscripcionesDAO).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("error listing inscripciones de la actividad: %w", err)
+	}
+
+	// Convertir a Domain
+	inscripciones := make([]domain.Inscripcion, len(inscripcionesDAO))
+	for i, inscDAO := range inscripcionesDAO {
+		inscripciones[i] = inscDAO.ToDomain()
+	}
+
+	return inscripciones, nil
+}
+
+// GetByUserAndActividad obtiene la inscripción fija semanal de un usuario en una actividad
 // Solo devuelve la inscripción si la actividad está activa
 func (r *MySQLInscripcionesRepository) GetByUserAndActividad(ctx context.Context, usuarioID, actividadID uint) (domain.Inscripcion, error) {
 	var inscripcionDAO dao.Inscripcion
@@ -71,6 +97,7 @@ func (r *MySQLInscripcionesRepository) GetByUserAndActividad(ctx context.Context
 	err := r.db.WithContext(ctx).
 		Joins("JOIN actividades ON inscripciones.actividad_id = actividades.id_actividad").
 		Where("inscripciones.usuario_id = ? AND inscripciones.actividad_id = ?", usuarioID, actividadID).
+		Where("inscripciones.sesion_id IS NULL").
 		Where("actividades.activa = ?", true).
 		First(&inscripcionDAO).Error
 
@@ -85,59 +112,199 @@ func (r *MySQLInscripcionesRepository) GetByUserAndActividad(ctx context.Context
 }
 
 // Create crea una nueva inscripción o reactiva una existente
+// Todo corre en una transacción que bloquea la fila de la actividad (SELECT ... FOR UPDATE):
+// las inscripciones a una misma clase se serializan y el cupo no se puede pasar
+// El cupo de clases del plan se valida y se descuenta en la misma transacción: si no se registra, no hay inscripción
 // Migrado de backend/clients/inscripcion/inscripcion_client.go:27
-func (r *MySQLInscripcionesRepository) Create(ctx context.Context, inscripcion domain.Inscripcion) (domain.Inscripcion, error) {
+func (r *MySQLInscripcionesRepository) Create(ctx context.Context, inscripcion domain.Inscripcion, cargo *domain.CargoCupo) (domain.Inscripcion, error) {
 	inscripcionDAO := dao.InscripcionFromDomain(inscripcion)
 	inscripcionDAO.FechaInscripcion = time.Now()
 	inscripcionDAO.IsActiva = true
 
-	// Intentar buscar inscripción existente (por usuario y actividad)
-	var existing dao.Inscripcion
-	err := r.db.WithContext(ctx).
-		Where("usuario_id = ? AND actividad_id = ?", inscripcionDAO.UsuarioID, inscripcionDAO.ActividadID).
-		First(&existing).Error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Bloquear la actividad. Es la primera lectura de la transacción, así que las consultas
+		// siguientes (REPEATABLE READ) ven todo lo que confirmaron las inscripciones anteriores
+		var actividad dao.Actividad
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id_actividad", "cupo").
+			Where("id_actividad = ? AND activa = ?", inscripcionDAO.ActividadID, true).
+			First(&actividad).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("actividad not found")
+			}
+			return err
+		}
 
-	if err == nil {
-		// La inscripción ya existe
-		if existing.IsActiva {
-			return domain.Inscripcion{}, errors.New("el usuario ya está inscripto en esta actividad")
+		// 2. Duplicados: la inscripción fija también cubre cada sesión de la actividad
+		var duplicadas int64
+		query := tx.Model(&dao.Inscripcion{}).
+			Where("usuario_id = ? AND actividad_id = ? AND is_activa = ?", inscripcionDAO.UsuarioID, inscripcionDAO.ActividadID, true)
+		if inscripcionDAO.SesionID == nil {
+			query = query.Where("sesion_id IS NULL")
+		} else {
+			query = query.Where("sesion_id IS NULL OR sesion_id = ?", *inscripcionDAO.SesionID)
+		}
+		if err := query.Count(&duplicadas).Error; err != nil {
+			return err
+		}
+		if duplicadas > 0 {
+			return domain.ErrYaInscripto
 		}
 
-		// Reactivar inscripción (ejecuta hook BeforeUpdate)
-		existing.IsActiva = true
-		if err := r.db.WithContext(ctx).Model(&existing).Update("is_activa", true).Error; err != nil {
-			return domain.Inscripcion{}, fmt.Errorf("error reactivating inscripcion: %w", err)
+		// 3. Cupo de la actividad o de la sesión
+		if err := validarCupo(tx, actividad, inscripcionDAO.SesionID); err != nil {
+			return err
 		}
 
-		return existing.ToDomain(), nil
-	}
+		// 4. Reactivar la inscripción dada de baja o crear una nueva
+		var existing dao.Inscripcion
+		err = whereSesion(tx, inscripcionDAO.SesionID).
+			Where("usuario_id = ? AND actividad_id = ?", inscripcionDAO.UsuarioID, inscripcionDAO.ActividadID).
+			First(&existing).Error
+		if err == nil {
+			// La suscripción y el plan pueden haber cambiado desde la baja
+			err := tx.Model(&existing).Updates(map[string]interface{}{
+				"is_activa":      true,
+				"suscripcion_id": inscripcionDAO.SuscripcionID,
+				"plan_id":        inscripcionDAO.PlanID,
+			}).Error
+			if err != nil {
+				return err
+			}
+			existing.IsActiva = true
+			existing.SuscripcionID = inscripcionDAO.SuscripcionID
+			existing.PlanID = inscripcionDAO.PlanID
+			inscripcionDAO = existing
+		} else if errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := tx.Create(&inscripcionDAO).Error; err != nil {
+				return err
+			}
+		} else {
+			return err
+		}
 
-	// No existe, crear nueva (ejecuta hook BeforeCreate)
-	if err := r.db.WithContext(ctx).Create(&inscripcionDAO).Error; err != nil {
+		// 5. Cupo de clases del plan: se cuenta con los consumos del socio bloqueados y se descuenta
+		if cargo == nil {
+			return nil
+		}
+		return descontarCupo(tx, inscripcionDAO, *cargo)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrYaInscripto) || errors.Is(err, domain.ErrCupoAlcanzado) || errors.Is(err, domain.ErrCupoAgotado) {
+			return domain.Inscripcion{}, err
+		}
+		// La clave única (usuario_id, actividad_id, sesion_clave) frena lo que se escape del bloqueo
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "Duplicate entry") {
+			return domain.Inscripcion{}, domain.ErrYaInscripto
+		}
 		return domain.Inscripcion{}, fmt.Errorf("error creating inscripcion: %w", err)
 	}
 
 	return inscripcionDAO.ToDomain(), nil
 }
 
-// Deactivate desactiva una inscripción (soft delete lógico)
-// Migrado de backend/clients/inscripcion/inscripcion_client.go:53
-func (r *MySQLInscripcionesRepository) Deactivate(ctx context.Context, usuarioID, actividadID uint) error {
-	result := r.db.WithContext(ctx).
-		Model(&dao.Inscripcion{}).
-		Where("usuario_id = ? AND actividad_id = ?", usuarioID, actividadID).
-		Update("is_activa", false)
-
-	if result.Error != nil {
-		return fmt.Errorf("error deactivating inscripcion: %w", result.Error)
+// validarCupo cuenta los lugares ocupados dentro de la transacción (con la actividad bloqueada)
+// Inscripción fija: las fijas activas contra el cupo de la actividad, y además cada sesión futura
+// ya generada tiene que tener lugar (fijas + reservas de la sesión contra el cupo de la sesión)
+// Reserva de una sesión: fijas de la actividad + reservas de la sesión contra el cupo de la sesión
+func validarCupo(tx *gorm.DB, actividad dao.Actividad, sesionID *uint) error {
+	var fijas int64
+	err := tx.Model(&dao.Inscripcion{}).
+		Where("actividad_id = ? AND sesion_id IS NULL AND is_activa = ? AND deleted_at IS NULL", actividad.ID, true).
+		Count(&fijas).Error
+	if err != nil {
+		return err
+	}
+
+	if sesionID != nil {
+		var sesion dao.Sesion
+		if err := tx.Where("id_sesion = ? AND actividad_id = ?", *sesionID, actividad.ID).First(&sesion).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("sesion not found")
+			}
+			return err
+		}
+		if sesion.Estado == domain.SesionCancelada {
+			return domain.ErrCupoAlcanzado
+		}
+
+		var reservas int64
+		err := tx.Model(&dao.Inscripcion{}).
+			Where("sesion_id = ? AND is_activa = ? AND deleted_at IS NULL", *sesionID, true).
+			Count(&reservas).Error
+		if err != nil {
+			return err
+		}
+		if fijas+reservas >= int64(sesion.Cupo) {
+			return domain.ErrCupoAlcanzado
+		}
+		return nil
+	}
+
+	if fijas >= int64(actividad.Cupo) {
+		return domain.ErrCupoAlcanzado
+	}
+
+	// Las reservas puntuales pueden haber llenado una sesión aunque la actividad tenga lugares
+	var llenas []time.Time
+	err = tx.Model(&dao.Sesion{}).
+		Where("actividad_id = ? AND estado = ?", actividad.ID, domain.SesionProgramada).
+		Where("TIMESTAMP(fecha, TIME(horario_inicio)) > ?", time.Now()).
+		Where("cupo <= ? + (SELECT COUNT(*) FROM inscripciones i WHERE i.sesion_id = sesiones.id_sesion AND i.is_activa = TRUE AND i.deleted_at IS NULL)", fijas).
+		Order("fecha ASC").
+		Limit(1).
+		Pluck("fecha", &llenas).Error
+	if err != nil {
+		return err
 	}
-	if result.RowsAffected == 0 {
-		return errors.New("inscripcion not found")
+	if len(llenas) > 0 {
+		return fmt.Errorf("%w en la sesión del %s", domain.ErrCupoAlcanzado, llenas[0].Format("2006-01-02"))
 	}
 
 	return nil
 }
 
+// Deactivate desactiva una inscripción (soft delete lógico)
+// Con sesionID nil desactiva la inscripción fija semanal, si no la reserva de esa sesión
+// Migrado de backend/clients/inscripcion/inscripcion_client.go:53
+// También libera los equipos que el usuario tenía reservados en las sesiones que cubría la inscripción
+func (r *MySQLInscripcionesRepository) Deactivate(ctx context.Context, usuarioID, actividadID uint, sesionID *uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := whereSesion(tx, sesionID).
+			Model(&dao.Inscripcion{}).
+			Where("usuario_id = ? AND actividad_id = ?", usuarioID, actividadID).
+			Update("is_activa", false)
+
+		if result.Error != nil {
+			return fmt.Errorf("error deactivating inscripcion: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("inscripcion not found")
+		}
+
+		reservas := tx.Where("usuario_id = ?", usuarioID)
+		if sesionID != nil {
+			reservas = reservas.Where("sesion_id = ?", *sesionID)
+		} else {
+			reservas = reservas.Where("sesion_id IN (SELECT id_sesion FROM sesiones WHERE actividad_id = ? AND fecha >= CURDATE())", actividadID)
+		}
+		if err := reservas.Delete(&dao.ReservaEquipo{}).Error; err != nil {
+			return fmt.Errorf("error deleting reservas de equipos: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// whereSesion filtra por la sesión reservada, o por las inscripciones fijas si sesionID es nil
+func whereSesion(db *gorm.DB, sesionID *uint) *gorm.DB {
+	if sesionID == nil {
+		return db.Where("sesion_id IS NULL")
+	}
+	return db.Where("sesion_id = ?", *sesionID)
+}
+
 // TODO: Los compañeros deben agregar:
 // - Validación de usuario existe (HTTP call a users-api)
 // - Validación de suscripción activa (HTTP call a subscriptions-api)
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"activities-api/internal/dao"
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// InstructoresRepository define la interfaz del repositorio de instructores
+// Solo ve los instructores activos: los dados de baja devuelven domain.ErrInstructorNotFound
+type InstructoresRepository interface {
+	List(ctx context.Context) ([]domain.Instructor, error)
+	GetByID(ctx context.Context, id uint) (domain.Instructor, error)
+	// Create devuelve domain.ErrInstructorUsuarioDuplicado si la cuenta ya está vinculada
+	Create(ctx context.Context, instructor domain.Instructor) (domain.Instructor, error)
+	// Update reemplaza los datos y las sucursales del instructor y copia nombre y cuenta a sus actividades
+	Update(ctx context.Context, id uint, instructor domain.Instructor) (domain.Instructor, error)
+	// Deactivate da de baja el instructor (activo = false)
+	Deactivate(ctx context.Context, id uint) error
+	// ListActividades obtiene las actividades activas que dicta el instructor (vista con lugares)
+	ListActividades(ctx context.Context, id uint) ([]domain.Actividad, error)
+}
+
+// MySQLInstructoresRepository implementa InstructoresRepository usando MySQL/GORM
+type MySQLInstructoresRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLInstructoresRepository crea una nueva instancia del repository
+// Comparte la conexión DB con ActividadesRepository (tablas en BDD/02-init-activities.sql)
+func NewMySQLInstructoresRepository(db *gorm.DB) *MySQLInstructoresRepository {
+	return &MySQLInstructoresRepository{
+		db: db,
+	}
+}
+
+// List obtiene los instructores activos con sus sucursales
+func (r *MySQLInstructoresRepository) List(ctx context.Context) ([]domain.Instructor, error) {
+	var instructoresDAO []dao.Instructor
+
+	err := r.db.WithContext(ctx).
+		Preload("Sucursales").
+		Where("activo = ?", true).
+		Order("nombre ASC").
+		Find(&instructoresDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing instructores: %w", err)
+	}
+
+	instructores := make([]domain.Instructor, len(instructoresDAO))
+	for i, instructorDAO := range instructoresDAO {
+		instructores[i] = instructorDAO.ToDomain()
+	}
+
+	return instructores, nil
+}
+
+// GetByID obtiene un instructor activo por ID
+func (r *MySQLInstructoresRepository) GetByID(ctx context.Context, id uint) (domain.Instructor, error) {
+	return r.getByID(r.db.WithContext(ctx), id)
+}
+
+// Create inserta el instructor y sus sucursales en una transacción
+func (r *MySQLInstructoresRepository) Create(ctx context.Context, instructor domain.Instructor) (domain.Instructor, error) {
+	instructorDAO := dao.InstructorFromDomain(instructor)
+	instructorDAO.Activo = true
+
+	// GORM crea el instructor y las filas de Sucursales dentro de la misma transacción
+	if err := r.db.WithContext(ctx).Create(&instructorDAO).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "Duplicate entry") {
+			return domain.Instructor{}, domain.ErrInstructorUsuarioDuplicado
+		}
+		return domain.Instructor{}, fmt.Errorf("error creating instructor: %w", err)
+	}
+
+	return instructorDAO.ToDomain(), nil
+}
+
+// Update actualiza el instructor, reemplaza sus sucursales y refresca el nombre y la cuenta
+// copiados en sus actividades, todo en una transacción
+func (r *MySQLInstructoresRepository) Update(ctx context.Context, id uint, instructor domain.Instructor) (domain.Instructor, error) {
+	instructorDAO := dao.InstructorFromDomain(instructor)
+
+	var updated domain.Instructor
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Select explícito: los campos vacíos y usuario_id nil también se guardan
+		result := tx.Model(&dao.Instructor{}).
+			Where("id_instructor = ? AND activo = ?", id, true).
+			Select("nombre", "bio", "foto_url", "especialidades", "usuario_id").
+			Updates(&instructorDAO)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrDuplicatedKey) || strings.Contains(result.Error.Error(), "Duplicate entry") {
+				return domain.ErrInstructorUsuarioDuplicado
+			}
+			return fmt.Errorf("error updating instructor: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			// Sin cambios MySQL informa 0 filas: distinguir de un instructor inexistente
+			if _, err := r.getByID(tx, id); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("instructor_id = ?", id).Delete(&dao.InstructorSucursal{}).Error; err != nil {
+			return fmt.Errorf("error replacing sucursales: %w", err)
+		}
+		for i := range instructorDAO.Sucursales {
+			instructorDAO.Sucursales[i].InstructorID = id
+		}
+		if len(instructorDAO.Sucursales) > 0 {
+			if err := tx.Create(&instructorDAO.Sucursales).Error; err != nil {
+				return fmt.Errorf("error replacing sucursales: %w", err)
+			}
+		}
+
+		// Las actividades guardan nombre y cuenta del instructor (búsquedas y permisos de rosters)
+		err := tx.Model(&dao.Actividad{}).
+			Where("instructor_perfil_id = ?", id).
+			Updates(map[string]interface{}{
+				"instructor":    instructorDAO.Nombre,
+				"instructor_id": instructorDAO.UsuarioID,
+			}).Error
+		if err != nil {
+			return fmt.Errorf("error updating actividades del instructor: %w", err)
+		}
+
+		updated, err = r.getByID(tx, id)
+		return err
+	})
+	if err != nil {
+		return domain.Instructor{}, err
+	}
+
+	return updated, nil
+}
+
+// Deactivate marca el instructor como inactivo
+func (r *MySQLInstructoresRepository) Deactivate(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).
+		Model(&dao.Instructor{}).
+		Where("id_instructor = ? AND activo = ?", id, true).
+		Update("activo", false)
+	if result.Error != nil {
+		return fmt.Errorf("error deactivating instructor: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrInstructorNotFound
+	}
+
+	return nil
+}
+
+// ListActividades obtiene las actividades activas del instructor ordenadas por día y horario
+func (r *MySQLInstructoresRepository) ListActividades(ctx context.Context, id uint) ([]domain.Actividad, error) {
+	var actividadesDAO []dao.ActividadVista
+
+	err := r.db.WithContext(ctx).
+		Where("instructor_perfil_id = ? AND activa = ?", id, true).
+		Order("FIELD(dia, 'Lunes', 'Martes', 'Miercoles', 'Jueves', 'Viernes', 'Sabado', 'Domingo'), horario_inicio").
+		Find(&actividadesDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing actividades del instructor: %w", err)
+	}
+
+	actividades := make([]domain.Actividad, len(actividadesDAO))
+	for i, actDAO := range actividadesDAO {
+		actividades[i] = actDAO.ToDomain()
+	}
+
+	return actividades, nil
+}
+
+// getByID busca el instructor activo con sus sucursales usando "db" (conexión o transacción)
+func (r *MySQLInstructoresRepository) getByID(db *gorm.DB, id uint) (domain.Instructor, error) {
+	var instructorDAO dao.Instructor
+
+	err := db.
+		Preload("Sucursales").
+		Where("id_instructor = ? AND activo = ?", id, true).
+		First(&instructorDAO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Instructor{}, domain.ErrInstructorNotFound
+		}
+		return domain.Instructor{}, fmt.Errorf("error getting instructor by ID: %w", err)
+	}
+
+	return instructorDAO.ToDomain(), nil
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"activities-api/internal/dao"
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrListaEsperaNotFound indica que la entrada de la lista de espera no existe
+var ErrListaEsperaNotFound = errors.New("entrada de lista de espera not found")
+
+// posicionSQL calcula la posición en la cola: las que siguen esperando en la misma clase y se anotaron antes
+// <=> compara sesion_id tratando NULL = NULL (inscripción fija)
+const posicionSQL = `(SELECT COUNT(*) FROM lista_espera o
+	WHERE o.actividad_id = lista_espera.actividad_id
+	AND o.sesion_id <=> lista_espera.sesion_id
+	AND o.estado = 'esperando'
+	AND o.id_lista_espera <= lista_espera.id_lista_espera) AS posicion`
+
+// ListaEsperaRepository define la interfaz del repositorio de la lista de espera
+type ListaEsperaRepository interface {
+	Create(ctx context.Context, entrada domain.EntradaListaEspera) (domain.EntradaListaEspera, error)
+	GetByID(ctx context.Context, id uint) (domain.EntradaListaEspera, error)
+	// GetActiva devuelve la entrada esperando u ofrecida del usuario en la actividad/sesión
+	GetActiva(ctx context.Context, usuarioID, actividadID uint, sesionID *uint) (domain.EntradaListaEspera, error)
+	// ListActivasByUser lista las entradas esperando u ofrecidas del usuario, con su posición
+	ListActivasByUser(ctx context.Context, usuarioID uint) ([]domain.EntradaListaEspera, error)
+	// NextEsperando devuelve la primera entrada de la cola (orden de llegada)
+	NextEsperando(ctx context.Context, actividadID uint, sesionID *uint) (domain.EntradaListaEspera, error)
+	// ListOfertasVencidas lista las ofertas que no se confirmaron antes de "now"
+	ListOfertasVencidas(ctx context.Context, now time.Time) ([]domain.EntradaListaEspera, error)
+	// Ofrecer pasa una entrada de esperando a ofrecida; devuelve false si otro proceso ya la tomó
+	Ofrecer(ctx context.Context, id uint, ofrecidaEn, venceEn time.Time) (bool, error)
+	// CambiarEstado pasa una entrada de un estado a otro; devuelve false si ya no estaba en "desde"
+	CambiarEstado(ctx context.Context, id uint, desde, hacia string) (bool, error)
+	// CancelActiva cancela la entrada esperando u ofrecida del usuario en la actividad/sesión
+	CancelActiva(ctx context.Context, usuarioID, actividadID uint, sesionID *uint) error
+	// CancelByUser cancela todas las entradas esperando u ofrecidas del usuario
+	CancelByUser(ctx context.Context, usuarioID uint) (int64, error)
+	// CancelBySesion cancela todas las entradas esperando u ofrecidas de una sesión (se canceló la clase)
+	CancelBySesion(ctx context.Context, sesionID uint) (int64, error)
+}
+
+// MySQLListaEsperaRepository implementa ListaEsperaRepository usando MySQL/GORM
+type MySQLListaEsperaRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLListaEsperaRepository crea una nueva instancia del repository
+// Comparte la conexión DB con ActividadesRepository (tabla en BDD/02-init-activities.sql)
+func NewMySQLListaEsperaRepository(db *gorm.DB) *MySQLListaEsperaRepository {
+	return &MySQLListaEsperaRepository{
+		db: db,
+	}
+}
+
+// Create agrega una entrada al final de la cola y devuelve su posición
+func (r *MySQLListaEsperaRepository) Create(ctx context.Context, entrada domain.EntradaListaEspera) (domain.EntradaListaEspera, error) {
+	entradaDAO := dao.ListaEsperaFromDomain(entrada)
+
+	if err := r.db.WithContext(ctx).Create(&entradaDAO).Error; err != nil {
+		return domain.EntradaListaEspera{}, fmt.Errorf("error creating entrada de lista de espera: %w", err)
+	}
+
+	return r.GetByID(ctx, entradaDAO.ID)
+}
+
+// GetByID obtiene una entrada por ID (con su posición si sigue esperando)
+func (r *MySQLListaEsperaRepository) GetByID(ctx context.Context, id uint) (domain.EntradaListaEspera, error) {
+	return r.first(r.db.WithContext(ctx).Where("id_lista_espera = ?", id))
+}
+
+// GetActiva obtiene la entrada activa del usuario en la actividad/sesión
+func (r *MySQLListaEsperaRepository) GetActiva(ctx context.Context, usuarioID, actividadID uint, sesionID *uint) (domain.EntradaListaEspera, error) {
+	return r.first(whereSesion(r.db.WithContext(ctx), sesionID).
+		Where("usuario_id = ? AND actividad_id = ?", usuarioID, actividadID).
+		Where("estado IN ?", []string{domain.ListaEsperaEsperando, domain.ListaEsperaOfrecida}))
+}
+
+// ListActivasByUser lista las entradas activas del usuario, primero las más antiguas
+func (r *MySQLListaEsperaRepository) ListActivasByUser(ctx context.Context, usuarioID uint) ([]domain.EntradaListaEspera, error) {
+	var entradasDAO []dao.ListaEspera
+
+	err := r.db.WithContext(ctx).
+		Select("lista_espera.*, "+posicionSQL).
+		Where("usuario_id = ?", usuarioID).
+		Where("estado IN ?", []string{domain.ListaEsperaEsperando, domain.ListaEsperaOfrecida}).
+		Order("id_lista_espera ASC").
+		Find(&entradasDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing lista de espera: %w", err)
+	}
+
+	return listaEsperaToDomain(entradasDAO), nil
+}
+
+// NextEsperando obtiene la próxima entrada de la cola de una actividad/sesión
+func (r *MySQLListaEsperaRepository) NextEsperando(ctx context.Context, actividadID uint, sesionID *uint) (domain.EntradaListaEspera, error) {
+	return r.first(whereSesion(r.db.WithContext(ctx), sesionID).
+		Where("actividad_id = ? AND estado = ?", actividadID, domain.ListaEsperaEsperando).
+		Order("id_lista_espera ASC"))
+}
+
+// ListOfertasVencidas lista las ofertas con vence_en anterior a "now"
+func (r *MySQLListaEsperaRepository) ListOfertasVencidas(ctx context.Context, now time.Time) ([]domain.EntradaListaEspera, error) {
+	var entradasDAO []dao.ListaEspera
+
+	err := r.db.WithContext(ctx).
+		Where("estado = ? AND vence_en < ?", domain.ListaEsperaOfrecida, now).
+		Order("vence_en ASC").
+		Find(&entradasDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing ofertas vencidas: %w", err)
+	}
+
+	return listaEsperaToDomain(entradasDAO), nil
+}
+
+// Ofrecer marca la entrada como ofrecida solo si sigue esperando (update condicional)
+func (r *MySQLListaEsperaRepository) Ofrecer(ctx context.Context, id uint, ofrecidaEn, venceEn time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&dao.ListaEspera{}).
+		Where("id_lista_espera = ? AND estado = ?", id, domain.ListaEsperaEsperando).
+		Updates(map[string]interface{}{
+			"estado":      domain.ListaEsperaOfrecida,
+			"ofrecida_en": ofrecidaEn,
+			"vence_en":    venceEn,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("error ofreciendo lugar: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// CambiarEstado cambia el estado solo si la entrada sigue en "desde" (update condicional)
+func (r *MySQLListaEsperaRepository) CambiarEstado(ctx context.Context, id uint, desde, hacia string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&dao.ListaEspera{}).
+		Where("id_lista_espera = ? AND estado = ?", id, desde).
+		Update("estado", hacia)
+	if result.Error != nil {
+		return false, fmt.Errorf("error updating lista de espera: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// CancelActiva cancela la entrada activa del usuario en la actividad/sesión (si tiene una)
+func (r *MySQLListaEsperaRepository) CancelActiva(ctx context.Context, usuarioID, actividadID uint, sesionID *uint) error {
+	err := whereSesion(r.db.WithContext(ctx), sesionID).
+		Model(&dao.ListaEspera{}).
+		Where("usuario_id = ? AND actividad_id = ?", usuarioID, actividadID).
+		Where("estado IN ?", []string{domain.ListaEsperaEsperando, domain.ListaEsperaOfrecida}).
+		Update("estado", domain.ListaEsperaCancelada).Error
+	if err != nil {
+		return fmt.Errorf("error cancelling lista de espera: %w", err)
+	}
+
+	return nil
+}
+
+// CancelByUser cancela todas las entradas activas del usuario
+func (r *MySQLListaEsperaRepository) CancelByUser(ctx context.Context, usuarioID uint) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&dao.ListaEspera{}).
+		Where("usuario_id = ?", usuarioID).
+		Where("estado IN ?", []string{domain.ListaEsperaEsperando, domain.ListaEsperaOfrecida}).
+		Update("estado", domain.ListaEsperaCancelada)
+	if result.Error != nil {
+		return 0, fmt.Errorf("error cancelling lista de espera: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// CancelBySesion cancela todas las entradas activas de la sesión
+func (r *MySQLListaEsperaRepository) CancelBySesion(ctx context.Context, sesionID uint) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&dao.ListaEspera{}).
+		Where("sesion_id = ?", sesionID).
+		Where("estado IN ?", []string{domain.ListaEsperaEsperando, domain.ListaEsperaOfrecida}).
+		Update("estado", domain.ListaEsperaCancelada)
+	if result.Error != nil {
+		return 0, fmt.Errorf("error cancelling lista de espera: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// first busca una entrada calculando su posición en la cola
+func (r *MySQLListaEsperaRepository) first(query *gorm.DB) (domain.EntradaListaEspera, error) {
+	var entradaDAO dao.ListaEspera
+
+	err := query.Select("lista_espera.*, " + posicionSQL).First(&entradaDAO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.EntradaListaEspera{}, ErrListaEsperaNotFound
+		}
+		return domain.EntradaListaEspera{}, fmt.Errorf("error getting lista de espera: %w", err)
+	}
+
+	entrada := entradaDAO.ToDomain()
+	if entrada.Estado != domain.ListaEsperaEsperando {
+		entrada.Posicion = 0
+	}
+	return entrada, nil
+}
+
+// listaEsperaToDomain convierte las entradas y deja la posición solo en las que esperan
+func listaEsperaToDomain(entradasDAO []dao.ListaEspera) []domain.EntradaListaEspera {
+	entradas := make([]domain.EntradaListaEspera, len(entradasDAO))
+	for i, entradaDAO := range entradasDAO {
+		entradas[i] = entradaDAO.ToDomain()
+		if entradas[i].Estado != domain.ListaEsperaEsperando {
+			entradas[i].Posicion = 0
+		}
+	}
+	return entradas
+}
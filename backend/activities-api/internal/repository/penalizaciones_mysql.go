@@ -0,0 +1,342 @@
+package repository
+
+import (
+	"activities-api/internal/dao"
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PenalizacionesRepository define la interfaz del repositorio de políticas de cancelación, strikes y bloqueos
+type PenalizacionesRepository interface {
+	ListPoliticas(ctx context.Context) ([]domain.PoliticaCancelacion, error)
+	// CreatePolitica devuelve domain.ErrPoliticaDuplicada si ya hay una política con ese alcance y valor
+	CreatePolitica(ctx context.Context, politica domain.PoliticaCancelacion) (domain.PoliticaCancelacion, error)
+	UpdatePolitica(ctx context.Context, id uint, politica domain.PoliticaCancelacion) (domain.PoliticaCancelacion, error)
+	DeletePolitica(ctx context.Context, id uint) error
+
+	// ListStrikes lista los últimos strikes del usuario (también los anulados), primero los más recientes
+	ListStrikes(ctx context.Context, usuarioID uint, limit int) ([]domain.Strike, error)
+	// RegistrarStrike inserta el strike y, si con él el usuario llega a los strikes de bloqueo de la política,
+	// crea el bloqueo y le asigna los strikes pendientes. Devuelve domain.ErrStrikeYaRegistrado si ya existía
+	RegistrarStrike(ctx context.Context, strike domain.Strike, politica domain.PoliticaCancelacion, now time.Time) (domain.Strike, *domain.BloqueoReservas, error)
+	// AnularStrike anula el strike; si había disparado un bloqueo vigente lo levanta
+	AnularStrike(ctx context.Context, id, adminID uint, motivo string, now time.Time) (domain.Strike, error)
+	// GetBloqueoVigente devuelve el bloqueo vigente del usuario o nil si no tiene
+	GetBloqueoVigente(ctx context.Context, usuarioID uint, now time.Time) (*domain.BloqueoReservas, error)
+	// ListAusentesSinStrike lista las ausencias a clases de [desde, hasta) que todavía no tienen strike
+	ListAusentesSinStrike(ctx context.Context, desde, hasta time.Time) ([]domain.AusenteSinStrike, error)
+}
+
+// MySQLPenalizacionesRepository implementa PenalizacionesRepository usando MySQL/GORM
+type MySQLPenalizacionesRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLPenalizacionesRepository crea una nueva instancia del repository
+// Comparte la conexión DB con ActividadesRepository (tablas en BDD/02-init-activities.sql)
+func NewMySQLPenalizacionesRepository(db *gorm.DB) *MySQLPenalizacionesRepository {
+	return &MySQLPenalizacionesRepository{
+		db: db,
+	}
+}
+
+// ListPoliticas obtiene todas las políticas de cancelación
+func (r *MySQLPenalizacionesRepository) ListPoliticas(ctx context.Context) ([]domain.PoliticaCancelacion, error) {
+	var politicasDAO []dao.PoliticaCancelacion
+
+	err := r.db.WithContext(ctx).
+		Order("FIELD(alcance, 'plan', 'categoria', 'general'), valor ASC").
+		Find(&politicasDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing politicas de cancelacion: %w", err)
+	}
+
+	politicas := make([]domain.PoliticaCancelacion, len(politicasDAO))
+	for i, politicaDAO := range politicasDAO {
+		politicas[i] = politicaDAO.ToDomain()
+	}
+
+	return politicas, nil
+}
+
+// CreatePolitica inserta una política de cancelación
+func (r *MySQLPenalizacionesRepository) CreatePolitica(ctx context.Context, politica domain.PoliticaCancelacion) (domain.PoliticaCancelacion, error) {
+	politicaDAO := dao.PoliticaCancelacionFromDomain(politica)
+
+	if err := r.db.WithContext(ctx).Create(&politicaDAO).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "Duplicate entry") {
+			return domain.PoliticaCancelacion{}, domain.ErrPoliticaDuplicada
+		}
+		return domain.PoliticaCancelacion{}, fmt.Errorf("error creating politica de cancelacion: %w", err)
+	}
+
+	return politicaDAO.ToDomain(), nil
+}
+
+// UpdatePolitica reemplaza todos los campos de la política
+func (r *MySQLPenalizacionesRepository) UpdatePolitica(ctx context.Context, id uint, politica domain.PoliticaCancelacion) (domain.PoliticaCancelacion, error) {
+	db := r.db.WithContext(ctx)
+
+	var existing dao.PoliticaCancelacion
+	if err := db.First(&existing, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.PoliticaCancelacion{}, domain.ErrPoliticaNotFound
+		}
+		return domain.PoliticaCancelacion{}, fmt.Errorf("error getting politica de cancelacion: %w", err)
+	}
+
+	// Con un map se guardan también los ceros y los false (Updates con struct los saltea)
+	err := db.Model(&existing).Updates(map[string]interface{}{
+		"alcance":              politica.Alcance,
+		"valor":                politica.Valor,
+		"minutos_limite":       politica.MinutosLimite,
+		"penalizar_ausentes":   politica.PenalizarAusentes,
+		"strikes_bloqueo":      politica.StrikesBloqueo,
+		"dias_bloqueo":         politica.DiasBloqueo,
+		"dias_vigencia_strike": politica.DiasVigenciaStrike,
+	}).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "Duplicate entry") {
+			return domain.PoliticaCancelacion{}, domain.ErrPoliticaDuplicada
+		}
+		return domain.PoliticaCancelacion{}, fmt.Errorf("error updating politica de cancelacion: %w", err)
+	}
+
+	if err := db.First(&existing, id).Error; err != nil {
+		return domain.PoliticaCancelacion{}, fmt.Errorf("error getting politica de cancelacion: %w", err)
+	}
+
+	return existing.ToDomain(), nil
+}
+
+// DeletePolitica borra la política; los strikes que registró quedan sin politica_id
+func (r *MySQLPenalizacionesRepository) DeletePolitica(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&dao.PoliticaCancelacion{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("error deleting politica de cancelacion: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrPoliticaNotFound
+	}
+
+	return nil
+}
+
+// ListStrikes obtiene los últimos strikes del usuario
+func (r *MySQLPenalizacionesRepository) ListStrikes(ctx context.Context, usuarioID uint, limit int) ([]domain.Strike, error) {
+	var strikesDAO []dao.Strike
+
+	err := r.db.WithContext(ctx).
+		Where("usuario_id = ?", usuarioID).
+		Order("created_at DESC, id_strike DESC").
+		Limit(limit).
+		Find(&strikesDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing strikes: %w", err)
+	}
+
+	strikes := make([]domain.Strike, len(strikesDAO))
+	for i, strikeDAO := range strikesDAO {
+		strikes[i] = strikeDAO.ToDomain()
+	}
+
+	return strikes, nil
+}
+
+// RegistrarStrike inserta el strike y evalúa el bloqueo en una sola transacción
+// Los strikes pendientes del usuario se leen con FOR UPDATE: dos strikes simultáneos no crean dos bloqueos
+func (r *MySQLPenalizacionesRepository) RegistrarStrike(ctx context.Context, strike domain.Strike, politica domain.PoliticaCancelacion, now time.Time) (domain.Strike, *domain.BloqueoReservas, error) {
+	strikeDAO := dao.StrikeFromDomain(strike)
+	strikeDAO.CreatedAt = now
+
+	var bloqueo *domain.BloqueoReservas
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&strikeDAO).Error; err != nil {
+			return err
+		}
+		if politica.StrikesBloqueo <= 0 {
+			return nil
+		}
+
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("usuario_id = ? AND anulado = ? AND bloqueo_id IS NULL", strikeDAO.UsuarioID, false)
+		if politica.DiasVigenciaStrike > 0 {
+			query = query.Where("created_at >= ?", now.AddDate(0, 0, -politica.DiasVigenciaStrike))
+		}
+		var pendientes []dao.Strike
+		if err := query.Find(&pendientes).Error; err != nil {
+			return err
+		}
+		if len(pendientes) < politica.StrikesBloqueo {
+			return nil
+		}
+
+		bloqueoDAO := dao.BloqueoReservas{
+			UsuarioID: strikeDAO.UsuarioID,
+			Hasta:     now.AddDate(0, 0, politica.DiasBloqueo),
+			CreatedAt: now,
+		}
+		if err := tx.Create(&bloqueoDAO).Error; err != nil {
+			return err
+		}
+
+		ids := make([]uint, len(pendientes))
+		for i, pendiente := range pendientes {
+			ids[i] = pendiente.ID
+		}
+		if err := tx.Model(&dao.Strike{}).Where("id_strike IN ?", ids).Update("bloqueo_id", bloqueoDAO.ID).Error; err != nil {
+			return err
+		}
+
+		strikeDAO.BloqueoID = &bloqueoDAO.ID
+		bloqueoDomain := bloqueoDAO.ToDomain()
+		bloqueo = &bloqueoDomain
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "Duplicate entry") {
+			return domain.Strike{}, nil, domain.ErrStrikeYaRegistrado
+		}
+		return domain.Strike{}, nil, fmt.Errorf("error registering strike: %w", err)
+	}
+
+	return strikeDAO.ToDomain(), bloqueo, nil
+}
+
+// AnularStrike marca el strike como anulado
+// Sin ese strike el bloqueo que disparó no se habría creado: si sigue vigente se levanta
+// y el resto de sus strikes vuelven a quedar pendientes
+func (r *MySQLPenalizacionesRepository) AnularStrike(ctx context.Context, id, adminID uint, motivo string, now time.Time) (domain.Strike, error) {
+	var strikeDAO dao.Strike
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&strikeDAO, id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrStrikeNotFound
+			}
+			return err
+		}
+		if strikeDAO.Anulado {
+			return domain.ErrStrikeYaAnulado
+		}
+
+		err = tx.Model(&strikeDAO).Updates(map[string]interface{}{
+			"anulado":          true,
+			"anulado_por":      adminID,
+			"motivo_anulacion": motivo,
+			"anulado_en":       now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if strikeDAO.BloqueoID == nil {
+			return nil
+		}
+
+		result := tx.Model(&dao.BloqueoReservas{}).
+			Where("id_bloqueo = ? AND levantado = ? AND hasta > ?", *strikeDAO.BloqueoID, false, now).
+			Update("levantado", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		return tx.Model(&dao.Strike{}).
+			Where("bloqueo_id = ?", *strikeDAO.BloqueoID).
+			Update("bloqueo_id", nil).Error
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrStrikeNotFound) || errors.Is(err, domain.ErrStrikeYaAnulado) {
+			return domain.Strike{}, err
+		}
+		return domain.Strike{}, fmt.Errorf("error anulando strike: %w", err)
+	}
+
+	// Releer: el bloqueo puede haber liberado el bloqueo_id
+	if err := r.db.WithContext(ctx).First(&strikeDAO, id).Error; err != nil {
+		return domain.Strike{}, fmt.Errorf("error getting strike: %w", err)
+	}
+
+	return strikeDAO.ToDomain(), nil
+}
+
+// GetBloqueoVigente obtiene el bloqueo no levantado que termina más tarde
+func (r *MySQLPenalizacionesRepository) GetBloqueoVigente(ctx context.Context, usuarioID uint, now time.Time) (*domain.BloqueoReservas, error) {
+	var bloqueoDAO dao.BloqueoReservas
+
+	err := r.db.WithContext(ctx).
+		Where("usuario_id = ? AND levantado = ? AND hasta > ?", usuarioID, false, now).
+		Order("hasta DESC").
+		First(&bloqueoDAO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting bloqueo: %w", err)
+	}
+
+	bloqueo := bloqueoDAO.ToDomain()
+	return &bloqueo, nil
+}
+
+// ausenteSinStrike es una fila de ListAusentesSinStrike
+type ausenteSinStrike struct {
+	UsuarioID   uint    `gorm:"column:usuario_id"`
+	ActividadID uint    `gorm:"column:actividad_id"`
+	SesionID    uint    `gorm:"column:sesion_id"`
+	Categoria   string  `gorm:"column:categoria"`
+	PlanID      *string `gorm:"column:plan_id"`
+}
+
+// ausentesSinStrikeSQL busca las ausencias sin strike con la categoría de la clase y el plan de la inscripción
+// (la inscripción puede estar dada de baja: se desinscribió después de faltar)
+const ausentesSinStrikeSQL = `
+SELECT a.usuario_id, a.actividad_id, a.sesion_id, COALESCE(act.categoria, '') AS categoria, MAX(i.plan_id) AS plan_id
+FROM asistencias a
+JOIN actividades act ON act.id_actividad = a.actividad_id
+LEFT JOIN inscripciones i ON i.usuario_id = a.usuario_id
+    AND i.actividad_id = a.actividad_id
+    AND (i.sesion_id IS NULL OR i.sesion_id = a.sesion_id)
+LEFT JOIN strikes st ON st.usuario_id = a.usuario_id
+    AND st.sesion_id = a.sesion_id
+    AND st.tipo = 'ausente'
+WHERE a.tipo = 'clase'
+  AND a.estado = 'ausente'
+  AND a.fecha >= ? AND a.fecha < ?
+  AND st.id_strike IS NULL
+GROUP BY a.usuario_id, a.actividad_id, a.sesion_id, act.categoria`
+
+// ListAusentesSinStrike corre ausentesSinStrikeSQL sobre las asistencias con fecha en [desde, hasta)
+func (r *MySQLPenalizacionesRepository) ListAusentesSinStrike(ctx context.Context, desde, hasta time.Time) ([]domain.AusenteSinStrike, error) {
+	var filas []ausenteSinStrike
+
+	err := r.db.WithContext(ctx).
+		Raw(ausentesSinStrikeSQL, desde.Format("2006-01-02"), hasta.Format("2006-01-02")).
+		Scan(&filas).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing ausentes: %w", err)
+	}
+
+	ausentes := make([]domain.AusenteSinStrike, len(filas))
+	for i, fila := range filas {
+		ausentes[i] = domain.AusenteSinStrike{
+			UsuarioID:   fila.UsuarioID,
+			ActividadID: fila.ActividadID,
+			SesionID:    fila.SesionID,
+			Categoria:   fila.Categoria,
+			PlanID:      fila.PlanID,
+		}
+	}
+
+	return ausentes, nil
+}
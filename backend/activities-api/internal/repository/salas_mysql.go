@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"activities-api/internal/dao"
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SalasRepository define la interfaz del repositorio de salas, sus equipos y las reservas de equipos
+// Solo ve las salas y equipos activos: los dados de baja devuelven ErrSalaNotFound / ErrEquipoNotFound
+type SalasRepository interface {
+	ListBySucursal(ctx context.Context, sucursalID uint) ([]domain.Sala, error)
+	GetByID(ctx context.Context, id uint) (domain.Sala, error)
+	// Create devuelve domain.ErrSalaDuplicada si la sucursal ya tiene una sala con ese nombre
+	Create(ctx context.Context, sala domain.Sala) (domain.Sala, error)
+	// Update cambia nombre y capacidad de la sala
+	Update(ctx context.Context, id uint, sala domain.Sala) (domain.Sala, error)
+	// Deactivate da de baja la sala (activa = false)
+	Deactivate(ctx context.Context, id uint) error
+	// ListActividades obtiene las actividades activas que se dictan en la sala
+	ListActividades(ctx context.Context, id uint) ([]domain.Actividad, error)
+
+	GetEquipo(ctx context.Context, id uint) (domain.Equipo, error)
+	// CreateEquipo agrega el equipo a la sala (o reactiva uno dado de baja con el mismo tipo y número)
+	CreateEquipo(ctx context.Context, equipo domain.Equipo) (domain.Equipo, error)
+	// DeactivateEquipo da de baja el equipo y borra sus reservas de sesiones futuras
+	DeactivateEquipo(ctx context.Context, salaID, equipoID uint) error
+
+	ListReservasSesion(ctx context.Context, sesionID uint) ([]domain.ReservaEquipo, error)
+	// CreateReserva devuelve domain.ErrEquipoReservado o domain.ErrReservaEquipoExistente si choca con otra reserva
+	CreateReserva(ctx context.Context, reserva domain.ReservaEquipo) (domain.ReservaEquipo, error)
+	// DeleteReserva borra la reserva de equipo del usuario en la sesión
+	DeleteReserva(ctx context.Context, usuarioID, sesionID uint) error
+}
+
+// MySQLSalasRepository implementa SalasRepository usando MySQL/GORM
+type MySQLSalasRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLSalasRepository crea una nueva instancia del repository
+// Comparte la conexión DB con ActividadesRepository (tablas en BDD/02-init-activities.sql)
+func NewMySQLSalasRepository(db *gorm.DB) *MySQLSalasRepository {
+	return &MySQLSalasRepository{
+		db: db,
+	}
+}
+
+// ListBySucursal obtiene las salas activas de una sucursal con sus equipos
+func (r *MySQLSalasRepository) ListBySucursal(ctx context.Context, sucursalID uint) ([]domain.Sala, error) {
+	var salasDAO []dao.Sala
+
+	err := r.db.WithContext(ctx).
+		Preload("Equipos", func(db *gorm.DB) *gorm.DB {
+			return db.Order("tipo ASC, numero ASC")
+		}).
+		Where("sucursal_id = ? AND activa = ?", sucursalID, true).
+		Order("nombre ASC").
+		Find(&salasDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing salas: %w", err)
+	}
+
+	salas := make([]domain.Sala, len(salasDAO))
+	for i, salaDAO := range salasDAO {
+		salas[i] = salaDAO.ToDomain()
+	}
+
+	return salas, nil
+}
+
+// GetByID obtiene una sala activa por ID
+func (r *MySQLSalasRepository) GetByID(ctx context.Context, id uint) (domain.Sala, error) {
+	return r.getByID(r.db.WithContext(ctx), id)
+}
+
+// Create inserta una sala
+func (r *MySQLSalasRepository) Create(ctx context.Context, sala domain.Sala) (domain.Sala, error) {
+	salaDAO := dao.SalaFromDomain(sala)
+	salaDAO.Activa = true
+
+	if err := r.db.WithContext(ctx).Create(&salaDAO).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "Duplicate entry") {
+			return domain.Sala{}, domain.ErrSalaDuplicada
+		}
+		return domain.Sala{}, fmt.Errorf("error creating sala: %w", err)
+	}
+
+	return salaDAO.ToDomain(), nil
+}
+
+// Update actualiza nombre y capacidad de una sala activa
+func (r *MySQLSalasRepository) Update(ctx context.Context, id uint, sala domain.Sala) (domain.Sala, error) {
+	result := r.db.WithContext(ctx).
+		Model(&dao.Sala{}).
+		Where("id_sala = ? AND activa = ?", id, true).
+		Updates(map[string]interface{}{
+			"nombre":    sala.Nombre,
+			"capacidad": sala.Capacidad,
+		})
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) || strings.Contains(result.Error.Error(), "Duplicate entry") {
+			return domain.Sala{}, domain.ErrSalaDuplicada
+		}
+		return domain.Sala{}, fmt.Errorf("error updating sala: %w", result.Error)
+	}
+
+	// Sin cambios MySQL informa 0 filas: getByID distingue una sala inexistente
+	return r.GetByID(ctx, id)
+}
+
+// Deactivate marca la sala como inactiva
+func (r *MySQLSalasRepository) Deactivate(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).
+		Model(&dao.Sala{}).
+		Where("id_sala = ? AND activa = ?", id, true).
+		Update("activa", false)
+	if result.Error != nil {
+		return fmt.Errorf("error deactivating sala: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrSalaNotFound
+	}
+
+	return nil
+}
+
+// ListActividades obtiene las actividades activas de la sala ordenadas por día y horario
+func (r *MySQLSalasRepository) ListActividades(ctx context.Context, id uint) ([]domain.Actividad, error) {
+	var actividadesDAO []dao.ActividadVista
+
+	err := r.db.WithContext(ctx).
+		Where("sala_id = ? AND activa = ?", id, true).
+		Order("FIELD(dia, 'Lunes', 'Martes', 'Miercoles', 'Jueves', 'Viernes', 'Sabado', 'Domingo'), horario_inicio").
+		Find(&actividadesDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing actividades de la sala: %w", err)
+	}
+
+	actividades := make([]domain.Actividad, len(actividadesDAO))
+	for i, actDAO := range actividadesDAO {
+		actividades[i] = actDAO.ToDomain()
+	}
+
+	return actividades, nil
+}
+
+// GetEquipo obtiene un equipo activo por ID
+func (r *MySQLSalasRepository) GetEquipo(ctx context.Context, id uint) (domain.Equipo, error) {
+	var equipoDAO dao.Equipo
+
+	err := r.db.WithContext(ctx).
+		Where("id_equipo = ? AND activo = ?", id, true).
+		First(&equipoDAO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Equipo{}, domain.ErrEquipoNotFound
+		}
+		return domain.Equipo{}, fmt.Errorf("error getting equipo: %w", err)
+	}
+
+	return equipoDAO.ToDomain(), nil
+}
+
+// CreateEquipo agrega un equipo a la sala en una transacción
+// Si ya hubo un equipo con el mismo tipo y número dado de baja, lo reactiva (la clave única los incluye)
+func (r *MySQLSalasRepository) CreateEquipo(ctx context.Context, equipo domain.Equipo) (domain.Equipo, error) {
+	var created dao.Equipo
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("sala_id = ? AND tipo = ? AND numero = ?", equipo.SalaID, equipo.Tipo, equipo.Numero).
+			First(&created).Error
+		if err == nil {
+			if created.Activo {
+				return domain.ErrEquipoDuplicado
+			}
+			created.Activo = true
+			return tx.Model(&created).Update("activo", true).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		created = dao.Equipo{
+			SalaID: equipo.SalaID,
+			Tipo:   equipo.Tipo,
+			Numero: equipo.Numero,
+			Activo: true,
+		}
+		if err := tx.Create(&created).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "Duplicate entry") {
+				return domain.ErrEquipoDuplicado
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrEquipoDuplicado) {
+			return domain.Equipo{}, err
+		}
+		return domain.Equipo{}, fmt.Errorf("error creating equipo: %w", err)
+	}
+
+	return created.ToDomain(), nil
+}
+
+// DeactivateEquipo da de baja un equipo de la sala y libera sus reservas de sesiones que no pasaron
+func (r *MySQLSalasRepository) DeactivateEquipo(ctx context.Context, salaID, equipoID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&dao.Equipo{}).
+			Where("id_equipo = ? AND sala_id = ? AND activo = ?", equipoID, salaID, true).
+			Update("activo", false)
+		if result.Error != nil {
+			return fmt.Errorf("error deactivating equipo: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrEquipoNotFound
+		}
+
+		err := tx.Where("equipo_id = ?", equipoID).
+			Where("sesion_id IN (SELECT id_sesion FROM sesiones WHERE fecha >= CURDATE())").
+			Delete(&dao.ReservaEquipo{}).Error
+		if err != nil {
+			return fmt.Errorf("error deleting reservas del equipo: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// ListReservasSesion obtiene las reservas de equipos de una sesión
+func (r *MySQLSalasRepository) ListReservasSesion(ctx context.Context, sesionID uint) ([]domain.ReservaEquipo, error) {
+	var reservasDAO []dao.ReservaEquipo
+
+	if err := r.db.WithContext(ctx).Where("sesion_id = ?", sesionID).Find(&reservasDAO).Error; err != nil {
+		return nil, fmt.Errorf("error listing reservas de equipos: %w", err)
+	}
+
+	reservas := make([]domain.ReservaEquipo, len(reservasDAO))
+	for i, reservaDAO := range reservasDAO {
+		reservas[i] = reservaDAO.ToDomain()
+	}
+
+	return reservas, nil
+}
+
+// CreateReserva inserta la reserva; las claves únicas resuelven dos pedidos simultáneos por el mismo equipo
+func (r *MySQLSalasRepository) CreateReserva(ctx context.Context, reserva domain.ReservaEquipo) (domain.ReservaEquipo, error) {
+	reservaDAO := dao.ReservaEquipo{
+		EquipoID:  reserva.EquipoID,
+		SesionID:  reserva.SesionID,
+		UsuarioID: reserva.UsuarioID,
+	}
+
+	if err := r.db.WithContext(ctx).Create(&reservaDAO).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "Duplicate entry") {
+			if strings.Contains(err.Error(), "uk_usuario_sesion") {
+				return domain.ReservaEquipo{}, domain.ErrReservaEquipoExistente
+			}
+			return domain.ReservaEquipo{}, domain.ErrEquipoReservado
+		}
+		return domain.ReservaEquipo{}, fmt.Errorf("error creating reserva de equipo: %w", err)
+	}
+
+	return reservaDAO.ToDomain(), nil
+}
+
+// DeleteReserva borra la reserva de equipo del usuario en la sesión
+func (r *MySQLSalasRepository) DeleteReserva(ctx context.Context, usuarioID, sesionID uint) error {
+	result := r.db.WithContext(ctx).
+		Where("usuario_id = ? AND sesion_id = ?", usuarioID, sesionID).
+		Delete(&dao.ReservaEquipo{})
+	if result.Error != nil {
+		return fmt.Errorf("error deleting reserva de equipo: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrReservaEquipoNotFound
+	}
+
+	return nil
+}
+
+// getByID busca la sala activa con sus equipos usando "db" (conexión o transacción)
+func (r *MySQLSalasRepository) getByID(db *gorm.DB, id uint) (domain.Sala, error) {
+	var salaDAO dao.Sala
+
+	err := db.
+		Preload("Equipos", func(db *gorm.DB) *gorm.DB {
+			return db.Order("tipo ASC, numero ASC")
+		}).
+		Where("id_sala = ? AND activa = ?", id, true).
+		First(&salaDAO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Sala{}, domain.ErrSalaNotFound
+		}
+		return domain.Sala{}, fmt.Errorf("error getting sala by ID: %w", err)
+	}
+
+	return salaDAO.ToDomain(), nil
+}
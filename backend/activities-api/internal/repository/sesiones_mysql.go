@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"activities-api/internal/dao"
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SesionesRepository define la interfaz del repositorio de sesiones
+type SesionesRepository interface {
+	ListByActividad(ctx context.Context, actividadID uint, desde, hasta time.Time) ([]domain.Sesion, error)
+	GetByID(ctx context.Context, id uint) (domain.Sesion, error)
+	// Create inserta la sesión; si ya existe una para la actividad en esa fecha no hace nada
+	Create(ctx context.Context, sesion domain.Sesion, fecha, horaInicio, horaFin time.Time) error
+	Update(ctx context.Context, id uint, sesion domain.Sesion, horaInicio, horaFin time.Time) (domain.Sesion, error)
+	// DeleteIfUnbooked borra la sesión solo si no tiene reservas activas
+	DeleteIfUnbooked(ctx context.Context, id uint) (bool, error)
+	// Reprogramar mueve la sesión a otra fecha u horario y guarda la fecha que le tocaba (fechaOriginal)
+	// Devuelve domain.ErrSesionDuplicada si la actividad ya tiene una sesión en la nueva fecha
+	Reprogramar(ctx context.Context, id uint, fechaOriginal, fecha, horaInicio, horaFin time.Time) (domain.Sesion, error)
+	// ListReprogramadas obtiene las sesiones de una actividad cuya fecha original cae en el rango (inclusive)
+	ListReprogramadas(ctx context.Context, actividadID uint, desde, hasta time.Time) ([]domain.Sesion, error)
+}
+
+// MySQLSesionesRepository implementa SesionesRepository usando MySQL/GORM
+type MySQLSesionesRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLSesionesRepository crea una nueva instancia del repository
+// Comparte la conexión DB con ActividadesRepository (tablas y vista en BDD/02-init-activities.sql)
+func NewMySQLSesionesRepository(db *gorm.DB) *MySQLSesionesRepository {
+	return &MySQLSesionesRepository{
+		db: db,
+	}
+}
+
+// ListByActividad obtiene las sesiones de una actividad entre dos fechas (inclusive), con lugares
+func (r *MySQLSesionesRepository) ListByActividad(ctx context.Context, actividadID uint, desde, hasta time.Time) ([]domain.Sesion, error) {
+	var sesionesDAO []dao.SesionVista
+
+	err := r.db.WithContext(ctx).
+		Where("actividad_id = ?", actividadID).
+		Where("fecha BETWEEN ? AND ?", desde.Format("2006-01-02"), hasta.Format("2006-01-02")).
+		Order("fecha ASC").
+		Find(&sesionesDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing sesiones: %w", err)
+	}
+
+	// Convertir a Domain
+	sesiones := make([]domain.Sesion, len(sesionesDAO))
+	for i, sesionDAO := range sesionesDAO {
+		sesiones[i] = sesionDAO.ToDomain()
+	}
+
+	return sesiones, nil
+}
+
+// GetByID obtiene una sesión por ID (usando la vista)
+func (r *MySQLSesionesRepository) GetByID(ctx context.Context, id uint) (domain.Sesion, error) {
+	var sesionDAO dao.SesionVista
+
+	err := r.db.WithContext(ctx).Where("id_sesion = ?", id).First(&sesionDAO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Sesion{}, errors.New("sesion not found")
+		}
+		return domain.Sesion{}, fmt.Errorf("error getting sesion by ID: %w", err)
+	}
+
+	return sesionDAO.ToDomain(), nil
+}
+
+// Create inserta una sesión generada
+// La clave única (actividad_id, fecha) evita duplicados si dos generaciones corren a la vez
+func (r *MySQLSesionesRepository) Create(ctx context.Context, sesion domain.Sesion, fecha, horaInicio, horaFin time.Time) error {
+	sesionDAO := dao.SesionFromDomain(sesion, soloFecha(fecha), horaInicio, horaFin)
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&sesionDAO).Error
+	if err != nil {
+		return fmt.Errorf("error creating sesion: %w", err)
+	}
+
+	return nil
+}
+
+// Update reemplaza horario, cupo, instructor y estado de una sesión
+func (r *MySQLSesionesRepository) Update(ctx context.Context, id uint, sesion domain.Sesion, horaInicio, horaFin time.Time) (domain.Sesion, error) {
+	sesionDAO := dao.SesionFromDomain(sesion, time.Time{}, horaInicio, horaFin)
+
+	// Se usa un map para poder volver a poner en cero motivo y personalizada
+	result := r.db.WithContext(ctx).
+		Model(&dao.Sesion{}).
+		Where("id_sesion = ?", id).
+		Updates(map[string]interface{}{
+			"horario_inicio":     sesionDAO.HorarioInicio,
+			"horario_final":      sesionDAO.HorarioFinal,
+			"cupo":               sesionDAO.Cupo,
+			"instructor":         sesionDAO.Instructor,
+			"instructor_id":      sesionDAO.InstructorID,
+			"estado":             sesionDAO.Estado,
+			"motivo_cancelacion": sesionDAO.MotivoCancelacion,
+			"personalizada":      sesionDAO.Personalizada,
+		})
+	if result.Error != nil {
+		return domain.Sesion{}, fmt.Errorf("error updating sesion: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.Sesion{}, errors.New("sesion not found")
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// DeleteIfUnbooked borra una sesión sin reservas activas (las fijas semanales no cuentan)
+// Devuelve false si la sesión tiene reservas y se conservó
+func (r *MySQLSesionesRepository) DeleteIfUnbooked(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("id_sesion = ?", id).
+		Where("NOT EXISTS (SELECT 1 FROM inscripciones i WHERE i.sesion_id = sesiones.id_sesion AND i.is_activa = TRUE)").
+		Delete(&dao.Sesion{})
+	if result.Error != nil {
+		return false, fmt.Errorf("error deleting sesion: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Reprogramar cambia fecha y horario y guarda la fecha original
+// La sesión queda personalizada para que la generación no la devuelva a su día
+func (r *MySQLSesionesRepository) Reprogramar(ctx context.Context, id uint, fechaOriginal, fecha, horaInicio, horaFin time.Time) (domain.Sesion, error) {
+	result := r.db.WithContext(ctx).
+		Model(&dao.Sesion{}).
+		Where("id_sesion = ?", id).
+		Updates(map[string]interface{}{
+			"fecha_original": soloFecha(fechaOriginal),
+			"fecha":          soloFecha(fecha),
+			"horario_inicio": horaInicio,
+			"horario_final":  horaFin,
+			"personalizada":  true,
+		})
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) || strings.Contains(result.Error.Error(), "Duplicate entry") {
+			return domain.Sesion{}, domain.ErrSesionDuplicada
+		}
+		return domain.Sesion{}, fmt.Errorf("error rescheduling sesion: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.Sesion{}, errors.New("sesion not found")
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// ListReprogramadas obtiene las sesiones movidas cuya fecha original cae en el rango (usando la vista)
+func (r *MySQLSesionesRepository) ListReprogramadas(ctx context.Context, actividadID uint, desde, hasta time.Time) ([]domain.Sesion, error) {
+	var sesionesDAO []dao.SesionVista
+
+	err := r.db.WithContext(ctx).
+		Where("actividad_id = ?", actividadID).
+		Where("fecha_original BETWEEN ? AND ?", desde.Format("2006-01-02"), hasta.Format("2006-01-02")).
+		Find(&sesionesDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing sesiones reprogramadas: %w", err)
+	}
+
+	sesiones := make([]domain.Sesion, len(sesionesDAO))
+	for i, sesionDAO := range sesionesDAO {
+		sesiones[i] = sesionDAO.ToDomain()
+	}
+
+	return sesiones, nil
+}
+
+// soloFecha normaliza a la medianoche local: la conexión usa loc=Local y la columna es DATE
+func soloFecha(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"activities-api/internal/dao"
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ordenDiasSQL ordena el horario semanal de lunes a domingo (el orden del ENUM)
+const ordenDiasSQL = "dia ASC"
+
+// SucursalesRepository define la interfaz del repositorio de sucursales
+// Solo ve las sucursales activas: las dadas de baja devuelven domain.ErrSucursalNotFound
+type SucursalesRepository interface {
+	List(ctx context.Context) ([]domain.Sucursal, error)
+	GetByID(ctx context.Context, id uint) (domain.Sucursal, error)
+	Create(ctx context.Context, sucursal domain.Sucursal) (domain.Sucursal, error)
+	// Update reemplaza los datos y el horario semanal de la sucursal
+	Update(ctx context.Context, id uint, sucursal domain.Sucursal) (domain.Sucursal, error)
+	// Deactivate da de baja la sucursal (activa = false)
+	Deactivate(ctx context.Context, id uint) error
+	// CountActividadesActivas cuenta las actividades activas y no eliminadas de la sucursal
+	CountActividadesActivas(ctx context.Context, id uint) (int64, error)
+}
+
+// MySQLSucursalesRepository implementa SucursalesRepository usando MySQL/GORM
+type MySQLSucursalesRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLSucursalesRepository crea una nueva instancia del repository
+// Comparte la conexión DB con ActividadesRepository (tablas en BDD/02-init-activities.sql)
+func NewMySQLSucursalesRepository(db *gorm.DB) *MySQLSucursalesRepository {
+	return &MySQLSucursalesRepository{
+		db: db,
+	}
+}
+
+// List obtiene las sucursales activas con su horario semanal
+func (r *MySQLSucursalesRepository) List(ctx context.Context) ([]domain.Sucursal, error) {
+	var sucursalesDAO []dao.Sucursal
+
+	err := r.db.WithContext(ctx).
+		Preload("Horarios", func(db *gorm.DB) *gorm.DB { return db.Order(ordenDiasSQL) }).
+		Where("activa = ?", true).
+		Order("nombre ASC").
+		Find(&sucursalesDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing sucursales: %w", err)
+	}
+
+	sucursales := make([]domain.Sucursal, len(sucursalesDAO))
+	for i, sucursalDAO := range sucursalesDAO {
+		sucursales[i] = sucursalDAO.ToDomain()
+	}
+
+	return sucursales, nil
+}
+
+// GetByID obtiene una sucursal activa por ID
+func (r *MySQLSucursalesRepository) GetByID(ctx context.Context, id uint) (domain.Sucursal, error) {
+	return r.getByID(r.db.WithContext(ctx), id)
+}
+
+// Create inserta la sucursal y su horario semanal en una transacción
+func (r *MySQLSucursalesRepository) Create(ctx context.Context, sucursal domain.Sucursal) (domain.Sucursal, error) {
+	sucursalDAO := dao.SucursalFromDomain(sucursal)
+	sucursalDAO.Activa = true
+
+	// GORM crea la sucursal y las filas de Horarios dentro de la misma transacción
+	if err := r.db.WithContext(ctx).Create(&sucursalDAO).Error; err != nil {
+		return domain.Sucursal{}, fmt.Errorf("error creating sucursal: %w", err)
+	}
+
+	return sucursalDAO.ToDomain(), nil
+}
+
+// Update actualiza la sucursal y reemplaza su horario semanal en una transacción
+func (r *MySQLSucursalesRepository) Update(ctx context.Context, id uint, sucursal domain.Sucursal) (domain.Sucursal, error) {
+	sucursalDAO := dao.SucursalFromDomain(sucursal)
+
+	var updated domain.Sucursal
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Select explícito: los campos vacíos y las coordenadas nil también se guardan
+		result := tx.Model(&dao.Sucursal{}).
+			Where("id_sucursal = ? AND activa = ?", id, true).
+			Select("nombre", "direccion", "telefono", "ciudad", "codigo_postal", "zona_horaria", "latitud", "longitud").
+			Updates(&sucursalDAO)
+		if result.Error != nil {
+			return fmt.Errorf("error updating sucursal: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			// Sin cambios MySQL informa 0 filas: distinguir de una sucursal inexistente
+			if _, err := r.getByID(tx, id); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("sucursal_id = ?", id).Delete(&dao.SucursalHorario{}).Error; err != nil {
+			return fmt.Errorf("error replacing horarios: %w", err)
+		}
+		for i := range sucursalDAO.Horarios {
+			sucursalDAO.Horarios[i].SucursalID = id
+		}
+		if len(sucursalDAO.Horarios) > 0 {
+			if err := tx.Create(&sucursalDAO.Horarios).Error; err != nil {
+				return fmt.Errorf("error replacing horarios: %w", err)
+			}
+		}
+
+		var err error
+		updated, err = r.getByID(tx, id)
+		return err
+	})
+	if err != nil {
+		return domain.Sucursal{}, err
+	}
+
+	return updated, nil
+}
+
+// Deactivate marca la sucursal como inactiva
+func (r *MySQLSucursalesRepository) Deactivate(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).
+		Model(&dao.Sucursal{}).
+		Where("id_sucursal = ? AND activa = ?", id, true).
+		Update("activa", false)
+	if result.Error != nil {
+		return fmt.Errorf("error deactivating sucursal: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrSucursalNotFound
+	}
+
+	return nil
+}
+
+// CountActividadesActivas cuenta las actividades que todavía dependen de la sucursal
+func (r *MySQLSucursalesRepository) CountActividadesActivas(ctx context.Context, id uint) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&dao.Actividad{}).
+		Where("sucursal_id = ? AND activa = ?", id, true).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("error counting actividades de la sucursal: %w", err)
+	}
+
+	return count, nil
+}
+
+// getByID busca la sucursal activa con su horario usando "db" (conexión o transacción)
+func (r *MySQLSucursalesRepository) getByID(db *gorm.DB, id uint) (domain.Sucursal, error) {
+	var sucursalDAO dao.Sucursal
+
+	err := db.
+		Preload("Horarios", func(db *gorm.DB) *gorm.DB { return db.Order(ordenDiasSQL) }).
+		Where("id_sucursal = ? AND activa = ?", id, true).
+		First(&sucursalDAO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Sucursal{}, domain.ErrSucursalNotFound
+		}
+		return domain.Sucursal{}, fmt.Errorf("error getting sucursal by ID: %w", err)
+	}
+
+	return sucursalDAO.ToDomain(), nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"activities-api/internal/dao"
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SuscripcionesRepository define la interfaz de la copia local de suscripciones y planes de subscriptions-api
+type SuscripcionesRepository interface {
+	// ListByUsuario devuelve todas las suscripciones conocidas del usuario (vacío = usuario desconocido)
+	ListByUsuario(ctx context.Context, usuarioID uint) ([]domain.Suscripcion, error)
+	// GetSuscripcion devuelve domain.ErrSuscripcionNotFound si no está en la copia local
+	GetSuscripcion(ctx context.Context, id string) (domain.Suscripcion, error)
+	// SaveSuscripcion crea o actualiza la suscripción; no pisa una fila con un EventoEn más nuevo
+	SaveSuscripcion(ctx context.Context, suscripcion domain.Suscripcion) error
+	// DesactivarAusentes marca inactivas las suscripciones activas que no están en vigentes
+	// y no cambiaron desde antes (las que cambió un evento durante la sincronización se respetan)
+	DesactivarAusentes(ctx context.Context, vigentes []string, antes time.Time) (int64, error)
+	// GetPlan devuelve domain.ErrPlanNotFound si no está en la copia local
+	GetPlan(ctx context.Context, id string) (domain.Plan, error)
+	// SavePlan crea o actualiza el plan; no pisa una fila con un EventoEn más nuevo
+	SavePlan(ctx context.Context, plan domain.Plan) error
+}
+
+// MySQLSuscripcionesRepository implementa SuscripcionesRepository usando MySQL/GORM
+type MySQLSuscripcionesRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLSuscripcionesRepository crea una nueva instancia del repository
+// Comparte la conexión DB con ActividadesRepository (tablas en BDD/02-init-activities.sql)
+func NewMySQLSuscripcionesRepository(db *gorm.DB) *MySQLSuscripcionesRepository {
+	return &MySQLSuscripcionesRepository{
+		db: db,
+	}
+}
+
+// ListByUsuario obtiene las suscripciones del usuario, la más reciente primero
+func (r *MySQLSuscripcionesRepository) ListByUsuario(ctx context.Context, usuarioID uint) ([]domain.Suscripcion, error) {
+	var suscripcionesDAO []dao.Suscripcion
+
+	err := r.db.WithContext(ctx).
+		Where("usuario_id = ?", usuarioID).
+		Order("fecha_inicio DESC").
+		Find(&suscripcionesDAO).Error
+	if err != nil {
+		return nil, fmt.Errorf("error listing suscripciones: %w", err)
+	}
+
+	suscripciones := make([]domain.Suscripcion, len(suscripcionesDAO))
+	for i, s := range suscripcionesDAO {
+		suscripciones[i] = s.ToDomain()
+	}
+	return suscripciones, nil
+}
+
+// GetSuscripcion obtiene una suscripción por su ID de subscriptions-api
+func (r *MySQLSuscripcionesRepository) GetSuscripcion(ctx context.Context, id string) (domain.Suscripcion, error) {
+	var suscripcionDAO dao.Suscripcion
+
+	err := r.db.WithContext(ctx).Where("id_suscripcion = ?", id).First(&suscripcionDAO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Suscripcion{}, domain.ErrSuscripcionNotFound
+		}
+		return domain.Suscripcion{}, fmt.Errorf("error getting suscripcion: %w", err)
+	}
+
+	return suscripcionDAO.ToDomain(), nil
+}
+
+// SaveSuscripcion hace el upsert de la suscripción
+func (r *MySQLSuscripcionesRepository) SaveSuscripcion(ctx context.Context, suscripcion domain.Suscripcion) error {
+	suscripcionDAO := dao.SuscripcionFromDomain(suscripcion)
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoUpdates: siEventoNuevo("usuario_id", "plan_id", "estado", "fecha_inicio", "fecha_vencimiento", "updated_at")}).
+		Create(&suscripcionDAO).Error
+	if err != nil {
+		return fmt.Errorf("error saving suscripcion: %w", err)
+	}
+
+	return nil
+}
+
+// DesactivarAusentes marca como inactivas las suscripciones que subscriptions-api ya no lista como activas
+func (r *MySQLSuscripcionesRepository) DesactivarAusentes(ctx context.Context, vigentes []string, antes time.Time) (int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&dao.Suscripcion{}).
+		Where("estado = ? AND evento_en < ?", domain.SuscripcionActiva, antes)
+	if len(vigentes) > 0 {
+		query = query.Where("id_suscripcion NOT IN ?", vigentes)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"estado":    domain.SuscripcionInactiva,
+		"evento_en": antes,
+	})
+	if result.Error != nil {
+		return 0, fmt.Errorf("error deactivating suscripciones: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// GetPlan obtiene un plan por su ID de subscriptions-api
+func (r *MySQLSuscripcionesRepository) GetPlan(ctx context.Context, id string) (domain.Plan, error) {
+	var planDAO dao.Plan
+
+	err := r.db.WithContext(ctx).Where("id_plan = ?", id).First(&planDAO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Plan{}, domain.ErrPlanNotFound
+		}
+		return domain.Plan{}, fmt.Errorf("error getting plan: %w", err)
+	}
+
+	return planDAO.ToDomain(), nil
+}
+
+// SavePlan hace el upsert del plan
+func (r *MySQLSuscripcionesRepository) SavePlan(ctx context.Context, plan domain.Plan) error {
+	planDAO := dao.PlanFromDomain(plan)
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoUpdates: siEventoNuevo("nombre", "tipo_acceso", "actividades_permitidas", "actividades_por_semana", "cupo_clases", "cupo_periodo", "activo", "updated_at")}).
+		Create(&planDAO).Error
+	if err != nil {
+		return fmt.Errorf("error saving plan: %w", err)
+	}
+
+	return nil
+}
+
+// siEventoNuevo arma el UPDATE del upsert: cada columna toma el valor nuevo solo si el evento
+// no es más viejo que el guardado. evento_en va última porque las demás comparan contra el valor anterior
+// (se usa clause.Set y no un map: GORM ordena los maps por nombre de columna)
+func siEventoNuevo(columnas ...string) clause.Set {
+	set := make(clause.Set, 0, len(columnas)+1)
+	for _, columna := range append(columnas, "evento_en") {
+		set = append(set, clause.Assignment{
+			Column: clause.Column{Name: columna},
+			Value:  gorm.Expr(fmt.Sprintf("IF(VALUES(evento_en) >= evento_en, VALUES(%s), %s)", columna, columna)),
+		})
+	}
+	return set
+}
@@ -4,8 +4,11 @@ import (
 	"activities-api/internal/domain"
 	"activities-api/internal/repository"
 	"context"
+	"errors"
 	"fmt"
 	"time"
+
+	"github.com/yourusername/gym-management/shared/auth"
 )
 
 // ActividadesService define la interfaz del servicio de actividades
@@ -16,19 +19,27 @@ type ActividadesService interface {
 	Create(ctx context.Context, actividadCreate domain.ActividadCreate) (domain.ActividadResponse, error)
 	Update(ctx context.Context, id uint, actividadUpdate domain.ActividadUpdate) (domain.ActividadResponse, error)
 	Delete(ctx context.Context, id uint) error
+	Importar(ctx context.Context, claims auth.Claims, filas []domain.FilaPlanilla, dryRun bool) (domain.ResultadoImportacion, error)
+	Exportar(ctx context.Context, sucursalID *uint) ([]domain.ActividadResponse, error)
 }
 
 // ActividadesServiceImpl implementa ActividadesService
 // Migrado de backend/services/actividad_service.go con dependency injection
 type ActividadesServiceImpl struct {
 	repository     repository.ActividadesRepository
+	sucursales     repository.SucursalesRepository
+	instructores   repository.InstructoresRepository
+	salas          repository.SalasRepository
 	eventPublisher EventPublisher
 }
 
 // NewActividadesService crea una nueva instancia del servicio
-func NewActividadesService(repo repository.ActividadesRepository, eventPublisher EventPublisher) *ActividadesServiceImpl {
+func NewActividadesService(repo repository.ActividadesRepository, sucursalesRepo repository.SucursalesRepository, instructoresRepo repository.InstructoresRepository, salasRepo repository.SalasRepository, eventPublisher EventPublisher) *ActividadesServiceImpl {
 	return &ActividadesServiceImpl{
 		repository:     repo,
+		sucursales:     sucursalesRepo,
+		instructores:   instructoresRepo,
+		salas:          salasRepo,
 		eventPublisher: eventPublisher,
 	}
 }
@@ -85,31 +96,26 @@ func (s *ActividadesServiceImpl) Create(ctx context.Context, actividadCreate dom
 	if err := s.validateBasicFields(actividadCreate); err != nil {
 		return domain.ActividadResponse{}, err
 	}
+	if err := s.validateSucursal(ctx, actividadCreate.SucursalID); err != nil {
+		return domain.ActividadResponse{}, err
+	}
 
 	// Parsear horarios
-	horaInicio, horaFin, err := s.parseHorarios(actividadCreate.HorarioInicio, actividadCreate.HorarioFinal)
+	horaInicio, horaFin, err := parseHorarios(actividadCreate.HorarioInicio, actividadCreate.HorarioFinal)
 	if err != nil {
 		return domain.ActividadResponse{}, err
 	}
 
-	// Aplicar valor por defecto para FotoUrl si está vacío
-	fotoUrl := actividadCreate.FotoUrl
-	if fotoUrl == "" {
-		fotoUrl = "https://via.placeholder.com/400x300?text=Sin+Imagen"
-	}
-
 	// Crear dominio
-	actividad := domain.Actividad{
-		Titulo:        actividadCreate.Titulo,
-		Descripcion:   actividadCreate.Descripcion,
-		Cupo:          actividadCreate.Cupo,
-		Dia:           actividadCreate.Dia,
-		HorarioInicio: actividadCreate.HorarioInicio,
-		HorarioFinal:  actividadCreate.HorarioFinal,
-		FotoUrl:       fotoUrl,
-		Instructor:    actividadCreate.Instructor,
-		Categoria:     actividadCreate.Categoria,
-		SucursalID:    actividadCreate.SucursalID,
+	actividad := nuevaActividad(actividadCreate)
+
+	// Con instructor_perfil_id: nombre y cuenta salen del perfil y no puede superponerse con otra clase suya
+	if err := asignarInstructor(ctx, s.instructores, &actividad, horaInicio, horaFin, 0); err != nil {
+		return domain.ActividadResponse{}, err
+	}
+	// Con sala: misma sucursal, cupo dentro de su capacidad y sin otra clase en la sala a esa hora
+	if err := asignarSala(ctx, s.salas, actividad, horaInicio, horaFin, 0); err != nil {
+		return domain.ActividadResponse{}, err
 	}
 
 	createdActividad, err := s.repository.Create(ctx, actividad, horaInicio, horaFin)
@@ -118,19 +124,8 @@ func (s *ActividadesServiceImpl) Create(ctx context.Context, actividadCreate dom
 	}
 
 	// Publicar evento a RabbitMQ con todos los campos para indexación directa
-	eventData := map[string]interface{}{
-		"titulo":          createdActividad.Titulo,
-		"descripcion":     createdActividad.Descripcion,
-		"categoria":       createdActividad.Categoria,
-		"dia":             createdActividad.Dia,
-		"instructor":      createdActividad.Instructor,
-		"horario_inicio":  createdActividad.HorarioInicio,
-		"horario_final":   createdActividad.HorarioFinal,
-		"sucursal_id":     createdActividad.SucursalID,
-		"sucursal_nombre": createdActividad.SucursalNombre,
-		"cupo_disponible": createdActividad.Cupo, // Al crear, cupo disponible = cupo total
-		"foto_url":        createdActividad.FotoUrl,
-	}
+	// Al crear, cupo disponible = cupo total
+	eventData := datosEventoActividad(createdActividad, createdActividad.Cupo)
 	if err := s.eventPublisher.PublishActivityEvent("create", fmt.Sprintf("%d", createdActividad.ID), eventData); err != nil {
 		// Log el error pero NO fallamos la creación (ya está creada)
 		fmt.Printf("⚠️  Error publicando evento activity.create: %v\n", err)
@@ -146,9 +141,12 @@ func (s *ActividadesServiceImpl) Update(ctx context.Context, id uint, actividadU
 	if err := s.validateBasicFieldsUpdate(actividadUpdate); err != nil {
 		return domain.ActividadResponse{}, err
 	}
+	if err := s.validateSucursal(ctx, actividadUpdate.SucursalID); err != nil {
+		return domain.ActividadResponse{}, err
+	}
 
 	// Parsear horarios
-	horaInicio, horaFin, err := s.parseHorarios(actividadUpdate.HorarioInicio, actividadUpdate.HorarioFinal)
+	horaInicio, horaFin, err := parseHorarios(actividadUpdate.HorarioInicio, actividadUpdate.HorarioFinal)
 	if err != nil {
 		return domain.ActividadResponse{}, err
 	}
@@ -161,16 +159,26 @@ func (s *ActividadesServiceImpl) Update(ctx context.Context, id uint, actividadU
 
 	// Crear dominio
 	actividad := domain.Actividad{
-		Titulo:        actividadUpdate.Titulo,
-		Descripcion:   actividadUpdate.Descripcion,
-		Cupo:          actividadUpdate.Cupo,
-		Dia:           actividadUpdate.Dia,
-		HorarioInicio: actividadUpdate.HorarioInicio,
-		HorarioFinal:  actividadUpdate.HorarioFinal,
-		FotoUrl:       fotoUrl,
-		Instructor:    actividadUpdate.Instructor,
-		Categoria:     actividadUpdate.Categoria,
-		SucursalID:    actividadUpdate.SucursalID,
+		Titulo:             actividadUpdate.Titulo,
+		Descripcion:        actividadUpdate.Descripcion,
+		Cupo:               actividadUpdate.Cupo,
+		Dia:                actividadUpdate.Dia,
+		HorarioInicio:      actividadUpdate.HorarioInicio,
+		HorarioFinal:       actividadUpdate.HorarioFinal,
+		FotoUrl:            fotoUrl,
+		Instructor:         actividadUpdate.Instructor,
+		InstructorID:       actividadUpdate.InstructorID,
+		InstructorPerfilID: actividadUpdate.InstructorPerfilID,
+		Categoria:          actividadUpdate.Categoria,
+		SucursalID:         actividadUpdate.SucursalID,
+		SalaID:             actividadUpdate.SalaID,
+	}
+
+	if err := asignarInstructor(ctx, s.instructores, &actividad, horaInicio, horaFin, id); err != nil {
+		return domain.ActividadResponse{}, err
+	}
+	if err := asignarSala(ctx, s.salas, actividad, horaInicio, horaFin, id); err != nil {
+		return domain.ActividadResponse{}, err
 	}
 
 	updatedActividad, err := s.repository.Update(ctx, id, actividad, horaInicio, horaFin)
@@ -179,19 +187,7 @@ func (s *ActividadesServiceImpl) Update(ctx context.Context, id uint, actividadU
 	}
 
 	// Publicar evento a RabbitMQ con todos los campos para indexación directa
-	eventData := map[string]interface{}{
-		"titulo":          updatedActividad.Titulo,
-		"descripcion":     updatedActividad.Descripcion,
-		"categoria":       updatedActividad.Categoria,
-		"dia":             updatedActividad.Dia,
-		"instructor":      updatedActividad.Instructor,
-		"horario_inicio":  updatedActividad.HorarioInicio,
-		"horario_final":   updatedActividad.HorarioFinal,
-		"sucursal_id":     updatedActividad.SucursalID,
-		"sucursal_nombre": updatedActividad.SucursalNombre,
-		"cupo_disponible": updatedActividad.CupoDisponible,
-		"foto_url":        updatedActividad.FotoUrl,
-	}
+	eventData := datosEventoActividad(updatedActividad, updatedActividad.CupoDisponible)
 	if err := s.eventPublisher.PublishActivityEvent("update", fmt.Sprintf("%d", updatedActividad.ID), eventData); err != nil {
 		// Log el error pero NO fallamos la actualización (ya está actualizada)
 		fmt.Printf("⚠️  Error publicando evento activity.update: %v\n", err)
@@ -219,6 +215,56 @@ func (s *ActividadesServiceImpl) Delete(ctx context.Context, id uint) error {
 	return nil
 }
 
+// nuevaActividad arma el dominio a partir de los datos de alta (foto por defecto si viene vacía)
+func nuevaActividad(actividadCreate domain.ActividadCreate) domain.Actividad {
+	// Aplicar valor por defecto para FotoUrl si está vacío
+	fotoUrl := actividadCreate.FotoUrl
+	if fotoUrl == "" {
+		fotoUrl = "https://via.placeholder.com/400x300?text=Sin+Imagen"
+	}
+
+	return domain.Actividad{
+		Titulo:             actividadCreate.Titulo,
+		Descripcion:        actividadCreate.Descripcion,
+		Cupo:               actividadCreate.Cupo,
+		Dia:                actividadCreate.Dia,
+		HorarioInicio:      actividadCreate.HorarioInicio,
+		HorarioFinal:       actividadCreate.HorarioFinal,
+		FotoUrl:            fotoUrl,
+		Instructor:         actividadCreate.Instructor,
+		InstructorID:       actividadCreate.InstructorID,
+		InstructorPerfilID: actividadCreate.InstructorPerfilID,
+		Categoria:          actividadCreate.Categoria,
+		SucursalID:         actividadCreate.SucursalID,
+		SalaID:             actividadCreate.SalaID,
+	}
+}
+
+// datosEventoActividad arma los datos de activity.create/activity.update con todos los campos para indexación directa
+func datosEventoActividad(actividad domain.Actividad, cupoDisponible uint) map[string]interface{} {
+	return map[string]interface{}{
+		"titulo":               actividad.Titulo,
+		"descripcion":          actividad.Descripcion,
+		"categoria":            actividad.Categoria,
+		"dia":                  actividad.Dia,
+		"instructor":           actividad.Instructor,
+		"instructor_id":        actividad.InstructorID,
+		"instructor_perfil_id": actividad.InstructorPerfilID,
+		"horario_inicio":       actividad.HorarioInicio,
+		"horario_final":        actividad.HorarioFinal,
+		"sucursal_id":          actividad.SucursalID,
+		"sala_id":              actividad.SalaID,
+		"sucursal_nombre":      actividad.SucursalNombre,
+		"cupo_disponible":      cupoDisponible,
+		"foto_url":             actividad.FotoUrl,
+		// Promedios de calificaciones (search-api puede ordenar por ellos)
+		"calificacion_promedio":            actividad.CalificacionPromedio,
+		"calificaciones":                   actividad.Calificaciones,
+		"instructor_calificacion_promedio": actividad.InstructorCalificacionPromedio,
+		"instructor_calificaciones":        actividad.InstructorCalificaciones,
+	}
+}
+
 // validateBasicFields valida los campos básicos para crear
 // Migrado de backend/services/actividad_service.go:32
 func (s *ActividadesServiceImpl) validateBasicFields(actividadCreate domain.ActividadCreate) error {
@@ -254,14 +300,27 @@ func (s *ActividadesServiceImpl) validateBasicFieldsUpdate(actividadUpdate domai
 	return nil
 }
 
+// validateSucursal verifica que la sucursal de la actividad exista y esté activa (sin sucursal es válido)
+func (s *ActividadesServiceImpl) validateSucursal(ctx context.Context, sucursalID *uint) error {
+	if sucursalID == nil {
+		return nil
+	}
+
+	if _, err := s.sucursales.GetByID(ctx, *sucursalID); err != nil {
+		if errors.Is(err, domain.ErrSucursalNotFound) {
+			return fmt.Errorf("%w (ID %d)", ErrSucursalInexistente, *sucursalID)
+		}
+		return fmt.Errorf("error validando la sucursal: %w", err)
+	}
+
+	return nil
+}
+
 // parseHorarios parsea horarios en formato "HH:MM" a time.Time
 // Migrado de backend/services/actividad_service.go:49
-func (s *ActividadesServiceImpl) parseHorarios(horaInicio, horaFin string) (time.Time, time.Time, error) {
-	// Obtener la zona horaria local
-	loc, err := time.LoadLocation("America/Argentina/Buenos_Aires")
-	if err != nil {
-		loc = time.Local // Si no se puede cargar, usar la zona horaria local del sistema
-	}
+func parseHorarios(horaInicio, horaFin string) (time.Time, time.Time, error) {
+	// Obtener la zona horaria del gimnasio
+	loc := gymLocation()
 
 	// Usar una fecha base (2024-01-01) para parsear las horas
 	fechaBase := "2024-01-01"
@@ -282,7 +341,3 @@ func (s *ActividadesServiceImpl) parseHorarios(horaInicio, horaFin string) (time
 
 	return inicio, fin, nil
 }
-
-// TODO: Los compañeros deben agregar:
-// - Publicación de eventos a RabbitMQ cuando se crea/actualiza/elimina actividad
-// - Validación de sucursal_id existe (HTTP call a activities-api sucursales endpoint cuando esté listo)
@@ -0,0 +1,447 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/gym-management/shared/auth"
+)
+
+var (
+	ErrPlanillaInvalida  = errors.New("planilla de horarios inválida")
+	ErrFormatoPlanilla   = errors.New("formato de planilla no soportado (csv o xlsx)")
+	ErrSinPermisoFila    = errors.New("no podés gestionar actividades de esta sucursal")
+	ErrActividadRepetida = errors.New("la actividad aparece en más de una fila")
+)
+
+// Formatos de la planilla de horarios
+const (
+	FormatoCSV  = "csv"
+	FormatoXLSX = "xlsx"
+)
+
+// maxFilasPlanilla limita las filas de una importación (sin contar la cabecera)
+const maxFilasPlanilla = 1000
+
+// columnasPlanilla son las columnas de la planilla, con los mismos nombres que el JSON de POST /actividades
+// id vacío = actividad nueva; con id se modifica esa actividad
+var columnasPlanilla = []string{
+	"id", "titulo", "descripcion", "categoria", "dia", "horario_inicio", "horario_final", "cupo",
+	"instructor", "instructor_perfil_id", "instructor_id", "sucursal_id", "sala_id", "foto_url",
+}
+
+// columnasObligatorias deben estar en la cabecera (el resto puede faltar)
+var columnasObligatorias = []string{"titulo", "categoria", "dia", "horario_inicio", "horario_final", "cupo"}
+
+// LeerPlanilla interpreta una planilla CSV (separada por coma o punto y coma) o XLSX
+// Los errores de cada fila quedan en FilaPlanilla.Error; solo falla si la planilla entera es ilegible
+func LeerPlanilla(data []byte, formato string) ([]domain.FilaPlanilla, error) {
+	var registros [][]string
+	var err error
+	switch formato {
+	case FormatoCSV:
+		registros, err = leerCSV(data)
+	case FormatoXLSX:
+		registros, err = leerXLSX(data, maxFilasPlanilla+1)
+	default:
+		return nil, ErrFormatoPlanilla
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPlanillaInvalida, err)
+	}
+	if len(registros) == 0 {
+		return nil, fmt.Errorf("%w: la planilla está vacía", ErrPlanillaInvalida)
+	}
+	if len(registros) > maxFilasPlanilla+1 {
+		return nil, fmt.Errorf("%w: la planilla supera las %d filas", ErrPlanillaInvalida, maxFilasPlanilla)
+	}
+
+	columnas, err := leerCabecera(registros[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPlanillaInvalida, err)
+	}
+
+	var filas []domain.FilaPlanilla
+	for i, registro := range registros[1:] {
+		valores := make(map[string]string, len(columnas))
+		vacia := true
+		for j, columna := range columnas {
+			if j < len(registro) && columna != "" {
+				valores[columna] = strings.TrimSpace(registro[j])
+				vacia = vacia && valores[columna] == ""
+			}
+		}
+		// Las filas en blanco (comunes al final de una planilla) se ignoran
+		if vacia {
+			continue
+		}
+		filas = append(filas, leerFila(i+2, valores))
+	}
+
+	return filas, nil
+}
+
+// leerCSV detecta el separador por la cabecera: Excel en español exporta con punto y coma
+func leerCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	cabecera := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		cabecera = data[:i]
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	if bytes.Count(cabecera, []byte(";")) > bytes.Count(cabecera, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	var registros [][]string
+	for {
+		registro, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(registros) > maxFilasPlanilla {
+			return nil, fmt.Errorf("la planilla supera las %d filas", maxFilasPlanilla)
+		}
+		registros = append(registros, registro)
+	}
+	return registros, nil
+}
+
+// leerCabecera devuelve el nombre normalizado de cada columna ("" para columnas sin título)
+func leerCabecera(cabecera []string) ([]string, error) {
+	conocidas := make(map[string]bool, len(columnasPlanilla))
+	for _, columna := range columnasPlanilla {
+		conocidas[columna] = true
+	}
+
+	columnas := make([]string, len(cabecera))
+	vistas := make(map[string]bool, len(cabecera))
+	for i, titulo := range cabecera {
+		columna := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(titulo)), " ", "_")
+		if columna == "" {
+			continue
+		}
+		if !conocidas[columna] {
+			return nil, fmt.Errorf("columna desconocida %q (columnas: %s)", titulo, strings.Join(columnasPlanilla, ", "))
+		}
+		if vistas[columna] {
+			return nil, fmt.Errorf("columna repetida %q", titulo)
+		}
+		vistas[columna] = true
+		columnas[i] = columna
+	}
+
+	for _, columna := range columnasObligatorias {
+		if !vistas[columna] {
+			return nil, fmt.Errorf("falta la columna %q", columna)
+		}
+	}
+
+	return columnas, nil
+}
+
+// leerFila arma la actividad de una fila; los números mal escritos quedan como error de la fila
+func leerFila(numero int, valores map[string]string) domain.FilaPlanilla {
+	fila := domain.FilaPlanilla{
+		Fila: numero,
+		Actividad: domain.ActividadCreate{
+			Titulo:        valores["titulo"],
+			Descripcion:   valores["descripcion"],
+			Categoria:     valores["categoria"],
+			Dia:           valores["dia"],
+			HorarioInicio: horaPlanilla(valores["horario_inicio"]),
+			HorarioFinal:  horaPlanilla(valores["horario_final"]),
+			Instructor:    valores["instructor"],
+			FotoUrl:       valores["foto_url"],
+		},
+	}
+
+	var errs []string
+	numeroColumna := func(columna string) *uint {
+		valor := valores[columna]
+		if valor == "" {
+			return nil
+		}
+		n, err := strconv.ParseUint(strings.TrimSuffix(valor, ".0"), 10, 32)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s debe ser un número entero positivo (%q)", columna, valor))
+			return nil
+		}
+		id := uint(n)
+		return &id
+	}
+
+	if id := numeroColumna("id"); id != nil {
+		fila.ID = *id
+	}
+	if cupo := numeroColumna("cupo"); cupo != nil {
+		fila.Actividad.Cupo = *cupo
+	}
+	fila.Actividad.InstructorPerfilID = numeroColumna("instructor_perfil_id")
+	fila.Actividad.InstructorID = numeroColumna("instructor_id")
+	fila.Actividad.SucursalID = numeroColumna("sucursal_id")
+	fila.Actividad.SalaID = numeroColumna("sala_id")
+
+	fila.Error = strings.Join(errs, "; ")
+	return fila
+}
+
+// horaPlanilla acepta "HH:MM" o la fracción de día con que Excel guarda las horas (0.375 = 09:00)
+func horaPlanilla(valor string) string {
+	fraccion, err := strconv.ParseFloat(valor, 64)
+	if err != nil || fraccion < 0 || fraccion >= 1 {
+		return valor
+	}
+	minutos := int(math.Round(fraccion * 24 * 60))
+	return fmt.Sprintf("%02d:%02d", minutos/60, minutos%60)
+}
+
+// EscribirPlanilla genera la planilla de horarios con las mismas columnas que acepta la importación
+func EscribirPlanilla(w io.Writer, formato string, actividades []domain.ActividadResponse) error {
+	registros := [][]string{columnasPlanilla}
+	for _, actividad := range actividades {
+		registros = append(registros, []string{
+			strconv.FormatUint(uint64(actividad.ID), 10),
+			actividad.Titulo,
+			actividad.Descripcion,
+			actividad.Categoria,
+			actividad.Dia,
+			actividad.HorarioInicio,
+			actividad.HorarioFinal,
+			strconv.FormatUint(uint64(actividad.Cupo), 10),
+			actividad.Instructor,
+			idPlanilla(actividad.InstructorPerfilID),
+			idPlanilla(actividad.InstructorID),
+			idPlanilla(actividad.SucursalID),
+			idPlanilla(actividad.SalaID),
+			actividad.FotoUrl,
+		})
+	}
+
+	switch formato {
+	case FormatoCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.WriteAll(registros); err != nil {
+			return fmt.Errorf("error escribiendo la planilla: %w", err)
+		}
+		return nil
+	case FormatoXLSX:
+		if err := escribirXLSX(w, "Horarios", registros); err != nil {
+			return fmt.Errorf("error escribiendo la planilla: %w", err)
+		}
+		return nil
+	default:
+		return ErrFormatoPlanilla
+	}
+}
+
+func idPlanilla(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+// Exportar devuelve el cronograma ordenado por sucursal, día de la semana (lunes primero) y horario
+// Con sucursalID solo las actividades de esa sucursal
+func (s *ActividadesServiceImpl) Exportar(ctx context.Context, sucursalID *uint) ([]domain.ActividadResponse, error) {
+	actividades, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cronograma := make([]domain.ActividadResponse, 0, len(actividades))
+	for _, actividad := range actividades {
+		if sucursalID != nil && (actividad.SucursalID == nil || *actividad.SucursalID != *sucursalID) {
+			continue
+		}
+		cronograma = append(cronograma, actividad)
+	}
+
+	ordenSucursal := func(id *uint) uint {
+		if id == nil {
+			return 0
+		}
+		return *id
+	}
+	ordenDia := func(dia string) int {
+		return (int(diasSemana[dia]) + 6) % 7
+	}
+	sort.SliceStable(cronograma, func(i, j int) bool {
+		a, b := cronograma[i], cronograma[j]
+		if ordenSucursal(a.SucursalID) != ordenSucursal(b.SucursalID) {
+			return ordenSucursal(a.SucursalID) < ordenSucursal(b.SucursalID)
+		}
+		if ordenDia(a.Dia) != ordenDia(b.Dia) {
+			return ordenDia(a.Dia) < ordenDia(b.Dia)
+		}
+		if a.HorarioInicio != b.HorarioInicio {
+			return a.HorarioInicio < b.HorarioInicio
+		}
+		return a.ID < b.ID
+	})
+
+	return cronograma, nil
+}
+
+// Importar valida cada fila con las mismas reglas que POST/PUT /actividades y guarda las válidas en una transacción
+// Con dryRun solo informa el resultado de cada fila. Publica activity.create/activity.update por fila guardada
+func (s *ActividadesServiceImpl) Importar(ctx context.Context, claims auth.Claims, filas []domain.FilaPlanilla, dryRun bool) (domain.ResultadoImportacion, error) {
+	resultado := domain.ResultadoImportacion{
+		DryRun: dryRun,
+		Total:  len(filas),
+		Filas:  make([]domain.ResultadoFilaImportacion, 0, len(filas)),
+	}
+
+	var cambios []domain.ActividadImportada
+	ids := make(map[uint]int)
+	for _, fila := range filas {
+		item := domain.ResultadoFilaImportacion{Fila: fila.Fila, ID: fila.ID, Accion: "create", Titulo: fila.Actividad.Titulo}
+		if fila.ID != 0 {
+			item.Accion = "update"
+		}
+
+		cambio, err := s.validarFila(ctx, claims, fila, ids, cambios)
+		if err != nil {
+			item.Error = err.Error()
+			resultado.Invalidas++
+		} else {
+			cambios = append(cambios, cambio)
+			resultado.Validas++
+		}
+		if fila.ID != 0 {
+			ids[fila.ID] = fila.Fila
+		}
+		resultado.Filas = append(resultado.Filas, item)
+	}
+
+	if dryRun || len(cambios) == 0 {
+		return resultado, nil
+	}
+
+	guardadas, err := s.repository.Importar(ctx, cambios)
+	if err != nil {
+		return domain.ResultadoImportacion{}, fmt.Errorf("error importando actividades: %w", err)
+	}
+
+	filaResultado := make(map[int]int, len(resultado.Filas))
+	for i, item := range resultado.Filas {
+		filaResultado[item.Fila] = i
+	}
+	for i, actividad := range guardadas {
+		accion, cupoDisponible := "update", actividad.CupoDisponible
+		if cambios[i].ID == 0 {
+			accion, cupoDisponible = "create", actividad.Cupo // Al crear, cupo disponible = cupo total
+			resultado.Creadas++
+		} else {
+			resultado.Actualizadas++
+		}
+		resultado.Filas[filaResultado[cambios[i].Fila]].ID = actividad.ID
+
+		if err := s.eventPublisher.PublishActivityEvent(accion, fmt.Sprintf("%d", actividad.ID), datosEventoActividad(actividad, cupoDisponible)); err != nil {
+			// Log el error pero NO fallamos la importación (ya está guardada)
+			fmt.Printf("⚠️  Error publicando evento activity.%s (fila %d): %v\n", accion, cambios[i].Fila, err)
+		}
+	}
+
+	return resultado, nil
+}
+
+// validarFila aplica a una fila las validaciones de Create/Update, más los choques con las filas ya aceptadas
+// ids son las actividades ya vistas en filas anteriores (id → fila)
+func (s *ActividadesServiceImpl) validarFila(ctx context.Context, claims auth.Claims, fila domain.FilaPlanilla, ids map[uint]int, aceptadas []domain.ActividadImportada) (domain.ActividadImportada, error) {
+	if fila.Error != "" {
+		return domain.ActividadImportada{}, errors.New(fila.Error)
+	}
+
+	if fila.ID != 0 {
+		if anterior, ok := ids[fila.ID]; ok {
+			return domain.ActividadImportada{}, fmt.Errorf("%w (id %d, fila %d)", ErrActividadRepetida, fila.ID, anterior)
+		}
+		existente, err := s.repository.GetByID(ctx, fila.ID)
+		if err != nil {
+			return domain.ActividadImportada{}, fmt.Errorf("la actividad %d no existe", fila.ID)
+		}
+		if !canManageActividades(claims, existente.SucursalID) {
+			return domain.ActividadImportada{}, ErrSinPermisoFila
+		}
+	}
+	if !canManageActividades(claims, fila.Actividad.SucursalID) {
+		return domain.ActividadImportada{}, ErrSinPermisoFila
+	}
+
+	if err := s.validateBasicFields(fila.Actividad); err != nil {
+		return domain.ActividadImportada{}, err
+	}
+	if _, ok := diasSemana[fila.Actividad.Dia]; !ok {
+		return domain.ActividadImportada{}, fmt.Errorf("%w: %q", ErrDiaInvalido, fila.Actividad.Dia)
+	}
+	if err := s.validateSucursal(ctx, fila.Actividad.SucursalID); err != nil {
+		return domain.ActividadImportada{}, err
+	}
+	horaInicio, horaFin, err := parseHorarios(fila.Actividad.HorarioInicio, fila.Actividad.HorarioFinal)
+	if err != nil {
+		return domain.ActividadImportada{}, err
+	}
+
+	// Horarios normalizados ("9:00" → "09:00") para comparar con las otras filas
+	inicio, fin := horaInicio.Format("15:04"), horaFin.Format("15:04")
+	actividad := nuevaActividad(fila.Actividad)
+	actividad.HorarioInicio, actividad.HorarioFinal = inicio, fin
+	if err := asignarInstructor(ctx, s.instructores, &actividad, horaInicio, horaFin, fila.ID); err != nil {
+		return domain.ActividadImportada{}, err
+	}
+	if err := asignarSala(ctx, s.salas, actividad, horaInicio, horaFin, fila.ID); err != nil {
+		return domain.ActividadImportada{}, err
+	}
+
+	// Las filas aceptadas todavía no están guardadas: los choques entre ellas se controlan acá
+	for _, otra := range aceptadas {
+		if otra.Actividad.Dia != actividad.Dia || !(otra.Actividad.HorarioInicio < fin && inicio < otra.Actividad.HorarioFinal) {
+			continue
+		}
+		if mismoID(otra.Actividad.SalaID, actividad.SalaID) {
+			return domain.ActividadImportada{}, fmt.Errorf("%w: %s (fila %d)", ErrSalaOcupada, otra.Actividad.Titulo, otra.Fila)
+		}
+		if mismoID(otra.Actividad.InstructorPerfilID, actividad.InstructorPerfilID) {
+			return domain.ActividadImportada{}, fmt.Errorf("%w: %s (fila %d)", ErrInstructorSuperpuesto, otra.Actividad.Titulo, otra.Fila)
+		}
+	}
+
+	return domain.ActividadImportada{
+		Fila:       fila.Fila,
+		ID:         fila.ID,
+		Actividad:  actividad,
+		HoraInicio: horaInicio,
+		HoraFin:    horaFin,
+	}, nil
+}
+
+// canManageActividades indica si claims tiene activities:manage en la sucursal (como canManageActividad del controller)
+// Las actividades sin sucursal solo las gestiona un owner
+func canManageActividades(claims auth.Claims, sucursalID *uint) bool {
+	if sucursalID == nil {
+		return claims.HasGlobalPermission(auth.PermActivitiesManage)
+	}
+	return claims.CanInBranch(auth.PermActivitiesManage, *sucursalID)
+}
+
+// mismoID indica si ambos IDs opcionales están asignados y son iguales
+func mismoID(a, b *uint) bool {
+	return a != nil && b != nil && *a == *b
+}
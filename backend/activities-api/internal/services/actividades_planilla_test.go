@@ -0,0 +1,213 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/gym-management/shared/auth"
+)
+
+// newPlanillaTestService arma el servicio con una actividad existente (ID 5, sucursal 1),
+// la sala 3 de la sucursal 1 y registra los eventos activity.* publicados
+func newPlanillaTestService() (*ActividadesServiceImpl, *MockActividadesRepository, *[]string) {
+	sucursales := newMockSucursalesRepository()
+	sucursales.sucursales[1] = domain.Sucursal{ID: 1, Nombre: "Centro", Activa: true}
+	sucursales.sucursales[2] = domain.Sucursal{ID: 2, Nombre: "Belgrano", Activa: true}
+
+	salas := newMockSalasRepository()
+	salas.salas[3] = domain.Sala{ID: 3, SucursalID: 1, Nombre: "Sala A", Capacidad: 20, Activa: true}
+
+	sucursal := uint(1)
+	repo := &MockActividadesRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (domain.Actividad, error) {
+			if id != 5 {
+				return domain.Actividad{}, errors.New("actividad not found")
+			}
+			return domain.Actividad{ID: 5, Titulo: "Yoga", SucursalID: &sucursal}, nil
+		},
+		ImportarFunc: func(ctx context.Context, cambios []domain.ActividadImportada) ([]domain.Actividad, error) {
+			guardadas := make([]domain.Actividad, len(cambios))
+			for i, cambio := range cambios {
+				guardadas[i] = cambio.Actividad
+				guardadas[i].ID = cambio.ID
+				if cambio.ID == 0 {
+					guardadas[i].ID = uint(100 + i)
+				}
+			}
+			return guardadas, nil
+		},
+	}
+
+	eventos := []string{}
+	publisher := &MockEventPublisher{
+		PublishActivityEventFunc: func(action, activityID string, data map[string]interface{}) error {
+			eventos = append(eventos, action+":"+activityID)
+			return nil
+		},
+	}
+
+	return NewActividadesService(repo, sucursales, newMockInstructoresRepository(), salas, publisher), repo, &eventos
+}
+
+const planillaCSV = "\xef\xbb\xbfid;titulo;categoria;dia;horario_inicio;horario_final;cupo;instructor;sucursal_id;sala_id\n" +
+	"5;Yoga;Relax;Lunes;9:00;10:00;15;Ana;1;3\n" +
+	";Spinning;Cardio;Lunes;0.416666667;11:00;18;Juan;1;3\n" +
+	";;;;;;;;;\n" +
+	";Pilates;Relax;Lunes;09:30;10:30;10;Ana;1;3\n" +
+	";Funcional;Fuerza;Feriado;18:00;19:00;diez;Ana;1;\n"
+
+// --- Tests ---
+
+func TestLeerPlanilla_CSV(t *testing.T) {
+	filas, err := LeerPlanilla([]byte(planillaCSV), FormatoCSV)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// La fila en blanco se ignora pero la numeración sigue la de la planilla
+	if len(filas) != 4 {
+		t.Fatalf("Expected 4 filas, got %d", len(filas))
+	}
+	if filas[0].Fila != 2 || filas[0].ID != 5 || *filas[0].Actividad.SalaID != 3 {
+		t.Errorf("Unexpected first fila: %+v", filas[0])
+	}
+	if filas[1].Actividad.HorarioInicio != "10:00" {
+		t.Errorf("Expected Excel time fraction as 10:00, got %q", filas[1].Actividad.HorarioInicio)
+	}
+	if filas[2].Fila != 5 {
+		t.Errorf("Expected blank row to keep numbering, got fila %d", filas[2].Fila)
+	}
+	if !strings.Contains(filas[3].Error, "cupo") {
+		t.Errorf("Expected cupo format error, got %q", filas[3].Error)
+	}
+}
+
+func TestLeerPlanilla_CabeceraInvalida(t *testing.T) {
+	casos := map[string]string{
+		"columna desconocida": "titulo,categoria,dia,horario_inicio,horario_final,cupo,profesor\n",
+		"falta columna":       "titulo,categoria,dia,horario_inicio,cupo\n",
+		"columna repetida":    "titulo,categoria,dia,horario_inicio,horario_final,cupo,Titulo\n",
+		"vacía":               "",
+	}
+	for nombre, csv := range casos {
+		if _, err := LeerPlanilla([]byte(csv), FormatoCSV); !errors.Is(err, ErrPlanillaInvalida) {
+			t.Errorf("%s: expected ErrPlanillaInvalida, got %v", nombre, err)
+		}
+	}
+
+	if _, err := LeerPlanilla([]byte("titulo\n"), "ods"); !errors.Is(err, ErrFormatoPlanilla) {
+		t.Errorf("Expected ErrFormatoPlanilla, got %v", err)
+	}
+}
+
+func TestPlanillaXLSX_IdaYVuelta(t *testing.T) {
+	sucursal, sala := uint(1), uint(3)
+	actividades := []domain.ActividadResponse{
+		{ID: 5, Titulo: "Yoga & Meditación", Categoria: "Relax", Dia: "Lunes", HorarioInicio: "09:00", HorarioFinal: "10:00",
+			Cupo: 15, Instructor: "Ana", SucursalID: &sucursal, SalaID: &sala},
+		{ID: 6, Titulo: "Spinning", Descripcion: "<intenso>", Categoria: "Cardio", Dia: "Martes", HorarioInicio: "18:00",
+			HorarioFinal: "19:00", Cupo: 20, Instructor: "Juan"},
+	}
+
+	var planilla bytes.Buffer
+	if err := EscribirPlanilla(&planilla, FormatoXLSX, actividades); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	filas, err := LeerPlanilla(planilla.Bytes(), FormatoXLSX)
+	if err != nil {
+		t.Fatalf("Expected no error reading exported XLSX, got %v", err)
+	}
+	if len(filas) != 2 {
+		t.Fatalf("Expected 2 filas, got %d", len(filas))
+	}
+	if filas[0].ID != 5 || filas[0].Actividad.Titulo != "Yoga & Meditación" || *filas[0].Actividad.SalaID != 3 {
+		t.Errorf("Unexpected first fila: %+v", filas[0])
+	}
+	if filas[1].Actividad.Descripcion != "<intenso>" || filas[1].Actividad.SucursalID != nil || filas[1].Actividad.Cupo != 20 {
+		t.Errorf("Unexpected second fila: %+v", filas[1])
+	}
+}
+
+func TestImportar_DryRunSoloInforma(t *testing.T) {
+	service, repo, eventos := newPlanillaTestService()
+	repo.ImportarFunc = func(ctx context.Context, cambios []domain.ActividadImportada) ([]domain.Actividad, error) {
+		t.Fatal("dry run must not save")
+		return nil, nil
+	}
+	filas, _ := LeerPlanilla([]byte(planillaCSV), FormatoCSV)
+
+	resultado, err := service.Importar(context.Background(), staffClaims(1, auth.RoleOwner), filas, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if resultado.Total != 4 || resultado.Validas != 2 || resultado.Invalidas != 2 {
+		t.Errorf("Unexpected totals: %+v", resultado)
+	}
+	// Pilates choca con Yoga (fila 2) en la misma sala; Funcional tiene el cupo mal escrito
+	if !strings.Contains(resultado.Filas[2].Error, "fila 2") || resultado.Filas[3].Error == "" {
+		t.Errorf("Expected errors on filas 5 and 6, got %+v", resultado.Filas)
+	}
+	if resultado.Filas[0].Accion != "update" || resultado.Filas[1].Accion != "create" {
+		t.Errorf("Unexpected acciones: %+v", resultado.Filas)
+	}
+	if len(*eventos) != 0 {
+		t.Errorf("Expected no events on dry run, got %v", *eventos)
+	}
+}
+
+func TestImportar_GuardaLasValidasYPublica(t *testing.T) {
+	service, repo, eventos := newPlanillaTestService()
+	var guardadas []domain.ActividadImportada
+	importar := repo.ImportarFunc
+	repo.ImportarFunc = func(ctx context.Context, cambios []domain.ActividadImportada) ([]domain.Actividad, error) {
+		guardadas = cambios
+		return importar(ctx, cambios)
+	}
+	filas, _ := LeerPlanilla([]byte(planillaCSV), FormatoCSV)
+
+	resultado, err := service.Importar(context.Background(), staffClaims(1, auth.RoleOwner), filas, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(guardadas) != 2 || guardadas[0].ID != 5 || guardadas[0].Actividad.HorarioInicio != "09:00" {
+		t.Fatalf("Expected the 2 valid filas saved together, got %+v", guardadas)
+	}
+	if resultado.Creadas != 1 || resultado.Actualizadas != 1 || resultado.Filas[1].ID != 101 {
+		t.Errorf("Unexpected resultado: %+v", resultado)
+	}
+	if strings.Join(*eventos, ",") != "update:5,create:101" {
+		t.Errorf("Expected activity.update and activity.create, got %v", *eventos)
+	}
+}
+
+func TestImportar_AlcancePorSucursal(t *testing.T) {
+	service, _, _ := newPlanillaTestService()
+	csv := "id,titulo,categoria,dia,horario_inicio,horario_final,cupo,instructor,sucursal_id\n" +
+		",Boxeo,Fuerza,Martes,18:00,19:00,10,Ana,2\n" +
+		"5,Yoga,Relax,Lunes,09:00,10:00,15,Ana,2\n" +
+		",Boxeo,Fuerza,Martes,19:00,20:00,10,Ana,1\n" +
+		"5,Yoga,Relax,Lunes,09:00,10:00,15,Ana,1\n" +
+		"99,Zumba,Cardio,Lunes,09:00,10:00,15,Ana,1\n"
+	filas, _ := LeerPlanilla([]byte(csv), FormatoCSV)
+
+	// El branch_manager de la sucursal 2 no puede crear en la 1 ni mover una actividad de la 1
+	resultado, err := service.Importar(context.Background(), staffClaims(1, auth.RoleBranchManager, 2), filas, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	esperados := []string{"", ErrSinPermisoFila.Error(), ErrSinPermisoFila.Error(), ErrActividadRepetida.Error(), "no existe"}
+	for i, esperado := range esperados {
+		got := resultado.Filas[i].Error
+		if (esperado == "" && got != "") || !strings.Contains(got, esperado) {
+			t.Errorf("fila %d: expected error %q, got %q", resultado.Filas[i].Fila, esperado, got)
+		}
+	}
+}
@@ -17,6 +17,7 @@ type MockActividadesRepository struct {
 	CreateFunc      func(ctx context.Context, actividad domain.Actividad, horaInicio, horaFin time.Time) (domain.Actividad, error)
 	UpdateFunc      func(ctx context.Context, id uint, actividad domain.Actividad, horaInicio, horaFin time.Time) (domain.Actividad, error)
 	DeleteFunc      func(ctx context.Context, id uint) error
+	ImportarFunc    func(ctx context.Context, cambios []domain.ActividadImportada) ([]domain.Actividad, error)
 }
 
 func (m *MockActividadesRepository) List(ctx context.Context) ([]domain.Actividad, error) {
@@ -37,10 +38,16 @@ func (m *MockActividadesRepository) Update(ctx context.Context, id uint, activid
 func (m *MockActividadesRepository) Delete(ctx context.Context, id uint) error {
 	return m.DeleteFunc(ctx, id)
 }
+func (m *MockActividadesRepository) Importar(ctx context.Context, cambios []domain.ActividadImportada) ([]domain.Actividad, error) {
+	return m.ImportarFunc(ctx, cambios)
+}
+func (m *MockActividadesRepository) InvalidateCache() {}
 
 type MockEventPublisher struct {
 	PublishActivityEventFunc    func(action, activityID string, data map[string]interface{}) error
 	PublishInscriptionEventFunc func(action, inscriptionID string, data map[string]interface{}) error
+	PublishAttendanceEventFunc  func(action, attendanceID string, data map[string]interface{}) error
+	PublishBranchEventFunc      func(action, branchID string, data map[string]interface{}) error
 }
 
 func (m *MockEventPublisher) PublishActivityEvent(action, activityID string, data map[string]interface{}) error {
@@ -55,6 +62,18 @@ func (m *MockEventPublisher) PublishInscriptionEvent(action, inscriptionID strin
 	}
 	return nil
 }
+func (m *MockEventPublisher) PublishAttendanceEvent(action, attendanceID string, data map[string]interface{}) error {
+	if m.PublishAttendanceEventFunc != nil {
+		return m.PublishAttendanceEventFunc(action, attendanceID, data)
+	}
+	return nil
+}
+func (m *MockEventPublisher) PublishBranchEvent(action, branchID string, data map[string]interface{}) error {
+	if m.PublishBranchEventFunc != nil {
+		return m.PublishBranchEventFunc(action, branchID, data)
+	}
+	return nil
+}
 
 // --- Tests ---
 
@@ -62,7 +81,7 @@ func TestCreateActividad_Success(t *testing.T) {
 	// Setup
 	mockRepo := &MockActividadesRepository{}
 	mockPublisher := &MockEventPublisher{}
-	service := NewActividadesService(mockRepo, mockPublisher)
+	service := NewActividadesService(mockRepo, newMockSucursalesRepository(), newMockInstructoresRepository(), newMockSalasRepository(), mockPublisher)
 
 	input := domain.ActividadCreate{
 		Titulo:        "Yoga",
@@ -115,7 +134,7 @@ func TestCreateActividad_Success(t *testing.T) {
 }
 
 func TestCreateActividad_ValidationError(t *testing.T) {
-	service := NewActividadesService(&MockActividadesRepository{}, &MockEventPublisher{})
+	service := NewActividadesService(&MockActividadesRepository{}, newMockSucursalesRepository(), newMockInstructoresRepository(), newMockSalasRepository(), &MockEventPublisher{})
 
 	// Case 1: Empty Title
 	input := domain.ActividadCreate{
@@ -146,7 +165,7 @@ func TestCreateActividad_ValidationError(t *testing.T) {
 
 func TestGetActividad_Found(t *testing.T) {
 	mockRepo := &MockActividadesRepository{}
-	service := NewActividadesService(mockRepo, &MockEventPublisher{})
+	service := NewActividadesService(mockRepo, newMockSucursalesRepository(), newMockInstructoresRepository(), newMockSalasRepository(), &MockEventPublisher{})
 
 	expectedID := uint(1)
 	expectedTitle := "Yoga"
@@ -176,7 +195,7 @@ func TestGetActividad_Found(t *testing.T) {
 
 func TestGetActividad_NotFound(t *testing.T) {
 	mockRepo := &MockActividadesRepository{}
-	service := NewActividadesService(mockRepo, &MockEventPublisher{})
+	service := NewActividadesService(mockRepo, newMockSucursalesRepository(), newMockInstructoresRepository(), newMockSalasRepository(), &MockEventPublisher{})
 
 	mockRepo.GetByIDFunc = func(ctx context.Context, id uint) (domain.Actividad, error) {
 		return domain.Actividad{}, errors.New("actividad not found")
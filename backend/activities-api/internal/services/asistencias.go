@@ -0,0 +1,394 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/yourusername/gym-management/shared/auth"
+)
+
+// Errores de asistencias
+var (
+	ErrCheckInForbidden           = errors.New("no podés registrar asistencias en esta sucursal")
+	ErrReporteAsistenciaForbidden = errors.New("no tenés permiso para ver la asistencia de esta actividad")
+	ErrSucursalRequerida          = errors.New("el socio no tiene una clase en este horario: indicá sucursal_id para registrar el acceso a sala libre")
+	ErrSesionFueraDeHorario       = errors.New("la sesión no está en horario de check-in")
+	ErrNoInscriptoEnSesion        = errors.New("el socio no está inscripto en esta sesión")
+	ErrSinPlanActivo              = errors.New("el socio no tiene un plan activo")
+)
+
+const (
+	// checkInAnticipacion es cuánto antes del inicio de la clase se puede hacer el check-in
+	checkInAnticipacion = 30 * time.Minute
+	// diasReporteDefault es el rango del reporte de asistencia cuando no se indica "desde"
+	diasReporteDefault = 30
+	// limiteHistorialAsistencias es la cantidad de asistencias que devuelve GET /asistencias
+	limiteHistorialAsistencias = 100
+)
+
+// SuscripcionActivaProvider consulta la suscripción activa de un socio
+// La implementa SuscripcionesServiceImpl (copia local de subscriptions-api)
+type SuscripcionActivaProvider interface {
+	ActiveSubscription(ctx context.Context, usuarioID uint, authToken string) (Subscription, error)
+}
+
+// PenalizadorAusentes suma strikes por las ausencias según la política de cancelación
+// La implementa PenalizacionesServiceImpl
+type PenalizadorAusentes interface {
+	PenalizarAusentes(ctx context.Context, desde, hasta time.Time) (int, error)
+}
+
+// AsistenciasService define la interfaz del servicio de asistencias
+type AsistenciasService interface {
+	GenerarQR(usuarioID uint) domain.CodigoQR
+	CheckIn(ctx context.Context, staff auth.Claims, req domain.CheckInRequest, authToken string) (domain.Asistencia, error)
+	ListByUser(ctx context.Context, usuarioID uint) ([]domain.Asistencia, error)
+	Reporte(ctx context.Context, actividadID uint, viewer auth.Claims, desde, hasta string) (domain.ReporteAsistencia, error)
+	MarcarAusentes(ctx context.Context) (int64, error)
+}
+
+// AsistenciasServiceImpl implementa AsistenciasService
+// El socio muestra un QR firmado que rota; el staff lo escanea y se registra la asistencia
+// a la clase que está por empezar (o en curso) o, si no tiene ninguna, el acceso a sala libre
+type AsistenciasServiceImpl struct {
+	asistenciasRepo   repository.AsistenciasRepository
+	inscripcionesRepo repository.InscripcionesRepository
+	sesionesRepo      repository.SesionesRepository
+	actividadesRepo   repository.ActividadesRepository
+	suscripciones     SuscripcionActivaProvider
+	penalizaciones    PenalizadorAusentes // Opcional: sin él las ausencias no suman strikes
+	qr                *CodigoQRSigner
+	eventPublisher    EventPublisher
+
+	diasAusentes int // Días hacia atrás que revisa el job de ausentes
+	now          func() time.Time
+}
+
+// NewAsistenciasService crea una nueva instancia del servicio
+func NewAsistenciasService(asistenciasRepo repository.AsistenciasRepository, inscripcionesRepo repository.InscripcionesRepository, sesionesRepo repository.SesionesRepository, actividadesRepo repository.ActividadesRepository, suscripciones SuscripcionActivaProvider, penalizaciones PenalizadorAusentes, qr *CodigoQRSigner, eventPublisher EventPublisher, diasAusentes int) *AsistenciasServiceImpl {
+	if diasAusentes <= 0 {
+		diasAusentes = 7
+	}
+
+	return &AsistenciasServiceImpl{
+		asistenciasRepo:   asistenciasRepo,
+		inscripcionesRepo: inscripcionesRepo,
+		sesionesRepo:      sesionesRepo,
+		actividadesRepo:   actividadesRepo,
+		suscripciones:     suscripciones,
+		penalizaciones:    penalizaciones,
+		qr:                qr,
+		eventPublisher:    eventPublisher,
+		diasAusentes:      diasAusentes,
+		now:               time.Now,
+	}
+}
+
+// GenerarQR devuelve el QR vigente del socio
+func (s *AsistenciasServiceImpl) GenerarQR(usuarioID uint) domain.CodigoQR {
+	payload, expiraEn := s.qr.Generate(usuarioID)
+	return domain.CodigoQR{QR: payload, ExpiraEn: expiraEn}
+}
+
+// CheckIn valida el QR del socio y registra su asistencia
+// Clase: la sesión indicada o la del día en la que está inscripto y que está en horario
+// Sala libre: sin clase en horario, con suscripción activa y en la sucursal indicada
+func (s *AsistenciasServiceImpl) CheckIn(ctx context.Context, staff auth.Claims, req domain.CheckInRequest, authToken string) (domain.Asistencia, error) {
+	usuarioID, err := s.qr.Verify(req.QR)
+	if err != nil {
+		return domain.Asistencia{}, err
+	}
+
+	now := s.now().In(gymLocation())
+
+	var asistencia domain.Asistencia
+	sesion, ok, err := s.sesionParaCheckIn(ctx, usuarioID, req, now)
+	if err != nil {
+		return domain.Asistencia{}, err
+	}
+
+	if ok {
+		if !canAttendance(staff, auth.PermAttendanceCheckIn, sesion.SucursalID) {
+			return domain.Asistencia{}, ErrCheckInForbidden
+		}
+		asistencia = domain.Asistencia{
+			UsuarioID:   usuarioID,
+			ActividadID: &sesion.ActividadID,
+			SesionID:    &sesion.ID,
+			SucursalID:  sesion.SucursalID,
+			Tipo:        domain.AsistenciaClase,
+			Fecha:       sesion.Fecha,
+		}
+	} else {
+		if req.SucursalID == nil {
+			return domain.Asistencia{}, ErrSucursalRequerida
+		}
+		if !staff.CanInBranch(auth.PermAttendanceCheckIn, *req.SucursalID) {
+			return domain.Asistencia{}, ErrCheckInForbidden
+		}
+
+		httpCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		if _, err := s.suscripciones.ActiveSubscription(httpCtx, usuarioID, authToken); err != nil {
+			return domain.Asistencia{}, fmt.Errorf("%w: %v", ErrSinPlanActivo, err)
+		}
+
+		asistencia = domain.Asistencia{
+			UsuarioID:  usuarioID,
+			SucursalID: req.SucursalID,
+			Tipo:       domain.AsistenciaLibre,
+			Fecha:      now.Format("2006-01-02"),
+		}
+	}
+
+	if staffID, err := staff.UserID(); err == nil {
+		asistencia.RegistradaPor = &staffID
+	}
+	asistencia.Estado = domain.AsistenciaPresente
+
+	created, err := s.asistenciasRepo.Create(ctx, asistencia)
+	if err != nil {
+		return domain.Asistencia{}, err
+	}
+
+	eventData := map[string]interface{}{
+		"usuario_id":     created.UsuarioID,
+		"tipo":           created.Tipo,
+		"actividad_id":   created.ActividadID,
+		"sesion_id":      created.SesionID,
+		"sucursal_id":    created.SucursalID,
+		"registrada_por": created.RegistradaPor,
+		"fecha":          created.Fecha,
+	}
+	if err := s.eventPublisher.PublishAttendanceEvent("checked_in", fmt.Sprintf("%d", created.ID), eventData); err != nil {
+		// Log el error pero NO fallamos el check-in (ya está registrado)
+		fmt.Printf("⚠️  Error publicando evento attendance.checked_in: %v\n", err)
+	}
+
+	return created, nil
+}
+
+// sesionParaCheckIn busca la sesión a registrar; devuelve false si el socio no tiene ninguna en horario
+// Con sesion_id la sesión tiene que estar en horario y el socio inscripto (fija o reserva)
+// Con sucursal_id se ignoran las clases de otras sucursales
+func (s *AsistenciasServiceImpl) sesionParaCheckIn(ctx context.Context, usuarioID uint, req domain.CheckInRequest, now time.Time) (domain.Sesion, bool, error) {
+	inscripciones, err := s.inscripcionesRepo.ListByUser(ctx, usuarioID)
+	if err != nil {
+		return domain.Sesion{}, false, fmt.Errorf("error listing inscripciones: %w", err)
+	}
+
+	if req.SesionID != nil {
+		sesion, err := s.sesionesRepo.GetByID(ctx, *req.SesionID)
+		if err != nil {
+			return domain.Sesion{}, false, err
+		}
+		if sesion.Cancelada() {
+			return domain.Sesion{}, false, ErrSesionCancelada
+		}
+		if !enHorarioCheckIn(sesion, now) {
+			return domain.Sesion{}, false, ErrSesionFueraDeHorario
+		}
+		if !inscriptoEnSesion(inscripciones, sesion) {
+			return domain.Sesion{}, false, ErrNoInscriptoEnSesion
+		}
+		return sesion, true, nil
+	}
+
+	// Las sesiones de hoy de cada actividad en la que está inscripto (la fija cubre todas)
+	hoy := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var elegida *domain.Sesion
+	consultadas := make(map[uint]bool)
+	for _, insc := range inscripciones {
+		if !insc.IsActiva || consultadas[insc.ActividadID] {
+			continue
+		}
+		consultadas[insc.ActividadID] = true
+
+		sesiones, err := s.sesionesRepo.ListByActividad(ctx, insc.ActividadID, hoy, hoy)
+		if err != nil {
+			return domain.Sesion{}, false, err
+		}
+		for i := range sesiones {
+			sesion := sesiones[i]
+			if sesion.Cancelada() || !enHorarioCheckIn(sesion, now) || !inscriptoEnSesion(inscripciones, sesion) {
+				continue
+			}
+			if req.SucursalID != nil && sesion.SucursalID != nil && *sesion.SucursalID != *req.SucursalID {
+				continue
+			}
+			if elegida == nil || sesionInicio(sesion).Before(sesionInicio(*elegida)) {
+				elegida = &sesion
+			}
+		}
+	}
+
+	if elegida == nil {
+		return domain.Sesion{}, false, nil
+	}
+	return *elegida, true, nil
+}
+
+// enHorarioCheckIn indica si "now" está entre 30 minutos antes del inicio y el final de la sesión
+func enHorarioCheckIn(sesion domain.Sesion, now time.Time) bool {
+	inicio := sesionInicio(sesion)
+	if inicio.IsZero() {
+		return false
+	}
+	return !now.Before(inicio.Add(-checkInAnticipacion)) && !now.After(sesionFin(sesion))
+}
+
+// inscriptoEnSesion indica si alguna inscripción activa cubre la sesión (fija de la actividad o reserva)
+func inscriptoEnSesion(inscripciones []domain.Inscripcion, sesion domain.Sesion) bool {
+	for _, insc := range inscripciones {
+		if !insc.IsActiva || insc.ActividadID != sesion.ActividadID {
+			continue
+		}
+		if insc.SesionID == nil || *insc.SesionID == sesion.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// canAttendance aplica el alcance por sucursal de los permisos de asistencia
+// Un recurso sin sucursal solo lo gestiona un owner
+func canAttendance(claims auth.Claims, perm auth.Permission, sucursalID *uint) bool {
+	if sucursalID == nil {
+		return claims.HasGlobalPermission(perm)
+	}
+	return claims.CanInBranch(perm, *sucursalID)
+}
+
+// ListByUser lista las últimas asistencias del socio
+func (s *AsistenciasServiceImpl) ListByUser(ctx context.Context, usuarioID uint) ([]domain.Asistencia, error) {
+	return s.asistenciasRepo.ListByUser(ctx, usuarioID, limiteHistorialAsistencias)
+}
+
+// Reporte arma la asistencia por sesión de una actividad entre dos fechas "YYYY-MM-DD" (inclusive)
+// Por defecto los últimos 30 días hasta hoy; attendance:read en la sucursal de la actividad
+func (s *AsistenciasServiceImpl) Reporte(ctx context.Context, actividadID uint, viewer auth.Claims, desdeParam, hastaParam string) (domain.ReporteAsistencia, error) {
+	actividad, err := s.actividadesRepo.GetByID(ctx, actividadID)
+	if err != nil {
+		return domain.ReporteAsistencia{}, err
+	}
+	if !canAttendance(viewer, auth.PermAttendanceRead, actividad.SucursalID) {
+		return domain.ReporteAsistencia{}, ErrReporteAsistenciaForbidden
+	}
+
+	desde, hasta, err := s.parseRangoReporte(desdeParam, hastaParam)
+	if err != nil {
+		return domain.ReporteAsistencia{}, err
+	}
+
+	sesiones, err := s.asistenciasRepo.ResumenPorSesion(ctx, actividadID, desde, hasta)
+	if err != nil {
+		return domain.ReporteAsistencia{}, err
+	}
+
+	reporte := domain.ReporteAsistencia{
+		ActividadID: actividad.ID,
+		Titulo:      actividad.Titulo,
+		Desde:       desde.Format("2006-01-02"),
+		Hasta:       hasta.Format("2006-01-02"),
+		Sesiones:    sesiones,
+	}
+	for i := range reporte.Sesiones {
+		sesion := &reporte.Sesiones[i]
+		sesion.Tasa = tasaAsistencia(sesion.Presentes, sesion.Ausentes)
+		reporte.Presentes += sesion.Presentes
+		reporte.Ausentes += sesion.Ausentes
+	}
+	reporte.Tasa = tasaAsistencia(reporte.Presentes, reporte.Ausentes)
+
+	return reporte, nil
+}
+
+// parseRangoReporte valida el rango del reporte (mismo límite de 92 días que las sesiones)
+func (s *AsistenciasServiceImpl) parseRangoReporte(desdeParam, hastaParam string) (time.Time, time.Time, error) {
+	loc := gymLocation()
+	now := s.now().In(loc)
+
+	hasta := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if hastaParam != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", hastaParam, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrFechaInvalida
+		}
+		hasta = parsed
+	}
+
+	desde := hasta.AddDate(0, 0, -diasReporteDefault)
+	if desdeParam != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", desdeParam, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrFechaInvalida
+		}
+		desde = parsed
+	}
+
+	if hasta.Before(desde) || hasta.After(desde.AddDate(0, 0, maxDiasRangoSesiones)) {
+		return time.Time{}, time.Time{}, ErrRangoSesiones
+	}
+
+	return desde, hasta, nil
+}
+
+// tasaAsistencia devuelve presentes / (presentes + ausentes) redondeada a 4 decimales (0.8333 = 83,33%)
+func tasaAsistencia(presentes, ausentes int) float64 {
+	total := presentes + ausentes
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(presentes)/float64(total)*10000) / 10000
+}
+
+// MarcarAusentes marca como ausentes a los inscriptos sin check-in de las sesiones de los últimos días
+// y suma los strikes por ausencia que correspondan según la política de cancelación
+// Solo revisa días cerrados (hasta ayer), así que correrlo varias veces no cambia el resultado
+func (s *AsistenciasServiceImpl) MarcarAusentes(ctx context.Context) (int64, error) {
+	now := s.now().In(gymLocation())
+	hoy := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	desde := hoy.AddDate(0, 0, -s.diasAusentes)
+
+	marcadas, err := s.asistenciasRepo.MarcarAusentes(ctx, desde, hoy)
+	if err != nil {
+		return 0, err
+	}
+
+	// Recorre todas las ausencias del rango sin strike: también reintenta las de corridas que fallaron
+	if s.penalizaciones != nil {
+		strikes, err := s.penalizaciones.PenalizarAusentes(ctx, desde, hoy)
+		if err != nil {
+			log.Printf("⚠️  No se pudieron registrar los strikes por ausencia: %v", err)
+		} else if strikes > 0 {
+			log.Printf("🟥 Strikes por ausencia registrados: %d", strikes)
+		}
+	}
+
+	return marcadas, nil
+}
+
+// NewAusentesJob crea el job que marca las ausencias de los días cerrados cada "interval"
+// Corre cada hora: la primera ejecución después de medianoche cierra el día anterior
+func NewAusentesJob(service AsistenciasService, interval time.Duration) *PeriodicJob {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return NewPeriodicJob(interval, func() {
+		marcadas, err := service.MarcarAusentes(context.Background())
+		if err != nil {
+			log.Printf("⚠️  No se pudieron marcar las ausencias: %v", err)
+			return
+		}
+		if marcadas > 0 {
+			log.Printf("🚫 Ausencias marcadas: %d", marcadas)
+		}
+	})
+}
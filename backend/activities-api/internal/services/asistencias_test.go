@@ -0,0 +1,301 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yourusername/gym-management/shared/auth"
+)
+
+// --- Manual Mocks ---
+
+// MockAsistenciasRepository guarda las asistencias en memoria con la misma clave única que MySQL
+type MockAsistenciasRepository struct {
+	asistencias          []domain.Asistencia
+	ResumenPorSesionFunc func(ctx context.Context, actividadID uint, desde, hasta time.Time) ([]domain.AsistenciaSesion, error)
+	MarcarAusentesFunc   func(ctx context.Context, desde, hasta time.Time) (int64, error)
+}
+
+func (m *MockAsistenciasRepository) Create(ctx context.Context, asistencia domain.Asistencia) (domain.Asistencia, error) {
+	for _, existing := range m.asistencias {
+		if existing.UsuarioID == asistencia.UsuarioID && sesionClave(existing) == sesionClave(asistencia) && existing.Fecha == asistencia.Fecha {
+			return domain.Asistencia{}, domain.ErrAsistenciaYaRegistrada
+		}
+	}
+	asistencia.ID = uint(len(m.asistencias) + 1)
+	m.asistencias = append(m.asistencias, asistencia)
+	return asistencia, nil
+}
+func (m *MockAsistenciasRepository) ListByUser(ctx context.Context, usuarioID uint, limit int) ([]domain.Asistencia, error) {
+	var result []domain.Asistencia
+	for _, asistencia := range m.asistencias {
+		if asistencia.UsuarioID == usuarioID {
+			result = append(result, asistencia)
+		}
+	}
+	return result, nil
+}
+func (m *MockAsistenciasRepository) ResumenPorSesion(ctx context.Context, actividadID uint, desde, hasta time.Time) ([]domain.AsistenciaSesion, error) {
+	return m.ResumenPorSesionFunc(ctx, actividadID, desde, hasta)
+}
+func (m *MockAsistenciasRepository) MarcarAusentes(ctx context.Context, desde, hasta time.Time) (int64, error) {
+	return m.MarcarAusentesFunc(ctx, desde, hasta)
+}
+
+// sesionClave replica la columna generada de asistencias (sesion_id o 0)
+func sesionClave(asistencia domain.Asistencia) uint {
+	if asistencia.SesionID == nil {
+		return 0
+	}
+	return *asistencia.SesionID
+}
+
+type MockSuscripcionActiva struct {
+	ActiveSubscriptionFunc func(ctx context.Context, usuarioID uint, authToken string) (Subscription, error)
+}
+
+func (m *MockSuscripcionActiva) ActiveSubscription(ctx context.Context, usuarioID uint, authToken string) (Subscription, error) {
+	return m.ActiveSubscriptionFunc(ctx, usuarioID, authToken)
+}
+
+// asistenciasTest agrupa el servicio y sus mocks
+type asistenciasTest struct {
+	service       *AsistenciasServiceImpl
+	repo          *MockAsistenciasRepository
+	inscripciones *[]domain.Inscripcion
+	eventos       *[]string
+	now           *time.Time
+}
+
+// newAsistenciasTestService arma el servicio con "ahora" fijo el martes 7/1/2025 a las 18:40
+// La sesión 1 es el spinning de la sucursal 2 de ese día, de 19:00 a 20:00
+func newAsistenciasTestService() asistenciasTest {
+	sucursal := uint(2)
+	sesionesRepo := newMockSesionesRepository()
+	sesionesRepo.sesiones[1] = domain.Sesion{
+		ID: 1, ActividadID: 7, SucursalID: &sucursal, Fecha: "2025-01-07",
+		HorarioInicio: "19:00", HorarioFinal: "20:00", Cupo: 20, Estado: domain.SesionProgramada,
+	}
+	sesionesRepo.nextID = 1
+
+	inscripciones := []domain.Inscripcion{}
+	inscripcionesRepo := &MockInscripcionesRepository{
+		ListByUserFunc: func(ctx context.Context, usuarioID uint) ([]domain.Inscripcion, error) {
+			var result []domain.Inscripcion
+			for _, insc := range inscripciones {
+				if insc.UsuarioID == usuarioID {
+					result = append(result, insc)
+				}
+			}
+			return result, nil
+		},
+	}
+	actividadesRepo := &MockActividadesRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (domain.Actividad, error) {
+			return domain.Actividad{ID: id, Titulo: "Spinning", SucursalID: &sucursal}, nil
+		},
+	}
+	suscripciones := &MockSuscripcionActiva{
+		ActiveSubscriptionFunc: func(ctx context.Context, usuarioID uint, authToken string) (Subscription, error) {
+			if usuarioID != 5 {
+				return Subscription{}, errors.New("debe tener un plan para inscribirse a esta actividad")
+			}
+			return Subscription{ID: "sub-5", Status: "activa"}, nil
+		},
+	}
+
+	var eventos []string
+	publisher := &MockEventPublisher{
+		PublishAttendanceEventFunc: func(action, attendanceID string, data map[string]interface{}) error {
+			eventos = append(eventos, fmt.Sprintf("attendance.%s:%v", action, data["tipo"]))
+			return nil
+		},
+	}
+
+	now := time.Date(2025, 1, 7, 18, 40, 0, 0, gymLocation())
+	clock := func() time.Time { return now }
+
+	qr := NewCodigoQRSigner("secreto-de-prueba", 30*time.Second)
+	qr.now = clock
+
+	repo := &MockAsistenciasRepository{}
+	service := NewAsistenciasService(repo, inscripcionesRepo, sesionesRepo, actividadesRepo, suscripciones, nil, qr, publisher, 0)
+	service.now = clock
+
+	return asistenciasTest{
+		service:       service,
+		repo:          repo,
+		inscripciones: &inscripciones,
+		eventos:       &eventos,
+		now:           &now,
+	}
+}
+
+// --- Tests ---
+
+func TestCodigoQR_RotatesAndExpires(t *testing.T) {
+	now := time.Date(2025, 1, 7, 18, 40, 10, 0, gymLocation())
+	signer := NewCodigoQRSigner("secreto", 30*time.Second)
+	signer.now = func() time.Time { return now }
+
+	payload, expiraEn := signer.Generate(5)
+	if !expiraEn.After(now) || expiraEn.Sub(now) > 30*time.Second {
+		t.Errorf("Expected expiry within the current period, got %v", expiraEn)
+	}
+	if usuarioID, err := signer.Verify(payload); err != nil || usuarioID != 5 {
+		t.Fatalf("Expected user 5, got %d (%v)", usuarioID, err)
+	}
+
+	// Vale en el período siguiente (margen para el escaneo), no después
+	now = now.Add(30 * time.Second)
+	if _, err := signer.Verify(payload); err != nil {
+		t.Errorf("Expected payload valid in the next period, got %v", err)
+	}
+	now = now.Add(30 * time.Second)
+	if _, err := signer.Verify(payload); !errors.Is(err, ErrQRVencido) {
+		t.Errorf("Expected ErrQRVencido, got %v", err)
+	}
+
+	// Cambiar el usuario o firmar con otro secreto invalida el QR
+	fresh, _ := signer.Generate(5)
+	otro := NewCodigoQRSigner("otro-secreto", 30*time.Second)
+	otro.now = signer.now
+	ajeno, _ := otro.Generate(5)
+	for _, invalido := range []string{"GYM1.6" + fresh[len("GYM1.5"):], ajeno, "GYM1.5.abc.firma", "cualquier cosa"} {
+		if _, err := signer.Verify(invalido); !errors.Is(err, ErrQRInvalido) {
+			t.Errorf("Expected ErrQRInvalido for %q, got %v", invalido, err)
+		}
+	}
+}
+
+func TestCheckIn_ClassSession(t *testing.T) {
+	test := newAsistenciasTestService()
+	*test.inscripciones = append(*test.inscripciones, domain.Inscripcion{ID: 1, UsuarioID: 5, ActividadID: 7, IsActiva: true})
+	qr := test.service.GenerarQR(5).QR
+
+	// Recepcionista de otra sucursal
+	if _, err := test.service.CheckIn(context.Background(), staffClaims(10, auth.RoleReceptionist, 3), domain.CheckInRequest{QR: qr}, ""); !errors.Is(err, ErrCheckInForbidden) {
+		t.Errorf("Expected ErrCheckInForbidden, got %v", err)
+	}
+
+	staff := staffClaims(10, auth.RoleReceptionist, 2)
+	asistencia, err := test.service.CheckIn(context.Background(), staff, domain.CheckInRequest{QR: qr}, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if asistencia.Tipo != domain.AsistenciaClase || asistencia.SesionID == nil || *asistencia.SesionID != 1 ||
+		asistencia.Fecha != "2025-01-07" || asistencia.RegistradaPor == nil || *asistencia.RegistradaPor != 10 {
+		t.Errorf("Expected class attendance for session 1 registered by staff 10, got %+v", asistencia)
+	}
+	if len(*test.eventos) != 1 || (*test.eventos)[0] != "attendance.checked_in:clase" {
+		t.Errorf("Expected attendance.checked_in event, got %v", *test.eventos)
+	}
+
+	if _, err := test.service.CheckIn(context.Background(), staff, domain.CheckInRequest{QR: qr}, ""); !errors.Is(err, domain.ErrAsistenciaYaRegistrada) {
+		t.Errorf("Expected ErrAsistenciaYaRegistrada, got %v", err)
+	}
+}
+
+func TestCheckIn_ExplicitSession(t *testing.T) {
+	test := newAsistenciasTestService()
+	staff := staffClaims(10, auth.RoleReceptionist, 2)
+	sesionID := uint(1)
+
+	// No está inscripto en la sesión
+	req := domain.CheckInRequest{QR: test.service.GenerarQR(5).QR, SesionID: &sesionID}
+	if _, err := test.service.CheckIn(context.Background(), staff, req, ""); !errors.Is(err, ErrNoInscriptoEnSesion) {
+		t.Errorf("Expected ErrNoInscriptoEnSesion, got %v", err)
+	}
+
+	// Reserva de la sesión, pero una hora antes de la ventana de check-in
+	*test.inscripciones = append(*test.inscripciones, domain.Inscripcion{ID: 1, UsuarioID: 5, ActividadID: 7, SesionID: &sesionID, IsActiva: true})
+	*test.now = test.now.Add(-time.Hour)
+	req.QR = test.service.GenerarQR(5).QR
+	if _, err := test.service.CheckIn(context.Background(), staff, req, ""); !errors.Is(err, ErrSesionFueraDeHorario) {
+		t.Errorf("Expected ErrSesionFueraDeHorario, got %v", err)
+	}
+
+	// Durante la clase
+	*test.now = test.now.Add(2 * time.Hour)
+	req.QR = test.service.GenerarQR(5).QR
+	if _, err := test.service.CheckIn(context.Background(), staff, req, ""); err != nil {
+		t.Errorf("Expected check-in during the class, got %v", err)
+	}
+}
+
+func TestCheckIn_OpenGym(t *testing.T) {
+	test := newAsistenciasTestService()
+	staff := staffClaims(10, auth.RoleReceptionist, 2)
+	sucursal := uint(2)
+
+	// Sin clase en horario hay que indicar la sucursal
+	if _, err := test.service.CheckIn(context.Background(), staff, domain.CheckInRequest{QR: test.service.GenerarQR(5).QR}, ""); !errors.Is(err, ErrSucursalRequerida) {
+		t.Errorf("Expected ErrSucursalRequerida, got %v", err)
+	}
+
+	// Sin suscripción activa no entra
+	req := domain.CheckInRequest{QR: test.service.GenerarQR(6).QR, SucursalID: &sucursal}
+	if _, err := test.service.CheckIn(context.Background(), staff, req, ""); !errors.Is(err, ErrSinPlanActivo) {
+		t.Errorf("Expected ErrSinPlanActivo, got %v", err)
+	}
+
+	req.QR = test.service.GenerarQR(5).QR
+	asistencia, err := test.service.CheckIn(context.Background(), staff, req, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if asistencia.Tipo != domain.AsistenciaLibre || asistencia.SesionID != nil || *asistencia.SucursalID != 2 {
+		t.Errorf("Expected open gym attendance at branch 2, got %+v", asistencia)
+	}
+}
+
+func TestReporte_RatesAndScope(t *testing.T) {
+	test := newAsistenciasTestService()
+	test.repo.ResumenPorSesionFunc = func(ctx context.Context, actividadID uint, desde, hasta time.Time) ([]domain.AsistenciaSesion, error) {
+		if desde.Format("2006-01-02") != "2024-12-08" || hasta.Format("2006-01-02") != "2025-01-07" {
+			t.Errorf("Expected default range of the last 30 days, got %s - %s", desde, hasta)
+		}
+		return []domain.AsistenciaSesion{
+			{SesionID: 1, Fecha: "2024-12-31", Presentes: 8, Ausentes: 2},
+			{SesionID: 2, Fecha: "2025-01-07", Presentes: 2, Ausentes: 4},
+			{SesionID: 3, Fecha: "2025-01-14"},
+		}, nil
+	}
+
+	if _, err := test.service.Reporte(context.Background(), 7, staffClaims(10, auth.RoleReceptionist, 2), "", ""); !errors.Is(err, ErrReporteAsistenciaForbidden) {
+		t.Errorf("Expected receptionist without attendance:read to be forbidden, got %v", err)
+	}
+
+	reporte, err := test.service.Reporte(context.Background(), 7, staffClaims(11, auth.RoleBranchManager, 2), "", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if reporte.Presentes != 10 || reporte.Ausentes != 6 || reporte.Tasa != 0.625 {
+		t.Errorf("Expected 10 presentes, 6 ausentes and rate 0.625, got %+v", reporte)
+	}
+	if reporte.Sesiones[0].Tasa != 0.8 || reporte.Sesiones[1].Tasa != 0.3333 || reporte.Sesiones[2].Tasa != 0 {
+		t.Errorf("Expected per-session rates, got %+v", reporte.Sesiones)
+	}
+
+	if _, err := test.service.Reporte(context.Background(), 7, staffClaims(11, auth.RoleBranchManager, 2), "2025-01-07", "2024-12-01"); !errors.Is(err, ErrRangoSesiones) {
+		t.Errorf("Expected ErrRangoSesiones, got %v", err)
+	}
+}
+
+func TestMarcarAusentes_OnlyClosedDays(t *testing.T) {
+	test := newAsistenciasTestService()
+	test.repo.MarcarAusentesFunc = func(ctx context.Context, desde, hasta time.Time) (int64, error) {
+		if desde.Format("2006-01-02") != "2024-12-31" || hasta.Format("2006-01-02") != "2025-01-07" {
+			t.Errorf("Expected [2024-12-31, 2025-01-07), got [%s, %s)", desde, hasta)
+		}
+		return 3, nil
+	}
+
+	if marcadas, err := test.service.MarcarAusentes(context.Background()); err != nil || marcadas != 3 {
+		t.Errorf("Expected 3 ausentes, got %d (%v)", marcadas, err)
+	}
+}
@@ -0,0 +1,387 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/repository"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Ventana de sesiones que se revisan para marcar cancelaciones y reprogramaciones en los calendarios
+const (
+	calendarioDiasAtras    = 28
+	calendarioDiasAdelante = 180
+)
+
+// dominioUID completa los UID de los eventos: actividad-<id>@... para la serie semanal
+// y sesion-<id>@... para las reservas de una sesión. No cambian al editar la clase
+const dominioUID = "activities-api"
+
+// CalendarioService define la interfaz de los calendarios iCalendar (.ics) de socios y sucursales
+type CalendarioService interface {
+	// Token devuelve el token del calendario del usuario (lo crea la primera vez)
+	Token(ctx context.Context, usuarioID uint) (string, error)
+	// RegenerarToken reemplaza el token: la URL anterior deja de funcionar
+	RegenerarToken(ctx context.Context, usuarioID uint) (string, error)
+	// CalendarioUsuario arma el calendario con las clases del dueño del token
+	CalendarioUsuario(ctx context.Context, token string) ([]byte, error)
+	// CalendarioSucursal arma el calendario con las clases semanales de la sucursal
+	CalendarioSucursal(ctx context.Context, sucursalID uint) ([]byte, error)
+}
+
+// CalendarioServiceImpl implementa CalendarioService
+type CalendarioServiceImpl struct {
+	repository        repository.CalendarioRepository
+	actividadesRepo   repository.ActividadesRepository
+	sesionesRepo      repository.SesionesRepository
+	inscripcionesRepo repository.InscripcionesRepository
+	sucursalesRepo    repository.SucursalesRepository
+	now               func() time.Time
+}
+
+// NewCalendarioService crea una nueva instancia del service
+func NewCalendarioService(
+	repo repository.CalendarioRepository,
+	actividadesRepo repository.ActividadesRepository,
+	sesionesRepo repository.SesionesRepository,
+	inscripcionesRepo repository.InscripcionesRepository,
+	sucursalesRepo repository.SucursalesRepository,
+) *CalendarioServiceImpl {
+	return &CalendarioServiceImpl{
+		repository:        repo,
+		actividadesRepo:   actividadesRepo,
+		sesionesRepo:      sesionesRepo,
+		inscripcionesRepo: inscripcionesRepo,
+		sucursalesRepo:    sucursalesRepo,
+		now:               time.Now,
+	}
+}
+
+// lugarCalendario es la zona horaria y la ubicación de los eventos de una sucursal
+type lugarCalendario struct {
+	loc       *time.Location
+	direccion string
+	geo       string
+}
+
+// Token obtiene el token del calendario del usuario o lo crea
+func (s *CalendarioServiceImpl) Token(ctx context.Context, usuarioID uint) (string, error) {
+	token, err := s.repository.GetToken(ctx, usuarioID)
+	if err != nil {
+		return "", err
+	}
+	if token != "" {
+		return token, nil
+	}
+
+	nuevo, err := nuevoTokenCalendario()
+	if err != nil {
+		return "", err
+	}
+	return s.repository.CreateToken(ctx, usuarioID, nuevo)
+}
+
+// RegenerarToken reemplaza el token del calendario del usuario
+func (s *CalendarioServiceImpl) RegenerarToken(ctx context.Context, usuarioID uint) (string, error) {
+	token, err := nuevoTokenCalendario()
+	if err != nil {
+		return "", err
+	}
+	if err := s.repository.ReplaceToken(ctx, usuarioID, token); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// CalendarioUsuario arma el calendario de las inscripciones activas del dueño del token
+// Las fijas son una serie semanal (con sus sesiones canceladas como EXDATE) y las reservas
+// un evento por sesión (STATUS:CANCELLED si se canceló)
+func (s *CalendarioServiceImpl) CalendarioUsuario(ctx context.Context, token string) ([]byte, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, domain.ErrCalendarioTokenInvalido
+	}
+
+	usuarioID, err := s.repository.GetUsuarioByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	inscripciones, err := s.inscripcionesRepo.ListByUser(ctx, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := newCalendarioICS("Mis clases", s.now())
+	lugares := make(map[uint]lugarCalendario)
+	for _, inscripcion := range inscripciones {
+		if !inscripcion.IsActiva {
+			continue
+		}
+
+		actividad, err := s.actividadesRepo.GetByID(ctx, inscripcion.ActividadID)
+		if err != nil {
+			return nil, fmt.Errorf("actividad con ID %d: %w", inscripcion.ActividadID, err)
+		}
+		lugar, err := s.lugar(ctx, actividad.SucursalID, lugares)
+		if err != nil {
+			return nil, err
+		}
+
+		if inscripcion.SesionID == nil {
+			if err := s.agregarSerie(ctx, cal, actividad, lugar); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		sesion, err := s.sesionesRepo.GetByID(ctx, *inscripcion.SesionID)
+		if err != nil {
+			return nil, fmt.Errorf("sesion con ID %d: %w", *inscripcion.SesionID, err)
+		}
+		agregarSesion(cal, actividad, sesion, lugar)
+	}
+
+	return cal.bytes(), nil
+}
+
+// CalendarioSucursal arma el calendario con la serie semanal de cada actividad activa de la sucursal
+func (s *CalendarioServiceImpl) CalendarioSucursal(ctx context.Context, sucursalID uint) ([]byte, error) {
+	sucursal, err := s.sucursalesRepo.GetByID(ctx, sucursalID)
+	if err != nil {
+		return nil, err
+	}
+
+	actividades, err := s.actividadesRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := newCalendarioICS("Clases - "+sucursal.Nombre, s.now())
+	lugar := lugarSucursal(sucursal)
+	for _, actividad := range actividades {
+		if !actividad.Activa || actividad.SucursalID == nil || *actividad.SucursalID != sucursalID {
+			continue
+		}
+		if err := s.agregarSerie(ctx, cal, actividad, lugar); err != nil {
+			return nil, err
+		}
+	}
+
+	return cal.bytes(), nil
+}
+
+// agregarSerie agrega la clase semanal como un VEVENT con RRULE
+// Las sesiones canceladas van como EXDATE y las reprogramadas como VEVENT con RECURRENCE-ID
+func (s *CalendarioServiceImpl) agregarSerie(ctx context.Context, cal *calendarioICS, actividad domain.Actividad, lugar lugarCalendario) error {
+	weekday, ok := diasSemana[actividad.Dia]
+	if !ok {
+		return nil // Actividad con un día que no se puede programar: no tiene sesiones
+	}
+
+	hoy := s.now().In(lugar.loc)
+	desde := time.Date(hoy.Year(), hoy.Month(), hoy.Day(), 0, 0, 0, 0, lugar.loc).AddDate(0, 0, -calendarioDiasAtras)
+	hasta := desde.AddDate(0, 0, calendarioDiasAtras+calendarioDiasAdelante)
+
+	// La serie arranca el primer día de la clase desde que se creó la actividad
+	primera := desde
+	if !actividad.CreatedAt.IsZero() {
+		creada := actividad.CreatedAt.In(lugar.loc)
+		primera = time.Date(creada.Year(), creada.Month(), creada.Day(), 0, 0, 0, 0, lugar.loc)
+	}
+	primera = primera.AddDate(0, 0, (int(weekday)-int(primera.Weekday())+7)%7)
+
+	inicio, fin, err := horarioLocal(primera.Format("2006-01-02"), actividad.HorarioInicio, actividad.HorarioFinal, lugar.loc)
+	if err != nil {
+		return fmt.Errorf("actividad con ID %d: %w", actividad.ID, err)
+	}
+
+	sesiones, err := s.sesionesRepo.ListByActividad(ctx, actividad.ID, desde, hasta)
+	if err != nil {
+		return fmt.Errorf("error listing sesiones: %w", err)
+	}
+	// Las que se movieron fuera de la ventana desde una fecha de la ventana
+	movidas, err := s.sesionesRepo.ListReprogramadas(ctx, actividad.ID, desde, hasta)
+	if err != nil {
+		return fmt.Errorf("error listing sesiones: %w", err)
+	}
+
+	serie := eventoICS{
+		UID:          fmt.Sprintf("actividad-%d@%s", actividad.ID, dominioUID),
+		Inicio:       inicio,
+		Fin:          fin,
+		RRule:        "FREQ=WEEKLY;BYDAY=" + diasICS[weekday],
+		Resumen:      actividad.Titulo,
+		Descripcion:  descripcionClase(actividad.Descripcion, actividad.Instructor),
+		Lugar:        lugar.direccion,
+		Geo:          lugar.geo,
+		Estado:       "CONFIRMED",
+		UltimoCambio: actividad.UpdatedAt,
+	}
+
+	var reemplazos []eventoICS
+	vistas := make(map[uint]bool)
+	for _, sesion := range append(sesiones, movidas...) {
+		if vistas[sesion.ID] {
+			continue
+		}
+		vistas[sesion.ID] = true
+
+		// Ocurrencia de la serie que le tocaba a la sesión
+		ocurrencia, _, err := horarioLocal(fechaProgramada(sesion), actividad.HorarioInicio, actividad.HorarioFinal, lugar.loc)
+		if err != nil || ocurrencia.Before(inicio) {
+			continue
+		}
+
+		if sesion.Cancelada() {
+			serie.ExDates = append(serie.ExDates, ocurrencia)
+			continue
+		}
+		if !sesion.Reprogramada() {
+			continue
+		}
+
+		reemplazo, err := eventoSesion(actividad, sesion, lugar)
+		if err != nil {
+			continue
+		}
+		reemplazo.UID = serie.UID
+		reemplazo.RecurrenceID = &ocurrencia
+		reemplazos = append(reemplazos, reemplazo)
+	}
+
+	cal.agregar(serie)
+	for _, reemplazo := range reemplazos {
+		cal.agregar(reemplazo)
+	}
+	return nil
+}
+
+// agregarSesion agrega la reserva de una sesión como evento único
+func agregarSesion(cal *calendarioICS, actividad domain.Actividad, sesion domain.Sesion, lugar lugarCalendario) {
+	evento, err := eventoSesion(actividad, sesion, lugar)
+	if err != nil {
+		return
+	}
+	evento.UID = fmt.Sprintf("sesion-%d@%s", sesion.ID, dominioUID)
+	if sesion.Cancelada() {
+		evento.Estado = "CANCELLED"
+		if sesion.MotivoCancelacion != "" {
+			evento.Descripcion = "Cancelada: " + sesion.MotivoCancelacion + "\n" + evento.Descripcion
+		}
+	}
+	cal.agregar(evento)
+}
+
+// eventoSesion arma el evento de una sesión puntual (con su horario e instructor propios)
+func eventoSesion(actividad domain.Actividad, sesion domain.Sesion, lugar lugarCalendario) (eventoICS, error) {
+	inicio, fin, err := horarioLocal(sesion.Fecha, sesion.HorarioInicio, sesion.HorarioFinal, lugar.loc)
+	if err != nil {
+		return eventoICS{}, err
+	}
+
+	descripcion := descripcionClase(actividad.Descripcion, sesion.Instructor)
+	if sesion.Reprogramada() {
+		descripcion = fmt.Sprintf("Reprogramada (era el %s)\n%s", sesion.FechaOriginal, descripcion)
+	}
+
+	return eventoICS{
+		Inicio:       inicio,
+		Fin:          fin,
+		Resumen:      actividad.Titulo,
+		Descripcion:  descripcion,
+		Lugar:        lugar.direccion,
+		Geo:          lugar.geo,
+		Estado:       "CONFIRMED",
+		UltimoCambio: sesion.UpdatedAt,
+	}, nil
+}
+
+// lugar resuelve la sucursal de la actividad (con cache por pedido)
+// Sin sucursal, o si se dio de baja, el evento va en la zona por defecto y sin ubicación
+func (s *CalendarioServiceImpl) lugar(ctx context.Context, sucursalID *uint, cache map[uint]lugarCalendario) (lugarCalendario, error) {
+	if sucursalID == nil {
+		return lugarSucursal(domain.Sucursal{}), nil
+	}
+	if lugar, ok := cache[*sucursalID]; ok {
+		return lugar, nil
+	}
+
+	sucursal, err := s.sucursalesRepo.GetByID(ctx, *sucursalID)
+	if err != nil && !errors.Is(err, domain.ErrSucursalNotFound) {
+		return lugarCalendario{}, err
+	}
+	lugar := lugarSucursal(sucursal)
+	cache[*sucursalID] = lugar
+	return lugar, nil
+}
+
+// lugarSucursal arma la ubicación de los eventos: "Nombre, Dirección, Ciudad" y sus coordenadas
+func lugarSucursal(sucursal domain.Sucursal) lugarCalendario {
+	var partes []string
+	for _, parte := range []string{sucursal.Nombre, sucursal.Direccion, sucursal.Ciudad} {
+		if parte = strings.TrimSpace(parte); parte != "" {
+			partes = append(partes, parte)
+		}
+	}
+
+	lugar := lugarCalendario{loc: zonaSucursal(sucursal), direccion: strings.Join(partes, ", ")}
+	if sucursal.Latitud != nil && sucursal.Longitud != nil {
+		lugar.geo = fmt.Sprintf("%.6f;%.6f", *sucursal.Latitud, *sucursal.Longitud)
+	}
+	return lugar
+}
+
+// zonaSucursal devuelve la zona horaria de la sucursal (la del gimnasio si no es válida)
+func zonaSucursal(sucursal domain.Sucursal) *time.Location {
+	zona := sucursal.ZonaHoraria
+	if zona == "" {
+		zona = domain.ZonaHorariaDefault
+	}
+	loc, err := time.LoadLocation(zona)
+	if err != nil {
+		return gymLocation()
+	}
+	return loc
+}
+
+// horarioLocal arma inicio y fin de una clase en la zona de la sucursal (si termina antes de empezar, cruza la medianoche)
+func horarioLocal(fecha, horaInicio, horaFin string, loc *time.Location) (time.Time, time.Time, error) {
+	inicio, err := time.ParseInLocation("2006-01-02 15:04", fecha+" "+horaInicio, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("horario de inicio inválido: %w", err)
+	}
+	fin, err := time.ParseInLocation("2006-01-02 15:04", fecha+" "+horaFin, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("horario de fin inválido: %w", err)
+	}
+	if !fin.After(inicio) {
+		fin = fin.AddDate(0, 0, 1)
+	}
+	return inicio, fin, nil
+}
+
+func descripcionClase(descripcion, instructor string) string {
+	descripcion = strings.TrimSpace(descripcion)
+	if instructor == "" {
+		return descripcion
+	}
+	if descripcion == "" {
+		return "Instructor: " + instructor
+	}
+	return descripcion + "\nInstructor: " + instructor
+}
+
+// nuevoTokenCalendario genera 32 bytes al azar en base64url (va en la URL del calendario)
+func nuevoTokenCalendario() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generando el token del calendario: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
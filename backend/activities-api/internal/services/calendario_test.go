@@ -0,0 +1,285 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// --- Manual Mocks ---
+
+// MockCalendarioRepository guarda los tokens en memoria (usuario -> token)
+type MockCalendarioRepository struct {
+	tokens map[uint]string
+}
+
+func newMockCalendarioRepository() *MockCalendarioRepository {
+	return &MockCalendarioRepository{tokens: make(map[uint]string)}
+}
+
+func (m *MockCalendarioRepository) GetToken(ctx context.Context, usuarioID uint) (string, error) {
+	return m.tokens[usuarioID], nil
+}
+func (m *MockCalendarioRepository) CreateToken(ctx context.Context, usuarioID uint, token string) (string, error) {
+	if _, ok := m.tokens[usuarioID]; !ok {
+		m.tokens[usuarioID] = token
+	}
+	return m.tokens[usuarioID], nil
+}
+func (m *MockCalendarioRepository) ReplaceToken(ctx context.Context, usuarioID uint, token string) error {
+	m.tokens[usuarioID] = token
+	return nil
+}
+func (m *MockCalendarioRepository) GetUsuarioByToken(ctx context.Context, token string) (uint, error) {
+	for usuarioID, t := range m.tokens {
+		if t == token {
+			return usuarioID, nil
+		}
+	}
+	return 0, domain.ErrCalendarioTokenInvalido
+}
+
+// calendarioTest reúne el servicio de calendarios y sus repositorios en memoria
+type calendarioTest struct {
+	service       *CalendarioServiceImpl
+	actividades   map[uint]*domain.Actividad
+	sesiones      *MockSesionesRepository
+	inscripciones *[]domain.Inscripcion
+}
+
+// newCalendarioTest arma el spinning de los martes en la sucursal Centro (Córdoba) y un funcional sin sucursal
+// "Ahora" es el lunes 6/1/2025 a las 9:00
+func newCalendarioTest() *calendarioTest {
+	sucursalID, otraSucursalID := uint(1), uint(2)
+	latitud, longitud := -31.4135, -64.18105
+
+	spinning := spinningMartes()
+	spinning.SucursalID = &sucursalID
+	spinning.Descripcion = "Clase de 45 minutos; traer toalla"
+	spinning.Activa = true
+	spinning.CreatedAt = time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	test := &calendarioTest{
+		actividades: map[uint]*domain.Actividad{
+			7: spinning,
+			8: {ID: 8, Titulo: "Funcional", Dia: "Miercoles", HorarioInicio: "18:30", HorarioFinal: "19:30", Activa: true},
+			9: {ID: 9, Titulo: "Yoga", Dia: "Lunes", HorarioInicio: "08:00", HorarioFinal: "09:00", SucursalID: &otraSucursalID, Activa: true},
+		},
+		sesiones:      newMockSesionesRepository(),
+		inscripciones: &[]domain.Inscripcion{},
+	}
+
+	actividadesRepo := &MockActividadesRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (domain.Actividad, error) {
+			actividad, ok := test.actividades[id]
+			if !ok {
+				return domain.Actividad{}, errors.New("actividad not found")
+			}
+			return *actividad, nil
+		},
+		ListFunc: func(ctx context.Context) ([]domain.Actividad, error) {
+			var result []domain.Actividad
+			for _, id := range []uint{7, 8, 9} {
+				result = append(result, *test.actividades[id])
+			}
+			return result, nil
+		},
+	}
+
+	sucursales := newMockSucursalesRepository()
+	sucursales.sucursales[1] = domain.Sucursal{
+		ID:          1,
+		Nombre:      "Centro",
+		Direccion:   "Av. Colón 1234",
+		Ciudad:      "Córdoba",
+		ZonaHoraria: "America/Argentina/Cordoba",
+		Latitud:     &latitud,
+		Longitud:    &longitud,
+		Activa:      true,
+	}
+
+	test.service = NewCalendarioService(newMockCalendarioRepository(), actividadesRepo, test.sesiones, newInscripcionesEnMemoria(20, test.inscripciones), sucursales)
+	test.service.now = func() time.Time {
+		return time.Date(2025, 1, 6, 9, 0, 0, 0, gymLocation())
+	}
+	return test
+}
+
+// sesion agrega una sesión en memoria y devuelve su ID
+func (c *calendarioTest) sesion(sesion domain.Sesion) uint {
+	c.sesiones.nextID++
+	sesion.ID = c.sesiones.nextID
+	if sesion.Estado == "" {
+		sesion.Estado = domain.SesionProgramada
+	}
+	c.sesiones.sesiones[sesion.ID] = sesion
+	return sesion.ID
+}
+
+// assertLineasICS controla el formato de las líneas: CRLF y a lo sumo 75 octetos
+func assertLineasICS(t *testing.T, ics string) {
+	t.Helper()
+	if !strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(ics, "END:VCALENDAR\r\n") {
+		t.Fatalf("Expected a VCALENDAR with CRLF line endings, got %q", ics)
+	}
+	for _, linea := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		if len(linea) > icsLargoLinea || strings.Contains(linea, "\n") {
+			t.Errorf("Expected folded lines of at most 75 octets, got %q", linea)
+		}
+	}
+}
+
+// --- Tests ---
+
+func TestCalendarioSucursal(t *testing.T) {
+	test := newCalendarioTest()
+	ctx := context.Background()
+
+	test.sesion(domain.Sesion{ActividadID: 7, Fecha: "2025-01-07", HorarioInicio: "19:00", HorarioFinal: "20:00"})
+	test.sesion(domain.Sesion{ActividadID: 7, Fecha: "2025-01-14", HorarioInicio: "19:00", HorarioFinal: "20:00", Estado: domain.SesionCancelada})
+	test.sesion(domain.Sesion{ActividadID: 7, Fecha: "2025-01-22", FechaOriginal: "2025-01-21", HorarioInicio: "20:00", HorarioFinal: "21:00", Instructor: "Luis"})
+
+	ics, err := test.service.CalendarioSucursal(ctx, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	cal := string(ics)
+	assertLineasICS(t, cal)
+
+	tz := "TZID=America/Argentina/Cordoba:"
+	for _, expected := range []string{
+		"X-WR-CALNAME:Clases - Centro",
+		"BEGIN:VTIMEZONE\r\nTZID:America/Argentina/Cordoba\r\nBEGIN:STANDARD",
+		"TZOFFSETTO:-0300",
+		// La serie arranca el primer martes después del alta de la actividad
+		"DTSTART;" + tz + "20250107T190000\r\nDTEND;" + tz + "20250107T200000\r\nRRULE:FREQ=WEEKLY;BYDAY=TU",
+		"EXDATE;" + tz + "20250114T190000",
+		"RECURRENCE-ID;" + tz + "20250121T190000\r\nDTSTART;" + tz + "20250122T200000",
+		`DESCRIPTION:Clase de 45 minutos\; traer toalla\nInstructor: Ana`,
+		`LOCATION:Centro\, Av. Colón 1234\, Córdoba`,
+		"GEO:-31.413500;-64.181050",
+	} {
+		if !strings.Contains(cal, expected) {
+			t.Errorf("Expected %q in the calendar, got:\n%s", expected, cal)
+		}
+	}
+	if n := strings.Count(cal, "UID:actividad-7@activities-api"); n != 2 {
+		t.Errorf("Expected the series and its rescheduled occurrence with the same UID, got %d", n)
+	}
+	if strings.Contains(cal, "Funcional") || strings.Contains(cal, "Yoga") {
+		t.Errorf("Expected only the classes of the branch, got:\n%s", cal)
+	}
+
+	// El UID no cambia al editar la clase
+	test.actividades[7].HorarioInicio = "19:30"
+	test.actividades[7].UpdatedAt = time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)
+	ics, err = test.service.CalendarioSucursal(ctx, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cal := string(ics); !strings.Contains(cal, "UID:actividad-7@activities-api") || !strings.Contains(cal, "DTSTART;"+tz+"20250107T193000") || !strings.Contains(cal, "LAST-MODIFIED:20250106T080000Z") {
+		t.Errorf("Expected the same UID with the new time, got:\n%s", cal)
+	}
+
+	if _, err := test.service.CalendarioSucursal(ctx, 3); !errors.Is(err, domain.ErrSucursalNotFound) {
+		t.Errorf("Expected ErrSucursalNotFound, got %v", err)
+	}
+}
+
+func TestCalendarioUsuario(t *testing.T) {
+	test := newCalendarioTest()
+	ctx := context.Background()
+
+	cancelada := test.sesion(domain.Sesion{ActividadID: 8, Fecha: "2025-01-08", HorarioInicio: "18:30", HorarioFinal: "19:30", Estado: domain.SesionCancelada, MotivoCancelacion: "Corte de luz"})
+	otra := test.sesion(domain.Sesion{ActividadID: 8, Fecha: "2025-01-15", HorarioInicio: "18:30", HorarioFinal: "19:30"})
+	*test.inscripciones = []domain.Inscripcion{
+		{ID: 1, UsuarioID: 1, ActividadID: 7, IsActiva: true},                       // Fija
+		{ID: 2, UsuarioID: 1, ActividadID: 8, SesionID: &cancelada, IsActiva: true}, // Reserva de una sesión cancelada
+		{ID: 3, UsuarioID: 1, ActividadID: 8, SesionID: &otra, IsActiva: false},     // Reserva dada de baja
+		{ID: 4, UsuarioID: 2, ActividadID: 9, IsActiva: true},                       // De otro socio
+	}
+
+	token, err := test.service.Token(ctx, 1)
+	if err != nil || len(token) < 40 {
+		t.Fatalf("Expected a random token, got %q (%v)", token, err)
+	}
+	if mismo, _ := test.service.Token(ctx, 1); mismo != token {
+		t.Errorf("Expected the same token on the second call, got %q", mismo)
+	}
+
+	ics, err := test.service.CalendarioUsuario(ctx, token)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	cal := string(ics)
+	assertLineasICS(t, cal)
+
+	for _, expected := range []string{
+		"UID:actividad-7@activities-api",
+		"RRULE:FREQ=WEEKLY;BYDAY=TU",
+		// Sin sucursal va en la zona por defecto
+		"UID:sesion-1@activities-api\r\nDTSTAMP:20250106T120000Z\r\nDTSTART;TZID=America/Argentina/Buenos_Aires:20250108T183000",
+		"STATUS:CANCELLED",
+		`DESCRIPTION:Cancelada: Corte de luz`,
+	} {
+		if !strings.Contains(cal, expected) {
+			t.Errorf("Expected %q in the calendar, got:\n%s", expected, cal)
+		}
+	}
+	if strings.Contains(cal, "sesion-2@") || strings.Contains(cal, "Yoga") {
+		t.Errorf("Expected only the active inscriptions of the user, got:\n%s", cal)
+	}
+	if strings.Count(cal, "BEGIN:VTIMEZONE") != 2 {
+		t.Errorf("Expected one VTIMEZONE per zone, got:\n%s", cal)
+	}
+
+	// Regenerar invalida la URL anterior
+	nuevo, err := test.service.RegenerarToken(ctx, 1)
+	if err != nil || nuevo == token {
+		t.Fatalf("Expected a new token, got %q (%v)", nuevo, err)
+	}
+	if _, err := test.service.CalendarioUsuario(ctx, token); !errors.Is(err, domain.ErrCalendarioTokenInvalido) {
+		t.Errorf("Expected ErrCalendarioTokenInvalido for the old token, got %v", err)
+	}
+	if _, err := test.service.CalendarioUsuario(ctx, ""); !errors.Is(err, domain.ErrCalendarioTokenInvalido) {
+		t.Errorf("Expected ErrCalendarioTokenInvalido without token, got %v", err)
+	}
+}
+
+func TestPlegarLineaICS(t *testing.T) {
+	linea := "DESCRIPTION:" + strings.Repeat("Clase de ñandú con música ", 8)
+
+	plegada := plegarLineaICS(linea)
+	for _, parte := range strings.Split(plegada, "\r\n") {
+		if len(parte) > icsLargoLinea || !utf8.ValidString(parte) {
+			t.Errorf("Expected valid UTF-8 lines of at most 75 octets, got %q", parte)
+		}
+	}
+	if desplegada := strings.ReplaceAll(plegada, "\r\n ", ""); desplegada != linea {
+		t.Errorf("Expected unfolding to restore the line, got %q", desplegada)
+	}
+}
+
+func TestEscribirVTimezone_HorarioDeVerano(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+
+	var w icsWriter
+	escribirVTimezone(&w, loc, 2025)
+	vtimezone := w.b.String()
+
+	for _, expected := range []string{
+		"BEGIN:DAYLIGHT\r\nDTSTART:20250309T020000\r\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\nTZNAME:EDT",
+		"BEGIN:STANDARD\r\nDTSTART:20251102T020000\r\nRRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU\r\nTZOFFSETFROM:-0400\r\nTZOFFSETTO:-0500\r\nTZNAME:EST",
+	} {
+		if !strings.Contains(vtimezone, expected) {
+			t.Errorf("Expected %q, got:\n%s", expected, vtimezone)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Errores de calificaciones
+var (
+	ErrCalificacionPuntaje       = errors.New("el puntaje debe ser de 1 a 5")
+	ErrCalificacionSesionCancel  = errors.New("la sesión fue cancelada: no se puede calificar")
+	ErrCalificacionAntesDeClase  = errors.New("la clase todavía no terminó: podés calificarla cuando finalice")
+	ErrCalificacionSinParticipar = errors.New("solo pueden calificar los socios que asistieron o estaban inscriptos en la clase")
+)
+
+const (
+	// maxComentarioCalificacion es el largo máximo del comentario (columna VARCHAR(1000))
+	maxComentarioCalificacion = 1000
+	// limiteCalificacionesPublicas es la cantidad de calificaciones que devuelven los listados públicos
+	limiteCalificacionesPublicas = 50
+)
+
+// CalificacionesService define la interfaz del servicio de calificaciones de clases
+type CalificacionesService interface {
+	Calificar(ctx context.Context, usuarioID, sesionID uint, input domain.CalificacionCreate) (domain.Calificacion, error)
+	ListByActividad(ctx context.Context, actividadID uint) (domain.CalificacionesResponse, error)
+	ListByInstructor(ctx context.Context, instructorID uint) (domain.CalificacionesResponse, error)
+	List(ctx context.Context, filtro domain.FiltroCalificaciones) ([]domain.Calificacion, error)
+	Ocultar(ctx context.Context, id, adminID uint, motivo string) (domain.Calificacion, error)
+	Mostrar(ctx context.Context, id, adminID uint) (domain.Calificacion, error)
+	Marcar(ctx context.Context, id, adminID uint, motivo string) (domain.Calificacion, error)
+	Desmarcar(ctx context.Context, id, adminID uint) (domain.Calificacion, error)
+}
+
+// CalificacionesServiceImpl implementa CalificacionesService
+// Cada socio que asistió (o estaba inscripto) califica una vez cada sesión terminada; la calificación
+// cuenta para la actividad y para el instructor que dictó la clase. Los promedios salen de la vista
+// actividades_lugares y se republican en activity.update cuando cambian
+type CalificacionesServiceImpl struct {
+	repository       repository.CalificacionesRepository
+	sesionesRepo     repository.SesionesRepository
+	actividadesRepo  repository.ActividadesRepository
+	instructoresRepo repository.InstructoresRepository
+	eventPublisher   EventPublisher
+
+	now func() time.Time
+}
+
+// NewCalificacionesService crea una nueva instancia del servicio
+func NewCalificacionesService(repo repository.CalificacionesRepository, sesionesRepo repository.SesionesRepository, actividadesRepo repository.ActividadesRepository, instructoresRepo repository.InstructoresRepository, eventPublisher EventPublisher) *CalificacionesServiceImpl {
+	return &CalificacionesServiceImpl{
+		repository:       repo,
+		sesionesRepo:     sesionesRepo,
+		actividadesRepo:  actividadesRepo,
+		instructoresRepo: instructoresRepo,
+		eventPublisher:   eventPublisher,
+		now:              time.Now,
+	}
+}
+
+// Calificar registra el puntaje y el comentario del socio sobre una sesión terminada
+func (s *CalificacionesServiceImpl) Calificar(ctx context.Context, usuarioID, sesionID uint, input domain.CalificacionCreate) (domain.Calificacion, error) {
+	if input.Puntaje < domain.PuntajeMinimo || input.Puntaje > domain.PuntajeMaximo {
+		return domain.Calificacion{}, ErrCalificacionPuntaje
+	}
+
+	sesion, err := s.sesionesRepo.GetByID(ctx, sesionID)
+	if err != nil {
+		return domain.Calificacion{}, fmt.Errorf("sesión con ID %d: %w", sesionID, err)
+	}
+	if sesion.Cancelada() {
+		return domain.Calificacion{}, ErrCalificacionSesionCancel
+	}
+
+	inicio, fin := sesionInicio(sesion), sesionFin(sesion)
+	if s.now().Before(fin) {
+		return domain.Calificacion{}, ErrCalificacionAntesDeClase
+	}
+
+	participo, err := s.repository.Participo(ctx, usuarioID, sesion, inicio, fin)
+	if err != nil {
+		return domain.Calificacion{}, err
+	}
+	if !participo {
+		return domain.Calificacion{}, ErrCalificacionSinParticipar
+	}
+
+	actividad, err := s.actividadesRepo.GetByID(ctx, sesion.ActividadID)
+	if err != nil {
+		return domain.Calificacion{}, fmt.Errorf("actividad con ID %d: %w", sesion.ActividadID, err)
+	}
+
+	comentario := strings.TrimSpace(input.Comentario)
+	if utf8.RuneCountInString(comentario) > maxComentarioCalificacion {
+		comentario = string([]rune(comentario)[:maxComentarioCalificacion])
+	}
+
+	calificacion, err := s.repository.Create(ctx, domain.Calificacion{
+		SesionID:           sesion.ID,
+		ActividadID:        sesion.ActividadID,
+		UsuarioID:          usuarioID,
+		InstructorPerfilID: instructorDeSesion(actividad, sesion),
+		Puntaje:            input.Puntaje,
+		Comentario:         comentario,
+	})
+	if err != nil {
+		return domain.Calificacion{}, err
+	}
+
+	log.Printf("⭐ Usuario %d calificó la sesión %d con %d", usuarioID, sesion.ID, calificacion.Puntaje)
+	s.publicarPromedios(ctx, calificacion)
+	return calificacion, nil
+}
+
+// ListByActividad devuelve el resumen y las últimas calificaciones visibles de la actividad
+func (s *CalificacionesServiceImpl) ListByActividad(ctx context.Context, actividadID uint) (domain.CalificacionesResponse, error) {
+	if _, err := s.actividadesRepo.GetByID(ctx, actividadID); err != nil {
+		return domain.CalificacionesResponse{}, fmt.Errorf("actividad con ID %d: %w", actividadID, err)
+	}
+
+	return s.listVisibles(ctx, domain.FiltroCalificaciones{ActividadID: &actividadID})
+}
+
+// ListByInstructor devuelve el resumen y las últimas calificaciones visibles del instructor
+func (s *CalificacionesServiceImpl) ListByInstructor(ctx context.Context, instructorID uint) (domain.CalificacionesResponse, error) {
+	if _, err := s.instructoresRepo.GetByID(ctx, instructorID); err != nil {
+		return domain.CalificacionesResponse{}, fmt.Errorf("instructor con ID %d: %w", instructorID, err)
+	}
+
+	return s.listVisibles(ctx, domain.FiltroCalificaciones{InstructorPerfilID: &instructorID})
+}
+
+// List obtiene las calificaciones del filtro (también las ocultas) para moderar
+func (s *CalificacionesServiceImpl) List(ctx context.Context, filtro domain.FiltroCalificaciones) ([]domain.Calificacion, error) {
+	return s.repository.List(ctx, filtro)
+}
+
+// Ocultar saca la calificación de los listados públicos y de los promedios
+func (s *CalificacionesServiceImpl) Ocultar(ctx context.Context, id, adminID uint, motivo string) (domain.Calificacion, error) {
+	return s.moderar(ctx, id, "oculta", true, adminID, motivo)
+}
+
+// Mostrar vuelve a publicar una calificación oculta
+func (s *CalificacionesServiceImpl) Mostrar(ctx context.Context, id, adminID uint) (domain.Calificacion, error) {
+	return s.moderar(ctx, id, "oculta", false, adminID, "")
+}
+
+// Marcar deja la calificación para revisar sin ocultarla
+func (s *CalificacionesServiceImpl) Marcar(ctx context.Context, id, adminID uint, motivo string) (domain.Calificacion, error) {
+	return s.moderar(ctx, id, "marcada", true, adminID, motivo)
+}
+
+// Desmarcar da por revisada una calificación marcada
+func (s *CalificacionesServiceImpl) Desmarcar(ctx context.Context, id, adminID uint) (domain.Calificacion, error) {
+	return s.moderar(ctx, id, "marcada", false, adminID, "")
+}
+
+func (s *CalificacionesServiceImpl) moderar(ctx context.Context, id uint, campo string, valor bool, adminID uint, motivo string) (domain.Calificacion, error) {
+	antes, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return domain.Calificacion{}, err
+	}
+
+	calificacion, err := s.repository.Moderar(ctx, id, campo, valor, strings.TrimSpace(motivo), adminID, s.now())
+	if err != nil {
+		return domain.Calificacion{}, err
+	}
+
+	log.Printf("🛡️  Calificación %d: %s = %t (admin %d)", id, campo, valor, adminID)
+	// Solo ocultar/mostrar cambia los promedios
+	if antes.Oculta != calificacion.Oculta {
+		s.publicarPromedios(ctx, calificacion)
+	}
+	return calificacion, nil
+}
+
+func (s *CalificacionesServiceImpl) listVisibles(ctx context.Context, filtro domain.FiltroCalificaciones) (domain.CalificacionesResponse, error) {
+	resumen, err := s.repository.Resumen(ctx, filtro)
+	if err != nil {
+		return domain.CalificacionesResponse{}, err
+	}
+
+	oculta := false
+	filtro.Oculta = &oculta
+	filtro.Limit = limiteCalificacionesPublicas
+	calificaciones, err := s.repository.List(ctx, filtro)
+	if err != nil {
+		return domain.CalificacionesResponse{}, err
+	}
+
+	return domain.CalificacionesResponse{Resumen: resumen, Calificaciones: calificaciones}, nil
+}
+
+// publicarPromedios publica activity.update de la actividad calificada y de las actividades del instructor
+// (su promedio aparece en todas) para que search-api reindexe los promedios
+func (s *CalificacionesServiceImpl) publicarPromedios(ctx context.Context, calificacion domain.Calificacion) {
+	// Los promedios salen de la vista: sin invalidar, el cache devolvería los anteriores
+	s.actividadesRepo.InvalidateCache()
+
+	var actividades []domain.Actividad
+	actividad, err := s.actividadesRepo.GetByID(ctx, calificacion.ActividadID)
+	if err != nil {
+		// La calificación ya está guardada: solo se pierde la reindexación inmediata
+		fmt.Printf("⚠️  Error obteniendo la actividad %d: %v\n", calificacion.ActividadID, err)
+	} else {
+		actividades = append(actividades, actividad)
+	}
+
+	if calificacion.InstructorPerfilID != nil {
+		delInstructor, err := s.instructoresRepo.ListActividades(ctx, *calificacion.InstructorPerfilID)
+		if err != nil {
+			fmt.Printf("⚠️  Error listando actividades del instructor %d: %v\n", *calificacion.InstructorPerfilID, err)
+		}
+		for _, a := range delInstructor {
+			if a.ID != calificacion.ActividadID {
+				actividades = append(actividades, a)
+			}
+		}
+	}
+
+	for _, a := range actividades {
+		if err := s.eventPublisher.PublishActivityEvent("update", fmt.Sprintf("%d", a.ID), datosEventoActividad(a, a.CupoDisponible)); err != nil {
+			fmt.Printf("⚠️  Error publicando evento activity.update: %v\n", err)
+		}
+	}
+}
+
+// instructorDeSesion devuelve el instructor (tabla instructores) al que se le acredita la calificación
+// Si la sesión tuvo un reemplazo no se le acredita a nadie: el reemplazo puede no tener perfil
+func instructorDeSesion(actividad domain.Actividad, sesion domain.Sesion) *uint {
+	if actividad.InstructorPerfilID == nil || sesion.Instructor != actividad.Instructor {
+		return nil
+	}
+	if (sesion.InstructorID == nil) != (actividad.InstructorID == nil) ||
+		(sesion.InstructorID != nil && *sesion.InstructorID != *actividad.InstructorID) {
+		return nil
+	}
+	return actividad.InstructorPerfilID
+}
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// --- Manual Mocks ---
+
+// MockCalificacionesRepository guarda las calificaciones en memoria
+// participantes son los usuarios que asistieron o estaban inscriptos en cada sesión
+type MockCalificacionesRepository struct {
+	calificaciones []domain.Calificacion
+	participantes  map[uint][]uint // sesionID -> usuarios
+}
+
+func newMockCalificacionesRepository() *MockCalificacionesRepository {
+	return &MockCalificacionesRepository{
+		participantes: make(map[uint][]uint),
+	}
+}
+
+func (m *MockCalificacionesRepository) Create(ctx context.Context, calificacion domain.Calificacion) (domain.Calificacion, error) {
+	for _, existing := range m.calificaciones {
+		if existing.SesionID == calificacion.SesionID && existing.UsuarioID == calificacion.UsuarioID {
+			return domain.Calificacion{}, domain.ErrCalificacionDuplicada
+		}
+	}
+	calificacion.ID = uint(len(m.calificaciones) + 1)
+	m.calificaciones = append(m.calificaciones, calificacion)
+	return calificacion, nil
+}
+func (m *MockCalificacionesRepository) GetByID(ctx context.Context, id uint) (domain.Calificacion, error) {
+	if id == 0 || int(id) > len(m.calificaciones) {
+		return domain.Calificacion{}, domain.ErrCalificacionNotFound
+	}
+	return m.calificaciones[id-1], nil
+}
+func (m *MockCalificacionesRepository) List(ctx context.Context, filtro domain.FiltroCalificaciones) ([]domain.Calificacion, error) {
+	var result []domain.Calificacion
+	for _, calificacion := range m.calificaciones {
+		if m.cumple(calificacion, filtro) && (filtro.Oculta == nil || calificacion.Oculta == *filtro.Oculta) {
+			result = append(result, calificacion)
+		}
+	}
+	return result, nil
+}
+func (m *MockCalificacionesRepository) Resumen(ctx context.Context, filtro domain.FiltroCalificaciones) (domain.ResumenCalificaciones, error) {
+	var resumen domain.ResumenCalificaciones
+	total := 0
+	for _, calificacion := range m.calificaciones {
+		if m.cumple(calificacion, filtro) && !calificacion.Oculta {
+			resumen.Cantidad++
+			total += calificacion.Puntaje
+		}
+	}
+	if resumen.Cantidad > 0 {
+		resumen.Promedio = math.Round(float64(total)/float64(resumen.Cantidad)*100) / 100
+	}
+	return resumen, nil
+}
+func (m *MockCalificacionesRepository) Moderar(ctx context.Context, id uint, campo string, valor bool, motivo string, adminID uint, now time.Time) (domain.Calificacion, error) {
+	if _, err := m.GetByID(ctx, id); err != nil {
+		return domain.Calificacion{}, err
+	}
+	calificacion := &m.calificaciones[id-1]
+	switch campo {
+	case "oculta":
+		calificacion.Oculta = valor
+		calificacion.MotivoOcultamiento = motivo
+	case "marcada":
+		calificacion.Marcada = valor
+		calificacion.MotivoMarca = motivo
+	}
+	calificacion.ModeradaPor = &adminID
+	calificacion.ModeradaEn = &now
+	return *calificacion, nil
+}
+func (m *MockCalificacionesRepository) Participo(ctx context.Context, usuarioID uint, sesion domain.Sesion, inicio, fin time.Time) (bool, error) {
+	for _, id := range m.participantes[sesion.ID] {
+		if id == usuarioID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockCalificacionesRepository) cumple(calificacion domain.Calificacion, filtro domain.FiltroCalificaciones) bool {
+	if filtro.ActividadID != nil && calificacion.ActividadID != *filtro.ActividadID {
+		return false
+	}
+	if filtro.InstructorPerfilID != nil && (calificacion.InstructorPerfilID == nil || *calificacion.InstructorPerfilID != *filtro.InstructorPerfilID) {
+		return false
+	}
+	if filtro.Marcada != nil && calificacion.Marcada != *filtro.Marcada {
+		return false
+	}
+	return true
+}
+
+// calificacionesTest reúne el servicio de calificaciones y sus repositorios en memoria
+type calificacionesTest struct {
+	service        *CalificacionesServiceImpl
+	calificaciones *MockCalificacionesRepository
+	sesiones       *MockSesionesRepository
+	eventos        *[]string // IDs de las actividades con activity.update publicado
+}
+
+// newCalificacionesTest arma el servicio con el spinning de los martes (actividad 5) que dicta
+// el instructor 1 ("Ana Gómez", cuenta 7), que también da Funcional (actividad 8)
+// La sesión 1 es el martes 7/1/2025 de 18:00 a 19:00 con la socia 42 inscripta; "ahora" es ese día a las 20:00
+func newCalificacionesTest(t *testing.T) *calificacionesTest {
+	perfilID, cuentaID := uint(1), uint(7)
+	spinning := domain.Actividad{ID: 5, Titulo: "Spinning", Instructor: "Ana Gómez", InstructorID: &cuentaID, InstructorPerfilID: &perfilID}
+	funcional := domain.Actividad{ID: 8, Titulo: "Funcional", Instructor: "Ana Gómez", InstructorID: &cuentaID, InstructorPerfilID: &perfilID}
+
+	actividadesRepo := &MockActividadesRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (domain.Actividad, error) {
+			switch id {
+			case spinning.ID:
+				return spinning, nil
+			case funcional.ID:
+				return funcional, nil
+			}
+			return domain.Actividad{}, errors.New("actividad not found")
+		},
+	}
+
+	instructores := newMockInstructoresRepository()
+	instructores.instructores[perfilID] = domain.Instructor{ID: perfilID, Nombre: "Ana Gómez", UsuarioID: &cuentaID, Activo: true}
+	instructores.actividades = []domain.Actividad{spinning, funcional}
+
+	sesiones := newMockSesionesRepository()
+	sesiones.sesiones[1] = domain.Sesion{
+		ID: 1, ActividadID: spinning.ID, Fecha: "2025-01-07", HorarioInicio: "18:00", HorarioFinal: "19:00",
+		Cupo: 20, Instructor: "Ana Gómez", InstructorID: &cuentaID, Estado: domain.SesionProgramada,
+	}
+
+	calificaciones := newMockCalificacionesRepository()
+	calificaciones.participantes[1] = []uint{42}
+
+	eventos := &[]string{}
+	publisher := &MockEventPublisher{
+		PublishActivityEventFunc: func(action, activityID string, data map[string]interface{}) error {
+			if action != "update" {
+				t.Errorf("se esperaba activity.update, se publicó %s", action)
+			}
+			if _, ok := data["calificacion_promedio"]; !ok {
+				t.Errorf("el evento de la actividad %s no trae calificacion_promedio", activityID)
+			}
+			*eventos = append(*eventos, activityID)
+			return nil
+		},
+	}
+
+	service := NewCalificacionesService(calificaciones, sesiones, actividadesRepo, instructores, publisher)
+	service.now = func() time.Time {
+		return time.Date(2025, 1, 7, 20, 0, 0, 0, gymLocation())
+	}
+
+	return &calificacionesTest{
+		service:        service,
+		calificaciones: calificaciones,
+		sesiones:       sesiones,
+		eventos:        eventos,
+	}
+}
+
+// --- Tests ---
+
+func TestCalificar_AcreditaAlInstructorYPublicaPromedios(t *testing.T) {
+	test := newCalificacionesTest(t)
+
+	calificacion, err := test.service.Calificar(context.Background(), 42, 1, domain.CalificacionCreate{
+		Puntaje:    5,
+		Comentario: "  Excelente clase  ",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calificacion.ActividadID != 5 || calificacion.Puntaje != 5 || calificacion.Comentario != "Excelente clase" {
+		t.Errorf("Calificación inesperada: %+v", calificacion)
+	}
+	if calificacion.InstructorPerfilID == nil || *calificacion.InstructorPerfilID != 1 {
+		t.Errorf("Se esperaba acreditar la calificación al instructor 1, se obtuvo %v", calificacion.InstructorPerfilID)
+	}
+	// La actividad calificada y la otra clase del instructor (su promedio aparece en las dos)
+	if strings.Join(*test.eventos, ",") != "5,8" {
+		t.Errorf("Se esperaba activity.update de las actividades 5 y 8, se publicó %v", *test.eventos)
+	}
+}
+
+func TestCalificar_UnaVezPorSesion(t *testing.T) {
+	test := newCalificacionesTest(t)
+	ctx := context.Background()
+
+	if _, err := test.service.Calificar(ctx, 42, 1, domain.CalificacionCreate{Puntaje: 4}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_, err := test.service.Calificar(ctx, 42, 1, domain.CalificacionCreate{Puntaje: 1})
+	if !errors.Is(err, domain.ErrCalificacionDuplicada) {
+		t.Errorf("Expected ErrCalificacionDuplicada, got %v", err)
+	}
+}
+
+func TestCalificar_Rechazos(t *testing.T) {
+	tests := []struct {
+		name      string
+		usuarioID uint
+		puntaje   int
+		preparar  func(test *calificacionesTest)
+		expected  error
+	}{
+		{"puntaje fuera de rango", 42, 6, nil, ErrCalificacionPuntaje},
+		{"sin asistir ni estar inscripto", 99, 5, nil, ErrCalificacionSinParticipar},
+		{"la clase todavía no terminó", 42, 5, func(test *calificacionesTest) {
+			test.service.now = func() time.Time { return time.Date(2025, 1, 7, 18, 30, 0, 0, gymLocation()) }
+		}, ErrCalificacionAntesDeClase},
+		{"sesión cancelada", 42, 5, func(test *calificacionesTest) {
+			sesion := test.sesiones.sesiones[1]
+			sesion.Estado = domain.SesionCancelada
+			test.sesiones.sesiones[1] = sesion
+		}, ErrCalificacionSesionCancel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := newCalificacionesTest(t)
+			if tt.preparar != nil {
+				tt.preparar(test)
+			}
+
+			_, err := test.service.Calificar(context.Background(), tt.usuarioID, 1, domain.CalificacionCreate{Puntaje: tt.puntaje})
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+			if len(test.calificaciones.calificaciones) != 0 {
+				t.Errorf("No se esperaba guardar la calificación")
+			}
+		})
+	}
+}
+
+func TestCalificar_ReemplazoNoSeAcreditaAlInstructor(t *testing.T) {
+	test := newCalificacionesTest(t)
+	sesion := test.sesiones.sesiones[1]
+	sesion.Instructor = "Juan Pérez"
+	sesion.InstructorID = nil
+	test.sesiones.sesiones[1] = sesion
+
+	calificacion, err := test.service.Calificar(context.Background(), 42, 1, domain.CalificacionCreate{Puntaje: 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calificacion.InstructorPerfilID != nil {
+		t.Errorf("La clase la dio un reemplazo: no se esperaba instructor, se obtuvo %d", *calificacion.InstructorPerfilID)
+	}
+	if strings.Join(*test.eventos, ",") != "5" {
+		t.Errorf("Se esperaba activity.update solo de la actividad 5, se publicó %v", *test.eventos)
+	}
+}
+
+func TestModeracion_OcultarSacaDelPromedioYMarcarNo(t *testing.T) {
+	test := newCalificacionesTest(t)
+	ctx := context.Background()
+	test.calificaciones.participantes[1] = []uint{42, 43}
+
+	if _, err := test.service.Calificar(ctx, 42, 1, domain.CalificacionCreate{Puntaje: 5}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ofensiva, err := test.service.Calificar(ctx, 43, 1, domain.CalificacionCreate{Puntaje: 1, Comentario: "insulto"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	*test.eventos = nil
+
+	marcada, err := test.service.Marcar(ctx, ofensiva.ID, 1, "revisar lenguaje")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !marcada.Marcada || marcada.MotivoMarca != "revisar lenguaje" || len(*test.eventos) != 0 {
+		t.Errorf("Marcar no cambia los promedios: %+v, eventos %v", marcada, *test.eventos)
+	}
+
+	if _, err := test.service.Ocultar(ctx, ofensiva.ID, 1, "lenguaje ofensivo"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(*test.eventos) == 0 {
+		t.Errorf("Se esperaba republicar los promedios al ocultar")
+	}
+
+	actividad, err := test.service.ListByActividad(ctx, 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if actividad.Resumen.Promedio != 5 || actividad.Resumen.Cantidad != 1 || len(actividad.Calificaciones) != 1 {
+		t.Errorf("La oculta no debería contar ni listarse: %+v", actividad)
+	}
+
+	instructor, err := test.service.ListByInstructor(ctx, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if instructor.Resumen.Cantidad != 1 {
+		t.Errorf("Se esperaba 1 calificación visible del instructor, se obtuvo %d", instructor.Resumen.Cantidad)
+	}
+
+	if _, err := test.service.Mostrar(ctx, ofensiva.ID, 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	actividad, _ = test.service.ListByActividad(ctx, 5)
+	if actividad.Resumen.Promedio != 3 || actividad.Resumen.Cantidad != 2 {
+		t.Errorf("Se esperaba promedio 3 con 2 calificaciones, se obtuvo %+v", actividad.Resumen)
+	}
+
+	if _, err := test.service.Ocultar(ctx, 99, 1, ""); !errors.Is(err, domain.ErrCalificacionNotFound) {
+		t.Errorf("Expected ErrCalificacionNotFound, got %v", err)
+	}
+}
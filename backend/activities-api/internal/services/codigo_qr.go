@@ -0,0 +1,110 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errores del código QR de asistencia
+var (
+	ErrQRInvalido = errors.New("código QR inválido")
+	ErrQRVencido  = errors.New("código QR vencido, el socio tiene que mostrar uno nuevo")
+)
+
+// prefijoQR versiona el formato del payload
+const prefijoQR = "GYM1"
+
+// CodigoQRSigner firma y verifica el QR rotativo de los socios
+// Payload: GYM1.<usuario_id>.<contador>.<firma>, con contador = unix / periodo y
+// firma = HMAC-SHA256(secreto, "GYM1.<usuario_id>.<contador>") en base64url
+// Un QR vale durante su período y el siguiente (margen para el escaneo); una captura vieja no sirve
+type CodigoQRSigner struct {
+	secret  []byte
+	periodo time.Duration
+	now     func() time.Time
+}
+
+// NewCodigoQRSigner crea el firmador de QR
+// Sin secreto genera uno al azar: los QR dejan de valer al reiniciar y no sirven entre réplicas
+func NewCodigoQRSigner(secret string, periodo time.Duration) *CodigoQRSigner {
+	if periodo <= 0 {
+		periodo = 30 * time.Second
+	}
+
+	key := []byte(secret)
+	if len(key) == 0 {
+		log.Println("⚠️  CHECKIN_QR_SECRET no configurado - usando un secreto aleatorio (los QR no sobreviven a un reinicio)")
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			log.Fatalf("No se pudo generar el secreto de los QR: %v", err)
+		}
+	}
+
+	return &CodigoQRSigner{
+		secret:  key,
+		periodo: periodo,
+		now:     time.Now,
+	}
+}
+
+// Generate arma el QR vigente del usuario y el momento en que conviene pedir uno nuevo
+func (s *CodigoQRSigner) Generate(usuarioID uint) (string, time.Time) {
+	contador := s.contador(s.now())
+	expiraEn := time.Unix(0, (contador+1)*int64(s.periodo))
+
+	return s.firmar(usuarioID, contador), expiraEn
+}
+
+// Verify valida la firma y la vigencia del QR y devuelve el usuario
+func (s *CodigoQRSigner) Verify(payload string) (uint, error) {
+	payload = strings.TrimSpace(payload)
+	partes := strings.Split(payload, ".")
+	if len(partes) != 4 || partes[0] != prefijoQR {
+		return 0, ErrQRInvalido
+	}
+
+	usuarioID, err := strconv.ParseUint(partes[1], 10, 32)
+	if err != nil || usuarioID == 0 {
+		return 0, ErrQRInvalido
+	}
+	contador, err := strconv.ParseInt(partes[2], 10, 64)
+	if err != nil {
+		return 0, ErrQRInvalido
+	}
+
+	// La firma se compara antes que la vigencia: un QR adulterado nunca se informa como vencido
+	esperado := s.firmar(uint(usuarioID), contador)
+	if !hmac.Equal([]byte(esperado), []byte(payload)) {
+		return 0, ErrQRInvalido
+	}
+
+	actual := s.contador(s.now())
+	if contador > actual || contador < actual-1 {
+		return 0, ErrQRVencido
+	}
+
+	return uint(usuarioID), nil
+}
+
+// contador devuelve el número de período de "t"
+func (s *CodigoQRSigner) contador(t time.Time) int64 {
+	return t.UnixNano() / int64(s.periodo)
+}
+
+// firmar arma el payload completo para el usuario y el contador
+func (s *CodigoQRSigner) firmar(usuarioID uint, contador int64) string {
+	mensaje := fmt.Sprintf("%s.%d.%d", prefijoQR, usuarioID, contador)
+
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(mensaje))
+
+	return mensaje + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,478 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// CuposService define la interfaz del cupo de clases de los planes
+type CuposService interface {
+	// Uso devuelve las clases usadas en el período que contiene la fecha ("YYYY-MM-DD", vacía = hoy en la sucursal)
+	Uso(ctx context.Context, usuarioID uint, fecha string, sucursalID *uint, authToken string) (domain.UsoCupo, error)
+	// ReintegrarSuscripcion reintegra las clases desde hoy de una suscripción cancelada por reembolso
+	ReintegrarSuscripcion(ctx context.Context, suscripcionID string) (int, error)
+}
+
+// maxDiasSesionesFijas es hasta dónde se buscan las sesiones generadas de una inscripción fija
+// (el horizonte de generación es de semanas: alcanza para todas)
+const maxDiasSesionesFijas = 366
+
+// CuposServiceImpl implementa CuposService; también es el CupoClases de las inscripciones y el CupoSesiones de las sesiones
+// Cada clase se descuenta del período (día, semana ISO o mes) de la fecha de la clase, no de la inscripción:
+// una reserva descuenta su sesión y una inscripción fija cada sesión de la actividad desde que se inscribió.
+// Las clases se descuentan en la transacción que crea la inscripción (inscripcionesRepo.Create con el cargo de Verificar):
+// la sesión reservada o las sesiones ya generadas de una fija. Las que se generan después las descuenta DescontarFijas.
+// Calcular el uso solo lee los consumos registrados
+type CuposServiceImpl struct {
+	repo              repository.CuposRepository
+	inscripcionesRepo repository.InscripcionesRepository
+	actividadesRepo   repository.ActividadesRepository
+	sesionesRepo      repository.SesionesRepository
+	sucursalesRepo    repository.SucursalesRepository
+	suscripciones     SuscripcionActivaProvider
+	now               func() time.Time
+}
+
+// NewCuposService crea una nueva instancia del servicio
+func NewCuposService(repo repository.CuposRepository, inscripcionesRepo repository.InscripcionesRepository, actividadesRepo repository.ActividadesRepository, sesionesRepo repository.SesionesRepository, sucursalesRepo repository.SucursalesRepository, suscripciones SuscripcionActivaProvider) *CuposServiceImpl {
+	return &CuposServiceImpl{
+		repo:              repo,
+		inscripcionesRepo: inscripcionesRepo,
+		actividadesRepo:   actividadesRepo,
+		sesionesRepo:      sesionesRepo,
+		sucursalesRepo:    sucursalesRepo,
+		suscripciones:     suscripciones,
+		now:               time.Now,
+	}
+}
+
+// Uso arma "3 de 4 clases usadas esta semana" con el plan de la suscripción activa
+// La semana (o el día, o el mes) es el de la fecha en la zona horaria de la sucursal
+func (s *CuposServiceImpl) Uso(ctx context.Context, usuarioID uint, fecha string, sucursalID *uint, authToken string) (domain.UsoCupo, error) {
+	loc := gymLocation()
+	if sucursalID != nil {
+		sucursal, err := s.sucursalesRepo.GetByID(ctx, *sucursalID)
+		if err != nil {
+			return domain.UsoCupo{}, err
+		}
+		loc = zonaSucursal(sucursal)
+	}
+
+	hoy := s.now().In(loc)
+	dia := hoy
+	if fecha != "" {
+		var err error
+		if dia, err = time.ParseInLocation("2006-01-02", fecha, loc); err != nil {
+			return domain.UsoCupo{}, ErrFechaInvalida
+		}
+	}
+
+	subscription, err := s.suscripciones.ActiveSubscription(ctx, usuarioID, authToken)
+	if err != nil {
+		return domain.UsoCupo{}, err
+	}
+
+	periodo, limite := subscription.PlanInfo.Cupo()
+	clave, desde, hasta := domain.PeriodoCupo(periodo, dia)
+	consumos, err := s.repo.ListByUsuario(ctx, usuarioID, desde, hasta)
+	if err != nil {
+		return domain.UsoCupo{}, err
+	}
+
+	titulos := map[uint]string{}
+	for i, consumo := range consumos {
+		titulo, ok := titulos[consumo.ActividadID]
+		if !ok {
+			if actividad, err := s.actividadesRepo.GetByID(ctx, consumo.ActividadID); err == nil {
+				titulo = actividad.Titulo
+			}
+			titulos[consumo.ActividadID] = titulo
+		}
+		consumos[i].Titulo = titulo
+	}
+
+	usadas := contarUsadas(consumos)
+	hoyClave, _, _ := domain.PeriodoCupo(periodo, hoy)
+	uso := domain.UsoCupo{
+		Periodo:      periodo,
+		PeriodoClave: clave,
+		Desde:        desde.Format("2006-01-02"),
+		Hasta:        hasta.Format("2006-01-02"),
+		Usadas:       usadas,
+		Limite:       limite,
+		Plan:         subscription.PlanInfo.Nombre,
+		Mensaje:      mensajeCupo(usadas, limite, periodo, clave, clave == hoyClave),
+		Clases:       consumos,
+	}
+	if limite > 0 {
+		disponibles := max(limite-usadas, 0)
+		uso.Disponibles = &disponibles
+	}
+	return uso, nil
+}
+
+// Verificar valida que el plan deje reservar la sesión (o inscribirse a la actividad) en el período de la clase
+// y arma el cargo que inscripcionesRepo.Create vuelve a validar y descuenta en la transacción de la inscripción
+// Una inscripción fija descuenta todas sus sesiones futuras ya generadas: cada período que toca tiene que tener
+// lugar para sus clases (si todavía no se generó ninguna, tiene que quedar lugar en el período de hoy)
+func (s *CuposServiceImpl) Verificar(ctx context.Context, inscripcion domain.Inscripcion, plan Plan) (*domain.CargoCupo, error) {
+	periodo, limite := plan.Cupo()
+
+	loc, err := s.zonaActividad(ctx, inscripcion.ActividadID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	sesiones, err := s.sesionesACobrar(ctx, inscripcion.ActividadID, inscripcion.SesionID, now, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	cargo := &domain.CargoCupo{Limite: limite}
+	for _, sesion := range sesiones {
+		if _, err := time.Parse("2006-01-02", sesion.Fecha); err != nil {
+			return nil, ErrFechaInvalida
+		}
+		cargo.Consumos = append(cargo.Consumos, consumoDe(inscripcion, sesion, periodo))
+	}
+	if limite == 0 {
+		return cargo, nil
+	}
+
+	periodos := cargo.Periodos()
+	if len(periodos) == 0 {
+		// Sin clases para descontar todavía: que el plan no esté agotado hoy
+		clave, desde, hasta := domain.PeriodoCupo(periodo, now.In(loc))
+		periodos = []domain.PeriodoCargo{{Clave: clave, Desde: desde, Hasta: hasta}}
+	}
+
+	for _, p := range periodos {
+		consumos, err := s.repo.ListByUsuario(ctx, inscripcion.UsuarioID, p.Desde, p.Hasta)
+		if err != nil {
+			return nil, fmt.Errorf("error verificando el cupo de clases: %w", err)
+		}
+
+		usadas := contarUsadas(consumos)
+		log.Printf("📊 [Cupos] Usuario %d: %d de %d clases (+%d) (%s %s)", inscripcion.UsuarioID, usadas, limite, p.Nuevas, periodo, p.Clave)
+		if usadas >= limite {
+			return nil, fmt.Errorf("%w: ya usaste %d de %d clases %s de tu plan '%s' (%s). Espera al próximo período o mejora tu plan",
+				domain.ErrCupoAgotado, usadas, limite, adjetivoPeriodo(periodo, limite), plan.Nombre, p.Clave)
+		}
+		if usadas+p.Nuevas > limite {
+			return nil, fmt.Errorf("%w: la inscripción suma %d clases en %s y ya usaste %d de %d clases %s de tu plan '%s'",
+				domain.ErrCupoAgotado, p.Nuevas, p.Clave, usadas, limite, adjetivoPeriodo(periodo, limite), plan.Nombre)
+		}
+	}
+	return cargo, nil
+}
+
+// Reintegrar devuelve al cupo las clases de la inscripción que todavía no empezaron (en la zona de su sucursal)
+func (s *CuposServiceImpl) Reintegrar(ctx context.Context, inscripcion domain.Inscripcion, motivo string) (int, error) {
+	loc, err := s.zonaActividad(ctx, inscripcion.ActividadID)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.now()
+	hoy := now.In(loc)
+
+	consumos, err := s.repo.ListByInscripcion(ctx, inscripcion.ID, hoy)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := []uint{}
+	for _, consumo := range consumos {
+		if consumo.Estado != domain.ConsumoUsado {
+			continue
+		}
+		// Una clase de hoy que ya empezó se usó
+		if consumo.FechaClase == hoy.Format("2006-01-02") {
+			sesion, err := s.sesionesRepo.GetByID(ctx, consumo.SesionID)
+			if err == nil && !inicioEn(sesion, loc).After(now) {
+				continue
+			}
+		}
+		ids = append(ids, consumo.ID)
+	}
+
+	reintegradas, err := s.repo.Reintegrar(ctx, ids, motivo)
+	if err != nil {
+		return 0, err
+	}
+	if reintegradas > 0 {
+		log.Printf("↩️  [Cupos] %d clases reintegradas al usuario %d (inscripción %d, %s)", reintegradas, inscripcion.UsuarioID, inscripcion.ID, motivo)
+	}
+	return int(reintegradas), nil
+}
+
+// ReintegrarSesion devuelve al cupo la sesión que canceló el gimnasio a todos los que la tenían descontada
+func (s *CuposServiceImpl) ReintegrarSesion(ctx context.Context, sesionID uint) (int, error) {
+	reintegradas, err := s.repo.ReintegrarSesion(ctx, sesionID, domain.ReintegroClaseCancelada)
+	if err != nil {
+		return 0, err
+	}
+	if reintegradas > 0 {
+		log.Printf("↩️  [Cupos] %d clases reintegradas por la cancelación de la sesión %d", reintegradas, sesionID)
+	}
+	return int(reintegradas), nil
+}
+
+// ReintegrarSuscripcion devuelve al cupo las clases descontadas con la suscripción desde hoy
+// (hoy en la zona de la sucursal de cada actividad, como al darse de baja)
+func (s *CuposServiceImpl) ReintegrarSuscripcion(ctx context.Context, suscripcionID string) (int, error) {
+	now := s.now()
+	// Desde ayer en UTC ninguna zona queda afuera; cada actividad filtra después con su propio hoy
+	consumos, err := s.repo.ListBySuscripcion(ctx, suscripcionID, now.UTC().AddDate(0, 0, -1))
+	if err != nil {
+		return 0, err
+	}
+
+	hoyActividad := map[uint]string{}
+	ids := []uint{}
+	for _, consumo := range consumos {
+		if consumo.Estado != domain.ConsumoUsado {
+			continue
+		}
+		hoy, ok := hoyActividad[consumo.ActividadID]
+		if !ok {
+			loc, err := s.zonaActividad(ctx, consumo.ActividadID)
+			if err != nil {
+				return 0, err
+			}
+			hoy = now.In(loc).Format("2006-01-02")
+			hoyActividad[consumo.ActividadID] = hoy
+		}
+		if consumo.FechaClase >= hoy {
+			ids = append(ids, consumo.ID)
+		}
+	}
+
+	reintegradas, err := s.repo.Reintegrar(ctx, ids, domain.ReintegroReembolso)
+	if err != nil {
+		return 0, err
+	}
+	if reintegradas > 0 {
+		log.Printf("↩️  [Cupos] %d clases reintegradas por el reembolso de la suscripción %s", reintegradas, suscripcionID)
+	}
+	return int(reintegradas), nil
+}
+
+// MoverSesion pasa las clases descontadas de una sesión reprogramada al período de su nueva fecha
+func (s *CuposServiceImpl) MoverSesion(ctx context.Context, sesion domain.Sesion) error {
+	fecha, err := time.Parse("2006-01-02", sesion.Fecha)
+	if err != nil {
+		return ErrFechaInvalida
+	}
+	return s.repo.MoverSesion(ctx, sesion.ID, fecha)
+}
+
+// DescontarFijas descuenta las sesiones futuras ya generadas de las inscripciones fijas activas de la actividad
+// La llama la generación de sesiones; cada fija se descuenta con el plan vigente del socio y las clases que ya
+// estaban registradas (también las reintegradas) no se tocan. Cada período se valida por separado: si el plan
+// no tiene lugar en una semana (o día, o mes), esas sesiones no se descuentan y se avisa en el log
+func (s *CuposServiceImpl) DescontarFijas(ctx context.Context, actividadID uint) error {
+	inscripciones, err := s.inscripcionesRepo.ListByActividad(ctx, actividadID)
+	if err != nil {
+		return err
+	}
+
+	var fijas []domain.Inscripcion
+	for _, insc := range inscripciones {
+		if insc.IsActiva && insc.SesionID == nil {
+			fijas = append(fijas, insc)
+		}
+	}
+	if len(fijas) == 0 {
+		return nil
+	}
+
+	loc, err := s.zonaActividad(ctx, actividadID)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	sesiones, err := s.sesionesACobrar(ctx, actividadID, nil, now, loc)
+	if err != nil || len(sesiones) == 0 {
+		return err
+	}
+
+	for _, insc := range fijas {
+		// Sin token: la suscripción sale de la copia local
+		subscription, err := s.suscripciones.ActiveSubscription(ctx, insc.UsuarioID, "")
+		if err != nil {
+			log.Printf("⚠️  [Cupos] Sin plan para descontar las sesiones de la inscripción %d (usuario %d): %v", insc.ID, insc.UsuarioID, err)
+			continue
+		}
+		periodo, limite := subscription.PlanInfo.Cupo()
+
+		registrados, err := s.repo.ListByInscripcion(ctx, insc.ID, now.In(loc))
+		if err != nil {
+			return err
+		}
+		yaRegistradas := map[uint]bool{}
+		for _, consumo := range registrados {
+			yaRegistradas[consumo.SesionID] = true
+		}
+
+		// Un cargo por período, en orden: uno agotado no frena a los siguientes
+		var cargos []domain.CargoCupo
+		indice := map[string]int{}
+		for _, sesion := range sesiones {
+			if yaRegistradas[sesion.ID] {
+				continue
+			}
+			consumo := consumoDe(insc, sesion, periodo)
+			i, ok := indice[consumo.PeriodoClave]
+			if !ok {
+				i = len(cargos)
+				indice[consumo.PeriodoClave] = i
+				cargos = append(cargos, domain.CargoCupo{Limite: limite})
+			}
+			cargos[i].Consumos = append(cargos[i].Consumos, consumo)
+		}
+
+		for _, cargo := range cargos {
+			err := s.repo.Descontar(ctx, insc, cargo)
+			if errors.Is(err, domain.ErrCupoAgotado) {
+				log.Printf("⚠️  [Cupos] Cupo agotado: %d sesiones de la inscripción fija %d (usuario %d) no se descontaron: %v", len(cargo.Consumos), insc.ID, insc.UsuarioID, err)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sesionesACobrar devuelve la sesión reservada o, para una inscripción fija, las sesiones futuras ya generadas
+// de la actividad, primero la próxima (ninguna si todavía no se generó: se generan por adelantado)
+// loc es la zona de la sucursal de la actividad
+func (s *CuposServiceImpl) sesionesACobrar(ctx context.Context, actividadID uint, sesionID *uint, now time.Time, loc *time.Location) ([]domain.Sesion, error) {
+	if sesionID != nil {
+		sesion, err := s.sesionesRepo.GetByID(ctx, *sesionID)
+		if err != nil {
+			return nil, err
+		}
+		return []domain.Sesion{sesion}, nil
+	}
+
+	hoy := now.In(loc)
+	generadas, err := s.sesionesRepo.ListByActividad(ctx, actividadID, hoy, hoy.AddDate(0, 0, maxDiasSesionesFijas))
+	if err != nil {
+		return nil, err
+	}
+	var futuras []domain.Sesion
+	for _, sesion := range generadas {
+		if !sesion.Cancelada() && inicioEn(sesion, loc).After(now) {
+			futuras = append(futuras, sesion)
+		}
+	}
+	return futuras, nil
+}
+
+// zonaActividad devuelve la zona horaria de la sucursal de la actividad (la del gimnasio si no tiene)
+func (s *CuposServiceImpl) zonaActividad(ctx context.Context, actividadID uint) (*time.Location, error) {
+	actividad, err := s.actividadesRepo.GetByID(ctx, actividadID)
+	if err != nil {
+		return nil, err
+	}
+	if actividad.SucursalID == nil {
+		return gymLocation(), nil
+	}
+
+	sucursal, err := s.sucursalesRepo.GetByID(ctx, *actividad.SucursalID)
+	if err != nil && !errors.Is(err, domain.ErrSucursalNotFound) {
+		return nil, err
+	}
+	return zonaSucursal(sucursal), nil
+}
+
+// inicioEn devuelve el momento en que empieza la sesión con su horario en la zona indicada
+func inicioEn(sesion domain.Sesion, loc *time.Location) time.Time {
+	inicio, _, err := horarioLocal(sesion.Fecha, sesion.HorarioInicio, sesion.HorarioFinal, loc)
+	if err != nil {
+		return time.Time{}
+	}
+	return inicio
+}
+
+// consumoDe arma el consumo de una sesión para la inscripción
+func consumoDe(inscripcion domain.Inscripcion, sesion domain.Sesion, periodo string) domain.ConsumoCupo {
+	consumo := domain.ConsumoCupo{
+		UsuarioID:     inscripcion.UsuarioID,
+		InscripcionID: inscripcion.ID,
+		ActividadID:   inscripcion.ActividadID,
+		SesionID:      sesion.ID,
+		FechaClase:    sesion.Fecha,
+		Periodo:       periodo,
+		Estado:        domain.ConsumoUsado,
+	}
+	if inscripcion.SuscripcionID != nil {
+		consumo.SuscripcionID = *inscripcion.SuscripcionID
+	}
+	if fecha, err := time.Parse("2006-01-02", sesion.Fecha); err == nil {
+		consumo.PeriodoClave, _, _ = domain.PeriodoCupo(periodo, fecha)
+	}
+	return consumo
+}
+
+func contarUsadas(consumos []domain.ConsumoCupo) int {
+	usadas := 0
+	for _, consumo := range consumos {
+		if consumo.Estado == domain.ConsumoUsado {
+			usadas++
+		}
+	}
+	return usadas
+}
+
+// adjetivoPeriodo devuelve "semanales", "diarias" o "mensuales" (en singular para una sola clase)
+func adjetivoPeriodo(periodo string, cantidad int) string {
+	adjetivo := map[string]string{
+		domain.CupoDiario:  "diaria",
+		domain.CupoSemanal: "semanal",
+		domain.CupoMensual: "mensual",
+	}[periodo]
+	if cantidad == 1 {
+		return adjetivo
+	}
+	if periodo == domain.CupoSemanal || periodo == domain.CupoMensual {
+		return adjetivo + "es"
+	}
+	return adjetivo + "s"
+}
+
+// mensajeCupo arma "3 de 4 clases usadas esta semana" (o "2 clases usadas este mes (sin límite)")
+func mensajeCupo(usadas, limite int, periodo, clave string, actual bool) string {
+	cuando := map[string]string{
+		domain.CupoDiario:  "hoy",
+		domain.CupoSemanal: "esta semana",
+		domain.CupoMensual: "este mes",
+	}[periodo]
+	if !actual {
+		cuando = map[string]string{
+			domain.CupoDiario:  "el " + clave,
+			domain.CupoSemanal: "la semana " + clave,
+			domain.CupoMensual: "en " + clave,
+		}[periodo]
+	}
+
+	if limite == 0 {
+		if usadas == 1 {
+			return fmt.Sprintf("1 clase usada %s (sin límite)", cuando)
+		}
+		return fmt.Sprintf("%d clases usadas %s (sin límite)", usadas, cuando)
+	}
+	if limite == 1 {
+		return fmt.Sprintf("%d de 1 clase usada %s", usadas, cuando)
+	}
+	return fmt.Sprintf("%d de %d clases usadas %s", usadas, limite, cuando)
+}
@@ -0,0 +1,634 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// --- Manual Mocks ---
+
+type MockCuposRepository struct {
+	consumos map[uint]domain.ConsumoCupo
+	nextID   uint
+}
+
+func newMockCuposRepository() *MockCuposRepository {
+	return &MockCuposRepository{consumos: make(map[uint]domain.ConsumoCupo)}
+}
+
+func (m *MockCuposRepository) Descontar(ctx context.Context, inscripcion domain.Inscripcion, cargo domain.CargoCupo) error {
+	return m.cargar(inscripcion, cargo, false)
+}
+
+// descontar registra el cargo como la transacción de inscripcionesRepo.Create: vuelve a contar cada período
+func (m *MockCuposRepository) descontar(inscripcion domain.Inscripcion, cargo domain.CargoCupo) error {
+	return m.cargar(inscripcion, cargo, true)
+}
+
+func (m *MockCuposRepository) cargar(inscripcion domain.Inscripcion, cargo domain.CargoCupo, reactivar bool) error {
+	if cargo.Limite > 0 {
+		for _, periodo := range cargo.Periodos() {
+			usados, _ := m.ListByUsuario(context.Background(), inscripcion.UsuarioID, periodo.Desde, periodo.Hasta)
+			if contarUsadas(usados)+periodo.Nuevas > cargo.Limite {
+				return domain.ErrCupoAgotado
+			}
+		}
+	}
+	for i := range cargo.Consumos {
+		cargo.Consumos[i].InscripcionID = inscripcion.ID
+	}
+	m.registrar(cargo.Consumos, reactivar)
+	return nil
+}
+func (m *MockCuposRepository) ListByUsuario(ctx context.Context, usuarioID uint, desde, hasta time.Time) ([]domain.ConsumoCupo, error) {
+	return m.filtrar(func(c domain.ConsumoCupo) bool {
+		return c.UsuarioID == usuarioID && c.FechaClase >= desde.Format("2006-01-02") && c.FechaClase <= hasta.Format("2006-01-02")
+	}), nil
+}
+func (m *MockCuposRepository) ListByInscripcion(ctx context.Context, inscripcionID uint, desde time.Time) ([]domain.ConsumoCupo, error) {
+	return m.filtrar(func(c domain.ConsumoCupo) bool {
+		return c.InscripcionID == inscripcionID && c.FechaClase >= desde.Format("2006-01-02")
+	}), nil
+}
+func (m *MockCuposRepository) Reintegrar(ctx context.Context, ids []uint, motivo string) (int64, error) {
+	return m.reintegrar(motivo, func(c domain.ConsumoCupo) bool {
+		for _, id := range ids {
+			if c.ID == id {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+func (m *MockCuposRepository) ReintegrarSesion(ctx context.Context, sesionID uint, motivo string) (int64, error) {
+	return m.reintegrar(motivo, func(c domain.ConsumoCupo) bool { return c.SesionID == sesionID }), nil
+}
+func (m *MockCuposRepository) ListBySuscripcion(ctx context.Context, suscripcionID string, desde time.Time) ([]domain.ConsumoCupo, error) {
+	return m.filtrar(func(c domain.ConsumoCupo) bool {
+		return c.SuscripcionID == suscripcionID && c.FechaClase >= desde.Format("2006-01-02")
+	}), nil
+}
+func (m *MockCuposRepository) MoverSesion(ctx context.Context, sesionID uint, fecha time.Time) error {
+	for id, consumo := range m.consumos {
+		if consumo.SesionID == sesionID {
+			consumo.FechaClase = fecha.Format("2006-01-02")
+			consumo.PeriodoClave, _, _ = domain.PeriodoCupo(consumo.Periodo, fecha)
+			m.consumos[id] = consumo
+		}
+	}
+	return nil
+}
+
+// registrar inserta los consumos nuevos; los que ya estaban solo se vuelven a usar si reactivar
+func (m *MockCuposRepository) registrar(consumos []domain.ConsumoCupo, reactivar bool) {
+	for _, consumo := range consumos {
+		consumo.Estado = domain.ConsumoUsado
+		for id, existente := range m.consumos {
+			if existente.InscripcionID == consumo.InscripcionID && existente.SesionID == consumo.SesionID {
+				consumo.ID = id
+			}
+		}
+		if consumo.ID == 0 {
+			m.nextID++
+			consumo.ID = m.nextID
+		} else if !reactivar {
+			continue
+		}
+		m.consumos[consumo.ID] = consumo
+	}
+}
+func (m *MockCuposRepository) filtrar(incluir func(domain.ConsumoCupo) bool) []domain.ConsumoCupo {
+	var result []domain.ConsumoCupo
+	for _, consumo := range m.consumos {
+		if incluir(consumo) {
+			result = append(result, consumo)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].FechaClase != result[j].FechaClase {
+			return result[i].FechaClase < result[j].FechaClase
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+func (m *MockCuposRepository) reintegrar(motivo string, incluir func(domain.ConsumoCupo) bool) int64 {
+	var n int64
+	for id, consumo := range m.consumos {
+		if consumo.Estado == domain.ConsumoUsado && incluir(consumo) {
+			consumo.Estado = domain.ConsumoReintegrado
+			consumo.MotivoReintegro = motivo
+			m.consumos[id] = consumo
+			n++
+		}
+	}
+	return n
+}
+
+// cuposTest es un socio (usuario 5) con sus inscripciones y las sesiones de las actividades
+// "Ahora" es el miércoles 15/01/2025 10:00 (semana 2025-W03, del lunes 13 al domingo 19)
+type cuposTest struct {
+	service       *CuposServiceImpl
+	repo          *MockCuposRepository
+	sesiones      *MockSesionesRepository
+	inscripciones *[]domain.Inscripcion
+	plan          Plan
+}
+
+func newCuposTest(inscripciones *[]domain.Inscripcion, plan Plan) *cuposTest {
+	test := &cuposTest{
+		repo:          newMockCuposRepository(),
+		sesiones:      newMockSesionesRepository(),
+		inscripciones: inscripciones,
+		plan:          plan,
+	}
+
+	actividades := &MockActividadesRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (domain.Actividad, error) {
+			return domain.Actividad{ID: id, Titulo: "Yoga"}, nil
+		},
+	}
+	suscripciones := &MockSuscripcionActiva{
+		ActiveSubscriptionFunc: func(ctx context.Context, usuarioID uint, authToken string) (Subscription, error) {
+			return Subscription{ID: "sub-1", PlanInfo: test.plan}, nil
+		},
+	}
+
+	test.service = NewCuposService(test.repo, newInscripcionesEnMemoria(20, inscripciones), actividades, test.sesiones, newMockSucursalesRepository(), suscripciones)
+	test.service.now = func() time.Time {
+		return time.Date(2025, 1, 15, 10, 0, 0, 0, gymLocation())
+	}
+	return test
+}
+
+func (c *cuposTest) sesion(actividadID uint, fecha, horario string) uint {
+	c.sesiones.nextID++
+	c.sesiones.sesiones[c.sesiones.nextID] = domain.Sesion{
+		ID:            c.sesiones.nextID,
+		ActividadID:   actividadID,
+		Fecha:         fecha,
+		HorarioInicio: horario,
+		HorarioFinal:  "23:00",
+		Estado:        domain.SesionProgramada,
+	}
+	return c.sesiones.nextID
+}
+
+// reservar crea la reserva de la sesión y la descuenta del cupo como la transacción de la inscripción
+func (c *cuposTest) reservar(t *testing.T, sesionID uint) domain.Inscripcion {
+	t.Helper()
+	return c.inscribir(t, reserva(sesionID, c.sesiones.sesiones[sesionID].ActividadID))
+}
+
+// inscribir crea (o reactiva, si ya tiene ID) la inscripción y la descuenta del cupo como la transacción de la inscripción
+func (c *cuposTest) inscribir(t *testing.T, inscripcion domain.Inscripcion) domain.Inscripcion {
+	t.Helper()
+	cargo, err := c.service.Verificar(context.Background(), inscripcion, c.plan)
+	if err != nil {
+		t.Fatalf("Expected inscripcion to activity %d allowed, got %v", inscripcion.ActividadID, err)
+	}
+
+	inscripcion.IsActiva = true
+	if inscripcion.ID == 0 {
+		inscripcion.ID = uint(len(*c.inscripciones) + 1)
+		*c.inscripciones = append(*c.inscripciones, inscripcion)
+	} else {
+		(*c.inscripciones)[inscripcion.ID-1] = inscripcion
+	}
+	if err := c.repo.descontar(inscripcion, *cargo); err != nil {
+		t.Fatalf("Expected no error charging inscripcion %d, got %v", inscripcion.ID, err)
+	}
+	return inscripcion
+}
+
+// reserva arma la inscripción del usuario 5 a la sesión
+func reserva(sesionID, actividadID uint) domain.Inscripcion {
+	return domain.Inscripcion{UsuarioID: 5, ActividadID: actividadID, SesionID: &sesionID, IsActiva: true}
+}
+
+// --- Tests ---
+
+func TestPeriodoCupo(t *testing.T) {
+	cases := []struct {
+		periodo, fecha, clave, desde, hasta string
+	}{
+		{domain.CupoSemanal, "2025-01-15", "2025-W03", "2025-01-13", "2025-01-19"},
+		{domain.CupoSemanal, "2025-01-19", "2025-W03", "2025-01-13", "2025-01-19"},
+		{domain.CupoSemanal, "2024-12-31", "2025-W01", "2024-12-30", "2025-01-05"},
+		{domain.CupoDiario, "2025-01-15", "2025-01-15", "2025-01-15", "2025-01-15"},
+		{domain.CupoMensual, "2024-02-10", "2024-02", "2024-02-01", "2024-02-29"},
+	}
+
+	for _, tc := range cases {
+		fecha, _ := time.ParseInLocation("2006-01-02", tc.fecha, gymLocation())
+		clave, desde, hasta := domain.PeriodoCupo(tc.periodo, fecha)
+		if clave != tc.clave || desde.Format("2006-01-02") != tc.desde || hasta.Format("2006-01-02") != tc.hasta {
+			t.Errorf("%s %s: expected %s [%s, %s], got %s [%s, %s]", tc.periodo, tc.fecha, tc.clave, tc.desde, tc.hasta,
+				clave, desde.Format("2006-01-02"), hasta.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestPlanCupo(t *testing.T) {
+	cases := []struct {
+		name    string
+		plan    Plan
+		periodo string
+		limite  int
+	}{
+		{"cupo mensual", Plan{CupoClases: 8, CupoPeriodo: domain.CupoMensual}, domain.CupoMensual, 8},
+		{"cupo sin período", Plan{CupoClases: 3}, domain.CupoSemanal, 3},
+		{"límite semanal anterior", Plan{TipoAcceso: "limitado", ActividadesPorSemana: 2}, domain.CupoSemanal, 2},
+		{"acceso completo", Plan{TipoAcceso: "completo", ActividadesPorSemana: 2}, domain.CupoSemanal, 0},
+	}
+
+	for _, tc := range cases {
+		if periodo, limite := tc.plan.Cupo(); periodo != tc.periodo || limite != tc.limite {
+			t.Errorf("%s: expected %s/%d, got %s/%d", tc.name, tc.periodo, tc.limite, periodo, limite)
+		}
+	}
+}
+
+func TestVerificar_CuentaLaSemanaDeLaClase(t *testing.T) {
+	test := newCuposTest(&[]domain.Inscripcion{}, Plan{Nombre: "Dos por semana", CupoClases: 2, CupoPeriodo: domain.CupoSemanal})
+	ctx := context.Background()
+
+	// Reservada la semana pasada: no cuenta para esta aunque la reserva sea reciente
+	test.reservar(t, test.sesion(7, "2025-01-10", "19:00"))
+	test.reservar(t, test.sesion(7, "2025-01-16", "19:00"))
+
+	viernes := test.sesion(7, "2025-01-17", "19:00")
+	test.reservar(t, viernes)
+
+	sabado := test.sesion(7, "2025-01-18", "10:00")
+	_, err := test.service.Verificar(ctx, reserva(sabado, 7), test.plan)
+	if !errors.Is(err, domain.ErrCupoAgotado) || !strings.Contains(err.Error(), "2 de 2 clases semanales") {
+		t.Fatalf("Expected ErrCupoAgotado with 2 de 2, got %v", err)
+	}
+
+	// Una clase de la semana próxima se descuenta de esa semana
+	lunes := test.sesion(7, "2025-01-20", "19:00")
+	if _, err := test.service.Verificar(ctx, reserva(lunes, 7), test.plan); err != nil {
+		t.Errorf("Expected next week's class allowed, got %v", err)
+	}
+
+	// Si el gimnasio cancela una clase, vuelve al cupo
+	if n, err := test.service.ReintegrarSesion(ctx, viernes); err != nil || n != 1 {
+		t.Fatalf("Expected 1 class refunded, got %d (%v)", n, err)
+	}
+	if _, err := test.service.Verificar(ctx, reserva(sabado, 7), test.plan); err != nil {
+		t.Errorf("Expected refunded class available again, got %v", err)
+	}
+}
+
+func TestVerificar_LaTransaccionVuelveAContar(t *testing.T) {
+	test := newCuposTest(&[]domain.Inscripcion{}, Plan{Nombre: "Una por semana", CupoClases: 1, CupoPeriodo: domain.CupoSemanal})
+	ctx := context.Background()
+
+	jueves := test.sesion(7, "2025-01-16", "19:00")
+	viernes := test.sesion(9, "2025-01-17", "19:00")
+
+	// Dos reservas simultáneas a actividades distintas pasan la validación previa
+	cargoJueves, err := test.service.Verificar(ctx, reserva(jueves, 7), test.plan)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	cargoViernes, err := test.service.Verificar(ctx, reserva(viernes, 9), test.plan)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(cargoJueves.Consumos) != 1 || cargoJueves.Consumos[0].SesionID != jueves || cargoJueves.Consumos[0].PeriodoClave != "2025-W03" ||
+		cargoJueves.Limite != 1 || cargoJueves.Periodos()[0].Desde.Format("2006-01-02") != "2025-01-13" {
+		t.Fatalf("Expected the Thursday class charged to 2025-W03, got %+v", cargoJueves)
+	}
+
+	// La transacción de la segunda ve la clase que descontó la primera
+	if err := test.repo.descontar(domain.Inscripcion{ID: 1, UsuarioID: 5}, *cargoJueves); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := test.repo.descontar(domain.Inscripcion{ID: 2, UsuarioID: 5}, *cargoViernes); !errors.Is(err, domain.ErrCupoAgotado) {
+		t.Fatalf("Expected ErrCupoAgotado, got %v", err)
+	}
+	if len(test.repo.consumos) != 1 {
+		t.Errorf("Expected only the Thursday class charged, got %+v", test.repo.consumos)
+	}
+}
+
+func TestVerificar_FijaMensualCuentaTodasSusSesiones(t *testing.T) {
+	test := newCuposTest(&[]domain.Inscripcion{}, Plan{Nombre: "Ocho por mes", CupoClases: 8, CupoPeriodo: domain.CupoMensual})
+	ctx := context.Background()
+
+	// 7 de 8 clases de enero usadas con reservas
+	for dia := 16; dia <= 22; dia++ {
+		test.reservar(t, test.sesion(7, fmt.Sprintf("2025-01-%02d", dia), "19:00"))
+	}
+
+	// La fija a la actividad 8 descontaría sus 3 sesiones de enero que quedan: 10 de 8
+	for _, fecha := range []string{"2025-01-17", "2025-01-24", "2025-01-31"} {
+		test.sesion(8, fecha, "08:00")
+	}
+	_, err := test.service.Verificar(ctx, domain.Inscripcion{UsuarioID: 5, ActividadID: 8}, test.plan)
+	if !errors.Is(err, domain.ErrCupoAgotado) || !strings.Contains(err.Error(), "suma 3 clases en 2025-01") {
+		t.Fatalf("Expected ErrCupoAgotado for 3 new classes in 2025-01, got %v", err)
+	}
+
+	// Aunque pase la validación previa, la transacción tampoco la deja pasar
+	cargo := &domain.CargoCupo{Limite: 8}
+	for _, sesion := range test.sesiones.sesiones {
+		if sesion.ActividadID == 8 {
+			cargo.Consumos = append(cargo.Consumos, consumoDe(domain.Inscripcion{UsuarioID: 5, ActividadID: 8}, sesion, domain.CupoMensual))
+		}
+	}
+	if err := test.repo.descontar(domain.Inscripcion{ID: 99, UsuarioID: 5}, *cargo); !errors.Is(err, domain.ErrCupoAgotado) {
+		t.Fatalf("Expected the transaction to reject 10 of 8, got %v", err)
+	}
+	if uso, _ := test.service.Uso(ctx, 5, "", nil, "token"); uso.Usadas != 7 {
+		t.Errorf("Expected 7 of 8 still used, got %d", uso.Usadas)
+	}
+}
+
+func TestVerificar_FijaCuentaCadaSemana(t *testing.T) {
+	test := newCuposTest(&[]domain.Inscripcion{}, Plan{Nombre: "Dos por semana", CupoClases: 2, CupoPeriodo: domain.CupoSemanal})
+	ctx := context.Background()
+
+	// Miércoles: las fijas de lunes y martes empiezan a descontar la semana próxima
+	test.sesion(7, "2025-01-20", "19:00")
+	test.sesion(8, "2025-01-21", "19:00")
+	test.inscribir(t, domain.Inscripcion{UsuarioID: 5, ActividadID: 7})
+	test.inscribir(t, domain.Inscripcion{UsuarioID: 5, ActividadID: 8})
+
+	// La del jueves entra esta semana, pero la semana próxima quedaría en 3 de 2
+	test.sesion(9, "2025-01-16", "19:00")
+	test.sesion(9, "2025-01-23", "19:00")
+	_, err := test.service.Verificar(ctx, domain.Inscripcion{UsuarioID: 5, ActividadID: 9}, test.plan)
+	if !errors.Is(err, domain.ErrCupoAgotado) || !strings.Contains(err.Error(), "2025-W04") {
+		t.Fatalf("Expected ErrCupoAgotado for 2025-W04, got %v", err)
+	}
+	if uso, _ := test.service.Uso(ctx, 5, "2025-01-22", nil, "token"); uso.Usadas != 2 {
+		t.Errorf("Expected 2 of 2 used next week, got %+v", uso.Clases)
+	}
+}
+
+func TestUso_DescuentaLasSesionesDeLasFijas(t *testing.T) {
+	test := newCuposTest(&[]domain.Inscripcion{}, Plan{Nombre: "Cuatro por semana", CupoClases: 4, CupoPeriodo: domain.CupoSemanal})
+	ctx := context.Background()
+
+	// Inscripción fija el miércoles: la sesión del lunes ya había pasado y la del sábado está cancelada
+	test.sesion(8, "2025-01-13", "18:00")
+	test.sesion(8, "2025-01-16", "18:00")
+	cancelada := test.sesion(8, "2025-01-18", "18:00")
+	sesion := test.sesiones.sesiones[cancelada]
+	sesion.Estado = domain.SesionCancelada
+	test.sesiones.sesiones[cancelada] = sesion
+	fija := test.inscribir(t, domain.Inscripcion{UsuarioID: 5, ActividadID: 8})
+	test.reservar(t, test.sesion(7, "2025-01-17", "19:00"))
+
+	uso, err := test.service.Uso(ctx, 5, "", nil, "token")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if uso.Usadas != 2 || uso.Limite != 4 || uso.Disponibles == nil || *uso.Disponibles != 2 {
+		t.Fatalf("Expected 2 of 4 used, got %+v", uso)
+	}
+	if uso.Mensaje != "2 de 4 clases usadas esta semana" || uso.PeriodoClave != "2025-W03" || uso.Desde != "2025-01-13" {
+		t.Errorf("Unexpected message or period: %q %s %s", uso.Mensaje, uso.PeriodoClave, uso.Desde)
+	}
+	if len(uso.Clases) != 2 || uso.Clases[0].FechaClase != "2025-01-16" || uso.Clases[0].Titulo != "Yoga" {
+		t.Errorf("Expected the standing class on 2025-01-16 first, got %+v", uso.Clases)
+	}
+
+	// La semana próxima no tiene sesiones generadas todavía: se descuentan al generarse, no al consultar el uso
+	proxima := test.sesion(8, "2025-01-23", "18:00")
+	uso, err = test.service.Uso(ctx, 5, "2025-01-22", nil, "token")
+	if err != nil || uso.Usadas != 0 || uso.Mensaje != "0 de 4 clases usadas la semana 2025-W04" {
+		t.Errorf("Expected nothing used next week, got %+v (%v)", uso, err)
+	}
+	if err := test.service.DescontarFijas(ctx, 8); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	uso, _ = test.service.Uso(ctx, 5, "2025-01-22", nil, "token")
+	if uso.Usadas != 1 || uso.Clases[0].SesionID != proxima || uso.Clases[0].InscripcionID != fija.ID {
+		t.Errorf("Expected the generated session charged to the standing enrollment, got %+v", uso.Clases)
+	}
+
+	if _, err := test.service.Uso(ctx, 5, "15/01/2025", nil, "token"); !errors.Is(err, ErrFechaInvalida) {
+		t.Errorf("Expected ErrFechaInvalida, got %v", err)
+	}
+}
+
+func TestDescontarFijas_RespetaElCupo(t *testing.T) {
+	test := newCuposTest(&[]domain.Inscripcion{}, Plan{Nombre: "Dos por semana", CupoClases: 2, CupoPeriodo: domain.CupoSemanal})
+	ctx := context.Background()
+
+	// La fija y una reserva llenan la semana; después se generan el sábado y el jueves próximo
+	test.sesion(8, "2025-01-16", "18:00")
+	test.inscribir(t, domain.Inscripcion{UsuarioID: 5, ActividadID: 8})
+	test.reservar(t, test.sesion(7, "2025-01-17", "19:00"))
+	sabado := test.sesion(8, "2025-01-18", "10:00")
+	proxima := test.sesion(8, "2025-01-23", "18:00")
+
+	if err := test.service.DescontarFijas(ctx, 8); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	uso, _ := test.service.Uso(ctx, 5, "", nil, "token")
+	if uso.Usadas != 2 || len(uso.Clases) != 2 {
+		t.Errorf("Expected the exhausted week not to be overdrawn, got %+v", uso.Clases)
+	}
+	for _, consumo := range uso.Clases {
+		if consumo.SesionID == sabado {
+			t.Errorf("Expected Saturday not charged, got %+v", consumo)
+		}
+	}
+	uso, _ = test.service.Uso(ctx, 5, "2025-01-22", nil, "token")
+	if uso.Usadas != 1 || uso.Clases[0].SesionID != proxima {
+		t.Errorf("Expected next week charged, got %+v", uso.Clases)
+	}
+
+	// Volver a generar no cobra dos veces la semana próxima
+	if err := test.service.DescontarFijas(ctx, 8); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if uso, _ = test.service.Uso(ctx, 5, "2025-01-22", nil, "token"); uso.Usadas != 1 {
+		t.Errorf("Expected 1 class next week, got %+v", uso.Clases)
+	}
+}
+
+func TestUso_SinLimite(t *testing.T) {
+	test := newCuposTest(&[]domain.Inscripcion{}, Plan{Nombre: "Full", TipoAcceso: "completo"})
+	test.reservar(t, test.sesion(7, "2025-01-16", "19:00"))
+
+	uso, err := test.service.Uso(context.Background(), 5, "", nil, "token")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if uso.Limite != 0 || uso.Disponibles != nil || uso.Mensaje != "1 clase usada esta semana (sin límite)" {
+		t.Errorf("Expected unlimited usage, got %+v", uso)
+	}
+}
+
+func TestReintegrar_SoloClasesQueNoEmpezaron(t *testing.T) {
+	test := newCuposTest(&[]domain.Inscripcion{}, Plan{CupoClases: 3, CupoPeriodo: domain.CupoSemanal})
+	ctx := context.Background()
+
+	// Inscripción fija a las 7:00, antes de la clase de las 8:00 de hoy
+	test.sesion(8, "2025-01-15", "08:00")
+	test.sesion(8, "2025-01-17", "08:00")
+	ahora := test.service.now
+	test.service.now = func() time.Time { return time.Date(2025, 1, 15, 7, 0, 0, 0, gymLocation()) }
+	fija := test.inscribir(t, domain.Inscripcion{UsuarioID: 5, ActividadID: 8})
+	test.service.now = ahora
+
+	fija.IsActiva = false
+	if n, err := test.service.Reintegrar(ctx, fija, domain.ReintegroCancelacion); err != nil || n != 1 {
+		t.Fatalf("Expected 1 class refunded, got %d (%v)", n, err)
+	}
+
+	uso, _ := test.service.Uso(ctx, 5, "", nil, "token")
+	if uso.Usadas != 1 || uso.Clases[1].Estado != domain.ConsumoReintegrado || uso.Clases[1].MotivoReintegro != domain.ReintegroCancelacion {
+		t.Errorf("Expected today's class still used and Friday refunded, got %+v", uso.Clases)
+	}
+
+	// Al volver a inscribirse el viernes se descuenta otra vez
+	test.inscribir(t, fija)
+	uso, _ = test.service.Uso(ctx, 5, "", nil, "token")
+	if uso.Usadas != 2 {
+		t.Errorf("Expected Friday charged again after re-enrolling, got %+v", uso.Clases)
+	}
+}
+
+func TestReintegrarSuscripcion_NoSeVuelveACobrar(t *testing.T) {
+	test := newCuposTest(&[]domain.Inscripcion{}, Plan{CupoClases: 2, CupoPeriodo: domain.CupoSemanal})
+	ctx := context.Background()
+
+	test.sesion(8, "2025-01-17", "08:00")
+	suscripcionID := "sub-1"
+	test.inscribir(t, domain.Inscripcion{UsuarioID: 5, ActividadID: 8, SuscripcionID: &suscripcionID})
+
+	// El reembolso deja la inscripción activa: ni el uso ni la generación de sesiones la vuelven a cobrar
+	if n, err := test.service.ReintegrarSuscripcion(ctx, "sub-1"); err != nil || n != 1 {
+		t.Fatalf("Expected 1 class refunded, got %d (%v)", n, err)
+	}
+	if err := test.service.DescontarFijas(ctx, 8); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	uso, err := test.service.Uso(ctx, 5, "", nil, "token")
+	if err != nil || uso.Usadas != 0 || len(uso.Clases) != 1 || uso.Clases[0].Estado != domain.ConsumoReintegrado {
+		t.Errorf("Expected the refunded class to stay refunded, got %+v (%v)", uso.Clases, err)
+	}
+}
+
+func TestCupos_ZonaDeLaSucursal(t *testing.T) {
+	inscripciones := &[]domain.Inscripcion{}
+	test := newCuposTest(inscripciones, Plan{Nombre: "Una por semana", CupoClases: 1, CupoPeriodo: domain.CupoSemanal})
+	ctx := context.Background()
+
+	// La actividad 7 es de una sucursal en Tokio (12 horas después del gimnasio)
+	tokio := uint(3)
+	sucursales := newMockSucursalesRepository()
+	sucursales.sucursales[tokio] = domain.Sucursal{ID: tokio, Activa: true, ZonaHoraria: "Asia/Tokyo"}
+	test.service.sucursalesRepo = sucursales
+	test.service.actividadesRepo = &MockActividadesRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (domain.Actividad, error) {
+			if id == 7 {
+				return domain.Actividad{ID: id, Titulo: "Yoga", SucursalID: &tokio}, nil
+			}
+			return domain.Actividad{ID: id, Titulo: "Spinning"}, nil
+		},
+	}
+
+	// Miércoles 10:00 en el gimnasio son las 22:00 en Tokio: la clase de las 21:00 ya empezó
+	empezada := test.reservar(t, test.sesion(7, "2025-01-15", "21:00"))
+	if n, err := test.service.Reintegrar(ctx, empezada, domain.ReintegroCancelacion); err != nil || n != 0 {
+		t.Errorf("Expected the started class kept, got %d refunded (%v)", n, err)
+	}
+
+	// Domingo 14:00 en el gimnasio ya es lunes en Tokio: una fija sin sesiones generadas mira la semana próxima
+	test.service.now = func() time.Time {
+		return time.Date(2025, 1, 19, 14, 0, 0, 0, gymLocation())
+	}
+	if _, err := test.service.Verificar(ctx, domain.Inscripcion{UsuarioID: 5, ActividadID: 7, IsActiva: true}, test.plan); err != nil {
+		t.Errorf("Expected week 2025-W04 in Tokyo to be free, got %v", err)
+	}
+	if _, err := test.service.Verificar(ctx, domain.Inscripcion{UsuarioID: 5, ActividadID: 8, IsActiva: true}, test.plan); !errors.Is(err, domain.ErrCupoAgotado) {
+		t.Errorf("Expected this week's quota used at the gym's branch, got %v", err)
+	}
+	// Jueves 20:00 en el gimnasio ya es viernes en Tokio: el reembolso no devuelve la clase del jueves allá
+	test.service.now = func() time.Time {
+		return time.Date(2025, 1, 16, 20, 0, 0, 0, gymLocation())
+	}
+	jueves := test.sesion(7, "2025-01-16", "08:00")
+	viernes := test.sesion(7, "2025-01-17", "08:00")
+	local := test.sesion(8, "2025-01-16", "22:00")
+	suscripcionID := "sub-2"
+	for i, sesionID := range []uint{jueves, viernes, local} {
+		test.repo.registrar([]domain.ConsumoCupo{{
+			UsuarioID: 6, InscripcionID: uint(100 + i), ActividadID: test.sesiones.sesiones[sesionID].ActividadID,
+			SesionID: sesionID, SuscripcionID: suscripcionID, FechaClase: test.sesiones.sesiones[sesionID].Fecha,
+			Periodo: domain.CupoSemanal, Estado: domain.ConsumoUsado,
+		}}, false)
+	}
+	if n, err := test.service.ReintegrarSuscripcion(ctx, suscripcionID); err != nil || n != 2 {
+		t.Fatalf("Expected 2 classes refunded, got %d (%v)", n, err)
+	}
+	for _, consumo := range test.repo.consumos {
+		if consumo.SuscripcionID != suscripcionID {
+			continue
+		}
+		if reintegrado := consumo.Estado == domain.ConsumoReintegrado; reintegrado == (consumo.SesionID == jueves) {
+			t.Errorf("Expected only Thursday in Tokyo kept, got %+v", consumo)
+		}
+	}
+}
+
+func TestReintegrarSuscripcion_Reembolso(t *testing.T) {
+	test := newCuposTest(&[]domain.Inscripcion{}, Plan{CupoClases: 2, CupoPeriodo: domain.CupoSemanal})
+	ctx := context.Background()
+
+	test.reservar(t, test.sesion(7, "2025-01-14", "19:00"))
+	manana := test.sesion(7, "2025-01-16", "19:00")
+	test.reservar(t, manana)
+	for id, consumo := range test.repo.consumos {
+		consumo.SuscripcionID = "sub-1"
+		test.repo.consumos[id] = consumo
+	}
+
+	if n, err := test.service.ReintegrarSuscripcion(ctx, "sub-1"); err != nil || n != 1 {
+		t.Fatalf("Expected 1 class refunded, got %d (%v)", n, err)
+	}
+	for _, consumo := range test.repo.consumos {
+		if reintegrado := consumo.Estado == domain.ConsumoReintegrado; reintegrado != (consumo.SesionID == manana) {
+			t.Errorf("Expected only tomorrow's class refunded, got %+v", consumo)
+		}
+		if consumo.SesionID == manana && consumo.MotivoReintegro != domain.ReintegroReembolso {
+			t.Errorf("Expected refund reason %s, got %s", domain.ReintegroReembolso, consumo.MotivoReintegro)
+		}
+	}
+}
+
+func TestMoverSesion_CambiaDePeriodo(t *testing.T) {
+	test := newCuposTest(&[]domain.Inscripcion{}, Plan{CupoClases: 1, CupoPeriodo: domain.CupoSemanal})
+	ctx := context.Background()
+
+	viernes := test.sesion(7, "2025-01-17", "19:00")
+	test.reservar(t, viernes)
+
+	sesion := test.sesiones.sesiones[viernes]
+	sesion.Fecha = "2025-01-21"
+	if err := test.service.MoverSesion(ctx, sesion); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	otra := test.sesion(7, "2025-01-18", "10:00")
+	if _, err := test.service.Verificar(ctx, reserva(otra, 7), test.plan); err != nil {
+		t.Errorf("Expected this week free after rescheduling, got %v", err)
+	}
+	if consumo := test.repo.consumos[1]; consumo.PeriodoClave != "2025-W04" {
+		t.Errorf("Expected class moved to 2025-W04, got %s", consumo.PeriodoClave)
+	}
+}
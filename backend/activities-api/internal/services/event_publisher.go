@@ -5,4 +5,6 @@ package services
 type EventPublisher interface {
 	PublishActivityEvent(action, activityID string, data map[string]interface{}) error
 	PublishInscriptionEvent(action, inscriptionID string, data map[string]interface{}) error
+	PublishAttendanceEvent(action, attendanceID string, data map[string]interface{}) error
+	PublishBranchEvent(action, branchID string, data map[string]interface{}) error
 }
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Formatos de fecha-hora de iCalendar (RFC 5545)
+const (
+	icsFechaHoraLocal = "20060102T150405"  // Con TZID
+	icsFechaHoraUTC   = "20060102T150405Z" // DTSTAMP, LAST-MODIFIED
+	icsLargoLinea     = 75                 // Octetos por línea antes de plegar
+)
+
+// diasICS traduce time.Weekday al BYDAY de las RRULE
+var diasICS = [7]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// eventoICS es un VEVENT del calendario; Inicio y Fin van en la zona de la sucursal (TZID)
+type eventoICS struct {
+	UID          string
+	Inicio       time.Time
+	Fin          time.Time
+	RRule        string      // Vacía = evento único
+	ExDates      []time.Time // Ocurrencias de la serie que no se dictan
+	RecurrenceID *time.Time  // Ocurrencia de la serie que reemplaza este evento (misma UID)
+	Resumen      string
+	Descripcion  string
+	Lugar        string
+	Geo          string // "lat;lon"
+	Estado       string // CONFIRMED | CANCELLED
+	UltimoCambio time.Time
+}
+
+// calendarioICS arma un VCALENDAR con los VTIMEZONE de las zonas que usan sus eventos
+type calendarioICS struct {
+	nombre  string
+	dtstamp time.Time
+	zonas   []*time.Location
+	eventos icsWriter
+	desde   time.Time // Primer inicio: desde ahí valen las reglas de los VTIMEZONE
+}
+
+func newCalendarioICS(nombre string, now time.Time) *calendarioICS {
+	return &calendarioICS{
+		nombre:  nombre,
+		dtstamp: now.UTC(),
+		desde:   now,
+	}
+}
+
+// agregar escribe el VEVENT y registra su zona horaria
+func (c *calendarioICS) agregar(e eventoICS) {
+	loc := e.Inicio.Location()
+	registrada := false
+	for _, zona := range c.zonas {
+		registrada = registrada || zona.String() == loc.String()
+	}
+	if !registrada {
+		c.zonas = append(c.zonas, loc)
+	}
+	if e.Inicio.Before(c.desde) {
+		c.desde = e.Inicio
+	}
+
+	w := &c.eventos
+	w.linea("BEGIN", "VEVENT")
+	w.linea("UID", e.UID)
+	w.linea("DTSTAMP", c.dtstamp.Format(icsFechaHoraUTC))
+	if e.RecurrenceID != nil {
+		w.fechaHora("RECURRENCE-ID", *e.RecurrenceID)
+	}
+	w.fechaHora("DTSTART", e.Inicio)
+	w.fechaHora("DTEND", e.Fin)
+	if e.RRule != "" {
+		w.linea("RRULE", e.RRule)
+	}
+	for _, exdate := range e.ExDates {
+		w.fechaHora("EXDATE", exdate)
+	}
+	w.texto("SUMMARY", e.Resumen)
+	if e.Descripcion != "" {
+		w.texto("DESCRIPTION", e.Descripcion)
+	}
+	if e.Lugar != "" {
+		w.texto("LOCATION", e.Lugar)
+	}
+	if e.Geo != "" {
+		w.linea("GEO", e.Geo)
+	}
+	if e.Estado != "" {
+		w.linea("STATUS", e.Estado)
+	}
+	if !e.UltimoCambio.IsZero() {
+		w.linea("LAST-MODIFIED", e.UltimoCambio.UTC().Format(icsFechaHoraUTC))
+	}
+	w.linea("END", "VEVENT")
+}
+
+// bytes devuelve el VCALENDAR completo
+func (c *calendarioICS) bytes() []byte {
+	var w icsWriter
+	w.linea("BEGIN", "VCALENDAR")
+	w.linea("VERSION", "2.0")
+	w.linea("PRODID", "-//Gym//activities-api//ES")
+	w.linea("CALSCALE", "GREGORIAN")
+	w.linea("METHOD", "PUBLISH")
+	w.texto("X-WR-CALNAME", c.nombre)
+	w.linea("REFRESH-INTERVAL;VALUE=DURATION", "PT1H")
+	w.linea("X-PUBLISHED-TTL", "PT1H")
+	for _, loc := range c.zonas {
+		escribirVTimezone(&w, loc, c.desde.In(loc).Year())
+	}
+	w.b.WriteString(c.eventos.b.String())
+	w.linea("END", "VCALENDAR")
+	return []byte(w.b.String())
+}
+
+// icsWriter escribe líneas de contenido con CRLF, plegadas a 75 octetos
+type icsWriter struct {
+	b strings.Builder
+}
+
+// linea escribe una propiedad con el valor tal cual
+func (w *icsWriter) linea(nombre, valor string) {
+	w.b.WriteString(plegarLineaICS(nombre + ":" + valor))
+	w.b.WriteString("\r\n")
+}
+
+// texto escribe una propiedad de tipo TEXT (escapa comas, punto y coma, barras y saltos de línea)
+func (w *icsWriter) texto(nombre, valor string) {
+	w.linea(nombre, escaparTextoICS(valor))
+}
+
+// fechaHora escribe una fecha-hora local con el TZID de su zona
+func (w *icsWriter) fechaHora(nombre string, t time.Time) {
+	w.linea(nombre+";TZID="+t.Location().String(), t.Format(icsFechaHoraLocal))
+}
+
+func escaparTextoICS(valor string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(valor)
+}
+
+// plegarLineaICS corta la línea cada 75 octetos sin partir caracteres UTF-8
+// Las líneas de continuación empiezan con un espacio (que cuenta dentro de los 75)
+func plegarLineaICS(linea string) string {
+	if len(linea) <= icsLargoLinea {
+		return linea
+	}
+
+	var b strings.Builder
+	largo := 0
+	for _, r := range linea {
+		n := utf8.RuneLen(r)
+		if largo+n > icsLargoLinea {
+			b.WriteString("\r\n ")
+			largo = 1
+		}
+		b.WriteRune(r)
+		largo += n
+	}
+	return b.String()
+}
+
+// transicionZona es un cambio de offset de la zona (entrada o salida del horario de verano)
+type transicionZona struct {
+	instante time.Time
+	desde    int // Offset en segundos antes del cambio
+	hacia    int
+	nombre   string // Abreviatura después del cambio
+}
+
+// escribirVTimezone describe la zona con las reglas de cambio de horario del año indicado
+// Las zonas sin horario de verano (ej. Argentina) llevan un único STANDARD
+func escribirVTimezone(w *icsWriter, loc *time.Location, anio int) {
+	w.linea("BEGIN", "VTIMEZONE")
+	w.linea("TZID", loc.String())
+
+	transiciones := transicionesZona(loc, anio)
+	if len(transiciones) == 0 {
+		nombre, offset := time.Date(anio, 1, 1, 0, 0, 0, 0, loc).Zone()
+		w.linea("BEGIN", "STANDARD")
+		w.linea("DTSTART", "19700101T000000")
+		w.linea("TZOFFSETFROM", offsetICS(offset))
+		w.linea("TZOFFSETTO", offsetICS(offset))
+		w.linea("TZNAME", nombre)
+		w.linea("END", "STANDARD")
+	}
+	for _, t := range transiciones {
+		componente := "STANDARD"
+		if t.hacia > t.desde {
+			componente = "DAYLIGHT"
+		}
+		// DTSTART va en la hora local de antes del cambio
+		local := t.instante.In(time.FixedZone("", t.desde))
+		w.linea("BEGIN", componente)
+		w.linea("DTSTART", local.Format(icsFechaHoraLocal))
+		w.linea("RRULE", reglaAnualICS(local))
+		w.linea("TZOFFSETFROM", offsetICS(t.desde))
+		w.linea("TZOFFSETTO", offsetICS(t.hacia))
+		w.linea("TZNAME", t.nombre)
+		w.linea("END", componente)
+	}
+
+	w.linea("END", "VTIMEZONE")
+}
+
+// transicionesZona busca los cambios de offset del año: día por día y después al minuto
+func transicionesZona(loc *time.Location, anio int) []transicionZona {
+	var transiciones []transicionZona
+
+	dia := time.Date(anio, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, anterior := dia.In(loc).Zone()
+	for dia.Year() == anio {
+		siguiente := dia.AddDate(0, 0, 1)
+		if _, offset := siguiente.In(loc).Zone(); offset != anterior {
+			// El cambio está entre dia y siguiente: búsqueda binaria en minutos
+			lo, hi := 0, 24*60
+			for hi-lo > 1 {
+				mid := (lo + hi) / 2
+				if _, o := dia.Add(time.Duration(mid) * time.Minute).In(loc).Zone(); o == anterior {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			instante := dia.Add(time.Duration(hi) * time.Minute)
+			nombre, _ := instante.In(loc).Zone()
+			transiciones = append(transiciones, transicionZona{instante: instante, desde: anterior, hacia: offset, nombre: nombre})
+			anterior = offset
+		}
+		dia = siguiente
+	}
+
+	return transiciones
+}
+
+// reglaAnualICS arma la RRULE anual del cambio de horario (ej. "segundo domingo de marzo" o "último domingo")
+func reglaAnualICS(t time.Time) string {
+	ordinal := (t.Day()-1)/7 + 1
+	if t.AddDate(0, 0, 7).Month() != t.Month() {
+		ordinal = -1
+	}
+	return fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", int(t.Month()), ordinal, diasICS[t.Weekday()])
+}
+
+// offsetICS formatea un offset en segundos como ±HHMM
+func offsetICS(segundos int) string {
+	signo := "+"
+	if segundos < 0 {
+		signo = "-"
+		segundos = -segundos
+	}
+	return fmt.Sprintf("%s%02d%02d", signo, segundos/3600, (segundos%3600)/60)
+}
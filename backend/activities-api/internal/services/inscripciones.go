@@ -4,21 +4,42 @@ import (
 	"activities-api/internal/domain"
 	"activities-api/internal/repository"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"time"
 
+	"github.com/yourusername/gym-management/shared/auth"
 	"golang.org/x/sync/errgroup"
 )
 
+// ErrRosterForbidden indica que el usuario no puede ver los inscriptos de la actividad
+var ErrRosterForbidden = errors.New("no tenés permiso para ver los inscriptos de esta actividad")
+
 // InscripcionesService define la interfaz del servicio de inscripciones
 type InscripcionesService interface {
 	ListByUser(ctx context.Context, usuarioID uint) ([]domain.InscripcionResponse, error)
-	Create(ctx context.Context, usuarioID, actividadID uint, authToken string) (domain.InscripcionResponse, error)
-	Deactivate(ctx context.Context, usuarioID, actividadID uint) error
+	ListByActividad(ctx context.Context, actividadID uint, viewer auth.Claims) ([]domain.InscripcionResponse, error)
+	Create(ctx context.Context, usuarioID, actividadID uint, sesionID *uint, listaEspera bool, authToken string) (domain.InscripcionResponse, error)
+	Deactivate(ctx context.Context, usuarioID, actividadID uint, sesionID *uint) error
 	DeactivateAllByUser(ctx context.Context, usuarioID uint) (int, error)
+	ListListaEspera(ctx context.Context, usuarioID uint) ([]domain.EntradaListaEsperaResponse, error)
+	ConfirmarListaEspera(ctx context.Context, usuarioID, entradaID uint, authToken string) (domain.EntradaListaEsperaResponse, error)
+	SalirListaEspera(ctx context.Context, usuarioID, entradaID uint) error
+	VencerOfertas(ctx context.Context) (int, error)
+}
+
+// PoliticaCancelaciones aplica la política de cancelación: bloqueo al inscribirse y strike por cancelar tarde
+// La implementa PenalizacionesServiceImpl
+type PoliticaCancelaciones interface {
+	VerificarBloqueo(ctx context.Context, usuarioID uint) error
+	RegistrarCancelacion(ctx context.Context, inscripcion domain.Inscripcion, categoria string, sesion domain.Sesion) (*domain.Strike, error)
+}
+
+// CupoClases valida, descuenta y reintegra las clases del cupo del plan
+// La implementa CuposServiceImpl; el cargo de Verificar lo descuenta inscripcionesRepo.Create en su transacción
+type CupoClases interface {
+	Verificar(ctx context.Context, inscripcion domain.Inscripcion, plan Plan) (*domain.CargoCupo, error)
+	Reintegrar(ctx context.Context, inscripcion domain.Inscripcion, motivo string) (int, error)
 }
 
 // InscripcionesServiceImpl implementa InscripcionesService
@@ -26,15 +47,34 @@ type InscripcionesService interface {
 type InscripcionesServiceImpl struct {
 	inscripcionesRepo repository.InscripcionesRepository
 	actividadesRepo   repository.ActividadesRepository
+	sesionesRepo      repository.SesionesRepository
+	listaEsperaRepo   repository.ListaEsperaRepository
+	suscripciones     SuscripcionActivaProvider
+	penalizaciones    PoliticaCancelaciones // Opcional: sin ella no hay bloqueos ni strikes
+	cupos             CupoClases            // Opcional: sin él los planes no limitan las clases
 	eventPublisher    EventPublisher
+
+	ventanaConfirmacion time.Duration // Tiempo para aceptar un lugar ofrecido desde la lista de espera
+	now                 func() time.Time
 }
 
 // NewInscripcionesService crea una nueva instancia del servicio
-func NewInscripcionesService(inscripcionesRepo repository.InscripcionesRepository, actividadesRepo repository.ActividadesRepository, eventPublisher EventPublisher) *InscripcionesServiceImpl {
+func NewInscripcionesService(inscripcionesRepo repository.InscripcionesRepository, actividadesRepo repository.ActividadesRepository, sesionesRepo repository.SesionesRepository, listaEsperaRepo repository.ListaEsperaRepository, suscripciones SuscripcionActivaProvider, penalizaciones PoliticaCancelaciones, cupos CupoClases, eventPublisher EventPublisher, ventanaConfirmacion time.Duration) *InscripcionesServiceImpl {
+	if ventanaConfirmacion <= 0 {
+		ventanaConfirmacion = ventanaConfirmacionDefault
+	}
+
 	return &InscripcionesServiceImpl{
-		inscripcionesRepo: inscripcionesRepo,
-		actividadesRepo:   actividadesRepo,
-		eventPublisher:    eventPublisher,
+		inscripcionesRepo:   inscripcionesRepo,
+		actividadesRepo:     actividadesRepo,
+		sesionesRepo:        sesionesRepo,
+		listaEsperaRepo:     listaEsperaRepo,
+		suscripciones:       suscripciones,
+		penalizaciones:      penalizaciones,
+		cupos:               cupos,
+		eventPublisher:      eventPublisher,
+		ventanaConfirmacion: ventanaConfirmacion,
+		now:                 time.Now,
 	}
 }
 
@@ -55,6 +95,50 @@ func (s *InscripcionesServiceImpl) ListByUser(ctx context.Context, usuarioID uin
 	return responses, nil
 }
 
+// ListByActividad obtiene los inscriptos de una actividad
+// Owners ven todas las clases, receptionist y branch_manager las de sus sucursales
+// y un instructor solo las clases que dicta (instructor_id)
+func (s *InscripcionesServiceImpl) ListByActividad(ctx context.Context, actividadID uint, viewer auth.Claims) ([]domain.InscripcionResponse, error) {
+	actividad, err := s.actividadesRepo.GetByID(ctx, actividadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canViewRoster(viewer, actividad) {
+		return nil, ErrRosterForbidden
+	}
+
+	inscripciones, err := s.inscripcionesRepo.ListByActividad(ctx, actividadID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing inscripciones: %w", err)
+	}
+
+	responses := make([]domain.InscripcionResponse, len(inscripciones))
+	for i, insc := range inscripciones {
+		responses[i] = insc.ToResponse()
+	}
+
+	return responses, nil
+}
+
+// canViewRoster aplica el alcance de rosters:read (por sucursal) y rosters:read_own (clases propias)
+// Una actividad sin sucursal solo la ve un owner o su instructor
+func canViewRoster(viewer auth.Claims, actividad domain.Actividad) bool {
+	if actividad.SucursalID == nil {
+		if viewer.HasGlobalPermission(auth.PermRostersRead) {
+			return true
+		}
+	} else if viewer.CanInBranch(auth.PermRostersRead, *actividad.SucursalID) {
+		return true
+	}
+
+	viewerID, err := viewer.UserID()
+	if err != nil || actividad.InstructorID == nil || *actividad.InstructorID != viewerID {
+		return false
+	}
+	return viewer.Can(auth.PermOwnRostersRead)
+}
+
 // ResultadoValidacion representa el resultado de una validación concurrente
 type ResultadoValidacion struct {
 	Nombre  string
@@ -64,9 +148,18 @@ type ResultadoValidacion struct {
 }
 
 // Create inscribe a un usuario en una actividad
+// Sin sesionID es una inscripción fija semanal; con sesionID reserva solo esa sesión
+// Con listaEspera, si la clase está llena anota al usuario en la cola y devuelve *ListaEsperaError
 // Migrado de backend/services/inscripcion_service.go:44
 // IMPLEMENTA PROCESAMIENTO CONCURRENTE con Go Routines, Errgroup y Context
-func (s *InscripcionesServiceImpl) Create(ctx context.Context, usuarioID, actividadID uint, authToken string) (domain.InscripcionResponse, error) {
+func (s *InscripcionesServiceImpl) Create(ctx context.Context, usuarioID, actividadID uint, sesionID *uint, listaEspera bool, authToken string) (domain.InscripcionResponse, error) {
+	// Un socio bloqueado por acumular strikes no puede inscribirse ni anotarse en la lista de espera
+	if s.penalizaciones != nil {
+		if err := s.penalizaciones.VerificarBloqueo(ctx, usuarioID); err != nil {
+			return domain.InscripcionResponse{}, err
+		}
+	}
+
 	// Crear contexto con timeout de 10 segundos para todas las validaciones
 	validationCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
@@ -88,45 +181,19 @@ func (s *InscripcionesServiceImpl) Create(ctx context.Context, usuarioID, activi
 		return nil
 	})
 
-	// Goroutine 2: Validar que el usuario no tenga inscripciones duplicadas
-	g.Go(func() error {
-		inscripciones, err := s.inscripcionesRepo.ListByUser(gCtx, usuarioID)
-		if err != nil {
-			return fmt.Errorf("error verificando inscripciones: %w", err)
-		}
-
-		// Verificar si ya está inscripto
-		for _, insc := range inscripciones {
-			if insc.ActividadID == actividadID && insc.IsActiva {
-				return fmt.Errorf("el usuario ya está inscripto a esta actividad")
-			}
-		}
-		return nil
-	})
-
-	// Goroutine 3: Validar disponibilidad de cupos del usuario
-	g.Go(func() error {
-		// Esta validación se podría expandir para verificar cupos en tiempo real
-		// o hacer cálculos más complejos
-		inscripcionesActivas, err := s.inscripcionesRepo.ListByUser(gCtx, usuarioID)
-		if err != nil {
-			return fmt.Errorf("error verificando disponibilidad: %w", err)
-		}
+	// Los duplicados y el cupo se validan en la transacción de inscripcionesRepo.Create
+	// (con la actividad bloqueada), no acá: una validación previa no evita la carrera
 
-		// Simular cálculo de disponibilidad (cantidad de inscripciones activas del usuario)
-		conteoActivas := 0
-		for _, insc := range inscripcionesActivas {
-			if insc.IsActiva {
-				conteoActivas++
+	// Goroutine 2: Validar que la sesión reservada sea de la actividad y se pueda reservar
+	if sesionID != nil {
+		g.Go(func() error {
+			sesion, err := s.sesionesRepo.GetByID(gCtx, *sesionID)
+			if err != nil {
+				return fmt.Errorf("sesión no encontrada: %w", err)
 			}
-		}
-
-		// Ejemplo: podríamos validar un límite máximo aquí
-		// if conteoActivas >= MAX_INSCRIPCIONES {
-		//     return fmt.Errorf("límite de inscripciones alcanzado")
-		// }
-		return nil
-	})
+			return validateSesionReservable(sesion, actividadID, time.Now())
+		})
+	}
 
 	// Esperar a que todas las validaciones terminen
 	// Si alguna falla, Wait() retorna el primer error y cancela las demás
@@ -143,12 +210,12 @@ func (s *InscripcionesServiceImpl) Create(ctx context.Context, usuarioID, activi
 		return domain.InscripcionResponse{}, fmt.Errorf("error en validación de actividad")
 	}
 
-	// Validación HTTP - Validar suscripción activa (HTTP call to subscriptions-api)
-	// Crear contexto con timeout específico para llamadas HTTP (5 segundos)
+	// Validar suscripción activa (copia local; subscriptions-api solo si el usuario no está en ella)
+	// Crear contexto con timeout específico por si hace falta la llamada HTTP (5 segundos)
 	httpCtx, httpCancel := context.WithTimeout(ctx, 5*time.Second)
 	defer httpCancel()
 
-	activeSub, err := s.getActiveSubscription(httpCtx, usuarioID, authToken)
+	activeSub, err := s.suscripciones.ActiveSubscription(httpCtx, usuarioID, authToken)
 	if err != nil {
 		// Manejar timeout específicamente
 		if httpCtx.Err() == context.DeadlineExceeded {
@@ -162,22 +229,40 @@ func (s *InscripcionesServiceImpl) Create(ctx context.Context, usuarioID, activi
 		return domain.InscripcionResponse{}, err
 	}
 
-	// Validar límite de actividades semanales del plan
-	if err := s.validateWeeklyActivityLimit(ctx, usuarioID, activeSub); err != nil {
-		return domain.InscripcionResponse{}, err
-	}
-
 	// Crear inscripción
 	inscripcion := domain.Inscripcion{
 		UsuarioID:      usuarioID,
 		ActividadID:    actividadID,
+		SesionID:       sesionID,
 		IsActiva:       true,
 		SuscripcionID:  &activeSub.ID,
 	}
+	// El plan elige la política de cancelación cuando se desinscriba o falte
+	if activeSub.PlanID != "" {
+		inscripcion.PlanID = &activeSub.PlanID
+	}
+
+	// Validar el cupo de clases del plan en el período (día, semana o mes) de la clase
+	var cargo *domain.CargoCupo
+	if s.cupos != nil {
+		if cargo, err = s.cupos.Verificar(ctx, inscripcion, activeSub.PlanInfo); err != nil {
+			return domain.InscripcionResponse{}, err
+		}
+	}
 
-	createdInscripcion, err := s.inscripcionesRepo.Create(ctx, inscripcion)
+	// Crea la inscripción validando duplicados, cupo y cupo de clases del plan en una sola transacción
+	createdInscripcion, err := s.inscripcionesRepo.Create(ctx, inscripcion, cargo)
 	if err != nil {
-		return domain.InscripcionResponse{}, fmt.Errorf("error creating inscripcion: %w", err)
+		// Clase llena: si lo pidió, queda en la lista de espera
+		if listaEspera && errors.Is(err, domain.ErrCupoAlcanzado) {
+			return domain.InscripcionResponse{}, s.anotarEnListaEspera(ctx, usuarioID, actividadID, sesionID, activeSub)
+		}
+		return domain.InscripcionResponse{}, err
+	}
+
+	// Si estaba en la lista de espera de esta clase, ya no necesita el lugar
+	if err := s.listaEsperaRepo.CancelActiva(ctx, usuarioID, actividadID, sesionID); err != nil {
+		fmt.Printf("⚠️  Error cancelando la lista de espera del usuario %d: %v\n", usuarioID, err)
 	}
 
 	// Invalidar cache de actividades para que se reflejen los nuevos cupos
@@ -189,6 +274,7 @@ func (s *InscripcionesServiceImpl) Create(ctx context.Context, usuarioID, activi
 	eventData := map[string]interface{}{
 		"usuario_id":   createdInscripcion.UsuarioID,
 		"actividad_id": createdInscripcion.ActividadID,
+		"sesion_id":    createdInscripcion.SesionID,
 		"is_activa":    createdInscripcion.IsActiva,
 	}
 	if err := s.eventPublisher.PublishInscriptionEvent("create", fmt.Sprintf("%d", createdInscripcion.ID), eventData); err != nil {
@@ -199,13 +285,43 @@ func (s *InscripcionesServiceImpl) Create(ctx context.Context, usuarioID, activi
 	return createdInscripcion.ToResponse(), nil
 }
 
-// Deactivate desinscribe a un usuario de una actividad
+// Deactivate desinscribe a un usuario de una actividad (o cancela la reserva de una sesión)
+// Si la sesión que libera empieza antes de la ventana de su política, suma un strike por cancelación tardía
+// Las clases que todavía no empezaron vuelven al cupo del plan y el lugar liberado se ofrece al siguiente de la lista de espera
 // Migrado de backend/services/inscripcion_service.go:48
-func (s *InscripcionesServiceImpl) Deactivate(ctx context.Context, usuarioID, actividadID uint) error {
-	if err := s.inscripcionesRepo.Deactivate(ctx, usuarioID, actividadID); err != nil {
+func (s *InscripcionesServiceImpl) Deactivate(ctx context.Context, usuarioID, actividadID uint, sesionID *uint) error {
+	// Se busca antes de la baja: después la inscripción ya no está activa
+	var inscripcion *domain.Inscripcion
+	if s.penalizaciones != nil || s.cupos != nil {
+		inscripcion = s.inscripcionActiva(ctx, usuarioID, actividadID, sesionID)
+	}
+	cancelacion := s.cancelacionPenalizable(ctx, inscripcion)
+
+	if err := s.inscripcionesRepo.Deactivate(ctx, usuarioID, actividadID, sesionID); err != nil {
 		return fmt.Errorf("error deactivating inscripcion: %w", err)
 	}
 
+	var strike *domain.Strike
+	if cancelacion != nil {
+		var err error
+		strike, err = s.penalizaciones.RegistrarCancelacion(ctx, cancelacion.inscripcion, cancelacion.categoria, cancelacion.sesion)
+		if err != nil {
+			// La baja ya está hecha: sin strike antes que fallar la cancelación
+			fmt.Printf("⚠️  Error registrando el strike por cancelación tardía del usuario %d: %v\n", usuarioID, err)
+		}
+	}
+
+	if inscripcion != nil && s.cupos != nil {
+		if _, err := s.cupos.Reintegrar(ctx, *inscripcion, domain.ReintegroCancelacion); err != nil {
+			fmt.Printf("⚠️  Error reintegrando el cupo del usuario %d: %v\n", usuarioID, err)
+		}
+	}
+
+	// Si el lugar venía de una oferta de la lista de espera, la oferta deja de estar pendiente
+	if err := s.listaEsperaRepo.CancelActiva(ctx, usuarioID, actividadID, sesionID); err != nil {
+		fmt.Printf("⚠️  Error cancelando la lista de espera del usuario %d: %v\n", usuarioID, err)
+	}
+
 	// Invalidar cache de actividades para reflejar cupos liberados
 	if s.actividadesRepo != nil {
 		s.actividadesRepo.InvalidateCache()
@@ -213,149 +329,123 @@ func (s *InscripcionesServiceImpl) Deactivate(ctx context.Context, usuarioID, ac
 
 	// Publicar evento a RabbitMQ
 	eventData := map[string]interface{}{
-		"usuario_id":   usuarioID,
-		"actividad_id": actividadID,
+		"usuario_id":         usuarioID,
+		"actividad_id":       actividadID,
+		"sesion_id":          sesionID,
+		"cancelacion_tardia": strike != nil,
 	}
-	inscripcionID := fmt.Sprintf("%d_%d", usuarioID, actividadID)
-	if err := s.eventPublisher.PublishInscriptionEvent("delete", inscripcionID, eventData); err != nil {
+	if err := s.eventPublisher.PublishInscriptionEvent("delete", inscripcionKey(usuarioID, actividadID, sesionID), eventData); err != nil {
 		// Log el error pero NO fallamos la operación (ya está desactivada)
 		fmt.Printf("⚠️  Error publicando evento inscription.delete: %v\n", err)
 	}
 
-	return nil
-}
+	s.promoverSiguiente(ctx, actividadID, sesionID)
 
-// Subscription representa una suscripción activa del usuario
-type Subscription struct {
-	ID       string `json:"id"`
-	UserID   string `json:"usuario_id"` // Cambiado de uint a string para coincidir con la respuesta de subscriptions-api
-	PlanID   string `json:"plan_id"`
-	Status   string `json:"estado"`
-	PlanInfo Plan   `json:"plan_info,omitempty"` // Info del plan expandida
+	return nil
 }
 
-// Plan representa la información del plan de suscripción
-type Plan struct {
-	ID                    string   `json:"id"`
-	Nombre                string   `json:"nombre"`
-	TipoAcceso            string   `json:"tipo_acceso"` // "limitado" | "completo"
-	ActividadesPermitidas []string `json:"actividades_permitidas"`
-	ActividadesPorSemana  int      `json:"actividades_por_semana"` // Límite de actividades por semana (0 = ilimitado)
+// cancelacionPendiente es lo que necesita la política de cancelación para evaluar una baja
+type cancelacionPendiente struct {
+	inscripcion domain.Inscripcion
+	categoria   string
+	sesion      domain.Sesion
 }
 
-// getActiveSubscription valida que el usuario tenga una suscripción activa
-func (s *InscripcionesServiceImpl) getActiveSubscription(ctx context.Context, userID uint, authToken string) (Subscription, error) {
-	// Crear cliente HTTP sin timeout hardcoded (usa el contexto)
-	client := &http.Client{}
-
-	// Construir URL usando el service name de Docker
-	url := fmt.Sprintf("http://subscriptions-api:8081/subscriptions/active/%d", userID)
-
-	// Crear request con contexto (respeta timeout del contexto padre)
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// inscripcionActiva busca la inscripción activa del usuario a la actividad (o la reserva de la sesión)
+// Devuelve nil si no la tiene o no se pudo averiguar
+func (s *InscripcionesServiceImpl) inscripcionActiva(ctx context.Context, usuarioID, actividadID uint, sesionID *uint) *domain.Inscripcion {
+	inscripciones, err := s.inscripcionesRepo.ListByUser(ctx, usuarioID)
 	if err != nil {
-		return Subscription{}, fmt.Errorf("error creando request: %w", err)
+		fmt.Printf("⚠️  Error buscando la inscripción a cancelar del usuario %d: %v\n", usuarioID, err)
+		return nil
 	}
-
-	// Agregar header de autorización
-	if authToken != "" {
-		req.Header.Set("Authorization", authToken)
-		fmt.Printf("🔐 [getActiveSubscription] Usando token de autorización para usuario %d\n", userID)
-	} else {
-		fmt.Printf("⚠️  [getActiveSubscription] NO se proporcionó token de autorización para usuario %d\n", userID)
+	for i, insc := range inscripciones {
+		if insc.IsActiva && insc.ActividadID == actividadID && mismaSesion(insc.SesionID, sesionID) {
+			return &inscripciones[i]
+		}
 	}
+	return nil
+}
 
-	// Ejecutar request
-	fmt.Printf("🌐 [getActiveSubscription] Llamando a: %s\n", url)
-	resp, err := client.Do(req)
-	if err != nil {
-		fmt.Printf("❌ [getActiveSubscription] Error ejecutando request: %v\n", err)
-		return Subscription{}, fmt.Errorf("error llamando a subscriptions-api: %w", err)
+// cancelacionPenalizable busca la sesión que libera la baja de la inscripción:
+// la reservada o, para una inscripción fija, la próxima sesión de la actividad
+// Devuelve nil si no hay política que aplicar, no hay sesión por delante o no se pudo averiguar
+func (s *InscripcionesServiceImpl) cancelacionPenalizable(ctx context.Context, inscripcion *domain.Inscripcion) *cancelacionPendiente {
+	if s.penalizaciones == nil || inscripcion == nil {
+		return nil
 	}
-	defer resp.Body.Close()
+	actividadID, sesionID := inscripcion.ActividadID, inscripcion.SesionID
 
-	fmt.Printf("📊 [getActiveSubscription] Status Code recibido: %d\n", resp.StatusCode)
-
-	// Manejar diferentes códigos de estado
-	if resp.StatusCode == 401 {
-		fmt.Printf("❌ [getActiveSubscription] Error 401 - No autorizado\n")
-		return Subscription{}, fmt.Errorf("no autorizado para consultar suscripción (falta token de autenticación)")
-	}
-	if resp.StatusCode == 404 {
-		fmt.Printf("❌ [getActiveSubscription] Error 404 - Suscripción no encontrada\n")
-		return Subscription{}, fmt.Errorf("debe tener un plan para inscribirse a esta actividad")
-	}
-	if resp.StatusCode != 200 {
-		// Leer el body para obtener más detalles del error
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		fmt.Printf("❌ [getActiveSubscription] Error HTTP %d desde subscriptions-api: %s\n", resp.StatusCode, string(bodyBytes))
-		return Subscription{}, fmt.Errorf("error obteniendo suscripción activa (status: %d)", resp.StatusCode)
+	actividad, err := s.actividadesRepo.GetByID(ctx, actividadID)
+	if err != nil {
+		fmt.Printf("⚠️  Error buscando la actividad %d para la política de cancelación: %v\n", actividadID, err)
+		return nil
 	}
 
-	// Decodificar respuesta
-	var subscription Subscription
-	if err := json.NewDecoder(resp.Body).Decode(&subscription); err != nil {
-		fmt.Printf("❌ [getActiveSubscription] Error decodificando JSON: %v\n", err)
-		return Subscription{}, fmt.Errorf("error decodificando suscripción: %w", err)
+	now := s.now()
+	var sesion *domain.Sesion
+	if sesionID != nil {
+		reservada, err := s.sesionesRepo.GetByID(ctx, *sesionID)
+		if err != nil {
+			fmt.Printf("⚠️  Error buscando la sesión %d para la política de cancelación: %v\n", *sesionID, err)
+			return nil
+		}
+		sesion = &reservada
+	} else {
+		// Las sesiones son semanales: la próxima está dentro de los próximos 7 días si ya se generó
+		proximas, err := s.sesionesRepo.ListByActividad(ctx, actividadID, now.In(gymLocation()), now.In(gymLocation()).AddDate(0, 0, 7))
+		if err != nil {
+			fmt.Printf("⚠️  Error buscando la próxima sesión de la actividad %d: %v\n", actividadID, err)
+			return nil
+		}
+		for i, proxima := range proximas {
+			if sesionInicio(proxima).After(now) {
+				sesion = &proximas[i]
+				break
+			}
+		}
 	}
-
-	fmt.Printf("📦 [getActiveSubscription] Suscripción decodificada - ID: %s, UserID: %s, PlanID: %s, Status: %s\n",
-		subscription.ID, subscription.UserID, subscription.PlanID, subscription.Status)
-
-	// Validar que la suscripción esté activa
-	if subscription.Status != "activa" {
-		fmt.Printf("❌ [getActiveSubscription] Suscripción no activa - Estado: %s\n", subscription.Status)
-		return Subscription{}, fmt.Errorf("la suscripción del usuario no está activa (estado: %s)", subscription.Status)
+	// Cancelar una reserva de una clase en curso también es tardío; una ya terminada no libera nada
+	// Si el gimnasio reprogramó la sesión, darse de baja no se penaliza
+	if sesion == nil || sesion.Cancelada() || sesion.Reprogramada() || !sesionFin(*sesion).After(now) {
+		return nil
 	}
 
-	// Obtener información del plan
-	fmt.Printf("📋 [getActiveSubscription] Obteniendo info del plan: %s\n", subscription.PlanID)
-	planInfo, err := s.getPlanInfo(ctx, subscription.PlanID, authToken)
-	if err != nil {
-		// Log el error pero no fallamos - el plan puede no estar disponible temporalmente
-		fmt.Printf("⚠️  [getActiveSubscription] No se pudo obtener info del plan %s: %v\n", subscription.PlanID, err)
-	} else {
-		subscription.PlanInfo = planInfo
-		fmt.Printf("✅ [getActiveSubscription] Plan cargado: %s (tipo_acceso: %s, actividades: %v)\n", planInfo.Nombre, planInfo.TipoAcceso, planInfo.ActividadesPermitidas)
+	return &cancelacionPendiente{
+		inscripcion: *inscripcion,
+		categoria:   actividad.Categoria,
+		sesion:      *sesion,
 	}
-
-	fmt.Printf("✅ [getActiveSubscription] Suscripción obtenida exitosamente para usuario %d (Estado: %s)\n", userID, subscription.Status)
-	return subscription, nil
 }
 
-// getPlanInfo obtiene la información del plan desde subscriptions-api
-func (s *InscripcionesServiceImpl) getPlanInfo(ctx context.Context, planID string, authToken string) (Plan, error) {
-	// Crear cliente HTTP sin timeout hardcoded (usa el contexto)
-	client := &http.Client{}
-
-	url := fmt.Sprintf("http://subscriptions-api:8081/plans/%s", planID)
-
-	// Crear request con contexto (respeta timeout del contexto padre)
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return Plan{}, fmt.Errorf("error creando request: %w", err)
+// mismaSesion compara dos sesion_id opcionales (nil = inscripción fija)
+func mismaSesion(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
 	}
+	return *a == *b
+}
 
-	if authToken != "" {
-		req.Header.Set("Authorization", authToken)
+// inscripcionKey arma el ID de los eventos de baja: usuario_actividad, o usuario_actividad_sesion para reservas
+func inscripcionKey(usuarioID, actividadID uint, sesionID *uint) string {
+	if sesionID == nil {
+		return fmt.Sprintf("%d_%d", usuarioID, actividadID)
 	}
+	return fmt.Sprintf("%d_%d_%d", usuarioID, actividadID, *sesionID)
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return Plan{}, fmt.Errorf("error llamando a subscriptions-api: %w", err)
+// validateSesionReservable valida que la sesión sea de la actividad, no esté cancelada y no haya empezado
+func validateSesionReservable(sesion domain.Sesion, actividadID uint, now time.Time) error {
+	if sesion.ActividadID != actividadID {
+		return ErrSesionOtraClase
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return Plan{}, fmt.Errorf("error obteniendo plan (status: %d)", resp.StatusCode)
+	if sesion.Cancelada() {
+		return ErrSesionCancelada
 	}
-
-	var plan Plan
-	if err := json.NewDecoder(resp.Body).Decode(&plan); err != nil {
-		return Plan{}, fmt.Errorf("error decodificando plan: %w", err)
+	if !sesionInicio(sesion).After(now) {
+		return ErrSesionIniciada
 	}
-
-	return plan, nil
+	return nil
 }
 
 // validatePlanRestrictions valida que la actividad esté permitida por el plan del usuario
@@ -392,65 +482,18 @@ func (s *InscripcionesServiceImpl) validatePlanRestrictions(subscription Subscri
 	return nil
 }
 
-// validateWeeklyActivityLimit valida que el usuario no exceda el límite de actividades por semana de su plan
-func (s *InscripcionesServiceImpl) validateWeeklyActivityLimit(ctx context.Context, usuarioID uint, subscription Subscription) error {
-	// Si el plan no tiene límite (0 o tipo_acceso completo), permitir
-	if subscription.PlanInfo.ActividadesPorSemana == 0 || subscription.PlanInfo.TipoAcceso == "completo" {
-		fmt.Printf("✅ [validateWeeklyActivityLimit] Sin límite semanal (ActividadesPorSemana: %d, TipoAcceso: %s)\n", subscription.PlanInfo.ActividadesPorSemana, subscription.PlanInfo.TipoAcceso)
-		return nil
-	}
-
-	// Obtener inscripciones activas del usuario
-	inscripcionesActivas, err := s.inscripcionesRepo.ListByUser(ctx, usuarioID)
-	if err != nil {
-		return fmt.Errorf("error verificando inscripciones activas: %w", err)
-	}
-
-	// Calcular el inicio de la semana actual (Lunes a las 00:00:00)
-	now := time.Now()
-	weekday := int(now.Weekday())
-	if weekday == 0 { // Domingo
-		weekday = 7
-	}
-	// Restar días para llegar al lunes
-	startOfWeek := now.AddDate(0, 0, -(weekday - 1))
-	startOfWeek = time.Date(startOfWeek.Year(), startOfWeek.Month(), startOfWeek.Day(), 0, 0, 0, 0, startOfWeek.Location())
-
-	fmt.Printf("📅 [validateWeeklyActivityLimit] Inicio de semana: %s\n", startOfWeek.Format("2006-01-02 15:04:05"))
-
-	// Contar inscripciones activas de esta semana
-	inscripcionesEstaSemana := 0
-	for _, insc := range inscripcionesActivas {
-		if insc.IsActiva && insc.FechaInscripcion.After(startOfWeek) {
-			inscripcionesEstaSemana++
-			fmt.Printf("📝 [validateWeeklyActivityLimit] Inscripción #%d (actividad: %d) en esta semana: %s\n",
-				insc.ID, insc.ActividadID, insc.FechaInscripcion.Format("2006-01-02 15:04:05"))
-		}
-	}
-
-	fmt.Printf("📊 [validateWeeklyActivityLimit] Inscripciones esta semana: %d, Límite del plan: %d\n",
-		inscripcionesEstaSemana, subscription.PlanInfo.ActividadesPorSemana)
-
-	// Verificar si ya alcanzó el límite
-	if inscripcionesEstaSemana >= subscription.PlanInfo.ActividadesPorSemana {
-		return fmt.Errorf(
-			"has alcanzado el límite de actividades semanales de tu plan '%s' (%d/%d actividades). Espera a la próxima semana o mejora tu plan",
-			subscription.PlanInfo.Nombre,
-			inscripcionesEstaSemana,
-			subscription.PlanInfo.ActividadesPorSemana,
-		)
-	}
-
-	fmt.Printf("✅ [validateWeeklyActivityLimit] Dentro del límite (%d/%d)\n",
-		inscripcionesEstaSemana+1, subscription.PlanInfo.ActividadesPorSemana)
-	return nil
-}
-
 // DeactivateAllByUser desactiva todas las inscripciones de un usuario
-// Se llama cuando se cancela la suscripción del usuario
+// Se llama cuando se cancela la suscripción del usuario: también lo saca de las listas de espera,
+// reintegra al cupo las clases que no empezaron y ofrece cada lugar liberado al siguiente de la cola
 func (s *InscripcionesServiceImpl) DeactivateAllByUser(ctx context.Context, usuarioID uint) (int, error) {
 	fmt.Printf("🔄 [DeactivateAllByUser] Desactivando todas las inscripciones del usuario %d\n", usuarioID)
 
+	if canceladas, err := s.listaEsperaRepo.CancelByUser(ctx, usuarioID); err != nil {
+		fmt.Printf("⚠️ [DeactivateAllByUser] Error cancelando lista de espera: %v\n", err)
+	} else if canceladas > 0 {
+		fmt.Printf("✅ [DeactivateAllByUser] Entradas de lista de espera canceladas: %d\n", canceladas)
+	}
+
 	// Obtener todas las inscripciones activas del usuario
 	inscripciones, err := s.inscripcionesRepo.ListByUser(ctx, usuarioID)
 	if err != nil {
@@ -462,23 +505,31 @@ func (s *InscripcionesServiceImpl) DeactivateAllByUser(ctx context.Context, usua
 	for _, insc := range inscripciones {
 		if insc.IsActiva {
 			// Desactivar cada inscripción
-			if err := s.inscripcionesRepo.Deactivate(ctx, usuarioID, insc.ActividadID); err != nil {
+			if err := s.inscripcionesRepo.Deactivate(ctx, usuarioID, insc.ActividadID, insc.SesionID); err != nil {
 				fmt.Printf("⚠️ [DeactivateAllByUser] Error desactivando inscripción actividad %d: %v\n", insc.ActividadID, err)
 				continue
 			}
 			count++
 			fmt.Printf("✅ [DeactivateAllByUser] Inscripción desactivada - Actividad ID: %d\n", insc.ActividadID)
 
+			if s.cupos != nil {
+				if _, err := s.cupos.Reintegrar(ctx, insc, domain.ReintegroSuscripcionCancelada); err != nil {
+					fmt.Printf("⚠️ [DeactivateAllByUser] Error reintegrando el cupo de la actividad %d: %v\n", insc.ActividadID, err)
+				}
+			}
+
 			// Publicar evento para cada desinscripción
 			eventData := map[string]interface{}{
 				"usuario_id":   usuarioID,
 				"actividad_id": insc.ActividadID,
+				"sesion_id":    insc.SesionID,
 				"reason":       "subscription_cancelled",
 			}
-			inscripcionID := fmt.Sprintf("%d_%d", usuarioID, insc.ActividadID)
-			if err := s.eventPublisher.PublishInscriptionEvent("delete", inscripcionID, eventData); err != nil {
+			if err := s.eventPublisher.PublishInscriptionEvent("delete", inscripcionKey(usuarioID, insc.ActividadID, insc.SesionID), eventData); err != nil {
 				fmt.Printf("⚠️ [DeactivateAllByUser] Error publicando evento: %v\n", err)
 			}
+
+			s.promoverSiguiente(ctx, insc.ActividadID, insc.SesionID)
 		}
 	}
 
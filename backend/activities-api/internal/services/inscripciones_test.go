@@ -0,0 +1,88 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/gym-management/shared/auth"
+)
+
+// --- Manual Mocks ---
+
+type MockInscripcionesRepository struct {
+	ListByUserFunc            func(ctx context.Context, usuarioID uint) ([]domain.Inscripcion, error)
+	ListByActividadFunc       func(ctx context.Context, actividadID uint) ([]domain.Inscripcion, error)
+	GetByUserAndActividadFunc func(ctx context.Context, usuarioID, actividadID uint) (domain.Inscripcion, error)
+	CreateFunc                func(ctx context.Context, inscripcion domain.Inscripcion, cargo *domain.CargoCupo) (domain.Inscripcion, error)
+	DeactivateFunc            func(ctx context.Context, usuarioID, actividadID uint, sesionID *uint) error
+}
+
+func (m *MockInscripcionesRepository) ListByUser(ctx context.Context, usuarioID uint) ([]domain.Inscripcion, error) {
+	return m.ListByUserFunc(ctx, usuarioID)
+}
+func (m *MockInscripcionesRepository) ListByActividad(ctx context.Context, actividadID uint) ([]domain.Inscripcion, error) {
+	return m.ListByActividadFunc(ctx, actividadID)
+}
+func (m *MockInscripcionesRepository) GetByUserAndActividad(ctx context.Context, usuarioID, actividadID uint) (domain.Inscripcion, error) {
+	return m.GetByUserAndActividadFunc(ctx, usuarioID, actividadID)
+}
+func (m *MockInscripcionesRepository) Create(ctx context.Context, inscripcion domain.Inscripcion, cargo *domain.CargoCupo) (domain.Inscripcion, error) {
+	return m.CreateFunc(ctx, inscripcion, cargo)
+}
+func (m *MockInscripcionesRepository) Deactivate(ctx context.Context, usuarioID, actividadID uint, sesionID *uint) error {
+	return m.DeactivateFunc(ctx, usuarioID, actividadID, sesionID)
+}
+
+// staffClaims arma los claims de un usuario con un rol de staff en las sucursales
+func staffClaims(userID uint, role string, branchIDs ...uint) auth.Claims {
+	claims := auth.NewClaims(userID, []string{auth.RoleMember, role}, nil, "jti", time.Now(), time.Minute)
+	claims.RoleBranches = map[string][]uint{role: branchIDs}
+	return claims
+}
+
+// --- Tests ---
+
+func TestListByActividad_RosterScope(t *testing.T) {
+	branch := uint(2)
+	instructorID := uint(30)
+
+	actividadesRepo := &MockActividadesRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (domain.Actividad, error) {
+			return domain.Actividad{ID: id, SucursalID: &branch, InstructorID: &instructorID}, nil
+		},
+	}
+	inscripcionesRepo := &MockInscripcionesRepository{
+		ListByActividadFunc: func(ctx context.Context, actividadID uint) ([]domain.Inscripcion, error) {
+			return []domain.Inscripcion{{ID: 1, UsuarioID: 5, ActividadID: actividadID, IsActiva: true}}, nil
+		},
+	}
+	service := NewInscripcionesService(inscripcionesRepo, actividadesRepo, newMockSesionesRepository(), newMockListaEsperaRepository(), nil, nil, nil, &MockEventPublisher{}, 0)
+
+	owner := auth.NewClaims(1, []string{auth.RoleMember, auth.RoleOwner}, nil, "jti", time.Now(), time.Minute)
+
+	cases := []struct {
+		name    string
+		viewer  auth.Claims
+		allowed bool
+	}{
+		{"owner", owner, true},
+		{"receptionist de la sucursal", staffClaims(10, auth.RoleReceptionist, 2), true},
+		{"receptionist de otra sucursal", staffClaims(10, auth.RoleReceptionist, 3), false},
+		{"instructor de la clase", staffClaims(instructorID, auth.RoleInstructor, 2), true},
+		{"otro instructor de la sucursal", staffClaims(31, auth.RoleInstructor, 2), false},
+		{"member", auth.NewClaims(5, []string{auth.RoleMember}, nil, "jti", time.Now(), time.Minute), false},
+	}
+
+	for _, tc := range cases {
+		roster, err := service.ListByActividad(context.Background(), 7, tc.viewer)
+		if tc.allowed && (err != nil || len(roster) != 1) {
+			t.Errorf("%s: expected roster, got %v (%v)", tc.name, roster, err)
+		}
+		if !tc.allowed && !errors.Is(err, ErrRosterForbidden) {
+			t.Errorf("%s: expected ErrRosterForbidden, got %v", tc.name, err)
+		}
+	}
+}
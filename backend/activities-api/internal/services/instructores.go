@@ -0,0 +1,327 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Errores de instructores
+var (
+	ErrInstructorInvalido       = errors.New("datos de instructor inválidos")
+	ErrInstructorInexistente    = errors.New("el instructor no existe o está dado de baja")
+	ErrInstructorConActividades = errors.New("el instructor tiene actividades activas: reasignalas o eliminalas antes de darlo de baja")
+	ErrInstructorOtraSucursal   = errors.New("el instructor no da clases en la sucursal de la actividad")
+	ErrInstructorSuperpuesto    = errors.New("el instructor ya tiene una clase en ese horario")
+	ErrInstructorSinAsignar     = errors.New("la actividad necesita un instructor (instructor_perfil_id o instructor)")
+)
+
+// diasAgendaDefault es el rango de GET /instructores/:id/agenda sin fechas (una semana desde hoy)
+const diasAgendaDefault = 7
+
+// InstructoresService define la interfaz del servicio de instructores
+type InstructoresService interface {
+	List(ctx context.Context) ([]domain.Instructor, error)
+	GetByID(ctx context.Context, id uint) (domain.Instructor, error)
+	Create(ctx context.Context, instructorCreate domain.InstructorCreate) (domain.Instructor, error)
+	Update(ctx context.Context, id uint, instructorUpdate domain.InstructorUpdate) (domain.Instructor, error)
+	Delete(ctx context.Context, id uint) error
+	Agenda(ctx context.Context, id uint, desde, hasta string) (domain.AgendaInstructor, error)
+}
+
+// InstructoresServiceImpl implementa InstructoresService
+type InstructoresServiceImpl struct {
+	repository     repository.InstructoresRepository
+	sucursalesRepo repository.SucursalesRepository
+	sesionesRepo   repository.SesionesRepository
+	eventPublisher EventPublisher
+	now            func() time.Time
+}
+
+// NewInstructoresService crea una nueva instancia del servicio
+func NewInstructoresService(repo repository.InstructoresRepository, sucursalesRepo repository.SucursalesRepository, sesionesRepo repository.SesionesRepository, eventPublisher EventPublisher) *InstructoresServiceImpl {
+	return &InstructoresServiceImpl{
+		repository:     repo,
+		sucursalesRepo: sucursalesRepo,
+		sesionesRepo:   sesionesRepo,
+		eventPublisher: eventPublisher,
+		now:            time.Now,
+	}
+}
+
+// List obtiene los instructores activos
+func (s *InstructoresServiceImpl) List(ctx context.Context) ([]domain.Instructor, error) {
+	instructores, err := s.repository.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing instructores: %w", err)
+	}
+
+	return instructores, nil
+}
+
+// GetByID obtiene un instructor activo por ID
+func (s *InstructoresServiceImpl) GetByID(ctx context.Context, id uint) (domain.Instructor, error) {
+	instructor, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return domain.Instructor{}, fmt.Errorf("instructor con ID %d: %w", id, err)
+	}
+
+	return instructor, nil
+}
+
+// Create valida y crea un instructor
+func (s *InstructoresServiceImpl) Create(ctx context.Context, instructorCreate domain.InstructorCreate) (domain.Instructor, error) {
+	instructor, err := s.buildInstructor(ctx, domain.InstructorUpdate(instructorCreate))
+	if err != nil {
+		return domain.Instructor{}, err
+	}
+
+	created, err := s.repository.Create(ctx, instructor)
+	if err != nil {
+		return domain.Instructor{}, fmt.Errorf("error creating instructor: %w", err)
+	}
+
+	return created, nil
+}
+
+// Update valida y reemplaza los datos del instructor
+// El repositorio copia nombre y cuenta a sus actividades; se publica activity.update para que search-api las reindexe
+func (s *InstructoresServiceImpl) Update(ctx context.Context, id uint, instructorUpdate domain.InstructorUpdate) (domain.Instructor, error) {
+	instructor, err := s.buildInstructor(ctx, instructorUpdate)
+	if err != nil {
+		return domain.Instructor{}, err
+	}
+
+	updated, err := s.repository.Update(ctx, id, instructor)
+	if err != nil {
+		return domain.Instructor{}, fmt.Errorf("error updating instructor: %w", err)
+	}
+
+	actividades, err := s.repository.ListActividades(ctx, id)
+	if err != nil {
+		// El instructor ya está actualizado: solo se pierde la reindexación inmediata
+		fmt.Printf("⚠️  Error listando actividades del instructor %d: %v\n", id, err)
+	}
+	for _, actividad := range actividades {
+		eventData := map[string]interface{}{
+			"instructor":           actividad.Instructor,
+			"instructor_id":        actividad.InstructorID,
+			"instructor_perfil_id": actividad.InstructorPerfilID,
+		}
+		if err := s.eventPublisher.PublishActivityEvent("update", fmt.Sprintf("%d", actividad.ID), eventData); err != nil {
+			fmt.Printf("⚠️  Error publicando evento activity.update: %v\n", err)
+		}
+	}
+
+	return updated, nil
+}
+
+// Delete da de baja un instructor sin actividades activas
+func (s *InstructoresServiceImpl) Delete(ctx context.Context, id uint) error {
+	if _, err := s.repository.GetByID(ctx, id); err != nil {
+		return fmt.Errorf("error deleting instructor: %w", err)
+	}
+
+	actividades, err := s.repository.ListActividades(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error deleting instructor: %w", err)
+	}
+	if len(actividades) > 0 {
+		return fmt.Errorf("%w (%d)", ErrInstructorConActividades, len(actividades))
+	}
+
+	if err := s.repository.Deactivate(ctx, id); err != nil {
+		return fmt.Errorf("error deleting instructor: %w", err)
+	}
+
+	return nil
+}
+
+// Agenda devuelve las clases semanales del instructor y sus sesiones entre desde y hasta (YYYY-MM-DD)
+// Sin fechas muestra la semana que empieza hoy; el rango puede abarcar como máximo 92 días
+// Solo figuran las sesiones ya generadas (ver SESSIONS_HORIZON_DAYS)
+func (s *InstructoresServiceImpl) Agenda(ctx context.Context, id uint, desdeParam, hastaParam string) (domain.AgendaInstructor, error) {
+	instructor, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return domain.AgendaInstructor{}, fmt.Errorf("instructor con ID %d: %w", id, err)
+	}
+
+	desde, hasta, err := s.parseRangoAgenda(desdeParam, hastaParam)
+	if err != nil {
+		return domain.AgendaInstructor{}, err
+	}
+
+	actividades, err := s.repository.ListActividades(ctx, id)
+	if err != nil {
+		return domain.AgendaInstructor{}, err
+	}
+
+	agenda := domain.AgendaInstructor{
+		Instructor: instructor,
+		Desde:      desde.Format("2006-01-02"),
+		Hasta:      hasta.Format("2006-01-02"),
+		Clases:     make([]domain.ActividadResponse, len(actividades)),
+		Sesiones:   []domain.SesionResponse{},
+	}
+
+	for i, actividad := range actividades {
+		agenda.Clases[i] = actividad.ToResponse()
+
+		sesiones, err := s.sesionesRepo.ListByActividad(ctx, actividad.ID, desde, hasta)
+		if err != nil {
+			return domain.AgendaInstructor{}, fmt.Errorf("error listing sesiones: %w", err)
+		}
+		for _, sesion := range sesiones {
+			// Una sesión cambiada a mano con otro instructor la dicta el reemplazo
+			if sesion.Personalizada && sesion.Instructor != actividad.Instructor {
+				continue
+			}
+			agenda.Sesiones = append(agenda.Sesiones, sesion.ToResponse())
+		}
+	}
+
+	// Orden cronológico entre todas las clases del instructor
+	sort.Slice(agenda.Sesiones, func(i, j int) bool {
+		a, b := agenda.Sesiones[i], agenda.Sesiones[j]
+		if a.Fecha != b.Fecha {
+			return a.Fecha < b.Fecha
+		}
+		return a.HorarioInicio < b.HorarioInicio
+	})
+
+	return agenda, nil
+}
+
+// buildInstructor valida los datos de alta/modificación y arma el dominio
+func (s *InstructoresServiceImpl) buildInstructor(ctx context.Context, datos domain.InstructorUpdate) (domain.Instructor, error) {
+	instructor := domain.Instructor{
+		Nombre:         strings.TrimSpace(datos.Nombre),
+		Bio:            strings.TrimSpace(datos.Bio),
+		FotoUrl:        strings.TrimSpace(datos.FotoUrl),
+		Especialidades: []string{},
+		UsuarioID:      datos.UsuarioID,
+		Sucursales:     []uint{},
+	}
+
+	if instructor.Nombre == "" {
+		return domain.Instructor{}, fmt.Errorf("%w: el nombre no puede estar vacío", ErrInstructorInvalido)
+	}
+	if len(instructor.Nombre) > 50 {
+		// actividades.instructor (donde se copia el nombre) es varchar(50)
+		return domain.Instructor{}, fmt.Errorf("%w: el nombre no puede superar los 50 caracteres", ErrInstructorInvalido)
+	}
+	if instructor.UsuarioID != nil && *instructor.UsuarioID == 0 {
+		return domain.Instructor{}, fmt.Errorf("%w: usuario_id inválido", ErrInstructorInvalido)
+	}
+
+	// Especialidades sin vacías ni repetidas (sin distinguir mayúsculas)
+	vistas := make(map[string]bool, len(datos.Especialidades))
+	for _, especialidad := range datos.Especialidades {
+		especialidad = strings.TrimSpace(especialidad)
+		clave := strings.ToLower(especialidad)
+		if especialidad == "" || vistas[clave] {
+			continue
+		}
+		vistas[clave] = true
+		instructor.Especialidades = append(instructor.Especialidades, especialidad)
+	}
+
+	// Las sucursales tienen que existir y estar activas
+	sucursalesVistas := make(map[uint]bool, len(datos.Sucursales))
+	for _, sucursalID := range datos.Sucursales {
+		if sucursalesVistas[sucursalID] {
+			continue
+		}
+		sucursalesVistas[sucursalID] = true
+
+		if _, err := s.sucursalesRepo.GetByID(ctx, sucursalID); err != nil {
+			if errors.Is(err, domain.ErrSucursalNotFound) {
+				return domain.Instructor{}, fmt.Errorf("%w: %v (ID %d)", ErrInstructorInvalido, ErrSucursalInexistente, sucursalID)
+			}
+			return domain.Instructor{}, fmt.Errorf("error validando la sucursal: %w", err)
+		}
+		instructor.Sucursales = append(instructor.Sucursales, sucursalID)
+	}
+
+	return instructor, nil
+}
+
+// parseRangoAgenda parsea desde/hasta (YYYY-MM-DD); sin fechas es la semana que empieza hoy
+func (s *InstructoresServiceImpl) parseRangoAgenda(desdeParam, hastaParam string) (time.Time, time.Time, error) {
+	now := s.now().In(gymLocation())
+	desde := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if desdeParam != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", desdeParam, gymLocation())
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrFechaInvalida
+		}
+		desde = parsed
+	}
+
+	hasta := desde.AddDate(0, 0, diasAgendaDefault-1)
+	if hastaParam != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", hastaParam, gymLocation())
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrFechaInvalida
+		}
+		hasta = parsed
+	}
+
+	if hasta.Before(desde) || hasta.After(desde.AddDate(0, 0, maxDiasRangoSesiones)) {
+		return time.Time{}, time.Time{}, ErrRangoSesiones
+	}
+
+	return desde, hasta, nil
+}
+
+// asignarInstructor resuelve el instructor de una actividad antes de guardarla
+// Con instructor_perfil_id copia nombre y cuenta del perfil, valida la sucursal y rechaza
+// superposiciones con otras clases del instructor el mismo día; sin él exige el texto libre
+// excluirID es la actividad que se está actualizando (0 al crear)
+func asignarInstructor(ctx context.Context, instructores repository.InstructoresRepository, actividad *domain.Actividad, horaInicio, horaFin time.Time, excluirID uint) error {
+	if actividad.InstructorPerfilID == nil {
+		actividad.Instructor = strings.TrimSpace(actividad.Instructor)
+		if actividad.Instructor == "" {
+			return ErrInstructorSinAsignar
+		}
+		return nil
+	}
+
+	instructor, err := instructores.GetByID(ctx, *actividad.InstructorPerfilID)
+	if err != nil {
+		if errors.Is(err, domain.ErrInstructorNotFound) {
+			return fmt.Errorf("%w (ID %d)", ErrInstructorInexistente, *actividad.InstructorPerfilID)
+		}
+		return fmt.Errorf("error validando el instructor: %w", err)
+	}
+
+	if actividad.SucursalID != nil && !instructor.DaClasesEn(*actividad.SucursalID) {
+		return fmt.Errorf("%w (sucursal %d)", ErrInstructorOtraSucursal, *actividad.SucursalID)
+	}
+
+	clases, err := instructores.ListActividades(ctx, instructor.ID)
+	if err != nil {
+		return fmt.Errorf("error validando el horario del instructor: %w", err)
+	}
+	inicio, fin := horaInicio.Format("15:04"), horaFin.Format("15:04")
+	for _, clase := range clases {
+		if clase.ID == excluirID || clase.Dia != actividad.Dia {
+			continue
+		}
+		// Rangos [inicio, fin): una clase puede empezar cuando termina la anterior
+		if clase.HorarioInicio < fin && inicio < clase.HorarioFinal {
+			return fmt.Errorf("%w: %s (%s %s-%s, actividad %d)", ErrInstructorSuperpuesto,
+				clase.Titulo, clase.Dia, clase.HorarioInicio, clase.HorarioFinal, clase.ID)
+		}
+	}
+
+	actividad.Instructor = instructor.Nombre
+	actividad.InstructorID = instructor.UsuarioID
+
+	return nil
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// --- Manual Mocks ---
+
+// MockInstructoresRepository guarda los instructores y las actividades que dictan en memoria
+type MockInstructoresRepository struct {
+	instructores map[uint]domain.Instructor
+	actividades  []domain.Actividad // actividades activas con InstructorPerfilID
+}
+
+func newMockInstructoresRepository() *MockInstructoresRepository {
+	return &MockInstructoresRepository{
+		instructores: make(map[uint]domain.Instructor),
+	}
+}
+
+func (m *MockInstructoresRepository) List(ctx context.Context) ([]domain.Instructor, error) {
+	var result []domain.Instructor
+	for _, instructor := range m.instructores {
+		if instructor.Activo {
+			result = append(result, instructor)
+		}
+	}
+	return result, nil
+}
+func (m *MockInstructoresRepository) GetByID(ctx context.Context, id uint) (domain.Instructor, error) {
+	instructor, ok := m.instructores[id]
+	if !ok || !instructor.Activo {
+		return domain.Instructor{}, domain.ErrInstructorNotFound
+	}
+	return instructor, nil
+}
+func (m *MockInstructoresRepository) Create(ctx context.Context, instructor domain.Instructor) (domain.Instructor, error) {
+	for _, existing := range m.instructores {
+		if instructor.UsuarioID != nil && existing.UsuarioID != nil && *existing.UsuarioID == *instructor.UsuarioID {
+			return domain.Instructor{}, domain.ErrInstructorUsuarioDuplicado
+		}
+	}
+	instructor.ID = uint(len(m.instructores) + 1)
+	instructor.Activo = true
+	m.instructores[instructor.ID] = instructor
+	return instructor, nil
+}
+func (m *MockInstructoresRepository) Update(ctx context.Context, id uint, instructor domain.Instructor) (domain.Instructor, error) {
+	if _, err := m.GetByID(ctx, id); err != nil {
+		return domain.Instructor{}, err
+	}
+	instructor.ID = id
+	instructor.Activo = true
+	m.instructores[id] = instructor
+	for i, actividad := range m.actividades {
+		if actividad.InstructorPerfilID != nil && *actividad.InstructorPerfilID == id {
+			m.actividades[i].Instructor = instructor.Nombre
+			m.actividades[i].InstructorID = instructor.UsuarioID
+		}
+	}
+	return instructor, nil
+}
+func (m *MockInstructoresRepository) Deactivate(ctx context.Context, id uint) error {
+	instructor, err := m.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	instructor.Activo = false
+	m.instructores[id] = instructor
+	return nil
+}
+func (m *MockInstructoresRepository) ListActividades(ctx context.Context, id uint) ([]domain.Actividad, error) {
+	var result []domain.Actividad
+	for _, actividad := range m.actividades {
+		if actividad.InstructorPerfilID != nil && *actividad.InstructorPerfilID == id {
+			result = append(result, actividad)
+		}
+	}
+	return result, nil
+}
+
+// newAsignacionTestService arma un ActividadesService con el instructor 1 ("Ana Gómez", cuenta 7)
+// que dicta Spinning (actividad 10) los lunes de 18:00 a 19:00 en la sucursal 1
+func newAsignacionTestService() (*ActividadesServiceImpl, *MockInstructoresRepository) {
+	sucursales := newMockSucursalesRepository()
+	sucursales.sucursales[1] = domain.Sucursal{ID: 1, Nombre: "Centro", Activa: true}
+	sucursales.sucursales[2] = domain.Sucursal{ID: 2, Nombre: "Belgrano", Activa: true}
+
+	instructores := newMockInstructoresRepository()
+	usuarioID, perfilID, sucursalID := uint(7), uint(1), uint(1)
+	instructores.instructores[1] = domain.Instructor{ID: 1, Nombre: "Ana Gómez", UsuarioID: &usuarioID, Sucursales: []uint{1}, Activo: true}
+	instructores.actividades = []domain.Actividad{{
+		ID: 10, Titulo: "Spinning", Dia: "Lunes", HorarioInicio: "18:00", HorarioFinal: "19:00",
+		Instructor: "Ana Gómez", InstructorID: &usuarioID, InstructorPerfilID: &perfilID, SucursalID: &sucursalID,
+	}}
+
+	mockRepo := &MockActividadesRepository{
+		CreateFunc: func(ctx context.Context, actividad domain.Actividad, horaInicio, horaFin time.Time) (domain.Actividad, error) {
+			actividad.ID = 11
+			return actividad, nil
+		},
+		UpdateFunc: func(ctx context.Context, id uint, actividad domain.Actividad, horaInicio, horaFin time.Time) (domain.Actividad, error) {
+			actividad.ID = id
+			return actividad, nil
+		},
+	}
+
+	return NewActividadesService(mockRepo, sucursales, instructores, newMockSalasRepository(), &MockEventPublisher{}), instructores
+}
+
+func TestCreateActividad_InstructorPerfil(t *testing.T) {
+	service, _ := newAsignacionTestService()
+
+	perfilID, sucursalID := uint(1), uint(1)
+	input := domain.ActividadCreate{
+		Titulo: "Yoga", Cupo: 20, Dia: "Lunes", HorarioInicio: "19:00", HorarioFinal: "20:00",
+		Instructor: "Ana Gomez", InstructorPerfilID: &perfilID, Categoria: "Relax", SucursalID: &sucursalID,
+	}
+
+	// Empieza justo cuando termina el Spinning: no se superpone
+	created, err := service.Create(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created.Instructor != "Ana Gómez" {
+		t.Errorf("Expected instructor name from the profile, got %q", created.Instructor)
+	}
+	if created.InstructorID == nil || *created.InstructorID != 7 {
+		t.Errorf("Expected instructor account 7 from the profile, got %v", created.InstructorID)
+	}
+}
+
+func TestCreateActividad_InstructorSuperpuesto(t *testing.T) {
+	service, _ := newAsignacionTestService()
+
+	perfilID, sucursalID := uint(1), uint(1)
+	tests := []struct {
+		name          string
+		dia           string
+		inicio, final string
+		wantErr       error
+	}{
+		{"same slot", "Lunes", "18:00", "19:00", ErrInstructorSuperpuesto},
+		{"starts inside", "Lunes", "18:30", "19:30", ErrInstructorSuperpuesto},
+		{"contains existing", "Lunes", "17:00", "20:00", ErrInstructorSuperpuesto},
+		{"ends when existing starts", "Lunes", "17:00", "18:00", nil},
+		{"other day", "Martes", "18:00", "19:00", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := domain.ActividadCreate{
+				Titulo: "Funcional", Cupo: 15, Dia: tt.dia, HorarioInicio: tt.inicio, HorarioFinal: tt.final,
+				InstructorPerfilID: &perfilID, Categoria: "Fuerza", SucursalID: &sucursalID,
+			}
+			_, err := service.Create(context.Background(), input)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestUpdateActividad_InstructorIgnoraLaMismaActividad(t *testing.T) {
+	service, _ := newAsignacionTestService()
+
+	// Mover el Spinning media hora no choca consigo mismo
+	perfilID, sucursalID := uint(1), uint(1)
+	input := domain.ActividadUpdate{
+		Titulo: "Spinning", Cupo: 20, Dia: "Lunes", HorarioInicio: "18:30", HorarioFinal: "19:30",
+		InstructorPerfilID: &perfilID, Categoria: "Cardio", SucursalID: &sucursalID,
+	}
+	if _, err := service.Update(context.Background(), 10, input); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestCreateActividad_InstructorOtraSucursalOInexistente(t *testing.T) {
+	service, _ := newAsignacionTestService()
+
+	perfilID, otraSucursal := uint(1), uint(2)
+	input := domain.ActividadCreate{
+		Titulo: "Pilates", Cupo: 10, Dia: "Miercoles", HorarioInicio: "09:00", HorarioFinal: "10:00",
+		InstructorPerfilID: &perfilID, Categoria: "Relax", SucursalID: &otraSucursal,
+	}
+	if _, err := service.Create(context.Background(), input); !errors.Is(err, ErrInstructorOtraSucursal) {
+		t.Errorf("Expected ErrInstructorOtraSucursal, got %v", err)
+	}
+
+	inexistente := uint(99)
+	input.InstructorPerfilID = &inexistente
+	if _, err := service.Create(context.Background(), input); !errors.Is(err, ErrInstructorInexistente) {
+		t.Errorf("Expected ErrInstructorInexistente, got %v", err)
+	}
+
+	// Sin perfil ni texto libre no hay instructor
+	input.InstructorPerfilID = nil
+	if _, err := service.Create(context.Background(), input); !errors.Is(err, ErrInstructorSinAsignar) {
+		t.Errorf("Expected ErrInstructorSinAsignar, got %v", err)
+	}
+}
+
+func TestCreateInstructor_Validations(t *testing.T) {
+	sucursales := newMockSucursalesRepository()
+	sucursales.sucursales[1] = domain.Sucursal{ID: 1, Nombre: "Centro", Activa: true}
+	service := NewInstructoresService(newMockInstructoresRepository(), sucursales, newMockSesionesRepository(), &MockEventPublisher{})
+
+	if _, err := service.Create(context.Background(), domain.InstructorCreate{Nombre: "   "}); !errors.Is(err, ErrInstructorInvalido) {
+		t.Errorf("Expected ErrInstructorInvalido for blank name, got %v", err)
+	}
+	if _, err := service.Create(context.Background(), domain.InstructorCreate{Nombre: "Ana", Sucursales: []uint{1, 5}}); !errors.Is(err, ErrInstructorInvalido) {
+		t.Errorf("Expected ErrInstructorInvalido for unknown sucursal, got %v", err)
+	}
+
+	created, err := service.Create(context.Background(), domain.InstructorCreate{
+		Nombre:         " Ana Gómez ",
+		Especialidades: []string{"Yoga", "yoga", " ", "Pilates"},
+		Sucursales:     []uint{1, 1},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created.Nombre != "Ana Gómez" || len(created.Especialidades) != 2 || len(created.Sucursales) != 1 {
+		t.Errorf("Expected trimmed and deduplicated instructor, got %+v", created)
+	}
+}
+
+func TestDeleteInstructor_ConActividades(t *testing.T) {
+	repo := newMockInstructoresRepository()
+	perfilID := uint(1)
+	repo.instructores[1] = domain.Instructor{ID: 1, Nombre: "Ana Gómez", Activo: true}
+	repo.instructores[2] = domain.Instructor{ID: 2, Nombre: "Luis Díaz", Activo: true}
+	repo.actividades = []domain.Actividad{{ID: 10, Titulo: "Spinning", InstructorPerfilID: &perfilID}}
+	service := NewInstructoresService(repo, newMockSucursalesRepository(), newMockSesionesRepository(), &MockEventPublisher{})
+
+	if err := service.Delete(context.Background(), 1); !errors.Is(err, ErrInstructorConActividades) {
+		t.Errorf("Expected ErrInstructorConActividades, got %v", err)
+	}
+	if err := service.Delete(context.Background(), 2); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.GetByID(context.Background(), 2); !errors.Is(err, domain.ErrInstructorNotFound) {
+		t.Errorf("Expected deleted instructor to be hidden, got %v", err)
+	}
+}
+
+func TestUpdateInstructor_ReindexaActividades(t *testing.T) {
+	repo := newMockInstructoresRepository()
+	perfilID := uint(1)
+	repo.instructores[1] = domain.Instructor{ID: 1, Nombre: "Ana Gomez", Activo: true}
+	repo.actividades = []domain.Actividad{{ID: 10, Titulo: "Spinning", Instructor: "Ana Gomez", InstructorPerfilID: &perfilID}}
+
+	var publicados []string
+	publisher := &MockEventPublisher{
+		PublishActivityEventFunc: func(action, activityID string, data map[string]interface{}) error {
+			publicados = append(publicados, action+":"+activityID+":"+data["instructor"].(string))
+			return nil
+		},
+	}
+	service := NewInstructoresService(repo, newMockSucursalesRepository(), newMockSesionesRepository(), publisher)
+
+	if _, err := service.Update(context.Background(), 1, domain.InstructorUpdate{Nombre: "Ana Gómez"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(publicados) != 1 || publicados[0] != "update:10:Ana Gómez" {
+		t.Errorf("Expected activity.update with the new name, got %v", publicados)
+	}
+}
+
+func TestAgendaInstructor(t *testing.T) {
+	repo := newMockInstructoresRepository()
+	perfilID := uint(1)
+	repo.instructores[1] = domain.Instructor{ID: 1, Nombre: "Ana Gómez", Activo: true}
+	repo.actividades = []domain.Actividad{
+		{ID: 10, Titulo: "Spinning", Dia: "Lunes", HorarioInicio: "18:00", HorarioFinal: "19:00", Instructor: "Ana Gómez", InstructorPerfilID: &perfilID},
+		{ID: 11, Titulo: "Yoga", Dia: "Miercoles", HorarioInicio: "08:00", HorarioFinal: "09:00", Instructor: "Ana Gómez", InstructorPerfilID: &perfilID},
+	}
+
+	sesiones := newMockSesionesRepository()
+	sesiones.sesiones[1] = domain.Sesion{ID: 1, ActividadID: 10, Fecha: "2025-03-10", HorarioInicio: "18:00", Instructor: "Ana Gómez"}
+	sesiones.sesiones[2] = domain.Sesion{ID: 2, ActividadID: 11, Fecha: "2025-03-12", HorarioInicio: "08:00", Instructor: "Ana Gómez"}
+	sesiones.sesiones[3] = domain.Sesion{ID: 3, ActividadID: 10, Fecha: "2025-03-17", HorarioInicio: "18:00", Instructor: "Luis Díaz", Personalizada: true}
+	sesiones.sesiones[4] = domain.Sesion{ID: 4, ActividadID: 11, Fecha: "2025-03-05", HorarioInicio: "08:00", Instructor: "Ana Gómez"}
+
+	service := NewInstructoresService(repo, newMockSucursalesRepository(), sesiones, &MockEventPublisher{})
+	service.now = func() time.Time { return time.Date(2025, 3, 10, 12, 0, 0, 0, gymLocation()) }
+
+	agenda, err := service.Agenda(context.Background(), 1, "", "2025-03-20")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if agenda.Desde != "2025-03-10" || len(agenda.Clases) != 2 {
+		t.Errorf("Expected 2 clases from 2025-03-10, got %s and %d", agenda.Desde, len(agenda.Clases))
+	}
+	// La sesión 3 la dicta un reemplazo y la 4 queda fuera del rango
+	if len(agenda.Sesiones) != 2 || agenda.Sesiones[0].ID != 1 || agenda.Sesiones[1].ID != 2 {
+		t.Errorf("Expected sesiones 1 and 2 in order, got %+v", agenda.Sesiones)
+	}
+
+	if _, err := service.Agenda(context.Background(), 1, "2025-03-10", "2025-09-10"); !errors.Is(err, ErrRangoSesiones) {
+		t.Errorf("Expected ErrRangoSesiones, got %v", err)
+	}
+	if _, err := service.Agenda(context.Background(), 99, "", ""); !errors.Is(err, domain.ErrInstructorNotFound) {
+		t.Errorf("Expected ErrInstructorNotFound, got %v", err)
+	}
+}
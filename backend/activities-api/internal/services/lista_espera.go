@@ -0,0 +1,332 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Errores de la lista de espera
+var (
+	ErrYaEnListaEspera     = errors.New("ya estás en la lista de espera de esta clase")
+	ErrListaEsperaInactiva = errors.New("la entrada de la lista de espera ya no está activa")
+	ErrOfertaNoDisponible  = errors.New("no tenés un lugar ofrecido en esta clase o la oferta ya venció")
+)
+
+// ventanaConfirmacionDefault es el tiempo para aceptar un lugar si no se configura WAITLIST_CONFIRMATION_MINUTES
+const ventanaConfirmacionDefault = 2 * time.Hour
+
+// ListaEsperaError indica que la clase estaba llena y el usuario quedó anotado en la lista de espera
+type ListaEsperaError struct {
+	Entrada domain.EntradaListaEsperaResponse
+}
+
+func (e *ListaEsperaError) Error() string {
+	return fmt.Sprintf("la clase está llena: quedaste en la posición %d de la lista de espera", e.Entrada.Posicion)
+}
+
+// anotarEnListaEspera agrega al usuario al final de la cola de la actividad/sesión
+// Guarda el cupo semanal del plan para poder validarlo al promoverlo (sin token del usuario)
+func (s *InscripcionesServiceImpl) anotarEnListaEspera(ctx context.Context, usuarioID, actividadID uint, sesionID *uint, subscription Subscription) error {
+	if _, err := s.listaEsperaRepo.GetActiva(ctx, usuarioID, actividadID, sesionID); err == nil {
+		return ErrYaEnListaEspera
+	} else if !errors.Is(err, repository.ErrListaEsperaNotFound) {
+		return err
+	}
+
+	// Los cupos diarios y mensuales se validan al inscribirse, no al promover
+	periodo, limite := subscription.PlanInfo.Cupo()
+	if periodo != domain.CupoSemanal {
+		limite = 0
+	}
+
+	entrada, err := s.listaEsperaRepo.Create(ctx, domain.EntradaListaEspera{
+		UsuarioID:     usuarioID,
+		ActividadID:   actividadID,
+		SesionID:      sesionID,
+		SuscripcionID: &subscription.ID,
+		LimiteSemanal: limite,
+		Estado:        domain.ListaEsperaEsperando,
+	})
+	if err != nil {
+		return fmt.Errorf("error anotando en la lista de espera: %w", err)
+	}
+
+	fmt.Printf("⏳ [ListaEspera] Usuario %d anotado en actividad %d (posición %d)\n", usuarioID, actividadID, entrada.Posicion)
+	return &ListaEsperaError{Entrada: entrada.ToResponse()}
+}
+
+// ListListaEspera lista las entradas activas del usuario con su posición o el vencimiento de la oferta
+func (s *InscripcionesServiceImpl) ListListaEspera(ctx context.Context, usuarioID uint) ([]domain.EntradaListaEsperaResponse, error) {
+	entradas, err := s.listaEsperaRepo.ListActivasByUser(ctx, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]domain.EntradaListaEsperaResponse, len(entradas))
+	for i, entrada := range entradas {
+		responses[i] = entrada.ToResponse()
+	}
+
+	return responses, nil
+}
+
+// ConfirmarListaEspera acepta el lugar ofrecido: la inscripción ya estaba creada, solo queda firme
+// Se vuelve a validar la suscripción con el token del usuario
+func (s *InscripcionesServiceImpl) ConfirmarListaEspera(ctx context.Context, usuarioID, entradaID uint, authToken string) (domain.EntradaListaEsperaResponse, error) {
+	entrada, err := s.entradaDelUsuario(ctx, usuarioID, entradaID)
+	if err != nil {
+		return domain.EntradaListaEsperaResponse{}, err
+	}
+	if entrada.Estado != domain.ListaEsperaOfrecida || (entrada.VenceEn != nil && entrada.VenceEn.Before(time.Now())) {
+		return domain.EntradaListaEsperaResponse{}, ErrOfertaNoDisponible
+	}
+
+	httpCtx, httpCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer httpCancel()
+
+	if _, err := s.suscripciones.ActiveSubscription(httpCtx, usuarioID, authToken); err != nil {
+		if httpCtx.Err() == context.DeadlineExceeded {
+			return domain.EntradaListaEsperaResponse{}, fmt.Errorf("timeout validando suscripción: el servicio tardó más de 5 segundos en responder")
+		}
+		return domain.EntradaListaEsperaResponse{}, fmt.Errorf("debe tener un plan para inscribirse a esta actividad")
+	}
+
+	ok, err := s.listaEsperaRepo.CambiarEstado(ctx, entrada.ID, domain.ListaEsperaOfrecida, domain.ListaEsperaConfirmada)
+	if err != nil {
+		return domain.EntradaListaEsperaResponse{}, err
+	}
+	if !ok {
+		// El job de vencimiento la tomó entre la lectura y la confirmación
+		return domain.EntradaListaEsperaResponse{}, ErrOfertaNoDisponible
+	}
+
+	entrada.Estado = domain.ListaEsperaConfirmada
+	entrada.VenceEn = nil
+	return entrada.ToResponse(), nil
+}
+
+// SalirListaEspera saca al usuario de la cola; si tenía un lugar ofrecido lo libera para el siguiente
+func (s *InscripcionesServiceImpl) SalirListaEspera(ctx context.Context, usuarioID, entradaID uint) error {
+	entrada, err := s.entradaDelUsuario(ctx, usuarioID, entradaID)
+	if err != nil {
+		return err
+	}
+	if !entrada.Activa() {
+		return ErrListaEsperaInactiva
+	}
+
+	ok, err := s.listaEsperaRepo.CambiarEstado(ctx, entrada.ID, entrada.Estado, domain.ListaEsperaCancelada)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrListaEsperaInactiva
+	}
+
+	if entrada.Estado == domain.ListaEsperaOfrecida {
+		s.liberarOferta(ctx, entrada, "waitlist_offer_declined")
+	}
+
+	return nil
+}
+
+// VencerOfertas pasa al siguiente de la cola los lugares que no se confirmaron a tiempo
+// Devuelve la cantidad de ofertas vencidas
+func (s *InscripcionesServiceImpl) VencerOfertas(ctx context.Context) (int, error) {
+	entradas, err := s.listaEsperaRepo.ListOfertasVencidas(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	vencidas := 0
+	for _, entrada := range entradas {
+		ok, err := s.listaEsperaRepo.CambiarEstado(ctx, entrada.ID, domain.ListaEsperaOfrecida, domain.ListaEsperaVencida)
+		if err != nil {
+			return vencidas, err
+		}
+		if !ok {
+			continue // Se confirmó o canceló mientras tanto
+		}
+
+		s.liberarOferta(ctx, entrada, "waitlist_offer_expired")
+		vencidas++
+	}
+
+	return vencidas, nil
+}
+
+// entradaDelUsuario obtiene una entrada verificando que sea del usuario (si no, not found)
+func (s *InscripcionesServiceImpl) entradaDelUsuario(ctx context.Context, usuarioID, entradaID uint) (domain.EntradaListaEspera, error) {
+	entrada, err := s.listaEsperaRepo.GetByID(ctx, entradaID)
+	if err != nil {
+		return domain.EntradaListaEspera{}, err
+	}
+	if entrada.UsuarioID != usuarioID {
+		return domain.EntradaListaEspera{}, repository.ErrListaEsperaNotFound
+	}
+	return entrada, nil
+}
+
+// liberarOferta da de baja la inscripción de una oferta rechazada o vencida y promueve al siguiente
+func (s *InscripcionesServiceImpl) liberarOferta(ctx context.Context, entrada domain.EntradaListaEspera, reason string) {
+	if err := s.inscripcionesRepo.Deactivate(ctx, entrada.UsuarioID, entrada.ActividadID, entrada.SesionID); err != nil {
+		// Si ya no estaba activa (se desinscribió) el lugar ya se liberó por otro lado
+		fmt.Printf("⚠️  [ListaEspera] No se pudo liberar el lugar de la entrada %d: %v\n", entrada.ID, err)
+		return
+	}
+
+	if s.actividadesRepo != nil {
+		s.actividadesRepo.InvalidateCache()
+	}
+
+	eventData := map[string]interface{}{
+		"usuario_id":   entrada.UsuarioID,
+		"actividad_id": entrada.ActividadID,
+		"sesion_id":    entrada.SesionID,
+		"reason":       reason,
+	}
+	if err := s.eventPublisher.PublishInscriptionEvent("delete", inscripcionKey(entrada.UsuarioID, entrada.ActividadID, entrada.SesionID), eventData); err != nil {
+		fmt.Printf("⚠️  Error publicando evento inscription.delete: %v\n", err)
+	}
+
+	s.promoverSiguiente(ctx, entrada.ActividadID, entrada.SesionID)
+}
+
+// promoverSiguiente ofrece el lugar liberado al primero elegible de la cola
+// La inscripción se crea en el momento (retiene el lugar) y queda "ofrecida" hasta que la confirme o venza
+// Los que ya no son elegibles se marcan como omitidos y se sigue con el próximo
+func (s *InscripcionesServiceImpl) promoverSiguiente(ctx context.Context, actividadID uint, sesionID *uint) {
+	for {
+		entrada, err := s.listaEsperaRepo.NextEsperando(ctx, actividadID, sesionID)
+		if err != nil {
+			if !errors.Is(err, repository.ErrListaEsperaNotFound) {
+				fmt.Printf("⚠️  [ListaEspera] Error buscando el siguiente de la actividad %d: %v\n", actividadID, err)
+			}
+			return
+		}
+
+		now := time.Now()
+		inscripcion := domain.Inscripcion{
+			UsuarioID:     entrada.UsuarioID,
+			ActividadID:   entrada.ActividadID,
+			SesionID:      entrada.SesionID,
+			IsActiva:      true,
+			SuscripcionID: entrada.SuscripcionID,
+		}
+		venceEn, cargo, err := s.validarPromocion(ctx, entrada, inscripcion, now)
+		if err != nil {
+			fmt.Printf("⏭️  [ListaEspera] Entrada %d (usuario %d) omitida: %v\n", entrada.ID, entrada.UsuarioID, err)
+			if _, err := s.listaEsperaRepo.CambiarEstado(ctx, entrada.ID, domain.ListaEsperaEsperando, domain.ListaEsperaOmitida); err != nil {
+				fmt.Printf("⚠️  [ListaEspera] Error omitiendo entrada %d: %v\n", entrada.ID, err)
+				return
+			}
+			continue
+		}
+
+		// Update condicional: si otro proceso ya tomó la entrada, probar con la próxima
+		ok, err := s.listaEsperaRepo.Ofrecer(ctx, entrada.ID, now, venceEn)
+		if err != nil {
+			fmt.Printf("⚠️  [ListaEspera] Error ofreciendo lugar a la entrada %d: %v\n", entrada.ID, err)
+			return
+		}
+		if !ok {
+			continue
+		}
+
+		inscripcion, err = s.inscripcionesRepo.Create(ctx, inscripcion, cargo)
+		if errors.Is(err, domain.ErrYaInscripto) || errors.Is(err, domain.ErrCupoAgotado) {
+			// Se inscribió por su cuenta mientras esperaba (no ocupa otro lugar) o en el medio usó su cupo de clases
+			if _, err := s.listaEsperaRepo.CambiarEstado(ctx, entrada.ID, domain.ListaEsperaOfrecida, domain.ListaEsperaOmitida); err != nil {
+				fmt.Printf("⚠️  [ListaEspera] Error omitiendo entrada %d: %v\n", entrada.ID, err)
+				return
+			}
+			continue
+		}
+		if err != nil {
+			// Otro usuario ocupó el lugar antes: la entrada vuelve a la cola con su antigüedad
+			fmt.Printf("⚠️  [ListaEspera] No se pudo inscribir la entrada %d: %v\n", entrada.ID, err)
+			if _, err := s.listaEsperaRepo.CambiarEstado(ctx, entrada.ID, domain.ListaEsperaOfrecida, domain.ListaEsperaEsperando); err != nil {
+				fmt.Printf("⚠️  [ListaEspera] Error devolviendo la entrada %d a la cola: %v\n", entrada.ID, err)
+			}
+			return
+		}
+
+		if s.actividadesRepo != nil {
+			s.actividadesRepo.InvalidateCache()
+		}
+
+		eventData := map[string]interface{}{
+			"usuario_id":      inscripcion.UsuarioID,
+			"actividad_id":    inscripcion.ActividadID,
+			"sesion_id":       inscripcion.SesionID,
+			"lista_espera_id": entrada.ID,
+			"vence_en":        venceEn,
+			"inscripcion_id":  inscripcion.ID,
+		}
+		if err := s.eventPublisher.PublishInscriptionEvent("promoted", fmt.Sprintf("%d", inscripcion.ID), eventData); err != nil {
+			fmt.Printf("⚠️  Error publicando evento inscription.promoted: %v\n", err)
+		}
+
+		fmt.Printf("🎟️  [ListaEspera] Lugar ofrecido al usuario %d en actividad %d (vence %s)\n", entrada.UsuarioID, entrada.ActividadID, venceEn.Format(time.RFC3339))
+		return
+	}
+}
+
+// validarPromocion valida que la entrada siga siendo elegible y calcula el vencimiento de la oferta
+// y el cargo del cupo de clases de la inscripción que retiene el lugar
+// La suscripción se da por activa: al cancelarse, DeactivateAllByUser cancela las entradas del usuario
+func (s *InscripcionesServiceImpl) validarPromocion(ctx context.Context, entrada domain.EntradaListaEspera, inscripcion domain.Inscripcion, now time.Time) (time.Time, *domain.CargoCupo, error) {
+	venceEn := now.Add(s.ventanaConfirmacion)
+
+	if entrada.SesionID != nil {
+		sesion, err := s.sesionesRepo.GetByID(ctx, *entrada.SesionID)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		if err := validateSesionReservable(sesion, entrada.ActividadID, now); err != nil {
+			return time.Time{}, nil, err
+		}
+		// La oferta no puede vencer después de que empiece la clase
+		if inicio := sesionInicio(sesion); inicio.Before(venceEn) {
+			venceEn = inicio
+		}
+	}
+
+	var cargo *domain.CargoCupo
+	if s.cupos != nil {
+		var err error
+		if cargo, err = s.cupos.Verificar(ctx, inscripcion, planListaEspera(entrada)); err != nil {
+			return time.Time{}, nil, err
+		}
+	}
+
+	return venceEn, cargo, nil
+}
+
+// planListaEspera arma el plan con el cupo semanal guardado en la entrada
+func planListaEspera(entrada domain.EntradaListaEspera) Plan {
+	return Plan{CupoClases: entrada.LimiteSemanal, CupoPeriodo: domain.CupoSemanal}
+}
+
+// NewListaEsperaExpirer crea el job que vence las ofertas no confirmadas cada "interval"
+func NewListaEsperaExpirer(service InscripcionesService, interval time.Duration) *PeriodicJob {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return NewPeriodicJob(interval, func() {
+		vencidas, err := service.VencerOfertas(context.Background())
+		if err != nil {
+			log.Printf("⚠️  No se pudieron vencer las ofertas de la lista de espera: %v", err)
+			return
+		}
+		if vencidas > 0 {
+			log.Printf("⌛ Ofertas de lista de espera vencidas: %d", vencidas)
+		}
+	})
+}
@@ -0,0 +1,368 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/repository"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// --- Manual Mocks ---
+
+// MockListaEsperaRepository guarda las entradas en memoria (el orden de la cola es el ID)
+type MockListaEsperaRepository struct {
+	entradas map[uint]domain.EntradaListaEspera
+	nextID   uint
+}
+
+func newMockListaEsperaRepository() *MockListaEsperaRepository {
+	return &MockListaEsperaRepository{
+		entradas: make(map[uint]domain.EntradaListaEspera),
+	}
+}
+
+func (m *MockListaEsperaRepository) Create(ctx context.Context, entrada domain.EntradaListaEspera) (domain.EntradaListaEspera, error) {
+	m.nextID++
+	entrada.ID = m.nextID
+	m.entradas[entrada.ID] = entrada
+	return m.GetByID(ctx, entrada.ID)
+}
+func (m *MockListaEsperaRepository) GetByID(ctx context.Context, id uint) (domain.EntradaListaEspera, error) {
+	entrada, ok := m.entradas[id]
+	if !ok {
+		return domain.EntradaListaEspera{}, repository.ErrListaEsperaNotFound
+	}
+	return m.withPosicion(entrada), nil
+}
+func (m *MockListaEsperaRepository) GetActiva(ctx context.Context, usuarioID, actividadID uint, sesionID *uint) (domain.EntradaListaEspera, error) {
+	for id := uint(1); id <= m.nextID; id++ {
+		entrada, ok := m.entradas[id]
+		if ok && entrada.UsuarioID == usuarioID && mismaClase(entrada, actividadID, sesionID) && entrada.Activa() {
+			return m.withPosicion(entrada), nil
+		}
+	}
+	return domain.EntradaListaEspera{}, repository.ErrListaEsperaNotFound
+}
+func (m *MockListaEsperaRepository) ListActivasByUser(ctx context.Context, usuarioID uint) ([]domain.EntradaListaEspera, error) {
+	var result []domain.EntradaListaEspera
+	for id := uint(1); id <= m.nextID; id++ {
+		entrada, ok := m.entradas[id]
+		if ok && entrada.UsuarioID == usuarioID && entrada.Activa() {
+			result = append(result, m.withPosicion(entrada))
+		}
+	}
+	return result, nil
+}
+func (m *MockListaEsperaRepository) NextEsperando(ctx context.Context, actividadID uint, sesionID *uint) (domain.EntradaListaEspera, error) {
+	for id := uint(1); id <= m.nextID; id++ {
+		entrada, ok := m.entradas[id]
+		if ok && mismaClase(entrada, actividadID, sesionID) && entrada.Estado == domain.ListaEsperaEsperando {
+			return m.withPosicion(entrada), nil
+		}
+	}
+	return domain.EntradaListaEspera{}, repository.ErrListaEsperaNotFound
+}
+func (m *MockListaEsperaRepository) ListOfertasVencidas(ctx context.Context, now time.Time) ([]domain.EntradaListaEspera, error) {
+	var result []domain.EntradaListaEspera
+	for id := uint(1); id <= m.nextID; id++ {
+		entrada, ok := m.entradas[id]
+		if ok && entrada.Estado == domain.ListaEsperaOfrecida && entrada.VenceEn.Before(now) {
+			result = append(result, entrada)
+		}
+	}
+	return result, nil
+}
+func (m *MockListaEsperaRepository) Ofrecer(ctx context.Context, id uint, ofrecidaEn, venceEn time.Time) (bool, error) {
+	entrada, ok := m.entradas[id]
+	if !ok || entrada.Estado != domain.ListaEsperaEsperando {
+		return false, nil
+	}
+	entrada.Estado = domain.ListaEsperaOfrecida
+	entrada.OfrecidaEn = &ofrecidaEn
+	entrada.VenceEn = &venceEn
+	m.entradas[id] = entrada
+	return true, nil
+}
+func (m *MockListaEsperaRepository) CambiarEstado(ctx context.Context, id uint, desde, hacia string) (bool, error) {
+	entrada, ok := m.entradas[id]
+	if !ok || entrada.Estado != desde {
+		return false, nil
+	}
+	entrada.Estado = hacia
+	m.entradas[id] = entrada
+	return true, nil
+}
+func (m *MockListaEsperaRepository) CancelActiva(ctx context.Context, usuarioID, actividadID uint, sesionID *uint) error {
+	for id, entrada := range m.entradas {
+		if entrada.UsuarioID == usuarioID && mismaClase(entrada, actividadID, sesionID) && entrada.Activa() {
+			entrada.Estado = domain.ListaEsperaCancelada
+			m.entradas[id] = entrada
+		}
+	}
+	return nil
+}
+func (m *MockListaEsperaRepository) CancelByUser(ctx context.Context, usuarioID uint) (int64, error) {
+	var canceladas int64
+	for id, entrada := range m.entradas {
+		if entrada.UsuarioID == usuarioID && entrada.Activa() {
+			entrada.Estado = domain.ListaEsperaCancelada
+			m.entradas[id] = entrada
+			canceladas++
+		}
+	}
+	return canceladas, nil
+}
+func (m *MockListaEsperaRepository) CancelBySesion(ctx context.Context, sesionID uint) (int64, error) {
+	var canceladas int64
+	for id, entrada := range m.entradas {
+		if entrada.SesionID != nil && *entrada.SesionID == sesionID && entrada.Activa() {
+			entrada.Estado = domain.ListaEsperaCancelada
+			m.entradas[id] = entrada
+			canceladas++
+		}
+	}
+	return canceladas, nil
+}
+
+// withPosicion calcula la posición como posicionSQL
+func (m *MockListaEsperaRepository) withPosicion(entrada domain.EntradaListaEspera) domain.EntradaListaEspera {
+	entrada.Posicion = 0
+	if entrada.Estado != domain.ListaEsperaEsperando {
+		return entrada
+	}
+	for id := uint(1); id <= entrada.ID; id++ {
+		otra, ok := m.entradas[id]
+		if ok && otra.Estado == domain.ListaEsperaEsperando && mismaClase(otra, entrada.ActividadID, entrada.SesionID) {
+			entrada.Posicion++
+		}
+	}
+	return entrada
+}
+
+func mismaClase(entrada domain.EntradaListaEspera, actividadID uint, sesionID *uint) bool {
+	if entrada.ActividadID != actividadID || (entrada.SesionID == nil) != (sesionID == nil) {
+		return false
+	}
+	return sesionID == nil || *entrada.SesionID == *sesionID
+}
+
+// newInscripcionesEnMemoria arma un MockInscripcionesRepository sobre un slice
+// Create falla como la transacción del repositorio cuando la actividad ya tiene "cupo" inscripciones activas
+// (el cargo del cupo de clases no se registra: los tests de cupos lo descuentan en MockCuposRepository)
+func newInscripcionesEnMemoria(cupo int, inscripciones *[]domain.Inscripcion) *MockInscripcionesRepository {
+	return &MockInscripcionesRepository{
+		ListByUserFunc: func(ctx context.Context, usuarioID uint) ([]domain.Inscripcion, error) {
+			var result []domain.Inscripcion
+			for _, insc := range *inscripciones {
+				if insc.UsuarioID == usuarioID {
+					result = append(result, insc)
+				}
+			}
+			return result, nil
+		},
+		ListByActividadFunc: func(ctx context.Context, actividadID uint) ([]domain.Inscripcion, error) {
+			var result []domain.Inscripcion
+			for _, insc := range *inscripciones {
+				if insc.ActividadID == actividadID && insc.IsActiva {
+					result = append(result, insc)
+				}
+			}
+			return result, nil
+		},
+		CreateFunc: func(ctx context.Context, inscripcion domain.Inscripcion, cargo *domain.CargoCupo) (domain.Inscripcion, error) {
+			ocupados := 0
+			for _, insc := range *inscripciones {
+				if insc.ActividadID == inscripcion.ActividadID && insc.IsActiva {
+					ocupados++
+				}
+			}
+			if ocupados >= cupo {
+				return domain.Inscripcion{}, domain.ErrCupoAlcanzado
+			}
+			inscripcion.ID = uint(len(*inscripciones) + 1)
+			inscripcion.FechaInscripcion = time.Now()
+			*inscripciones = append(*inscripciones, inscripcion)
+			return inscripcion, nil
+		},
+		DeactivateFunc: func(ctx context.Context, usuarioID, actividadID uint, sesionID *uint) error {
+			for i, insc := range *inscripciones {
+				if insc.UsuarioID == usuarioID && insc.ActividadID == actividadID && mismaSesion(insc.SesionID, sesionID) && insc.IsActiva {
+					(*inscripciones)[i].IsActiva = false
+					return nil
+				}
+			}
+			return errors.New("inscripcion not found")
+		},
+	}
+}
+
+// newListaEsperaTestService arma el servicio con una clase de cupo 1 ocupada por el usuario 1
+func newListaEsperaTestService() (*InscripcionesServiceImpl, *MockListaEsperaRepository, *[]domain.Inscripcion, *[]string) {
+	inscripciones := &[]domain.Inscripcion{
+		{ID: 1, UsuarioID: 1, ActividadID: 7, IsActiva: true, FechaInscripcion: time.Now()},
+	}
+	listaEsperaRepo := newMockListaEsperaRepository()
+
+	eventos := &[]string{}
+	publisher := &MockEventPublisher{
+		PublishInscriptionEventFunc: func(action, inscriptionID string, data map[string]interface{}) error {
+			*eventos = append(*eventos, action)
+			return nil
+		},
+	}
+
+	service := NewInscripcionesService(newInscripcionesEnMemoria(1, inscripciones), &MockActividadesRepository{}, newMockSesionesRepository(), listaEsperaRepo, nil, nil, nil, publisher, 30*time.Minute)
+	return service, listaEsperaRepo, inscripciones, eventos
+}
+
+func activa(inscripciones []domain.Inscripcion, usuarioID uint) bool {
+	for _, insc := range inscripciones {
+		if insc.UsuarioID == usuarioID && insc.IsActiva {
+			return true
+		}
+	}
+	return false
+}
+
+// --- Tests ---
+
+func TestAnotarEnListaEspera(t *testing.T) {
+	service, repo, _, _ := newListaEsperaTestService()
+	sub := Subscription{ID: "sub-2", PlanInfo: Plan{TipoAcceso: "completo", ActividadesPorSemana: 3}}
+
+	err := service.anotarEnListaEspera(context.Background(), 2, 7, nil, sub)
+	var listaEsperaErr *ListaEsperaError
+	if !errors.As(err, &listaEsperaErr) || listaEsperaErr.Entrada.Posicion != 1 {
+		t.Fatalf("Expected ListaEsperaError at position 1, got %v", err)
+	}
+	if repo.entradas[1].LimiteSemanal != 0 {
+		t.Errorf("Expected no weekly limit for a full-access plan, got %d", repo.entradas[1].LimiteSemanal)
+	}
+
+	if err := service.anotarEnListaEspera(context.Background(), 2, 7, nil, sub); !errors.Is(err, ErrYaEnListaEspera) {
+		t.Errorf("Expected ErrYaEnListaEspera, got %v", err)
+	}
+}
+
+func TestDeactivate_PromotesNextEligible(t *testing.T) {
+	service, repo, inscripciones, eventos := newListaEsperaTestService()
+	ctx := context.Background()
+
+	// El usuario 2 ya usó su única clase de la semana; el 3 no tiene límite
+	*inscripciones = append(*inscripciones, domain.Inscripcion{ID: 2, UsuarioID: 2, ActividadID: 8, IsActiva: true, FechaInscripcion: time.Now()})
+	cupos := newCuposTest(inscripciones, Plan{})
+	cupos.service.now = time.Now
+	cupos.repo.registrar([]domain.ConsumoCupo{{UsuarioID: 2, InscripcionID: 2, ActividadID: 8, SesionID: 1, FechaClase: time.Now().In(gymLocation()).Format("2006-01-02")}}, false)
+	service.cupos = cupos.service
+	repo.Create(ctx, domain.EntradaListaEspera{UsuarioID: 2, ActividadID: 7, LimiteSemanal: 1, Estado: domain.ListaEsperaEsperando})
+	repo.Create(ctx, domain.EntradaListaEspera{UsuarioID: 3, ActividadID: 7, Estado: domain.ListaEsperaEsperando})
+	repo.Create(ctx, domain.EntradaListaEspera{UsuarioID: 4, ActividadID: 7, Estado: domain.ListaEsperaEsperando})
+
+	if err := service.Deactivate(ctx, 1, 7, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if repo.entradas[1].Estado != domain.ListaEsperaOmitida {
+		t.Errorf("Expected user over the weekly quota skipped, got %s", repo.entradas[1].Estado)
+	}
+	ofrecida := repo.entradas[2]
+	if ofrecida.Estado != domain.ListaEsperaOfrecida || ofrecida.VenceEn == nil || !activa(*inscripciones, 3) {
+		t.Fatalf("Expected seat offered to user 3, got %+v", ofrecida)
+	}
+	if ventana := ofrecida.VenceEn.Sub(*ofrecida.OfrecidaEn); ventana != 30*time.Minute {
+		t.Errorf("Expected 30 minute confirmation window, got %v", ventana)
+	}
+	if entrada, _ := repo.GetByID(ctx, 3); entrada.Posicion != 1 {
+		t.Errorf("Expected user 4 first in line, got position %d", entrada.Posicion)
+	}
+
+	if len(*eventos) != 2 || (*eventos)[0] != "delete" || (*eventos)[1] != "promoted" {
+		t.Errorf("Expected delete and promoted events, got %v", *eventos)
+	}
+}
+
+func TestVencerOfertas_PassesSeatOn(t *testing.T) {
+	service, repo, inscripciones, _ := newListaEsperaTestService()
+	ctx := context.Background()
+
+	repo.Create(ctx, domain.EntradaListaEspera{UsuarioID: 3, ActividadID: 7, Estado: domain.ListaEsperaEsperando})
+	repo.Create(ctx, domain.EntradaListaEspera{UsuarioID: 4, ActividadID: 7, Estado: domain.ListaEsperaEsperando})
+	if err := service.Deactivate(ctx, 1, 7, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Sin vencer no pasa nada
+	if vencidas, err := service.VencerOfertas(ctx); err != nil || vencidas != 0 {
+		t.Fatalf("Expected no expired offers, got %d (%v)", vencidas, err)
+	}
+
+	vencida := time.Now().Add(-time.Minute)
+	entrada := repo.entradas[1]
+	entrada.VenceEn = &vencida
+	repo.entradas[1] = entrada
+
+	if vencidas, err := service.VencerOfertas(ctx); err != nil || vencidas != 1 {
+		t.Fatalf("Expected 1 expired offer, got %d (%v)", vencidas, err)
+	}
+	if repo.entradas[1].Estado != domain.ListaEsperaVencida || activa(*inscripciones, 3) {
+		t.Errorf("Expected user 3 offer expired and seat released, got %+v", repo.entradas[1])
+	}
+	if repo.entradas[2].Estado != domain.ListaEsperaOfrecida || !activa(*inscripciones, 4) {
+		t.Errorf("Expected seat offered to user 4, got %+v", repo.entradas[2])
+	}
+
+	// La oferta vencida ya no se puede confirmar
+	if _, err := service.ConfirmarListaEspera(ctx, 3, 1, ""); !errors.Is(err, ErrOfertaNoDisponible) {
+		t.Errorf("Expected ErrOfertaNoDisponible, got %v", err)
+	}
+}
+
+func TestSalirListaEspera(t *testing.T) {
+	service, repo, inscripciones, _ := newListaEsperaTestService()
+	ctx := context.Background()
+
+	repo.Create(ctx, domain.EntradaListaEspera{UsuarioID: 3, ActividadID: 7, Estado: domain.ListaEsperaEsperando})
+	repo.Create(ctx, domain.EntradaListaEspera{UsuarioID: 4, ActividadID: 7, Estado: domain.ListaEsperaEsperando})
+	if err := service.Deactivate(ctx, 1, 7, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := service.SalirListaEspera(ctx, 4, 1); !errors.Is(err, repository.ErrListaEsperaNotFound) {
+		t.Errorf("Expected not found for another user's entry, got %v", err)
+	}
+
+	// Rechazar el lugar ofrecido lo pasa al siguiente
+	if err := service.SalirListaEspera(ctx, 3, 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if repo.entradas[1].Estado != domain.ListaEsperaCancelada || activa(*inscripciones, 3) {
+		t.Errorf("Expected offer declined and seat released, got %+v", repo.entradas[1])
+	}
+	if repo.entradas[2].Estado != domain.ListaEsperaOfrecida {
+		t.Errorf("Expected seat offered to user 4, got %s", repo.entradas[2].Estado)
+	}
+
+	if err := service.SalirListaEspera(ctx, 3, 1); !errors.Is(err, ErrListaEsperaInactiva) {
+		t.Errorf("Expected ErrListaEsperaInactiva, got %v", err)
+	}
+}
+
+func TestDeactivateAllByUser_CancelsWaitlist(t *testing.T) {
+	service, repo, _, _ := newListaEsperaTestService()
+	ctx := context.Background()
+
+	repo.Create(ctx, domain.EntradaListaEspera{UsuarioID: 1, ActividadID: 9, Estado: domain.ListaEsperaEsperando})
+	repo.Create(ctx, domain.EntradaListaEspera{UsuarioID: 2, ActividadID: 7, Estado: domain.ListaEsperaEsperando})
+
+	if count, err := service.DeactivateAllByUser(ctx, 1); err != nil || count != 1 {
+		t.Fatalf("Expected 1 inscripcion deactivated, got %d (%v)", count, err)
+	}
+	if repo.entradas[1].Estado != domain.ListaEsperaCancelada {
+		t.Errorf("Expected waitlist entry cancelled, got %s", repo.entradas[1].Estado)
+	}
+	if repo.entradas[2].Estado != domain.ListaEsperaOfrecida {
+		t.Errorf("Expected released seat offered to user 2, got %s", repo.entradas[2].Estado)
+	}
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Errores de penalizaciones
+var (
+	ErrPoliticaInvalida   = errors.New("política inválida: 'valor' es obligatorio para los alcances plan y categoria (y vacío en la general), y con strikes_bloqueo hace falta dias_bloqueo")
+	ErrReservasBloqueadas = errors.New("tenés las reservas bloqueadas por cancelaciones tardías o ausencias")
+)
+
+// limiteHistorialStrikes es la cantidad de strikes que devuelve el estado de penalizaciones
+const limiteHistorialStrikes = 100
+
+// PenalizacionesService define la interfaz del servicio de políticas de cancelación y strikes
+type PenalizacionesService interface {
+	ListPoliticas(ctx context.Context) ([]domain.PoliticaCancelacion, error)
+	CreatePolitica(ctx context.Context, input domain.PoliticaCancelacionCreate) (domain.PoliticaCancelacion, error)
+	UpdatePolitica(ctx context.Context, id uint, input domain.PoliticaCancelacionCreate) (domain.PoliticaCancelacion, error)
+	DeletePolitica(ctx context.Context, id uint) error
+	Estado(ctx context.Context, usuarioID uint) (domain.EstadoPenalizaciones, error)
+	AnularStrike(ctx context.Context, id, adminID uint, motivo string) (domain.Strike, error)
+	VerificarBloqueo(ctx context.Context, usuarioID uint) error
+	RegistrarCancelacion(ctx context.Context, inscripcion domain.Inscripcion, categoria string, sesion domain.Sesion) (*domain.Strike, error)
+	PenalizarAusentes(ctx context.Context, desde, hasta time.Time) (int, error)
+}
+
+// PenalizacionesServiceImpl implementa PenalizacionesService
+// Cada cancelación tardía o ausencia suma un strike según la política del plan, de la categoría
+// o la general; al juntar los strikes de bloqueo el socio no puede inscribirse por unos días
+type PenalizacionesServiceImpl struct {
+	repository repository.PenalizacionesRepository
+
+	now func() time.Time
+}
+
+// NewPenalizacionesService crea una nueva instancia del servicio
+func NewPenalizacionesService(repo repository.PenalizacionesRepository) *PenalizacionesServiceImpl {
+	return &PenalizacionesServiceImpl{
+		repository: repo,
+		now:        time.Now,
+	}
+}
+
+// ListPoliticas obtiene las políticas de cancelación
+func (s *PenalizacionesServiceImpl) ListPoliticas(ctx context.Context) ([]domain.PoliticaCancelacion, error) {
+	return s.repository.ListPoliticas(ctx)
+}
+
+// CreatePolitica crea una política para un plan, una categoría o la general
+func (s *PenalizacionesServiceImpl) CreatePolitica(ctx context.Context, input domain.PoliticaCancelacionCreate) (domain.PoliticaCancelacion, error) {
+	politica, err := normalizarPolitica(input)
+	if err != nil {
+		return domain.PoliticaCancelacion{}, err
+	}
+
+	return s.repository.CreatePolitica(ctx, politica)
+}
+
+// UpdatePolitica reemplaza la política (los strikes ya registrados no cambian)
+func (s *PenalizacionesServiceImpl) UpdatePolitica(ctx context.Context, id uint, input domain.PoliticaCancelacionCreate) (domain.PoliticaCancelacion, error) {
+	politica, err := normalizarPolitica(input)
+	if err != nil {
+		return domain.PoliticaCancelacion{}, err
+	}
+
+	return s.repository.UpdatePolitica(ctx, id, politica)
+}
+
+// DeletePolitica borra la política
+func (s *PenalizacionesServiceImpl) DeletePolitica(ctx context.Context, id uint) error {
+	return s.repository.DeletePolitica(ctx, id)
+}
+
+// normalizarPolitica valida el alcance y el bloqueo de la política
+func normalizarPolitica(input domain.PoliticaCancelacionCreate) (domain.PoliticaCancelacion, error) {
+	valor := strings.TrimSpace(input.Valor)
+	if (input.Alcance == domain.AlcanceGeneral) != (valor == "") {
+		return domain.PoliticaCancelacion{}, ErrPoliticaInvalida
+	}
+	if input.StrikesBloqueo > 0 && input.DiasBloqueo <= 0 {
+		return domain.PoliticaCancelacion{}, ErrPoliticaInvalida
+	}
+
+	return domain.PoliticaCancelacion{
+		Alcance:            input.Alcance,
+		Valor:              valor,
+		MinutosLimite:      input.MinutosLimite,
+		PenalizarAusentes:  input.PenalizarAusentes,
+		StrikesBloqueo:     input.StrikesBloqueo,
+		DiasBloqueo:        input.DiasBloqueo,
+		DiasVigenciaStrike: input.DiasVigenciaStrike,
+	}, nil
+}
+
+// resolverPolitica elige la política del plan, si no la de la categoría y si no la general
+func resolverPolitica(politicas []domain.PoliticaCancelacion, planID *string, categoria string) (domain.PoliticaCancelacion, bool) {
+	var porCategoria, general *domain.PoliticaCancelacion
+	for i, politica := range politicas {
+		switch politica.Alcance {
+		case domain.AlcancePlan:
+			if planID != nil && politica.Valor == *planID {
+				return politica, true
+			}
+		case domain.AlcanceCategoria:
+			if strings.EqualFold(politica.Valor, categoria) {
+				porCategoria = &politicas[i]
+			}
+		case domain.AlcanceGeneral:
+			general = &politicas[i]
+		}
+	}
+
+	if porCategoria != nil {
+		return *porCategoria, true
+	}
+	if general != nil {
+		return *general, true
+	}
+	return domain.PoliticaCancelacion{}, false
+}
+
+// Estado obtiene los strikes del socio y su bloqueo vigente
+func (s *PenalizacionesServiceImpl) Estado(ctx context.Context, usuarioID uint) (domain.EstadoPenalizaciones, error) {
+	strikes, err := s.repository.ListStrikes(ctx, usuarioID, limiteHistorialStrikes)
+	if err != nil {
+		return domain.EstadoPenalizaciones{}, err
+	}
+
+	bloqueo, err := s.repository.GetBloqueoVigente(ctx, usuarioID, s.now())
+	if err != nil {
+		return domain.EstadoPenalizaciones{}, err
+	}
+
+	estado := domain.EstadoPenalizaciones{
+		UsuarioID: usuarioID,
+		Strikes:   strikes,
+		Bloqueo:   bloqueo,
+	}
+	if estado.Strikes == nil {
+		estado.Strikes = []domain.Strike{}
+	}
+	for _, strike := range strikes {
+		if !strike.Anulado && strike.BloqueoID == nil {
+			estado.StrikesPendientes++
+		}
+	}
+
+	return estado, nil
+}
+
+// AnularStrike anula un strike (lo pide un admin); si había disparado un bloqueo vigente se levanta
+func (s *PenalizacionesServiceImpl) AnularStrike(ctx context.Context, id, adminID uint, motivo string) (domain.Strike, error) {
+	strike, err := s.repository.AnularStrike(ctx, id, adminID, strings.TrimSpace(motivo), s.now())
+	if err != nil {
+		return domain.Strike{}, err
+	}
+
+	log.Printf("✅ Strike %d del usuario %d anulado por %d", strike.ID, strike.UsuarioID, adminID)
+	return strike, nil
+}
+
+// VerificarBloqueo devuelve ErrReservasBloqueadas (con la fecha de fin) si el socio tiene un bloqueo vigente
+func (s *PenalizacionesServiceImpl) VerificarBloqueo(ctx context.Context, usuarioID uint) error {
+	bloqueo, err := s.repository.GetBloqueoVigente(ctx, usuarioID, s.now())
+	if err != nil {
+		return fmt.Errorf("error verificando bloqueo de reservas: %w", err)
+	}
+	if bloqueo != nil {
+		return fmt.Errorf("%w hasta el %s", ErrReservasBloqueadas, bloqueo.Hasta.In(gymLocation()).Format("02/01/2006 15:04"))
+	}
+	return nil
+}
+
+// RegistrarCancelacion suma un strike si la baja de la inscripción libera la sesión con menos anticipación
+// que la que pide su política. Devuelve nil si la cancelación fue a tiempo o no hay política
+func (s *PenalizacionesServiceImpl) RegistrarCancelacion(ctx context.Context, inscripcion domain.Inscripcion, categoria string, sesion domain.Sesion) (*domain.Strike, error) {
+	politicas, err := s.repository.ListPoliticas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	politica, ok := resolverPolitica(politicas, inscripcion.PlanID, categoria)
+	if !ok || politica.MinutosLimite <= 0 {
+		return nil, nil
+	}
+
+	now := s.now()
+	if sesionInicio(sesion).Sub(now) >= time.Duration(politica.MinutosLimite)*time.Minute {
+		return nil, nil
+	}
+
+	return s.registrarStrike(ctx, domain.Strike{
+		UsuarioID:   inscripcion.UsuarioID,
+		Tipo:        domain.StrikeCancelacionTardia,
+		ActividadID: inscripcion.ActividadID,
+		SesionID:    sesion.ID,
+		PoliticaID:  &politica.ID,
+	}, politica, now)
+}
+
+// PenalizarAusentes suma un strike por cada ausencia de [desde, hasta) cuya política penaliza ausentes
+// Las ausencias que ya tienen strike no se vuelven a leer, así que correrlo varias veces no duplica
+func (s *PenalizacionesServiceImpl) PenalizarAusentes(ctx context.Context, desde, hasta time.Time) (int, error) {
+	ausentes, err := s.repository.ListAusentesSinStrike(ctx, desde, hasta)
+	if err != nil {
+		return 0, err
+	}
+	if len(ausentes) == 0 {
+		return 0, nil
+	}
+
+	politicas, err := s.repository.ListPoliticas(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	registrados := 0
+	for _, ausente := range ausentes {
+		politica, ok := resolverPolitica(politicas, ausente.PlanID, ausente.Categoria)
+		if !ok || !politica.PenalizarAusentes {
+			continue
+		}
+
+		strike, err := s.registrarStrike(ctx, domain.Strike{
+			UsuarioID:   ausente.UsuarioID,
+			Tipo:        domain.StrikeAusente,
+			ActividadID: ausente.ActividadID,
+			SesionID:    ausente.SesionID,
+			PoliticaID:  &politica.ID,
+		}, politica, s.now())
+		if err != nil {
+			log.Printf("⚠️  No se pudo registrar el strike por ausencia del usuario %d en la sesión %d: %v", ausente.UsuarioID, ausente.SesionID, err)
+			continue
+		}
+		if strike != nil {
+			registrados++
+		}
+	}
+
+	return registrados, nil
+}
+
+// registrarStrike guarda el strike e informa el bloqueo si lo disparó
+// Un strike repetido (misma sesión y tipo) no es un error: devuelve nil
+func (s *PenalizacionesServiceImpl) registrarStrike(ctx context.Context, strike domain.Strike, politica domain.PoliticaCancelacion, now time.Time) (*domain.Strike, error) {
+	registrado, bloqueo, err := s.repository.RegistrarStrike(ctx, strike, politica, now)
+	if err != nil {
+		if errors.Is(err, domain.ErrStrikeYaRegistrado) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if bloqueo != nil {
+		log.Printf("🚫 Usuario %d bloqueado para inscribirse hasta %s (%d strikes)", bloqueo.UsuarioID, bloqueo.Hasta.Format(time.RFC3339), politica.StrikesBloqueo)
+	}
+	return &registrado, nil
+}
@@ -0,0 +1,367 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// --- Manual Mocks ---
+
+// MockPenalizacionesRepository guarda políticas, strikes y bloqueos en memoria
+// RegistrarStrike y AnularStrike reproducen la lógica de bloqueo de la transacción de MySQL
+type MockPenalizacionesRepository struct {
+	politicas []domain.PoliticaCancelacion
+	strikes   []domain.Strike
+	bloqueos  []domain.BloqueoReservas
+	ausentes  []domain.AusenteSinStrike
+}
+
+func newMockPenalizacionesRepository(politicas ...domain.PoliticaCancelacion) *MockPenalizacionesRepository {
+	for i := range politicas {
+		politicas[i].ID = uint(i + 1)
+	}
+	return &MockPenalizacionesRepository{politicas: politicas}
+}
+
+func (m *MockPenalizacionesRepository) ListPoliticas(ctx context.Context) ([]domain.PoliticaCancelacion, error) {
+	return m.politicas, nil
+}
+func (m *MockPenalizacionesRepository) CreatePolitica(ctx context.Context, politica domain.PoliticaCancelacion) (domain.PoliticaCancelacion, error) {
+	for _, existing := range m.politicas {
+		if existing.Alcance == politica.Alcance && existing.Valor == politica.Valor {
+			return domain.PoliticaCancelacion{}, domain.ErrPoliticaDuplicada
+		}
+	}
+	politica.ID = uint(len(m.politicas) + 1)
+	m.politicas = append(m.politicas, politica)
+	return politica, nil
+}
+func (m *MockPenalizacionesRepository) UpdatePolitica(ctx context.Context, id uint, politica domain.PoliticaCancelacion) (domain.PoliticaCancelacion, error) {
+	for i, existing := range m.politicas {
+		if existing.ID == id {
+			politica.ID = id
+			m.politicas[i] = politica
+			return politica, nil
+		}
+	}
+	return domain.PoliticaCancelacion{}, domain.ErrPoliticaNotFound
+}
+func (m *MockPenalizacionesRepository) DeletePolitica(ctx context.Context, id uint) error {
+	for i, existing := range m.politicas {
+		if existing.ID == id {
+			m.politicas = append(m.politicas[:i], m.politicas[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrPoliticaNotFound
+}
+func (m *MockPenalizacionesRepository) ListStrikes(ctx context.Context, usuarioID uint, limit int) ([]domain.Strike, error) {
+	var result []domain.Strike
+	for i := len(m.strikes) - 1; i >= 0 && len(result) < limit; i-- {
+		if m.strikes[i].UsuarioID == usuarioID {
+			result = append(result, m.strikes[i])
+		}
+	}
+	return result, nil
+}
+func (m *MockPenalizacionesRepository) RegistrarStrike(ctx context.Context, strike domain.Strike, politica domain.PoliticaCancelacion, now time.Time) (domain.Strike, *domain.BloqueoReservas, error) {
+	for _, existing := range m.strikes {
+		if existing.UsuarioID == strike.UsuarioID && existing.SesionID == strike.SesionID && existing.Tipo == strike.Tipo {
+			return domain.Strike{}, nil, domain.ErrStrikeYaRegistrado
+		}
+	}
+	strike.ID = uint(len(m.strikes) + 1)
+	strike.CreatedAt = now
+	m.strikes = append(m.strikes, strike)
+	if politica.StrikesBloqueo <= 0 {
+		return strike, nil, nil
+	}
+
+	var pendientes []int
+	for i, existing := range m.strikes {
+		vigente := politica.DiasVigenciaStrike == 0 || !existing.CreatedAt.Before(now.AddDate(0, 0, -politica.DiasVigenciaStrike))
+		if existing.UsuarioID == strike.UsuarioID && !existing.Anulado && existing.BloqueoID == nil && vigente {
+			pendientes = append(pendientes, i)
+		}
+	}
+	if len(pendientes) < politica.StrikesBloqueo {
+		return strike, nil, nil
+	}
+
+	bloqueo := domain.BloqueoReservas{ID: uint(len(m.bloqueos) + 1), UsuarioID: strike.UsuarioID, Hasta: now.AddDate(0, 0, politica.DiasBloqueo), CreatedAt: now}
+	m.bloqueos = append(m.bloqueos, bloqueo)
+	for _, i := range pendientes {
+		m.strikes[i].BloqueoID = &bloqueo.ID
+	}
+	return m.strikes[len(m.strikes)-1], &bloqueo, nil
+}
+func (m *MockPenalizacionesRepository) AnularStrike(ctx context.Context, id, adminID uint, motivo string, now time.Time) (domain.Strike, error) {
+	if id == 0 || int(id) > len(m.strikes) {
+		return domain.Strike{}, domain.ErrStrikeNotFound
+	}
+	strike := &m.strikes[id-1]
+	if strike.Anulado {
+		return domain.Strike{}, domain.ErrStrikeYaAnulado
+	}
+	strike.Anulado, strike.AnuladoPor, strike.MotivoAnulacion, strike.AnuladoEn = true, &adminID, motivo, &now
+
+	if strike.BloqueoID != nil {
+		bloqueo := &m.bloqueos[*strike.BloqueoID-1]
+		if !bloqueo.Levantado && bloqueo.Hasta.After(now) {
+			bloqueo.Levantado = true
+			for i := range m.strikes {
+				if m.strikes[i].BloqueoID != nil && *m.strikes[i].BloqueoID == bloqueo.ID {
+					m.strikes[i].BloqueoID = nil
+				}
+			}
+		}
+	}
+	return *strike, nil
+}
+func (m *MockPenalizacionesRepository) GetBloqueoVigente(ctx context.Context, usuarioID uint, now time.Time) (*domain.BloqueoReservas, error) {
+	for i := len(m.bloqueos) - 1; i >= 0; i-- {
+		bloqueo := m.bloqueos[i]
+		if bloqueo.UsuarioID == usuarioID && !bloqueo.Levantado && bloqueo.Hasta.After(now) {
+			return &bloqueo, nil
+		}
+	}
+	return nil, nil
+}
+func (m *MockPenalizacionesRepository) ListAusentesSinStrike(ctx context.Context, desde, hasta time.Time) ([]domain.AusenteSinStrike, error) {
+	var result []domain.AusenteSinStrike
+	for _, ausente := range m.ausentes {
+		conStrike := false
+		for _, strike := range m.strikes {
+			if strike.UsuarioID == ausente.UsuarioID && strike.SesionID == ausente.SesionID && strike.Tipo == domain.StrikeAusente {
+				conStrike = true
+			}
+		}
+		if !conStrike {
+			result = append(result, ausente)
+		}
+	}
+	return result, nil
+}
+
+// politicaSpinning cuenta tardías las cancelaciones con menos de 2 horas y bloquea 7 días con 2 strikes
+func politicaSpinning() domain.PoliticaCancelacion {
+	return domain.PoliticaCancelacion{
+		Alcance: domain.AlcanceCategoria, Valor: "Spinning", MinutosLimite: 120, PenalizarAusentes: true,
+		StrikesBloqueo: 2, DiasBloqueo: 7, DiasVigenciaStrike: 30,
+	}
+}
+
+// newCancelacionesTestService arma el servicio de inscripciones con la política de spinning
+// El martes 7/1/2025 a las 16:00 el usuario 1 tiene la inscripción fija al Spinning Intenso (actividad 10,
+// sesiones 5 y 6 los martes a las 18:00) y reservas de las sesiones 7 (viernes 10/1 18:00) y 8 (martes 7/1 17:00, cancelada)
+func newCancelacionesTestService() (*InscripcionesServiceImpl, *PenalizacionesServiceImpl, *MockPenalizacionesRepository, *[]map[string]interface{}) {
+	now := time.Date(2025, 1, 7, 16, 0, 0, 0, gymLocation())
+
+	penalizacionesRepo := newMockPenalizacionesRepository(politicaSpinning())
+	penalizaciones := NewPenalizacionesService(penalizacionesRepo)
+	penalizaciones.now = func() time.Time { return now }
+
+	sesiones := newMockSesionesRepository()
+	sesiones.sesiones[5] = domain.Sesion{ID: 5, ActividadID: 10, Fecha: "2025-01-07", HorarioInicio: "18:00", HorarioFinal: "19:00", Estado: domain.SesionProgramada}
+	sesiones.sesiones[6] = domain.Sesion{ID: 6, ActividadID: 10, Fecha: "2025-01-14", HorarioInicio: "18:00", HorarioFinal: "19:00", Estado: domain.SesionProgramada}
+	sesiones.sesiones[7] = domain.Sesion{ID: 7, ActividadID: 11, Fecha: "2025-01-10", HorarioInicio: "18:00", HorarioFinal: "19:00", Estado: domain.SesionProgramada}
+	sesiones.sesiones[8] = domain.Sesion{ID: 8, ActividadID: 11, Fecha: "2025-01-07", HorarioInicio: "17:00", HorarioFinal: "18:00", Estado: domain.SesionCancelada}
+
+	actividades := &MockActividadesRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (domain.Actividad, error) {
+			return domain.Actividad{ID: id, Titulo: "Spinning", Categoria: "spinning"}, nil
+		},
+	}
+
+	sesion7, sesion8 := uint(7), uint(8)
+	inscripciones := []domain.Inscripcion{
+		{ID: 1, UsuarioID: 1, ActividadID: 10, IsActiva: true},
+		{ID: 2, UsuarioID: 1, ActividadID: 11, SesionID: &sesion7, IsActiva: true},
+		{ID: 3, UsuarioID: 1, ActividadID: 11, SesionID: &sesion8, IsActiva: true},
+	}
+
+	eventos := &[]map[string]interface{}{}
+	publisher := &MockEventPublisher{
+		PublishInscriptionEventFunc: func(action, inscriptionID string, data map[string]interface{}) error {
+			*eventos = append(*eventos, data)
+			return nil
+		},
+	}
+
+	service := NewInscripcionesService(newInscripcionesEnMemoria(15, &inscripciones), actividades, sesiones, newMockListaEsperaRepository(), nil, penalizaciones, nil, publisher, 0)
+	service.now = func() time.Time { return now }
+	return service, penalizaciones, penalizacionesRepo, eventos
+}
+
+// --- Tests ---
+
+func TestResolverPolitica(t *testing.T) {
+	premium := "plan-premium"
+	otroPlan := "plan-basico"
+	politicas := []domain.PoliticaCancelacion{
+		{ID: 1, Alcance: domain.AlcanceGeneral},
+		{ID: 2, Alcance: domain.AlcanceCategoria, Valor: "Spinning"},
+		{ID: 3, Alcance: domain.AlcancePlan, Valor: premium},
+	}
+
+	tests := []struct {
+		name      string
+		planID    *string
+		categoria string
+		wantID    uint
+	}{
+		{"plan over category", &premium, "spinning", 3},
+		{"category ignores case", &otroPlan, "SPINNING", 2},
+		{"general as fallback", nil, "yoga", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			politica, ok := resolverPolitica(politicas, tt.planID, tt.categoria)
+			if !ok || politica.ID != tt.wantID {
+				t.Errorf("Expected policy %d, got %d (%v)", tt.wantID, politica.ID, ok)
+			}
+		})
+	}
+
+	if _, ok := resolverPolitica(politicas[1:2], nil, "yoga"); ok {
+		t.Errorf("Expected no policy without a general one")
+	}
+}
+
+func TestCreatePolitica_Validation(t *testing.T) {
+	service := NewPenalizacionesService(newMockPenalizacionesRepository())
+	ctx := context.Background()
+
+	invalidas := []domain.PoliticaCancelacionCreate{
+		{Alcance: domain.AlcanceCategoria},
+		{Alcance: domain.AlcanceGeneral, Valor: "yoga"},
+		{Alcance: domain.AlcancePlan, Valor: "plan-1", StrikesBloqueo: 3},
+	}
+	for _, input := range invalidas {
+		if _, err := service.CreatePolitica(ctx, input); !errors.Is(err, ErrPoliticaInvalida) {
+			t.Errorf("Expected ErrPoliticaInvalida for %+v, got %v", input, err)
+		}
+	}
+
+	politica, err := service.CreatePolitica(ctx, domain.PoliticaCancelacionCreate{Alcance: domain.AlcanceCategoria, Valor: " yoga ", MinutosLimite: 60})
+	if err != nil || politica.Valor != "yoga" {
+		t.Fatalf("Expected trimmed policy, got %+v (%v)", politica, err)
+	}
+	if _, err := service.CreatePolitica(ctx, domain.PoliticaCancelacionCreate{Alcance: domain.AlcanceCategoria, Valor: "yoga"}); !errors.Is(err, domain.ErrPoliticaDuplicada) {
+		t.Errorf("Expected ErrPoliticaDuplicada, got %v", err)
+	}
+}
+
+func TestDeactivate_CancelacionTardia(t *testing.T) {
+	service, _, repo, eventos := newCancelacionesTestService()
+	ctx := context.Background()
+
+	// Reserva del viernes: faltan 3 días, es a tiempo
+	sesion7 := uint(7)
+	if err := service.Deactivate(ctx, 1, 11, &sesion7); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// Sesión cancelada por el gimnasio: no cuenta
+	sesion8 := uint(8)
+	if err := service.Deactivate(ctx, 1, 11, &sesion8); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(repo.strikes) != 0 {
+		t.Fatalf("Expected no strikes for on-time cancellations, got %+v", repo.strikes)
+	}
+
+	// Inscripción fija: a la próxima sesión (hoy 18:00) le faltan exactamente 120 minutos, es a tiempo
+	if err := service.Deactivate(ctx, 1, 10, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(repo.strikes) != 0 {
+		t.Fatalf("Expected cancellation exactly at the cutoff to be on time, got %+v", repo.strikes)
+	}
+	if (*eventos)[2]["cancelacion_tardia"] != false {
+		t.Errorf("Expected cancelacion_tardia false in the event, got %v", (*eventos)[2])
+	}
+}
+
+func TestDeactivate_StrikesBloqueanReservas(t *testing.T) {
+	service, penalizaciones, repo, eventos := newCancelacionesTestService()
+	ctx := context.Background()
+
+	// 16:30: la sesión 5 de hoy a las 18:00 está dentro de la ventana de 2 horas
+	tarde := time.Date(2025, 1, 7, 16, 30, 0, 0, gymLocation())
+	service.now = func() time.Time { return tarde }
+	penalizaciones.now = service.now
+
+	if err := service.Deactivate(ctx, 1, 10, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(repo.strikes) != 1 || repo.strikes[0].Tipo != domain.StrikeCancelacionTardia || repo.strikes[0].SesionID != 5 {
+		t.Fatalf("Expected a late-cancel strike for session 5, got %+v", repo.strikes)
+	}
+	if (*eventos)[0]["cancelacion_tardia"] != true {
+		t.Errorf("Expected cancelacion_tardia true in the event, got %v", (*eventos)[0])
+	}
+	if err := penalizaciones.VerificarBloqueo(ctx, 1); err != nil {
+		t.Fatalf("Expected no ban after one strike, got %v", err)
+	}
+
+	// Una ausencia de otra clase con la misma política completa los 2 strikes
+	repo.ausentes = []domain.AusenteSinStrike{{UsuarioID: 1, ActividadID: 12, SesionID: 3, Categoria: "spinning"}}
+	registrados, err := penalizaciones.PenalizarAusentes(ctx, tarde.AddDate(0, 0, -7), tarde)
+	if err != nil || registrados != 1 {
+		t.Fatalf("Expected one no-show strike, got %d (%v)", registrados, err)
+	}
+
+	if _, err := service.Create(ctx, 1, 10, nil, false, ""); !errors.Is(err, ErrReservasBloqueadas) {
+		t.Fatalf("Expected ErrReservasBloqueadas, got %v", err)
+	}
+	estado, err := penalizaciones.Estado(ctx, 1)
+	if err != nil || estado.Bloqueo == nil || estado.StrikesPendientes != 0 || len(estado.Strikes) != 2 {
+		t.Fatalf("Expected an active ban using both strikes, got %+v (%v)", estado, err)
+	}
+	if !estado.Bloqueo.Hasta.Equal(tarde.AddDate(0, 0, 7)) {
+		t.Errorf("Expected a 7 day ban, got %v", estado.Bloqueo.Hasta)
+	}
+
+	// Anular uno levanta el bloqueo y el otro vuelve a quedar pendiente
+	if _, err := penalizaciones.AnularStrike(ctx, 1, 99, "Avisó por teléfono"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := penalizaciones.VerificarBloqueo(ctx, 1); err != nil {
+		t.Errorf("Expected ban lifted, got %v", err)
+	}
+	estado, _ = penalizaciones.Estado(ctx, 1)
+	if estado.StrikesPendientes != 1 {
+		t.Errorf("Expected one pending strike, got %d", estado.StrikesPendientes)
+	}
+	if _, err := penalizaciones.AnularStrike(ctx, 1, 99, "De nuevo"); !errors.Is(err, domain.ErrStrikeYaAnulado) {
+		t.Errorf("Expected ErrStrikeYaAnulado, got %v", err)
+	}
+}
+
+func TestPenalizarAusentes_SegunPolitica(t *testing.T) {
+	repo := newMockPenalizacionesRepository(
+		politicaSpinning(),
+		domain.PoliticaCancelacion{Alcance: domain.AlcanceCategoria, Valor: "yoga", MinutosLimite: 60},
+	)
+	service := NewPenalizacionesService(repo)
+	repo.ausentes = []domain.AusenteSinStrike{
+		{UsuarioID: 1, ActividadID: 10, SesionID: 5, Categoria: "spinning"},
+		{UsuarioID: 2, ActividadID: 20, SesionID: 9, Categoria: "yoga"},
+		{UsuarioID: 3, ActividadID: 30, SesionID: 4, Categoria: "crossfit"},
+	}
+
+	ctx := context.Background()
+	hasta := time.Date(2025, 1, 8, 0, 0, 0, 0, gymLocation())
+	registrados, err := service.PenalizarAusentes(ctx, hasta.AddDate(0, 0, -7), hasta)
+	if err != nil || registrados != 1 || repo.strikes[0].UsuarioID != 1 || repo.strikes[0].Tipo != domain.StrikeAusente {
+		t.Fatalf("Expected only the spinning no-show penalized, got %d %+v (%v)", registrados, repo.strikes, err)
+	}
+
+	// Correrlo de nuevo no duplica
+	if registrados, _ := service.PenalizarAusentes(ctx, hasta.AddDate(0, 0, -7), hasta); registrados != 0 {
+		t.Errorf("Expected no new strikes, got %d", registrados)
+	}
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// PeriodicJob corre una tarea en background cada "interval" (generación de sesiones, vencimiento de ofertas)
+type PeriodicJob struct {
+	run      func()
+	interval time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// NewPeriodicJob crea un job que ejecuta run cada "interval"
+func NewPeriodicJob(interval time.Duration, run func()) *PeriodicJob {
+	return &PeriodicJob{
+		run:      run,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start hace una primera ejecución y lanza la ejecución periódica en background
+func (j *PeriodicJob) Start() {
+	go func() {
+		j.run()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				j.run()
+			case <-j.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop detiene la ejecución periódica
+func (j *PeriodicJob) Stop() {
+	j.stopOnce.Do(func() { close(j.stop) })
+}
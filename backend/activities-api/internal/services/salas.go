@@ -0,0 +1,352 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Errores de salas y equipos
+var (
+	ErrSalaInvalida              = errors.New("datos de sala inválidos")
+	ErrSalaInexistente           = errors.New("la sala no existe o está dada de baja")
+	ErrSalaOtraSucursal          = errors.New("la sala no pertenece a la sucursal de la actividad")
+	ErrSalaOcupada               = errors.New("la sala ya está ocupada en ese horario")
+	ErrCupoSuperaSala            = errors.New("el cupo supera la capacidad de la sala")
+	ErrSalaConActividades        = errors.New("la sala tiene actividades activas: reasignalas o eliminalas antes de darla de baja")
+	ErrCapacidadSalaInsuficiente = errors.New("la nueva capacidad es menor al cupo de actividades de la sala")
+	ErrEquipoInvalido            = errors.New("datos de equipo inválidos")
+	ErrEquipoOtraSala            = errors.New("el equipo no está en la sala de la clase")
+	ErrSesionSinSala             = errors.New("la clase no tiene sala con equipos reservables")
+)
+
+// SalasService define la interfaz del servicio de salas, equipos y reservas de equipos
+type SalasService interface {
+	ListBySucursal(ctx context.Context, sucursalID uint) ([]domain.Sala, error)
+	GetByID(ctx context.Context, id uint) (domain.Sala, error)
+	Create(ctx context.Context, salaCreate domain.SalaCreate) (domain.Sala, error)
+	Update(ctx context.Context, id uint, salaUpdate domain.SalaUpdate) (domain.Sala, error)
+	Delete(ctx context.Context, id uint) error
+	CreateEquipo(ctx context.Context, salaID uint, equipoCreate domain.EquipoCreate) (domain.Equipo, error)
+	DeleteEquipo(ctx context.Context, salaID, equipoID uint) error
+	ListEquiposSesion(ctx context.Context, sesionID uint, usuarioID *uint) ([]domain.EquipoSesion, error)
+	ReservarEquipo(ctx context.Context, usuarioID, sesionID, equipoID uint) (domain.ReservaEquipo, error)
+	CancelarReservaEquipo(ctx context.Context, usuarioID, sesionID uint) error
+}
+
+// SalasServiceImpl implementa SalasService
+type SalasServiceImpl struct {
+	repository        repository.SalasRepository
+	sucursalesRepo    repository.SucursalesRepository
+	sesionesRepo      repository.SesionesRepository
+	actividadesRepo   repository.ActividadesRepository
+	inscripcionesRepo repository.InscripcionesRepository
+	now               func() time.Time
+}
+
+// NewSalasService crea una nueva instancia del servicio
+func NewSalasService(repo repository.SalasRepository, sucursalesRepo repository.SucursalesRepository, sesionesRepo repository.SesionesRepository, actividadesRepo repository.ActividadesRepository, inscripcionesRepo repository.InscripcionesRepository) *SalasServiceImpl {
+	return &SalasServiceImpl{
+		repository:        repo,
+		sucursalesRepo:    sucursalesRepo,
+		sesionesRepo:      sesionesRepo,
+		actividadesRepo:   actividadesRepo,
+		inscripcionesRepo: inscripcionesRepo,
+		now:               time.Now,
+	}
+}
+
+// ListBySucursal obtiene las salas activas de una sucursal
+func (s *SalasServiceImpl) ListBySucursal(ctx context.Context, sucursalID uint) ([]domain.Sala, error) {
+	if _, err := s.sucursalesRepo.GetByID(ctx, sucursalID); err != nil {
+		return nil, fmt.Errorf("sucursal con ID %d: %w", sucursalID, err)
+	}
+
+	salas, err := s.repository.ListBySucursal(ctx, sucursalID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing salas: %w", err)
+	}
+
+	return salas, nil
+}
+
+// GetByID obtiene una sala activa con sus equipos
+func (s *SalasServiceImpl) GetByID(ctx context.Context, id uint) (domain.Sala, error) {
+	sala, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return domain.Sala{}, fmt.Errorf("sala con ID %d: %w", id, err)
+	}
+
+	return sala, nil
+}
+
+// Create valida y crea una sala en una sucursal activa
+func (s *SalasServiceImpl) Create(ctx context.Context, salaCreate domain.SalaCreate) (domain.Sala, error) {
+	nombre := strings.TrimSpace(salaCreate.Nombre)
+	if nombre == "" {
+		return domain.Sala{}, fmt.Errorf("%w: el nombre no puede estar vacío", ErrSalaInvalida)
+	}
+	if salaCreate.Capacidad == 0 {
+		return domain.Sala{}, fmt.Errorf("%w: la capacidad debe ser mayor a 0", ErrSalaInvalida)
+	}
+
+	if _, err := s.sucursalesRepo.GetByID(ctx, salaCreate.SucursalID); err != nil {
+		if errors.Is(err, domain.ErrSucursalNotFound) {
+			return domain.Sala{}, fmt.Errorf("%w: %v (ID %d)", ErrSalaInvalida, ErrSucursalInexistente, salaCreate.SucursalID)
+		}
+		return domain.Sala{}, fmt.Errorf("error validando la sucursal: %w", err)
+	}
+
+	created, err := s.repository.Create(ctx, domain.Sala{
+		SucursalID: salaCreate.SucursalID,
+		Nombre:     nombre,
+		Capacidad:  salaCreate.Capacidad,
+	})
+	if err != nil {
+		return domain.Sala{}, fmt.Errorf("error creating sala: %w", err)
+	}
+
+	return created, nil
+}
+
+// Update cambia nombre y capacidad de la sala
+// La capacidad no puede quedar por debajo del cupo de las actividades que se dictan en ella
+func (s *SalasServiceImpl) Update(ctx context.Context, id uint, salaUpdate domain.SalaUpdate) (domain.Sala, error) {
+	nombre := strings.TrimSpace(salaUpdate.Nombre)
+	if nombre == "" {
+		return domain.Sala{}, fmt.Errorf("%w: el nombre no puede estar vacío", ErrSalaInvalida)
+	}
+	if salaUpdate.Capacidad == 0 {
+		return domain.Sala{}, fmt.Errorf("%w: la capacidad debe ser mayor a 0", ErrSalaInvalida)
+	}
+
+	if _, err := s.repository.GetByID(ctx, id); err != nil {
+		return domain.Sala{}, fmt.Errorf("error updating sala: %w", err)
+	}
+
+	actividades, err := s.repository.ListActividades(ctx, id)
+	if err != nil {
+		return domain.Sala{}, fmt.Errorf("error updating sala: %w", err)
+	}
+	for _, actividad := range actividades {
+		if actividad.Cupo > salaUpdate.Capacidad {
+			return domain.Sala{}, fmt.Errorf("%w: %s tiene cupo %d (actividad %d)", ErrCapacidadSalaInsuficiente,
+				actividad.Titulo, actividad.Cupo, actividad.ID)
+		}
+	}
+
+	updated, err := s.repository.Update(ctx, id, domain.Sala{Nombre: nombre, Capacidad: salaUpdate.Capacidad})
+	if err != nil {
+		return domain.Sala{}, fmt.Errorf("error updating sala: %w", err)
+	}
+
+	return updated, nil
+}
+
+// Delete da de baja una sala sin actividades activas
+func (s *SalasServiceImpl) Delete(ctx context.Context, id uint) error {
+	if _, err := s.repository.GetByID(ctx, id); err != nil {
+		return fmt.Errorf("error deleting sala: %w", err)
+	}
+
+	actividades, err := s.repository.ListActividades(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error deleting sala: %w", err)
+	}
+	if len(actividades) > 0 {
+		return fmt.Errorf("%w (%d)", ErrSalaConActividades, len(actividades))
+	}
+
+	if err := s.repository.Deactivate(ctx, id); err != nil {
+		return fmt.Errorf("error deleting sala: %w", err)
+	}
+
+	return nil
+}
+
+// CreateEquipo agrega un equipo reservable a la sala
+func (s *SalasServiceImpl) CreateEquipo(ctx context.Context, salaID uint, equipoCreate domain.EquipoCreate) (domain.Equipo, error) {
+	tipo := strings.ToLower(strings.TrimSpace(equipoCreate.Tipo))
+	if tipo == "" {
+		return domain.Equipo{}, fmt.Errorf("%w: el tipo no puede estar vacío", ErrEquipoInvalido)
+	}
+	if equipoCreate.Numero == 0 {
+		return domain.Equipo{}, fmt.Errorf("%w: el número debe ser mayor a 0", ErrEquipoInvalido)
+	}
+
+	if _, err := s.repository.GetByID(ctx, salaID); err != nil {
+		return domain.Equipo{}, fmt.Errorf("error creating equipo: %w", err)
+	}
+
+	created, err := s.repository.CreateEquipo(ctx, domain.Equipo{SalaID: salaID, Tipo: tipo, Numero: equipoCreate.Numero})
+	if err != nil {
+		return domain.Equipo{}, fmt.Errorf("error creating equipo: %w", err)
+	}
+
+	return created, nil
+}
+
+// DeleteEquipo da de baja un equipo de la sala (sus reservas futuras se liberan)
+func (s *SalasServiceImpl) DeleteEquipo(ctx context.Context, salaID, equipoID uint) error {
+	if err := s.repository.DeactivateEquipo(ctx, salaID, equipoID); err != nil {
+		return fmt.Errorf("error deleting equipo: %w", err)
+	}
+
+	return nil
+}
+
+// ListEquiposSesion lista los equipos de la sala de la sesión con su disponibilidad
+// usuarioID (opcional) marca como propio el equipo que reservó ese usuario
+func (s *SalasServiceImpl) ListEquiposSesion(ctx context.Context, sesionID uint, usuarioID *uint) ([]domain.EquipoSesion, error) {
+	_, sala, err := s.salaDeSesion(ctx, sesionID)
+	if err != nil {
+		return nil, err
+	}
+
+	reservas, err := s.repository.ListReservasSesion(ctx, sesionID)
+	if err != nil {
+		return nil, err
+	}
+	reservadoPor := make(map[uint]uint, len(reservas))
+	for _, reserva := range reservas {
+		reservadoPor[reserva.EquipoID] = reserva.UsuarioID
+	}
+
+	equipos := make([]domain.EquipoSesion, len(sala.Equipos))
+	for i, equipo := range sala.Equipos {
+		usuario, reservado := reservadoPor[equipo.ID]
+		equipos[i] = domain.EquipoSesion{
+			Equipo:     equipo,
+			Disponible: !reservado,
+			Propio:     reservado && usuarioID != nil && usuario == *usuarioID,
+		}
+	}
+
+	return equipos, nil
+}
+
+// ReservarEquipo reserva un equipo de la sala para el usuario en la sesión
+// El usuario tiene que estar inscripto en la sesión (fija o reserva), y la sesión no puede haber empezado
+func (s *SalasServiceImpl) ReservarEquipo(ctx context.Context, usuarioID, sesionID, equipoID uint) (domain.ReservaEquipo, error) {
+	sesion, sala, err := s.salaDeSesion(ctx, sesionID)
+	if err != nil {
+		return domain.ReservaEquipo{}, err
+	}
+	if sesion.Cancelada() {
+		return domain.ReservaEquipo{}, ErrSesionCancelada
+	}
+	if !sesionInicio(sesion).After(s.now()) {
+		return domain.ReservaEquipo{}, ErrSesionIniciada
+	}
+
+	enSala := false
+	for _, equipo := range sala.Equipos {
+		if equipo.ID == equipoID {
+			enSala = true
+			break
+		}
+	}
+	if !enSala {
+		return domain.ReservaEquipo{}, fmt.Errorf("%w (equipo %d)", ErrEquipoOtraSala, equipoID)
+	}
+
+	inscripciones, err := s.inscripcionesRepo.ListByUser(ctx, usuarioID)
+	if err != nil {
+		return domain.ReservaEquipo{}, fmt.Errorf("error validando la inscripción: %w", err)
+	}
+	if !inscriptoEnSesion(inscripciones, sesion) {
+		return domain.ReservaEquipo{}, ErrNoInscriptoEnSesion
+	}
+
+	reserva, err := s.repository.CreateReserva(ctx, domain.ReservaEquipo{
+		EquipoID:  equipoID,
+		SesionID:  sesionID,
+		UsuarioID: usuarioID,
+	})
+	if err != nil {
+		return domain.ReservaEquipo{}, fmt.Errorf("error reservando equipo: %w", err)
+	}
+
+	return reserva, nil
+}
+
+// CancelarReservaEquipo libera el equipo que el usuario reservó en la sesión
+func (s *SalasServiceImpl) CancelarReservaEquipo(ctx context.Context, usuarioID, sesionID uint) error {
+	if err := s.repository.DeleteReserva(ctx, usuarioID, sesionID); err != nil {
+		return fmt.Errorf("error cancelando la reserva de equipo: %w", err)
+	}
+
+	return nil
+}
+
+// salaDeSesion obtiene la sesión y la sala de su actividad
+func (s *SalasServiceImpl) salaDeSesion(ctx context.Context, sesionID uint) (domain.Sesion, domain.Sala, error) {
+	sesion, err := s.sesionesRepo.GetByID(ctx, sesionID)
+	if err != nil {
+		return domain.Sesion{}, domain.Sala{}, err
+	}
+
+	actividad, err := s.actividadesRepo.GetByID(ctx, sesion.ActividadID)
+	if err != nil {
+		return domain.Sesion{}, domain.Sala{}, err
+	}
+	if actividad.SalaID == nil {
+		return domain.Sesion{}, domain.Sala{}, ErrSesionSinSala
+	}
+
+	sala, err := s.repository.GetByID(ctx, *actividad.SalaID)
+	if err != nil {
+		if errors.Is(err, domain.ErrSalaNotFound) {
+			return domain.Sesion{}, domain.Sala{}, ErrSesionSinSala
+		}
+		return domain.Sesion{}, domain.Sala{}, err
+	}
+
+	return sesion, sala, nil
+}
+
+// asignarSala valida la sala de una actividad antes de guardarla: que sea de la misma sucursal,
+// que el cupo no supere su capacidad y que no haya otra clase en la sala el mismo día con horario solapado
+// excluirID es la actividad que se está actualizando (0 al crear)
+func asignarSala(ctx context.Context, salas repository.SalasRepository, actividad domain.Actividad, horaInicio, horaFin time.Time, excluirID uint) error {
+	if actividad.SalaID == nil {
+		return nil
+	}
+
+	sala, err := salas.GetByID(ctx, *actividad.SalaID)
+	if err != nil {
+		if errors.Is(err, domain.ErrSalaNotFound) {
+			return fmt.Errorf("%w (ID %d)", ErrSalaInexistente, *actividad.SalaID)
+		}
+		return fmt.Errorf("error validando la sala: %w", err)
+	}
+
+	if actividad.SucursalID == nil || *actividad.SucursalID != sala.SucursalID {
+		return fmt.Errorf("%w (sala %d de la sucursal %d)", ErrSalaOtraSucursal, sala.ID, sala.SucursalID)
+	}
+	if actividad.Cupo > sala.Capacidad {
+		return fmt.Errorf("%w (%s: %d lugares)", ErrCupoSuperaSala, sala.Nombre, sala.Capacidad)
+	}
+
+	clases, err := salas.ListActividades(ctx, sala.ID)
+	if err != nil {
+		return fmt.Errorf("error validando el horario de la sala: %w", err)
+	}
+	inicio, fin := horaInicio.Format("15:04"), horaFin.Format("15:04")
+	for _, clase := range clases {
+		if clase.ID == excluirID || clase.Dia != actividad.Dia {
+			continue
+		}
+		// Rangos [inicio, fin): una clase puede empezar cuando termina la anterior
+		if clase.HorarioInicio < fin && inicio < clase.HorarioFinal {
+			return fmt.Errorf("%w: %s (%s %s-%s, actividad %d)", ErrSalaOcupada,
+				clase.Titulo, clase.Dia, clase.HorarioInicio, clase.HorarioFinal, clase.ID)
+		}
+	}
+
+	return nil
+}
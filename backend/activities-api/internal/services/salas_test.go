@@ -0,0 +1,325 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// --- Manual Mocks ---
+
+// MockSalasRepository guarda salas, equipos, las actividades de cada sala y las reservas en memoria
+type MockSalasRepository struct {
+	salas       map[uint]domain.Sala
+	actividades []domain.Actividad // actividades activas con SalaID
+	reservas    []domain.ReservaEquipo
+}
+
+func newMockSalasRepository() *MockSalasRepository {
+	return &MockSalasRepository{
+		salas: make(map[uint]domain.Sala),
+	}
+}
+
+func (m *MockSalasRepository) ListBySucursal(ctx context.Context, sucursalID uint) ([]domain.Sala, error) {
+	var result []domain.Sala
+	for _, sala := range m.salas {
+		if sala.Activa && sala.SucursalID == sucursalID {
+			result = append(result, sala)
+		}
+	}
+	return result, nil
+}
+func (m *MockSalasRepository) GetByID(ctx context.Context, id uint) (domain.Sala, error) {
+	sala, ok := m.salas[id]
+	if !ok || !sala.Activa {
+		return domain.Sala{}, domain.ErrSalaNotFound
+	}
+	return sala, nil
+}
+func (m *MockSalasRepository) Create(ctx context.Context, sala domain.Sala) (domain.Sala, error) {
+	sala.ID = uint(len(m.salas) + 1)
+	sala.Activa = true
+	m.salas[sala.ID] = sala
+	return sala, nil
+}
+func (m *MockSalasRepository) Update(ctx context.Context, id uint, sala domain.Sala) (domain.Sala, error) {
+	existing, err := m.GetByID(ctx, id)
+	if err != nil {
+		return domain.Sala{}, err
+	}
+	existing.Nombre = sala.Nombre
+	existing.Capacidad = sala.Capacidad
+	m.salas[id] = existing
+	return existing, nil
+}
+func (m *MockSalasRepository) Deactivate(ctx context.Context, id uint) error {
+	sala, err := m.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	sala.Activa = false
+	m.salas[id] = sala
+	return nil
+}
+func (m *MockSalasRepository) ListActividades(ctx context.Context, id uint) ([]domain.Actividad, error) {
+	var result []domain.Actividad
+	for _, actividad := range m.actividades {
+		if actividad.SalaID != nil && *actividad.SalaID == id {
+			result = append(result, actividad)
+		}
+	}
+	return result, nil
+}
+func (m *MockSalasRepository) GetEquipo(ctx context.Context, id uint) (domain.Equipo, error) {
+	for _, sala := range m.salas {
+		for _, equipo := range sala.Equipos {
+			if equipo.ID == id {
+				return equipo, nil
+			}
+		}
+	}
+	return domain.Equipo{}, domain.ErrEquipoNotFound
+}
+func (m *MockSalasRepository) CreateEquipo(ctx context.Context, equipo domain.Equipo) (domain.Equipo, error) {
+	sala := m.salas[equipo.SalaID]
+	for _, existing := range sala.Equipos {
+		if existing.Tipo == equipo.Tipo && existing.Numero == equipo.Numero {
+			return domain.Equipo{}, domain.ErrEquipoDuplicado
+		}
+	}
+	equipo.ID = uint(100*equipo.SalaID) + uint(len(sala.Equipos)+1)
+	equipo.Activo = true
+	sala.Equipos = append(sala.Equipos, equipo)
+	m.salas[equipo.SalaID] = sala
+	return equipo, nil
+}
+func (m *MockSalasRepository) DeactivateEquipo(ctx context.Context, salaID, equipoID uint) error {
+	sala := m.salas[salaID]
+	for i, equipo := range sala.Equipos {
+		if equipo.ID == equipoID {
+			sala.Equipos = append(sala.Equipos[:i], sala.Equipos[i+1:]...)
+			m.salas[salaID] = sala
+			return nil
+		}
+	}
+	return domain.ErrEquipoNotFound
+}
+func (m *MockSalasRepository) ListReservasSesion(ctx context.Context, sesionID uint) ([]domain.ReservaEquipo, error) {
+	var result []domain.ReservaEquipo
+	for _, reserva := range m.reservas {
+		if reserva.SesionID == sesionID {
+			result = append(result, reserva)
+		}
+	}
+	return result, nil
+}
+func (m *MockSalasRepository) CreateReserva(ctx context.Context, reserva domain.ReservaEquipo) (domain.ReservaEquipo, error) {
+	for _, existing := range m.reservas {
+		if existing.SesionID != reserva.SesionID {
+			continue
+		}
+		if existing.UsuarioID == reserva.UsuarioID {
+			return domain.ReservaEquipo{}, domain.ErrReservaEquipoExistente
+		}
+		if existing.EquipoID == reserva.EquipoID {
+			return domain.ReservaEquipo{}, domain.ErrEquipoReservado
+		}
+	}
+	reserva.ID = uint(len(m.reservas) + 1)
+	m.reservas = append(m.reservas, reserva)
+	return reserva, nil
+}
+func (m *MockSalasRepository) DeleteReserva(ctx context.Context, usuarioID, sesionID uint) error {
+	for i, reserva := range m.reservas {
+		if reserva.UsuarioID == usuarioID && reserva.SesionID == sesionID {
+			m.reservas = append(m.reservas[:i], m.reservas[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrReservaEquipoNotFound
+}
+
+// newSalaSpinning registra en repo la sala 1 ("Spinning", 15 lugares, bicicletas 1 a 3) de la sucursal 1,
+// ocupada por la actividad 10 los martes de 18:00 a 19:00
+func newSalaSpinning(repo *MockSalasRepository) {
+	salaID, sucursalID := uint(1), uint(1)
+	repo.salas[1] = domain.Sala{
+		ID: 1, SucursalID: 1, Nombre: "Spinning", Capacidad: 15, Activa: true,
+		Equipos: []domain.Equipo{
+			{ID: 101, SalaID: 1, Tipo: "bicicleta", Numero: 1, Activo: true},
+			{ID: 102, SalaID: 1, Tipo: "bicicleta", Numero: 2, Activo: true},
+			{ID: 103, SalaID: 1, Tipo: "bicicleta", Numero: 3, Activo: true},
+		},
+	}
+	repo.salas[2] = domain.Sala{ID: 2, SucursalID: 2, Nombre: "Estudio", Capacidad: 30, Activa: true}
+	repo.actividades = []domain.Actividad{{
+		ID: 10, Titulo: "Spinning Intenso", Cupo: 15, Dia: "Martes", HorarioInicio: "18:00", HorarioFinal: "19:00",
+		SucursalID: &sucursalID, SalaID: &salaID,
+	}}
+}
+
+func TestCreateActividad_ValidaSala(t *testing.T) {
+	sucursales := newMockSucursalesRepository()
+	sucursales.sucursales[1] = domain.Sucursal{ID: 1, Nombre: "Centro", Activa: true}
+	salas := newMockSalasRepository()
+	newSalaSpinning(salas)
+
+	mockRepo := &MockActividadesRepository{
+		CreateFunc: func(ctx context.Context, actividad domain.Actividad, horaInicio, horaFin time.Time) (domain.Actividad, error) {
+			actividad.ID = 11
+			return actividad, nil
+		},
+	}
+	service := NewActividadesService(mockRepo, sucursales, newMockInstructoresRepository(), salas, &MockEventPublisher{})
+
+	salaSpinning, salaOtraSucursal, salaInexistente, sucursalID := uint(1), uint(2), uint(9), uint(1)
+	tests := []struct {
+		name          string
+		salaID        *uint
+		cupo          uint
+		inicio, final string
+		wantErr       error
+	}{
+		{"overlapping class", &salaSpinning, 15, "18:30", "19:30", ErrSalaOcupada},
+		{"cupo above capacity", &salaSpinning, 20, "07:00", "08:00", ErrCupoSuperaSala},
+		{"room of another branch", &salaOtraSucursal, 10, "07:00", "08:00", ErrSalaOtraSucursal},
+		{"unknown room", &salaInexistente, 10, "07:00", "08:00", ErrSalaInexistente},
+		{"starts when the other ends", &salaSpinning, 15, "19:00", "20:00", nil},
+		{"no room", nil, 40, "18:00", "19:00", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := domain.ActividadCreate{
+				Titulo: "Spinning Nocturno", Cupo: tt.cupo, Dia: "Martes", HorarioInicio: tt.inicio, HorarioFinal: tt.final,
+				Instructor: "Carlos", Categoria: "Cardio", SucursalID: &sucursalID, SalaID: tt.salaID,
+			}
+			_, err := service.Create(context.Background(), input)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestUpdateSala_CapacidadYBaja(t *testing.T) {
+	salas := newMockSalasRepository()
+	newSalaSpinning(salas)
+	service := NewSalasService(salas, newMockSucursalesRepository(), newMockSesionesRepository(), &MockActividadesRepository{}, &MockInscripcionesRepository{})
+
+	// La actividad 10 tiene cupo 15
+	if _, err := service.Update(context.Background(), 1, domain.SalaUpdate{Nombre: "Spinning", Capacidad: 12}); !errors.Is(err, ErrCapacidadSalaInsuficiente) {
+		t.Errorf("Expected ErrCapacidadSalaInsuficiente, got %v", err)
+	}
+	if _, err := service.Update(context.Background(), 1, domain.SalaUpdate{Nombre: "Spinning A", Capacidad: 18}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if err := service.Delete(context.Background(), 1); !errors.Is(err, ErrSalaConActividades) {
+		t.Errorf("Expected ErrSalaConActividades, got %v", err)
+	}
+	if err := service.Delete(context.Background(), 2); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+// newReservasEquipoTestService arma el servicio con la sesión 5 del Spinning Intenso (martes 7/1/2025 18:00)
+// El usuario 1 tiene la inscripción fija, el usuario 2 reservó la sesión y el usuario 3 no está inscripto
+func newReservasEquipoTestService() (*SalasServiceImpl, *MockSalasRepository) {
+	salas := newMockSalasRepository()
+	newSalaSpinning(salas)
+
+	sesiones := newMockSesionesRepository()
+	sesiones.sesiones[5] = domain.Sesion{ID: 5, ActividadID: 10, Fecha: "2025-01-07", HorarioInicio: "18:00", HorarioFinal: "19:00", Estado: domain.SesionProgramada}
+
+	actividades := &MockActividadesRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (domain.Actividad, error) {
+			return salas.actividades[0], nil
+		},
+	}
+
+	sesionID := uint(5)
+	inscripciones := []domain.Inscripcion{
+		{UsuarioID: 1, ActividadID: 10, IsActiva: true},
+		{UsuarioID: 2, ActividadID: 10, SesionID: &sesionID, IsActiva: true},
+	}
+
+	service := NewSalasService(salas, newMockSucursalesRepository(), sesiones, actividades, newInscripcionesEnMemoria(15, &inscripciones))
+	service.now = func() time.Time { return time.Date(2025, 1, 7, 12, 0, 0, 0, gymLocation()) }
+	return service, salas
+}
+
+func TestReservarEquipo(t *testing.T) {
+	service, _ := newReservasEquipoTestService()
+	ctx := context.Background()
+
+	if _, err := service.ReservarEquipo(ctx, 3, 5, 101); !errors.Is(err, ErrNoInscriptoEnSesion) {
+		t.Errorf("Expected ErrNoInscriptoEnSesion, got %v", err)
+	}
+	if _, err := service.ReservarEquipo(ctx, 1, 5, 999); !errors.Is(err, ErrEquipoOtraSala) {
+		t.Errorf("Expected ErrEquipoOtraSala, got %v", err)
+	}
+
+	if _, err := service.ReservarEquipo(ctx, 1, 5, 102); err != nil {
+		t.Fatalf("Expected no error for fixed inscription, got %v", err)
+	}
+	if _, err := service.ReservarEquipo(ctx, 2, 5, 102); !errors.Is(err, domain.ErrEquipoReservado) {
+		t.Errorf("Expected ErrEquipoReservado, got %v", err)
+	}
+	if _, err := service.ReservarEquipo(ctx, 1, 5, 103); !errors.Is(err, domain.ErrReservaEquipoExistente) {
+		t.Errorf("Expected ErrReservaEquipoExistente, got %v", err)
+	}
+	if _, err := service.ReservarEquipo(ctx, 2, 5, 103); err != nil {
+		t.Errorf("Expected no error for session booking, got %v", err)
+	}
+
+	usuarioID := uint(1)
+	equipos, err := service.ListEquiposSesion(ctx, 5, &usuarioID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(equipos) != 3 || !equipos[0].Disponible || equipos[1].Disponible || !equipos[1].Propio || equipos[2].Disponible || equipos[2].Propio {
+		t.Errorf("Expected bike 1 free, bike 2 own and bike 3 taken, got %+v", equipos)
+	}
+
+	if err := service.CancelarReservaEquipo(ctx, 1, 5); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := service.CancelarReservaEquipo(ctx, 1, 5); !errors.Is(err, domain.ErrReservaEquipoNotFound) {
+		t.Errorf("Expected ErrReservaEquipoNotFound, got %v", err)
+	}
+}
+
+func TestReservarEquipo_SesionIniciada(t *testing.T) {
+	service, _ := newReservasEquipoTestService()
+	service.now = func() time.Time { return time.Date(2025, 1, 7, 18, 5, 0, 0, gymLocation()) }
+
+	if _, err := service.ReservarEquipo(context.Background(), 1, 5, 101); !errors.Is(err, ErrSesionIniciada) {
+		t.Errorf("Expected ErrSesionIniciada, got %v", err)
+	}
+}
+
+func TestUpdateSesion_CupoLimitadoPorSala(t *testing.T) {
+	actividad := spinningMartes()
+	salaID, sucursalID := uint(1), uint(1)
+	actividad.SalaID, actividad.SucursalID = &salaID, &sucursalID
+
+	service, sesionesRepo := newSesionesTestService(actividad)
+	newSalaSpinning(service.salasRepo.(*MockSalasRepository))
+	sesionesRepo.sesiones[1] = domain.Sesion{ID: 1, ActividadID: actividad.ID, Fecha: "2025-01-07", HorarioInicio: "19:00", HorarioFinal: "20:00", Cupo: 15}
+
+	cupo := uint(16)
+	if _, err := service.Update(context.Background(), 1, domain.SesionUpdate{Cupo: &cupo}); !errors.Is(err, ErrCupoSuperaSala) {
+		t.Errorf("Expected ErrCupoSuperaSala, got %v", err)
+	}
+	cupo = 15
+	if _, err := service.Update(context.Background(), 1, domain.SesionUpdate{Cupo: &cupo}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,450 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Errores de sesiones
+var (
+	ErrSesionCancelada = errors.New("la sesión está cancelada")
+	ErrSesionIniciada  = errors.New("la sesión ya empezó")
+	ErrSesionOtraClase = errors.New("la sesión no pertenece a la actividad")
+	ErrFechaInvalida   = errors.New("fecha inválida (debe ser YYYY-MM-DD)")
+	ErrRangoSesiones   = errors.New("rango de fechas inválido: hasta no puede ser anterior a desde ni abarcar más de 92 días")
+	ErrDiaInvalido     = errors.New("día de la actividad inválido")
+)
+
+// maxDiasRangoSesiones limita el rango de GET /actividades/:id/sesiones
+const maxDiasRangoSesiones = 92
+
+// diasSemana traduce el enum Dia de las actividades a time.Weekday
+var diasSemana = map[string]time.Weekday{
+	"Domingo":   time.Sunday,
+	"Lunes":     time.Monday,
+	"Martes":    time.Tuesday,
+	"Miercoles": time.Wednesday,
+	"Jueves":    time.Thursday,
+	"Viernes":   time.Friday,
+	"Sabado":    time.Saturday,
+}
+
+// SesionesService define la interfaz del servicio de sesiones
+type SesionesService interface {
+	ListByActividad(ctx context.Context, actividadID uint, desde, hasta string) ([]domain.SesionResponse, error)
+	GetByID(ctx context.Context, id uint) (domain.Sesion, error)
+	Update(ctx context.Context, id uint, update domain.SesionUpdate) (domain.SesionResponse, error)
+	GetActividad(ctx context.Context, actividadID uint) (domain.Actividad, error)
+	CancelarSesiones(ctx context.Context, actividadID uint, input domain.SesionesCancelacion) (domain.CancelacionSesionesResponse, error)
+	Reprogramar(ctx context.Context, id uint, input domain.SesionReprogramacion) (domain.SesionResponse, error)
+	GenerateAll(ctx context.Context) (int, error)
+}
+
+// CupoSesiones descuenta, reintegra y mueve las clases del cupo de los planes
+// La implementa CuposServiceImpl
+type CupoSesiones interface {
+	DescontarFijas(ctx context.Context, actividadID uint) error
+	ReintegrarSesion(ctx context.Context, sesionID uint) (int, error)
+	MoverSesion(ctx context.Context, sesion domain.Sesion) error
+}
+
+// SesionesServiceImpl implementa SesionesService
+// Materializa las sesiones fechadas de cada actividad para los próximos "horizonte" días
+// Al cancelar o reprogramar una sesión libera o mueve las reservas y avisa a los inscriptos por eventos
+type SesionesServiceImpl struct {
+	sesionesRepo      repository.SesionesRepository
+	actividadesRepo   repository.ActividadesRepository
+	salasRepo         repository.SalasRepository
+	inscripcionesRepo repository.InscripcionesRepository
+	listaEsperaRepo   repository.ListaEsperaRepository
+	cupos             CupoSesiones // Opcional: sin él no hay cupo de clases que descontar ni reintegrar
+	eventPublisher    EventPublisher
+	horizonte         int // Días hacia adelante que se generan
+	now               func() time.Time
+}
+
+// NewSesionesService crea una nueva instancia del servicio
+func NewSesionesService(sesionesRepo repository.SesionesRepository, actividadesRepo repository.ActividadesRepository, salasRepo repository.SalasRepository, inscripcionesRepo repository.InscripcionesRepository, listaEsperaRepo repository.ListaEsperaRepository, cupos CupoSesiones, eventPublisher EventPublisher, horizonteDias int) *SesionesServiceImpl {
+	if horizonteDias <= 0 {
+		horizonteDias = 28
+	}
+
+	return &SesionesServiceImpl{
+		sesionesRepo:      sesionesRepo,
+		actividadesRepo:   actividadesRepo,
+		salasRepo:         salasRepo,
+		inscripcionesRepo: inscripcionesRepo,
+		listaEsperaRepo:   listaEsperaRepo,
+		cupos:             cupos,
+		eventPublisher:    eventPublisher,
+		horizonte:         horizonteDias,
+		now:               time.Now,
+	}
+}
+
+// ListByActividad lista las sesiones de una actividad entre dos fechas "YYYY-MM-DD" (inclusive) con sus lugares
+// Por defecto desde hoy y hasta el fin del horizonte de generación
+// Antes de listar sincroniza la parte del rango que cae dentro del horizonte
+func (s *SesionesServiceImpl) ListByActividad(ctx context.Context, actividadID uint, desdeParam, hastaParam string) ([]domain.SesionResponse, error) {
+	desde, hasta, err := s.parseRango(desdeParam, hastaParam)
+	if err != nil {
+		return nil, err
+	}
+
+	actividad, err := s.actividadesRepo.GetByID(ctx, actividadID)
+	if err != nil {
+		return nil, err
+	}
+
+	hoy, limite := s.ventana()
+	if desde.After(hoy) {
+		hoy = desde
+	}
+	if hasta.Before(limite) {
+		limite = hasta
+	}
+	if _, err := s.syncActividad(ctx, actividad, hoy, limite); err != nil {
+		return nil, err
+	}
+
+	sesiones, err := s.sesionesRepo.ListByActividad(ctx, actividadID, desde, hasta)
+	if err != nil {
+		return nil, fmt.Errorf("error listing sesiones: %w", err)
+	}
+
+	responses := make([]domain.SesionResponse, len(sesiones))
+	for i, sesion := range sesiones {
+		responses[i] = sesion.ToResponse()
+	}
+
+	return responses, nil
+}
+
+// GetByID obtiene una sesión por ID
+func (s *SesionesServiceImpl) GetByID(ctx context.Context, id uint) (domain.Sesion, error) {
+	return s.sesionesRepo.GetByID(ctx, id)
+}
+
+// GetActividad obtiene la actividad de las sesiones (el controller valida con ella la sucursal)
+func (s *SesionesServiceImpl) GetActividad(ctx context.Context, actividadID uint) (domain.Actividad, error) {
+	return s.actividadesRepo.GetByID(ctx, actividadID)
+}
+
+// Update cambia una sesión puntual: cupo, reemplazo de instructor o cancelación
+// La sesión queda personalizada y la generación ya no la pisa con los datos de la actividad
+// Al cancelarla se liberan sus reservas y se publica activity.session_cancelled
+func (s *SesionesServiceImpl) Update(ctx context.Context, id uint, update domain.SesionUpdate) (domain.SesionResponse, error) {
+	sesion, err := s.sesionesRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.SesionResponse{}, err
+	}
+	cancelando := update.Cancelada != nil && *update.Cancelada && !sesion.Cancelada()
+
+	if update.Cupo != nil {
+		if *update.Cupo < sesion.Ocupados {
+			return domain.SesionResponse{}, fmt.Errorf("no se puede cambiar el cupo, hay inscripciones activas que superan el nuevo límite")
+		}
+		if err := s.validarCupoSala(ctx, sesion.ActividadID, *update.Cupo); err != nil {
+			return domain.SesionResponse{}, err
+		}
+		sesion.Cupo = *update.Cupo
+	}
+	if update.Instructor != nil {
+		sesion.Instructor = *update.Instructor
+	}
+	if update.InstructorID != nil {
+		sesion.InstructorID = update.InstructorID
+	}
+	if update.Cancelada != nil {
+		if *update.Cancelada {
+			sesion.Estado = domain.SesionCancelada
+			sesion.MotivoCancelacion = update.MotivoCancelacion
+		} else {
+			sesion.Estado = domain.SesionProgramada
+			sesion.MotivoCancelacion = ""
+		}
+	}
+	sesion.Personalizada = true
+
+	horaInicio, horaFin, err := parseHorarios(sesion.HorarioInicio, sesion.HorarioFinal)
+	if err != nil {
+		return domain.SesionResponse{}, err
+	}
+
+	updated, err := s.sesionesRepo.Update(ctx, id, sesion, horaInicio, horaFin)
+	if err != nil {
+		return domain.SesionResponse{}, fmt.Errorf("error updating sesion: %w", err)
+	}
+
+	if cancelando {
+		s.liberarSesion(ctx, updated)
+	}
+
+	return updated.ToResponse(), nil
+}
+
+// GenerateAll sincroniza las sesiones de todas las actividades para el horizonte
+// Un error en una actividad se loguea y no frena a las demás
+func (s *SesionesServiceImpl) GenerateAll(ctx context.Context) (int, error) {
+	actividades, err := s.actividadesRepo.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error listing actividades: %w", err)
+	}
+
+	desde, hasta := s.ventana()
+	total := 0
+	for _, actividad := range actividades {
+		creadas, err := s.syncActividad(ctx, actividad, desde, hasta)
+		if err != nil {
+			log.Printf("⚠️  [GenerateAll] Error generando sesiones de la actividad %d: %v", actividad.ID, err)
+			continue
+		}
+		total += creadas
+	}
+
+	return total, nil
+}
+
+// parseRango parsea desde/hasta en la zona del gimnasio y aplica los valores por defecto
+func (s *SesionesServiceImpl) parseRango(desdeParam, hastaParam string) (time.Time, time.Time, error) {
+	desde, _ := s.ventana()
+	if desdeParam != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", desdeParam, gymLocation())
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrFechaInvalida
+		}
+		desde = parsed
+	}
+
+	hasta := desde.AddDate(0, 0, s.horizonte-1)
+	if hastaParam != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", hastaParam, gymLocation())
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrFechaInvalida
+		}
+		hasta = parsed
+	}
+
+	if hasta.Before(desde) || hasta.After(desde.AddDate(0, 0, maxDiasRangoSesiones)) {
+		return time.Time{}, time.Time{}, ErrRangoSesiones
+	}
+	return desde, hasta, nil
+}
+
+// ventana devuelve hoy y el último día del horizonte (medianoche en la zona del gimnasio)
+func (s *SesionesServiceImpl) ventana() (time.Time, time.Time) {
+	now := s.now().In(gymLocation())
+	hoy := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return hoy, hoy.AddDate(0, 0, s.horizonte-1)
+}
+
+// syncActividad deja las sesiones del rango iguales a la actividad:
+// crea las que faltan y actualiza o borra las generadas que ya no coinciden
+// Nunca toca sesiones personalizadas, ya empezadas, ni borra sesiones con reservas
+func (s *SesionesServiceImpl) syncActividad(ctx context.Context, actividad domain.Actividad, desde, hasta time.Time) (int, error) {
+	if hasta.Before(desde) {
+		return 0, nil
+	}
+
+	fechas, err := ocurrencias(actividad.Dia, desde, hasta)
+	if err != nil {
+		return 0, err
+	}
+	horaInicio, horaFin, err := parseHorarios(actividad.HorarioInicio, actividad.HorarioFinal)
+	if err != nil {
+		return 0, err
+	}
+
+	existentes, err := s.sesionesRepo.ListByActividad(ctx, actividad.ID, desde, hasta)
+	if err != nil {
+		return 0, fmt.Errorf("error listing sesiones: %w", err)
+	}
+	// Una sesión reprogramada sigue ocupando la fecha que le tocaba: la generación no la vuelve a crear
+	movidas, err := s.sesionesRepo.ListReprogramadas(ctx, actividad.ID, desde, hasta)
+	if err != nil {
+		return 0, fmt.Errorf("error listing sesiones: %w", err)
+	}
+	porFecha := make(map[string]domain.Sesion, len(existentes)+len(movidas))
+	for _, sesion := range append(existentes, movidas...) {
+		porFecha[fechaProgramada(sesion)] = sesion
+	}
+
+	now := s.now()
+	creadas := 0
+	for _, fecha := range fechas {
+		generada := sesionDeActividad(actividad, fecha)
+
+		existente, ok := porFecha[generada.Fecha]
+		if !ok {
+			if err := s.sesionesRepo.Create(ctx, generada, fecha, horaInicio, horaFin); err != nil {
+				return creadas, err
+			}
+			creadas++
+			continue
+		}
+		delete(porFecha, generada.Fecha)
+
+		if existente.Personalizada || coincideConActividad(existente, generada) || !sesionInicio(existente).After(now) {
+			continue
+		}
+		existente.HorarioInicio = generada.HorarioInicio
+		existente.HorarioFinal = generada.HorarioFinal
+		existente.Cupo = generada.Cupo
+		existente.Instructor = generada.Instructor
+		existente.InstructorID = generada.InstructorID
+		if _, err := s.sesionesRepo.Update(ctx, existente.ID, existente, horaInicio, horaFin); err != nil {
+			return creadas, err
+		}
+	}
+
+	// Lo que queda ya no corresponde al día de la actividad (cambió el día)
+	for _, sobrante := range porFecha {
+		if sobrante.Personalizada || !sesionInicio(sobrante).After(now) {
+			continue
+		}
+		borrada, err := s.sesionesRepo.DeleteIfUnbooked(ctx, sobrante.ID)
+		if err != nil {
+			return creadas, err
+		}
+		if !borrada {
+			log.Printf("⚠️  [syncActividad] La sesión %d (%s) tiene reservas y ya no coincide con la actividad %d", sobrante.ID, sobrante.Fecha, actividad.ID)
+		}
+	}
+
+	// Las sesiones nuevas se descuentan del cupo de los inscriptos fijos
+	if creadas > 0 && s.cupos != nil {
+		if err := s.cupos.DescontarFijas(ctx, actividad.ID); err != nil {
+			log.Printf("⚠️  [syncActividad] Error descontando las sesiones nuevas de la actividad %d del cupo: %v", actividad.ID, err)
+		}
+	}
+
+	return creadas, nil
+}
+
+// ocurrencias devuelve las fechas del rango (inclusive) que caen en el día de la actividad
+func ocurrencias(dia string, desde, hasta time.Time) ([]time.Time, error) {
+	weekday, ok := diasSemana[dia]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrDiaInvalido, dia)
+	}
+
+	var fechas []time.Time
+	primera := desde.AddDate(0, 0, (int(weekday)-int(desde.Weekday())+7)%7)
+	for fecha := primera; !fecha.After(hasta); fecha = fecha.AddDate(0, 0, 7) {
+		fechas = append(fechas, fecha)
+	}
+
+	return fechas, nil
+}
+
+// fechaProgramada devuelve la fecha que le toca a la sesión según el día de la actividad
+func fechaProgramada(sesion domain.Sesion) string {
+	if sesion.Reprogramada() {
+		return sesion.FechaOriginal
+	}
+	return sesion.Fecha
+}
+
+// sesionDeActividad arma la sesión de una fecha con los datos de la actividad
+func sesionDeActividad(actividad domain.Actividad, fecha time.Time) domain.Sesion {
+	return domain.Sesion{
+		ActividadID:   actividad.ID,
+		Fecha:         fecha.Format("2006-01-02"),
+		HorarioInicio: actividad.HorarioInicio,
+		HorarioFinal:  actividad.HorarioFinal,
+		Cupo:          actividad.Cupo,
+		Instructor:    actividad.Instructor,
+		InstructorID:  actividad.InstructorID,
+		Estado:        domain.SesionProgramada,
+	}
+}
+
+// coincideConActividad indica si una sesión generada sigue igual a la actividad
+func coincideConActividad(sesion, generada domain.Sesion) bool {
+	sameInstructorID := (sesion.InstructorID == nil && generada.InstructorID == nil) ||
+		(sesion.InstructorID != nil && generada.InstructorID != nil && *sesion.InstructorID == *generada.InstructorID)
+
+	return sesion.HorarioInicio == generada.HorarioInicio &&
+		sesion.HorarioFinal == generada.HorarioFinal &&
+		sesion.Cupo == generada.Cupo &&
+		sesion.Instructor == generada.Instructor &&
+		sameInstructorID
+}
+
+// validarCupoSala verifica que el cupo de una sesión no supere la capacidad de la sala de su actividad
+func (s *SesionesServiceImpl) validarCupoSala(ctx context.Context, actividadID uint, cupo uint) error {
+	actividad, err := s.actividadesRepo.GetByID(ctx, actividadID)
+	if err != nil {
+		return err
+	}
+	if actividad.SalaID == nil {
+		return nil
+	}
+
+	sala, err := s.salasRepo.GetByID(ctx, *actividad.SalaID)
+	if err != nil {
+		if errors.Is(err, domain.ErrSalaNotFound) {
+			return nil
+		}
+		return fmt.Errorf("error validando la sala: %w", err)
+	}
+	if cupo > sala.Capacidad {
+		return fmt.Errorf("%w (%s: %d lugares)", ErrCupoSuperaSala, sala.Nombre, sala.Capacidad)
+	}
+
+	return nil
+}
+
+// sesionInicio devuelve el momento en que empieza la sesión (zona del gimnasio)
+// Ante datos inválidos devuelve el cero, que se trata como sesión pasada
+func sesionInicio(sesion domain.Sesion) time.Time {
+	inicio, err := time.ParseInLocation("2006-01-02 15:04", sesion.Fecha+" "+sesion.HorarioInicio, gymLocation())
+	if err != nil {
+		return time.Time{}
+	}
+	return inicio
+}
+
+// sesionFin devuelve el momento en que termina la sesión (una clase que cruza la medianoche termina al día siguiente)
+func sesionFin(sesion domain.Sesion) time.Time {
+	fin, err := time.ParseInLocation("2006-01-02 15:04", sesion.Fecha+" "+sesion.HorarioFinal, gymLocation())
+	if err != nil {
+		return time.Time{}
+	}
+	if inicio := sesionInicio(sesion); !fin.After(inicio) {
+		fin = fin.AddDate(0, 0, 1)
+	}
+	return fin
+}
+
+// gymLocation devuelve la zona horaria del gimnasio
+func gymLocation() *time.Location {
+	loc, err := time.LoadLocation("America/Argentina/Buenos_Aires")
+	if err != nil {
+		return time.Local // Si no se puede cargar, usar la zona horaria local del sistema
+	}
+	return loc
+}
+
+// NewSesionesGenerator crea el job que genera las sesiones del horizonte cada "interval"
+func NewSesionesGenerator(service SesionesService, interval time.Duration) *PeriodicJob {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return NewPeriodicJob(interval, func() {
+		creadas, err := service.GenerateAll(context.Background())
+		if err != nil {
+			log.Printf("⚠️  No se pudieron generar las sesiones: %v", err)
+			return
+		}
+		if creadas > 0 {
+			log.Printf("📅 Sesiones generadas: %d", creadas)
+		}
+	})
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Errores de cancelación y reprogramación de sesiones
+var (
+	ErrHorarioSesion = errors.New("horario inválido")
+	ErrSesionPasada  = errors.New("la nueva fecha y hora de la sesión ya pasaron")
+)
+
+// CancelarSesiones cancela las sesiones de la actividad entre desde y hasta (inclusive) que todavía no empezaron
+// Las del rango que no se generaron aún se crean primero para que la generación no las vuelva a programar
+// Cada sesión cancelada libera sus reservas y publica activity.session_cancelled con los inscriptos
+func (s *SesionesServiceImpl) CancelarSesiones(ctx context.Context, actividadID uint, input domain.SesionesCancelacion) (domain.CancelacionSesionesResponse, error) {
+	hastaParam := input.Hasta
+	if hastaParam == "" {
+		hastaParam = input.Desde
+	}
+	desde, hasta, err := s.parseRango(input.Desde, hastaParam)
+	if err != nil {
+		return domain.CancelacionSesionesResponse{}, err
+	}
+
+	actividad, err := s.actividadesRepo.GetByID(ctx, actividadID)
+	if err != nil {
+		return domain.CancelacionSesionesResponse{}, err
+	}
+
+	hoy, _ := s.ventana()
+	if hoy.Before(desde) {
+		hoy = desde
+	}
+	if _, err := s.syncActividad(ctx, actividad, hoy, hasta); err != nil {
+		return domain.CancelacionSesionesResponse{}, err
+	}
+
+	sesiones, err := s.sesionesRepo.ListByActividad(ctx, actividadID, desde, hasta)
+	if err != nil {
+		return domain.CancelacionSesionesResponse{}, fmt.Errorf("error listing sesiones: %w", err)
+	}
+
+	now := s.now()
+	resultado := domain.CancelacionSesionesResponse{Sesiones: []domain.SesionResponse{}}
+	for _, sesion := range sesiones {
+		if sesion.Cancelada() || !sesionInicio(sesion).After(now) {
+			continue
+		}
+
+		horaInicio, horaFin, err := parseHorarios(sesion.HorarioInicio, sesion.HorarioFinal)
+		if err != nil {
+			return resultado, err
+		}
+		sesion.Estado = domain.SesionCancelada
+		sesion.MotivoCancelacion = input.Motivo
+		sesion.Personalizada = true
+
+		cancelada, err := s.sesionesRepo.Update(ctx, sesion.ID, sesion, horaInicio, horaFin)
+		if err != nil {
+			return resultado, fmt.Errorf("error cancelling sesion %d: %w", sesion.ID, err)
+		}
+
+		reservas, usuarios := s.liberarSesion(ctx, cancelada)
+		resultado.Sesiones = append(resultado.Sesiones, cancelada.ToResponse())
+		resultado.ReservasLiberadas += reservas
+		resultado.UsuariosNotificados += usuarios
+	}
+
+	log.Printf("🚫 Actividad %d: %d sesiones canceladas (%s a %s), %d reservas liberadas", actividadID,
+		len(resultado.Sesiones), desde.Format("2006-01-02"), hasta.Format("2006-01-02"), resultado.ReservasLiberadas)
+	return resultado, nil
+}
+
+// Reprogramar mueve una sesión que todavía no empezó a otra fecha u horario
+// Las reservas, las fijas y los equipos reservados siguen apuntando a la sesión, así que se mueven con ella
+// La sala de la actividad tiene que estar libre en el nuevo horario; se publica activity.session_rescheduled
+func (s *SesionesServiceImpl) Reprogramar(ctx context.Context, id uint, input domain.SesionReprogramacion) (domain.SesionResponse, error) {
+	sesion, err := s.sesionesRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.SesionResponse{}, err
+	}
+
+	now := s.now()
+	if sesion.Cancelada() {
+		return domain.SesionResponse{}, ErrSesionCancelada
+	}
+	if !sesionInicio(sesion).After(now) {
+		return domain.SesionResponse{}, ErrSesionIniciada
+	}
+
+	fecha, err := time.ParseInLocation("2006-01-02", input.Fecha, gymLocation())
+	if err != nil {
+		return domain.SesionResponse{}, ErrFechaInvalida
+	}
+	horaInicio, horaFin, err := parseHorarios(input.HorarioInicio, input.HorarioFinal)
+	if err != nil {
+		return domain.SesionResponse{}, fmt.Errorf("%w: %v", ErrHorarioSesion, err)
+	}
+
+	nueva := sesion
+	nueva.Fecha = fecha.Format("2006-01-02")
+	nueva.HorarioInicio = horaInicio.Format("15:04")
+	nueva.HorarioFinal = horaFin.Format("15:04")
+	if !sesionInicio(nueva).After(now) {
+		return domain.SesionResponse{}, ErrSesionPasada
+	}
+
+	actividad, err := s.actividadesRepo.GetByID(ctx, sesion.ActividadID)
+	if err != nil {
+		return domain.SesionResponse{}, err
+	}
+
+	// Otro día de la semana de la actividad ya tiene (o va a tener) su propia sesión
+	fechaOriginal := fechaProgramada(sesion)
+	if nueva.Fecha != fechaOriginal && fecha.Weekday() == diasSemana[actividad.Dia] {
+		return domain.SesionResponse{}, domain.ErrSesionDuplicada
+	}
+	if err := s.validarSalaLibre(ctx, actividad, nueva); err != nil {
+		return domain.SesionResponse{}, err
+	}
+
+	original, err := time.ParseInLocation("2006-01-02", fechaOriginal, gymLocation())
+	if err != nil {
+		return domain.SesionResponse{}, ErrFechaInvalida
+	}
+	reprogramada, err := s.sesionesRepo.Reprogramar(ctx, id, original, fecha, horaInicio, horaFin)
+	if err != nil {
+		return domain.SesionResponse{}, err
+	}
+
+	if s.cupos != nil {
+		if err := s.cupos.MoverSesion(ctx, reprogramada); err != nil {
+			log.Printf("⚠️  Error moviendo el cupo de la sesión reprogramada %d: %v", id, err)
+		}
+	}
+
+	if s.actividadesRepo != nil {
+		s.actividadesRepo.InvalidateCache()
+	}
+
+	eventData := map[string]interface{}{
+		"sesion_id":               reprogramada.ID,
+		"actividad_id":            reprogramada.ActividadID,
+		"titulo":                  reprogramada.Titulo,
+		"fecha_anterior":          sesion.Fecha,
+		"horario_inicio_anterior": sesion.HorarioInicio,
+		"horario_final_anterior":  sesion.HorarioFinal,
+		"fecha":                   reprogramada.Fecha,
+		"horario_inicio":          reprogramada.HorarioInicio,
+		"horario_final":           reprogramada.HorarioFinal,
+		"motivo":                  input.Motivo,
+		"usuarios":                s.inscriptosSesion(ctx, reprogramada),
+	}
+	if err := s.eventPublisher.PublishActivityEvent("session_rescheduled", fmt.Sprintf("%d", reprogramada.ActividadID), eventData); err != nil {
+		log.Printf("⚠️  Error publicando evento activity.session_rescheduled: %v", err)
+	}
+
+	log.Printf("📅 Sesión %d reprogramada: %s %s → %s %s", id, sesion.Fecha, sesion.HorarioInicio, reprogramada.Fecha, reprogramada.HorarioInicio)
+	return reprogramada.ToResponse(), nil
+}
+
+// liberarSesion da de baja las reservas de una sesión recién cancelada, la reintegra al cupo, cancela su lista de espera
+// y sus equipos reservados, y publica activity.session_cancelled con todos los inscriptos (fijas y reservas)
+// Las fijas siguen activas para las demás sesiones. Devuelve las reservas liberadas y los usuarios avisados
+// Los errores se loguean: la sesión ya está cancelada
+func (s *SesionesServiceImpl) liberarSesion(ctx context.Context, sesion domain.Sesion) (int, int) {
+	inscripciones, err := s.inscripcionesRepo.ListByActividad(ctx, sesion.ActividadID)
+	if err != nil {
+		log.Printf("⚠️  Error buscando los inscriptos de la sesión cancelada %d: %v", sesion.ID, err)
+	}
+
+	usuarios := []uint{}
+	reservas := []uint{}
+	for _, insc := range inscripciones {
+		if !insc.IsActiva || (insc.SesionID != nil && *insc.SesionID != sesion.ID) {
+			continue
+		}
+		usuarios = append(usuarios, insc.UsuarioID)
+		if insc.SesionID == nil {
+			continue
+		}
+
+		if err := s.inscripcionesRepo.Deactivate(ctx, insc.UsuarioID, insc.ActividadID, insc.SesionID); err != nil {
+			log.Printf("⚠️  Error liberando la reserva del usuario %d en la sesión %d: %v", insc.UsuarioID, sesion.ID, err)
+			continue
+		}
+		reservas = append(reservas, insc.UsuarioID)
+
+		eventData := map[string]interface{}{
+			"usuario_id":   insc.UsuarioID,
+			"actividad_id": insc.ActividadID,
+			"sesion_id":    insc.SesionID,
+			"reason":       "session_cancelled",
+		}
+		if err := s.eventPublisher.PublishInscriptionEvent("delete", inscripcionKey(insc.UsuarioID, insc.ActividadID, insc.SesionID), eventData); err != nil {
+			log.Printf("⚠️  Error publicando evento inscription.delete: %v", err)
+		}
+	}
+
+	// La clase cancelada vuelve al cupo del plan de todos los que la tenían descontada (fijas y reservas)
+	if s.cupos != nil {
+		if _, err := s.cupos.ReintegrarSesion(ctx, sesion.ID); err != nil {
+			log.Printf("⚠️  Error reintegrando el cupo de la sesión cancelada %d: %v", sesion.ID, err)
+		}
+	}
+
+	if _, err := s.listaEsperaRepo.CancelBySesion(ctx, sesion.ID); err != nil {
+		log.Printf("⚠️  Error cancelando la lista de espera de la sesión %d: %v", sesion.ID, err)
+	}
+
+	equipos, err := s.salasRepo.ListReservasSesion(ctx, sesion.ID)
+	if err != nil {
+		log.Printf("⚠️  Error buscando los equipos reservados de la sesión %d: %v", sesion.ID, err)
+	}
+	for _, reserva := range equipos {
+		if err := s.salasRepo.DeleteReserva(ctx, reserva.UsuarioID, sesion.ID); err != nil {
+			log.Printf("⚠️  Error liberando el equipo %d de la sesión %d: %v", reserva.EquipoID, sesion.ID, err)
+		}
+	}
+
+	if s.actividadesRepo != nil {
+		s.actividadesRepo.InvalidateCache()
+	}
+
+	eventData := map[string]interface{}{
+		"sesion_id":          sesion.ID,
+		"actividad_id":       sesion.ActividadID,
+		"titulo":             sesion.Titulo,
+		"fecha":              sesion.Fecha,
+		"horario_inicio":     sesion.HorarioInicio,
+		"horario_final":      sesion.HorarioFinal,
+		"motivo":             sesion.MotivoCancelacion,
+		"usuarios":           usuarios,
+		"reservas_liberadas": reservas,
+	}
+	if err := s.eventPublisher.PublishActivityEvent("session_cancelled", fmt.Sprintf("%d", sesion.ActividadID), eventData); err != nil {
+		log.Printf("⚠️  Error publicando evento activity.session_cancelled: %v", err)
+	}
+
+	return len(reservas), len(usuarios)
+}
+
+// inscriptosSesion devuelve los usuarios con inscripción fija a la actividad o reserva de la sesión
+func (s *SesionesServiceImpl) inscriptosSesion(ctx context.Context, sesion domain.Sesion) []uint {
+	inscripciones, err := s.inscripcionesRepo.ListByActividad(ctx, sesion.ActividadID)
+	if err != nil {
+		log.Printf("⚠️  Error buscando los inscriptos de la sesión %d: %v", sesion.ID, err)
+	}
+
+	usuarios := []uint{}
+	for _, insc := range inscripciones {
+		if insc.IsActiva && (insc.SesionID == nil || *insc.SesionID == sesion.ID) {
+			usuarios = append(usuarios, insc.UsuarioID)
+		}
+	}
+	return usuarios
+}
+
+// validarSalaLibre verifica que ninguna otra clase de la sala de la actividad se superponga con la sesión en su nueva fecha
+// Cuenta las sesiones de ese día (programadas) y, si todavía no se generaron, el horario semanal de la actividad
+func (s *SesionesServiceImpl) validarSalaLibre(ctx context.Context, actividad domain.Actividad, sesion domain.Sesion) error {
+	if actividad.SalaID == nil {
+		return nil
+	}
+
+	clases, err := s.salasRepo.ListActividades(ctx, *actividad.SalaID)
+	if err != nil {
+		return fmt.Errorf("error validando el horario de la sala: %w", err)
+	}
+	fecha, err := time.ParseInLocation("2006-01-02", sesion.Fecha, gymLocation())
+	if err != nil {
+		return ErrFechaInvalida
+	}
+
+	for _, clase := range clases {
+		if clase.ID == actividad.ID {
+			continue
+		}
+
+		delDia, err := s.sesionesRepo.ListByActividad(ctx, clase.ID, fecha, fecha)
+		if err != nil {
+			return fmt.Errorf("error validando el horario de la sala: %w", err)
+		}
+		if len(delDia) == 0 {
+			if diasSemana[clase.Dia] != fecha.Weekday() {
+				continue
+			}
+			// La sesión de ese día no se generó todavía, salvo que ya se haya movido a otra fecha
+			movidas, err := s.sesionesRepo.ListReprogramadas(ctx, clase.ID, fecha, fecha)
+			if err != nil {
+				return fmt.Errorf("error validando el horario de la sala: %w", err)
+			}
+			if len(movidas) > 0 {
+				continue
+			}
+			delDia = []domain.Sesion{sesionDeActividad(clase, fecha)}
+		}
+
+		for _, otra := range delDia {
+			// Rangos [inicio, fin): una clase puede empezar cuando termina la anterior
+			if !otra.Cancelada() && otra.HorarioInicio < sesion.HorarioFinal && sesion.HorarioInicio < otra.HorarioFinal {
+				return fmt.Errorf("%w: %s (%s %s-%s, actividad %d)", ErrSalaOcupada,
+					clase.Titulo, otra.Fecha, otra.HorarioInicio, otra.HorarioFinal, clase.ID)
+			}
+		}
+	}
+
+	return nil
+}
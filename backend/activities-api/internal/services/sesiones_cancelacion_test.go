@@ -0,0 +1,231 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// eventoActividad guarda un evento activity.* publicado
+type eventoActividad struct {
+	action string
+	data   map[string]interface{}
+}
+
+// cancelacionesSesionTest reúne el servicio de sesiones y sus repositorios en memoria
+type cancelacionesSesionTest struct {
+	service       *SesionesServiceImpl
+	sesiones      *MockSesionesRepository
+	salas         *MockSalasRepository
+	listaEspera   *MockListaEsperaRepository
+	inscripciones *[]domain.Inscripcion
+	eventos       *[]eventoActividad
+}
+
+// newCancelacionesSesionTest arma el servicio con el spinning de los martes (sala 1) y "ahora" fijo
+// El lunes 6/1/2025 a las 9:00 con un horizonte de 14 días; genera las sesiones del 7 y del 14
+func newCancelacionesSesionTest(t *testing.T) *cancelacionesSesionTest {
+	actividad := spinningMartes()
+	salaID := uint(1)
+	actividad.SalaID = &salaID
+
+	actividadesRepo := &MockActividadesRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (domain.Actividad, error) {
+			if id != actividad.ID {
+				return domain.Actividad{}, errors.New("actividad not found")
+			}
+			return *actividad, nil
+		},
+		ListFunc: func(ctx context.Context) ([]domain.Actividad, error) {
+			return []domain.Actividad{*actividad}, nil
+		},
+	}
+
+	salas := newMockSalasRepository()
+	salas.salas[1] = domain.Sala{ID: 1, SucursalID: 1, Nombre: "Sala Bici", Capacidad: 20, Activa: true}
+	salas.actividades = []domain.Actividad{
+		*actividad,
+		{ID: 8, Titulo: "Funcional", SalaID: &salaID, Dia: "Miercoles", HorarioInicio: "18:30", HorarioFinal: "19:30"},
+	}
+
+	test := &cancelacionesSesionTest{
+		sesiones:      newMockSesionesRepository(),
+		salas:         salas,
+		listaEspera:   newMockListaEsperaRepository(),
+		inscripciones: &[]domain.Inscripcion{},
+		eventos:       &[]eventoActividad{},
+	}
+	publisher := &MockEventPublisher{
+		PublishActivityEventFunc: func(action, activityID string, data map[string]interface{}) error {
+			*test.eventos = append(*test.eventos, eventoActividad{action: action, data: data})
+			return nil
+		},
+	}
+
+	test.service = NewSesionesService(test.sesiones, actividadesRepo, salas, newInscripcionesEnMemoria(20, test.inscripciones), test.listaEspera, nil, publisher, 14)
+	test.service.now = func() time.Time {
+		return time.Date(2025, 1, 6, 9, 0, 0, 0, gymLocation())
+	}
+
+	if _, err := test.service.GenerateAll(context.Background()); err != nil {
+		t.Fatalf("Expected no error generating sesiones, got %v", err)
+	}
+	return test
+}
+
+// sesionDel busca el ID de la sesión de la actividad 7 en una fecha
+func (c *cancelacionesSesionTest) sesionDel(t *testing.T, fecha string) uint {
+	for _, sesion := range c.sesiones.sesiones {
+		if sesion.ActividadID == 7 && sesion.Fecha == fecha {
+			return sesion.ID
+		}
+	}
+	t.Fatalf("Expected a sesion on %s", fecha)
+	return 0
+}
+
+// --- Tests ---
+
+func TestCancelarSesiones_LiberaReservas(t *testing.T) {
+	test := newCancelacionesSesionTest(t)
+	ctx := context.Background()
+
+	sesion7, sesion14 := test.sesionDel(t, "2025-01-07"), test.sesionDel(t, "2025-01-14")
+	*test.inscripciones = []domain.Inscripcion{
+		{ID: 1, UsuarioID: 1, ActividadID: 7, IsActiva: true},                      // Fija
+		{ID: 2, UsuarioID: 2, ActividadID: 7, SesionID: &sesion14, IsActiva: true}, // Reserva del 14
+		{ID: 3, UsuarioID: 3, ActividadID: 7, SesionID: &sesion7, IsActiva: true},  // Reserva del 7 (fuera del rango)
+	}
+	test.listaEspera.entradas[1] = domain.EntradaListaEspera{ID: 1, UsuarioID: 4, ActividadID: 7, SesionID: &sesion14, Estado: domain.ListaEsperaEsperando}
+	test.salas.reservas = []domain.ReservaEquipo{{ID: 1, EquipoID: 3, SesionID: sesion14, UsuarioID: 1}}
+
+	// Del 13 al 21: la sesión del 21 queda fuera del horizonte y se crea para cancelarla
+	resultado, err := test.service.CancelarSesiones(ctx, 7, domain.SesionesCancelacion{Desde: "2025-01-13", Hasta: "2025-01-21", Motivo: "Vacaciones del instructor"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(resultado.Sesiones) != 2 || resultado.Sesiones[0].Fecha != "2025-01-14" || resultado.Sesiones[1].Fecha != "2025-01-21" {
+		t.Fatalf("Expected sesiones of the 14th and 21st cancelled, got %+v", resultado.Sesiones)
+	}
+	if resultado.ReservasLiberadas != 1 || resultado.UsuariosNotificados != 3 {
+		t.Errorf("Expected 1 reservation released and 3 users notified, got %+v", resultado)
+	}
+
+	if (*test.inscripciones)[1].IsActiva {
+		t.Errorf("Expected the reservation of the 14th released")
+	}
+	if !(*test.inscripciones)[0].IsActiva || !(*test.inscripciones)[2].IsActiva {
+		t.Errorf("Expected the fixed inscription and the reservation of the 7th kept")
+	}
+	if test.listaEspera.entradas[1].Estado != domain.ListaEsperaCancelada {
+		t.Errorf("Expected the waitlist of the 14th cancelled, got %s", test.listaEspera.entradas[1].Estado)
+	}
+	if len(test.salas.reservas) != 0 {
+		t.Errorf("Expected the equipment reservation released, got %+v", test.salas.reservas)
+	}
+
+	if len(*test.eventos) != 2 || (*test.eventos)[0].action != "session_cancelled" {
+		t.Fatalf("Expected two session_cancelled events, got %+v", *test.eventos)
+	}
+	evento := (*test.eventos)[0].data
+	usuarios, _ := evento["usuarios"].([]uint)
+	reservas, _ := evento["reservas_liberadas"].([]uint)
+	if len(usuarios) != 2 || len(reservas) != 1 || reservas[0] != 2 || evento["motivo"] != "Vacaciones del instructor" {
+		t.Errorf("Expected users [1 2] and released [2] in the event, got %v", evento)
+	}
+
+	// La generación no vuelve a programar las sesiones canceladas
+	if _, err := test.service.GenerateAll(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sesion := test.sesiones.sesiones[sesion14]; !sesion.Cancelada() {
+		t.Errorf("Expected sesion of the 14th still cancelled")
+	}
+
+	// Cancelar de nuevo el mismo rango no hace nada
+	resultado, err = test.service.CancelarSesiones(ctx, 7, domain.SesionesCancelacion{Desde: "2025-01-14", Motivo: "Otra vez"})
+	if err != nil || len(resultado.Sesiones) != 0 {
+		t.Errorf("Expected nothing to cancel, got %+v (%v)", resultado, err)
+	}
+}
+
+func TestUpdateSesion_CancelarPublicaEvento(t *testing.T) {
+	test := newCancelacionesSesionTest(t)
+	sesion7 := test.sesionDel(t, "2025-01-07")
+	*test.inscripciones = []domain.Inscripcion{{ID: 1, UsuarioID: 5, ActividadID: 7, SesionID: &sesion7, IsActiva: true}}
+
+	cancelada := true
+	if _, err := test.service.Update(context.Background(), sesion7, domain.SesionUpdate{Cancelada: &cancelada, MotivoCancelacion: "Corte de luz"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if (*test.inscripciones)[0].IsActiva {
+		t.Errorf("Expected the reservation released")
+	}
+	if len(*test.eventos) != 1 || (*test.eventos)[0].action != "session_cancelled" || (*test.eventos)[0].data["motivo"] != "Corte de luz" {
+		t.Errorf("Expected a session_cancelled event, got %+v", *test.eventos)
+	}
+}
+
+func TestReprogramarSesion(t *testing.T) {
+	test := newCancelacionesSesionTest(t)
+	ctx := context.Background()
+	sesion14 := test.sesionDel(t, "2025-01-14")
+	*test.inscripciones = []domain.Inscripcion{
+		{ID: 1, UsuarioID: 1, ActividadID: 7, IsActiva: true},
+		{ID: 2, UsuarioID: 2, ActividadID: 7, SesionID: &sesion14, IsActiva: true},
+	}
+
+	tests := []struct {
+		name  string
+		input domain.SesionReprogramacion
+		want  error
+	}{
+		{"another tuesday of the activity", domain.SesionReprogramacion{Fecha: "2025-01-21", HorarioInicio: "19:00", HorarioFinal: "20:00"}, domain.ErrSesionDuplicada},
+		{"already past", domain.SesionReprogramacion{Fecha: "2025-01-06", HorarioInicio: "08:00", HorarioFinal: "09:00"}, ErrSesionPasada},
+		{"room taken by Funcional", domain.SesionReprogramacion{Fecha: "2025-01-15", HorarioInicio: "18:00", HorarioFinal: "19:00"}, ErrSalaOcupada},
+		{"invalid time", domain.SesionReprogramacion{Fecha: "2025-01-15", HorarioInicio: "25:00", HorarioFinal: "26:00"}, ErrHorarioSesion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := test.service.Reprogramar(ctx, sesion14, tt.input); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	// El miércoles 15 a las 19:30, cuando termina Funcional
+	reprogramada, err := test.service.Reprogramar(ctx, sesion14, domain.SesionReprogramacion{Fecha: "2025-01-15", HorarioInicio: "19:30", HorarioFinal: "20:30", Motivo: "Feriado"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if reprogramada.Fecha != "2025-01-15" || reprogramada.HorarioInicio != "19:30" || reprogramada.FechaOriginal != "2025-01-14" {
+		t.Errorf("Expected the sesion moved to 2025-01-15 19:30 from the 14th, got %+v", reprogramada)
+	}
+
+	if len(*test.eventos) != 1 || (*test.eventos)[0].action != "session_rescheduled" {
+		t.Fatalf("Expected a session_rescheduled event, got %+v", *test.eventos)
+	}
+	evento := (*test.eventos)[0].data
+	if usuarios, _ := evento["usuarios"].([]uint); len(usuarios) != 2 || evento["fecha_anterior"] != "2025-01-14" {
+		t.Errorf("Expected both inscriptos and the previous date in the event, got %v", evento)
+	}
+
+	// La generación no vuelve a crear la sesión del martes 14
+	if _, err := test.service.GenerateAll(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, fecha := range test.sesiones.fechas(7) {
+		if fecha == "2025-01-14" {
+			t.Errorf("Expected no new sesion on the 14th, got %v", test.sesiones.fechas(7))
+		}
+	}
+
+	// Volver a moverla conserva la fecha original y puede volver a su día
+	reprogramada, err = test.service.Reprogramar(ctx, sesion14, domain.SesionReprogramacion{Fecha: "2025-01-14", HorarioInicio: "20:00", HorarioFinal: "21:00"})
+	if err != nil || reprogramada.FechaOriginal != "2025-01-14" {
+		t.Errorf("Expected the sesion back on the 14th at 20:00, got %+v (%v)", reprogramada, err)
+	}
+}
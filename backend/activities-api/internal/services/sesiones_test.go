@@ -0,0 +1,336 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+)
+
+// --- Manual Mocks ---
+
+// MockSesionesRepository guarda las sesiones en memoria
+// reservadas marca las sesiones con reservas activas (DeleteIfUnbooked no las borra)
+type MockSesionesRepository struct {
+	sesiones   map[uint]domain.Sesion
+	reservadas map[uint]bool
+	nextID     uint
+}
+
+func newMockSesionesRepository() *MockSesionesRepository {
+	return &MockSesionesRepository{
+		sesiones:   make(map[uint]domain.Sesion),
+		reservadas: make(map[uint]bool),
+	}
+}
+
+func (m *MockSesionesRepository) ListByActividad(ctx context.Context, actividadID uint, desde, hasta time.Time) ([]domain.Sesion, error) {
+	var result []domain.Sesion
+	for _, sesion := range m.sesiones {
+		if sesion.ActividadID == actividadID && sesion.Fecha >= desde.Format("2006-01-02") && sesion.Fecha <= hasta.Format("2006-01-02") {
+			result = append(result, m.withLugares(sesion))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Fecha < result[j].Fecha })
+	return result, nil
+}
+func (m *MockSesionesRepository) GetByID(ctx context.Context, id uint) (domain.Sesion, error) {
+	sesion, ok := m.sesiones[id]
+	if !ok {
+		return domain.Sesion{}, errors.New("sesion not found")
+	}
+	return m.withLugares(sesion), nil
+}
+func (m *MockSesionesRepository) Create(ctx context.Context, sesion domain.Sesion, fecha, horaInicio, horaFin time.Time) error {
+	for _, existing := range m.sesiones {
+		if existing.ActividadID == sesion.ActividadID && existing.Fecha == sesion.Fecha {
+			return nil
+		}
+	}
+	m.nextID++
+	sesion.ID = m.nextID
+	m.sesiones[sesion.ID] = sesion
+	return nil
+}
+func (m *MockSesionesRepository) Update(ctx context.Context, id uint, sesion domain.Sesion, horaInicio, horaFin time.Time) (domain.Sesion, error) {
+	if _, ok := m.sesiones[id]; !ok {
+		return domain.Sesion{}, errors.New("sesion not found")
+	}
+	m.sesiones[id] = sesion
+	return m.withLugares(sesion), nil
+}
+func (m *MockSesionesRepository) DeleteIfUnbooked(ctx context.Context, id uint) (bool, error) {
+	if m.reservadas[id] {
+		return false, nil
+	}
+	delete(m.sesiones, id)
+	return true, nil
+}
+
+func (m *MockSesionesRepository) Reprogramar(ctx context.Context, id uint, fechaOriginal, fecha, horaInicio, horaFin time.Time) (domain.Sesion, error) {
+	sesion, ok := m.sesiones[id]
+	if !ok {
+		return domain.Sesion{}, errors.New("sesion not found")
+	}
+	for _, existing := range m.sesiones {
+		if existing.ID != id && existing.ActividadID == sesion.ActividadID && existing.Fecha == fecha.Format("2006-01-02") {
+			return domain.Sesion{}, domain.ErrSesionDuplicada
+		}
+	}
+	sesion.FechaOriginal = fechaOriginal.Format("2006-01-02")
+	sesion.Fecha = fecha.Format("2006-01-02")
+	sesion.HorarioInicio = horaInicio.Format("15:04")
+	sesion.HorarioFinal = horaFin.Format("15:04")
+	sesion.Personalizada = true
+	m.sesiones[id] = sesion
+	return m.withLugares(sesion), nil
+}
+func (m *MockSesionesRepository) ListReprogramadas(ctx context.Context, actividadID uint, desde, hasta time.Time) ([]domain.Sesion, error) {
+	var result []domain.Sesion
+	for _, sesion := range m.sesiones {
+		if sesion.ActividadID == actividadID && sesion.Reprogramada() && sesion.FechaOriginal >= desde.Format("2006-01-02") && sesion.FechaOriginal <= hasta.Format("2006-01-02") {
+			result = append(result, m.withLugares(sesion))
+		}
+	}
+	return result, nil
+}
+
+// withLugares calcula los lugares como la vista sesiones_lugares
+func (m *MockSesionesRepository) withLugares(sesion domain.Sesion) domain.Sesion {
+	sesion.Lugares = 0
+	if !sesion.Cancelada() && sesion.Cupo > sesion.Ocupados {
+		sesion.Lugares = sesion.Cupo - sesion.Ocupados
+	}
+	return sesion
+}
+
+// fechas devuelve las fechas de las sesiones de una actividad, ordenadas
+func (m *MockSesionesRepository) fechas(actividadID uint) []string {
+	var result []string
+	for _, sesion := range m.sesiones {
+		if sesion.ActividadID == actividadID {
+			result = append(result, sesion.Fecha)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// newSesionesTestService arma el servicio con una actividad en memoria y "ahora" fijo
+// El lunes 6/1/2025 a las 9:00 con un horizonte de 14 días (hasta el domingo 19)
+func newSesionesTestService(actividad *domain.Actividad) (*SesionesServiceImpl, *MockSesionesRepository) {
+	sesionesRepo := newMockSesionesRepository()
+	actividadesRepo := &MockActividadesRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (domain.Actividad, error) {
+			if id != actividad.ID {
+				return domain.Actividad{}, errors.New("actividad not found")
+			}
+			return *actividad, nil
+		},
+		ListFunc: func(ctx context.Context) ([]domain.Actividad, error) {
+			return []domain.Actividad{*actividad}, nil
+		},
+	}
+
+	service := NewSesionesService(sesionesRepo, actividadesRepo, newMockSalasRepository(), newInscripcionesEnMemoria(20, &[]domain.Inscripcion{}), newMockListaEsperaRepository(), nil, &MockEventPublisher{}, 14)
+	service.now = func() time.Time {
+		return time.Date(2025, 1, 6, 9, 0, 0, 0, gymLocation())
+	}
+	return service, sesionesRepo
+}
+
+func spinningMartes() *domain.Actividad {
+	return &domain.Actividad{
+		ID:            7,
+		Titulo:        "Spinning",
+		Cupo:          20,
+		Dia:           "Martes",
+		HorarioInicio: "19:00",
+		HorarioFinal:  "20:00",
+		Instructor:    "Ana",
+	}
+}
+
+// --- Tests ---
+
+func TestOcurrencias(t *testing.T) {
+	loc := gymLocation()
+	desde := time.Date(2025, 1, 6, 0, 0, 0, 0, loc) // Lunes
+	hasta := time.Date(2025, 1, 31, 0, 0, 0, 0, loc)
+
+	fechas, err := ocurrencias("Viernes", desde, hasta)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{"2025-01-10", "2025-01-17", "2025-01-24", "2025-01-31"}
+	if len(fechas) != len(expected) {
+		t.Fatalf("Expected %d fechas, got %v", len(expected), fechas)
+	}
+	for i, fecha := range fechas {
+		if fecha.Format("2006-01-02") != expected[i] {
+			t.Errorf("Expected %s, got %s", expected[i], fecha.Format("2006-01-02"))
+		}
+	}
+
+	if _, err := ocurrencias("Feriado", desde, hasta); !errors.Is(err, ErrDiaInvalido) {
+		t.Errorf("Expected ErrDiaInvalido, got %v", err)
+	}
+}
+
+func TestListSesiones_GeneratesWithinHorizon(t *testing.T) {
+	service, repo := newSesionesTestService(spinningMartes())
+
+	sesiones, err := service.ListByActividad(context.Background(), 7, "", "2025-01-31")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Solo se generan los martes del horizonte (7 y 14); el 21 y el 28 todavía no
+	if len(sesiones) != 2 || sesiones[0].Fecha != "2025-01-07" || sesiones[1].Fecha != "2025-01-14" {
+		t.Fatalf("Expected sessions on 7th and 14th, got %+v", sesiones)
+	}
+	if sesiones[0].Lugares != 20 || sesiones[0].HorarioInicio != "19:00" || sesiones[0].Instructor != "Ana" {
+		t.Errorf("Expected session copied from the activity, got %+v", sesiones[0])
+	}
+
+	// Listar de nuevo no duplica
+	if _, err := service.ListByActividad(context.Background(), 7, "", ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(repo.sesiones) != 2 {
+		t.Errorf("Expected 2 sessions stored, got %d", len(repo.sesiones))
+	}
+}
+
+func TestListSesiones_InvalidRange(t *testing.T) {
+	service, _ := newSesionesTestService(spinningMartes())
+
+	cases := []struct {
+		desde, hasta string
+		expected     error
+	}{
+		{"14/01/2025", "", ErrFechaInvalida},
+		{"2025-01-14", "2025-01-07", ErrRangoSesiones},
+		{"2025-01-01", "2025-06-01", ErrRangoSesiones},
+	}
+
+	for _, tc := range cases {
+		if _, err := service.ListByActividad(context.Background(), 7, tc.desde, tc.hasta); !errors.Is(err, tc.expected) {
+			t.Errorf("desde=%s hasta=%s: expected %v, got %v", tc.desde, tc.hasta, tc.expected, err)
+		}
+	}
+}
+
+func TestGenerateAll_FollowsActivityChanges(t *testing.T) {
+	actividad := spinningMartes()
+	service, repo := newSesionesTestService(actividad)
+
+	if creadas, err := service.GenerateAll(context.Background()); err != nil || creadas != 2 {
+		t.Fatalf("Expected 2 sessions created, got %d (%v)", creadas, err)
+	}
+
+	// El 14 se personaliza (reemplazo de instructor) y el 7 tiene reservas
+	var martes7, martes14 uint
+	for id, sesion := range repo.sesiones {
+		if sesion.Fecha == "2025-01-07" {
+			martes7 = id
+		} else {
+			martes14 = id
+		}
+	}
+	reemplazo := "Beto"
+	if _, err := service.Update(context.Background(), martes14, domain.SesionUpdate{Instructor: &reemplazo}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	repo.reservadas[martes7] = true
+
+	// Cambia el cupo: se actualizan las generadas, no las personalizadas
+	actividad.Cupo = 25
+	if _, err := service.GenerateAll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if repo.sesiones[martes7].Cupo != 25 {
+		t.Errorf("Expected generated session updated to 25, got %d", repo.sesiones[martes7].Cupo)
+	}
+	if repo.sesiones[martes14].Cupo != 20 || repo.sesiones[martes14].Instructor != "Beto" {
+		t.Errorf("Expected customized session untouched, got %+v", repo.sesiones[martes14])
+	}
+
+	// Cambia el día: se crean los jueves y se conservan la reservada y la personalizada
+	actividad.Dia = "Jueves"
+	if _, err := service.GenerateAll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []string{"2025-01-07", "2025-01-09", "2025-01-14", "2025-01-16"}
+	fechas := repo.fechas(7)
+	if len(fechas) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, fechas)
+	}
+	for i := range expected {
+		if fechas[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, fechas)
+			break
+		}
+	}
+
+	// Sin reservas, el martes se borra en la próxima generación
+	repo.reservadas[martes7] = false
+	if _, err := service.GenerateAll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := repo.sesiones[martes7]; ok {
+		t.Error("Expected unbooked session on the old day to be deleted")
+	}
+}
+
+func TestUpdateSesion(t *testing.T) {
+	service, repo := newSesionesTestService(spinningMartes())
+	if _, err := service.GenerateAll(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	sesion := repo.sesiones[1]
+	sesion.Ocupados = 12
+	repo.sesiones[1] = sesion
+
+	cupo := uint(10)
+	if _, err := service.Update(context.Background(), 1, domain.SesionUpdate{Cupo: &cupo}); err == nil {
+		t.Error("Expected error when reducing cupo below the occupied seats")
+	}
+
+	cancelada := true
+	updated, err := service.Update(context.Background(), 1, domain.SesionUpdate{Cancelada: &cancelada, MotivoCancelacion: "Feriado"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated.Estado != domain.SesionCancelada || updated.MotivoCancelacion != "Feriado" || updated.Lugares != 0 {
+		t.Errorf("Expected cancelled session without seats, got %+v", updated)
+	}
+	if !repo.sesiones[1].Personalizada {
+		t.Error("Expected session marked as customized")
+	}
+}
+
+func TestValidateSesionReservable(t *testing.T) {
+	sesion := domain.Sesion{ActividadID: 7, Fecha: "2025-01-07", HorarioInicio: "19:00", Estado: domain.SesionProgramada}
+	antes := time.Date(2025, 1, 7, 18, 0, 0, 0, gymLocation())
+	despues := time.Date(2025, 1, 7, 19, 5, 0, 0, gymLocation())
+
+	if err := validateSesionReservable(sesion, 7, antes); err != nil {
+		t.Errorf("Expected bookable session, got %v", err)
+	}
+	if err := validateSesionReservable(sesion, 8, antes); !errors.Is(err, ErrSesionOtraClase) {
+		t.Errorf("Expected ErrSesionOtraClase, got %v", err)
+	}
+	if err := validateSesionReservable(sesion, 7, despues); !errors.Is(err, ErrSesionIniciada) {
+		t.Errorf("Expected ErrSesionIniciada, got %v", err)
+	}
+
+	sesion.Estado = domain.SesionCancelada
+	if err := validateSesionReservable(sesion, 7, antes); !errors.Is(err, ErrSesionCancelada) {
+		t.Errorf("Expected ErrSesionCancelada, got %v", err)
+	}
+}
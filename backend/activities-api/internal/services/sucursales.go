@@ -0,0 +1,221 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Errores de sucursales
+var (
+	ErrSucursalInvalida       = errors.New("datos de sucursal inválidos")
+	ErrSucursalConActividades = errors.New("la sucursal tiene actividades activas: reasignalas o eliminalas antes de darla de baja")
+	ErrSucursalInexistente    = errors.New("la sucursal no existe o está dada de baja")
+)
+
+// SucursalesService define la interfaz del servicio de sucursales
+type SucursalesService interface {
+	List(ctx context.Context) ([]domain.Sucursal, error)
+	GetByID(ctx context.Context, id uint) (domain.Sucursal, error)
+	Create(ctx context.Context, sucursalCreate domain.SucursalCreate) (domain.Sucursal, error)
+	Update(ctx context.Context, id uint, sucursalUpdate domain.SucursalUpdate) (domain.Sucursal, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// SucursalesServiceImpl implementa SucursalesService
+type SucursalesServiceImpl struct {
+	repository     repository.SucursalesRepository
+	eventPublisher EventPublisher
+}
+
+// NewSucursalesService crea una nueva instancia del servicio
+func NewSucursalesService(repo repository.SucursalesRepository, eventPublisher EventPublisher) *SucursalesServiceImpl {
+	return &SucursalesServiceImpl{
+		repository:     repo,
+		eventPublisher: eventPublisher,
+	}
+}
+
+// List obtiene las sucursales activas
+func (s *SucursalesServiceImpl) List(ctx context.Context) ([]domain.Sucursal, error) {
+	sucursales, err := s.repository.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing sucursales: %w", err)
+	}
+
+	return sucursales, nil
+}
+
+// GetByID obtiene una sucursal activa por ID
+func (s *SucursalesServiceImpl) GetByID(ctx context.Context, id uint) (domain.Sucursal, error) {
+	sucursal, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return domain.Sucursal{}, fmt.Errorf("sucursal con ID %d: %w", id, err)
+	}
+
+	return sucursal, nil
+}
+
+// Create valida y crea una sucursal
+func (s *SucursalesServiceImpl) Create(ctx context.Context, sucursalCreate domain.SucursalCreate) (domain.Sucursal, error) {
+	sucursal, err := buildSucursal(domain.SucursalUpdate(sucursalCreate))
+	if err != nil {
+		return domain.Sucursal{}, err
+	}
+
+	created, err := s.repository.Create(ctx, sucursal)
+	if err != nil {
+		return domain.Sucursal{}, fmt.Errorf("error creating sucursal: %w", err)
+	}
+
+	s.publish("create", created)
+
+	return created, nil
+}
+
+// Update valida y reemplaza los datos y el horario semanal de una sucursal
+// search-api reindexa las actividades de la sucursal al recibir branch.update (sucursal_nombre)
+func (s *SucursalesServiceImpl) Update(ctx context.Context, id uint, sucursalUpdate domain.SucursalUpdate) (domain.Sucursal, error) {
+	sucursal, err := buildSucursal(sucursalUpdate)
+	if err != nil {
+		return domain.Sucursal{}, err
+	}
+
+	updated, err := s.repository.Update(ctx, id, sucursal)
+	if err != nil {
+		return domain.Sucursal{}, fmt.Errorf("error updating sucursal: %w", err)
+	}
+
+	s.publish("update", updated)
+
+	return updated, nil
+}
+
+// Delete da de baja una sucursal sin actividades activas
+func (s *SucursalesServiceImpl) Delete(ctx context.Context, id uint) error {
+	if _, err := s.repository.GetByID(ctx, id); err != nil {
+		return fmt.Errorf("error deleting sucursal: %w", err)
+	}
+
+	actividades, err := s.repository.CountActividadesActivas(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error deleting sucursal: %w", err)
+	}
+	if actividades > 0 {
+		return fmt.Errorf("%w (%d)", ErrSucursalConActividades, actividades)
+	}
+
+	if err := s.repository.Deactivate(ctx, id); err != nil {
+		return fmt.Errorf("error deleting sucursal: %w", err)
+	}
+
+	eventData := map[string]interface{}{
+		"deleted_at": time.Now(),
+	}
+	if err := s.eventPublisher.PublishBranchEvent("delete", fmt.Sprintf("%d", id), eventData); err != nil {
+		// Log el error pero NO fallamos la baja (ya está dada de baja)
+		fmt.Printf("⚠️  Error publicando evento branch.delete: %v\n", err)
+	}
+
+	return nil
+}
+
+// publish publica branch.<action> con los datos que muestran los listados de actividades
+func (s *SucursalesServiceImpl) publish(action string, sucursal domain.Sucursal) {
+	eventData := map[string]interface{}{
+		"nombre":       sucursal.Nombre,
+		"direccion":    sucursal.Direccion,
+		"ciudad":       sucursal.Ciudad,
+		"zona_horaria": sucursal.ZonaHoraria,
+		"latitud":      sucursal.Latitud,
+		"longitud":     sucursal.Longitud,
+	}
+	if err := s.eventPublisher.PublishBranchEvent(action, fmt.Sprintf("%d", sucursal.ID), eventData); err != nil {
+		// Log el error pero NO fallamos la operación (ya está guardada)
+		fmt.Printf("⚠️  Error publicando evento branch.%s: %v\n", action, err)
+	}
+}
+
+// buildSucursal valida los datos de alta/modificación y arma el dominio
+func buildSucursal(datos domain.SucursalUpdate) (domain.Sucursal, error) {
+	sucursal := domain.Sucursal{
+		Nombre:       strings.TrimSpace(datos.Nombre),
+		Direccion:    strings.TrimSpace(datos.Direccion),
+		Ciudad:       strings.TrimSpace(datos.Ciudad),
+		CodigoPostal: strings.TrimSpace(datos.CodigoPostal),
+		Telefono:     strings.TrimSpace(datos.Telefono),
+		ZonaHoraria:  strings.TrimSpace(datos.ZonaHoraria),
+		Latitud:      datos.Latitud,
+		Longitud:     datos.Longitud,
+	}
+
+	if sucursal.Nombre == "" {
+		return domain.Sucursal{}, fmt.Errorf("%w: el nombre no puede estar vacío", ErrSucursalInvalida)
+	}
+	if sucursal.Direccion == "" {
+		return domain.Sucursal{}, fmt.Errorf("%w: la dirección no puede estar vacía", ErrSucursalInvalida)
+	}
+
+	if sucursal.ZonaHoraria == "" {
+		sucursal.ZonaHoraria = domain.ZonaHorariaDefault
+	}
+	if _, err := time.LoadLocation(sucursal.ZonaHoraria); err != nil {
+		return domain.Sucursal{}, fmt.Errorf("%w: zona horaria desconocida %q (usar el nombre IANA, ej. America/Argentina/Cordoba)", ErrSucursalInvalida, sucursal.ZonaHoraria)
+	}
+
+	if (sucursal.Latitud == nil) != (sucursal.Longitud == nil) {
+		return domain.Sucursal{}, fmt.Errorf("%w: latitud y longitud van juntas", ErrSucursalInvalida)
+	}
+	if sucursal.Latitud != nil && (*sucursal.Latitud < -90 || *sucursal.Latitud > 90) {
+		return domain.Sucursal{}, fmt.Errorf("%w: la latitud debe estar entre -90 y 90", ErrSucursalInvalida)
+	}
+	if sucursal.Longitud != nil && (*sucursal.Longitud < -180 || *sucursal.Longitud > 180) {
+		return domain.Sucursal{}, fmt.Errorf("%w: la longitud debe estar entre -180 y 180", ErrSucursalInvalida)
+	}
+
+	horarios, err := validarHorariosSucursal(datos.Horarios)
+	if err != nil {
+		return domain.Sucursal{}, err
+	}
+	sucursal.Horarios = horarios
+
+	return sucursal, nil
+}
+
+// validarHorariosSucursal valida el horario semanal: un rango por día, HH:MM y cierre posterior a la apertura
+func validarHorariosSucursal(horarios []domain.HorarioSucursal) ([]domain.HorarioSucursal, error) {
+	vistos := make(map[string]bool, len(horarios))
+	validos := make([]domain.HorarioSucursal, 0, len(horarios))
+
+	for _, h := range horarios {
+		dia := strings.TrimSpace(h.Dia)
+		if _, ok := diasSemana[dia]; !ok {
+			return nil, fmt.Errorf("%w: día %q inválido (Lunes, Martes, Miercoles, Jueves, Viernes, Sabado o Domingo)", ErrSucursalInvalida, h.Dia)
+		}
+		if vistos[dia] {
+			return nil, fmt.Errorf("%w: el día %s tiene más de un horario", ErrSucursalInvalida, dia)
+		}
+		vistos[dia] = true
+
+		apertura, errApertura := time.Parse("15:04", h.Apertura)
+		cierre, errCierre := time.Parse("15:04", h.Cierre)
+		if errApertura != nil || errCierre != nil {
+			return nil, fmt.Errorf("%w: horario del %s inválido (debe ser HH:MM)", ErrSucursalInvalida, dia)
+		}
+		if !cierre.After(apertura) {
+			return nil, fmt.Errorf("%w: el cierre del %s debe ser posterior a la apertura", ErrSucursalInvalida, dia)
+		}
+
+		validos = append(validos, domain.HorarioSucursal{
+			Dia:      dia,
+			Apertura: apertura.Format("15:04"),
+			Cierre:   cierre.Format("15:04"),
+		})
+	}
+
+	return validos, nil
+}
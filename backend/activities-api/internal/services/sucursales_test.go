@@ -0,0 +1,231 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// --- Manual Mocks ---
+
+// MockSucursalesRepository guarda las sucursales en memoria; las dadas de baja no se ven
+type MockSucursalesRepository struct {
+	sucursales  map[uint]domain.Sucursal
+	actividades map[uint]int64 // actividades activas por sucursal
+}
+
+func newMockSucursalesRepository() *MockSucursalesRepository {
+	return &MockSucursalesRepository{
+		sucursales:  make(map[uint]domain.Sucursal),
+		actividades: make(map[uint]int64),
+	}
+}
+
+func (m *MockSucursalesRepository) List(ctx context.Context) ([]domain.Sucursal, error) {
+	var result []domain.Sucursal
+	for _, sucursal := range m.sucursales {
+		if sucursal.Activa {
+			result = append(result, sucursal)
+		}
+	}
+	return result, nil
+}
+func (m *MockSucursalesRepository) GetByID(ctx context.Context, id uint) (domain.Sucursal, error) {
+	sucursal, ok := m.sucursales[id]
+	if !ok || !sucursal.Activa {
+		return domain.Sucursal{}, domain.ErrSucursalNotFound
+	}
+	return sucursal, nil
+}
+func (m *MockSucursalesRepository) Create(ctx context.Context, sucursal domain.Sucursal) (domain.Sucursal, error) {
+	sucursal.ID = uint(len(m.sucursales) + 1)
+	sucursal.Activa = true
+	m.sucursales[sucursal.ID] = sucursal
+	return sucursal, nil
+}
+func (m *MockSucursalesRepository) Update(ctx context.Context, id uint, sucursal domain.Sucursal) (domain.Sucursal, error) {
+	if _, err := m.GetByID(ctx, id); err != nil {
+		return domain.Sucursal{}, err
+	}
+	sucursal.ID = id
+	sucursal.Activa = true
+	m.sucursales[id] = sucursal
+	return sucursal, nil
+}
+func (m *MockSucursalesRepository) Deactivate(ctx context.Context, id uint) error {
+	sucursal, err := m.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	sucursal.Activa = false
+	m.sucursales[id] = sucursal
+	return nil
+}
+func (m *MockSucursalesRepository) CountActividadesActivas(ctx context.Context, id uint) (int64, error) {
+	return m.actividades[id], nil
+}
+
+// newSucursalesTestService arma el servicio y registra las acciones branch.* publicadas
+func newSucursalesTestService() (*SucursalesServiceImpl, *MockSucursalesRepository, *[]string) {
+	repo := newMockSucursalesRepository()
+	eventos := []string{}
+	publisher := &MockEventPublisher{
+		PublishBranchEventFunc: func(action, branchID string, data map[string]interface{}) error {
+			eventos = append(eventos, action+":"+branchID)
+			return nil
+		},
+	}
+	return NewSucursalesService(repo, publisher), repo, &eventos
+}
+
+func sucursalValida() domain.SucursalCreate {
+	lat, lng := -34.6037, -58.3816
+	return domain.SucursalCreate{
+		Nombre:    " Sucursal Centro ",
+		Direccion: "Av. Corrientes 1234",
+		Telefono:  "+54 11 4567-8900",
+		Latitud:   &lat,
+		Longitud:  &lng,
+		Horarios: []domain.HorarioSucursal{
+			{Dia: "Lunes", Apertura: "06:00", Cierre: "23:00"},
+			{Dia: "Sabado", Apertura: "08:00", Cierre: "14:00"},
+		},
+	}
+}
+
+// --- Tests ---
+
+func TestCreateSucursal_DefaultsAndEvent(t *testing.T) {
+	service, _, eventos := newSucursalesTestService()
+
+	sucursal, err := service.Create(context.Background(), sucursalValida())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sucursal.Nombre != "Sucursal Centro" {
+		t.Errorf("Expected trimmed nombre, got %q", sucursal.Nombre)
+	}
+	if sucursal.ZonaHoraria != domain.ZonaHorariaDefault {
+		t.Errorf("Expected default zona horaria, got %q", sucursal.ZonaHoraria)
+	}
+	if len(sucursal.Horarios) != 2 {
+		t.Errorf("Expected 2 horarios, got %d", len(sucursal.Horarios))
+	}
+	if len(*eventos) != 1 || (*eventos)[0] != "create:1" {
+		t.Errorf("Expected branch.create for sucursal 1, got %v", *eventos)
+	}
+}
+
+func TestCreateSucursal_ValidationError(t *testing.T) {
+	service, _, eventos := newSucursalesTestService()
+	lat := 10.0
+	lejos := 200.0
+
+	casos := map[string]func(*domain.SucursalCreate){
+		"zona horaria desconocida": func(s *domain.SucursalCreate) { s.ZonaHoraria = "America/Nowhere" },
+		"latitud sin longitud":     func(s *domain.SucursalCreate) { s.Latitud, s.Longitud = &lat, nil },
+		"longitud fuera de rango":  func(s *domain.SucursalCreate) { s.Longitud = &lejos },
+		"día inválido": func(s *domain.SucursalCreate) {
+			s.Horarios = []domain.HorarioSucursal{{Dia: "Feriado", Apertura: "08:00", Cierre: "12:00"}}
+		},
+		"día repetido": func(s *domain.SucursalCreate) {
+			s.Horarios = append(s.Horarios, domain.HorarioSucursal{Dia: "Lunes", Apertura: "08:00", Cierre: "12:00"})
+		},
+		"cierre antes de la apertura": func(s *domain.SucursalCreate) {
+			s.Horarios = []domain.HorarioSucursal{{Dia: "Martes", Apertura: "22:00", Cierre: "06:00"}}
+		},
+		"hora mal formada": func(s *domain.SucursalCreate) {
+			s.Horarios = []domain.HorarioSucursal{{Dia: "Martes", Apertura: "8am", Cierre: "12:00"}}
+		},
+	}
+
+	for nombre, modificar := range casos {
+		input := sucursalValida()
+		modificar(&input)
+		if _, err := service.Create(context.Background(), input); !errors.Is(err, ErrSucursalInvalida) {
+			t.Errorf("%s: expected ErrSucursalInvalida, got %v", nombre, err)
+		}
+	}
+	if len(*eventos) != 0 {
+		t.Errorf("Expected no events for invalid sucursales, got %v", *eventos)
+	}
+}
+
+func TestUpdateSucursal_PublishesForReindex(t *testing.T) {
+	service, _, eventos := newSucursalesTestService()
+	created, _ := service.Create(context.Background(), sucursalValida())
+
+	update := domain.SucursalUpdate(sucursalValida())
+	update.Nombre = "Sucursal Microcentro"
+	update.ZonaHoraria = "America/Argentina/Cordoba"
+	updated, err := service.Update(context.Background(), created.ID, update)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated.Nombre != "Sucursal Microcentro" || updated.ZonaHoraria != "America/Argentina/Cordoba" {
+		t.Errorf("Unexpected sucursal after update: %+v", updated)
+	}
+	if (*eventos)[len(*eventos)-1] != "update:1" {
+		t.Errorf("Expected branch.update for sucursal 1, got %v", *eventos)
+	}
+
+	if _, err := service.Update(context.Background(), 99, update); !errors.Is(err, domain.ErrSucursalNotFound) {
+		t.Errorf("Expected ErrSucursalNotFound, got %v", err)
+	}
+}
+
+func TestDeleteSucursal_RefusedWithActiveActividades(t *testing.T) {
+	service, repo, eventos := newSucursalesTestService()
+	created, _ := service.Create(context.Background(), sucursalValida())
+	repo.actividades[created.ID] = 3
+
+	if err := service.Delete(context.Background(), created.ID); !errors.Is(err, ErrSucursalConActividades) {
+		t.Fatalf("Expected ErrSucursalConActividades, got %v", err)
+	}
+
+	repo.actividades[created.ID] = 0
+	if err := service.Delete(context.Background(), created.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.GetByID(context.Background(), created.ID); !errors.Is(err, domain.ErrSucursalNotFound) {
+		t.Errorf("Expected deleted sucursal to be hidden, got %v", err)
+	}
+	if (*eventos)[len(*eventos)-1] != "delete:1" {
+		t.Errorf("Expected branch.delete for sucursal 1, got %v", *eventos)
+	}
+}
+
+func TestCreateActividad_ValidatesSucursal(t *testing.T) {
+	sucursales := newMockSucursalesRepository()
+	sucursales.sucursales[1] = domain.Sucursal{ID: 1, Nombre: "Centro", Activa: true}
+	sucursales.sucursales[2] = domain.Sucursal{ID: 2, Nombre: "Belgrano", Activa: false}
+
+	mockRepo := &MockActividadesRepository{
+		CreateFunc: func(ctx context.Context, actividad domain.Actividad, horaInicio, horaFin time.Time) (domain.Actividad, error) {
+			actividad.ID = 1
+			return actividad, nil
+		},
+	}
+	service := NewActividadesService(mockRepo, sucursales, newMockInstructoresRepository(), newMockSalasRepository(), &MockEventPublisher{})
+
+	input := domain.ActividadCreate{
+		Titulo: "Yoga", Cupo: 20, Dia: "Lunes", HorarioInicio: "10:00", HorarioFinal: "11:00",
+		Instructor: "Juan Perez", Categoria: "Relax",
+	}
+
+	for _, id := range []uint{2, 99} {
+		sucursalID := id
+		input.SucursalID = &sucursalID
+		if _, err := service.Create(context.Background(), input); !errors.Is(err, ErrSucursalInexistente) {
+			t.Errorf("sucursal %d: expected ErrSucursalInexistente, got %v", id, err)
+		}
+	}
+
+	activa := uint(1)
+	input.SucursalID = &activa
+	if _, err := service.Create(context.Background(), input); err != nil {
+		t.Errorf("Expected no error for active sucursal, got %v", err)
+	}
+}
@@ -0,0 +1,492 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"activities-api/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/gym-management/shared/httpclient"
+)
+
+var (
+	// ErrSinSuscripcionActiva indica que el usuario no tiene una suscripción vigente
+	ErrSinSuscripcionActiva = errors.New("debe tener un plan para inscribirse a esta actividad")
+	// ErrSincronizacionNoAutorizada indica que subscriptions-api rechazó el token de la sincronización
+	ErrSincronizacionNoAutorizada = errors.New("subscriptions-api rechazó el token: la sincronización requiere el permiso subscriptions:manage")
+)
+
+// planesPorPagina es el máximo que devuelve GET /plans de subscriptions-api
+const planesPorPagina = 100
+
+// Acciones de los eventos subscription.* que cambian el estado sin informarlo en data
+// (los eventos publicados antes de que llevaran la suscripción completa)
+var estadoPorAccion = map[string]string{
+	"activated":           domain.SuscripcionActiva,
+	"cancelled":           "cancelada",
+	"cancelled_by_refund": "cancelada",
+	"expired":             "expirada",
+}
+
+// SuscripcionesService define la interfaz de la copia local de suscripciones y planes de subscriptions-api
+type SuscripcionesService interface {
+	ActiveSubscription(ctx context.Context, usuarioID uint, authToken string) (Subscription, error)
+	AplicarEventoSuscripcion(ctx context.Context, accion, id string, ocurrido time.Time, data map[string]interface{}) error
+	AplicarEventoPlan(ctx context.Context, accion, id string, ocurrido time.Time, data map[string]interface{}) error
+	Sincronizar(ctx context.Context, authToken string) (domain.ResultadoSincronizacion, error)
+	SincronizarPlanes(ctx context.Context) (int, error)
+}
+
+// SuscripcionesServiceImpl implementa SuscripcionesService
+// Las inscripciones y el check-in validan contra la copia local (eventos subscription.* y plan.* de gym_events);
+// a subscriptions-api solo se le pregunta por los usuarios que la copia todavía no conoce (si fallbackHTTP)
+type SuscripcionesServiceImpl struct {
+	repo         repository.SuscripcionesRepository
+	api          *httpclient.Client // subscriptions-api
+	fallbackHTTP bool
+	now          func() time.Time
+}
+
+// NewSuscripcionesService crea una nueva instancia del servicio
+func NewSuscripcionesService(repo repository.SuscripcionesRepository, api *httpclient.Client, fallbackHTTP bool) *SuscripcionesServiceImpl {
+	return &SuscripcionesServiceImpl{
+		repo:         repo,
+		api:          api,
+		fallbackHTTP: fallbackHTTP,
+		now:          time.Now,
+	}
+}
+
+// Subscription representa una suscripción activa del usuario
+type Subscription struct {
+	ID               string     `json:"id"`
+	UserID           string     `json:"usuario_id"` // Cambiado de uint a string para coincidir con la respuesta de subscriptions-api
+	PlanID           string     `json:"plan_id"`
+	Status           string     `json:"estado"`
+	FechaInicio      *time.Time `json:"fecha_inicio,omitempty"`
+	FechaVencimiento *time.Time `json:"fecha_vencimiento,omitempty"`
+	PlanInfo         Plan       `json:"plan_info,omitempty"` // Info del plan expandida
+}
+
+// Plan representa la información del plan de suscripción
+type Plan struct {
+	ID                    string   `json:"id"`
+	Nombre                string   `json:"nombre"`
+	TipoAcceso            string   `json:"tipo_acceso"` // "limitado" | "completo"
+	ActividadesPermitidas []string `json:"actividades_permitidas"`
+	ActividadesPorSemana  int      `json:"actividades_por_semana"` // Límite de actividades por semana (0 = ilimitado)
+	CupoClases            int      `json:"cupo_clases"`            // Clases por período (0 = usa ActividadesPorSemana)
+	CupoPeriodo           string   `json:"cupo_periodo"`           // diario | semanal | mensual (vacío = semanal)
+	Activo                bool     `json:"activo"`
+}
+
+// Cupo devuelve el período y la cantidad de clases que permite el plan (limite 0 = sin límite)
+// Los planes anteriores al cupo usan actividades_por_semana, que no limitaba a los de acceso completo
+func (p Plan) Cupo() (periodo string, limite int) {
+	if p.CupoClases > 0 {
+		switch p.CupoPeriodo {
+		case domain.CupoDiario, domain.CupoMensual:
+			return p.CupoPeriodo, p.CupoClases
+		default:
+			return domain.CupoSemanal, p.CupoClases
+		}
+	}
+	if p.ActividadesPorSemana > 0 && p.TipoAcceso != "completo" {
+		return domain.CupoSemanal, p.ActividadesPorSemana
+	}
+	return domain.CupoSemanal, 0
+}
+
+// ActiveSubscription devuelve la suscripción vigente del usuario con la info del plan
+// La usan las inscripciones, la confirmación de la lista de espera y el check-in de sala libre
+func (s *SuscripcionesServiceImpl) ActiveSubscription(ctx context.Context, usuarioID uint, authToken string) (Subscription, error) {
+	suscripciones, err := s.repo.ListByUsuario(ctx, usuarioID)
+	if err != nil {
+		if !s.fallbackHTTP {
+			return Subscription{}, err
+		}
+		fmt.Printf("⚠️  [ActiveSubscription] Error leyendo la copia local, consultando subscriptions-api: %v\n", err)
+		return s.getActiveSubscription(ctx, usuarioID, authToken)
+	}
+
+	// Usuario que la copia local no conoce (ej. se suscribió antes de la primera sincronización)
+	if len(suscripciones) == 0 {
+		if !s.fallbackHTTP {
+			return Subscription{}, ErrSinSuscripcionActiva
+		}
+		subscription, err := s.getActiveSubscription(ctx, usuarioID, authToken)
+		if err != nil {
+			return Subscription{}, err
+		}
+		s.recordar(ctx, usuarioID, subscription)
+		return subscription, nil
+	}
+
+	now := s.now()
+	for _, suscripcion := range suscripciones {
+		if suscripcion.Vigente(now) {
+			return s.conPlan(ctx, suscripcion, authToken), nil
+		}
+	}
+
+	return Subscription{}, ErrSinSuscripcionActiva
+}
+
+// conPlan arma la Subscription con las condiciones del plan de la copia local
+// Sin el plan la inscripción sigue sin restricciones, como cuando subscriptions-api no lo devolvía
+func (s *SuscripcionesServiceImpl) conPlan(ctx context.Context, suscripcion domain.Suscripcion, authToken string) Subscription {
+	subscription := Subscription{
+		ID:               suscripcion.ID,
+		UserID:           strconv.FormatUint(uint64(suscripcion.UsuarioID), 10),
+		PlanID:           suscripcion.PlanID,
+		Status:           suscripcion.Estado,
+		FechaInicio:      suscripcion.FechaInicio,
+		FechaVencimiento: suscripcion.FechaVencimiento,
+	}
+
+	plan, err := s.repo.GetPlan(ctx, suscripcion.PlanID)
+	switch {
+	case err == nil:
+		subscription.PlanInfo = planFromDomain(plan)
+	case errors.Is(err, domain.ErrPlanNotFound) && s.fallbackHTTP:
+		planInfo, err := s.getPlanInfo(ctx, suscripcion.PlanID, authToken)
+		if err != nil {
+			fmt.Printf("⚠️  [ActiveSubscription] No se pudo obtener info del plan %s: %v\n", suscripcion.PlanID, err)
+			break
+		}
+		subscription.PlanInfo = planInfo
+		if err := s.repo.SavePlan(ctx, planToDomain(planInfo, s.now())); err != nil {
+			fmt.Printf("⚠️  [ActiveSubscription] Error guardando el plan %s: %v\n", planInfo.ID, err)
+		}
+	default:
+		fmt.Printf("⚠️  [ActiveSubscription] Plan %s no disponible: %v\n", suscripcion.PlanID, err)
+	}
+
+	return subscription
+}
+
+// recordar guarda en la copia local la suscripción (y el plan) que devolvió subscriptions-api
+func (s *SuscripcionesServiceImpl) recordar(ctx context.Context, usuarioID uint, subscription Subscription) {
+	// Lo que se leyó ahora ya incluye los eventos anteriores que todavía no llegaron
+	leido := s.now()
+
+	if err := s.repo.SaveSuscripcion(ctx, suscripcionToDomain(subscription, usuarioID, leido)); err != nil {
+		fmt.Printf("⚠️  [ActiveSubscription] Error guardando la suscripción %s: %v\n", subscription.ID, err)
+	}
+	if subscription.PlanInfo.ID == "" {
+		return
+	}
+	if _, err := s.repo.GetPlan(ctx, subscription.PlanInfo.ID); errors.Is(err, domain.ErrPlanNotFound) {
+		if err := s.repo.SavePlan(ctx, planToDomain(subscription.PlanInfo, leido)); err != nil {
+			fmt.Printf("⚠️  [ActiveSubscription] Error guardando el plan %s: %v\n", subscription.PlanInfo.ID, err)
+		}
+	}
+}
+
+// AplicarEventoSuscripcion actualiza la copia local con un evento subscription.*
+// Los campos que el evento no trae se conservan; un evento más viejo que el último aplicado se ignora
+func (s *SuscripcionesServiceImpl) AplicarEventoSuscripcion(ctx context.Context, accion, id string, ocurrido time.Time, data map[string]interface{}) error {
+	suscripcion, err := s.repo.GetSuscripcion(ctx, id)
+	if err != nil && !errors.Is(err, domain.ErrSuscripcionNotFound) {
+		return err
+	}
+	if err == nil && ocurrido.Before(suscripcion.EventoEn) {
+		fmt.Printf("⏭️  [Suscripciones] Evento subscription.%s viejo para %s, ignorado\n", accion, id)
+		return nil
+	}
+	suscripcion.ID = id
+
+	if usuarioID, ok := usuarioIDEvento(data["usuario_id"]); ok {
+		suscripcion.UsuarioID = usuarioID
+	}
+	if planID, ok := data["plan_id"].(string); ok {
+		suscripcion.PlanID = planID
+	}
+	if estado, ok := data["estado"].(string); ok && estado != "" {
+		suscripcion.Estado = estado
+	} else if estado, ok := estadoPorAccion[accion]; ok {
+		suscripcion.Estado = estado
+	}
+	if fecha, ok := fechaEvento(data["fecha_inicio"]); ok {
+		suscripcion.FechaInicio = &fecha
+	}
+	if fecha, ok := fechaEvento(data["fecha_vencimiento"]); ok {
+		suscripcion.FechaVencimiento = &fecha
+	}
+
+	// Sin usuario no sirve para validar inscripciones: la completa la sincronización
+	if suscripcion.UsuarioID == 0 || suscripcion.Estado == "" {
+		fmt.Printf("⚠️  [Suscripciones] Evento subscription.%s de %s sin usuario_id/estado, ignorado\n", accion, id)
+		return nil
+	}
+
+	suscripcion.EventoEn = ocurrido
+	return s.repo.SaveSuscripcion(ctx, suscripcion)
+}
+
+// AplicarEventoPlan actualiza la copia local con un evento plan.*
+// Un plan eliminado queda inactivo (las suscripciones que lo usan conservan sus condiciones)
+func (s *SuscripcionesServiceImpl) AplicarEventoPlan(ctx context.Context, accion, id string, ocurrido time.Time, data map[string]interface{}) error {
+	plan, err := s.repo.GetPlan(ctx, id)
+	if err != nil && !errors.Is(err, domain.ErrPlanNotFound) {
+		return err
+	}
+	if err == nil && ocurrido.Before(plan.EventoEn) {
+		fmt.Printf("⏭️  [Suscripciones] Evento plan.%s viejo para %s, ignorado\n", accion, id)
+		return nil
+	}
+	plan.ID = id
+
+	if nombre, ok := data["nombre"].(string); ok {
+		plan.Nombre = nombre
+	}
+	if tipoAcceso, ok := data["tipo_acceso"].(string); ok {
+		plan.TipoAcceso = tipoAcceso
+	}
+	if activo, ok := data["activo"].(bool); ok {
+		plan.Activo = activo
+	}
+	if porSemana, ok := data["actividades_por_semana"].(float64); ok {
+		plan.ActividadesPorSemana = int(porSemana)
+	}
+	if cupo, ok := data["cupo_clases"].(float64); ok {
+		plan.CupoClases = int(cupo)
+	}
+	if periodo, ok := data["cupo_periodo"].(string); ok {
+		plan.CupoPeriodo = periodo
+	}
+	if permitidas, ok := data["actividades_permitidas"].([]interface{}); ok {
+		plan.ActividadesPermitidas = make([]string, 0, len(permitidas))
+		for _, categoria := range permitidas {
+			if c, ok := categoria.(string); ok {
+				plan.ActividadesPermitidas = append(plan.ActividadesPermitidas, c)
+			}
+		}
+	}
+	if accion == "deleted" {
+		plan.Activo = false
+	}
+
+	plan.EventoEn = ocurrido
+	return s.repo.SavePlan(ctx, plan)
+}
+
+// Sincronizar reconstruye la copia local desde subscriptions-api: todos los planes y las suscripciones activas
+// Las activas de la copia local que subscriptions-api ya no lista (un evento perdido) quedan inactivas
+// authToken debe tener el permiso subscriptions:manage
+func (s *SuscripcionesServiceImpl) Sincronizar(ctx context.Context, authToken string) (domain.ResultadoSincronizacion, error) {
+	// MySQL guarda microsegundos: truncar evita que lo guardado quede "antes" del inicio
+	inicio := s.now().Truncate(time.Microsecond)
+
+	planes, err := s.sincronizarPlanes(ctx, inicio)
+	if err != nil {
+		return domain.ResultadoSincronizacion{}, err
+	}
+
+	var subscriptions []Subscription
+	status, err := s.getJSON(ctx, "/subscriptions/active", authToken, &subscriptions)
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return domain.ResultadoSincronizacion{}, ErrSincronizacionNoAutorizada
+	}
+	if err != nil {
+		return domain.ResultadoSincronizacion{}, fmt.Errorf("error obteniendo suscripciones activas: %w", err)
+	}
+
+	vigentes := make([]string, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		usuarioID, err := strconv.ParseUint(subscription.UserID, 10, 32)
+		if err != nil {
+			fmt.Printf("⚠️  [Sincronizar] Suscripción %s con usuario_id inválido (%q), ignorada\n", subscription.ID, subscription.UserID)
+			continue
+		}
+		if err := s.repo.SaveSuscripcion(ctx, suscripcionToDomain(subscription, uint(usuarioID), inicio)); err != nil {
+			return domain.ResultadoSincronizacion{}, err
+		}
+		vigentes = append(vigentes, subscription.ID)
+	}
+
+	desactivadas, err := s.repo.DesactivarAusentes(ctx, vigentes, inicio)
+	if err != nil {
+		return domain.ResultadoSincronizacion{}, err
+	}
+
+	fmt.Printf("✅ [Sincronizar] Planes: %d, suscripciones activas: %d, desactivadas: %d\n", planes, len(vigentes), desactivadas)
+	return domain.ResultadoSincronizacion{
+		Planes:        planes,
+		Suscripciones: len(vigentes),
+		Desactivadas:  desactivadas,
+	}, nil
+}
+
+// SincronizarPlanes copia los planes de subscriptions-api (GET /plans es público, no necesita token)
+// Se llama al arrancar; las suscripciones se completan con los eventos, el fallback HTTP o Sincronizar
+func (s *SuscripcionesServiceImpl) SincronizarPlanes(ctx context.Context) (int, error) {
+	return s.sincronizarPlanes(ctx, s.now().Truncate(time.Microsecond))
+}
+
+func (s *SuscripcionesServiceImpl) sincronizarPlanes(ctx context.Context, inicio time.Time) (int, error) {
+	total := 0
+	for pagina := 1; ; pagina++ {
+		var respuesta struct {
+			Plans      []Plan `json:"plans"`
+			TotalPages int    `json:"total_pages"`
+		}
+		path := fmt.Sprintf("/plans?page=%d&page_size=%d", pagina, planesPorPagina)
+		if _, err := s.getJSON(ctx, path, "", &respuesta); err != nil {
+			return total, fmt.Errorf("error obteniendo planes: %w", err)
+		}
+
+		for _, plan := range respuesta.Plans {
+			if err := s.repo.SavePlan(ctx, planToDomain(plan, inicio)); err != nil {
+				return total, err
+			}
+			total++
+		}
+
+		if pagina >= respuesta.TotalPages {
+			return total, nil
+		}
+	}
+}
+
+// getActiveSubscription consulta a subscriptions-api la suscripción activa del usuario
+func (s *SuscripcionesServiceImpl) getActiveSubscription(ctx context.Context, userID uint, authToken string) (Subscription, error) {
+	if authToken == "" {
+		fmt.Printf("⚠️  [getActiveSubscription] NO se proporcionó token de autorización para usuario %d\n", userID)
+	}
+
+	var subscription Subscription
+	status, err := s.getJSON(ctx, fmt.Sprintf("/subscriptions/active/%d", userID), authToken, &subscription)
+	switch {
+	case status == http.StatusUnauthorized:
+		fmt.Printf("❌ [getActiveSubscription] Error 401 - No autorizado\n")
+		return Subscription{}, fmt.Errorf("no autorizado para consultar suscripción (falta token de autenticación)")
+	case status == http.StatusNotFound:
+		fmt.Printf("❌ [getActiveSubscription] Error 404 - Suscripción no encontrada\n")
+		return Subscription{}, ErrSinSuscripcionActiva
+	case err != nil:
+		fmt.Printf("❌ [getActiveSubscription] %v\n", err)
+		return Subscription{}, fmt.Errorf("error obteniendo suscripción activa: %w", err)
+	}
+
+	// Validar que la suscripción esté activa
+	if subscription.Status != domain.SuscripcionActiva {
+		fmt.Printf("❌ [getActiveSubscription] Suscripción no activa - Estado: %s\n", subscription.Status)
+		return Subscription{}, fmt.Errorf("la suscripción del usuario no está activa (estado: %s)", subscription.Status)
+	}
+
+	// Obtener información del plan
+	planInfo, err := s.getPlanInfo(ctx, subscription.PlanID, authToken)
+	if err != nil {
+		// Log el error pero no fallamos - el plan puede no estar disponible temporalmente
+		fmt.Printf("⚠️  [getActiveSubscription] No se pudo obtener info del plan %s: %v\n", subscription.PlanID, err)
+	} else {
+		subscription.PlanInfo = planInfo
+	}
+
+	fmt.Printf("✅ [getActiveSubscription] Suscripción obtenida de subscriptions-api para usuario %d (Estado: %s)\n", userID, subscription.Status)
+	return subscription, nil
+}
+
+// getPlanInfo obtiene la información del plan desde subscriptions-api
+func (s *SuscripcionesServiceImpl) getPlanInfo(ctx context.Context, planID string, authToken string) (Plan, error) {
+	var plan Plan
+	if _, err := s.getJSON(ctx, "/plans/"+planID, authToken, &plan); err != nil {
+		return Plan{}, err
+	}
+	return plan, nil
+}
+
+// getJSON hace un GET a subscriptions-api y decodifica la respuesta 200
+// Devuelve el status para que el llamador distinga 401/404 del resto de los errores
+// (los reintentos, el deadline y el circuit breaker los pone httpclient)
+func (s *SuscripcionesServiceImpl) getJSON(ctx context.Context, path, authToken string, destino interface{}) (int, error) {
+	header := http.Header{}
+	if authToken != "" {
+		header.Set("Authorization", authToken)
+	}
+
+	resp, err := s.api.Get(ctx, path, header)
+	if err != nil {
+		return 0, fmt.Errorf("error llamando a subscriptions-api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return resp.StatusCode, fmt.Errorf("subscriptions-api respondió %d en %s: %s", resp.StatusCode, path, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(destino); err != nil {
+		return resp.StatusCode, fmt.Errorf("error decodificando respuesta de %s: %w", path, err)
+	}
+	return resp.StatusCode, nil
+}
+
+func suscripcionToDomain(subscription Subscription, usuarioID uint, en time.Time) domain.Suscripcion {
+	return domain.Suscripcion{
+		ID:               subscription.ID,
+		UsuarioID:        usuarioID,
+		PlanID:           subscription.PlanID,
+		Estado:           subscription.Status,
+		FechaInicio:      subscription.FechaInicio,
+		FechaVencimiento: subscription.FechaVencimiento,
+		EventoEn:         en,
+	}
+}
+
+func planToDomain(plan Plan, en time.Time) domain.Plan {
+	return domain.Plan{
+		ID:                    plan.ID,
+		Nombre:                plan.Nombre,
+		TipoAcceso:            plan.TipoAcceso,
+		ActividadesPermitidas: plan.ActividadesPermitidas,
+		ActividadesPorSemana:  plan.ActividadesPorSemana,
+		CupoClases:            plan.CupoClases,
+		CupoPeriodo:           plan.CupoPeriodo,
+		Activo:                plan.Activo,
+		EventoEn:              en,
+	}
+}
+
+func planFromDomain(plan domain.Plan) Plan {
+	return Plan{
+		ID:                    plan.ID,
+		Nombre:                plan.Nombre,
+		TipoAcceso:            plan.TipoAcceso,
+		ActividadesPermitidas: plan.ActividadesPermitidas,
+		ActividadesPorSemana:  plan.ActividadesPorSemana,
+		CupoClases:            plan.CupoClases,
+		CupoPeriodo:           plan.CupoPeriodo,
+		Activo:                plan.Activo,
+	}
+}
+
+// usuarioIDEvento lee el usuario_id de un evento (subscriptions-api lo publica como string)
+func usuarioIDEvento(v interface{}) (uint, bool) {
+	switch id := v.(type) {
+	case string:
+		n, err := strconv.ParseUint(id, 10, 32)
+		return uint(n), err == nil && n > 0
+	case float64:
+		return uint(id), id > 0
+	}
+	return 0, false
+}
+
+// fechaEvento lee una fecha RFC 3339 de un evento
+func fechaEvento(v interface{}) (time.Time, bool) {
+	texto, ok := v.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	fecha, err := time.Parse(time.RFC3339Nano, texto)
+	return fecha, err == nil
+}
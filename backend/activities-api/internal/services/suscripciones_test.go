@@ -0,0 +1,348 @@
+package services
+
+import (
+	"activities-api/internal/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yourusername/gym-management/shared/httpclient"
+)
+
+// MockSuscripcionesRepository guarda la copia local en memoria
+// Como el upsert de MySQL, no pisa una fila con un EventoEn más nuevo
+type MockSuscripcionesRepository struct {
+	suscripciones map[string]domain.Suscripcion
+	planes        map[string]domain.Plan
+}
+
+func newMockSuscripcionesRepository() *MockSuscripcionesRepository {
+	return &MockSuscripcionesRepository{
+		suscripciones: make(map[string]domain.Suscripcion),
+		planes:        make(map[string]domain.Plan),
+	}
+}
+
+func (m *MockSuscripcionesRepository) ListByUsuario(ctx context.Context, usuarioID uint) ([]domain.Suscripcion, error) {
+	var result []domain.Suscripcion
+	for _, s := range m.suscripciones {
+		if s.UsuarioID == usuarioID {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockSuscripcionesRepository) GetSuscripcion(ctx context.Context, id string) (domain.Suscripcion, error) {
+	s, ok := m.suscripciones[id]
+	if !ok {
+		return domain.Suscripcion{}, domain.ErrSuscripcionNotFound
+	}
+	return s, nil
+}
+
+func (m *MockSuscripcionesRepository) SaveSuscripcion(ctx context.Context, suscripcion domain.Suscripcion) error {
+	if actual, ok := m.suscripciones[suscripcion.ID]; ok && suscripcion.EventoEn.Before(actual.EventoEn) {
+		return nil
+	}
+	m.suscripciones[suscripcion.ID] = suscripcion
+	return nil
+}
+
+func (m *MockSuscripcionesRepository) DesactivarAusentes(ctx context.Context, vigentes []string, antes time.Time) (int64, error) {
+	listadas := make(map[string]bool)
+	for _, id := range vigentes {
+		listadas[id] = true
+	}
+	var count int64
+	for id, s := range m.suscripciones {
+		if s.Estado == domain.SuscripcionActiva && s.EventoEn.Before(antes) && !listadas[id] {
+			s.Estado = domain.SuscripcionInactiva
+			s.EventoEn = antes
+			m.suscripciones[id] = s
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockSuscripcionesRepository) GetPlan(ctx context.Context, id string) (domain.Plan, error) {
+	p, ok := m.planes[id]
+	if !ok {
+		return domain.Plan{}, domain.ErrPlanNotFound
+	}
+	return p, nil
+}
+
+func (m *MockSuscripcionesRepository) SavePlan(ctx context.Context, plan domain.Plan) error {
+	if actual, ok := m.planes[plan.ID]; ok && plan.EventoEn.Before(actual.EventoEn) {
+		return nil
+	}
+	m.planes[plan.ID] = plan
+	return nil
+}
+
+// subscriptionsAPIFake simula subscriptions-api y cuenta las llamadas
+// Las primeras "fallas" llamadas responden 503
+type subscriptionsAPIFake struct {
+	server  *httptest.Server
+	llamado int32
+	fallas  int32
+}
+
+func newSubscriptionsAPIFake(t *testing.T, rutas map[string]interface{}) *subscriptionsAPIFake {
+	t.Helper()
+	fake := &subscriptionsAPIFake{}
+	fake.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fake.llamado, 1)
+		if atomic.AddInt32(&fake.fallas, -1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		respuesta, ok := rutas[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if status, ok := respuesta.(int); ok {
+			w.WriteHeader(status)
+			return
+		}
+		json.NewEncoder(w).Encode(respuesta)
+	}))
+	t.Cleanup(fake.server.Close)
+	return fake
+}
+
+func (f *subscriptionsAPIFake) llamadas() int {
+	return int(atomic.LoadInt32(&f.llamado))
+}
+
+// testSubscriptionsClient crea el cliente de subscriptions-api con esperas cortas entre reintentos
+func testSubscriptionsClient(url string) *httpclient.Client {
+	return httpclient.New("subscriptions-api-test", httpclient.Config{
+		URLs:        []string{url},
+		BackoffBase: time.Millisecond,
+		BackoffMax:  5 * time.Millisecond,
+	})
+}
+
+func TestActiveSubscription_UsaLaCopiaLocal(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	vence := now.AddDate(0, 0, 20)
+	api := newSubscriptionsAPIFake(t, nil)
+
+	repo := newMockSuscripcionesRepository()
+	repo.suscripciones["sub-1"] = domain.Suscripcion{ID: "sub-1", UsuarioID: 5, PlanID: "plan-1", Estado: "activa", FechaVencimiento: &vence}
+	repo.planes["plan-1"] = domain.Plan{ID: "plan-1", Nombre: "Básico", TipoAcceso: "limitado", ActividadesPermitidas: []string{"yoga"}, ActividadesPorSemana: 2}
+
+	service := NewSuscripcionesService(repo, testSubscriptionsClient(api.server.URL), true)
+	service.now = func() time.Time { return now }
+
+	sub, err := service.ActiveSubscription(context.Background(), 5, "Bearer x")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if sub.ID != "sub-1" || sub.UserID != "5" || sub.PlanInfo.TipoAcceso != "limitado" || sub.PlanInfo.ActividadesPorSemana != 2 {
+		t.Errorf("suscripción inesperada: %+v", sub)
+	}
+	if api.llamadas() != 0 {
+		t.Errorf("no debería llamar a subscriptions-api, llamó %d veces", api.llamadas())
+	}
+
+	// Vencida aunque el evento expired no haya llegado todavía
+	service.now = func() time.Time { return vence.Add(time.Minute) }
+	if _, err := service.ActiveSubscription(context.Background(), 5, "Bearer x"); !errors.Is(err, ErrSinSuscripcionActiva) {
+		t.Errorf("esperaba ErrSinSuscripcionActiva con la suscripción vencida, obtuvo %v", err)
+	}
+	if api.llamadas() != 0 {
+		t.Errorf("un usuario conocido no debería caer al fallback, llamó %d veces", api.llamadas())
+	}
+}
+
+func TestActiveSubscription_FallbackHTTP(t *testing.T) {
+	vence := time.Now().AddDate(0, 1, 0)
+	api := newSubscriptionsAPIFake(t, map[string]interface{}{
+		"/subscriptions/active/8": Subscription{ID: "sub-8", UserID: "8", PlanID: "plan-2", Status: "activa", FechaVencimiento: &vence},
+		"/plans/plan-2":           Plan{ID: "plan-2", Nombre: "Full", TipoAcceso: "completo", Activo: true},
+	})
+	repo := newMockSuscripcionesRepository()
+	service := NewSuscripcionesService(repo, testSubscriptionsClient(api.server.URL), true)
+
+	sub, err := service.ActiveSubscription(context.Background(), 8, "Bearer x")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if sub.PlanInfo.TipoAcceso != "completo" || api.llamadas() != 2 {
+		t.Fatalf("esperaba la suscripción y el plan de subscriptions-api (2 llamadas), obtuvo %+v con %d llamadas", sub, api.llamadas())
+	}
+
+	// Queda en la copia local: la segunda consulta no sale por HTTP
+	if _, err := service.ActiveSubscription(context.Background(), 8, "Bearer x"); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if api.llamadas() != 2 {
+		t.Errorf("la segunda consulta debería usar la copia local, llamadas: %d", api.llamadas())
+	}
+
+	// Sin fallback, un usuario desconocido no tiene suscripción
+	sinFallback := NewSuscripcionesService(newMockSuscripcionesRepository(), testSubscriptionsClient(api.server.URL), false)
+	if _, err := sinFallback.ActiveSubscription(context.Background(), 8, "Bearer x"); !errors.Is(err, ErrSinSuscripcionActiva) {
+		t.Errorf("esperaba ErrSinSuscripcionActiva sin fallback, obtuvo %v", err)
+	}
+	if api.llamadas() != 2 {
+		t.Errorf("sin fallback no debería llamar a subscriptions-api, llamadas: %d", api.llamadas())
+	}
+}
+
+func TestActiveSubscription_FallbackResiliente(t *testing.T) {
+	vence := time.Now().AddDate(0, 1, 0)
+	rutas := map[string]interface{}{
+		"/subscriptions/active/8": Subscription{ID: "sub-8", UserID: "8", PlanID: "plan-2", Status: "activa", FechaVencimiento: &vence},
+		"/plans/plan-2":           Plan{ID: "plan-2", Nombre: "Full", TipoAcceso: "completo", Activo: true},
+	}
+
+	// Un 503 pasajero se reintenta
+	api := newSubscriptionsAPIFake(t, rutas)
+	api.fallas = 1
+	service := NewSuscripcionesService(newMockSuscripcionesRepository(), testSubscriptionsClient(api.server.URL), true)
+	if _, err := service.ActiveSubscription(context.Background(), 8, "Bearer x"); err != nil {
+		t.Fatalf("esperaba que el reintento consiga la suscripción, obtuvo %v", err)
+	}
+	if api.llamadas() != 3 {
+		t.Errorf("esperaba 3 llamadas (503, reintento y plan), obtuvo %d", api.llamadas())
+	}
+
+	// Caído: con el circuito abierto las inscripciones fallan sin esperar a subscriptions-api
+	caida := newSubscriptionsAPIFake(t, rutas)
+	caida.fallas = 1000
+	client := httpclient.New("subscriptions-api-caida", httpclient.Config{
+		URLs:             []string{caida.server.URL},
+		MaxRetries:       -1,
+		FailureThreshold: 2,
+		OpenTimeout:      time.Minute,
+	})
+	service = NewSuscripcionesService(newMockSuscripcionesRepository(), client, true)
+	for i := 0; i < 3; i++ {
+		if _, err := service.ActiveSubscription(context.Background(), 8, "Bearer x"); err == nil {
+			t.Fatal("esperaba error con subscriptions-api caído")
+		}
+	}
+	_, err := service.ActiveSubscription(context.Background(), 8, "Bearer x")
+	if !errors.Is(err, httpclient.ErrCircuitOpen) {
+		t.Errorf("esperaba ErrCircuitOpen, obtuvo %v", err)
+	}
+	if caida.llamadas() != 2 {
+		t.Errorf("con el circuito abierto no debería llamar a subscriptions-api, llamadas: %d", caida.llamadas())
+	}
+}
+
+func TestAplicarEventoSuscripcion(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockSuscripcionesRepository()
+	service := NewSuscripcionesService(repo, testSubscriptionsClient(""), false)
+	t0 := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	err := service.AplicarEventoSuscripcion(ctx, "activated", "sub-1", t0, map[string]interface{}{
+		"usuario_id":        "5",
+		"plan_id":           "plan-1",
+		"estado":            "activa",
+		"fecha_vencimiento": t0.AddDate(0, 1, 0).Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if s := repo.suscripciones["sub-1"]; s.UsuarioID != 5 || s.Estado != "activa" || s.FechaVencimiento == nil {
+		t.Fatalf("suscripción mal aplicada: %+v", s)
+	}
+
+	// Un evento anterior que llega tarde no pisa el estado
+	service.AplicarEventoSuscripcion(ctx, "create", "sub-1", t0.Add(-time.Hour), map[string]interface{}{"usuario_id": "5", "estado": "pendiente_pago"})
+	if s := repo.suscripciones["sub-1"]; s.Estado != "activa" {
+		t.Errorf("el evento viejo no debería aplicarse, estado %s", s.Estado)
+	}
+
+	// Cancelación sin estado en data (formato anterior): el estado sale de la acción y se conserva el resto
+	service.AplicarEventoSuscripcion(ctx, "cancelled", "sub-1", t0.Add(time.Hour), map[string]interface{}{"usuario_id": "5"})
+	s := repo.suscripciones["sub-1"]
+	if s.Estado != "cancelada" || s.PlanID != "plan-1" || s.FechaVencimiento == nil {
+		t.Errorf("la cancelación debería cambiar solo el estado: %+v", s)
+	}
+
+	// Sin usuario_id no se puede guardar una suscripción nueva
+	service.AplicarEventoSuscripcion(ctx, "update", "sub-2", t0, map[string]interface{}{"estado": "activa"})
+	if _, ok := repo.suscripciones["sub-2"]; ok {
+		t.Error("no debería guardar una suscripción sin usuario")
+	}
+}
+
+func TestAplicarEventoPlan(t *testing.T) {
+	ctx := context.Background()
+	repo := newMockSuscripcionesRepository()
+	service := NewSuscripcionesService(repo, testSubscriptionsClient(""), false)
+	t0 := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	// Los números y listas llegan como float64 e []interface{} del JSON
+	service.AplicarEventoPlan(ctx, "created", "plan-1", t0, map[string]interface{}{
+		"nombre":                 "Básico",
+		"tipo_acceso":            "limitado",
+		"activo":                 true,
+		"actividades_permitidas": []interface{}{"yoga", "spinning"},
+		"actividades_por_semana": float64(3),
+	})
+	p := repo.planes["plan-1"]
+	if p.TipoAcceso != "limitado" || len(p.ActividadesPermitidas) != 2 || p.ActividadesPorSemana != 3 || !p.Activo {
+		t.Fatalf("plan mal aplicado: %+v", p)
+	}
+
+	service.AplicarEventoPlan(ctx, "deleted", "plan-1", t0.Add(time.Hour), nil)
+	if p := repo.planes["plan-1"]; p.Activo || p.TipoAcceso != "limitado" {
+		t.Errorf("el plan eliminado debería quedar inactivo con sus condiciones: %+v", p)
+	}
+}
+
+func TestSincronizar(t *testing.T) {
+	vence := time.Now().AddDate(0, 1, 0)
+	api := newSubscriptionsAPIFake(t, map[string]interface{}{
+		"/plans": map[string]interface{}{
+			"plans":       []Plan{{ID: "plan-1", Nombre: "Básico", TipoAcceso: "limitado", Activo: true}},
+			"total_pages": 1,
+		},
+		"/subscriptions/active": []Subscription{{ID: "sub-1", UserID: "5", PlanID: "plan-1", Status: "activa", FechaVencimiento: &vence}},
+	})
+
+	repo := newMockSuscripcionesRepository()
+	ayer := time.Now().AddDate(0, 0, -1)
+	// Activa en la copia local pero ya no en subscriptions-api (se perdió el evento de cancelación)
+	repo.suscripciones["sub-vieja"] = domain.Suscripcion{ID: "sub-vieja", UsuarioID: 9, Estado: "activa", EventoEn: ayer}
+
+	service := NewSuscripcionesService(repo, testSubscriptionsClient(api.server.URL), true)
+	resultado, err := service.Sincronizar(context.Background(), "Bearer admin")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if resultado.Planes != 1 || resultado.Suscripciones != 1 || resultado.Desactivadas != 1 {
+		t.Errorf("resultado inesperado: %+v", resultado)
+	}
+	if s := repo.suscripciones["sub-1"]; s.UsuarioID != 5 || s.Estado != "activa" {
+		t.Errorf("sub-1 mal sincronizada: %+v", s)
+	}
+	if s := repo.suscripciones["sub-vieja"]; s.Estado != domain.SuscripcionInactiva {
+		t.Errorf("sub-vieja debería quedar inactiva, estado %s", s.Estado)
+	}
+
+	// Sin permiso en subscriptions-api
+	noAutorizada := newSubscriptionsAPIFake(t, map[string]interface{}{
+		"/plans":                map[string]interface{}{"plans": []Plan{}, "total_pages": 0},
+		"/subscriptions/active": http.StatusForbidden,
+	})
+	service = NewSuscripcionesService(newMockSuscripcionesRepository(), testSubscriptionsClient(noAutorizada.server.URL), true)
+	if _, err := service.Sincronizar(context.Background(), "Bearer socio"); !errors.Is(err, ErrSincronizacionNoAutorizada) {
+		t.Errorf("esperaba ErrSincronizacionNoAutorizada, obtuvo %v", err)
+	}
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Lectura y escritura mínima de planillas XLSX (Office Open XML) con la biblioteca estándar:
+// solo la primera hoja, valores como texto y sin estilos
+
+const xlsxNSRelaciones = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+
+type xlsxLibro struct {
+	Hojas []struct {
+		RelacionID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelaciones struct {
+	Relaciones []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxTexto es un texto de sharedStrings o inline: plano (<t>) o con formato (<r><t>)
+type xlsxTexto struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxTexto) String() string {
+	var sb strings.Builder
+	sb.WriteString(t.T)
+	for _, run := range t.Runs {
+		sb.WriteString(run.T)
+	}
+	return sb.String()
+}
+
+type xlsxTextosCompartidos struct {
+	Items []xlsxTexto `xml:"si"`
+}
+
+type xlsxHoja struct {
+	Filas []struct {
+		Numero int         `xml:"r,attr"`
+		Celdas []xlsxCelda `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+type xlsxCelda struct {
+	Ref    string    `xml:"r,attr"`
+	Tipo   string    `xml:"t,attr"`
+	Valor  string    `xml:"v"`
+	Inline xlsxTexto `xml:"is"`
+}
+
+// leerXLSX devuelve las celdas de la primera hoja; filas[i] es la fila i+1 de la planilla
+// Falla si la hoja tiene más de maxFilas filas
+func leerXLSX(data []byte, maxFilas int) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("el archivo no es un XLSX válido: %w", err)
+	}
+	archivos := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		archivos[f.Name] = f
+	}
+
+	rutaHoja, err := primeraHojaXLSX(archivos)
+	if err != nil {
+		return nil, err
+	}
+
+	var compartidos xlsxTextosCompartidos
+	if f, ok := archivos["xl/sharedStrings.xml"]; ok {
+		if err := decodificarXLSX(f, &compartidos); err != nil {
+			return nil, err
+		}
+	}
+
+	var hoja xlsxHoja
+	if err := decodificarXLSX(archivos[rutaHoja], &hoja); err != nil {
+		return nil, err
+	}
+
+	var filas [][]string
+	for i, fila := range hoja.Filas {
+		numero := fila.Numero
+		if numero == 0 {
+			numero = i + 1
+		}
+		if numero > maxFilas {
+			return nil, fmt.Errorf("la planilla supera las %d filas", maxFilas)
+		}
+		for len(filas) < numero {
+			filas = append(filas, nil)
+		}
+
+		var valores []string
+		for j, celda := range fila.Celdas {
+			columna := j
+			if celda.Ref != "" {
+				columna = columnaDeReferencia(celda.Ref)
+			}
+			for len(valores) <= columna {
+				valores = append(valores, "")
+			}
+
+			switch celda.Tipo {
+			case "s":
+				var idx int
+				if _, err := fmt.Sscan(celda.Valor, &idx); err != nil || idx < 0 || idx >= len(compartidos.Items) {
+					return nil, fmt.Errorf("celda %s: texto compartido inválido", celda.Ref)
+				}
+				valores[columna] = compartidos.Items[idx].String()
+			case "inlineStr":
+				valores[columna] = celda.Inline.String()
+			default:
+				valores[columna] = celda.Valor
+			}
+		}
+		filas[numero-1] = valores
+	}
+
+	return filas, nil
+}
+
+// primeraHojaXLSX resuelve la ruta de la primera hoja del libro (workbook.xml + sus relaciones)
+func primeraHojaXLSX(archivos map[string]*zip.File) (string, error) {
+	const porDefecto = "xl/worksheets/sheet1.xml"
+
+	libroXML, okLibro := archivos["xl/workbook.xml"]
+	relsXML, okRels := archivos["xl/_rels/workbook.xml.rels"]
+	if !okLibro || !okRels {
+		if _, ok := archivos[porDefecto]; ok {
+			return porDefecto, nil
+		}
+		return "", fmt.Errorf("el archivo no es un XLSX válido: falta el libro")
+	}
+
+	var libro xlsxLibro
+	if err := decodificarXLSX(libroXML, &libro); err != nil {
+		return "", err
+	}
+	var rels xlsxRelaciones
+	if err := decodificarXLSX(relsXML, &rels); err != nil {
+		return "", err
+	}
+	if len(libro.Hojas) == 0 {
+		return "", fmt.Errorf("el libro no tiene hojas")
+	}
+
+	for _, rel := range rels.Relaciones {
+		if rel.ID != libro.Hojas[0].RelacionID {
+			continue
+		}
+		ruta := "xl/" + rel.Target
+		if strings.HasPrefix(rel.Target, "/") {
+			ruta = strings.TrimPrefix(rel.Target, "/")
+		}
+		if _, ok := archivos[ruta]; !ok {
+			return "", fmt.Errorf("el libro referencia una hoja inexistente (%s)", rel.Target)
+		}
+		return ruta, nil
+	}
+
+	return "", fmt.Errorf("no se encontró la primera hoja del libro")
+}
+
+func decodificarXLSX(f *zip.File, destino interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("error abriendo %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(rc).Decode(destino); err != nil {
+		return fmt.Errorf("error leyendo %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// columnaDeReferencia traduce la referencia de una celda ("C12") a su columna (2)
+func columnaDeReferencia(ref string) int {
+	columna := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		columna = columna*26 + int(r-'A'+1)
+	}
+	return columna - 1
+}
+
+// referenciaDeColumna es la inversa de columnaDeReferencia sin el número de fila (2 → "C")
+func referenciaDeColumna(columna int) string {
+	ref := ""
+	for columna++; columna > 0; columna = (columna - 1) / 26 {
+		ref = string(rune('A'+(columna-1)%26)) + ref
+	}
+	return ref
+}
+
+// escribirXLSX genera un libro con una sola hoja; todas las celdas van como texto inline
+func escribirXLSX(w io.Writer, hoja string, filas [][]string) error {
+	var datos strings.Builder
+	for i, fila := range filas {
+		fmt.Fprintf(&datos, `<row r="%d">`, i+1)
+		for j, valor := range fila {
+			if valor == "" {
+				continue
+			}
+			fmt.Fprintf(&datos, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, referenciaDeColumna(j), i+1)
+			if err := xml.EscapeText(&datos, []byte(valor)); err != nil {
+				return err
+			}
+			datos.WriteString(`</t></is></c>`)
+		}
+		datos.WriteString(`</row>`)
+	}
+
+	var nombreHoja strings.Builder
+	if err := xml.EscapeText(&nombreHoja, []byte(hoja)); err != nil {
+		return err
+	}
+
+	archivos := []struct{ nombre, contenido string }{
+		{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="` + xlsxNSRelaciones + `">` +
+			`<sheets><sheet name="` + nombreHoja.String() + `" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`},
+		{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + xlsxNSRelaciones + `/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`},
+		{"xl/worksheets/sheet1.xml", xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<sheetData>` + datos.String() + `</sheetData>` +
+			`</worksheet>`},
+	}
+
+	zw := zip.NewWriter(w)
+	for _, archivo := range archivos {
+		f, err := zw.Create(archivo.nombre)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, archivo.contenido); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
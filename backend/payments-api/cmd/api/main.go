@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gym-management/shared/auth"
+	"github.com/yourusername/gym-management/shared/httpclient"
 	"github.com/yourusername/payments-api/internal/clients"
 	"github.com/yourusername/payments-api/internal/config"
 	"github.com/yourusername/payments-api/internal/controllers"
@@ -87,12 +89,24 @@ func main() {
 	webhookController := controllers.NewWebhookController(gatewayFactory, paymentRepo, paymentService)
 	log.Println("✅ Controllers inicializados (Payment + Webhook)")
 
+	// ========== 6.5. LISTA DE TOKENS REVOCADOS ==========
+	// Se sincroniza periódicamente desde users-api (logout / sesiones comprometidas)
+	// Cliente hacia users-api (también para el JWKS): timeouts, reintentos y circuit breaker de httpclient
+	usersAPI := httpclient.New("users-api", cfg.UsersAPI)
+	revocations := auth.NewRevocationList(usersAPI, 15*time.Second)
+	revocations.Start()
+	defer revocations.Stop()
+
+	// Claves públicas de users-api (JWKS) para verificar los JWT sin secreto compartido
+	jwtKeys := auth.NewRemoteKeySet(usersAPI, auth.JWKSPath, 10*time.Minute)
+	tokenVerifier := auth.NewVerifier(jwtKeys, revocations)
+
 	// ========== 7. CONFIGURAR GIN ROUTER ==========
 	router := gin.Default()
 	router.Use(middleware.CORS())
 
 	// ========== 8. REGISTRAR RUTAS ==========
-	registerRoutes(router, paymentController, webhookController, paymentService, mongoDB, rabbitMQClient, cfg)
+	registerRoutes(router, paymentController, webhookController, paymentService, mongoDB, rabbitMQClient, tokenVerifier, cfg)
 
 	// ========== 9. INICIAR SERVIDOR ==========
 	log.Println("")
@@ -130,6 +144,7 @@ func registerRoutes(
 	paymentService *services.PaymentService,
 	mongoDB *database.MongoDB,
 	rabbitMQClient *clients.RabbitMQPublisher,
+	tokenVerifier *auth.Verifier,
 	cfg *config.Config,
 ) {
 	// ========== HEALTH CHECK ==========
@@ -172,11 +187,7 @@ func registerRoutes(
 	})
 
 	// ========== MIDDLEWARE DE AUTENTICACIÓN ==========
-	jwtSecret := cfg.JWTSecret
-	if jwtSecret == "" {
-		jwtSecret = "your-secret-key" // Valor por defecto para desarrollo
-	}
-	authMiddleware := middleware.JWTAuthMiddleware(jwtSecret)
+	authMiddleware := middleware.JWTAuthMiddleware(tokenVerifier)
 	adminMiddleware := middleware.AdminOnlyMiddleware()
 
 	// ========== RUTAS BÁSICAS (compatibilidad) ==========
@@ -216,12 +227,11 @@ func registerRoutes(
 		// Procesar reembolso
 		paymentRoutes.POST("/:id/refund", refundPaymentHandler(paymentService))
 
-		// ========== RUTAS PARA PAGOS EN EFECTIVO (ADMIN) ⭐ ==========
-		// Aprobar pago en efectivo (solo admin)
-		paymentRoutes.POST("/:id/approve", paymentController.ApproveCashPayment)
-
-		// Rechazar pago en efectivo (solo admin)
-		paymentRoutes.POST("/:id/reject", paymentController.RejectCashPayment)
+		// ========== RUTAS PARA PAGOS EN EFECTIVO (STAFF) ⭐ ==========
+		// Aprobar/rechazar pago en efectivo: owners en todas las sucursales, receptionist/branch_manager en las suyas
+		cashMiddleware := middleware.RequirePermission(auth.PermPaymentsApproveCash)
+		paymentRoutes.POST("/:id/approve", authMiddleware, cashMiddleware, paymentController.ApproveCashPayment)
+		paymentRoutes.POST("/:id/reject", authMiddleware, cashMiddleware, paymentController.RejectCashPayment)
 	}
 
 	// ========== WEBHOOKS ==========
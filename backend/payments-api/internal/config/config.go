@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/joho/godotenv"
+	"github.com/yourusername/gym-management/shared/httpclient"
 )
 
 // Config - Configuración general del microservicio
@@ -12,7 +13,7 @@ type Config struct {
 	Port          string
 	MongoURI      string
 	MongoDatabase string
-	JWTSecret     string
+	UsersAPI      httpclient.Config // users-api (JWKS y lista de tokens revocados): USERS_API_URL, _TIMEOUT, _MAX_RETRIES...
 	MercadoPago   MercadoPagoConfig
 	Stripe        StripeConfig
 	RabbitMQ      RabbitMQConfig
@@ -48,7 +49,7 @@ func LoadConfig() *Config {
 		Port:          getEnv("PORT", "8083"),
 		MongoURI:      getEnv("MONGO_URI", "mongodb://localhost:27017"),
 		MongoDatabase: getEnv("MONGO_DATABASE", "payments"),
-		JWTSecret:     getEnv("JWT_SECRET", "your-secret-key"),
+		UsersAPI:      httpclient.ConfigFromEnv("USERS_API", "http://localhost:8080"),
 
 		// Mercado Pago
 		MercadoPago: MercadoPagoConfig{
@@ -5,7 +5,9 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gym-management/shared/auth"
 	"github.com/yourusername/payments-api/internal/domain/dtos"
+	"github.com/yourusername/payments-api/internal/middleware"
 	"github.com/yourusername/payments-api/internal/services"
 )
 
@@ -30,6 +32,11 @@ func (c *PaymentController) CreatePayment(ctx *gin.Context) {
 		return
 	}
 
+	// Sin sucursal explícita se cobra en la sucursal de origen del usuario
+	if claims, ok := middleware.ClaimsFromContext(ctx); ok && req.SucursalID == nil && len(claims.BranchIDs) > 0 {
+		req.SucursalID = &claims.BranchIDs[0]
+	}
+
 	payment, err := c.service.CreatePayment(ctx.Request.Context(), req)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -45,7 +52,6 @@ func (c *PaymentController) GetPayment(ctx *gin.Context) {
 
 	// Obtener información del usuario autenticado
 	currentUserID, _ := ctx.Get("id_usuario")
-	isAdmin, _ := ctx.Get("is_admin")
 
 	payment, err := c.service.GetPaymentByID(ctx.Request.Context(), paymentID)
 	if err != nil {
@@ -53,8 +59,8 @@ func (c *PaymentController) GetPayment(ctx *gin.Context) {
 		return
 	}
 
-	// 🔒 VALIDACIÓN DE SEGURIDAD: Solo el dueño del pago o admin puede verlo
-	if payment.UserID != fmt.Sprintf("%d", currentUserID.(uint)) && !isAdmin.(bool) {
+	// 🔒 VALIDACIÓN DE SEGURIDAD: Solo el dueño del pago o el staff con payments:read en su sucursal puede verlo
+	if payment.UserID != fmt.Sprintf("%d", currentUserID.(uint)) && !canAccessPayment(ctx, auth.PermPaymentsRead, payment) {
 		ctx.JSON(http.StatusForbidden, gin.H{
 			"error": "You don't have permission to view this payment",
 		})
@@ -170,40 +176,44 @@ func (c *PaymentController) ProcessPayment(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Pago procesado correctamente"})
 }
 
-// ApproveCashPayment aprueba un pago en efectivo (solo admin)
+// ApproveCashPayment aprueba un pago en efectivo (payments:approve_cash en la sucursal del pago)
 func (c *PaymentController) ApproveCashPayment(ctx *gin.Context) {
-	paymentID := ctx.Param("id")
+	c.resolveCashPayment(ctx, "completed", "Pago en efectivo aprobado correctamente")
+}
 
-	// Actualizar el estado a "completed"
-	req := dtos.UpdatePaymentStatusRequest{
-		Status: "completed",
-	}
+// RejectCashPayment rechaza un pago en efectivo (payments:approve_cash en la sucursal del pago)
+func (c *PaymentController) RejectCashPayment(ctx *gin.Context) {
+	c.resolveCashPayment(ctx, "failed", "Pago en efectivo rechazado")
+}
 
-	err := c.service.UpdatePaymentStatus(ctx.Request.Context(), paymentID, req)
+// resolveCashPayment aprueba ("completed") o rechaza ("failed") un pago en efectivo
+// Un receptionist solo resuelve los pagos de sus sucursales; los pagos sin sucursal solo un owner
+func (c *PaymentController) resolveCashPayment(ctx *gin.Context, status, message string) {
+	paymentID := ctx.Param("id")
+
+	payment, err := c.service.GetPaymentByID(ctx.Request.Context(), paymentID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "Pago en efectivo aprobado correctamente"})
-}
-
-// RejectCashPayment rechaza un pago en efectivo (solo admin)
-func (c *PaymentController) RejectCashPayment(ctx *gin.Context) {
-	paymentID := ctx.Param("id")
+	if !canAccessPayment(ctx, auth.PermPaymentsApproveCash, payment) {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"error": "You can only approve or reject cash payments of your branches",
+		})
+		return
+	}
 
-	// Actualizar el estado a "failed"
-	updateReq := dtos.UpdatePaymentStatusRequest{
-		Status: "failed",
+	req := dtos.UpdatePaymentStatusRequest{
+		Status: status,
 	}
 
-	err := c.service.UpdatePaymentStatus(ctx.Request.Context(), paymentID, updateReq)
-	if err != nil {
+	if err := c.service.UpdatePaymentStatus(ctx.Request.Context(), paymentID, req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "Pago en efectivo rechazado"})
+	ctx.JSON(http.StatusOK, gin.H{"message": message})
 }
 
 // HealthCheck verifica el estado del servicio
@@ -213,3 +223,16 @@ func (c *PaymentController) HealthCheck(ctx *gin.Context) {
 		"service": "payments-api",
 	})
 }
+
+// canAccessPayment indica si el usuario tiene el permiso en la sucursal del pago
+// Los pagos sin sucursal solo los gestiona un owner
+func canAccessPayment(ctx *gin.Context, perm auth.Permission, payment dtos.PaymentResponse) bool {
+	claims, ok := middleware.ClaimsFromContext(ctx)
+	if !ok {
+		return false
+	}
+	if payment.SucursalID == nil {
+		return claims.HasGlobalPermission(perm)
+	}
+	return claims.CanInBranch(perm, *payment.SucursalID)
+}
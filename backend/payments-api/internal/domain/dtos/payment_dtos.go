@@ -19,6 +19,7 @@ type CreatePaymentRequest struct {
 	CallbackURL    string                 `json:"callback_url,omitempty"`
 	WebhookURL     string                 `json:"webhook_url,omitempty"`
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	SucursalID     *uint                  `json:"sucursal_id,omitempty"` // Por defecto la sucursal de origen del usuario
 }
 
 // UpdatePaymentStatusRequest - DTO para actualizar el estado de un pago
@@ -41,13 +42,14 @@ type PaymentResponse struct {
 	TransactionID  string                 `json:"transaction_id,omitempty"`
 	IdempotencyKey string                 `json:"idempotency_key,omitempty"`
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	SucursalID     *uint                  `json:"sucursal_id,omitempty"`
 	CreatedAt      time.Time              `json:"created_at"`
 	UpdatedAt      time.Time              `json:"updated_at"`
 	ProcessedAt    *time.Time             `json:"processed_at,omitempty"`
 }
 
 // ToPaymentResponse - Convierte una entidad Payment a PaymentResponse
-func ToPaymentResponse(id primitive.ObjectID, entityType, entityID, userID string, amount float64, currency, status, paymentMethod, paymentGateway, transactionID, idempotencyKey string, metadata map[string]interface{}, createdAt, updatedAt time.Time, processedAt *time.Time) PaymentResponse {
+func ToPaymentResponse(id primitive.ObjectID, entityType, entityID, userID string, amount float64, currency, status, paymentMethod, paymentGateway, transactionID, idempotencyKey string, metadata map[string]interface{}, createdAt, updatedAt time.Time, processedAt *time.Time, sucursalID *uint) PaymentResponse {
 	return PaymentResponse{
 		ID:             id.Hex(),
 		EntityType:     entityType,
@@ -61,6 +63,7 @@ func ToPaymentResponse(id primitive.ObjectID, entityType, entityID, userID strin
 		TransactionID:  transactionID,
 		IdempotencyKey: idempotencyKey,
 		Metadata:       metadata,
+		SucursalID:     sucursalID,
 		CreatedAt:      createdAt,
 		UpdatedAt:      updatedAt,
 		ProcessedAt:    processedAt,
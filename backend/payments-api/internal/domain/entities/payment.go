@@ -23,6 +23,7 @@ type Payment struct {
 	PaymentType    string                 `bson:"payment_type,omitempty"` // "one_time" o "recurring" (opcional)
 	IdempotencyKey string                 `bson:"idempotency_key,omitempty"` // UUID para prevenir duplicados (idempotencia)
 	Metadata       map[string]interface{} `bson:"metadata"`        // Información adicional específica del dominio
+	SucursalID     *uint                  `bson:"sucursal_id,omitempty"` // Sucursal donde se cobra (el staff solo gestiona los pagos de sus sucursales)
 	CreatedAt      time.Time              `bson:"created_at"`
 	UpdatedAt      time.Time              `bson:"updated_at"`
 	ProcessedAt    *time.Time             `bson:"processed_at"` // Fecha de procesamiento del pago
@@ -74,6 +74,7 @@ func (s *PaymentService) ProcessRecurringPayment(
 				existing.CreatedAt,
 				existing.UpdatedAt,
 				existing.ProcessedAt,
+				existing.SucursalID,
 			), nil
 		}
 	}
@@ -92,6 +93,7 @@ func (s *PaymentService) ProcessRecurringPayment(
 		PaymentType:    "recurring",        // Marcar como recurrente
 		IdempotencyKey: req.IdempotencyKey, // ⭐ Guardar idempotency key
 		Metadata:       req.Metadata,
+		SucursalID:     req.SucursalID,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
@@ -172,6 +174,7 @@ func (s *PaymentService) ProcessRecurringPayment(
 		payment.CreatedAt,
 		payment.UpdatedAt,
 		payment.ProcessedAt,
+		payment.SucursalID,
 	), nil
 }
 
@@ -204,6 +207,7 @@ func (s *PaymentService) ProcessPaymentWithGateway(
 				existing.CreatedAt,
 				existing.UpdatedAt,
 				existing.ProcessedAt,
+				existing.SucursalID,
 			), nil
 		}
 	}
@@ -222,6 +226,7 @@ func (s *PaymentService) ProcessPaymentWithGateway(
 		PaymentType:    "one_time",         // Marcar como pago único
 		IdempotencyKey: req.IdempotencyKey, // ⭐ Guardar idempotency key
 		Metadata:       req.Metadata,
+		SucursalID:     req.SucursalID,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
@@ -322,6 +327,7 @@ func (s *PaymentService) ProcessPaymentWithGateway(
 		payment.CreatedAt,
 		payment.UpdatedAt,
 		payment.ProcessedAt,
+		payment.SucursalID,
 	), nil
 }
 
@@ -385,6 +391,7 @@ func (s *PaymentService) SyncPaymentStatus(ctx context.Context, paymentID string
 		payment.CreatedAt,
 		payment.UpdatedAt,
 		payment.ProcessedAt,
+		payment.SucursalID,
 	), nil
 }
 
@@ -472,6 +479,7 @@ func (s *PaymentService) GetPaymentByID(ctx context.Context, paymentID string) (
 		payment.CreatedAt,
 		payment.UpdatedAt,
 		payment.ProcessedAt,
+		payment.SucursalID,
 	), nil
 }
 
@@ -498,6 +506,7 @@ func (s *PaymentService) GetAllPayments(ctx context.Context) ([]dtos.PaymentResp
 			payment.CreatedAt,
 			payment.UpdatedAt,
 			payment.ProcessedAt,
+			payment.SucursalID,
 		)
 	}
 
@@ -527,6 +536,7 @@ func (s *PaymentService) GetPaymentsByUser(ctx context.Context, userID string) (
 			payment.CreatedAt,
 			payment.UpdatedAt,
 			payment.ProcessedAt,
+			payment.SucursalID,
 		)
 	}
 
@@ -556,6 +566,7 @@ func (s *PaymentService) GetPaymentsByEntity(ctx context.Context, entityType, en
 			payment.CreatedAt,
 			payment.UpdatedAt,
 			payment.ProcessedAt,
+			payment.SucursalID,
 		)
 	}
 
@@ -585,6 +596,7 @@ func (s *PaymentService) GetPaymentsByStatus(ctx context.Context, status string)
 			payment.CreatedAt,
 			payment.UpdatedAt,
 			payment.ProcessedAt,
+			payment.SucursalID,
 		)
 	}
 
@@ -614,6 +626,7 @@ func (s *PaymentService) CreatePayment(ctx context.Context, req dtos.CreatePayme
 				existing.CreatedAt,
 				existing.UpdatedAt,
 				existing.ProcessedAt,
+				existing.SucursalID,
 			), nil
 		}
 	}
@@ -630,6 +643,7 @@ func (s *PaymentService) CreatePayment(ctx context.Context, req dtos.CreatePayme
 		PaymentGateway: req.PaymentGateway,
 		IdempotencyKey: req.IdempotencyKey, // ⭐ Guardar idempotency key
 		Metadata:       req.Metadata,
+		SucursalID:     req.SucursalID,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
@@ -659,6 +673,7 @@ func (s *PaymentService) CreatePayment(ctx context.Context, req dtos.CreatePayme
 		payment.CreatedAt,
 		payment.UpdatedAt,
 		payment.ProcessedAt,
+		payment.SucursalID,
 	), nil
 }
 
@@ -23,6 +23,8 @@ type MockPaymentRepository struct {
 	FindByStatusFunc func(ctx context.Context, status string) ([]*entities.Payment, error)
 	UpdateStatusFunc func(ctx context.Context, id primitive.ObjectID, status, transactionID string) error
 	CountFunc        func(ctx context.Context, filters map[string]interface{}) (int64, error)
+
+	FindByIdempotencyKeyFunc func(ctx context.Context, idempotencyKey string) (*entities.Payment, error)
 }
 
 func (m *MockPaymentRepository) Create(ctx context.Context, payment *entities.Payment) error {
@@ -39,6 +41,13 @@ func (m *MockPaymentRepository) FindByID(ctx context.Context, id primitive.Objec
 	return nil, nil
 }
 
+func (m *MockPaymentRepository) FindByIdempotencyKey(ctx context.Context, idempotencyKey string) (*entities.Payment, error) {
+	if m.FindByIdempotencyKeyFunc != nil {
+		return m.FindByIdempotencyKeyFunc(ctx, idempotencyKey)
+	}
+	return nil, nil
+}
+
 func (m *MockPaymentRepository) FindAll(ctx context.Context) ([]*entities.Payment, error) {
 	if m.FindAllFunc != nil {
 		return m.FindAllFunc(ctx)
@@ -666,13 +675,13 @@ func TestRefundPayment_ExceedsAmount(t *testing.T) {
 // Mocks adicionales para gateway
 
 type MockPaymentGateway struct {
-	GetNameFunc              func() string
-	CreatePaymentFunc        func(ctx context.Context, req gateways.PaymentRequest) (*gateways.PaymentResult, error)
-	GetPaymentStatusFunc     func(ctx context.Context, transactionID string) (*gateways.PaymentStatus, error)
-	RefundPaymentFunc        func(ctx context.Context, transactionID string, amount float64) (*gateways.RefundResult, error)
-	CancelPaymentFunc        func(ctx context.Context, transactionID string) error
-	ProcessWebhookFunc       func(ctx context.Context, payload []byte, headers map[string]string) (*gateways.WebhookEvent, error)
-	ValidateCredentialsFunc  func(ctx context.Context) error
+	GetNameFunc             func() string
+	CreatePaymentFunc       func(ctx context.Context, req gateways.PaymentRequest) (*gateways.PaymentResult, error)
+	GetPaymentStatusFunc    func(ctx context.Context, transactionID string) (*gateways.PaymentStatus, error)
+	RefundPaymentFunc       func(ctx context.Context, transactionID string, amount float64) (*gateways.RefundResult, error)
+	CancelPaymentFunc       func(ctx context.Context, transactionID string) error
+	ProcessWebhookFunc      func(ctx context.Context, payload []byte, headers map[string]string) (*gateways.WebhookEvent, error)
+	ValidateCredentialsFunc func(ctx context.Context) error
 }
 
 func (m *MockPaymentGateway) GetName() string {
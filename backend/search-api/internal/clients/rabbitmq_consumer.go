@@ -66,6 +66,7 @@ func NewRabbitMQConsumer(url, exchange, queueName string, searchService *service
 	bindings := []string{
 		"activity.*",
 		"inscription.*",
+		"branch.*",
 	}
 
 	for _, binding := range bindings {
@@ -155,6 +156,17 @@ func (r *RabbitMQConsumer) handleMessage(msg amqp.Delivery) {
 		}
 		log.Printf("✅ Actividad procesada: %s (action: %s)\n", event.ID, event.Action)
 
+	case "branch":
+		// Cambió una sucursal: reindexar sus actividades (sucursal_nombre)
+		err = r.handleBranchEvent(event)
+		if err != nil {
+			log.Printf("❌ Error procesando evento de sucursal: %v\n", err)
+			msg.Nack(false, true) // Requeue
+			return
+		}
+		// Las búsquedas cacheadas incluyen el nombre de la sucursal
+		r.cacheService.FlushAll()
+
 	default:
 		// Eventos no manejados específicamente - ignorar
 		log.Printf("⏭️  Evento ignorado: %s.%s (ID: %s)\n", event.Type, event.Action, event.ID)
@@ -178,12 +190,37 @@ func (r *RabbitMQConsumer) handleActivityEvent(event dtos.RabbitMQEvent) error {
 		log.Printf("🗑️  Eliminando actividad %s del índice\n", event.ID)
 		return r.searchService.DeleteDocument(event.ID)
 
+	case "session_cancelled", "session_rescheduled":
+		// Cambios de una sesión puntual: el índice solo tiene el horario semanal de la actividad
+		return nil
+
 	default:
 		log.Printf("⚠️  Acción desconocida para actividad: %s\n", event.Action)
 		return nil
 	}
 }
 
+// handleBranchEvent procesa eventos de sucursal reindexando sus actividades desde MySQL
+// branch.create no tiene actividades todavía y branch.delete solo se permite sin actividades activas
+func (r *RabbitMQConsumer) handleBranchEvent(event dtos.RabbitMQEvent) error {
+	switch event.Action {
+	case "update":
+		reindexadas, err := r.searchService.ReindexActivitiesBySucursal(event.ID)
+		if err != nil {
+			return err
+		}
+		log.Printf("🏢 Sucursal %s actualizada: %d actividades reindexadas\n", event.ID, reindexadas)
+		return nil
+
+	case "create", "delete":
+		return nil
+
+	default:
+		log.Printf("⚠️  Acción desconocida para sucursal: %s\n", event.Action)
+		return nil
+	}
+}
+
 // handleInscriptionEvent procesa eventos de inscripción actualizando solo el cupo (partial update)
 func (r *RabbitMQConsumer) handleInscriptionEvent(event dtos.RabbitMQEvent) error {
 	// Extraer actividad_id del evento
@@ -96,11 +96,13 @@ func (c *SearchController) QuickSearch(ctx *gin.Context) {
 	}
 
 	req := dtos.SearchRequest{
-		Query:    query,
-		Type:     typeFilter,
-		Filters:  filters,
-		Page:     page,
-		PageSize: pageSize,
+		Query:     query,
+		Type:      typeFilter,
+		Filters:   filters,
+		Page:      page,
+		PageSize:  pageSize,
+		SortBy:    ctx.Query("sort_by"),
+		SortOrder: ctx.Query("sort_order"),
 	}
 
 	// Generar clave de caché
@@ -20,6 +20,12 @@ type SearchDocument struct {
 	Cupo             int    `json:"cupo,omitempty"`             // Cupo total
 	CupoDisponible   int    `json:"cupo_disponible,omitempty"` // Lugares disponibles
 
+	// Promedios de calificaciones visibles (0 sin calificaciones); se puede ordenar por ellos
+	CalificacionPromedio           float64 `json:"calificacion_promedio,omitempty"`
+	Calificaciones                 int     `json:"calificaciones,omitempty"`
+	InstructorCalificacionPromedio float64 `json:"instructor_calificacion_promedio,omitempty"`
+	InstructorCalificaciones       int     `json:"instructor_calificaciones,omitempty"`
+
 	// Campos de Plan
 	PlanNombre     string  `json:"plan_nombre,omitempty"`
 	PlanPrecio     float64 `json:"plan_precio,omitempty"`
@@ -41,8 +47,14 @@ type SearchRequest struct {
 	Type       string            `json:"type"`       // Filtrar por tipo: activity, plan, subscription
 	Page       int               `json:"page"`       // Página (default: 1)
 	PageSize   int               `json:"page_size"`  // Tamaño de página (default: 10)
-	SortBy     string            `json:"sort_by"`    // Campo para ordenar
-	SortOrder  string            `json:"sort_order"` // asc o desc
+	SortBy     string            `json:"sort_by"`    // Campo para ordenar: calificacion_promedio o instructor_calificacion_promedio
+	SortOrder  string            `json:"sort_order"` // asc o desc (default: desc)
+}
+
+// SortFields - Campos por los que se puede ordenar (sort_by); sin sort_by se ordena por relevancia o título
+var SortFields = map[string]bool{
+	"calificacion_promedio":            true,
+	"instructor_calificacion_promedio": true,
 }
 
 // SearchResponse - DTO de respuesta con resultados de búsqueda
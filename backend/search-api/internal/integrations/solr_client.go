@@ -29,23 +29,35 @@ func NewSolrClient(solrURL string) *SolrClient {
 // SolrDocument - Estructura de documento Solr
 // Nota: Solr devuelve campos multivaluados como arrays, por eso usamos []string
 type SolrDocument struct {
-	ID              string    `json:"id"`
-	Type            []string  `json:"type"` // Solr devuelve arrays para campos multivaluados
-	Titulo          []string  `json:"titulo,omitempty"`
-	Descripcion     []string  `json:"descripcion,omitempty"`
-	Categoria       []string  `json:"categoria,omitempty"`
-	Instructor      []string  `json:"instructor,omitempty"`
-	Dia             []string  `json:"dia,omitempty"`
-	HorarioInicio   []string  `json:"horario_inicio,omitempty"`
-	HorarioFinal    []string  `json:"horario_final,omitempty"`
-	SucursalID      []int     `json:"sucursal_id,omitempty"`
-	SucursalNombre  []string  `json:"sucursal_nombre,omitempty"`
-	RequierePremium []bool    `json:"requiere_premium,omitempty"`
-	Cupo            []int     `json:"cupo,omitempty"`            // Cupo total
-	CupoDisponible  []int     `json:"cupo_disponible,omitempty"` // Lugares disponibles
-	PlanNombre      []string  `json:"plan_nombre,omitempty"`
-	PlanPrecio      []float64 `json:"plan_precio,omitempty"`
-	PlanTipoAcceso  []string  `json:"plan_tipo_acceso,omitempty"`
+	ID              string   `json:"id"`
+	Type            []string `json:"type"` // Solr devuelve arrays para campos multivaluados
+	Titulo          []string `json:"titulo,omitempty"`
+	Descripcion     []string `json:"descripcion,omitempty"`
+	Categoria       []string `json:"categoria,omitempty"`
+	Instructor      []string `json:"instructor,omitempty"`
+	Dia             []string `json:"dia,omitempty"`
+	HorarioInicio   []string `json:"horario_inicio,omitempty"`
+	HorarioFinal    []string `json:"horario_final,omitempty"`
+	SucursalID      []int    `json:"sucursal_id,omitempty"`
+	SucursalNombre  []string `json:"sucursal_nombre,omitempty"`
+	RequierePremium []bool   `json:"requiere_premium,omitempty"`
+	Cupo            []int    `json:"cupo,omitempty"`            // Cupo total
+	CupoDisponible  []int    `json:"cupo_disponible,omitempty"` // Lugares disponibles
+	// Promedios de calificaciones con los campos dinámicos tipados del configset _default
+	// (*_d pdouble, *_i pint, monovaluados): el tipo no depende del primer valor indexado y se puede ordenar
+	CalificacionPromedio           *float64  `json:"calificacion_promedio_d,omitempty"`
+	Calificaciones                 *int      `json:"calificaciones_i,omitempty"`
+	InstructorCalificacionPromedio *float64  `json:"instructor_calificacion_promedio_d,omitempty"`
+	InstructorCalificaciones       *int      `json:"instructor_calificaciones_i,omitempty"`
+	PlanNombre                     []string  `json:"plan_nombre,omitempty"`
+	PlanPrecio                     []float64 `json:"plan_precio,omitempty"`
+	PlanTipoAcceso                 []string  `json:"plan_tipo_acceso,omitempty"`
+}
+
+// solrSortFields - Campo de Solr de cada sort_by permitido
+var solrSortFields = map[string]string{
+	"calificacion_promedio":            "calificacion_promedio_d",
+	"instructor_calificacion_promedio": "instructor_calificacion_promedio_d",
 }
 
 // SolrResponse - Respuesta de búsqueda de Solr
@@ -178,6 +190,15 @@ func (c *SolrClient) Search(req dtos.SearchRequest) ([]dtos.SearchDocument, int,
 	params.Set("start", fmt.Sprintf("%d", start))
 	params.Set("rows", fmt.Sprintf("%d", req.PageSize))
 
+	// Ordenamiento (sort_by es uno de dtos.SortFields; a igual valor, por id)
+	if campo, ok := solrSortFields[req.SortBy]; ok {
+		direccion := "desc"
+		if strings.EqualFold(req.SortOrder, "asc") {
+			direccion = "asc"
+		}
+		params.Set("sort", fmt.Sprintf("%s %s,id asc", campo, direccion))
+	}
+
 	// Formato de respuesta
 	params.Set("wt", "json")
 
@@ -361,6 +382,13 @@ func (c *SolrClient) convertToSolrDocument(doc dtos.SearchDocument) SolrDocument
 		solrDoc.Cupo = []int{doc.Cupo}
 	}
 	solrDoc.CupoDisponible = []int{doc.CupoDisponible}
+	if doc.Type == "activity" {
+		// Siempre indexar los promedios de las actividades (0 sin calificaciones) para poder ordenar
+		solrDoc.CalificacionPromedio = &doc.CalificacionPromedio
+		solrDoc.Calificaciones = &doc.Calificaciones
+		solrDoc.InstructorCalificacionPromedio = &doc.InstructorCalificacionPromedio
+		solrDoc.InstructorCalificaciones = &doc.InstructorCalificaciones
+	}
 	if doc.PlanNombre != "" {
 		solrDoc.PlanNombre = []string{doc.PlanNombre}
 	}
@@ -477,6 +505,10 @@ func (c *SolrClient) convertFromSolrDocument(solrDoc map[string]interface{}) dto
 	doc.CupoDisponible = getIntValue(solrDoc["cupo_disponible"])
 	doc.RequierePremium = getBoolValue(solrDoc["requiere_premium"])
 	doc.PlanPrecio = getFloat64Value(solrDoc["plan_precio"])
+	doc.CalificacionPromedio = getFloat64Value(solrDoc["calificacion_promedio_d"])
+	doc.Calificaciones = getIntValue(solrDoc["calificaciones_i"])
+	doc.InstructorCalificacionPromedio = getFloat64Value(solrDoc["instructor_calificacion_promedio_d"])
+	doc.InstructorCalificaciones = getIntValue(solrDoc["instructor_calificaciones_i"])
 
 	return doc
 }
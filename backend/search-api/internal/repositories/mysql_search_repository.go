@@ -30,6 +30,12 @@ func NewMySQLSearchRepository(dbUser, dbPass, dbHost, dbPort, dbSchema string) (
 	return &MySQLSearchRepository{db: db}, nil
 }
 
+// ordenColumnas - Columnas de la vista para cada sort_by permitido
+var ordenColumnas = map[string]string{
+	"calificacion_promedio":            "a.calificacion_promedio",
+	"instructor_calificacion_promedio": "a.instructor_calificacion_promedio",
+}
+
 // SearchActivities - Busca actividades usando MySQL FULLTEXT
 func (r *MySQLSearchRepository) SearchActivities(req dtos.SearchRequest) ([]dtos.SearchDocument, int, error) {
 	// Base query using view that calculates available spots
@@ -47,6 +53,10 @@ func (r *MySQLSearchRepository) SearchActivities(req dtos.SearchRequest) ([]dtos
 			a.lugares,
 			COALESCE(s.nombre, '') as sucursal_nombre,
 			a.sucursal_id,
+			a.calificacion_promedio,
+			a.calificaciones,
+			a.instructor_calificacion_promedio,
+			a.instructor_calificaciones,
 			COUNT(*) OVER() as total_count
 		FROM actividades_lugares a
 		LEFT JOIN sucursales s ON a.sucursal_id = s.id_sucursal`
@@ -92,8 +102,16 @@ func (r *MySQLSearchRepository) SearchActivities(req dtos.SearchRequest) ([]dtos
 		baseQuery += " WHERE " + strings.Join(whereClauses, " AND ")
 	}
 
-	// Ordenamiento
-	baseQuery += " ORDER BY a.titulo ASC"
+	// Ordenamiento (sort_by solo acepta las columnas de ordenColumnas; a igual valor, por título)
+	if columna, ok := ordenColumnas[req.SortBy]; ok {
+		direccion := "DESC"
+		if strings.EqualFold(req.SortOrder, "asc") {
+			direccion = "ASC"
+		}
+		baseQuery += " ORDER BY " + columna + " " + direccion + ", a.titulo ASC"
+	} else {
+		baseQuery += " ORDER BY a.titulo ASC"
+	}
 
 	// Paginación
 	if req.Page < 1 {
@@ -135,6 +153,10 @@ func (r *MySQLSearchRepository) SearchActivities(req dtos.SearchRequest) ([]dtos
 			&doc.CupoDisponible,
 			&doc.SucursalNombre,
 			&sucursalID,
+			&doc.CalificacionPromedio,
+			&doc.Calificaciones,
+			&doc.InstructorCalificacionPromedio,
+			&doc.InstructorCalificaciones,
 			&totalCount,
 		)
 		if err != nil {
@@ -158,9 +180,8 @@ func (r *MySQLSearchRepository) SearchActivities(req dtos.SearchRequest) ([]dtos
 	return results, totalCount, nil
 }
 
-// GetAllActivities - Obtiene todas las actividades para indexación inicial
-func (r *MySQLSearchRepository) GetAllActivities() ([]dtos.SearchDocument, error) {
-	query := `
+// activitiesQuery - SELECT común de las actividades a indexar (vista actividades_lugares + sucursal)
+const activitiesQuery = `
 		SELECT
 			a.id_actividad,
 			a.titulo,
@@ -173,13 +194,30 @@ func (r *MySQLSearchRepository) GetAllActivities() ([]dtos.SearchDocument, error
 			a.cupo,
 			a.lugares,
 			COALESCE(s.nombre, '') as sucursal_nombre,
-			a.sucursal_id
+			a.sucursal_id,
+			a.calificacion_promedio,
+			a.calificaciones,
+			a.instructor_calificacion_promedio,
+			a.instructor_calificaciones
 		FROM actividades_lugares a
 		LEFT JOIN sucursales s ON a.sucursal_id = s.id_sucursal`
 
-	rows, err := r.db.Query(query)
+// GetAllActivities - Obtiene todas las actividades para indexación inicial
+func (r *MySQLSearchRepository) GetAllActivities() ([]dtos.SearchDocument, error) {
+	return r.queryActivities(activitiesQuery)
+}
+
+// GetActivitiesBySucursal - Obtiene las actividades de una sucursal (reindexación al cambiar la sucursal)
+func (r *MySQLSearchRepository) GetActivitiesBySucursal(sucursalID string) ([]dtos.SearchDocument, error) {
+	return r.queryActivities(activitiesQuery+`
+		WHERE a.sucursal_id = ?`, sucursalID)
+}
+
+// queryActivities - Ejecuta una consulta basada en activitiesQuery y arma los documentos
+func (r *MySQLSearchRepository) queryActivities(query string, args ...interface{}) ([]dtos.SearchDocument, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("error getting all activities: %w", err)
+		return nil, fmt.Errorf("error getting activities: %w", err)
 	}
 	defer rows.Close()
 
@@ -203,6 +241,10 @@ func (r *MySQLSearchRepository) GetAllActivities() ([]dtos.SearchDocument, error
 			&doc.CupoDisponible,
 			&doc.SucursalNombre,
 			&sucursalID,
+			&doc.CalificacionPromedio,
+			&doc.Calificaciones,
+			&doc.InstructorCalificacionPromedio,
+			&doc.InstructorCalificaciones,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning activity: %w", err)
@@ -236,7 +278,11 @@ func (r *MySQLSearchRepository) GetActivityByID(activityID string) (dtos.SearchD
 			a.cupo,
 			a.lugares,
 			COALESCE(s.nombre, '') as sucursal_nombre,
-			a.sucursal_id
+			a.sucursal_id,
+			a.calificacion_promedio,
+			a.calificaciones,
+			a.instructor_calificacion_promedio,
+			a.instructor_calificaciones
 		FROM actividades_lugares a
 		LEFT JOIN sucursales s ON a.sucursal_id = s.id_sucursal
 		WHERE a.id_actividad = ?`
@@ -258,6 +304,10 @@ func (r *MySQLSearchRepository) GetActivityByID(activityID string) (dtos.SearchD
 		&doc.CupoDisponible,
 		&doc.SucursalNombre,
 		&sucursalID,
+		&doc.CalificacionPromedio,
+		&doc.Calificaciones,
+		&doc.InstructorCalificacionPromedio,
+		&doc.InstructorCalificaciones,
 	)
 	if err != nil {
 		return dtos.SearchDocument{}, fmt.Errorf("error getting activity by ID: %w", err)
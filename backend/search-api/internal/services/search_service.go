@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"sync"
 
@@ -115,6 +116,10 @@ func (s *SearchService) Search(req dtos.SearchRequest) (*dtos.SearchResponse, er
 	if req.PageSize < 1 {
 		req.PageSize = 10
 	}
+	// Ordenamiento: solo los campos de dtos.SortFields (cualquier otro se ignora)
+	if !dtos.SortFields[req.SortBy] {
+		req.SortBy = ""
+	}
 
 	var results []dtos.SearchDocument
 	var totalCount int
@@ -172,6 +177,10 @@ func (s *SearchService) searchInMemory(req dtos.SearchRequest) ([]dtos.SearchDoc
 		}
 	}
 
+	if req.SortBy != "" {
+		sortDocuments(results, req.SortBy, strings.EqualFold(req.SortOrder, "asc"))
+	}
+
 	// Calcular paginación
 	totalCount := len(results)
 	start := (req.Page - 1) * req.PageSize
@@ -189,6 +198,27 @@ func (s *SearchService) searchInMemory(req dtos.SearchRequest) ([]dtos.SearchDoc
 	return results, totalCount
 }
 
+// sortDocuments ordena por el campo numérico de sort_by (a igual valor, por título)
+func sortDocuments(docs []dtos.SearchDocument, sortBy string, asc bool) {
+	valor := func(doc dtos.SearchDocument) float64 {
+		if sortBy == "instructor_calificacion_promedio" {
+			return doc.InstructorCalificacionPromedio
+		}
+		return doc.CalificacionPromedio
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		a, b := valor(docs[i]), valor(docs[j])
+		if a == b {
+			return docs[i].Titulo < docs[j].Titulo
+		}
+		if asc {
+			return a < b
+		}
+		return a > b
+	})
+}
+
 // matchesSearch verifica si un documento coincide con los criterios de búsqueda
 func (s *SearchService) matchesSearch(doc dtos.SearchDocument, req dtos.SearchRequest) bool {
 	// Filtrar por tipo si se especifica
@@ -290,6 +320,24 @@ func (s *SearchService) ReindexActivityByID(activityID string) error {
 	return s.IndexDocument(activity)
 }
 
+// ReindexActivitiesBySucursal reindexa desde MySQL todas las actividades de una sucursal
+// Se usa cuando cambia la sucursal (nombre) para refrescar sucursal_nombre en el índice
+func (s *SearchService) ReindexActivitiesBySucursal(sucursalID string) (int, error) {
+	if s.mysqlRepo == nil {
+		return 0, fmt.Errorf("mysql repository not available")
+	}
+
+	activities, err := s.mysqlRepo.GetActivitiesBySucursal(sucursalID)
+	if err != nil {
+		return 0, fmt.Errorf("error obteniendo actividades de la sucursal desde MySQL: %w", err)
+	}
+	if len(activities) == 0 {
+		return 0, nil
+	}
+
+	return len(activities), s.IndexDocuments(activities)
+}
+
 // UpdateActivityCupo actualiza solo el cupo_disponible de una actividad (partial update optimizado)
 func (s *SearchService) UpdateActivityCupo(activityID string) error {
 	if s.mysqlRepo == nil {
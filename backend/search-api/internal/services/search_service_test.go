@@ -1,6 +1,7 @@
 package services
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/yourusername/gym-management/search-api/internal/domain/dtos"
@@ -549,3 +550,39 @@ func TestSearch_ByDescription(t *testing.T) {
 		t.Error("Resultado incorrecto")
 	}
 }
+
+func TestSearch_SortByCalificacion(t *testing.T) {
+	// Sin Solr ni MySQL: busca en la caché en memoria
+	service := NewSearchService(nil, nil)
+
+	service.IndexDocument(dtos.SearchDocument{ID: "1", Type: "activity", Titulo: "Pilates", CalificacionPromedio: 3.5, InstructorCalificacionPromedio: 4.8})
+	service.IndexDocument(dtos.SearchDocument{ID: "2", Type: "activity", Titulo: "Yoga", CalificacionPromedio: 4.5, InstructorCalificacionPromedio: 4.1})
+	service.IndexDocument(dtos.SearchDocument{ID: "3", Type: "activity", Titulo: "Spinning"})
+
+	response, err := service.Search(dtos.SearchRequest{Type: "activity", SortBy: "calificacion_promedio"})
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if ids := idsDe(response.Results); ids != "2,1,3" {
+		t.Errorf("Orden esperado 2,1,3 (mejor calificada primero), obtenido %s", ids)
+	}
+
+	response, _ = service.Search(dtos.SearchRequest{Type: "activity", SortBy: "instructor_calificacion_promedio", SortOrder: "asc"})
+	if ids := idsDe(response.Results); ids != "3,2,1" {
+		t.Errorf("Orden esperado 3,2,1 (instructor ascendente), obtenido %s", ids)
+	}
+
+	// Un campo no permitido se ignora
+	response, _ = service.Search(dtos.SearchRequest{Type: "activity", SortBy: "titulo; DROP TABLE actividades"})
+	if response.TotalCount != 3 {
+		t.Errorf("Total esperado 3, obtenido %d", response.TotalCount)
+	}
+}
+
+func idsDe(docs []dtos.SearchDocument) string {
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return strings.Join(ids, ",")
+}
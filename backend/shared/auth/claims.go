@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Issuer es el "iss" de los access tokens emitidos por users-api
+const Issuer = "gym-management-system"
+
+// Roles del modelo anterior (is_admin): se aceptan como alias de owner y member
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// Claims es el contrato único de los access tokens que emite users-api y validan todos los servicios
+// - sub: ID del usuario (string, como indica RFC 7519)
+// - roles: roles del usuario
+// - branch_ids: sucursales a las que está asociado el usuario
+// - role_branches: sucursales donde ejerce cada rol de staff (instructor, receptionist, branch_manager)
+// - email_verified: si el usuario confirmó su email (algunos planes lo exigen para suscribirse)
+// - jti, iat, exp: identificador (para revocación), emisión y expiración
+type Claims struct {
+	Roles         []string          `json:"roles"`
+	BranchIDs     []uint            `json:"branch_ids,omitempty"`
+	RoleBranches  map[string][]uint `json:"role_branches,omitempty"`
+	EmailVerified bool              `json:"email_verified"`
+	jwt.RegisteredClaims
+}
+
+// NewClaims arma los claims de un access token para el usuario
+func NewClaims(userID uint, roles []string, branchIDs []uint, jti string, issuedAt time.Time, ttl time.Duration) Claims {
+	return Claims{
+		Roles:     roles,
+		BranchIDs: branchIDs,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer,
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(issuedAt.Add(ttl)),
+		},
+	}
+}
+
+// UserID devuelve el ID numérico del usuario (claim "sub")
+func (c Claims) UserID() (uint, error) {
+	id, err := strconv.ParseUint(c.Subject, 10, 64)
+	if err != nil || id == 0 {
+		return 0, errors.New("claim sub inválido")
+	}
+	return uint(id), nil
+}
+
+// HasRole indica si el usuario tiene el rol (admin y user valen como owner y member)
+func (c Claims) HasRole(role string) bool {
+	role = canonicalRole(role)
+	for _, r := range c.Roles {
+		if canonicalRole(r) == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdmin indica si el usuario es owner (el admin del modelo anterior)
+func (c Claims) IsAdmin() bool {
+	return c.HasRole(RoleOwner)
+}
+
+// Role devuelve el rol principal (admin > user) para los servicios que manejan un único rol
+func (c Claims) Role() string {
+	if c.IsAdmin() {
+		return RoleAdmin
+	}
+	return RoleUser
+}
+
+// legacyClaims son los claims del formato anterior (id_usuario, is_admin, role)
+// Se aceptan solo hasta LegacyClaimsDeadline
+type legacyClaims struct {
+	LegacyUserID  *float64 `json:"id_usuario,omitempty"`
+	LegacyIsAdmin bool     `json:"is_admin,omitempty"`
+	LegacyRole    string   `json:"role,omitempty"`
+}
+
+// tokenClaims es lo que se parsea del token: el contrato actual más los claims deprecados
+type tokenClaims struct {
+	Claims
+	legacyClaims
+}
+
+// isLegacy indica si el token viene en el formato anterior (sin "sub")
+func (c tokenClaims) isLegacy() bool {
+	return c.Subject == "" && c.LegacyUserID != nil
+}
+
+// upgrade convierte los claims del formato anterior al contrato actual
+func (c tokenClaims) upgrade() Claims {
+	claims := c.Claims
+	claims.Subject = strconv.FormatUint(uint64(*c.LegacyUserID), 10)
+
+	switch {
+	case c.LegacyIsAdmin || c.LegacyRole == RoleAdmin:
+		claims.Roles = []string{RoleAdmin}
+	case c.LegacyRole != "":
+		claims.Roles = []string{c.LegacyRole}
+	default:
+		claims.Roles = []string{RoleUser}
+	}
+
+	return claims
+}
@@ -0,0 +1,236 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/yourusername/gym-management/shared/httpclient"
+)
+
+// Algoritmos de firma aceptados para los access tokens
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// JWKSPath es la ruta donde users-api publica el JWKS
+const JWKSPath = "/.well-known/jwks.json"
+
+// SigningAlgorithms son los únicos "alg" que los middlewares deben aceptar
+// (evita tokens "none" o HS256 firmados con una clave pública)
+var SigningAlgorithms = []string{AlgRS256, AlgEdDSA}
+
+// JWK representa una clave pública en formato JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"` // OKP (Ed25519)
+	X   string `json:"x,omitempty"`   // OKP (Ed25519)
+	N   string `json:"n,omitempty"`   // RSA
+	E   string `json:"e,omitempty"`   // RSA
+}
+
+// JWKSet es el documento publicado en /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// AlgorithmFor devuelve el "alg" JWT correspondiente a una clave pública
+func AlgorithmFor(pub crypto.PublicKey) (string, error) {
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		return AlgRS256, nil
+	case ed25519.PublicKey:
+		return AlgEdDSA, nil
+	default:
+		return "", fmt.Errorf("tipo de clave no soportado: %T", pub)
+	}
+}
+
+// NewJWK convierte una clave pública RSA o Ed25519 a JWK
+func NewJWK(kid string, pub crypto.PublicKey) (JWK, error) {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: AlgRS256,
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: kid,
+			Use: "sig",
+			Alg: AlgEdDSA,
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key),
+		}, nil
+	default:
+		return JWK{}, fmt.Errorf("tipo de clave no soportado: %T", pub)
+	}
+}
+
+// PublicKey reconstruye la clave pública a partir del JWK
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("módulo RSA inválido: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("exponente RSA inválido: %w", err)
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("curva no soportada: %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("clave Ed25519 inválida")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("tipo de clave no soportado: %s", k.Kty)
+	}
+}
+
+// KeyProvider resuelve la clave pública con la que verificar un token según su "kid" y "alg"
+type KeyProvider interface {
+	PublicKey(kid, alg string) (crypto.PublicKey, error)
+}
+
+// cachedKey es una clave pública ya parseada junto con su algoritmo
+type cachedKey struct {
+	alg string
+	key crypto.PublicKey
+}
+
+// RemoteKeySet obtiene y cachea el JWKS publicado por users-api
+// - Las claves se refrescan cuando vence el TTL del cache
+// - Un "kid" desconocido fuerza un refresh (rotación), como máximo una vez cada minRefresh
+// - Si users-api no responde se siguen usando las claves cacheadas
+// Los timeouts, reintentos y el circuit breaker los pone httpclient
+type RemoteKeySet struct {
+	client     *httpclient.Client
+	path       string
+	ttl        time.Duration
+	minRefresh time.Duration
+
+	mu          sync.RWMutex
+	keys        map[string]cachedKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+// NewRemoteKeySet crea un KeyProvider que consulta el JWKS en path (normalmente JWKSPath) del cliente de users-api
+func NewRemoteKeySet(client *httpclient.Client, path string, ttl time.Duration) *RemoteKeySet {
+	if ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+
+	return &RemoteKeySet{
+		client:     client,
+		path:       path,
+		ttl:        ttl,
+		minRefresh: 10 * time.Second,
+		keys:       make(map[string]cachedKey),
+	}
+}
+
+// PublicKey devuelve la clave pública para el kid, validando que coincida el algoritmo
+func (s *RemoteKeySet) PublicKey(kid, alg string) (crypto.PublicKey, error) {
+	if kid == "" {
+		return nil, errors.New("token sin kid")
+	}
+
+	s.mu.RLock()
+	cached, ok := s.keys[kid]
+	stale := time.Since(s.fetchedAt) > s.ttl
+	s.mu.RUnlock()
+
+	if !ok || stale {
+		if err := s.refresh(context.Background()); err != nil && !ok {
+			return nil, fmt.Errorf("no se pudo obtener el JWKS: %w", err)
+		}
+
+		s.mu.RLock()
+		cached, ok = s.keys[kid]
+		s.mu.RUnlock()
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("kid desconocido: %s", kid)
+	}
+	if cached.alg != alg {
+		return nil, fmt.Errorf("algoritmo %s no corresponde a la clave %s", alg, kid)
+	}
+
+	return cached.key, nil
+}
+
+// refresh descarga el JWKS y reemplaza el cache (rate-limited por minRefresh)
+func (s *RemoteKeySet) refresh(ctx context.Context) error {
+	s.mu.Lock()
+	if time.Since(s.lastAttempt) < s.minRefresh {
+		s.mu.Unlock()
+		return errors.New("refresh de JWKS demasiado frecuente")
+	}
+	s.lastAttempt = time.Now()
+	s.mu.Unlock()
+
+	resp, err := s.client.Get(ctx, s.path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("users-api respondió con status %d", resp.StatusCode)
+	}
+
+	var set JWKSet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("JWKS inválido: %w", err)
+	}
+
+	keys := make(map[string]cachedKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		pub, err := jwk.PublicKey()
+		if err != nil {
+			continue // Se ignoran claves que no sabemos usar
+		}
+		alg, err := AlgorithmFor(pub)
+		if err != nil || (jwk.Alg != "" && jwk.Alg != alg) {
+			continue
+		}
+		keys[jwk.Kid] = cachedKey{alg: alg, key: pub}
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	s.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yourusername/gym-management/shared/httpclient"
+)
+
+func TestJWK_RoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Error generating RSA key: %v", err)
+	}
+	edPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating Ed25519 key: %v", err)
+	}
+
+	rsaJWK, err := NewJWK("rsa-1", &rsaKey.PublicKey)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rsaJWK.Alg != AlgRS256 || rsaJWK.Kty != "RSA" {
+		t.Errorf("Expected RSA/RS256 JWK, got %s/%s", rsaJWK.Kty, rsaJWK.Alg)
+	}
+	pub, err := rsaJWK.PublicKey()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !rsaKey.PublicKey.Equal(pub) {
+		t.Error("Expected RSA public key to survive the JWK round trip")
+	}
+
+	edJWK, err := NewJWK("ed-1", edPub)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if edJWK.Alg != AlgEdDSA || edJWK.Crv != "Ed25519" {
+		t.Errorf("Expected OKP/EdDSA JWK, got %s/%s", edJWK.Kty, edJWK.Alg)
+	}
+	pub, err = edJWK.PublicKey()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !edPub.Equal(pub) {
+		t.Error("Expected Ed25519 public key to survive the JWK round trip")
+	}
+}
+
+// newUsersAPIClient crea el cliente hacia el users-api de prueba (backoff corto para no demorar los tests)
+func newUsersAPIClient(url string) *httpclient.Client {
+	return httpclient.New("users-api-test", httpclient.Config{URLs: []string{url}, BackoffBase: time.Millisecond})
+}
+
+// newJWKSServer publica el JWKS devuelto por keys() y cuenta los requests
+func newJWKSServer(t *testing.T, keys func() []JWK, hits *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		_ = json.NewEncoder(w).Encode(JWKSet{Keys: keys()})
+	}))
+}
+
+func TestRemoteKeySet_PublicKey(t *testing.T) {
+	edPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	jwk, _ := NewJWK("ed-1", edPub)
+
+	var hits int32
+	server := newJWKSServer(t, func() []JWK { return []JWK{jwk} }, &hits)
+	defer server.Close()
+
+	keySet := NewRemoteKeySet(newUsersAPIClient(server.URL), JWKSPath, time.Minute)
+
+	pub, err := keySet.PublicKey("ed-1", AlgEdDSA)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !edPub.Equal(pub) {
+		t.Error("Expected the published Ed25519 key")
+	}
+
+	// Segunda consulta sale del cache
+	if _, err := keySet.PublicKey("ed-1", AlgEdDSA); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if atomic.LoadInt32(&hits) != 1 {
+		t.Errorf("Expected 1 JWKS request, got %d", hits)
+	}
+
+	// Un alg distinto al de la clave se rechaza (alg confusion)
+	if _, err := keySet.PublicKey("ed-1", "HS256"); err == nil {
+		t.Error("Expected error for algorithm mismatch")
+	}
+
+	if _, err := keySet.PublicKey("", AlgEdDSA); err == nil {
+		t.Error("Expected error for missing kid")
+	}
+}
+
+func TestRemoteKeySet_RefreshesOnUnknownKid(t *testing.T) {
+	oldPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	newPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	oldJWK, _ := NewJWK("old", oldPub)
+	newJWK, _ := NewJWK("new", newPub)
+
+	var rotated atomic.Bool
+	var hits int32
+	server := newJWKSServer(t, func() []JWK {
+		if rotated.Load() {
+			return []JWK{oldJWK, newJWK}
+		}
+		return []JWK{oldJWK}
+	}, &hits)
+	defer server.Close()
+
+	keySet := NewRemoteKeySet(newUsersAPIClient(server.URL), JWKSPath, time.Hour)
+	keySet.minRefresh = 0
+
+	if _, err := keySet.PublicKey("old", AlgEdDSA); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// users-api rota la clave: el kid nuevo fuerza un refresh aunque el cache no venció
+	rotated.Store(true)
+	pub, err := keySet.PublicKey("new", AlgEdDSA)
+	if err != nil {
+		t.Fatalf("Expected rotated key to be fetched, got %v", err)
+	}
+	if !newPub.Equal(pub) {
+		t.Error("Expected the new Ed25519 key")
+	}
+}
+
+func TestRemoteKeySet_KeepsCacheWhenUnavailable(t *testing.T) {
+	edPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	jwk, _ := NewJWK("ed-1", edPub)
+
+	var down atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(JWKSet{Keys: []JWK{jwk}})
+	}))
+	defer server.Close()
+
+	keySet := NewRemoteKeySet(newUsersAPIClient(server.URL), JWKSPath, time.Millisecond)
+	keySet.minRefresh = 0
+
+	if _, err := keySet.PublicKey("ed-1", AlgEdDSA); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	down.Store(true)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := keySet.PublicKey("ed-1", AlgEdDSA); err != nil {
+		t.Errorf("Expected stale cached key to be used while users-api is down, got %v", err)
+	}
+	if _, err := keySet.PublicKey("unknown", AlgEdDSA); err == nil {
+		t.Error("Expected error for unknown kid while users-api is down")
+	}
+}
@@ -0,0 +1,144 @@
+package auth
+
+// Roles del sistema
+// - member: socio (cualquier usuario registrado)
+// - instructor, receptionist, branch_manager: staff, con alcance limitado a sus sucursales
+// - owner: dueño, con todos los permisos en todas las sucursales
+const (
+	RoleMember        = "member"
+	RoleInstructor    = "instructor"
+	RoleReceptionist  = "receptionist"
+	RoleBranchManager = "branch_manager"
+	RoleOwner         = "owner"
+)
+
+// Permission es una acción autorizable; los servicios la exigen con RequirePermission
+type Permission string
+
+const (
+	PermAdminAccess         Permission = "admin:access"          // Acceso de administrador (solo owner)
+	PermUsersRead           Permission = "users:read"            // Ver usuarios
+	PermUsersManage         Permission = "users:manage"          // Deshabilitar, restaurar, eliminar y desbloquear usuarios
+	PermRolesManage         Permission = "roles:manage"          // Asignar roles de staff y promover owners
+	PermActivitiesManage    Permission = "activities:manage"     // Crear, editar y eliminar actividades
+	PermRostersRead         Permission = "rosters:read"          // Ver los inscriptos de cualquier clase
+	PermOwnRostersRead      Permission = "rosters:read_own"      // Ver los inscriptos de las clases propias (instructor)
+	PermPlansManage         Permission = "plans:manage"          // Crear, editar y eliminar planes
+	PermSubscriptionsManage Permission = "subscriptions:manage"  // Tareas administrativas de suscripciones
+	PermPaymentsRead        Permission = "payments:read"         // Ver pagos de otros usuarios
+	PermPaymentsApproveCash Permission = "payments:approve_cash" // Aprobar o rechazar pagos en efectivo
+	PermPaymentsManage      Permission = "payments:manage"       // Cambiar el estado de cualquier pago
+	PermAttendanceCheckIn   Permission = "attendance:check_in"   // Registrar la asistencia de un socio (QR)
+	PermAttendanceRead      Permission = "attendance:read"       // Ver los reportes de asistencia de las clases
+)
+
+// rolePermissions son los permisos de cada rol
+// owner no figura: tiene todos los permisos
+var rolePermissions = map[string][]Permission{
+	RoleMember:     {},
+	RoleInstructor: {PermOwnRostersRead},
+	RoleReceptionist: {
+		PermUsersRead,
+		PermRostersRead,
+		PermPaymentsRead,
+		PermPaymentsApproveCash,
+		PermAttendanceCheckIn,
+	},
+	RoleBranchManager: {
+		PermUsersRead,
+		PermActivitiesManage,
+		PermRostersRead,
+		PermPaymentsRead,
+		PermPaymentsApproveCash,
+		PermAttendanceCheckIn,
+		PermAttendanceRead,
+	},
+}
+
+// legacyRoleAliases traduce los roles del modelo anterior (is_admin / role)
+var legacyRoleAliases = map[string]string{
+	RoleAdmin: RoleOwner,
+	RoleUser:  RoleMember,
+}
+
+// IsValidRole indica si el rol existe (sin contar los alias legados)
+func IsValidRole(role string) bool {
+	if role == RoleOwner {
+		return true
+	}
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// IsStaffRole indica si el rol se asigna por sucursal
+func IsStaffRole(role string) bool {
+	return role == RoleInstructor || role == RoleReceptionist || role == RoleBranchManager
+}
+
+// canonicalRole devuelve el rol actual para un alias legado (admin -> owner, user -> member)
+func canonicalRole(role string) string {
+	if canonical, ok := legacyRoleAliases[role]; ok {
+		return canonical
+	}
+	return role
+}
+
+// roleGrants indica si el rol otorga el permiso
+func roleGrants(role string, perm Permission) bool {
+	if role == RoleOwner {
+		return true
+	}
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Can indica si el usuario tiene el permiso en al menos una sucursal
+// Sirve para el chequeo de ruta; el alcance por sucursal se valida con CanInBranch
+func (c Claims) Can(perm Permission) bool {
+	for _, r := range c.Roles {
+		role := canonicalRole(r)
+		if !roleGrants(role, perm) {
+			continue
+		}
+		if !IsStaffRole(role) || len(c.RoleBranches[role]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// CanInBranch indica si el usuario tiene el permiso en la sucursal
+// Los roles de staff solo valen en las sucursales asignadas; owner vale en todas
+func (c Claims) CanInBranch(perm Permission, branchID uint) bool {
+	for _, r := range c.Roles {
+		role := canonicalRole(r)
+		if !roleGrants(role, perm) {
+			continue
+		}
+		if !IsStaffRole(role) {
+			return true
+		}
+		for _, id := range c.RoleBranches[role] {
+			if id == branchID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasGlobalPermission indica si el usuario tiene el permiso sin restricción de sucursal (owner)
+// Se usa para recursos que no pertenecen a ninguna sucursal
+func (c Claims) HasGlobalPermission(perm Permission) bool {
+	for _, r := range c.Roles {
+		role := canonicalRole(r)
+		if !IsStaffRole(role) && roleGrants(role, perm) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClaims_StaffPermissionsAreBranchScoped(t *testing.T) {
+	claims := NewClaims(10, []string{RoleMember, RoleReceptionist, RoleInstructor}, nil, "jti", time.Now(), time.Minute)
+	claims.RoleBranches = map[string][]uint{
+		RoleReceptionist: {2},
+		RoleInstructor:   {3},
+	}
+
+	if !claims.Can(PermPaymentsApproveCash) {
+		t.Error("Expected receptionist to approve cash payments")
+	}
+	if !claims.CanInBranch(PermPaymentsApproveCash, 2) {
+		t.Error("Expected permission at branch 2")
+	}
+	if claims.CanInBranch(PermPaymentsApproveCash, 3) {
+		t.Error("Expected no cash approval at branch 3 (instructor only)")
+	}
+	if !claims.CanInBranch(PermOwnRostersRead, 3) || claims.CanInBranch(PermRostersRead, 3) {
+		t.Error("Expected only own rosters at branch 3")
+	}
+	if !claims.CanInBranch(PermAttendanceCheckIn, 2) || claims.Can(PermAttendanceRead) {
+		t.Error("Expected receptionist to check members in without reading attendance reports")
+	}
+	if claims.HasGlobalPermission(PermPaymentsApproveCash) {
+		t.Error("Expected staff permissions not to be global")
+	}
+	if claims.Can(PermPlansManage) || claims.IsAdmin() {
+		t.Error("Expected receptionist not to manage plans")
+	}
+}
+
+func TestClaims_StaffRoleWithoutBranchesGrantsNothing(t *testing.T) {
+	claims := NewClaims(10, []string{RoleBranchManager}, nil, "jti", time.Now(), time.Minute)
+
+	if claims.Can(PermActivitiesManage) {
+		t.Error("Expected a staff role without branches to grant nothing")
+	}
+}
+
+func TestClaims_OwnerAndLegacyAdmin(t *testing.T) {
+	for _, role := range []string{RoleOwner, RoleAdmin} {
+		claims := NewClaims(1, []string{role}, nil, "jti", time.Now(), time.Minute)
+
+		if !claims.IsAdmin() || !claims.HasRole(RoleOwner) || claims.Role() != RoleAdmin {
+			t.Errorf("Expected %s to be owner", role)
+		}
+		if !claims.HasGlobalPermission(PermPaymentsManage) || !claims.CanInBranch(PermRostersRead, 99) {
+			t.Errorf("Expected %s to have every permission in every branch", role)
+		}
+	}
+
+	member := NewClaims(5, []string{RoleUser}, nil, "jti", time.Now(), time.Minute)
+	if !member.HasRole(RoleMember) || member.Can(PermUsersRead) {
+		t.Error("Expected legacy user to be a member without staff permissions")
+	}
+}
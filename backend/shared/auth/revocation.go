@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/yourusername/gym-management/shared/httpclient"
+)
+
+// RevocationChecker permite a los middlewares JWT consultar si un access token fue revocado
+// (logout, reutilización de refresh token, etc.) usando el claim "jti"
+type RevocationChecker interface {
+	IsRevoked(jti string) bool
+}
+
+// RevokedToken es un jti revocado tal como lo publica users-api en GET /token/revocations
+type RevokedToken struct {
+	JTI       string    `json:"jti"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// revocationsResponse es el body de GET /token/revocations
+type revocationsResponse struct {
+	Revocations []RevokedToken `json:"revocations"`
+}
+
+// RevocationList mantiene en memoria los jti revocados, sincronizados periódicamente desde users-api
+// Una revocación tarda como máximo un intervalo de sincronización en propagarse al servicio
+// Los timeouts, reintentos y el circuit breaker los pone httpclient
+type RevocationList struct {
+	client   *httpclient.Client
+	interval time.Duration
+
+	mu      sync.RWMutex
+	revoked map[string]time.Time // jti -> expiración del access token
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// NewRevocationList crea una lista de revocación que consulta a users-api (client) cada "interval"
+func NewRevocationList(client *httpclient.Client, interval time.Duration) *RevocationList {
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+
+	return &RevocationList{
+		client:   client,
+		interval: interval,
+		revoked:  make(map[string]time.Time),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start hace una primera sincronización y lanza la sincronización periódica en background
+// Si users-api no está disponible se loguea y se reintenta en el próximo ciclo
+func (l *RevocationList) Start() {
+	if err := l.Sync(context.Background()); err != nil {
+		log.Printf("⚠️  No se pudo sincronizar la lista de tokens revocados: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(l.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := l.Sync(context.Background()); err != nil {
+					log.Printf("⚠️  No se pudo sincronizar la lista de tokens revocados: %v", err)
+				}
+			case <-l.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop detiene la sincronización periódica
+func (l *RevocationList) Stop() {
+	l.stopOnce.Do(func() { close(l.stop) })
+}
+
+// Sync descarga la lista completa de revocaciones vigentes y reemplaza la copia local
+// Ante un error se conserva la última lista conocida
+func (l *RevocationList) Sync(ctx context.Context) error {
+	resp, err := l.client.Get(ctx, "/token/revocations", nil)
+	if err != nil {
+		return fmt.Errorf("error consultando users-api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("users-api respondió con status %d", resp.StatusCode)
+	}
+
+	var body revocationsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("error decodificando revocaciones: %w", err)
+	}
+
+	revoked := make(map[string]time.Time, len(body.Revocations))
+	for _, r := range body.Revocations {
+		revoked[r.JTI] = r.ExpiresAt
+	}
+
+	l.mu.Lock()
+	l.revoked = revoked
+	l.mu.Unlock()
+
+	return nil
+}
+
+// Add agrega un jti a la lista local sin esperar la próxima sincronización
+func (l *RevocationList) Add(jti string, expiresAt time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.revoked[jti] = expiresAt
+}
+
+// IsRevoked indica si el jti está revocado y su access token todavía no expiró
+func (l *RevocationList) IsRevoked(jti string) bool {
+	if jti == "" {
+		return false
+	}
+
+	l.mu.RLock()
+	expiresAt, ok := l.revoked[jti]
+	l.mu.RUnlock()
+
+	return ok && time.Now().Before(expiresAt)
+}
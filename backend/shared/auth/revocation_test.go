@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRevocationList_Sync(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/token/revocations" {
+			t.Errorf("Expected path /token/revocations, got %s", r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(revocationsResponse{
+			Revocations: []RevokedToken{
+				{JTI: "revoked-jti", ExpiresAt: time.Now().Add(10 * time.Minute)},
+				{JTI: "expired-jti", ExpiresAt: time.Now().Add(-time.Minute)},
+			},
+		})
+	}))
+	defer server.Close()
+
+	list := NewRevocationList(newUsersAPIClient(server.URL+"/"), time.Minute)
+	if err := list.Sync(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !list.IsRevoked("revoked-jti") {
+		t.Error("Expected revoked-jti to be revoked")
+	}
+	if list.IsRevoked("expired-jti") {
+		t.Error("Expected expired entry to be ignored")
+	}
+	if list.IsRevoked("other-jti") {
+		t.Error("Expected unknown jti not to be revoked")
+	}
+	if list.IsRevoked("") {
+		t.Error("Expected empty jti not to be revoked")
+	}
+}
+
+func TestRevocationList_SyncErrorKeepsPreviousList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	list := NewRevocationList(newUsersAPIClient(server.URL), time.Minute)
+	list.Add("local-jti", time.Now().Add(time.Minute))
+
+	if err := list.Sync(context.Background()); err == nil {
+		t.Fatal("Expected error when users-api is unavailable")
+	}
+
+	if !list.IsRevoked("local-jti") {
+		t.Error("Expected previous revocations to be kept after a failed sync")
+	}
+}
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// LegacyClaimsDeadline es el fin de la ventana de deprecación del formato anterior de claims
+// (id_usuario / is_admin / role). Después de esta fecha esos tokens se rechazan
+var LegacyClaimsDeadline = time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+var (
+	// ErrTokenRevoked indica que el jti del token está en la lista de revocación
+	ErrTokenRevoked = errors.New("token revocado")
+	// ErrLegacyClaims indica un token en el formato anterior fuera de la ventana de deprecación
+	ErrLegacyClaims = errors.New("formato de claims deprecado")
+)
+
+// Verifier valida access tokens: firma (JWKS), algoritmo, issuer, expiración, revocación y claims
+// Es el único punto donde los servicios parsean tokens, para que todos lean el mismo contrato
+type Verifier struct {
+	keys        KeyProvider
+	revocations RevocationChecker
+	now         func() time.Time
+}
+
+// NewVerifier crea un Verifier
+// revocations puede ser nil (sin chequeo de revocación, ej: users-api consulta su propia BD)
+func NewVerifier(keys KeyProvider, revocations RevocationChecker) *Verifier {
+	return &Verifier{
+		keys:        keys,
+		revocations: revocations,
+		now:         time.Now,
+	}
+}
+
+// Verify valida el token y devuelve sus claims en el formato actual
+func (v *Verifier) Verify(tokenString string) (*Claims, error) {
+	parsed := &tokenClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, parsed, func(token *jwt.Token) (interface{}, error) {
+		// La clave se elige por kid y debe corresponder al algoritmo del header
+		kid, _ := token.Header["kid"].(string)
+		return v.keys.PublicKey(kid, token.Method.Alg())
+	},
+		jwt.WithValidMethods(SigningAlgorithms),
+		jwt.WithIssuer(Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(v.now),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := parsed.Claims
+	if parsed.isLegacy() {
+		if v.now().After(LegacyClaimsDeadline) {
+			return nil, ErrLegacyClaims
+		}
+		if *parsed.LegacyUserID < 1 {
+			return nil, errors.New("claim id_usuario inválido")
+		}
+		claims = parsed.upgrade()
+	}
+
+	if _, err := claims.UserID(); err != nil {
+		return nil, err
+	}
+	if claims.ID == "" {
+		return nil, errors.New("token sin jti")
+	}
+
+	if v.revocations != nil && v.revocations.IsRevoked(claims.ID) {
+		return nil, ErrTokenRevoked
+	}
+
+	return &claims, nil
+}
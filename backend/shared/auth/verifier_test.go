@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// staticKeys es un KeyProvider con una única clave Ed25519
+type staticKeys struct {
+	kid  string
+	priv ed25519.PrivateKey
+}
+
+func newStaticKeys(t *testing.T) *staticKeys {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	return &staticKeys{kid: "test", priv: priv}
+}
+
+func (k *staticKeys) PublicKey(kid, alg string) (crypto.PublicKey, error) {
+	if kid != k.kid || alg != AlgEdDSA {
+		return nil, errors.New("unknown key")
+	}
+	return k.priv.Public(), nil
+}
+
+func (k *staticKeys) sign(t *testing.T, claims jwt.Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = k.kid
+	tokenString, err := token.SignedString(k.priv)
+	if err != nil {
+		t.Fatalf("Error signing token: %v", err)
+	}
+	return tokenString
+}
+
+// revokedSet es un RevocationChecker en memoria
+type revokedSet map[string]bool
+
+func (r revokedSet) IsRevoked(jti string) bool { return r[jti] }
+
+func TestVerifier_CurrentClaims(t *testing.T) {
+	keys := newStaticKeys(t)
+	verifier := NewVerifier(keys, revokedSet{})
+
+	claims := NewClaims(42, []string{RoleAdmin}, []uint{1, 3}, "jti-1", time.Now(), 30*time.Minute)
+	got, err := verifier.Verify(keys.sign(t, claims))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	userID, err := got.UserID()
+	if err != nil || userID != 42 {
+		t.Errorf("Expected user 42, got %d (%v)", userID, err)
+	}
+	if !got.IsAdmin() || got.Role() != RoleAdmin {
+		t.Errorf("Expected admin role, got %v", got.Roles)
+	}
+	if len(got.BranchIDs) != 2 || got.BranchIDs[1] != 3 {
+		t.Errorf("Expected branch ids [1 3], got %v", got.BranchIDs)
+	}
+}
+
+func TestVerifier_LegacyClaims(t *testing.T) {
+	keys := newStaticKeys(t)
+	verifier := NewVerifier(keys, nil)
+
+	legacy := jwt.MapClaims{
+		"iss":        Issuer,
+		"jti":        "jti-legacy",
+		"iat":        LegacyClaimsDeadline.Add(-2 * time.Hour).Unix(),
+		"exp":        LegacyClaimsDeadline.Unix(),
+		"id_usuario": 7,
+		"username":   "juanperez",
+		"is_admin":   false,
+		"role":       "user",
+	}
+
+	// Dentro de la ventana de deprecación se traduce al contrato actual
+	verifier.now = func() time.Time { return LegacyClaimsDeadline.Add(-time.Hour) }
+	tokenString := keys.sign(t, legacy)
+
+	got, err := verifier.Verify(tokenString)
+	if err != nil {
+		t.Fatalf("Expected legacy token to be accepted, got %v", err)
+	}
+	if got.Subject != "7" || got.Role() != RoleUser {
+		t.Errorf("Expected sub 7 with role user, got %s %v", got.Subject, got.Roles)
+	}
+
+	// Después de la fecha límite se rechaza
+	legacy["exp"] = LegacyClaimsDeadline.Add(2 * time.Hour).Unix()
+	tokenString = keys.sign(t, legacy)
+	verifier.now = func() time.Time { return LegacyClaimsDeadline.Add(time.Hour) }
+
+	if _, err := verifier.Verify(tokenString); !errors.Is(err, ErrLegacyClaims) {
+		t.Errorf("Expected ErrLegacyClaims after the deadline, got %v", err)
+	}
+}
+
+func TestVerifier_Rejects(t *testing.T) {
+	keys := newStaticKeys(t)
+	verifier := NewVerifier(keys, revokedSet{"revoked": true})
+	now := time.Now()
+
+	revoked := keys.sign(t, NewClaims(1, []string{RoleUser}, nil, "revoked", now, time.Minute))
+	if _, err := verifier.Verify(revoked); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("Expected ErrTokenRevoked, got %v", err)
+	}
+
+	expired := keys.sign(t, NewClaims(1, []string{RoleUser}, nil, "jti", now.Add(-time.Hour), time.Minute))
+	if _, err := verifier.Verify(expired); err == nil {
+		t.Error("Expected error for expired token")
+	}
+
+	noSubject := NewClaims(1, []string{RoleUser}, nil, "jti", now, time.Minute)
+	noSubject.Subject = ""
+	if _, err := verifier.Verify(keys.sign(t, noSubject)); err == nil {
+		t.Error("Expected error for token without sub")
+	}
+
+	otherIssuer := NewClaims(1, []string{RoleUser}, nil, "jti", now, time.Minute)
+	otherIssuer.Issuer = "someone-else"
+	if _, err := verifier.Verify(keys.sign(t, otherIssuer)); err == nil {
+		t.Error("Expected error for unknown issuer")
+	}
+
+	// HS256 firmado con un secreto nunca se acepta
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, NewClaims(1, []string{RoleAdmin}, nil, "jti", now, time.Minute))
+	hmacToken.Header["kid"] = keys.kid
+	hmacString, _ := hmacToken.SignedString([]byte("secret"))
+	if _, err := verifier.Verify(hmacString); err == nil {
+		t.Error("Expected error for HS256 token")
+	}
+}
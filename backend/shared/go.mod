@@ -0,0 +1,5 @@
+module github.com/yourusername/gym-management/shared
+
+go 1.23
+
+require github.com/golang-jwt/jwt/v5 v5.2.1
@@ -0,0 +1,106 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+)
+
+// Estados del circuit breaker
+const (
+	StateClosed   = "closed"    // Las llamadas pasan
+	StateOpen     = "open"      // Las llamadas fallan sin salir (ErrCircuitOpen) hasta OpenTimeout
+	StateHalfOpen = "half_open" // Pasa una sola llamada de prueba: si anda se cierra, si falla se vuelve a abrir
+)
+
+// breaker es el circuit breaker de una instancia del servicio
+type breaker struct {
+	threshold   int
+	openTimeout time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	state    string
+	failures int       // Fallos consecutivos
+	openedAt time.Time // Momento en que se abrió
+	probing  bool      // Hay una llamada de prueba en curso (half-open)
+}
+
+func newBreaker(threshold int, openTimeout time.Duration, now func() time.Time) *breaker {
+	return &breaker{
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		now:         now,
+		state:       StateClosed,
+	}
+}
+
+// allow indica si la llamada puede salir; en half-open reserva la llamada de prueba
+// Si devuelve true, el llamador debe informar el resultado con success, failure o release
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return false
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+		return true
+	case StateHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// success registra una respuesta de la instancia (cualquier status < 500)
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = StateClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// failure registra un error de red, timeout o 5xx; devuelve true si el circuito se abrió
+func (b *breaker) failure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		opened := b.state != StateOpen
+		b.state = StateOpen
+		b.openedAt = b.now()
+		b.probing = false
+		return opened
+	}
+	return false
+}
+
+// release libera la llamada de prueba sin resultado (el llamador canceló el contexto)
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// snapshot devuelve el estado y los fallos consecutivos
+func (b *breaker) snapshot() (string, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := b.state
+	if state == StateOpen && b.now().Sub(b.openedAt) >= b.openTimeout {
+		state = StateHalfOpen // La próxima llamada es la de prueba
+	}
+	return state, b.failures
+}
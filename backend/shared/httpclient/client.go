@@ -0,0 +1,285 @@
+// Package httpclient es el cliente HTTP para las llamadas entre servicios:
+// deadline por llamada y por intento, reintentos con backoff para los GET, circuit breaker por instancia,
+// bulkhead (límite de llamadas en curso) y métricas expuestas con MetricsHandler
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrCircuitOpen indica que todas las instancias del servicio tienen el circuito abierto
+	ErrCircuitOpen = errors.New("circuit breaker abierto")
+	// ErrBulkheadFull indica que se alcanzó el máximo de llamadas en curso hacia el servicio
+	ErrBulkheadFull = errors.New("demasiadas llamadas en curso")
+	// ErrNoEndpoints indica que el cliente no tiene URLs configuradas
+	ErrNoEndpoints = errors.New("sin URLs configuradas")
+)
+
+// Client hace las llamadas a un servicio (una o más instancias)
+// Es seguro para uso concurrente; se crea uno por servicio destino y se comparte
+type Client struct {
+	name      string
+	cfg       Config
+	endpoints []*endpoint
+	next      atomic.Uint64 // Round robin
+	bulkhead  chan struct{}
+	http      *http.Client
+	metrics   metrics
+}
+
+// endpoint es una instancia del servicio con su circuit breaker
+type endpoint struct {
+	url     string
+	breaker *breaker
+}
+
+// New crea un cliente hacia el servicio "name" y lo registra para MetricsHandler
+func New(name string, cfg Config) *Client {
+	cfg = cfg.withDefaults()
+
+	c := &Client{
+		name:     name,
+		cfg:      cfg,
+		bulkhead: make(chan struct{}, cfg.MaxConcurrent),
+		http:     &http.Client{}, // Sin timeout: lo ponen los contextos de la llamada y del intento
+	}
+	for _, url := range cfg.URLs {
+		if url = strings.TrimRight(strings.TrimSpace(url), "/"); url != "" {
+			c.endpoints = append(c.endpoints, &endpoint{
+				url:     url,
+				breaker: newBreaker(cfg.FailureThreshold, cfg.OpenTimeout, time.Now),
+			})
+		}
+	}
+
+	register(c)
+	return c
+}
+
+// Name devuelve el nombre del servicio destino
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Get hace un GET a path (ej. "/plans/123") con reintentos
+func (c *Client) Get(ctx context.Context, path string, header http.Header) (*http.Response, error) {
+	return c.Do(ctx, http.MethodGet, path, header, nil)
+}
+
+// Do hace la llamada a path en alguna instancia del servicio
+// Solo GET y HEAD se reintentan (ante errores de red, timeouts del intento, 429, 502, 503 y 504);
+// si se agotan los reintentos se devuelve la última respuesta. El llamador debe cerrar resp.Body:
+// hasta entonces la llamada ocupa su lugar en el bulkhead
+func (c *Client) Do(ctx context.Context, method, path string, header http.Header, body []byte) (*http.Response, error) {
+	if len(c.endpoints) == 0 {
+		return nil, fmt.Errorf("%s: %w", c.name, ErrNoEndpoints)
+	}
+
+	c.metrics.requests.Add(1)
+	select {
+	case c.bulkhead <- struct{}{}:
+	default:
+		c.metrics.bulkheadRejected.Add(1)
+		return nil, fmt.Errorf("%s: %w", c.name, ErrBulkheadFull)
+	}
+	c.metrics.inFlight.Add(1)
+
+	start := time.Now()
+	callCtx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	done := func() {
+		cancel()
+		c.metrics.inFlight.Add(-1)
+		<-c.bulkhead
+	}
+
+	resp, err := c.do(ctx, callCtx, method, path, header, body)
+	if err != nil {
+		done()
+		if !errors.Is(err, ErrCircuitOpen) {
+			c.metrics.observe(start, false)
+		}
+		return nil, err
+	}
+
+	c.metrics.observe(start, resp.StatusCode < http.StatusInternalServerError)
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: done}
+	return resp, nil
+}
+
+// Stats devuelve las métricas del cliente
+func (c *Client) Stats() Stats {
+	stats := Stats{
+		Name:             c.name,
+		Requests:         c.metrics.requests.Load(),
+		Successes:        c.metrics.successes.Load(),
+		Failures:         c.metrics.failures.Load(),
+		Attempts:         c.metrics.attempts.Load(),
+		Retries:          c.metrics.retries.Load(),
+		ShortCircuited:   c.metrics.shortCircuited.Load(),
+		BulkheadRejected: c.metrics.bulkheadRejected.Load(),
+		InFlight:         c.metrics.inFlight.Load(),
+		Endpoints:        make([]EndpointStats, len(c.endpoints)),
+	}
+	if completed := stats.Successes + stats.Failures; completed > 0 {
+		stats.AvgLatencyMs = float64(c.metrics.latencyNanos.Load()) / float64(completed) / float64(time.Millisecond)
+	}
+	for i, ep := range c.endpoints {
+		state, failures := ep.breaker.snapshot()
+		stats.Endpoints[i] = EndpointStats{URL: ep.url, State: state, ConsecutiveFailures: failures}
+	}
+	return stats
+}
+
+// do hace los intentos; ctx es el del llamador y callCtx el que tiene el deadline de la llamada
+func (c *Client) do(ctx, callCtx context.Context, method, path string, header http.Header, body []byte) (*http.Response, error) {
+	attempts := 1
+	if method == http.MethodGet || method == http.MethodHead {
+		attempts += c.cfg.MaxRetries
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := sleep(callCtx, c.backoff(attempt)); err != nil {
+				return nil, lastErr
+			}
+			c.metrics.retries.Add(1)
+		}
+
+		ep := c.pick()
+		if ep == nil {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			c.metrics.shortCircuited.Add(1)
+			return nil, fmt.Errorf("%s: %w", c.name, ErrCircuitOpen)
+		}
+
+		resp, err := c.attempt(callCtx, ep, method, path, header, body)
+		if err != nil {
+			if ctx.Err() != nil {
+				// El llamador canceló: no es culpa de la instancia
+				ep.breaker.release()
+				return nil, fmt.Errorf("%s: %w", c.name, ctx.Err())
+			}
+			c.fail(ep)
+			lastErr = fmt.Errorf("%s: %w", c.name, err)
+			continue
+		}
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			c.fail(ep)
+		} else {
+			ep.breaker.success()
+		}
+
+		if attempt < attempts-1 && retryableStatus(resp.StatusCode) {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+			lastErr = fmt.Errorf("%s: %s %s respondió %d", c.name, method, path, resp.StatusCode)
+			continue
+		}
+		return resp, nil
+	}
+	return nil, lastErr
+}
+
+// attempt hace un intento contra una instancia con el deadline del intento
+func (c *Client) attempt(callCtx context.Context, ep *endpoint, method, path string, header http.Header, body []byte) (*http.Response, error) {
+	attemptCtx, cancel := context.WithTimeout(callCtx, c.cfg.AttemptTimeout)
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(attemptCtx, method, ep.url+path, reader)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("error creando request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	c.metrics.attempts.Add(1)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: cancel}
+	return resp, nil
+}
+
+// pick elige la próxima instancia en round robin salteando las que tienen el circuito abierto
+func (c *Client) pick() *endpoint {
+	start := c.next.Add(1) - 1
+	for i := range c.endpoints {
+		ep := c.endpoints[(start+uint64(i))%uint64(len(c.endpoints))]
+		if ep.breaker.allow() {
+			return ep
+		}
+	}
+	return nil
+}
+
+func (c *Client) fail(ep *endpoint) {
+	if ep.breaker.failure() {
+		log.Printf("⚠️  [httpclient] %s: circuito abierto para %s por %s", c.name, ep.url, c.cfg.OpenTimeout)
+	}
+}
+
+// backoff devuelve la espera antes del reintento: exponencial acotado con jitter completo
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.cfg.BackoffBase << (attempt - 1)
+	if d <= 0 || d > c.cfg.BackoffMax {
+		d = c.cfg.BackoffMax
+	}
+	return rand.N(d) + 1
+}
+
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// releaseBody libera los recursos de la llamada (contexto, bulkhead) al cerrar el body
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,326 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer responde con los status de "fallos" (uno por llamada) y después 200
+// delay se aplica a las llamadas indicadas en slow (índice desde 0)
+type flakyServer struct {
+	*httptest.Server
+	calls atomic.Int32
+}
+
+func newFlakyServer(t *testing.T, fallos []int, slow map[int]time.Duration) *flakyServer {
+	t.Helper()
+	s := &flakyServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(s.calls.Add(1)) - 1
+		if d, ok := slow[n]; ok {
+			select {
+			case <-time.After(d):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if n < len(fallos) {
+			w.WriteHeader(fallos[n])
+			return
+		}
+		_, _ = io.WriteString(w, `{"ok":true}`)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func testConfig(urls ...string) Config {
+	return Config{
+		URLs:           urls,
+		Timeout:        time.Second,
+		AttemptTimeout: 500 * time.Millisecond,
+		BackoffBase:    time.Millisecond,
+		BackoffMax:     5 * time.Millisecond,
+	}
+}
+
+func getStatus(t *testing.T, c *Client) (int, error) {
+	t.Helper()
+	resp, err := c.Get(context.Background(), "/recurso", nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.ReadAll(resp.Body)
+	return resp.StatusCode, nil
+}
+
+func TestClient_RetriesGETOnTransientErrors(t *testing.T) {
+	server := newFlakyServer(t, []int{http.StatusServiceUnavailable, http.StatusBadGateway}, nil)
+	client := New(t.Name(), testConfig(server.URL))
+
+	status, err := getStatus(t, client)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if status != http.StatusOK {
+		t.Errorf("Expected 200 after retries, got %d", status)
+	}
+
+	stats := client.Stats()
+	if stats.Attempts != 3 || stats.Retries != 2 {
+		t.Errorf("Expected 3 attempts and 2 retries, got %d and %d", stats.Attempts, stats.Retries)
+	}
+	if stats.Successes != 1 || stats.Failures != 0 || stats.InFlight != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestClient_ReturnsLastResponseWhenRetriesExhausted(t *testing.T) {
+	server := newFlakyServer(t, []int{503, 503, 503, 503}, nil)
+	client := New(t.Name(), testConfig(server.URL))
+
+	status, err := getStatus(t, client)
+	if err != nil {
+		t.Fatalf("Expected the last response, got error %v", err)
+	}
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", status)
+	}
+	if calls := server.calls.Load(); calls != 1+DefaultMaxRetries {
+		t.Errorf("Expected %d attempts, got %d", 1+DefaultMaxRetries, calls)
+	}
+}
+
+func TestClient_DoesNotRetryNonIdempotentOrClientErrors(t *testing.T) {
+	server := newFlakyServer(t, []int{503}, nil)
+	client := New(t.Name(), testConfig(server.URL))
+
+	resp, err := client.Do(context.Background(), http.MethodPost, "/recurso", nil, []byte(`{}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || server.calls.Load() != 1 {
+		t.Errorf("Expected a single POST answered 503, got %d after %d calls", resp.StatusCode, server.calls.Load())
+	}
+
+	notFound := newFlakyServer(t, []int{404}, nil)
+	client = New(t.Name()+"/404", testConfig(notFound.URL))
+	if status, _ := getStatus(t, client); status != http.StatusNotFound || notFound.calls.Load() != 1 {
+		t.Errorf("Expected a single GET answered 404, got %d after %d calls", status, notFound.calls.Load())
+	}
+}
+
+func TestClient_AttemptTimeoutRetriesSlowResponse(t *testing.T) {
+	server := newFlakyServer(t, nil, map[int]time.Duration{0: 300 * time.Millisecond})
+	cfg := testConfig(server.URL)
+	cfg.AttemptTimeout = 50 * time.Millisecond
+	client := New(t.Name(), cfg)
+
+	status, err := getStatus(t, client)
+	if err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if status != http.StatusOK || client.Stats().Retries != 1 {
+		t.Errorf("Expected 200 after 1 retry, got %d after %d", status, client.Stats().Retries)
+	}
+}
+
+func TestClient_CallDeadlineBoundsRetries(t *testing.T) {
+	slow := map[int]time.Duration{}
+	for i := 0; i < 10; i++ {
+		slow[i] = time.Second
+	}
+	server := newFlakyServer(t, nil, slow)
+	cfg := testConfig(server.URL)
+	cfg.Timeout = 120 * time.Millisecond
+	cfg.AttemptTimeout = 50 * time.Millisecond
+	cfg.MaxRetries = 10
+	client := New(t.Name(), cfg)
+
+	start := time.Now()
+	if _, err := getStatus(t, client); err == nil {
+		t.Fatal("Expected a timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the call to stop at its deadline, took %s", elapsed)
+	}
+	if stats := client.Stats(); stats.Failures != 1 || stats.InFlight != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestClient_CircuitBreakerOpensAndProbes(t *testing.T) {
+	server := newFlakyServer(t, []int{500, 500, 500}, nil)
+	cfg := testConfig(server.URL)
+	cfg.MaxRetries = -1
+	cfg.FailureThreshold = 2
+	cfg.OpenTimeout = 80 * time.Millisecond
+	client := New(t.Name(), cfg)
+
+	for i := 0; i < 2; i++ {
+		if status, _ := getStatus(t, client); status != http.StatusInternalServerError {
+			t.Fatalf("Expected 500, got %d", status)
+		}
+	}
+	if state := client.Stats().Endpoints[0].State; state != StateOpen {
+		t.Fatalf("Expected open circuit after 2 failures, got %s", state)
+	}
+
+	if _, err := getStatus(t, client); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if server.calls.Load() != 2 {
+		t.Errorf("Expected the open circuit not to call the server, got %d calls", server.calls.Load())
+	}
+
+	// La prueba de half-open falla: vuelve a abrirse
+	time.Sleep(100 * time.Millisecond)
+	if status, _ := getStatus(t, client); status != http.StatusInternalServerError {
+		t.Fatalf("Expected the probe to reach the server, got %d", status)
+	}
+	if _, err := getStatus(t, client); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected the failed probe to reopen the circuit, got %v", err)
+	}
+
+	// La siguiente prueba anda: se cierra
+	time.Sleep(100 * time.Millisecond)
+	if status, err := getStatus(t, client); err != nil || status != http.StatusOK {
+		t.Fatalf("Expected the probe to succeed, got %d, %v", status, err)
+	}
+	stats := client.Stats()
+	if stats.Endpoints[0].State != StateClosed || stats.ShortCircuited != 2 {
+		t.Errorf("Expected closed circuit and 2 short-circuited calls, got %+v", stats)
+	}
+}
+
+func TestBreaker_HalfOpenAllowsSingleProbe(t *testing.T) {
+	b := newBreaker(1, time.Minute, time.Now)
+	b.failure()
+	b.openedAt = time.Now().Add(-2 * time.Minute)
+
+	if !b.allow() {
+		t.Fatal("Expected the first call after the open timeout to be the probe")
+	}
+	if b.allow() {
+		t.Error("Expected a second call to be rejected while the probe is in flight")
+	}
+	b.release()
+	if !b.allow() {
+		t.Error("Expected a released probe to let the next call through")
+	}
+}
+
+func TestClient_FailsOverToHealthyInstance(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close() // Conexión rechazada
+	healthy := newFlakyServer(t, nil, nil)
+
+	client := New(t.Name(), testConfig(down.URL, healthy.URL))
+	for i := 0; i < 4; i++ {
+		if status, err := getStatus(t, client); err != nil || status != http.StatusOK {
+			t.Fatalf("Expected every call to reach the healthy instance, got %d, %v", status, err)
+		}
+	}
+	if healthy.calls.Load() != 4 {
+		t.Errorf("Expected 4 calls on the healthy instance, got %d", healthy.calls.Load())
+	}
+}
+
+func TestClient_BulkheadRejectsWhenFull(t *testing.T) {
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-unblock
+	}))
+	defer server.Close()
+
+	cfg := testConfig(server.URL)
+	cfg.MaxConcurrent = 1
+	client := New(t.Name(), cfg)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := getStatus(t, client)
+		result <- err
+	}()
+	<-entered
+
+	if _, err := client.Get(context.Background(), "/recurso", nil); !errors.Is(err, ErrBulkheadFull) {
+		t.Errorf("Expected ErrBulkheadFull, got %v", err)
+	}
+
+	close(unblock)
+	if err := <-result; err != nil {
+		t.Fatalf("Expected the first call to succeed, got %v", err)
+	}
+	go func() { <-entered }()
+	if _, err := getStatus(t, client); err != nil {
+		t.Errorf("Expected the slot to be released after closing the body, got %v", err)
+	}
+	if stats := client.Stats(); stats.BulkheadRejected != 1 || stats.InFlight != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestClient_CallerCancellationDoesNotOpenCircuit(t *testing.T) {
+	server := newFlakyServer(t, nil, map[int]time.Duration{0: time.Second})
+	cfg := testConfig(server.URL)
+	cfg.FailureThreshold = 1
+	client := New(t.Name(), cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.Get(ctx, "/recurso", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the caller deadline error, got %v", err)
+	}
+	if state := client.Stats().Endpoints[0].State; state != StateClosed {
+		t.Errorf("Expected the circuit to stay closed, got %s", state)
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	New("zz-metrics-test", Config{URLs: []string{"http://localhost:1"}})
+
+	recorder := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics/clients", nil))
+
+	var body struct {
+		Clients []Stats `json:"clients"`
+	}
+	if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+		t.Fatalf("Expected JSON, got %v", err)
+	}
+	last := body.Clients[len(body.Clients)-1]
+	if last.Name != "zz-metrics-test" || len(last.Endpoints) != 1 || last.Endpoints[0].State != StateClosed {
+		t.Errorf("Unexpected metrics: %+v", last)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("USERS_API_URL", "http://users-1:8080/, http://users-2:8080")
+	t.Setenv("USERS_API_TIMEOUT", "3s")
+	t.Setenv("USERS_API_MAX_RETRIES", "-1")
+	t.Setenv("USERS_API_MAX_CONCURRENT", "not-a-number")
+
+	cfg := ConfigFromEnv("USERS_API", "http://localhost:8080")
+	if len(cfg.URLs) != 2 || cfg.URLs[0] != "http://users-1:8080" || cfg.URLs[1] != "http://users-2:8080" {
+		t.Errorf("Unexpected URLs: %v", cfg.URLs)
+	}
+	if cfg.Timeout != 3*time.Second || cfg.MaxRetries != -1 || cfg.MaxConcurrent != DefaultMaxConcurrent {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+
+	if cfg := ConfigFromEnv("PAYMENTS_API", "http://payments:8083"); len(cfg.URLs) != 1 || cfg.URLs[0] != "http://payments:8083" {
+		t.Errorf("Expected the default URL, got %v", cfg.URLs)
+	}
+}
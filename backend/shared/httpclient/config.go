@@ -0,0 +1,128 @@
+package httpclient
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Valores por defecto de Config (se aplican a los campos en cero)
+const (
+	DefaultTimeout          = 5 * time.Second
+	DefaultAttemptTimeout   = 2 * time.Second
+	DefaultMaxRetries       = 2
+	DefaultBackoffBase      = 100 * time.Millisecond
+	DefaultBackoffMax       = time.Second
+	DefaultFailureThreshold = 5
+	DefaultOpenTimeout      = 10 * time.Second
+	DefaultMaxConcurrent    = 20
+)
+
+// Config configura un Client hacia un servicio
+type Config struct {
+	// URLs son las instancias del servicio; las llamadas se reparten en round robin
+	// y un reintento pasa a la siguiente instancia
+	URLs []string
+
+	// Timeout es el deadline de la llamada completa, reintentos incluidos
+	Timeout time.Duration
+	// AttemptTimeout es el deadline de cada intento
+	AttemptTimeout time.Duration
+
+	// MaxRetries son los reintentos de los GET/HEAD ante errores de red, 429, 502, 503 y 504 (negativo = sin reintentos)
+	MaxRetries int
+	// BackoffBase y BackoffMax acotan la espera entre reintentos (backoff exponencial con jitter completo)
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+
+	// FailureThreshold son los fallos consecutivos de una instancia que abren su circuito
+	FailureThreshold int
+	// OpenTimeout es cuánto queda abierto el circuito antes de dejar pasar una llamada de prueba
+	OpenTimeout time.Duration
+
+	// MaxConcurrent es el máximo de llamadas en curso (bulkhead); las que no entran fallan con ErrBulkheadFull
+	MaxConcurrent int
+}
+
+// ConfigFromEnv lee la configuración de un servicio desde variables de entorno con el prefijo indicado:
+//
+//	{PREFIX}_URL                (una o más URLs separadas por coma)
+//	{PREFIX}_TIMEOUT            {PREFIX}_ATTEMPT_TIMEOUT  (duraciones de Go: "5s", "500ms")
+//	{PREFIX}_MAX_RETRIES        {PREFIX}_BREAKER_THRESHOLD
+//	{PREFIX}_BREAKER_OPEN_TIMEOUT                         {PREFIX}_MAX_CONCURRENT
+//
+// Las variables ausentes o inválidas toman el valor por defecto
+func ConfigFromEnv(prefix, defaultURL string) Config {
+	return Config{
+		URLs:             splitURLs(envString(prefix+"_URL", defaultURL)),
+		Timeout:          envDuration(prefix+"_TIMEOUT", DefaultTimeout),
+		AttemptTimeout:   envDuration(prefix+"_ATTEMPT_TIMEOUT", DefaultAttemptTimeout),
+		MaxRetries:       envInt(prefix+"_MAX_RETRIES", DefaultMaxRetries),
+		FailureThreshold: envInt(prefix+"_BREAKER_THRESHOLD", DefaultFailureThreshold),
+		OpenTimeout:      envDuration(prefix+"_BREAKER_OPEN_TIMEOUT", DefaultOpenTimeout),
+		MaxConcurrent:    envInt(prefix+"_MAX_CONCURRENT", DefaultMaxConcurrent),
+	}
+}
+
+// withDefaults completa los campos en cero
+func (c Config) withDefaults() Config {
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.AttemptTimeout <= 0 || c.AttemptTimeout > c.Timeout {
+		c.AttemptTimeout = min(DefaultAttemptTimeout, c.Timeout)
+	}
+	if c.MaxRetries == 0 {
+		c.MaxRetries = DefaultMaxRetries
+	} else if c.MaxRetries < 0 {
+		c.MaxRetries = 0
+	}
+	if c.BackoffBase <= 0 {
+		c.BackoffBase = DefaultBackoffBase
+	}
+	if c.BackoffMax < c.BackoffBase {
+		c.BackoffMax = max(DefaultBackoffMax, c.BackoffBase)
+	}
+	if c.FailureThreshold <= 0 {
+		c.FailureThreshold = DefaultFailureThreshold
+	}
+	if c.OpenTimeout <= 0 {
+		c.OpenTimeout = DefaultOpenTimeout
+	}
+	if c.MaxConcurrent <= 0 {
+		c.MaxConcurrent = DefaultMaxConcurrent
+	}
+	return c
+}
+
+func splitURLs(value string) []string {
+	var urls []string
+	for _, url := range strings.Split(value, ",") {
+		if url = strings.TrimRight(strings.TrimSpace(url), "/"); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+func envString(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func envDuration(key string, defaultValue time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+		return d
+	}
+	return defaultValue
+}
+
+func envInt(key string, defaultValue int) int {
+	if n, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return n
+	}
+	return defaultValue
+}
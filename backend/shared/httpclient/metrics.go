@@ -0,0 +1,87 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats son las métricas de un Client desde que arrancó el servicio
+type Stats struct {
+	Name             string          `json:"name"`
+	Requests         uint64          `json:"requests"`          // Llamadas (cada una puede tener varios intentos)
+	Successes        uint64          `json:"successes"`         // Llamadas con respuesta < 500
+	Failures         uint64          `json:"failures"`          // Llamadas que terminaron en error o 5xx
+	Attempts         uint64          `json:"attempts"`          // Intentos que salieron hacia el servicio
+	Retries          uint64          `json:"retries"`           // Intentos que fueron reintentos
+	ShortCircuited   uint64          `json:"short_circuited"`   // Llamadas rechazadas con ErrCircuitOpen
+	BulkheadRejected uint64          `json:"bulkhead_rejected"` // Llamadas rechazadas con ErrBulkheadFull
+	InFlight         int64           `json:"in_flight"`
+	AvgLatencyMs     float64         `json:"avg_latency_ms"` // Promedio por llamada, reintentos incluidos
+	Endpoints        []EndpointStats `json:"endpoints"`
+}
+
+// EndpointStats es el estado del circuit breaker de una instancia
+type EndpointStats struct {
+	URL                 string `json:"url"`
+	State               string `json:"state"` // closed | open | half_open
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+}
+
+// metrics acumula los contadores de un Client
+type metrics struct {
+	requests         atomic.Uint64
+	successes        atomic.Uint64
+	failures         atomic.Uint64
+	attempts         atomic.Uint64
+	retries          atomic.Uint64
+	shortCircuited   atomic.Uint64
+	bulkheadRejected atomic.Uint64
+	inFlight         atomic.Int64
+	latencyNanos     atomic.Int64
+}
+
+func (m *metrics) observe(start time.Time, ok bool) {
+	m.latencyNanos.Add(int64(time.Since(start)))
+	if ok {
+		m.successes.Add(1)
+	} else {
+		m.failures.Add(1)
+	}
+}
+
+// registry guarda los Client creados con New para exponer sus métricas
+var registry = struct {
+	mu      sync.RWMutex
+	clients map[string]*Client
+}{clients: make(map[string]*Client)}
+
+func register(c *Client) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.clients[c.name] = c
+}
+
+// Snapshot devuelve las métricas de todos los Client del proceso, ordenadas por nombre
+func Snapshot() []Stats {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	stats := make([]Stats, 0, len(registry.clients))
+	for _, c := range registry.clients {
+		stats = append(stats, c.Stats())
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// MetricsHandler expone Snapshot como JSON ({"clients": [...]}) para montarlo en el router del servicio
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string][]Stats{"clients": Snapshot()})
+	})
+}
@@ -5,8 +5,11 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gym-management/shared/auth"
+	"github.com/yourusername/gym-management/shared/httpclient"
 	"github.com/yourusername/gym-management/subscriptions-api/internal/clients"
 	"github.com/yourusername/gym-management/subscriptions-api/internal/config"
 	"github.com/yourusername/gym-management/subscriptions-api/internal/controllers"
@@ -40,18 +43,22 @@ func main() {
 
 	// Intentar conectar a RabbitMQ, si falla usar NullEventPublisher
 	var eventPublisher services.EventPublisher
+	var planEventPublisher services.PlanEventPublisher
 	rabbitPublisher, err := clients.NewRabbitMQEventPublisher(cfg.RabbitMQURL, cfg.RabbitMQExchange)
 	if err != nil {
 		log.Printf("⚠️  Warning: No se pudo conectar a RabbitMQ: %v", err)
 		log.Println("⚠️  Usando NullEventPublisher como fallback")
-		eventPublisher = clients.NewNullEventPublisher()
+		nullPublisher := clients.NewNullEventPublisher()
+		eventPublisher = nullPublisher
+		planEventPublisher = nullPublisher
 	} else {
 		eventPublisher = rabbitPublisher
+		planEventPublisher = rabbitPublisher
 		defer rabbitPublisher.Close()
 	}
 
 	// 5. Inicializar Services (Lógica de Negocio) con DI
-	planService := services.NewPlanService(planRepo)
+	planService := services.NewPlanService(planRepo, planEventPublisher)
 	subscriptionService := services.NewSubscriptionService(
 		subscriptionRepo,
 		planRepo,
@@ -88,12 +95,23 @@ func main() {
 	planController := controllers.NewPlanController(planService)
 	subscriptionController := controllers.NewSubscriptionController(subscriptionService, healthService)
 
+	// 8.5. Lista de tokens revocados (sincronizada periódicamente desde users-api)
+	// Cliente hacia users-api (también para el JWKS): timeouts, reintentos y circuit breaker de httpclient
+	usersAPI := httpclient.New("users-api", cfg.UsersAPI)
+	revocations := auth.NewRevocationList(usersAPI, 15*time.Second)
+	revocations.Start()
+	defer revocations.Stop()
+
+	// Claves públicas de users-api (JWKS) para verificar los JWT sin secreto compartido
+	jwtKeys := auth.NewRemoteKeySet(usersAPI, auth.JWKSPath, 10*time.Minute)
+	tokenVerifier := auth.NewVerifier(jwtKeys, revocations)
+
 	// 9. Configurar Gin Router
 	router := gin.Default()
 	router.Use(middleware.CORS())
 
 	// 10. Registrar Rutas
-	registerRoutes(router, planController, subscriptionController, cfg)
+	registerRoutes(router, planController, subscriptionController, tokenVerifier, cfg)
 
 	// 11. Configurar graceful shutdown
 	go func() {
@@ -127,6 +145,7 @@ func registerRoutes(
 	router *gin.Engine,
 	planController *controllers.PlanController,
 	subscriptionController *controllers.SubscriptionController,
+	tokenVerifier *auth.Verifier,
 	cfg *config.Config,
 ) {
 	// Health check (público)
@@ -139,10 +158,10 @@ func registerRoutes(
 		publicPlanRoutes.GET("/:id", planController.GetPlan)
 	}
 
-	// Rutas protegidas de planes (solo admins)
+	// Rutas protegidas de planes (plans:manage, solo owners)
 	protectedPlanRoutes := router.Group("/plans")
-	protectedPlanRoutes.Use(middleware.JWTAuth(cfg.JWTSecret))
-	protectedPlanRoutes.Use(middleware.RequireRole("admin"))
+	protectedPlanRoutes.Use(middleware.JWTAuth(tokenVerifier))
+	protectedPlanRoutes.Use(middleware.RequirePermission(auth.PermPlansManage))
 	{
 		protectedPlanRoutes.POST("", planController.CreatePlan)
 		protectedPlanRoutes.PUT("/:id", planController.UpdatePlan)
@@ -152,7 +171,7 @@ func registerRoutes(
 
 	// Rutas protegidas de suscripciones (requieren autenticación)
 	subscriptionRoutes := router.Group("/subscriptions")
-	subscriptionRoutes.Use(middleware.JWTAuth(cfg.JWTSecret))
+	subscriptionRoutes.Use(middleware.JWTAuth(tokenVerifier))
 	{
 		subscriptionRoutes.POST("", subscriptionController.CreateSubscription)
 		subscriptionRoutes.GET("/:id", subscriptionController.GetSubscription)
@@ -162,11 +181,12 @@ func registerRoutes(
 		subscriptionRoutes.DELETE("/:id", subscriptionController.CancelSubscription)
 	}
 
-	// Rutas admin para gestión de suscripciones
+	// Rutas admin para gestión de suscripciones (subscriptions:manage, solo owners)
 	adminSubscriptionRoutes := router.Group("/subscriptions")
-	adminSubscriptionRoutes.Use(middleware.JWTAuth(cfg.JWTSecret))
-	adminSubscriptionRoutes.Use(middleware.RequireRole("admin"))
+	adminSubscriptionRoutes.Use(middleware.JWTAuth(tokenVerifier))
+	adminSubscriptionRoutes.Use(middleware.RequirePermission(auth.PermSubscriptionsManage))
 	{
+		adminSubscriptionRoutes.GET("/active", subscriptionController.ListActiveSubscriptions)
 		adminSubscriptionRoutes.POST("/expire-overdue", subscriptionController.ExpireOverdueSubscriptions)
 	}
 }
@@ -16,3 +16,9 @@ func (n *NullEventPublisher) PublishSubscriptionEvent(action, subscriptionID str
 	log.Printf("⚠️  [NullEventPublisher] Evento no publicado: subscription.%s (ID: %s)", action, subscriptionID)
 	return nil
 }
+
+// PublishPlanEvent - Implementa la interface PlanEventPublisher sin hacer nada
+func (n *NullEventPublisher) PublishPlanEvent(action, planID string, data map[string]interface{}) error {
+	log.Printf("⚠️  [NullEventPublisher] Evento no publicado: plan.%s (ID: %s)", action, planID)
+	return nil
+}
@@ -64,10 +64,19 @@ type rabbitMQEvent struct {
 
 // PublishSubscriptionEvent - Implementa la interface EventPublisher
 func (r *RabbitMQEventPublisher) PublishSubscriptionEvent(action, subscriptionID string, data map[string]interface{}) error {
+	return r.publish("subscription", action, subscriptionID, data)
+}
+
+// PublishPlanEvent - Implementa la interface PlanEventPublisher
+func (r *RabbitMQEventPublisher) PublishPlanEvent(action, planID string, data map[string]interface{}) error {
+	return r.publish("plan", action, planID, data)
+}
+
+func (r *RabbitMQEventPublisher) publish(eventType, action, id string, data map[string]interface{}) error {
 	event := rabbitMQEvent{
 		Action:    action,
-		Type:      "subscription",
-		ID:        subscriptionID,
+		Type:      eventType,
+		ID:        id,
 		Timestamp: time.Now(),
 		Data:      data,
 	}
@@ -77,8 +86,8 @@ func (r *RabbitMQEventPublisher) PublishSubscriptionEvent(action, subscriptionID
 		return fmt.Errorf("error serializando evento: %w", err)
 	}
 
-	// Routing key: subscription.{action}
-	routingKey := fmt.Sprintf("subscription.%s", action)
+	// Routing key: {type}.{action} (ej. subscription.activated, plan.updated)
+	routingKey := fmt.Sprintf("%s.%s", eventType, action)
 
 	err = r.channel.Publish(
 		r.exchange, // exchange
@@ -96,7 +105,7 @@ func (r *RabbitMQEventPublisher) PublishSubscriptionEvent(action, subscriptionID
 		return fmt.Errorf("error publicando evento: %w", err)
 	}
 
-	log.Printf("📤 Evento publicado: %s (ID: %s)\n", routingKey, subscriptionID)
+	log.Printf("📤 Evento publicado: %s (ID: %s)\n", routingKey, id)
 	return nil
 }
 
@@ -5,22 +5,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
+	"net/url"
+
+	"github.com/yourusername/gym-management/shared/httpclient"
 )
 
 // UsersAPIValidator - Implementación de UserValidator que consulta users-api
+// Los timeouts, reintentos y el circuit breaker los pone httpclient
 type UsersAPIValidator struct {
-	baseURL string
-	client  *http.Client
+	client *httpclient.Client
 }
 
 // NewUsersAPIValidator - Constructor con DI
-func NewUsersAPIValidator(baseURL string) *UsersAPIValidator {
+func NewUsersAPIValidator(client *httpclient.Client) *UsersAPIValidator {
 	return &UsersAPIValidator{
-		baseURL: baseURL,
-		client: &http.Client{
-			Timeout: 5 * time.Second,
-		},
+		client: client,
 	}
 }
 
@@ -31,14 +30,7 @@ type userResponse struct {
 
 // ValidateUser - Implementa la interface UserValidator
 func (u *UsersAPIValidator) ValidateUser(ctx context.Context, userID string) (bool, error) {
-	url := fmt.Sprintf("%s/users/%s", u.baseURL, userID)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return false, fmt.Errorf("error creando request: %w", err)
-	}
-
-	resp, err := u.client.Do(req)
+	resp, err := u.client.Get(ctx, "/users/"+url.PathEscape(userID), nil)
 	if err != nil {
 		return false, fmt.Errorf("error consultando users-api: %w", err)
 	}
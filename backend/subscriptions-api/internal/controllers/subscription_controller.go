@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -31,7 +32,14 @@ func (c *SubscriptionController) CreateSubscription(ctx *gin.Context) {
 		return
 	}
 
+	// El estado de verificación viene del JWT, nunca del body
+	req.EmailVerificado = ctx.GetBool("email_verified")
+
 	subscription, err := c.subscriptionService.CreateSubscription(ctx.Request.Context(), req)
+	if errors.Is(err, services.ErrEmailNoVerificado) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -126,6 +134,18 @@ func (c *SubscriptionController) HealthCheck(ctx *gin.Context) {
 	ctx.JSON(statusCode, healthStatus)
 }
 
+// ListActiveSubscriptions - Lista las suscripciones activas de todos los usuarios
+// GET /subscriptions/active (solo admin; la usa activities-api para sincronizar su copia local)
+func (c *SubscriptionController) ListActiveSubscriptions(ctx *gin.Context) {
+	subscriptions, err := c.subscriptionService.ListActiveSubscriptions(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subscriptions)
+}
+
 // ExpireOverdueSubscriptions - Endpoint para expirar suscripciones vencidas manualmente
 // POST /subscriptions/expire-overdue (solo admin)
 func (c *SubscriptionController) ExpireOverdueSubscriptions(ctx *gin.Context) {
@@ -4,41 +4,50 @@ import "time"
 
 // CreatePlanRequest - DTO para crear un plan
 type CreatePlanRequest struct {
-	Nombre                string   `json:"nombre" binding:"required,min=3,max=100"`
-	Descripcion           string   `json:"descripcion" binding:"max=500"`
-	PrecioMensual         float64  `json:"precio_mensual" binding:"required,gt=0"`
-	TipoAcceso            string   `json:"tipo_acceso" binding:"required,oneof=limitado completo"`
-	DuracionDias          int      `json:"duracion_dias" binding:"required,gt=0"`
-	Activo                bool     `json:"activo"`
-	ActividadesPermitidas []string `json:"actividades_permitidas"`
-	ActividadesPorSemana  int      `json:"actividades_por_semana" binding:"omitempty,min=0"` // 0 = ilimitado
+	Nombre                  string   `json:"nombre" binding:"required,min=3,max=100"`
+	Descripcion             string   `json:"descripcion" binding:"max=500"`
+	PrecioMensual           float64  `json:"precio_mensual" binding:"required,gt=0"`
+	TipoAcceso              string   `json:"tipo_acceso" binding:"required,oneof=limitado completo"`
+	DuracionDias            int      `json:"duracion_dias" binding:"required,gt=0"`
+	Activo                  bool     `json:"activo"`
+	ActividadesPermitidas   []string `json:"actividades_permitidas"`
+	ActividadesPorSemana    int      `json:"actividades_por_semana" binding:"omitempty,min=0"` // 0 = ilimitado
+	CupoClases              int      `json:"cupo_clases" binding:"omitempty,min=0"`            // Clases por período; 0 = usa actividades_por_semana
+	CupoPeriodo             string   `json:"cupo_periodo" binding:"omitempty,oneof=diario semanal mensual"`
+	RequiereEmailVerificado bool     `json:"requiere_email_verificado"`
 }
 
 // UpdatePlanRequest - DTO para actualizar un plan
 type UpdatePlanRequest struct {
-	Nombre                *string   `json:"nombre,omitempty" binding:"omitempty,min=3,max=100"`
-	Descripcion           *string   `json:"descripcion,omitempty" binding:"omitempty,max=500"`
-	PrecioMensual         *float64  `json:"precio_mensual,omitempty" binding:"omitempty,gt=0"`
-	TipoAcceso            *string   `json:"tipo_acceso,omitempty" binding:"omitempty,oneof=limitado completo"`
-	DuracionDias          *int      `json:"duracion_dias,omitempty" binding:"omitempty,gt=0"`
-	Activo                *bool     `json:"activo,omitempty"`
-	ActividadesPermitidas *[]string `json:"actividades_permitidas,omitempty"`
-	ActividadesPorSemana  *int      `json:"actividades_por_semana,omitempty" binding:"omitempty,min=0"`
+	Nombre                  *string   `json:"nombre,omitempty" binding:"omitempty,min=3,max=100"`
+	Descripcion             *string   `json:"descripcion,omitempty" binding:"omitempty,max=500"`
+	PrecioMensual           *float64  `json:"precio_mensual,omitempty" binding:"omitempty,gt=0"`
+	TipoAcceso              *string   `json:"tipo_acceso,omitempty" binding:"omitempty,oneof=limitado completo"`
+	DuracionDias            *int      `json:"duracion_dias,omitempty" binding:"omitempty,gt=0"`
+	Activo                  *bool     `json:"activo,omitempty"`
+	ActividadesPermitidas   *[]string `json:"actividades_permitidas,omitempty"`
+	ActividadesPorSemana    *int      `json:"actividades_por_semana,omitempty" binding:"omitempty,min=0"`
+	CupoClases              *int      `json:"cupo_clases,omitempty" binding:"omitempty,min=0"`
+	CupoPeriodo             *string   `json:"cupo_periodo,omitempty" binding:"omitempty,oneof=diario semanal mensual"`
+	RequiereEmailVerificado *bool     `json:"requiere_email_verificado,omitempty"`
 }
 
 // PlanResponse - DTO para respuesta de un plan
 type PlanResponse struct {
-	ID                    string    `json:"id"`
-	Nombre                string    `json:"nombre"`
-	Descripcion           string    `json:"descripcion"`
-	PrecioMensual         float64   `json:"precio_mensual"`
-	TipoAcceso            string    `json:"tipo_acceso"`
-	DuracionDias          int       `json:"duracion_dias"`
-	Activo                bool      `json:"activo"`
-	ActividadesPermitidas []string  `json:"actividades_permitidas"`
-	ActividadesPorSemana  int       `json:"actividades_por_semana"`
-	CreatedAt             time.Time `json:"created_at"`
-	UpdatedAt             time.Time `json:"updated_at"`
+	ID                      string    `json:"id"`
+	Nombre                  string    `json:"nombre"`
+	Descripcion             string    `json:"descripcion"`
+	PrecioMensual           float64   `json:"precio_mensual"`
+	TipoAcceso              string    `json:"tipo_acceso"`
+	DuracionDias            int       `json:"duracion_dias"`
+	Activo                  bool      `json:"activo"`
+	ActividadesPermitidas   []string  `json:"actividades_permitidas"`
+	ActividadesPorSemana    int       `json:"actividades_por_semana"`
+	CupoClases              int       `json:"cupo_clases"`
+	CupoPeriodo             string    `json:"cupo_periodo"`
+	RequiereEmailVerificado bool      `json:"requiere_email_verificado"`
+	CreatedAt               time.Time `json:"created_at"`
+	UpdatedAt               time.Time `json:"updated_at"`
 }
 
 // ListPlansQuery - DTO para query params de listado
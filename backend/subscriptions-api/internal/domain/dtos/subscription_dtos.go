@@ -10,6 +10,7 @@ type CreateSubscriptionRequest struct {
 	MetodoPago       string `json:"metodo_pago" binding:"required"`
 	AutoRenovacion   bool   `json:"auto_renovacion"`
 	Notas            string `json:"notas"`
+	EmailVerificado  bool   `json:"-"` // Lo completa el controller con el claim email_verified del JWT
 }
 
 // UpdateSubscriptionStatusRequest - DTO para actualizar estado
@@ -6,19 +6,11 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/yourusername/gym-management/shared/auth"
 )
 
-// Claims - Estructura de claims del JWT
-type Claims struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
-	jwt.RegisteredClaims
-}
-
-// JWTAuth - Middleware para validar JWT
-func JWTAuth(jwtSecret string) gin.HandlerFunc {
+// JWTAuth - Middleware para validar JWT con shared/auth (firma con el JWKS de users-api, expiración, revocación por jti)
+func JWTAuth(verifier *auth.Verifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Obtener token del header Authorization
 		authHeader := c.GetHeader("Authorization")
@@ -36,58 +28,39 @@ func JWTAuth(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		tokenString := parts[1]
-
 		// Validar token
-		token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-			// Verificar método de firma
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("método de firma inválido")
-			}
-			return []byte(jwtSecret), nil
-		})
-
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido o expirado"})
+		claims, err := verifier.Verify(parts[1])
+		if errors.Is(err, auth.ErrTokenRevoked) {
+			// Rechazar tokens revocados (logout / sesión comprometida)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token revocado"})
 			c.Abort()
 			return
 		}
-
-		// Extraer claims
-		if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-			// Guardar información del usuario en el contexto
-			c.Set("user_id", claims.UserID)
-			c.Set("username", claims.Username)
-			c.Set("role", claims.Role)
-			c.Next()
-		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Claims inválidos"})
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido o expirado"})
 			c.Abort()
 			return
 		}
+
+		// Guardar información del usuario en el contexto
+		setClaims(c, claims)
+		c.Next()
 	}
 }
 
-// RequireRole - Middleware para verificar roles específicos
-func RequireRole(roles ...string) gin.HandlerFunc {
+// RequirePermission - Middleware para verificar permisos (alcanza con uno de la lista)
+// El permiso puede ser en cualquier sucursal: el alcance por sucursal lo valida el handler con ClaimsFromContext
+func RequirePermission(perms ...auth.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userRole, exists := c.Get("role")
-		if !exists {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Rol no encontrado en el contexto"})
-			c.Abort()
-			return
-		}
-
-		roleStr, ok := userRole.(string)
+		claims, ok := ClaimsFromContext(c)
 		if !ok {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Rol inválido"})
+			c.JSON(http.StatusForbidden, gin.H{"error": "Usuario no autenticado"})
 			c.Abort()
 			return
 		}
 
-		// Verificar si el rol del usuario está en la lista permitida
-		for _, allowedRole := range roles {
-			if roleStr == allowedRole {
+		for _, perm := range perms {
+			if claims.Can(perm) {
 				c.Next()
 				return
 			}
@@ -98,6 +71,16 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 	}
 }
 
+// ClaimsFromContext - Devuelve los claims que guardó JWTAuth
+func ClaimsFromContext(c *gin.Context) (*auth.Claims, bool) {
+	value, exists := c.Get("claims")
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*auth.Claims)
+	return claims, ok
+}
+
 // GetUserIDFromContext - Helper para obtener el user_id del contexto
 func GetUserIDFromContext(c *gin.Context) (string, error) {
 	userID, exists := c.Get("user_id")
@@ -114,7 +97,7 @@ func GetUserIDFromContext(c *gin.Context) (string, error) {
 }
 
 // OptionalAuth - Middleware opcional (no requiere autenticación pero la procesa si existe)
-func OptionalAuth(jwtSecret string) gin.HandlerFunc {
+func OptionalAuth(verifier *auth.Verifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -129,23 +112,22 @@ func OptionalAuth(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		tokenString := parts[1]
-
-		token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("método de firma inválido")
-			}
-			return []byte(jwtSecret), nil
-		})
-
-		if err == nil {
-			if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-				c.Set("user_id", claims.UserID)
-				c.Set("username", claims.Username)
-				c.Set("role", claims.Role)
-			}
+		// Un token inválido o revocado se trata igual que la ausencia de token
+		if claims, err := verifier.Verify(parts[1]); err == nil {
+			setClaims(c, claims)
 		}
 
 		c.Next()
 	}
 }
+
+// setClaims - Guarda en el contexto los datos del usuario del token
+// user_id es el claim "sub" (ID de users-api) y role el rol principal (admin > user)
+func setClaims(c *gin.Context, claims *auth.Claims) {
+	c.Set("user_id", claims.Subject)
+	c.Set("role", claims.Role())
+	c.Set("roles", claims.Roles)
+	c.Set("branch_ids", claims.BranchIDs)
+	c.Set("email_verified", claims.EmailVerified)
+	c.Set("claims", claims)
+}
@@ -9,14 +9,15 @@ import (
 
 // MockSubscriptionRepository - Mock para tests
 type MockSubscriptionRepository struct {
-	CreateFunc              func(ctx context.Context, subscription *entities.Subscription) error
-	FindByIDFunc            func(ctx context.Context, id primitive.ObjectID) (*entities.Subscription, error)
-	FindAllFunc             func(ctx context.Context, filters map[string]interface{}) ([]*entities.Subscription, error)
-	FindActiveByUserIDFunc  func(ctx context.Context, userID string) (*entities.Subscription, error)
-	UpdateFunc              func(ctx context.Context, id primitive.ObjectID, subscription *entities.Subscription) error
-	UpdateStatusFunc        func(ctx context.Context, id primitive.ObjectID, status, pagoID string) error
-	DeleteFunc              func(ctx context.Context, id primitive.ObjectID) error
-	CountFunc               func(ctx context.Context, filters map[string]interface{}) (int64, error)
+	CreateFunc                   func(ctx context.Context, subscription *entities.Subscription) error
+	FindByIDFunc                 func(ctx context.Context, id primitive.ObjectID) (*entities.Subscription, error)
+	FindAllFunc                  func(ctx context.Context, filters map[string]interface{}) ([]*entities.Subscription, error)
+	FindActiveByUserIDFunc       func(ctx context.Context, userID string) (*entities.Subscription, error)
+	UpdateFunc                   func(ctx context.Context, id primitive.ObjectID, subscription *entities.Subscription) error
+	UpdateStatusFunc             func(ctx context.Context, id primitive.ObjectID, status, pagoID string) error
+	DeleteFunc                   func(ctx context.Context, id primitive.ObjectID) error
+	CountFunc                    func(ctx context.Context, filters map[string]interface{}) (int64, error)
+	FindExpiredSubscriptionsFunc func(ctx context.Context) ([]*entities.Subscription, error)
 }
 
 func (m *MockSubscriptionRepository) Create(ctx context.Context, subscription *entities.Subscription) error {
@@ -74,3 +75,10 @@ func (m *MockSubscriptionRepository) Count(ctx context.Context, filters map[stri
 	}
 	return 0, nil
 }
+
+func (m *MockSubscriptionRepository) FindExpiredSubscriptions(ctx context.Context) ([]*entities.Subscription, error) {
+	if m.FindExpiredSubscriptionsFunc != nil {
+		return m.FindExpiredSubscriptionsFunc(ctx)
+	}
+	return []*entities.Subscription{}, nil
+}
@@ -3,6 +3,7 @@ package mocks
 // MockEventPublisher - Mock para tests
 type MockEventPublisher struct {
 	PublishSubscriptionEventFunc func(action, subscriptionID string, data map[string]interface{}) error
+	PublishPlanEventFunc         func(action, planID string, data map[string]interface{}) error
 }
 
 func (m *MockEventPublisher) PublishSubscriptionEvent(action, subscriptionID string, data map[string]interface{}) error {
@@ -11,3 +12,10 @@ func (m *MockEventPublisher) PublishSubscriptionEvent(action, subscriptionID str
 	}
 	return nil
 }
+
+func (m *MockEventPublisher) PublishPlanEvent(action, planID string, data map[string]interface{}) error {
+	if m.PublishPlanEventFunc != nil {
+		return m.PublishPlanEventFunc(action, planID, data)
+	}
+	return nil
+}
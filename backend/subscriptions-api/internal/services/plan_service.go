@@ -12,13 +12,20 @@ import (
 
 // PlanService - Servicio de lógica de negocio para planes
 type PlanService struct {
-	planRepo repository.PlanRepository // Inyección de Dependencias (Interface)
+	planRepo       repository.PlanRepository // Inyección de Dependencias (Interface)
+	eventPublisher PlanEventPublisher        // DI (Interface para publicar eventos)
+}
+
+// PlanEventPublisher - Interface para publicar eventos de planes (abstrae RabbitMQ)
+type PlanEventPublisher interface {
+	PublishPlanEvent(action, planID string, data map[string]interface{}) error
 }
 
 // NewPlanService - Constructor con DI
-func NewPlanService(planRepo repository.PlanRepository) *PlanService {
+func NewPlanService(planRepo repository.PlanRepository, eventPublisher PlanEventPublisher) *PlanService {
 	return &PlanService{
-		planRepo: planRepo,
+		planRepo:       planRepo,
+		eventPublisher: eventPublisher,
 	}
 }
 
@@ -26,16 +33,20 @@ func NewPlanService(planRepo repository.PlanRepository) *PlanService {
 func (s *PlanService) CreatePlan(ctx context.Context, req dtos.CreatePlanRequest) (*dtos.PlanResponse, error) {
 	// Mapear DTO a entidad
 	plan := &entities.Plan{
-		ID:                    primitive.NewObjectID(),
-		Nombre:                req.Nombre,
-		Descripcion:           req.Descripcion,
-		PrecioMensual:         req.PrecioMensual,
-		TipoAcceso:            req.TipoAcceso,
-		DuracionDias:          req.DuracionDias,
-		Activo:                req.Activo,
-		ActividadesPermitidas: req.ActividadesPermitidas,
-		CreatedAt:             time.Now(),
-		UpdatedAt:             time.Now(),
+		ID:                      primitive.NewObjectID(),
+		Nombre:                  req.Nombre,
+		Descripcion:             req.Descripcion,
+		PrecioMensual:           req.PrecioMensual,
+		TipoAcceso:              req.TipoAcceso,
+		DuracionDias:            req.DuracionDias,
+		Activo:                  req.Activo,
+		ActividadesPermitidas:   req.ActividadesPermitidas,
+		ActividadesPorSemana:    req.ActividadesPorSemana,
+		CupoClases:              req.CupoClases,
+		CupoPeriodo:             req.CupoPeriodo,
+		RequiereEmailVerificado: req.RequiereEmailVerificado,
+		CreatedAt:               time.Now(),
+		UpdatedAt:               time.Now(),
 	}
 
 	// Guardar en repositorio
@@ -43,6 +54,8 @@ func (s *PlanService) CreatePlan(ctx context.Context, req dtos.CreatePlanRequest
 		return nil, err
 	}
 
+	s.eventPublisher.PublishPlanEvent("created", plan.ID.Hex(), planEventData(plan))
+
 	// Mapear entidad a DTO de respuesta
 	return s.mapPlanToResponse(plan), nil
 }
@@ -157,6 +170,18 @@ func (s *PlanService) UpdatePlan(ctx context.Context, id string, req dtos.Update
 	if req.ActividadesPermitidas != nil {
 		plan.ActividadesPermitidas = *req.ActividadesPermitidas
 	}
+	if req.ActividadesPorSemana != nil {
+		plan.ActividadesPorSemana = *req.ActividadesPorSemana
+	}
+	if req.CupoClases != nil {
+		plan.CupoClases = *req.CupoClases
+	}
+	if req.CupoPeriodo != nil {
+		plan.CupoPeriodo = *req.CupoPeriodo
+	}
+	if req.RequiereEmailVerificado != nil {
+		plan.RequiereEmailVerificado = *req.RequiereEmailVerificado
+	}
 
 	plan.UpdatedAt = time.Now()
 
@@ -165,6 +190,8 @@ func (s *PlanService) UpdatePlan(ctx context.Context, id string, req dtos.Update
 		return nil, err
 	}
 
+	s.eventPublisher.PublishPlanEvent("updated", id, planEventData(plan))
+
 	return s.mapPlanToResponse(plan), nil
 }
 
@@ -175,7 +202,13 @@ func (s *PlanService) DeletePlan(ctx context.Context, id string) error {
 		return err
 	}
 
-	return s.planRepo.Delete(ctx, objID)
+	if err := s.planRepo.Delete(ctx, objID); err != nil {
+		return err
+	}
+
+	s.eventPublisher.PublishPlanEvent("deleted", id, nil)
+
+	return nil
 }
 
 // TogglePlanStatus - Activa o desactiva un plan
@@ -200,22 +233,42 @@ func (s *PlanService) TogglePlanStatus(ctx context.Context, id string, activo bo
 		return nil, err
 	}
 
+	s.eventPublisher.PublishPlanEvent("status_changed", id, planEventData(plan))
+
 	return s.mapPlanToResponse(plan), nil
 }
 
+// planEventData arma el payload de los eventos con lo que otros servicios necesitan del plan
+// (activities-api valida las inscripciones contra su copia local de los planes)
+func planEventData(plan *entities.Plan) map[string]interface{} {
+	return map[string]interface{}{
+		"nombre":                 plan.Nombre,
+		"tipo_acceso":            plan.TipoAcceso,
+		"activo":                 plan.Activo,
+		"actividades_permitidas": plan.ActividadesPermitidas,
+		"actividades_por_semana": plan.ActividadesPorSemana,
+		"cupo_clases":            plan.CupoClases,
+		"cupo_periodo":           plan.CupoPeriodo,
+		"duracion_dias":          plan.DuracionDias,
+	}
+}
+
 // mapPlanToResponse - Helper para mapear entidad a DTO
 func (s *PlanService) mapPlanToResponse(plan *entities.Plan) *dtos.PlanResponse {
 	return &dtos.PlanResponse{
-		ID:                    plan.ID.Hex(),
-		Nombre:                plan.Nombre,
-		Descripcion:           plan.Descripcion,
-		PrecioMensual:         plan.PrecioMensual,
-		TipoAcceso:            plan.TipoAcceso,
-		DuracionDias:          plan.DuracionDias,
-		Activo:                plan.Activo,
-		ActividadesPermitidas: plan.ActividadesPermitidas,
-		ActividadesPorSemana:  plan.ActividadesPorSemana,
-		CreatedAt:             plan.CreatedAt,
-		UpdatedAt:             plan.UpdatedAt,
+		ID:                      plan.ID.Hex(),
+		Nombre:                  plan.Nombre,
+		Descripcion:             plan.Descripcion,
+		PrecioMensual:           plan.PrecioMensual,
+		TipoAcceso:              plan.TipoAcceso,
+		DuracionDias:            plan.DuracionDias,
+		Activo:                  plan.Activo,
+		ActividadesPermitidas:   plan.ActividadesPermitidas,
+		ActividadesPorSemana:    plan.ActividadesPorSemana,
+		CupoClases:              plan.CupoClases,
+		CupoPeriodo:             plan.CupoPeriodo,
+		RequiereEmailVerificado: plan.RequiereEmailVerificado,
+		CreatedAt:               plan.CreatedAt,
+		UpdatedAt:               plan.UpdatedAt,
 	}
 }
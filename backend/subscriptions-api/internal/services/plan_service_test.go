@@ -8,6 +8,7 @@ import (
 	"github.com/yourusername/gym-management/subscriptions-api/internal/domain/dtos"
 	"github.com/yourusername/gym-management/subscriptions-api/internal/domain/entities"
 	"github.com/yourusername/gym-management/subscriptions-api/internal/repository/mocks"
+	serviceMocks "github.com/yourusername/gym-management/subscriptions-api/internal/services/mocks"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -20,7 +21,7 @@ func TestPlanService_CreatePlan(t *testing.T) {
 				return nil
 			},
 		}
-		service := NewPlanService(mockRepo)
+		service := NewPlanService(mockRepo, &serviceMocks.MockEventPublisher{})
 
 		req := dtos.CreatePlanRequest{
 			Nombre:                "Plan Test",
@@ -50,6 +51,42 @@ func TestPlanService_CreatePlan(t *testing.T) {
 		}
 	})
 
+	t.Run("Crear plan guarda el límite de clases", func(t *testing.T) {
+		// Arrange
+		var guardado *entities.Plan
+		mockRepo := &mocks.MockPlanRepository{
+			CreateFunc: func(ctx context.Context, plan *entities.Plan) error {
+				guardado = plan
+				return nil
+			},
+		}
+		service := NewPlanService(mockRepo, &serviceMocks.MockEventPublisher{})
+
+		req := dtos.CreatePlanRequest{
+			Nombre:               "Plan 8 clases",
+			PrecioMensual:        100.0,
+			TipoAcceso:           "limitado",
+			DuracionDias:         30,
+			ActividadesPorSemana: 3,
+			CupoClases:           8,
+			CupoPeriodo:          "mensual",
+		}
+
+		// Act
+		result, err := service.CreatePlan(context.Background(), req)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("No se esperaba error, pero se obtuvo: %v", err)
+		}
+		if guardado.ActividadesPorSemana != 3 || guardado.CupoClases != 8 || guardado.CupoPeriodo != "mensual" {
+			t.Errorf("El plan guardado debería tener el límite de clases, obtenido %+v", guardado)
+		}
+		if result.ActividadesPorSemana != 3 || result.CupoClases != 8 || result.CupoPeriodo != "mensual" {
+			t.Errorf("La respuesta debería tener el límite de clases, obtenido %+v", result)
+		}
+	})
+
 	t.Run("Error al crear plan en repositorio", func(t *testing.T) {
 		// Arrange
 		expectedError := errors.New("error de base de datos")
@@ -58,7 +95,7 @@ func TestPlanService_CreatePlan(t *testing.T) {
 				return expectedError
 			},
 		}
-		service := NewPlanService(mockRepo)
+		service := NewPlanService(mockRepo, &serviceMocks.MockEventPublisher{})
 
 		req := dtos.CreatePlanRequest{
 			Nombre:        "Plan Test",
@@ -101,7 +138,7 @@ func TestPlanService_GetPlanByID(t *testing.T) {
 				return nil, errors.New("plan no encontrado")
 			},
 		}
-		service := NewPlanService(mockRepo)
+		service := NewPlanService(mockRepo, &serviceMocks.MockEventPublisher{})
 
 		// Act
 		result, err := service.GetPlanByID(context.Background(), planID.Hex())
@@ -124,7 +161,7 @@ func TestPlanService_GetPlanByID(t *testing.T) {
 	t.Run("Error con ID inválido", func(t *testing.T) {
 		// Arrange
 		mockRepo := &mocks.MockPlanRepository{}
-		service := NewPlanService(mockRepo)
+		service := NewPlanService(mockRepo, &serviceMocks.MockEventPublisher{})
 
 		// Act
 		result, err := service.GetPlanByID(context.Background(), "invalid-id")
@@ -145,7 +182,7 @@ func TestPlanService_GetPlanByID(t *testing.T) {
 				return nil, errors.New("plan no encontrado")
 			},
 		}
-		service := NewPlanService(mockRepo)
+		service := NewPlanService(mockRepo, &serviceMocks.MockEventPublisher{})
 
 		// Act
 		result, err := service.GetPlanByID(context.Background(), primitive.NewObjectID().Hex())
@@ -186,7 +223,7 @@ func TestPlanService_ListPlans(t *testing.T) {
 				return int64(len(mockPlans)), nil
 			},
 		}
-		service := NewPlanService(mockRepo)
+		service := NewPlanService(mockRepo, &serviceMocks.MockEventPublisher{})
 
 		activo := true
 		query := dtos.ListPlansQuery{
@@ -213,3 +250,71 @@ func TestPlanService_ListPlans(t *testing.T) {
 		}
 	})
 }
+
+func TestPlanService_PublicaEventos(t *testing.T) {
+	t.Run("Actualizar plan publica las condiciones del plan", func(t *testing.T) {
+		// Arrange
+		planID := primitive.NewObjectID()
+		mockRepo := &mocks.MockPlanRepository{
+			FindByIDFunc: func(ctx context.Context, id primitive.ObjectID) (*entities.Plan, error) {
+				return &entities.Plan{
+					ID:                    id,
+					Nombre:                "Plan Basic",
+					TipoAcceso:            "limitado",
+					Activo:                true,
+					ActividadesPermitidas: []string{"yoga"},
+					ActividadesPorSemana:  2,
+				}, nil
+			},
+		}
+
+		var action string
+		var data map[string]interface{}
+		publisher := &serviceMocks.MockEventPublisher{
+			PublishPlanEventFunc: func(a, id string, d map[string]interface{}) error {
+				action, data = a, d
+				return nil
+			},
+		}
+		service := NewPlanService(mockRepo, publisher)
+
+		tipoAcceso := "completo"
+		// Act
+		_, err := service.UpdatePlan(context.Background(), planID.Hex(), dtos.UpdatePlanRequest{TipoAcceso: &tipoAcceso})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("No se esperaba error, pero se obtuvo: %v", err)
+		}
+		if action != "updated" {
+			t.Errorf("Acción esperada updated, obtenida %s", action)
+		}
+		if data["tipo_acceso"] != "completo" || data["actividades_por_semana"] != 2 {
+			t.Errorf("El evento debería llevar las condiciones actualizadas, obtenido %v", data)
+		}
+	})
+
+	t.Run("Eliminar plan publica el evento", func(t *testing.T) {
+		// Arrange
+		planID := primitive.NewObjectID()
+		var action string
+		publisher := &serviceMocks.MockEventPublisher{
+			PublishPlanEventFunc: func(a, id string, d map[string]interface{}) error {
+				action = a
+				return nil
+			},
+		}
+		service := NewPlanService(&mocks.MockPlanRepository{}, publisher)
+
+		// Act
+		err := service.DeletePlan(context.Background(), planID.Hex())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("No se esperaba error, pero se obtuvo: %v", err)
+		}
+		if action != "deleted" {
+			t.Errorf("Acción esperada deleted, obtenida %s", action)
+		}
+	})
+}
@@ -57,6 +57,10 @@ func TestPlanUpgradeFlow(t *testing.T) {
 		eventsPublished := []string{}
 
 		mockSubRepo := &repoMocks.MockSubscriptionRepository{
+			// Obtener la suscripción a cancelar
+			FindByIDFunc: func(ctx context.Context, id primitive.ObjectID) (*entities.Subscription, error) {
+				return currentSubscription, nil
+			},
 			// Encontrar suscripción activa
 			FindActiveByUserIDFunc: func(ctx context.Context, userID string) (*entities.Subscription, error) {
 				if cancelCalled {
@@ -163,15 +167,15 @@ func TestPlanUpgradeFlow(t *testing.T) {
 		}
 
 		// Verificar que se publicó evento de cancelación
-		foundDeleteEvent := false
+		foundCancelledEvent := false
 		for _, event := range eventsPublished {
-			if event == "delete" {
-				foundDeleteEvent = true
+			if event == "cancelled" {
+				foundCancelledEvent = true
 				break
 			}
 		}
-		if !foundDeleteEvent {
-			t.Error("Se esperaba evento 'delete' para la cancelación")
+		if !foundCancelledEvent {
+			t.Error("Se esperaba evento 'cancelled' para la cancelación")
 		}
 	})
 
@@ -284,6 +288,9 @@ func TestPlanUpgradeFlow(t *testing.T) {
 		created := false
 
 		mockSubRepo := &repoMocks.MockSubscriptionRepository{
+			FindByIDFunc: func(ctx context.Context, id primitive.ObjectID) (*entities.Subscription, error) {
+				return currentSubscription, nil
+			},
 			FindActiveByUserIDFunc: func(ctx context.Context, userID string) (*entities.Subscription, error) {
 				if cancelled {
 					return nil, errors.New("no hay suscripción activa")
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,6 +12,9 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ErrEmailNoVerificado - El plan exige email verificado y el usuario no lo confirmó
+var ErrEmailNoVerificado = errors.New("este plan requiere que verifiques tu email antes de suscribirte")
+
 // SubscriptionService - Servicio de lógica de negocio para suscripciones
 type SubscriptionService struct {
 	subscriptionRepo repository.SubscriptionRepository // DI
@@ -84,6 +88,10 @@ func (s *SubscriptionService) CreateSubscription(ctx context.Context, req dtos.C
 		return nil, fmt.Errorf("el plan no está activo")
 	}
 
+	if plan.RequiereEmailVerificado && !req.EmailVerificado {
+		return nil, ErrEmailNoVerificado
+	}
+
 	// 4. Calcular fechas
 	now := time.Now()
 	fechaVencimiento := now.AddDate(0, 0, plan.DuracionDias)
@@ -113,12 +121,7 @@ func (s *SubscriptionService) CreateSubscription(ctx context.Context, req dtos.C
 	}
 
 	// 7. Publicar evento
-	eventData := map[string]interface{}{
-		"usuario_id": subscription.UsuarioID,
-		"plan_id":    subscription.PlanID.Hex(),
-		"estado":     subscription.Estado,
-	}
-	s.eventPublisher.PublishSubscriptionEvent("create", subscription.ID.Hex(), eventData)
+	s.eventPublisher.PublishSubscriptionEvent("create", subscription.ID.Hex(), subscriptionEventData(subscription, nil))
 
 	// 8. Mapear a DTO de respuesta
 	return s.mapSubscriptionToResponse(subscription, plan.Nombre), nil
@@ -203,11 +206,14 @@ func (s *SubscriptionService) UpdateSubscriptionStatus(ctx context.Context, id s
 		return err
 	}
 
-	// Publicar evento
+	// Publicar evento con la suscripción actualizada (los consumidores mantienen su copia local)
 	eventData := map[string]interface{}{
 		"estado":  req.Estado,
 		"pago_id": req.PagoID,
 	}
+	if subscription, err := s.subscriptionRepo.FindByID(ctx, objID); err == nil && subscription != nil {
+		eventData = subscriptionEventData(subscription, map[string]interface{}{"pago_id": req.PagoID})
+	}
 	s.eventPublisher.PublishSubscriptionEvent("update", id, eventData)
 
 	return nil
@@ -231,11 +237,8 @@ func (s *SubscriptionService) CancelSubscription(ctx context.Context, id string)
 	}
 
 	// Publicar evento con usuario_id para que activities-api pueda desinscribir
-	eventData := map[string]interface{}{
-		"usuario_id": subscription.UsuarioID,
-		"plan_id":    subscription.PlanID.Hex(),
-	}
-	s.eventPublisher.PublishSubscriptionEvent("cancelled", id, eventData)
+	subscription.Estado = "cancelada"
+	s.eventPublisher.PublishSubscriptionEvent("cancelled", id, subscriptionEventData(subscription, nil))
 
 	return nil
 }
@@ -270,6 +273,56 @@ func (s *SubscriptionService) mapSubscriptionToResponse(subscription *entities.S
 	}
 }
 
+// ListActiveSubscriptions - Lista todas las suscripciones activas y vigentes
+// Lo usan otros servicios para reconstruir su copia local de las suscripciones
+func (s *SubscriptionService) ListActiveSubscriptions(ctx context.Context) ([]*dtos.SubscriptionResponse, error) {
+	filters := map[string]interface{}{
+		"estado": "activa",
+	}
+
+	subscriptions, err := s.subscriptionRepo.FindAll(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	planNombres := make(map[primitive.ObjectID]string)
+	responses := []*dtos.SubscriptionResponse{}
+	for _, subscription := range subscriptions {
+		if !subscription.FechaVencimiento.After(now) {
+			continue // Vencida pero todavía no procesada por ExpireOverdueSubscriptions
+		}
+
+		planNombre, ok := planNombres[subscription.PlanID]
+		if !ok {
+			if plan, _ := s.planRepo.FindByID(ctx, subscription.PlanID); plan != nil {
+				planNombre = plan.Nombre
+			}
+			planNombres[subscription.PlanID] = planNombre
+		}
+
+		responses = append(responses, s.mapSubscriptionToResponse(subscription, planNombre))
+	}
+
+	return responses, nil
+}
+
+// subscriptionEventData arma el payload de los eventos con el estado completo de la suscripción,
+// así los consumidores pueden mantener su copia local sin consultar esta API
+func subscriptionEventData(subscription *entities.Subscription, extra map[string]interface{}) map[string]interface{} {
+	data := map[string]interface{}{
+		"usuario_id":        subscription.UsuarioID,
+		"plan_id":           subscription.PlanID.Hex(),
+		"estado":            subscription.Estado,
+		"fecha_inicio":      subscription.FechaInicio,
+		"fecha_vencimiento": subscription.FechaVencimiento,
+	}
+	for k, v := range extra {
+		data[k] = v
+	}
+	return data
+}
+
 // ============================================================================
 // MÉTODOS PARA PROCESAR EVENTOS DE PAGOS (Invocados por PaymentEventHandler)
 // ============================================================================
@@ -313,12 +366,9 @@ func (s *SubscriptionService) ActivateSubscriptionByPayment(ctx context.Context,
 	}
 
 	// Publicar evento de activación
-	eventData := map[string]interface{}{
-		"usuario_id": subscription.UsuarioID,
-		"plan_id":    subscription.PlanID.Hex(),
+	eventData := subscriptionEventData(subscription, map[string]interface{}{
 		"payment_id": paymentID,
-		"estado":     "activa",
-	}
+	})
 	s.eventPublisher.PublishSubscriptionEvent("activated", subscriptionID, eventData)
 
 	return nil
@@ -354,11 +404,9 @@ func (s *SubscriptionService) RegisterPaymentFailure(ctx context.Context, subscr
 	}
 
 	// Publicar evento de fallo
-	eventData := map[string]interface{}{
-		"usuario_id": subscription.UsuarioID,
+	eventData := subscriptionEventData(subscription, map[string]interface{}{
 		"payment_id": paymentID,
-		"estado":     subscription.Estado,
-	}
+	})
 	s.eventPublisher.PublishSubscriptionEvent("payment_failed", subscriptionID, eventData)
 
 	return nil
@@ -391,12 +439,10 @@ func (s *SubscriptionService) CancelSubscriptionByRefund(ctx context.Context, su
 	}
 
 	// Publicar evento de cancelación por reembolso
-	eventData := map[string]interface{}{
-		"usuario_id": subscription.UsuarioID,
+	eventData := subscriptionEventData(subscription, map[string]interface{}{
 		"payment_id": paymentID,
-		"estado":     "cancelada",
 		"motivo":     "refund",
-	}
+	})
 	s.eventPublisher.PublishSubscriptionEvent("cancelled_by_refund", subscriptionID, eventData)
 
 	return nil
@@ -425,12 +471,8 @@ func (s *SubscriptionService) ExpireOverdueSubscriptions(ctx context.Context) (i
 		}
 
 		// Publicar evento de expiración
-		eventData := map[string]interface{}{
-			"usuario_id":         subscription.UsuarioID,
-			"plan_id":            subscription.PlanID.Hex(),
-			"fecha_vencimiento":  subscription.FechaVencimiento,
-		}
-		s.eventPublisher.PublishSubscriptionEvent("expired", subscription.ID.Hex(), eventData)
+		subscription.Estado = "expirada"
+		s.eventPublisher.PublishSubscriptionEvent("expired", subscription.ID.Hex(), subscriptionEventData(subscription, nil))
 
 		count++
 	}
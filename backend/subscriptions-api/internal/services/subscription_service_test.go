@@ -320,6 +320,52 @@ func TestSubscriptionService_UpdateSubscriptionStatus(t *testing.T) {
 		}
 	})
 
+	t.Run("El evento lleva la suscripción completa", func(t *testing.T) {
+		// Arrange
+		subscriptionID := primitive.NewObjectID()
+		planID := primitive.NewObjectID()
+		vencimiento := time.Now().AddDate(0, 1, 0)
+
+		mockSubRepo := &repoMocks.MockSubscriptionRepository{
+			FindByIDFunc: func(ctx context.Context, id primitive.ObjectID) (*entities.Subscription, error) {
+				return &entities.Subscription{
+					ID:               id,
+					UsuarioID:        "42",
+					PlanID:           planID,
+					Estado:           "activa",
+					FechaVencimiento: vencimiento,
+				}, nil
+			},
+		}
+
+		var eventData map[string]interface{}
+		mockEventPublisher := &serviceMocks.MockEventPublisher{
+			PublishSubscriptionEventFunc: func(action, subscriptionID string, data map[string]interface{}) error {
+				eventData = data
+				return nil
+			},
+		}
+
+		service := NewSubscriptionService(mockSubRepo, nil, nil, mockEventPublisher)
+
+		// Act
+		err := service.UpdateSubscriptionStatus(context.Background(), subscriptionID.Hex(), dtos.UpdateSubscriptionStatusRequest{
+			Estado: "activa",
+			PagoID: "payment123",
+		})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("No se esperaba error, pero se obtuvo: %v", err)
+		}
+		if eventData["usuario_id"] != "42" || eventData["plan_id"] != planID.Hex() {
+			t.Errorf("El evento debería identificar usuario y plan, obtenido %v", eventData)
+		}
+		if eventData["fecha_vencimiento"] != vencimiento || eventData["pago_id"] != "payment123" {
+			t.Errorf("El evento debería incluir vencimiento y pago, obtenido %v", eventData)
+		}
+	})
+
 	t.Run("Error con ID inválido", func(t *testing.T) {
 		// Arrange
 		service := NewSubscriptionService(nil, nil, nil, nil)
@@ -344,6 +390,9 @@ func TestSubscriptionService_CancelSubscription(t *testing.T) {
 		subscriptionID := primitive.NewObjectID()
 
 		mockSubRepo := &repoMocks.MockSubscriptionRepository{
+			FindByIDFunc: func(ctx context.Context, id primitive.ObjectID) (*entities.Subscription, error) {
+				return &entities.Subscription{ID: id, UsuarioID: "user123", PlanID: primitive.NewObjectID()}, nil
+			},
 			UpdateStatusFunc: func(ctx context.Context, id primitive.ObjectID, status, pagoID string) error {
 				if id == subscriptionID && status == "cancelada" {
 					return nil
@@ -355,7 +404,7 @@ func TestSubscriptionService_CancelSubscription(t *testing.T) {
 		eventCalled := false
 		mockEventPublisher := &serviceMocks.MockEventPublisher{
 			PublishSubscriptionEventFunc: func(action, subscriptionID string, data map[string]interface{}) error {
-				if action == "delete" {
+				if action == "cancelled" {
 					eventCalled = true
 				}
 				return nil
@@ -372,7 +421,81 @@ func TestSubscriptionService_CancelSubscription(t *testing.T) {
 			t.Errorf("No se esperaba error, pero se obtuvo: %v", err)
 		}
 		if !eventCalled {
-			t.Error("Se esperaba que se publicara un evento de tipo 'delete'")
+			t.Error("Se esperaba que se publicara un evento de tipo 'cancelled'")
+		}
+	})
+}
+
+func TestSubscriptionService_CreateSubscription_RequiereEmailVerificado(t *testing.T) {
+	planID := primitive.NewObjectID()
+	mockPlan := &entities.Plan{
+		ID:                      planID,
+		Nombre:                  "Plan Premium",
+		PrecioMensual:           100.0,
+		DuracionDias:            30,
+		Activo:                  true,
+		RequiereEmailVerificado: true,
+	}
+
+	newService := func(created *bool) *SubscriptionService {
+		mockSubRepo := &repoMocks.MockSubscriptionRepository{
+			CreateFunc: func(ctx context.Context, subscription *entities.Subscription) error {
+				*created = true
+				return nil
+			},
+		}
+		mockPlanRepo := &repoMocks.MockPlanRepository{
+			FindByIDFunc: func(ctx context.Context, id primitive.ObjectID) (*entities.Plan, error) {
+				return mockPlan, nil
+			},
+		}
+		mockUserValidator := &serviceMocks.MockUserValidator{
+			ValidateUserFunc: func(ctx context.Context, userID string) (bool, error) {
+				return true, nil
+			},
+		}
+		mockEventPublisher := &serviceMocks.MockEventPublisher{
+			PublishSubscriptionEventFunc: func(action, subscriptionID string, data map[string]interface{}) error {
+				return nil
+			},
+		}
+		return NewSubscriptionService(mockSubRepo, mockPlanRepo, mockUserValidator, mockEventPublisher)
+	}
+
+	t.Run("Rechaza usuario sin email verificado", func(t *testing.T) {
+		created := false
+		service := newService(&created)
+
+		result, err := service.CreateSubscription(context.Background(), dtos.CreateSubscriptionRequest{
+			UsuarioID:  "user123",
+			PlanID:     planID.Hex(),
+			MetodoPago: "credit_card",
+		})
+
+		if !errors.Is(err, ErrEmailNoVerificado) {
+			t.Errorf("Se esperaba ErrEmailNoVerificado, se obtuvo: %v", err)
+		}
+		if result != nil || created {
+			t.Error("No se debería crear la suscripción")
+		}
+	})
+
+	t.Run("Acepta usuario con email verificado", func(t *testing.T) {
+		created := false
+		service := newService(&created)
+
+		_, err := service.CreateSubscription(context.Background(), dtos.CreateSubscriptionRequest{
+			UsuarioID:       "user123",
+			PlanID:          planID.Hex(),
+			MetodoPago:      "credit_card",
+			EmailVerificado: true,
+		})
+
+		if err != nil {
+			t.Errorf("No se esperaba error, pero se obtuvo: %v", err)
+		}
+		if !created {
+			t.Error("Se esperaba que se creara la suscripción")
 		}
 	})
 }
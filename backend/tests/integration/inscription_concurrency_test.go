@@ -0,0 +1,142 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// miembroConPlan registra un usuario y le activa el plan Premium
+func miembroConPlan(t *testing.T, adminToken string) (string, int) {
+	token, userID, _ := registerUser(t)
+	subscriptionID := createSubscription(t, token, userID, PlanPremiumID)
+	paymentID := createCashPayment(t, adminToken, userID, subscriptionID, 3000.0)
+	updatePaymentStatus(t, adminToken, paymentID, "completed")
+	activateSubscription(t, token, subscriptionID, paymentID)
+	return token, userID
+}
+
+// inscribirEnParalelo manda todas las inscripciones a la vez y devuelve la cantidad por status code
+func inscribirEnParalelo(t *testing.T, tokens []string, activityID int) map[int]int {
+	body, _ := json.Marshal(map[string]int{"actividad_id": activityID})
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		codigos = make(map[int]int)
+		largada = make(chan struct{})
+	)
+
+	for _, token := range tokens {
+		wg.Add(1)
+		go func(token string) {
+			defer wg.Done()
+			<-largada // Todas las goroutines salen juntas
+
+			req, _ := http.NewRequest("POST", activitiesAPIURL+"/inscripciones", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", token)
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Errorf("❌ Error en inscripción concurrente: %v", err)
+				return
+			}
+			resp.Body.Close()
+
+			mu.Lock()
+			codigos[resp.StatusCode]++
+			mu.Unlock()
+		}(token)
+	}
+
+	close(largada)
+	wg.Wait()
+	return codigos
+}
+
+// lugaresDisponibles consulta los lugares de la actividad (vista actividades_lugares)
+func lugaresDisponibles(t *testing.T, activityID int) int {
+	resp, err := http.Get(fmt.Sprintf("%s/actividades/%d", activitiesAPIURL, activityID))
+	if err != nil {
+		t.Fatalf("❌ Error consultando actividad: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var actividad map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&actividad); err != nil {
+		t.Fatalf("❌ Error decodificando actividad: %v", err)
+	}
+	lugares, _ := actividad["lugares"].(float64)
+	return int(lugares)
+}
+
+// TestConcurrentEnrollmentNeverExceedsCupo martilla una clase desde muchas goroutines:
+// las inscripciones exitosas nunca pueden superar el cupo y el resto recibe 409
+func TestConcurrentEnrollmentNeverExceedsCupo(t *testing.T) {
+	t.Log("🚀 Iniciando test de integración: Concurrent Enrollment")
+
+	adminToken, _ := login(t, "admin", "admin123")
+
+	// createActivity crea la clase con cupo 10
+	const cupo = 10
+	const miembros = 25
+	activity := createActivity(t, adminToken, "Clase Concurrencia", "yoga", 1)
+	activityID := int(activity["id"].(float64))
+	t.Logf("✅ Actividad creada con ID: %d, Cupo: %d", activityID, cupo)
+
+	t.Logf("\n📝 Preparando %d miembros con plan activo", miembros)
+	tokens := make([]string, miembros)
+	for i := range tokens {
+		tokens[i], _ = miembroConPlan(t, adminToken)
+	}
+
+	t.Log("\n📝 Inscribiendo a todos a la vez")
+	codigos := inscribirEnParalelo(t, tokens, activityID)
+	t.Logf("📊 Respuestas: %v", codigos)
+
+	if codigos[http.StatusCreated] != cupo {
+		t.Errorf("❌ Se esperaban %d inscripciones exitosas, hubo %d", cupo, codigos[http.StatusCreated])
+	}
+	if codigos[http.StatusConflict] != miembros-cupo {
+		t.Errorf("❌ Se esperaban %d rechazos 409 por cupo, hubo %d", miembros-cupo, codigos[http.StatusConflict])
+	}
+	if lugares := lugaresDisponibles(t, activityID); lugares != 0 {
+		t.Errorf("❌ La clase debería quedar sin lugares, quedan %d", lugares)
+	}
+
+	t.Log("✅ El cupo no se superó")
+}
+
+// TestConcurrentDuplicateEnrollment manda la misma inscripción muchas veces a la vez:
+// solo una puede crearse, las demás son duplicados (409)
+func TestConcurrentDuplicateEnrollment(t *testing.T) {
+	t.Log("🚀 Iniciando test de integración: Concurrent Duplicate Enrollment")
+
+	adminToken, _ := login(t, "admin", "admin123")
+	activity := createActivity(t, adminToken, "Clase Duplicados", "yoga", 1)
+	activityID := int(activity["id"].(float64))
+
+	token, _ := miembroConPlan(t, adminToken)
+
+	const intentos = 10
+	tokens := make([]string, intentos)
+	for i := range tokens {
+		tokens[i] = token
+	}
+
+	codigos := inscribirEnParalelo(t, tokens, activityID)
+	t.Logf("📊 Respuestas: %v", codigos)
+
+	if codigos[http.StatusCreated] != 1 || codigos[http.StatusConflict] != intentos-1 {
+		t.Errorf("❌ Se esperaba 1 inscripción y %d duplicados, hubo %v", intentos-1, codigos)
+	}
+	if lugares := lugaresDisponibles(t, activityID); lugares != 9 {
+		t.Errorf("❌ Debería quedar ocupado un solo lugar (9 libres), hay %d", lugares)
+	}
+
+	t.Log("✅ Sin inscripciones duplicadas")
+}
@@ -0,0 +1,119 @@
+package integration
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// tokenClaims es el contrato único de claims de los access tokens (shared/auth.Claims)
+type tokenClaims struct {
+	Sub       string   `json:"sub"`
+	Roles     []string `json:"roles"`
+	BranchIDs []uint   `json:"branch_ids"`
+	JTI       string   `json:"jti"`
+	IAT       int64    `json:"iat"`
+	EXP       int64    `json:"exp"`
+}
+
+// decodeClaims lee el payload del JWT (sin verificar la firma, eso lo hacen los servicios)
+func decodeClaims(t *testing.T, bearer string) (tokenClaims, map[string]interface{}) {
+	parts := strings.Split(strings.TrimPrefix(bearer, "Bearer "), ".")
+	if len(parts) != 3 {
+		t.Fatalf("❌ Token con formato inválido: %s", bearer)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("❌ Error decodificando payload: %v", err)
+	}
+
+	var claims tokenClaims
+	var raw map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("❌ Error parseando claims: %v", err)
+	}
+	json.Unmarshal(payload, &raw)
+
+	return claims, raw
+}
+
+// expectStatus hace un request autenticado y verifica el status
+func expectStatus(t *testing.T, method, url, token string, expected int) {
+	req, _ := http.NewRequest(method, url, nil)
+	req.Header.Set("Authorization", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("❌ Error en %s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expected {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		t.Errorf("❌ %s %s - Esperado: %d, Obtenido: %d, Body: %s", method, url, expected, resp.StatusCode, string(bodyBytes))
+		return
+	}
+
+	t.Logf("✅ %s %s - Status: %d", method, url, resp.StatusCode)
+}
+
+// TestJWTClaimsContract valida que un token emitido por /login autoriza igual en todos los servicios
+func TestJWTClaimsContract(t *testing.T) {
+	t.Log("🚀 Iniciando test de integración: JWT Claims Contract")
+
+	// ==================== PASO 1: Login de usuario y admin ====================
+	t.Log("\n📝 PASO 1: Registrar usuario y loguear admin")
+
+	userToken, userID, _ := registerUser(t)
+	adminToken, adminID := login(t, "admin", "admin123")
+
+	// ==================== PASO 2: Verificar el contrato de claims ====================
+	t.Log("\n📝 PASO 2: Verificar claims del token (sub, roles, jti, iat, exp)")
+
+	claims, raw := decodeClaims(t, userToken)
+	if claims.Sub != fmt.Sprintf("%d", userID) {
+		t.Errorf("❌ sub esperado %d, obtenido %q", userID, claims.Sub)
+	}
+	if len(claims.Roles) != 1 || claims.Roles[0] != "user" {
+		t.Errorf("❌ roles esperados [user], obtenidos %v", claims.Roles)
+	}
+	if claims.JTI == "" || claims.IAT == 0 || claims.EXP <= claims.IAT {
+		t.Errorf("❌ jti/iat/exp inválidos: %+v", claims)
+	}
+	for _, legacy := range []string{"id_usuario", "is_admin", "role"} {
+		if _, ok := raw[legacy]; ok {
+			t.Errorf("❌ El token no debería incluir el claim deprecado %q", legacy)
+		}
+	}
+
+	adminClaims, _ := decodeClaims(t, adminToken)
+	if adminClaims.Sub != fmt.Sprintf("%d", adminID) || len(adminClaims.Roles) == 0 || adminClaims.Roles[0] != "admin" {
+		t.Errorf("❌ Claims de admin inválidos: %+v", adminClaims)
+	}
+
+	// ==================== PASO 3: El token de usuario autoriza en todos los servicios ====================
+	t.Log("\n📝 PASO 3: Usuario accede a sus recursos en cada servicio")
+
+	expectStatus(t, "GET", fmt.Sprintf("%s/users/%d", usersAPIURL, userID), userToken, http.StatusOK)
+	expectStatus(t, "GET", activitiesAPIURL+"/inscripciones", userToken, http.StatusOK)
+	expectStatus(t, "GET", fmt.Sprintf("%s/subscriptions/user/%d", subscriptionsAPIURL, userID), userToken, http.StatusOK)
+	expectStatus(t, "GET", fmt.Sprintf("%s/payments/user/%d", paymentsAPIURL, userID), userToken, http.StatusOK)
+
+	// ==================== PASO 4: Rutas de admin ====================
+	t.Log("\n📝 PASO 4: Rutas de admin rechazan al usuario y aceptan al admin")
+
+	expectStatus(t, "GET", usersAPIURL+"/users", userToken, http.StatusForbidden)
+	expectStatus(t, "GET", paymentsAPIURL+"/payments", userToken, http.StatusForbidden)
+	expectStatus(t, "POST", subscriptionsAPIURL+"/subscriptions/expire-overdue", userToken, http.StatusForbidden)
+
+	expectStatus(t, "GET", usersAPIURL+"/users", adminToken, http.StatusOK)
+	expectStatus(t, "GET", paymentsAPIURL+"/payments", adminToken, http.StatusOK)
+	expectStatus(t, "POST", subscriptionsAPIURL+"/subscriptions/expire-overdue", adminToken, http.StatusOK)
+
+	t.Log("\n✅ El mismo token autoriza de forma consistente en users, activities, subscriptions y payments")
+}
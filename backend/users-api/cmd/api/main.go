@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"strings"
 	"time"
+	"users-api/internal/clients"
 	"users-api/internal/config"
 	"users-api/internal/controllers"
 	"users-api/internal/middleware"
@@ -11,6 +14,7 @@ import (
 	"users-api/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gym-management/shared/auth"
 )
 
 func main() {
@@ -25,9 +29,76 @@ func main() {
 
 	// 1️⃣ Capa de datos: Repository (maneja operaciones con MySQL)
 	usersRepo := repository.NewMySQLUsersRepository(cfg.MySQL)
+	tokensRepo := repository.NewMySQLTokensRepository(usersRepo.GetDB()) // Comparte la conexión DB
+	accountTokensRepo := repository.NewMySQLAccountTokensRepository(usersRepo.GetDB())
+	loginAttemptsRepo := repository.NewMySQLLoginAttemptsRepository(usersRepo.GetDB())
+	twoFactorRepo := repository.NewMySQLTwoFactorRepository(usersRepo.GetDB())
+
+	// 🔑 Claves de firma de JWT (RS256/EdDSA): solo users-api tiene las claves privadas
+	signingKeys := loadSigningKeys(cfg)
+
+	// 📧 Emails transaccionales (reset de contraseña, verificación de email)
+	mailer := newMailer(cfg)
+
+	// 🐰 Eventos de usuarios (user.updated, user.disabled, user.deleted, user.locked...): sin RabbitMQ se usa NullEventPublisher
+	var eventPublisher services.EventPublisher
+	rabbitPublisher, err := clients.NewRabbitMQEventPublisher(cfg.RabbitMQURL, cfg.RabbitMQExchange)
+	if err != nil {
+		log.Printf("⚠️  Error conectando a RabbitMQ: %v", err)
+		eventPublisher = clients.NewNullEventPublisher()
+	} else {
+		defer rabbitPublisher.Close()
+		eventPublisher = rabbitPublisher
+	}
+
+	// 🛡️ Protección contra fuerza bruta en el login (por cuenta y por IP, persistida en MySQL)
+	loginGuard := services.NewLoginGuard(loginAttemptsRepo, eventPublisher, services.LoginPolicy{
+		Window:          time.Duration(cfg.LoginProtection.WindowMinutes) * time.Minute,
+		DelayAfter:      cfg.LoginProtection.DelayAfter,
+		BaseDelay:       time.Second,
+		MaxDelay:        time.Duration(cfg.LoginProtection.MaxDelaySeconds) * time.Second,
+		MaxFailures:     cfg.LoginProtection.MaxFailures,
+		LockoutDuration: time.Duration(cfg.LoginProtection.LockoutMinutes) * time.Minute,
+		IPMaxFailures:   cfg.LoginProtection.IPMaxFailures,
+		IPBlockDuration: time.Duration(cfg.LoginProtection.IPBlockMinutes) * time.Minute,
+	})
 
 	// 2️⃣ Capa de lógica de negocio: Service (validaciones, transformaciones, JWT)
-	usersService := services.NewUsersService(usersRepo, cfg.JWT.Secret)
+	usersService := services.NewUsersService(
+		usersRepo,
+		tokensRepo,
+		accountTokensRepo,
+		twoFactorRepo,
+		signingKeys,
+		mailer,
+		eventPublisher,
+		services.AccountLinks{
+			ResetPasswordURL: strings.TrimRight(cfg.AppURL, "/") + "/reset-password",
+			VerifyEmailURL:   strings.TrimRight(cfg.PublicURL, "/") + "/email/verify",
+		},
+		loginGuard,
+		services.TwoFactorPolicy{
+			Issuer:           cfg.TwoFactor.Issuer,
+			RequiredForStaff: cfg.TwoFactor.RequiredForStaff,
+			ChallengeTTL:     time.Duration(cfg.TwoFactor.ChallengeTTLMinutes) * time.Minute,
+		},
+		time.Duration(cfg.JWT.AccessTTLMinutes)*time.Minute,
+		time.Duration(cfg.JWT.RefreshTTLHours)*time.Hour,
+	)
+
+	// 🧹 Limpieza periódica de refresh tokens, revocaciones, tokens de cuenta e intentos de login expirados
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := usersService.PurgeExpiredTokens(context.Background()); err != nil {
+				log.Printf("⚠️  Error limpiando tokens expirados: %v", err)
+			}
+			if err := loginGuard.PurgeExpired(context.Background()); err != nil {
+				log.Printf("⚠️  Error limpiando intentos de login expirados: %v", err)
+			}
+		}
+	}()
 
 	// 3️⃣ Capa de controladores: Controller (maneja HTTP requests/responses)
 	usersController := controllers.NewUsersController(usersService)
@@ -35,6 +106,11 @@ func main() {
 	// 🌐 Configurar router HTTP con Gin
 	router := gin.Default()
 
+	// Solo se confía en X-Forwarded-For si viene de un proxy conocido: si no, la IP del login se podría falsificar
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("❌ TRUSTED_PROXIES inválido: %v", err)
+	}
+
 	// Middleware CORS (debe ir primero)
 	router.Use(middleware.CORSMiddleware())
 
@@ -65,6 +141,13 @@ func main() {
 			}
 		}
 
+		// Check RabbitMQ connection (no degrada: sin broker solo se pierden eventos)
+		if rabbitPublisher != nil && rabbitPublisher.Conn != nil && !rabbitPublisher.Conn.IsClosed() {
+			health["checks"].(gin.H)["rabbitmq"] = "connected"
+		} else {
+			health["checks"].(gin.H)["rabbitmq"] = "unavailable"
+		}
+
 		// Return 503 if degraded
 		if health["status"] == "degraded" {
 			c.JSON(http.StatusServiceUnavailable, health)
@@ -82,24 +165,81 @@ func main() {
 	)
 
 	// Rate limit para login: 5 intentos cada 15 minutos por IP
+	// Los logins fallidos además se cuentan por cuenta y por IP en MySQL (LoginGuard): espera progresiva y bloqueo
 	router.POST("/login",
 		// middleware.RateLimitMiddleware(cfg.RateLimit.LoginAttempts, cfg.RateLimit.LoginWindow),
 		usersController.Login,
 	)
 
+	// 🔐 Segundo paso del login con 2FA: canjea el challenge de /login + código TOTP o de recuperación
+	// Las cuentas que deben configurar 2FA se inscriben con el mismo challenge (/login/2fa/enroll)
+	router.POST("/login/2fa", usersController.LoginTwoFactor)
+	router.POST("/login/2fa/enroll", usersController.EnrollTwoFactorAtLogin)
+
+	// 🔄 Refresh token: rota el refresh token y emite un nuevo access token
+	router.POST("/token/refresh", usersController.Refresh)
+
+	// 🔐 Reset de contraseña (el link llega por email) y verificación de email
+	router.POST("/password/forgot",
+		// middleware.RateLimitMiddleware(cfg.RateLimit.LoginAttempts, cfg.RateLimit.LoginWindow),
+		usersController.ForgotPassword,
+	)
+	router.POST("/password/reset", usersController.ResetPassword)
+	router.GET("/email/verify", usersController.VerifyEmail)
+
+	// 🔑 Claves públicas para verificar los JWT (las cachean los demás microservicios)
+	router.GET("/.well-known/jwks.json", usersController.JWKS)
+
+	// 🚫 Lista de tokens revocados (la sincronizan los demás microservicios)
+	router.GET("/token/revocations", usersController.Revocations)
+
 	// 📚 Rutas protegidas (requieren JWT + rate limiting)
 	protected := router.Group("/")
 	// protected.Use(middleware.RateLimitMiddleware(cfg.RateLimit.PublicRPM, 1)) // 100 req/min
 	protected.Use(middleware.JWTAuthMiddleware(usersService))
 	{
+		// Cierra la sesión: revoca el access token y su familia de refresh tokens
+		protected.POST("/logout", usersController.Logout)
+
+		// Reenvía el link de verificación de email al usuario logueado
+		protected.POST("/email/verify/resend", usersController.ResendEmailVerification)
+
+		// Autogestión del perfil (nombre, email, sucursal de origen, contraseña)
+		protected.PATCH("/users/me", usersController.UpdateMe)
+		protected.PUT("/users/me/password", usersController.ChangePassword)
+
+		// Segundo factor (TOTP) del usuario logueado
+		protected.GET("/users/me/2fa", usersController.TwoFactorStatus)
+		protected.POST("/users/me/2fa/enroll", usersController.EnrollTwoFactor)
+		protected.POST("/users/me/2fa/verify", usersController.ConfirmTwoFactor)
+		protected.POST("/users/me/2fa/recovery-codes", usersController.RegenerateRecoveryCodes)
+		protected.DELETE("/users/me/2fa", usersController.DisableTwoFactor)
+
 		// Endpoint para que otros microservicios validen usuario existe
 		protected.GET("/users/:id", usersController.GetByID)
 
-		// Solo admins pueden listar todos los usuarios
-		adminOnly := protected.Group("/")
-		adminOnly.Use(middleware.AdminOnlyMiddleware())
+		// Listado de usuarios: owners ven todos, el staff solo los de sus sucursales
+		protected.GET("/users", middleware.RequirePermission(auth.PermUsersRead), usersController.List)
+
+		// Administración de cuentas (solo owners): desbloquear, deshabilitar, restaurar y eliminar
+		manageUsers := protected.Group("/")
+		manageUsers.Use(middleware.RequirePermission(auth.PermUsersManage))
+		{
+			manageUsers.POST("/users/:id/unlock", usersController.UnlockUser)
+			manageUsers.POST("/users/:id/disable", usersController.DisableUser)
+			manageUsers.POST("/users/:id/restore", usersController.RestoreUser)
+			manageUsers.POST("/users/:id/2fa/reset", usersController.ResetTwoFactor)
+			manageUsers.DELETE("/users/:id", usersController.DeleteUser)
+		}
+
+		// Roles (solo owners): promover/degradar owners y asignar roles de staff por sucursal
+		manageRoles := protected.Group("/")
+		manageRoles.Use(middleware.RequirePermission(auth.PermRolesManage))
 		{
-			adminOnly.GET("/users", usersController.List)
+			manageRoles.POST("/users/:id/promote", usersController.PromoteUser)
+			manageRoles.POST("/users/:id/demote", usersController.DemoteUser)
+			manageRoles.GET("/users/:id/roles", usersController.GetRoles)
+			manageRoles.PUT("/users/:id/roles", usersController.SetRoles)
 		}
 	}
 
@@ -118,14 +258,89 @@ func main() {
 	log.Printf("   Login: %d attempts per %d minutes", cfg.RateLimit.LoginAttempts, cfg.RateLimit.LoginWindow)
 	log.Printf("   Register: %d attempts per %d minutes per IP", cfg.RateLimit.RegisterAttempts, cfg.RateLimit.RegisterWindow)
 	log.Printf("   Public APIs: %d requests per minute", cfg.RateLimit.PublicRPM)
+	log.Printf("   Login lockout: %d failures per account (%d min), %d per IP, progressive delay after %d",
+		cfg.LoginProtection.MaxFailures, cfg.LoginProtection.LockoutMinutes, cfg.LoginProtection.IPMaxFailures, cfg.LoginProtection.DelayAfter)
+	log.Printf("🔐 2FA (TOTP): required for staff=%t, challenge %d min", cfg.TwoFactor.RequiredForStaff, cfg.TwoFactor.ChallengeTTLMinutes)
 	log.Printf("📚 Endpoints:")
 	log.Printf("   POST   /register - Register new user (rate limited)")
 	log.Printf("   POST   /login - Login user (rate limited)")
+	log.Printf("   POST   /login/2fa - Complete login with TOTP or recovery code")
+	log.Printf("   POST   /login/2fa/enroll - Enroll TOTP during login (2FA required by role)")
+	log.Printf("   POST   /token/refresh - Rotate refresh token")
+	log.Printf("   GET    /token/revocations - List revoked access tokens")
+	log.Printf("   POST   /password/forgot - Send password reset link")
+	log.Printf("   POST   /password/reset - Reset password with emailed token")
+	log.Printf("   GET    /email/verify - Verify email with emailed token")
+	log.Printf("   POST   /email/verify/resend - Resend verification email (protected)")
+	log.Printf("   GET    /.well-known/jwks.json - Public signing keys (JWKS)")
+	log.Printf("   POST   /logout - Revoke current session (protected)")
+	log.Printf("   PATCH  /users/me - Update own profile (protected)")
+	log.Printf("   PUT    /users/me/password - Change own password (protected)")
+	log.Printf("   GET    /users/me/2fa - Two-factor status (protected)")
+	log.Printf("   POST   /users/me/2fa/enroll|verify - Enroll and confirm TOTP (protected)")
+	log.Printf("   POST   /users/me/2fa/recovery-codes - Regenerate recovery codes (protected)")
+	log.Printf("   DELETE /users/me/2fa - Disable TOTP (protected)")
 	log.Printf("   GET    /users/:id - Get user by ID (protected + rate limited)")
-	log.Printf("   GET    /users - List all users (admin only + rate limited)")
+	log.Printf("   GET    /users - List users (users:read, staff scoped to their branches)")
+	log.Printf("   POST   /users/:id/unlock - Unlock account locked by failed logins (users:manage)")
+	log.Printf("   POST   /users/:id/disable|restore - Disable or restore account (users:manage)")
+	log.Printf("   DELETE /users/:id - Delete account permanently (users:manage)")
+	log.Printf("   POST   /users/:id/2fa/reset - Reset lost second factor (users:manage)")
+	log.Printf("   POST   /users/:id/promote|demote - Grant or revoke owner (roles:manage)")
+	log.Printf("   GET    /users/:id/roles - Get user roles (roles:manage)")
+	log.Printf("   PUT    /users/:id/roles - Assign staff roles per branch (roles:manage)")
 
 	// Iniciar servidor (bloquea hasta que se pare el servidor)
 	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		log.Fatalf("❌ Server error: %v", err)
 	}
 }
+
+// loadSigningKeys carga las claves de JWT_KEYS_DIR
+// Sin directorio configurado (solo en local) se genera una clave efímera
+func loadSigningKeys(cfg config.Config) *services.SigningKeys {
+	if cfg.JWT.KeysDir == "" {
+		if cfg.Environment != "local" {
+			log.Fatalf("❌ JWT_KEYS_DIR es obligatorio en el entorno %s", cfg.Environment)
+		}
+
+		keys, err := services.NewEphemeralSigningKeys()
+		if err != nil {
+			log.Fatalf("❌ Error generando clave de firma: %v", err)
+		}
+		kid, _, alg := keys.Active()
+		log.Printf("⚠️  JWT_KEYS_DIR no configurado: usando clave efímera %s (%s), los tokens no sobreviven un reinicio", kid, alg)
+		return keys
+	}
+
+	keys, err := services.LoadSigningKeys(cfg.JWT.KeysDir, cfg.JWT.ActiveKID)
+	if err != nil {
+		log.Fatalf("❌ Error cargando claves de firma: %v", err)
+	}
+	kid, _, alg := keys.Active()
+	log.Printf("🔑 Firmando JWT con la clave %s (%s), %d clave(s) publicada(s)", kid, alg, len(keys.JWKS().Keys))
+	return keys
+}
+
+// newMailer elige la implementación de Mailer
+// Sin SMTP_HOST (solo en local) los emails se guardan como archivos .eml en MAIL_OUTBOX_DIR
+func newMailer(cfg config.Config) services.Mailer {
+	if cfg.Mail.SMTPHost == "" {
+		if cfg.Environment != "local" {
+			log.Fatalf("❌ SMTP_HOST es obligatorio en el entorno %s", cfg.Environment)
+		}
+
+		mailer, err := clients.NewFileMailer(cfg.Mail.OutboxDir, cfg.Mail.From)
+		if err != nil {
+			log.Fatalf("❌ Error inicializando FileMailer: %v", err)
+		}
+		return mailer
+	}
+
+	mailer, err := clients.NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUser, cfg.Mail.SMTPPass, cfg.Mail.From)
+	if err != nil {
+		log.Fatalf("❌ Error inicializando SMTPMailer: %v", err)
+	}
+	log.Printf("📧 Enviando emails vía SMTP %s:%s", cfg.Mail.SMTPHost, cfg.Mail.SMTPPort)
+	return mailer
+}
@@ -0,0 +1,51 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// unsafeFileChars son los caracteres que no se usan en el nombre del archivo .eml
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]`)
+
+// FileMailer guarda cada email como un archivo .eml en un directorio
+// Pensado para desarrollo local: los links de reset/verificación se leen del archivo
+type FileMailer struct {
+	dir  string
+	from *mail.Address
+}
+
+// NewFileMailer crea el mailer y el directorio de salida si no existe
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	fromAddr, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("remitente inválido %q: %w", from, err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating mail outbox %s: %w", dir, err)
+	}
+
+	log.Printf("⚠️  Usando FileMailer - Los emails se guardan en %s", dir)
+	return &FileMailer{dir: dir, from: fromAddr}, nil
+}
+
+// Send escribe el email en <dir>/<timestamp>-<destinatario>.eml
+func (m *FileMailer) Send(ctx context.Context, to, subject, body string) error {
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405.000000000"), unsafeFileChars.ReplaceAllString(to, "_"))
+	path := filepath.Join(m.dir, name)
+
+	if err := os.WriteFile(path, buildMessage(m.from, to, subject, body, now), 0o644); err != nil {
+		return fmt.Errorf("error writing email to %s: %w", path, err)
+	}
+
+	log.Printf("📧 [FileMailer] Email para %s guardado en %s", to, path)
+	return nil
+}
@@ -0,0 +1,33 @@
+package clients
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/mail"
+	"time"
+)
+
+// Message representa un email enviado (lo guardan MemoryMailer y FileMailer)
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Body    string
+	SentAt  time.Time
+}
+
+// buildMessage arma el email en formato RFC 5322 (texto plano UTF-8)
+func buildMessage(from *mail.Address, to, subject, body string, date time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(body)
+	return buf.Bytes()
+}
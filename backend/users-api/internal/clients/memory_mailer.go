@@ -0,0 +1,59 @@
+package clients
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryMailer guarda los emails en memoria (tests)
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+	err      error
+}
+
+// NewMemoryMailer crea un mailer en memoria
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Send guarda el email (o devuelve el error configurado con FailWith)
+func (m *MemoryMailer) Send(ctx context.Context, to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	m.messages = append(m.messages, Message{To: to, Subject: subject, Body: body, SentAt: time.Now()})
+	return nil
+}
+
+// FailWith hace que los próximos envíos fallen con err (nil vuelve a aceptar envíos)
+func (m *MemoryMailer) FailWith(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
+
+// Messages devuelve una copia de los emails enviados
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.messages...)
+}
+
+// LastTo devuelve el último email enviado al destinatario
+func (m *MemoryMailer) LastTo(to string) (Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].To == to {
+			return m.messages[i], true
+		}
+	}
+	return Message{}, false
+}
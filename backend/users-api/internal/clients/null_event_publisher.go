@@ -0,0 +1,18 @@
+package clients
+
+import "log"
+
+// NullEventPublisher - Implementación que no hace nada cuando RabbitMQ no está disponible
+type NullEventPublisher struct{}
+
+// NewNullEventPublisher - Constructor
+func NewNullEventPublisher() *NullEventPublisher {
+	log.Println("⚠️  Usando NullEventPublisher - Los eventos no serán publicados")
+	return &NullEventPublisher{}
+}
+
+// PublishUserEvent - Implementa la interface EventPublisher sin hacer nada
+func (n *NullEventPublisher) PublishUserEvent(action, userID string, data map[string]interface{}) error {
+	log.Printf("⚠️  [NullEventPublisher] Evento no publicado: user.%s (ID: %s)", action, userID)
+	return nil
+}
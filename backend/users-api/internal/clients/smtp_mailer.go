@@ -0,0 +1,88 @@
+package clients
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// SMTPMailer envía emails a través de un servidor SMTP (STARTTLS si el servidor lo ofrece)
+type SMTPMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     *mail.Address
+}
+
+// NewSMTPMailer crea un mailer SMTP
+// from acepta "Nombre <email>" o solo el email
+func NewSMTPMailer(host, port, username, password, from string) (*SMTPMailer, error) {
+	fromAddr, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("remitente inválido %q: %w", from, err)
+	}
+
+	return &SMTPMailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     fromAddr,
+	}, nil
+}
+
+// Send envía el email respetando la cancelación/deadline del contexto
+func (m *SMTPMailer) Send(ctx context.Context, to, subject, body string) error {
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.host, m.port))
+	if err != nil {
+		return fmt.Errorf("error connecting to SMTP server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error starting SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return fmt.Errorf("error starting TLS: %w", err)
+		}
+	}
+
+	if m.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return fmt.Errorf("error authenticating with SMTP server: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.from.Address); err != nil {
+		return fmt.Errorf("error setting sender: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("error setting recipient: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("error opening message body: %w", err)
+	}
+	if _, err := w.Write(buildMessage(m.from, to, subject, body, time.Now())); err != nil {
+		return fmt.Errorf("error writing message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("error sending message: %w", err)
+	}
+
+	return client.Quit()
+}
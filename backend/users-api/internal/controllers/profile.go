@@ -0,0 +1,300 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"users-api/internal/domain"
+	"users-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateMe maneja PATCH /users/me - Actualiza el perfil del usuario logueado
+// @Summary Actualiza el propio perfil
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param body body domain.UpdateProfileRequest true "Campos a modificar"
+// @Success 200 {object} domain.UserResponse
+// @Failure 400 {object} map[string]interface{} "error, details"
+// @Failure 409 {object} map[string]interface{} "error, details"
+// @Router /users/me [patch]
+func (c *UsersController) UpdateMe(ctx *gin.Context) {
+	var req domain.UpdateProfileRequest
+
+	// Parsear JSON del body
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	user, err := c.service.UpdateProfile(ctx.Request.Context(), ctx.GetUint("id_usuario"), req)
+	if err != nil {
+		ctx.JSON(userErrorStatus(err), gin.H{
+			"error":   "Failed to update profile",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+// ChangePassword maneja PUT /users/me/password - Cambia la contraseña del usuario logueado
+// @Summary Cambia la propia contraseña
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param body body domain.ChangePasswordRequest true "Contraseña actual y nueva"
+// @Success 200 {object} domain.TokenPair
+// @Failure 400 {object} map[string]interface{} "error, details"
+// @Failure 403 {object} map[string]interface{} "error, details"
+// @Router /users/me/password [put]
+func (c *UsersController) ChangePassword(ctx *gin.Context) {
+	var req domain.ChangePasswordRequest
+
+	// Parsear JSON del body
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Las demás sesiones se cierran: se devuelven tokens nuevos para la sesión actual
+	tokens, err := c.service.ChangePassword(ctx.Request.Context(), ctx.GetUint("id_usuario"), req)
+	if err != nil {
+		ctx.JSON(userErrorStatus(err), gin.H{
+			"error":   "Failed to change password",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
+// DisableUser maneja POST /users/:id/disable - Deshabilita una cuenta y cierra sus sesiones (solo admins)
+// @Summary Deshabilita un usuario
+// @Tags users
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{} "error, details"
+// @Failure 404 {object} map[string]interface{} "error, details"
+// @Router /users/{id}/disable [post]
+func (c *UsersController) DisableUser(ctx *gin.Context) {
+	id, ok := userIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DisableUser(ctx.Request.Context(), ctx.GetUint("id_usuario"), id); err != nil {
+		ctx.JSON(userErrorStatus(err), gin.H{
+			"error":   "Failed to disable user",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RestoreUser maneja POST /users/:id/restore - Vuelve a habilitar una cuenta (solo admins)
+// @Summary Restaura un usuario deshabilitado
+// @Tags users
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 404 {object} map[string]interface{} "error, details"
+// @Router /users/{id}/restore [post]
+func (c *UsersController) RestoreUser(ctx *gin.Context) {
+	id, ok := userIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.RestoreUser(ctx.Request.Context(), id); err != nil {
+		ctx.JSON(userErrorStatus(err), gin.H{
+			"error":   "Failed to restore user",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// PromoteUser maneja POST /users/:id/promote - Da permisos de admin (solo admins)
+// @Summary Promueve un usuario a admin
+// @Tags users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} domain.UserResponse
+// @Failure 404 {object} map[string]interface{} "error, details"
+// @Router /users/{id}/promote [post]
+func (c *UsersController) PromoteUser(ctx *gin.Context) {
+	c.setAdmin(ctx, true)
+}
+
+// DemoteUser maneja POST /users/:id/demote - Quita los permisos de admin y cierra sus sesiones (solo admins)
+// @Summary Degrada un admin a usuario
+// @Tags users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} domain.UserResponse
+// @Failure 400 {object} map[string]interface{} "error, details"
+// @Failure 404 {object} map[string]interface{} "error, details"
+// @Router /users/{id}/demote [post]
+func (c *UsersController) DemoteUser(ctx *gin.Context) {
+	c.setAdmin(ctx, false)
+}
+
+// DeleteUser maneja DELETE /users/:id - Elimina una cuenta de forma definitiva (solo admins)
+// @Summary Elimina un usuario
+// @Tags users
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{} "error, details"
+// @Failure 404 {object} map[string]interface{} "error, details"
+// @Router /users/{id} [delete]
+func (c *UsersController) DeleteUser(ctx *gin.Context) {
+	id, ok := userIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteUser(ctx.Request.Context(), ctx.GetUint("id_usuario"), id); err != nil {
+		ctx.JSON(userErrorStatus(err), gin.H{
+			"error":   "Failed to delete user",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetRoles maneja GET /users/:id/roles - Devuelve los roles del usuario (solo owners)
+// @Summary Roles de un usuario
+// @Tags users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} domain.UserRolesResponse
+// @Failure 404 {object} map[string]interface{} "error, details"
+// @Router /users/{id}/roles [get]
+func (c *UsersController) GetRoles(ctx *gin.Context) {
+	id, ok := userIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	roles, err := c.service.GetRoles(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.JSON(userErrorStatus(err), gin.H{
+			"error":   "Failed to get user roles",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, roles)
+}
+
+// SetRoles maneja PUT /users/:id/roles - Reemplaza los roles de staff por sucursal y cierra sus sesiones (solo owners)
+// @Summary Asigna roles de staff a un usuario
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param body body domain.SetRolesRequest true "Roles de staff por sucursal"
+// @Success 200 {object} domain.UserRolesResponse
+// @Failure 400 {object} map[string]interface{} "error, details"
+// @Failure 404 {object} map[string]interface{} "error, details"
+// @Router /users/{id}/roles [put]
+func (c *UsersController) SetRoles(ctx *gin.Context) {
+	id, ok := userIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.SetRolesRequest
+
+	// Parsear JSON del body
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	roles, err := c.service.SetRoles(ctx.Request.Context(), ctx.GetUint("id_usuario"), id, req)
+	if err != nil {
+		ctx.JSON(userErrorStatus(err), gin.H{
+			"error":   "Failed to update user roles",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, roles)
+}
+
+// setAdmin promueve o degrada al usuario del path
+func (c *UsersController) setAdmin(ctx *gin.Context, isAdmin bool) {
+	id, ok := userIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	user, err := c.service.SetAdmin(ctx.Request.Context(), ctx.GetUint("id_usuario"), id, isAdmin)
+	if err != nil {
+		ctx.JSON(userErrorStatus(err), gin.H{
+			"error":   "Failed to update user role",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+// userIDParam lee el :id del path; si es inválido responde 400
+func userIDParam(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid user ID",
+			"details": "ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// userErrorStatus traduce los errores de gestión de usuarios a códigos HTTP
+func userErrorStatus(err error) int {
+	switch {
+	case err.Error() == "user not found":
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidProfile),
+		errors.Is(err, services.ErrInvalidPassword),
+		errors.Is(err, services.ErrCannotModifySelf),
+		errors.Is(err, services.ErrInvalidRole):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrInvalidCurrentPassword):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrEmailInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
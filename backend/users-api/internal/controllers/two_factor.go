@@ -0,0 +1,280 @@
+package controllers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"users-api/internal/domain"
+	"users-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoginTwoFactor maneja POST /login/2fa - Segundo paso del login
+// Canjea el challenge de POST /login y un código TOTP o de recuperación por los tokens
+// Si la cuenta se estaba inscribiendo también devuelve sus códigos de recuperación
+// @Summary Completa el login con segundo factor
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body domain.TwoFactorLoginRequest true "Challenge y código"
+// @Success 200 {object} map[string]interface{} "user, token, refresh_token, expires_in, recovery_codes"
+// @Failure 400 {object} map[string]interface{} "error, details"
+// @Failure 401 {object} map[string]interface{} "error, details"
+// @Failure 423 {object} map[string]interface{} "error, details, retry_after"
+// @Failure 429 {object} map[string]interface{} "error, details, retry_after"
+// @Router /login/2fa [post]
+func (c *UsersController) LoginTwoFactor(ctx *gin.Context) {
+	var req domain.TwoFactorLoginRequest
+
+	// Parsear JSON del body
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Los códigos fallidos se cuentan por cuenta y por IP, igual que las contraseñas
+	req.ClientIP = ctx.ClientIP()
+
+	user, tokens, recoveryCodes, err := c.service.CompleteTwoFactorLogin(ctx.Request.Context(), req)
+	if respondLoginBlocked(ctx, err) {
+		return
+	}
+	if err != nil {
+		ctx.JSON(twoFactorErrorStatus(err), gin.H{
+			"error":   "Two-factor login failed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response := gin.H{
+		"user":          user,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	}
+	if recoveryCodes != nil {
+		response["recovery_codes"] = recoveryCodes
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// EnrollTwoFactorAtLogin maneja POST /login/2fa/enroll - Inscripción TOTP obligatoria durante el login
+// Para cuentas cuyo rol exige 2FA y que recibieron enrollment_required en POST /login
+// @Summary Inscribe el segundo factor con el challenge del login
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body domain.ChallengeRequest true "Challenge"
+// @Success 200 {object} domain.TwoFactorEnrollment
+// @Failure 401 {object} map[string]interface{} "error, details"
+// @Failure 409 {object} map[string]interface{} "error, details"
+// @Router /login/2fa/enroll [post]
+func (c *UsersController) EnrollTwoFactorAtLogin(ctx *gin.Context) {
+	var req domain.ChallengeRequest
+
+	// Parsear JSON del body
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	enrollment, err := c.service.EnrollTwoFactorWithChallenge(ctx.Request.Context(), req.ChallengeToken)
+	if err != nil {
+		ctx.JSON(twoFactorErrorStatus(err), gin.H{
+			"error":   "Failed to enroll two-factor",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, enrollment)
+}
+
+// TwoFactorStatus maneja GET /users/me/2fa - Estado del segundo factor del usuario logueado
+// @Summary Estado del segundo factor
+// @Tags users
+// @Produce json
+// @Success 200 {object} domain.TwoFactorStatus
+// @Router /users/me/2fa [get]
+func (c *UsersController) TwoFactorStatus(ctx *gin.Context) {
+	status, err := c.service.GetTwoFactorStatus(ctx.Request.Context(), ctx.GetUint("id_usuario"))
+	if err != nil {
+		ctx.JSON(twoFactorErrorStatus(err), gin.H{
+			"error":   "Failed to get two-factor status",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, status)
+}
+
+// EnrollTwoFactor maneja POST /users/me/2fa/enroll - Genera el secreto TOTP (pendiente de confirmar)
+// @Summary Inicia la inscripción del segundo factor
+// @Tags users
+// @Produce json
+// @Success 200 {object} domain.TwoFactorEnrollment
+// @Failure 409 {object} map[string]interface{} "error, details"
+// @Router /users/me/2fa/enroll [post]
+func (c *UsersController) EnrollTwoFactor(ctx *gin.Context) {
+	enrollment, err := c.service.EnrollTwoFactor(ctx.Request.Context(), ctx.GetUint("id_usuario"))
+	if err != nil {
+		ctx.JSON(twoFactorErrorStatus(err), gin.H{
+			"error":   "Failed to enroll two-factor",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmTwoFactor maneja POST /users/me/2fa/verify - Activa el segundo factor con el primer código
+// @Summary Confirma la inscripción del segundo factor
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param body body domain.TwoFactorCodeRequest true "Código TOTP"
+// @Success 200 {object} domain.RecoveryCodesResponse
+// @Failure 400 {object} map[string]interface{} "error, details"
+// @Failure 401 {object} map[string]interface{} "error, details"
+// @Router /users/me/2fa/verify [post]
+func (c *UsersController) ConfirmTwoFactor(ctx *gin.Context) {
+	c.withTwoFactorCode(ctx, "Failed to verify two-factor", func(userID uint, code string) (interface{}, error) {
+		return c.service.ConfirmTwoFactor(ctx.Request.Context(), userID, code)
+	})
+}
+
+// RegenerateRecoveryCodes maneja POST /users/me/2fa/recovery-codes - Genera códigos de recuperación nuevos
+// @Summary Regenera los códigos de recuperación
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param body body domain.TwoFactorCodeRequest true "Código TOTP o de recuperación"
+// @Success 200 {object} domain.RecoveryCodesResponse
+// @Failure 401 {object} map[string]interface{} "error, details"
+// @Router /users/me/2fa/recovery-codes [post]
+func (c *UsersController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	c.withTwoFactorCode(ctx, "Failed to regenerate recovery codes", func(userID uint, code string) (interface{}, error) {
+		return c.service.RegenerateRecoveryCodes(ctx.Request.Context(), userID, code)
+	})
+}
+
+// DisableTwoFactor maneja DELETE /users/me/2fa - Desactiva el segundo factor
+// @Summary Desactiva el segundo factor
+// @Tags users
+// @Accept json
+// @Param body body domain.TwoFactorCodeRequest true "Código TOTP o de recuperación"
+// @Success 204
+// @Failure 401 {object} map[string]interface{} "error, details"
+// @Failure 403 {object} map[string]interface{} "error, details"
+// @Router /users/me/2fa [delete]
+func (c *UsersController) DisableTwoFactor(ctx *gin.Context) {
+	c.withTwoFactorCode(ctx, "Failed to disable two-factor", func(userID uint, code string) (interface{}, error) {
+		return nil, c.service.DisableTwoFactor(ctx.Request.Context(), userID, code)
+	})
+}
+
+// ResetTwoFactor maneja POST /users/:id/2fa/reset - Borra el segundo factor de un usuario (solo owners)
+// @Summary Resetea el segundo factor de un usuario
+// @Tags users
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 404 {object} map[string]interface{} "error, details"
+// @Router /users/{id}/2fa/reset [post]
+func (c *UsersController) ResetTwoFactor(ctx *gin.Context) {
+	id, ok := userIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.ResetTwoFactor(ctx.Request.Context(), ctx.GetUint("id_usuario"), id); err != nil {
+		ctx.JSON(twoFactorErrorStatus(err), gin.H{
+			"error":   "Failed to reset two-factor",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// withTwoFactorCode parsea el código del body y ejecuta la acción del usuario logueado
+// Responde 200 con el resultado, o 204 si la acción no devuelve nada
+func (c *UsersController) withTwoFactorCode(ctx *gin.Context, failure string, action func(userID uint, code string) (interface{}, error)) {
+	var req domain.TwoFactorCodeRequest
+
+	// Parsear JSON del body
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := action(ctx.GetUint("id_usuario"), req.Code)
+	if err != nil {
+		ctx.JSON(twoFactorErrorStatus(err), gin.H{
+			"error":   failure,
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if result == nil {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// respondLoginBlocked responde 429 (espera progresiva o IP bloqueada) o 423 (cuenta bloqueada)
+// Devuelve false si err no es un LoginBlockedError
+func respondLoginBlocked(ctx *gin.Context, err error) bool {
+	var blocked *services.LoginBlockedError
+	if !errors.As(err, &blocked) {
+		return false
+	}
+
+	statusCode := http.StatusTooManyRequests
+	if errors.Is(err, services.ErrAccountLocked) {
+		statusCode = http.StatusLocked
+	}
+
+	retryAfter := int(math.Ceil(blocked.RetryAfter.Seconds()))
+	ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+	ctx.JSON(statusCode, gin.H{
+		"error":       "Login blocked",
+		"details":     blocked.Reason.Error(),
+		"retry_after": retryAfter,
+	})
+	return true
+}
+
+// twoFactorErrorStatus traduce los errores del segundo factor a códigos HTTP
+func twoFactorErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrInvalidTwoFactorCode),
+		errors.Is(err, services.ErrInvalidLoginChallenge):
+		return http.StatusUnauthorized
+	case errors.Is(err, services.ErrTwoFactorRequiredForRole):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrTwoFactorAlreadyEnabled):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrTwoFactorNotEnabled),
+		errors.Is(err, services.ErrTwoFactorNotEnrolled):
+		return http.StatusBadRequest
+	default:
+		return userErrorStatus(err)
+	}
+}
@@ -1,12 +1,15 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"users-api/internal/domain"
+	"users-api/internal/middleware"
 	"users-api/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gym-management/shared/auth"
 )
 
 // UsersController maneja las peticiones HTTP para usuarios
@@ -28,7 +31,7 @@ func NewUsersController(usersService services.UsersService) *UsersController {
 // @Accept json
 // @Produce json
 // @Param user body domain.UserRegister true "Datos del usuario"
-// @Success 201 {object} map[string]interface{} "user, token"
+// @Success 201 {object} map[string]interface{} "user, token, refresh_token, expires_in"
 // @Failure 400 {object} map[string]interface{} "error, details"
 // @Failure 500 {object} map[string]interface{} "error, details"
 // @Router /register [post]
@@ -45,7 +48,7 @@ func (c *UsersController) Register(ctx *gin.Context) {
 	}
 
 	// Llamar al service
-	user, token, err := c.service.Register(ctx.Request.Context(), userReg)
+	user, tokens, err := c.service.Register(ctx.Request.Context(), userReg)
 	if err != nil {
 		// LOG DETALLADO DEL ERROR
 		ctx.Writer.Header().Set("X-Debug-Error", err.Error())
@@ -90,20 +93,26 @@ func (c *UsersController) Register(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusCreated, gin.H{
-		"user":  user,
-		"token": token,
+		"user":          user,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
 	})
 }
 
 // Login maneja POST /login - Autentica un usuario
+// Si la cuenta tiene (o debe tener) segundo factor responde un domain.LoginChallenge en lugar de los tokens
 // @Summary Login de usuario
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param credentials body domain.UserLogin true "Credenciales"
-// @Success 200 {object} map[string]interface{} "user, token"
+// @Success 200 {object} map[string]interface{} "user, token, refresh_token, expires_in (o domain.LoginChallenge)"
 // @Failure 400 {object} map[string]interface{} "error, details"
 // @Failure 401 {object} map[string]interface{} "error, details"
+// @Failure 403 {object} map[string]interface{} "error, details"
+// @Failure 423 {object} map[string]interface{} "error, details, retry_after"
+// @Failure 429 {object} map[string]interface{} "error, details, retry_after"
 // @Router /login [post]
 func (c *UsersController) Login(ctx *gin.Context) {
 	var credentials domain.UserLogin
@@ -117,8 +126,28 @@ func (c *UsersController) Login(ctx *gin.Context) {
 		return
 	}
 
+	// La IP se resuelve con los proxies de confianza (X-Forwarded-For solo si viene de uno)
+	credentials.ClientIP = ctx.ClientIP()
+
 	// Llamar al service
-	user, token, err := c.service.Login(ctx.Request.Context(), credentials)
+	user, tokens, err := c.service.Login(ctx.Request.Context(), credentials)
+
+	// Contraseña correcta pero falta el segundo factor: se devuelve el challenge para POST /login/2fa
+	var twoFactor *services.TwoFactorRequiredError
+	if errors.As(err, &twoFactor) {
+		ctx.JSON(http.StatusOK, twoFactor.Challenge)
+		return
+	}
+	if respondLoginBlocked(ctx, err) {
+		return
+	}
+	if errors.Is(err, services.ErrUserDisabled) {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"error":   "Account disabled",
+			"details": err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, gin.H{
 			"error":   "Invalid credentials",
@@ -128,11 +157,278 @@ func (c *UsersController) Login(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
-		"user":  user,
-		"token": token,
+		"user":          user,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
+// Refresh maneja POST /token/refresh - Rota el refresh token y emite un nuevo access token
+// @Summary Refresca la sesión
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body domain.RefreshRequest true "Refresh token"
+// @Success 200 {object} domain.TokenPair
+// @Failure 400 {object} map[string]interface{} "error, details"
+// @Failure 401 {object} map[string]interface{} "error, details"
+// @Router /token/refresh [post]
+func (c *UsersController) Refresh(ctx *gin.Context) {
+	var req domain.RefreshRequest
+
+	// Parsear JSON del body
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Llamar al service
+	tokens, err := c.service.RefreshTokens(ctx.Request.Context(), req.RefreshToken)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Invalid refresh token",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+// Logout maneja POST /logout - Revoca el access token actual y su familia de refresh tokens
+// @Summary Cierra la sesión
+// @Tags auth
+// @Accept json
+// @Param body body domain.LogoutRequest false "Refresh token (opcional)"
+// @Success 204
+// @Failure 500 {object} map[string]interface{} "error, details"
+// @Router /logout [post]
+func (c *UsersController) Logout(ctx *gin.Context) {
+	// El body es opcional: sin refresh token se revoca la sesión asociada al access token
+	var req domain.LogoutRequest
+	_ = ctx.ShouldBindJSON(&req)
+
+	userID := ctx.GetUint("id_usuario")
+	jti := ctx.GetString("jti")
+
+	if err := c.service.Logout(ctx.Request.Context(), userID, jti, req.RefreshToken); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to logout",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ForgotPassword maneja POST /password/forgot - Envía un link de reset de contraseña
+// Responde 202 exista o no la cuenta (no permite enumerar emails registrados)
+// @Summary Solicita el reset de contraseña
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body domain.ForgotPasswordRequest true "Email"
+// @Success 202 {object} map[string]interface{} "message"
+// @Failure 400 {object} map[string]interface{} "error, details"
+// @Failure 500 {object} map[string]interface{} "error, details"
+// @Router /password/forgot [post]
+func (c *UsersController) ForgotPassword(ctx *gin.Context) {
+	var req domain.ForgotPasswordRequest
+
+	// Parsear JSON del body
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Llamar al service
+	if err := c.service.ForgotPassword(ctx.Request.Context(), req.Email); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to process password reset",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{
+		"message": "Si el email está registrado, vas a recibir un link para restablecer la contraseña",
+	})
+}
+
+// ResetPassword maneja POST /password/reset - Cambia la contraseña con el token recibido por email
+// Revoca todas las sesiones del usuario
+// @Summary Restablece la contraseña
+// @Tags auth
+// @Accept json
+// @Param body body domain.ResetPasswordRequest true "Token y nueva contraseña"
+// @Success 204
+// @Failure 400 {object} map[string]interface{} "error, details"
+// @Failure 500 {object} map[string]interface{} "error, details"
+// @Router /password/reset [post]
+func (c *UsersController) ResetPassword(ctx *gin.Context) {
+	var req domain.ResetPasswordRequest
+
+	// Parsear JSON del body
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid JSON format",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Llamar al service
+	if err := c.service.ResetPassword(ctx.Request.Context(), req.Token, req.Password); err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidAccountToken) || errors.Is(err, services.ErrInvalidPassword) {
+			statusCode = http.StatusBadRequest
+		}
+
+		ctx.JSON(statusCode, gin.H{
+			"error":   "Failed to reset password",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// VerifyEmail maneja GET /email/verify?token= - Confirma el email con el link enviado al registrarse
+// @Summary Verifica el email
+// @Tags auth
+// @Produce json
+// @Param token query string true "Token de verificación"
+// @Success 200 {object} map[string]interface{} "message"
+// @Failure 400 {object} map[string]interface{} "error, details"
+// @Failure 500 {object} map[string]interface{} "error, details"
+// @Router /email/verify [get]
+func (c *UsersController) VerifyEmail(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Missing token",
+			"details": "token query param is required",
+		})
+		return
+	}
+
+	// Llamar al service
+	if err := c.service.VerifyEmail(ctx.Request.Context(), token); err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidAccountToken) {
+			statusCode = http.StatusBadRequest
+		}
+
+		ctx.JSON(statusCode, gin.H{
+			"error":   "Failed to verify email",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Email verificado. Volvé a iniciar sesión (o refrescá el token) para que se refleje en tu sesión",
+	})
+}
+
+// ResendEmailVerification maneja POST /email/verify/resend - Reenvía el link de verificación al usuario logueado
+// @Summary Reenvía el email de verificación
+// @Tags auth
+// @Produce json
+// @Success 202 {object} map[string]interface{} "message"
+// @Failure 409 {object} map[string]interface{} "error, details"
+// @Failure 500 {object} map[string]interface{} "error, details"
+// @Router /email/verify/resend [post]
+func (c *UsersController) ResendEmailVerification(ctx *gin.Context) {
+	userID := ctx.GetUint("id_usuario")
+
+	if err := c.service.ResendEmailVerification(ctx.Request.Context(), userID); err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, services.ErrEmailAlreadyVerified) {
+			statusCode = http.StatusConflict
+		}
+
+		ctx.JSON(statusCode, gin.H{
+			"error":   "Failed to resend verification email",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{
+		"message": "Te enviamos un nuevo link de verificación",
+	})
+}
+
+// Revocations maneja GET /token/revocations - Lista los access tokens revocados vigentes
+// Lo consultan periódicamente los demás microservicios (solo expone jti y expiración)
+// @Summary Lista de tokens revocados
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]interface{} "revocations"
+// @Failure 500 {object} map[string]interface{} "error, details"
+// @Router /token/revocations [get]
+func (c *UsersController) Revocations(ctx *gin.Context) {
+	revoked, err := c.service.ListRevokedTokens(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list revoked tokens",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"revocations": revoked,
 	})
 }
 
+// JWKS maneja GET /.well-known/jwks.json - Publica las claves públicas de firma
+// Los demás microservicios verifican los access tokens con estas claves (por kid)
+// @Summary Claves públicas para verificar JWT
+// @Tags auth
+// @Produce json
+// @Success 200 {object} auth.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (c *UsersController) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, c.service.JWKS())
+}
+
+// UnlockUser maneja POST /users/:id/unlock - Desbloquea una cuenta bloqueada por logins fallidos (solo admins)
+// @Summary Desbloquea una cuenta
+// @Tags users
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{} "error, details"
+// @Failure 404 {object} map[string]interface{} "error, details"
+// @Router /users/{id}/unlock [post]
+func (c *UsersController) UnlockUser(ctx *gin.Context) {
+	id, ok := userIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.UnlockUser(ctx.Request.Context(), id); err != nil {
+		ctx.JSON(userErrorStatus(err), gin.H{
+			"error":   "Failed to unlock user",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
 // GetByID maneja GET /users/:id - Obtiene un usuario por ID
 // @Summary Obtiene un usuario por ID
 // @Tags users
@@ -172,67 +468,65 @@ func (c *UsersController) GetByID(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, user)
 }
 
-// List maneja GET /users - Lista todos los usuarios con paginación
-// @Summary Lista todos los usuarios
+// List maneja GET /users - Lista usuarios con filtros, orden y paginación
+// El staff con users:read solo puede listar filtrando por una de sus sucursales
+// @Summary Lista usuarios
 // @Tags users
 // @Produce json
 // @Param page query int false "Número de página (default: 1)"
 // @Param limit query int false "Registros por página (default: 20, max: 100)"
-// @Success 200 {object} map[string]interface{} "data, page, limit, total, total_pages"
+// @Param q query string false "Substring de nombre, apellido, username o email"
+// @Param is_admin query bool false "Solo admins (true) o solo usuarios (false)"
+// @Param sucursal_origen_id query int false "Sucursal de origen"
+// @Param registered_from query string false "Registrados desde (YYYY-MM-DD)"
+// @Param registered_to query string false "Registrados hasta, inclusive (YYYY-MM-DD)"
+// @Param disabled query bool false "Solo deshabilitados (true) o solo activos (false)"
+// @Param sort_by query string false "id | nombre | apellido | username | email | fecha_registro (default: id)"
+// @Param sort_order query string false "asc | desc (default: asc)"
+// @Success 200 {object} domain.UserListResponse
+// @Failure 400 {object} map[string]interface{} "error, details"
+// @Failure 403 {object} map[string]interface{} "error, details"
 // @Failure 500 {object} map[string]interface{} "error, details"
 // @Router /users [get]
 func (c *UsersController) List(ctx *gin.Context) {
-	// Parámetros de paginación
-	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	var query domain.UserListQuery
 
-	// Validar parámetros
-	if page < 1 {
-		page = 1
+	// Parsear query params (page/limit fuera de rango toman el valor por defecto en el service)
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
 	}
-	if limit < 1 || limit > 100 {
-		limit = 20
+
+	// El staff (receptionist, branch_manager) solo lista los usuarios de sus sucursales
+	if claims, ok := middleware.ClaimsFromContext(ctx); ok && !claims.HasGlobalPermission(auth.PermUsersRead) {
+		if query.SucursalOrigenID == nil || !claims.CanInBranch(auth.PermUsersRead, *query.SucursalOrigenID) {
+			ctx.JSON(http.StatusForbidden, gin.H{
+				"error":   "Forbidden",
+				"details": "sucursal_origen_id must be one of your branches",
+			})
+			return
+		}
 	}
 
 	// Llamar al service
-	users, err := c.service.List(ctx.Request.Context())
+	users, err := c.service.List(ctx.Request.Context(), query)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidListQuery) {
+			statusCode = http.StatusBadRequest
+		}
+
+		ctx.JSON(statusCode, gin.H{
 			"error":   "Failed to list users",
 			"details": err.Error(),
 		})
 		return
 	}
 
-	// Calcular paginación
-	total := len(users)
-	offset := (page - 1) * limit
-	end := offset + limit
-
-	if offset >= total {
-		ctx.JSON(http.StatusOK, gin.H{
-			"data":        []domain.UserResponse{},
-			"page":        page,
-			"limit":       limit,
-			"total":       total,
-			"total_pages": (total + limit - 1) / limit,
-		})
-		return
-	}
-
-	if end > total {
-		end = total
-	}
-
-	paginatedUsers := users[offset:end]
-
-	ctx.JSON(http.StatusOK, gin.H{
-		"data":        paginatedUsers,
-		"page":        page,
-		"limit":       limit,
-		"total":       total,
-		"total_pages": (total + limit - 1) / limit,
-	})
+	ctx.JSON(http.StatusOK, users)
 }
 
 // Helper functions
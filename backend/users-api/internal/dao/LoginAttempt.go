@@ -0,0 +1,33 @@
+package dao
+
+import (
+	"time"
+	"users-api/internal/domain"
+)
+
+// IntentoLogin representa los logins fallidos de una cuenta o IP
+// Se guarda en MySQL para que el bloqueo sobreviva reinicios y se comparta entre réplicas
+type IntentoLogin struct {
+	ID             uint       `gorm:"column:id_intento_login;primaryKey;autoIncrement"`
+	Tipo           string     `gorm:"column:tipo;type:varchar(10);not null;uniqueIndex:uk_tipo_clave"` // usuario | ip
+	Clave          string     `gorm:"column:clave;type:varchar(64);not null;uniqueIndex:uk_tipo_clave"`
+	Fallos         int        `gorm:"column:fallos;not null;default:0"`
+	UltimoFallo    time.Time  `gorm:"column:ultimo_fallo;not null"`
+	BloqueadoHasta *time.Time `gorm:"column:bloqueado_hasta"`
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (IntentoLogin) TableName() string {
+	return "intentos_login"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (i IntentoLogin) ToDomain() domain.LoginAttempt {
+	return domain.LoginAttempt{
+		Kind:          i.Tipo,
+		Key:           i.Clave,
+		Failures:      i.Fallos,
+		LastFailureAt: i.UltimoFallo,
+		BlockedUntil:  i.BloqueadoHasta,
+	}
+}
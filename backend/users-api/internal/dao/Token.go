@@ -0,0 +1,108 @@
+package dao
+
+import (
+	"time"
+	"users-api/internal/domain"
+)
+
+// RefreshToken representa el modelo de base de datos de un refresh token
+type RefreshToken struct {
+	ID             uint       `gorm:"column:id_refresh_token;primaryKey;autoIncrement"`
+	UsuarioID      uint       `gorm:"column:usuario_id;not null;index"`
+	TokenHash      string     `gorm:"column:token_hash;type:char(64);unique;not null"` // SHA-256 hex del token
+	FamiliaID      string     `gorm:"column:familia_id;type:char(32);not null;index"`
+	AccessJTI      string     `gorm:"column:access_jti;type:char(32);not null;index"`
+	AccessExpiraEn time.Time  `gorm:"column:access_expira_en;not null"`
+	ExpiraEn       time.Time  `gorm:"column:expira_en;not null"`
+	UsadoEn        *time.Time `gorm:"column:usado_en"`
+	RevocadoEn     *time.Time `gorm:"column:revocado_en"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime"`
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (t RefreshToken) ToDomain() domain.RefreshToken {
+	return domain.RefreshToken{
+		ID:              t.ID,
+		UserID:          t.UsuarioID,
+		TokenHash:       t.TokenHash,
+		FamilyID:        t.FamiliaID,
+		AccessJTI:       t.AccessJTI,
+		AccessExpiresAt: t.AccessExpiraEn,
+		ExpiresAt:       t.ExpiraEn,
+		UsedAt:          t.UsadoEn,
+		RevokedAt:       t.RevocadoEn,
+		CreatedAt:       t.CreatedAt,
+	}
+}
+
+// RefreshTokenFromDomain convierte de Domain (negocio) a DAO (MySQL)
+func RefreshTokenFromDomain(t domain.RefreshToken) RefreshToken {
+	return RefreshToken{
+		ID:             t.ID,
+		UsuarioID:      t.UserID,
+		TokenHash:      t.TokenHash,
+		FamiliaID:      t.FamilyID,
+		AccessJTI:      t.AccessJTI,
+		AccessExpiraEn: t.AccessExpiresAt,
+		ExpiraEn:       t.ExpiresAt,
+		UsadoEn:        t.UsedAt,
+		RevocadoEn:     t.RevokedAt,
+		CreatedAt:      t.CreatedAt,
+	}
+}
+
+// TokenRevocado representa un access token revocado (por jti) antes de su expiración
+type TokenRevocado struct {
+	JTI       string    `gorm:"column:jti;type:char(32);primaryKey"`
+	UsuarioID uint      `gorm:"column:usuario_id;not null"`
+	ExpiraEn  time.Time `gorm:"column:expira_en;not null;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (TokenRevocado) TableName() string {
+	return "tokens_revocados"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (t TokenRevocado) ToDomain() domain.RevokedToken {
+	return domain.RevokedToken{
+		JTI:       t.JTI,
+		UserID:    t.UsuarioID,
+		ExpiresAt: t.ExpiraEn,
+	}
+}
+
+// TokenCuenta representa un token de un solo uso enviado por email (reset de contraseña, verificación de email)
+type TokenCuenta struct {
+	ID        uint       `gorm:"column:id_token_cuenta;primaryKey;autoIncrement"`
+	UsuarioID uint       `gorm:"column:usuario_id;not null;index"`
+	Tipo      string     `gorm:"column:tipo;type:varchar(30);not null"`           // password_reset | email_verification | two_factor_login
+	TokenHash string     `gorm:"column:token_hash;type:char(64);unique;not null"` // SHA-256 hex del token
+	ExpiraEn  time.Time  `gorm:"column:expira_en;not null;index"`
+	UsadoEn   *time.Time `gorm:"column:usado_en"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (TokenCuenta) TableName() string {
+	return "tokens_cuenta"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (t TokenCuenta) ToDomain() domain.AccountToken {
+	return domain.AccountToken{
+		ID:        t.ID,
+		UserID:    t.UsuarioID,
+		Purpose:   t.Tipo,
+		TokenHash: t.TokenHash,
+		ExpiresAt: t.ExpiraEn,
+		UsedAt:    t.UsadoEn,
+		CreatedAt: t.CreatedAt,
+	}
+}
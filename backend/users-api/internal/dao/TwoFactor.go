@@ -0,0 +1,47 @@
+package dao
+
+import (
+	"time"
+	"users-api/internal/domain"
+)
+
+// DosFactores representa la configuración TOTP de un usuario (una fila por usuario)
+type DosFactores struct {
+	UsuarioID  uint       `gorm:"column:usuario_id;primaryKey"`
+	Secreto    string     `gorm:"column:secreto;type:varchar(64);not null"` // base32
+	ActivadoEn *time.Time `gorm:"column:activado_en"`                       // Nil = inscripción pendiente
+	UltimoPaso int64      `gorm:"column:ultimo_paso;not null;default:0"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime"`
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (DosFactores) TableName() string {
+	return "dos_factores"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (d DosFactores) ToDomain() domain.TwoFactor {
+	return domain.TwoFactor{
+		UserID:    d.UsuarioID,
+		Secret:    d.Secreto,
+		EnabledAt: d.ActivadoEn,
+		LastStep:  d.UltimoPaso,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
+
+// CodigoRecuperacion representa un código de recuperación de un solo uso (solo el hash SHA-256)
+type CodigoRecuperacion struct {
+	ID         uint       `gorm:"column:id_codigo_recuperacion;primaryKey;autoIncrement"`
+	UsuarioID  uint       `gorm:"column:usuario_id;not null;index"`
+	CodigoHash string     `gorm:"column:codigo_hash;type:char(64);unique;not null"`
+	UsadoEn    *time.Time `gorm:"column:usado_en"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (CodigoRecuperacion) TableName() string {
+	return "codigos_recuperacion"
+}
@@ -8,18 +8,19 @@ import (
 // User representa el modelo de base de datos con tags de GORM
 // Este modelo está acoplado a MySQL/GORM
 type User struct {
-	ID               uint       `gorm:"column:id_usuario;primaryKey;autoIncrement"`
-	Nombre           string     `gorm:"type:varchar(30);not null"`
-	Apellido         string     `gorm:"type:varchar(30);not null"`
-	Username         string     `gorm:"type:varchar(30);unique;not null;index"`
-	Email            string     `gorm:"type:varchar(100);unique;not null;index"`
-	Password         string     `gorm:"type:char(64);collation:ascii_bin;not null"`
-	IsAdmin          bool       `gorm:"column:is_admin;default:false;not null"`
-	SucursalOrigenID *uint      `gorm:"column:sucursal_origen_id;index"` // Nullable, referencia lógica
-	FechaRegistro    time.Time  `gorm:"column:fecha_registro;type:timestamp;default:CURRENT_TIMESTAMP;not null"`
-	CreatedAt        time.Time  `gorm:"autoCreateTime"`
-	UpdatedAt        time.Time  `gorm:"autoUpdateTime"`
-	DeletedAt        *time.Time `gorm:"index"` // Soft delete
+	ID                uint       `gorm:"column:id_usuario;primaryKey;autoIncrement"`
+	Nombre            string     `gorm:"type:varchar(30);not null"`
+	Apellido          string     `gorm:"type:varchar(30);not null"`
+	Username          string     `gorm:"type:varchar(30);unique;not null;index"`
+	Email             string     `gorm:"type:varchar(100);unique;not null;index"`
+	Password          string     `gorm:"type:varchar(255);collation:ascii_bin;not null"` // argon2id (PHC) o SHA-256 legado
+	IsAdmin           bool       `gorm:"column:is_admin;default:false;not null"`
+	SucursalOrigenID  *uint      `gorm:"column:sucursal_origen_id;index"` // Nullable, referencia lógica
+	EmailVerificadoEn *time.Time `gorm:"column:email_verificado_en"`      // Nil = email sin verificar
+	FechaRegistro     time.Time  `gorm:"column:fecha_registro;type:timestamp;default:CURRENT_TIMESTAMP;not null"`
+	CreatedAt         time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime"`
+	DeletedAt         *time.Time `gorm:"index"` // Soft delete: cuenta deshabilitada por un admin
 }
 
 // TableName especifica el nombre de la tabla en MySQL
@@ -38,6 +39,8 @@ func (u User) ToDomain() domain.User {
 		Password:         u.Password,
 		IsAdmin:          u.IsAdmin,
 		SucursalOrigenID: u.SucursalOrigenID,
+		EmailVerifiedAt:  u.EmailVerificadoEn,
+		DisabledAt:       u.DeletedAt,
 		FechaRegistro:    u.FechaRegistro,
 		CreatedAt:        u.CreatedAt,
 		UpdatedAt:        u.UpdatedAt,
@@ -47,16 +50,18 @@ func (u User) ToDomain() domain.User {
 // FromDomain convierte de Domain (negocio) a DAO (MySQL)
 func FromDomain(domainUser domain.User) User {
 	return User{
-		ID:               domainUser.ID,
-		Nombre:           domainUser.Nombre,
-		Apellido:         domainUser.Apellido,
-		Username:         domainUser.Username,
-		Email:            domainUser.Email,
-		Password:         domainUser.Password,
-		IsAdmin:          domainUser.IsAdmin,
-		SucursalOrigenID: domainUser.SucursalOrigenID,
-		FechaRegistro:    domainUser.FechaRegistro,
-		CreatedAt:        domainUser.CreatedAt,
-		UpdatedAt:        domainUser.UpdatedAt,
+		ID:                domainUser.ID,
+		Nombre:            domainUser.Nombre,
+		Apellido:          domainUser.Apellido,
+		Username:          domainUser.Username,
+		Email:             domainUser.Email,
+		Password:          domainUser.Password,
+		IsAdmin:           domainUser.IsAdmin,
+		SucursalOrigenID:  domainUser.SucursalOrigenID,
+		EmailVerificadoEn: domainUser.EmailVerifiedAt,
+		DeletedAt:         domainUser.DisabledAt,
+		FechaRegistro:     domainUser.FechaRegistro,
+		CreatedAt:         domainUser.CreatedAt,
+		UpdatedAt:         domainUser.UpdatedAt,
 	}
 }
@@ -0,0 +1,28 @@
+package dao
+
+import (
+	"time"
+	"users-api/internal/domain"
+)
+
+// RolUsuario representa un rol de staff asignado a un usuario en una sucursal
+type RolUsuario struct {
+	ID         uint      `gorm:"column:id_rol_usuario;primaryKey;autoIncrement"`
+	UsuarioID  uint      `gorm:"column:usuario_id;not null;uniqueIndex:uk_usuario_rol_sucursal"`
+	Rol        string    `gorm:"column:rol;type:varchar(30);not null;uniqueIndex:uk_usuario_rol_sucursal"`
+	SucursalID uint      `gorm:"column:sucursal_id;not null;uniqueIndex:uk_usuario_rol_sucursal"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+// TableName especifica el nombre de la tabla en MySQL
+func (RolUsuario) TableName() string {
+	return "roles_usuario"
+}
+
+// ToDomain convierte de DAO (MySQL) a Domain (negocio)
+func (r RolUsuario) ToDomain() domain.RoleAssignment {
+	return domain.RoleAssignment{
+		Role:       r.Rol,
+		SucursalID: r.SucursalID,
+	}
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// Tipos de clave con las que se cuentan los logins fallidos
+const (
+	LoginAttemptAccount = "usuario" // Clave: ID del usuario
+	LoginAttemptIP      = "ip"      // Clave: IP del cliente
+)
+
+// LoginAttempt representa los logins fallidos recientes de una cuenta o una IP
+type LoginAttempt struct {
+	Kind          string
+	Key           string
+	Failures      int // Fallos consecutivos dentro de la ventana
+	LastFailureAt time.Time
+	BlockedUntil  *time.Time // Hasta cuándo se rechazan los intentos (espera progresiva o bloqueo)
+}
+
+// BlockedAt indica si los intentos están bloqueados en el instante now
+func (a LoginAttempt) BlockedAt(now time.Time) bool {
+	return a.BlockedUntil != nil && a.BlockedUntil.After(now)
+}
@@ -0,0 +1,21 @@
+package domain
+
+// RoleAssignment es un rol de staff (instructor, receptionist, branch_manager) en una sucursal
+// member lo tienen todos los usuarios y owner se guarda en is_admin
+type RoleAssignment struct {
+	Role       string `json:"role" binding:"required"`
+	SucursalID uint   `json:"sucursal_id" binding:"required"`
+}
+
+// SetRolesRequest reemplaza los roles de staff de un usuario (una lista vacía los quita todos)
+type SetRolesRequest struct {
+	Roles []RoleAssignment `json:"roles" binding:"dive"`
+}
+
+// UserRolesResponse representa los roles de un usuario
+// Roles son los roles efectivos (los que van en el token) y Assignments los de staff por sucursal
+type UserRolesResponse struct {
+	UserID      uint             `json:"user_id"`
+	Roles       []string         `json:"roles"`
+	Assignments []RoleAssignment `json:"assignments"`
+}
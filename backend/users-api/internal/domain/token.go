@@ -0,0 +1,72 @@
+package domain
+
+import "time"
+
+// TokenPair representa los tokens que se entregan al loguearse o refrescar la sesión
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // Segundos de vida del access token
+}
+
+// RefreshRequest representa el body de POST /token/refresh
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest representa el body (opcional) de POST /logout
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RefreshToken representa un refresh token persistido
+// Solo se guarda el hash: el valor en claro lo tiene únicamente el cliente
+type RefreshToken struct {
+	ID              uint
+	UserID          uint
+	TokenHash       string
+	FamilyID        string // Cadena de rotación a la que pertenece el token
+	AccessJTI       string // jti del access token emitido junto con este refresh token
+	AccessExpiresAt time.Time
+	ExpiresAt       time.Time
+	UsedAt          *time.Time // Se marca al rotar: volver a usarlo es reutilización
+	RevokedAt       *time.Time
+	CreatedAt       time.Time
+}
+
+// RevokedToken representa un access token revocado antes de su expiración
+type RevokedToken struct {
+	JTI       string    `json:"jti"`
+	UserID    uint      `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Propósitos de los tokens de cuenta (links enviados por email)
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposeTwoFactorLogin    = "two_factor_login" // Challenge del segundo paso del login (no se envía por email)
+)
+
+// AccountToken representa un token de un solo uso enviado por email (reset de contraseña, verificación)
+// Igual que los refresh tokens, solo se persiste el hash
+type AccountToken struct {
+	ID        uint
+	UserID    uint
+	Purpose   string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// ForgotPasswordRequest representa el body de POST /password/forgot
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest representa el body de POST /password/reset
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
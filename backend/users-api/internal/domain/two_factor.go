@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// TwoFactor representa la configuración TOTP (RFC 6238) de un usuario
+// Mientras EnabledAt sea nil el secreto está pendiente de confirmar con un primer código
+type TwoFactor struct {
+	UserID    uint
+	Secret    string     // Secreto en base32 (sin padding), el mismo que carga la app autenticadora
+	EnabledAt *time.Time // Nil = inscripción pendiente
+	LastStep  int64      // Último paso TOTP aceptado: un código no se puede reutilizar
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Enabled indica si el usuario confirmó la inscripción (el login pide el segundo factor)
+func (t TwoFactor) Enabled() bool {
+	return t.EnabledAt != nil
+}
+
+// TwoFactorEnrollment es lo que necesita la app autenticadora para dar de alta la cuenta
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"` // Se muestra como QR
+}
+
+// TwoFactorStatus representa el estado del segundo factor del usuario (GET /users/me/2fa)
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	Pending                bool       `json:"pending"`  // Inscripción iniciada sin confirmar
+	Required               bool       `json:"required"` // Su rol exige segundo factor
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorCodeRequest representa un código TOTP o de recuperación
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// RecoveryCodesResponse son los códigos de recuperación en claro (se muestran una sola vez)
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// LoginChallenge es la respuesta de POST /login cuando la cuenta requiere segundo factor
+// El challenge se canjea en POST /login/2fa junto con el código
+type LoginChallenge struct {
+	TwoFactorRequired  bool   `json:"two_factor_required"`
+	ChallengeToken     string `json:"challenge_token"`
+	ExpiresIn          int64  `json:"expires_in"`          // Segundos de vida del challenge
+	EnrollmentRequired bool   `json:"enrollment_required"` // El rol exige 2FA y todavía no lo configuró
+}
+
+// TwoFactorLoginRequest representa el body de POST /login/2fa
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+	ClientIP       string `json:"-"` // Lo completa el controller (protección contra fuerza bruta)
+}
+
+// ChallengeRequest representa el body de POST /login/2fa/enroll
+type ChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
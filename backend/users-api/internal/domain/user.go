@@ -5,23 +5,26 @@ import "time"
 // User representa la entidad de negocio Usuario
 // Este modelo es independiente de la base de datos
 type User struct {
-	ID                uint      `json:"id"`
-	Nombre            string    `json:"nombre"`
-	Apellido          string    `json:"apellido"`
-	Username          string    `json:"username"`
-	Email             string    `json:"email"`
-	Password          string    `json:"password,omitempty"` // omitempty para no exponerlo en responses
-	IsAdmin           bool      `json:"is_admin"`
-	SucursalOrigenID  *uint     `json:"sucursal_origen_id,omitempty"` // Nullable
-	FechaRegistro     time.Time `json:"fecha_registro"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID               uint       `json:"id"`
+	Nombre           string     `json:"nombre"`
+	Apellido         string     `json:"apellido"`
+	Username         string     `json:"username"`
+	Email            string     `json:"email"`
+	Password         string     `json:"password,omitempty"` // omitempty para no exponerlo en responses
+	IsAdmin          bool       `json:"is_admin"`
+	SucursalOrigenID *uint      `json:"sucursal_origen_id,omitempty"` // Nullable
+	EmailVerifiedAt  *time.Time `json:"email_verified_at,omitempty"`  // Nil mientras no confirme su email
+	DisabledAt       *time.Time `json:"disabled_at,omitempty"`        // Nil = cuenta activa
+	FechaRegistro    time.Time  `json:"fecha_registro"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
 // UserLogin representa las credenciales de login
 type UserLogin struct {
 	UsernameOrEmail string `json:"username_or_email" binding:"required"`
 	Password        string `json:"password" binding:"required"`
+	ClientIP        string `json:"-"` // Lo completa el controller (protección contra fuerza bruta)
 }
 
 // UserRegister representa los datos para registrar un nuevo usuario
@@ -34,6 +37,55 @@ type UserRegister struct {
 	SucursalOrigenID *uint  `json:"sucursal_origen_id,omitempty"`
 }
 
+// UpdateProfileRequest representa los cambios del propio perfil (PATCH /users/me)
+// Los campos omitidos no se modifican
+type UpdateProfileRequest struct {
+	Nombre           *string `json:"nombre,omitempty"`
+	Apellido         *string `json:"apellido,omitempty"`
+	Email            *string `json:"email,omitempty"`
+	SucursalOrigenID *uint   `json:"sucursal_origen_id,omitempty"`
+}
+
+// ChangePasswordRequest representa el cambio de contraseña del usuario logueado
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// UserChanges son los campos a modificar de un usuario (nil = sin cambios)
+type UserChanges struct {
+	Nombre           *string
+	Apellido         *string
+	Email            *string
+	SucursalOrigenID *uint
+	IsAdmin          *bool
+	UnverifyEmail    bool // El email cambió: hay que volver a verificarlo
+}
+
+// UserListQuery representa los filtros, el orden y la paginación de GET /users
+// Los filtros nil o vacíos no se aplican
+type UserListQuery struct {
+	Page             int        `form:"page"`
+	Limit            int        `form:"limit"`
+	Search           string     `form:"q"` // Substring de nombre, apellido, username o email
+	IsAdmin          *bool      `form:"is_admin"`
+	SucursalOrigenID *uint      `form:"sucursal_origen_id"`
+	RegisteredFrom   *time.Time `form:"registered_from" time_format:"2006-01-02" time_location:"Local"`
+	RegisteredTo     *time.Time `form:"registered_to" time_format:"2006-01-02" time_location:"Local"` // Inclusive (día completo)
+	Disabled         *bool      `form:"disabled"`
+	SortBy           string     `form:"sort_by"`    // id | nombre | apellido | username | email | fecha_registro
+	SortOrder        string     `form:"sort_order"` // asc | desc
+}
+
+// UserListResponse representa una página de usuarios
+type UserListResponse struct {
+	Data       []UserResponse `json:"data"`
+	Page       int            `json:"page"`
+	Limit      int            `json:"limit"`
+	Total      int64          `json:"total"`
+	TotalPages int            `json:"total_pages"`
+}
+
 // UserResponse representa la respuesta pública del usuario (sin password)
 type UserResponse struct {
 	ID               uint      `json:"id"`
@@ -43,9 +95,21 @@ type UserResponse struct {
 	Email            string    `json:"email"`
 	IsAdmin          bool      `json:"is_admin"`
 	SucursalOrigenID *uint     `json:"sucursal_origen_id,omitempty"`
+	EmailVerified    bool      `json:"email_verified"`
+	Disabled         bool      `json:"disabled"`
 	FechaRegistro    time.Time `json:"fecha_registro"`
 }
 
+// EmailVerified indica si el usuario confirmó su email
+func (u User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// Disabled indica si un admin deshabilitó la cuenta
+func (u User) Disabled() bool {
+	return u.DisabledAt != nil
+}
+
 // ToResponse convierte User a UserResponse (sin password)
 func (u User) ToResponse() UserResponse {
 	return UserResponse{
@@ -56,6 +120,8 @@ func (u User) ToResponse() UserResponse {
 		Email:            u.Email,
 		IsAdmin:          u.IsAdmin,
 		SucursalOrigenID: u.SucursalOrigenID,
+		EmailVerified:    u.EmailVerified(),
+		Disabled:         u.Disabled(),
 		FechaRegistro:    u.FechaRegistro,
 	}
 }
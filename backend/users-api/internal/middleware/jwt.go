@@ -6,14 +6,15 @@ import (
 	"users-api/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yourusername/gym-management/shared/auth"
 )
 
 // JWTAuthMiddleware valida el token JWT en el header Authorization
 func JWTAuthMiddleware(userService services.UsersService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// Obtener header Authorization
-		auth := ctx.GetHeader("Authorization")
-		if auth == "" {
+		authHeader := ctx.GetHeader("Authorization")
+		if authHeader == "" {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"error": "Authorization header required",
 			})
@@ -21,7 +22,7 @@ func JWTAuthMiddleware(userService services.UsersService) gin.HandlerFunc {
 		}
 
 		// Validar formato "Bearer <token>"
-		parts := strings.SplitN(auth, " ", 2)
+		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || parts[0] != "Bearer" {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"error": "Invalid authorization header format. Expected 'Bearer <token>'",
@@ -39,31 +40,70 @@ func JWTAuthMiddleware(userService services.UsersService) gin.HandlerFunc {
 			return
 		}
 
-		// Extraer claims y guardar en contexto
-		idUser, _ := tokenClaims["id_usuario"].(float64)
-		isAdmin, _ := tokenClaims["is_admin"].(bool)
-		username, _ := tokenClaims["username"].(string)
+		// Verificar que el token no haya sido revocado (logout / reutilización de refresh token)
+		revoked, err := userService.IsTokenRevoked(ctx.Request.Context(), tokenClaims.ID)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "Could not verify token revocation",
+				"details": err.Error(),
+			})
+			return
+		}
+		if revoked {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Token has been revoked",
+			})
+			return
+		}
 
-		ctx.Set("id_usuario", uint(idUser))
-		ctx.Set("is_admin", isAdmin)
-		ctx.Set("username", username)
+		// Guardar claims en contexto (el Verifier ya validó que sub es un ID válido)
+		idUser, _ := tokenClaims.UserID()
+
+		ctx.Set("id_usuario", idUser)
+		ctx.Set("is_admin", tokenClaims.IsAdmin())
+		ctx.Set("roles", tokenClaims.Roles)
+		ctx.Set("branch_ids", tokenClaims.BranchIDs)
+		ctx.Set("jti", tokenClaims.ID)
+		ctx.Set("claims", tokenClaims)
 
 		ctx.Next()
 	}
 }
 
-// AdminOnlyMiddleware verifica que el usuario sea administrador
+// RequirePermission verifica que el usuario tenga alguno de los permisos (en al menos una sucursal)
+// El alcance por sucursal lo valida cada handler con ClaimsFromContext y CanInBranch
 // Debe usarse DESPUÉS de JWTAuthMiddleware
-func AdminOnlyMiddleware() gin.HandlerFunc {
+func RequirePermission(perms ...auth.Permission) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		isAdmin, exists := ctx.Get("is_admin")
-		if !exists || !isAdmin.(bool) {
-			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-				"error": "You don't have permission to perform this action. Admin access required.",
-			})
-			return
+		claims, ok := ClaimsFromContext(ctx)
+		if ok {
+			for _, perm := range perms {
+				if claims.Can(perm) {
+					ctx.Next()
+					return
+				}
+			}
 		}
 
-		ctx.Next()
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error": "You don't have permission to perform this action.",
+		})
+	}
+}
+
+// AdminOnlyMiddleware verifica que el usuario sea administrador (owner)
+// Se mantiene por compatibilidad: equivale a RequirePermission(auth.PermAdminAccess)
+// Debe usarse DESPUÉS de JWTAuthMiddleware
+func AdminOnlyMiddleware() gin.HandlerFunc {
+	return RequirePermission(auth.PermAdminAccess)
+}
+
+// ClaimsFromContext devuelve los claims que guardó JWTAuthMiddleware
+func ClaimsFromContext(ctx *gin.Context) (*auth.Claims, bool) {
+	value, exists := ctx.Get("claims")
+	if !exists {
+		return nil, false
 	}
+	claims, ok := value.(*auth.Claims)
+	return claims, ok
 }
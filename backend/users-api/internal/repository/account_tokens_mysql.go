@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"users-api/internal/dao"
+	"users-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// ErrAccountTokenInvalid indica un token de cuenta inexistente, ya usado o expirado
+// No se distingue el motivo para no dar pistas a quien prueba tokens
+var ErrAccountTokenInvalid = errors.New("account token invalid or expired")
+
+// AccountTokensRepository define la interfaz del repositorio de tokens de cuenta
+// (reset de contraseña, verificación de email y challenge del login con segundo factor)
+type AccountTokensRepository interface {
+	CreateAccountToken(ctx context.Context, token domain.AccountToken) error
+	GetAccountToken(ctx context.Context, tokenHash, purpose string) (domain.AccountToken, error)
+	ConsumeAccountToken(ctx context.Context, tokenHash, purpose string) (domain.AccountToken, error)
+	DeleteExpired(ctx context.Context) error
+}
+
+// MySQLAccountTokensRepository implementa AccountTokensRepository usando MySQL/GORM
+type MySQLAccountTokensRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLAccountTokensRepository crea una nueva instancia del repository
+// Comparte la conexión DB con UsersRepository
+func NewMySQLAccountTokensRepository(db *gorm.DB) *MySQLAccountTokensRepository {
+	return &MySQLAccountTokensRepository{
+		db: db,
+	}
+}
+
+// CreateAccountToken persiste un nuevo token (solo su hash)
+// Invalida los tokens pendientes del mismo usuario y propósito: solo vale el último link enviado
+func (r *MySQLAccountTokensRepository) CreateAccountToken(ctx context.Context, token domain.AccountToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&dao.TokenCuenta{}).
+			Where("usuario_id = ? AND tipo = ? AND usado_en IS NULL", token.UserID, token.Purpose).
+			Update("usado_en", time.Now()).Error; err != nil {
+			return fmt.Errorf("error invalidating previous account tokens: %w", err)
+		}
+
+		tokenDAO := dao.TokenCuenta{
+			UsuarioID: token.UserID,
+			Tipo:      token.Purpose,
+			TokenHash: token.TokenHash,
+			ExpiraEn:  token.ExpiresAt,
+		}
+		if err := tx.Create(&tokenDAO).Error; err != nil {
+			return fmt.Errorf("error creating account token: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetAccountToken devuelve un token vigente sin consumirlo
+// Lo usa el login con segundo factor: un código mal tipeado no debe quemar el challenge
+func (r *MySQLAccountTokensRepository) GetAccountToken(ctx context.Context, tokenHash, purpose string) (domain.AccountToken, error) {
+	var tokenDAO dao.TokenCuenta
+
+	err := r.db.WithContext(ctx).
+		Where("token_hash = ? AND tipo = ? AND usado_en IS NULL AND expira_en > ?", tokenHash, purpose, time.Now()).
+		First(&tokenDAO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.AccountToken{}, ErrAccountTokenInvalid
+		}
+		return domain.AccountToken{}, fmt.Errorf("error getting account token: %w", err)
+	}
+
+	return tokenDAO.ToDomain(), nil
+}
+
+// ConsumeAccountToken marca el token como usado y lo devuelve
+// El UPDATE condicional garantiza un solo uso aunque lleguen dos requests en paralelo
+func (r *MySQLAccountTokensRepository) ConsumeAccountToken(ctx context.Context, tokenHash, purpose string) (domain.AccountToken, error) {
+	var tokenDAO dao.TokenCuenta
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&dao.TokenCuenta{}).
+			Where("token_hash = ? AND tipo = ? AND usado_en IS NULL AND expira_en > ?", tokenHash, purpose, now).
+			Update("usado_en", now)
+		if result.Error != nil {
+			return fmt.Errorf("error consuming account token: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrAccountTokenInvalid
+		}
+
+		if err := tx.Where("token_hash = ?", tokenHash).First(&tokenDAO).Error; err != nil {
+			return fmt.Errorf("error getting account token: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.AccountToken{}, err
+	}
+
+	return tokenDAO.ToDomain(), nil
+}
+
+// DeleteExpired elimina los tokens de cuenta expirados
+func (r *MySQLAccountTokensRepository) DeleteExpired(ctx context.Context) error {
+	if err := r.db.WithContext(ctx).Where("expira_en <= ?", time.Now()).Delete(&dao.TokenCuenta{}).Error; err != nil {
+		return fmt.Errorf("error deleting expired account tokens: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"users-api/internal/dao"
+	"users-api/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginAttemptsRepository define la interfaz del repositorio de logins fallidos (por cuenta y por IP)
+type LoginAttemptsRepository interface {
+	GetLoginAttempt(ctx context.Context, kind, key string) (domain.LoginAttempt, error)
+	RecordLoginFailure(ctx context.Context, kind, key string, window time.Duration) (int, error)
+	BlockLoginAttempts(ctx context.Context, kind, key string, until time.Time) error
+	ResetLoginAttempts(ctx context.Context, kind, key string) error
+	DeleteExpired(ctx context.Context, olderThan time.Time) error
+}
+
+// MySQLLoginAttemptsRepository implementa LoginAttemptsRepository usando MySQL/GORM
+type MySQLLoginAttemptsRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLLoginAttemptsRepository crea una nueva instancia del repository
+// Comparte la conexión DB con UsersRepository
+func NewMySQLLoginAttemptsRepository(db *gorm.DB) *MySQLLoginAttemptsRepository {
+	return &MySQLLoginAttemptsRepository{
+		db: db,
+	}
+}
+
+// GetLoginAttempt devuelve los fallos registrados (sin fallos devuelve un intento vacío)
+func (r *MySQLLoginAttemptsRepository) GetLoginAttempt(ctx context.Context, kind, key string) (domain.LoginAttempt, error) {
+	var attemptDAO dao.IntentoLogin
+
+	err := r.db.WithContext(ctx).Where("tipo = ? AND clave = ?", kind, key).First(&attemptDAO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.LoginAttempt{Kind: kind, Key: key}, nil
+		}
+		return domain.LoginAttempt{}, fmt.Errorf("error getting login attempts: %w", err)
+	}
+
+	return attemptDAO.ToDomain(), nil
+}
+
+// RecordLoginFailure suma un fallo y devuelve el total de fallos consecutivos
+// El contador vuelve a 1 si el último fallo es más viejo que window
+// El upsert es atómico: réplicas en paralelo no pierden fallos
+func (r *MySQLLoginAttemptsRepository) RecordLoginFailure(ctx context.Context, kind, key string, window time.Duration) (int, error) {
+	var failures int
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		attempt := dao.IntentoLogin{Tipo: kind, Clave: key, Fallos: 1, UltimoFallo: now}
+		// fallos se asigna antes que ultimo_fallo: el IF compara contra el fallo anterior
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "tipo"}, {Name: "clave"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"fallos":       gorm.Expr("IF(ultimo_fallo < ?, 1, fallos + 1)", now.Add(-window)),
+				"ultimo_fallo": now,
+			}),
+		}).Create(&attempt).Error; err != nil {
+			return fmt.Errorf("error recording login failure: %w", err)
+		}
+
+		var stored dao.IntentoLogin
+		if err := tx.Where("tipo = ? AND clave = ?", kind, key).First(&stored).Error; err != nil {
+			return fmt.Errorf("error getting login attempts: %w", err)
+		}
+		failures = stored.Fallos
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return failures, nil
+}
+
+// BlockLoginAttempts rechaza los intentos hasta until
+// Nunca acorta un bloqueo vigente (otra réplica pudo haber bloqueado por más tiempo)
+func (r *MySQLLoginAttemptsRepository) BlockLoginAttempts(ctx context.Context, kind, key string, until time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&dao.IntentoLogin{}).
+		Where("tipo = ? AND clave = ?", kind, key).
+		Update("bloqueado_hasta", gorm.Expr("GREATEST(COALESCE(bloqueado_hasta, ?), ?)", until, until)).Error
+	if err != nil {
+		return fmt.Errorf("error blocking login attempts: %w", err)
+	}
+
+	return nil
+}
+
+// ResetLoginAttempts borra los fallos (login exitoso o desbloqueo de un admin)
+func (r *MySQLLoginAttemptsRepository) ResetLoginAttempts(ctx context.Context, kind, key string) error {
+	if err := r.db.WithContext(ctx).Where("tipo = ? AND clave = ?", kind, key).Delete(&dao.IntentoLogin{}).Error; err != nil {
+		return fmt.Errorf("error resetting login attempts: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired elimina los registros sin fallos recientes ni bloqueo vigente
+func (r *MySQLLoginAttemptsRepository) DeleteExpired(ctx context.Context, olderThan time.Time) error {
+	err := r.db.WithContext(ctx).
+		Where("ultimo_fallo < ? AND (bloqueado_hasta IS NULL OR bloqueado_hasta < ?)", olderThan, time.Now()).
+		Delete(&dao.IntentoLogin{}).Error
+	if err != nil {
+		return fmt.Errorf("error deleting expired login attempts: %w", err)
+	}
+
+	return nil
+}
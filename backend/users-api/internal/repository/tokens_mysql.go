@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"users-api/internal/dao"
+	"users-api/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TokensRepository define la interfaz del repositorio de refresh tokens y tokens revocados
+type TokensRepository interface {
+	CreateRefreshToken(ctx context.Context, token domain.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (domain.RefreshToken, error)
+	GetRefreshTokenByAccessJTI(ctx context.Context, jti string) (domain.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, id uint) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeUserSessions(ctx context.Context, userID uint) error
+	RevokeAccessToken(ctx context.Context, token domain.RevokedToken) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	ListRevokedTokens(ctx context.Context) ([]domain.RevokedToken, error)
+	DeleteExpired(ctx context.Context) error
+}
+
+// MySQLTokensRepository implementa TokensRepository usando MySQL/GORM
+type MySQLTokensRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLTokensRepository crea una nueva instancia del repository
+// Comparte la conexión DB con UsersRepository
+func NewMySQLTokensRepository(db *gorm.DB) *MySQLTokensRepository {
+	return &MySQLTokensRepository{
+		db: db,
+	}
+}
+
+// CreateRefreshToken persiste un nuevo refresh token (solo su hash)
+func (r *MySQLTokensRepository) CreateRefreshToken(ctx context.Context, token domain.RefreshToken) error {
+	tokenDAO := dao.RefreshTokenFromDomain(token)
+
+	if err := r.db.WithContext(ctx).Create(&tokenDAO).Error; err != nil {
+		return fmt.Errorf("error creating refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// GetRefreshTokenByHash busca un refresh token por el hash de su valor
+func (r *MySQLTokensRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (domain.RefreshToken, error) {
+	var tokenDAO dao.RefreshToken
+
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&tokenDAO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.RefreshToken{}, errors.New("refresh token not found")
+		}
+		return domain.RefreshToken{}, fmt.Errorf("error getting refresh token: %w", err)
+	}
+
+	return tokenDAO.ToDomain(), nil
+}
+
+// GetRefreshTokenByAccessJTI busca el refresh token emitido junto con un access token
+func (r *MySQLTokensRepository) GetRefreshTokenByAccessJTI(ctx context.Context, jti string) (domain.RefreshToken, error) {
+	var tokenDAO dao.RefreshToken
+
+	err := r.db.WithContext(ctx).Where("access_jti = ?", jti).First(&tokenDAO).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.RefreshToken{}, errors.New("refresh token not found")
+		}
+		return domain.RefreshToken{}, fmt.Errorf("error getting refresh token: %w", err)
+	}
+
+	return tokenDAO.ToDomain(), nil
+}
+
+// MarkRefreshTokenUsed marca un refresh token como usado (rotado)
+// Devuelve false si ya estaba usado o revocado: otra request lo consumió primero
+func (r *MySQLTokensRepository) MarkRefreshTokenUsed(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&dao.RefreshToken{}).
+		Where("id_refresh_token = ? AND usado_en IS NULL AND revocado_en IS NULL", id).
+		Update("usado_en", time.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("error marking refresh token as used: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// RevokeFamily revoca todos los refresh tokens de una familia y
+// agrega a la lista de revocación los access tokens de esa familia que siguen vigentes
+func (r *MySQLTokensRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var family []dao.RefreshToken
+		if err := tx.Where("familia_id = ?", familyID).Find(&family).Error; err != nil {
+			return fmt.Errorf("error getting token family: %w", err)
+		}
+
+		if err := tx.Model(&dao.RefreshToken{}).
+			Where("familia_id = ? AND revocado_en IS NULL", familyID).
+			Update("revocado_en", now).Error; err != nil {
+			return fmt.Errorf("error revoking token family: %w", err)
+		}
+
+		revoked := make([]dao.TokenRevocado, 0, len(family))
+		for _, t := range family {
+			if t.AccessExpiraEn.After(now) {
+				revoked = append(revoked, dao.TokenRevocado{
+					JTI:       t.AccessJTI,
+					UsuarioID: t.UsuarioID,
+					ExpiraEn:  t.AccessExpiraEn,
+				})
+			}
+		}
+		if len(revoked) == 0 {
+			return nil
+		}
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&revoked).Error; err != nil {
+			return fmt.Errorf("error revoking access tokens: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// RevokeUserSessions revoca todas las familias de refresh tokens del usuario y
+// agrega a la lista de revocación sus access tokens vigentes (ej: después de un reset de contraseña)
+func (r *MySQLTokensRepository) RevokeUserSessions(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var active []dao.RefreshToken
+		if err := tx.Where("usuario_id = ? AND access_expira_en > ?", userID, now).Find(&active).Error; err != nil {
+			return fmt.Errorf("error getting user tokens: %w", err)
+		}
+
+		if err := tx.Model(&dao.RefreshToken{}).
+			Where("usuario_id = ? AND revocado_en IS NULL", userID).
+			Update("revocado_en", now).Error; err != nil {
+			return fmt.Errorf("error revoking user sessions: %w", err)
+		}
+
+		if len(active) == 0 {
+			return nil
+		}
+
+		revoked := make([]dao.TokenRevocado, len(active))
+		for i, t := range active {
+			revoked[i] = dao.TokenRevocado{
+				JTI:       t.AccessJTI,
+				UsuarioID: t.UsuarioID,
+				ExpiraEn:  t.AccessExpiraEn,
+			}
+		}
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&revoked).Error; err != nil {
+			return fmt.Errorf("error revoking access tokens: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// RevokeAccessToken agrega un access token (por jti) a la lista de revocación
+func (r *MySQLTokensRepository) RevokeAccessToken(ctx context.Context, token domain.RevokedToken) error {
+	revoked := dao.TokenRevocado{
+		JTI:       token.JTI,
+		UsuarioID: token.UserID,
+		ExpiraEn:  token.ExpiresAt,
+	}
+
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&revoked).Error; err != nil {
+		return fmt.Errorf("error revoking access token: %w", err)
+	}
+
+	return nil
+}
+
+// IsAccessTokenRevoked indica si un jti está en la lista de revocación
+func (r *MySQLTokensRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&dao.TokenRevocado{}).
+		Where("jti = ? AND expira_en > ?", jti, time.Now()).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("error checking revoked token: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// ListRevokedTokens devuelve los access tokens revocados que todavía no expiraron
+func (r *MySQLTokensRepository) ListRevokedTokens(ctx context.Context) ([]domain.RevokedToken, error) {
+	var revokedDAO []dao.TokenRevocado
+
+	if err := r.db.WithContext(ctx).Where("expira_en > ?", time.Now()).Find(&revokedDAO).Error; err != nil {
+		return nil, fmt.Errorf("error listing revoked tokens: %w", err)
+	}
+
+	revoked := make([]domain.RevokedToken, len(revokedDAO))
+	for i, t := range revokedDAO {
+		revoked[i] = t.ToDomain()
+	}
+
+	return revoked, nil
+}
+
+// DeleteExpired elimina refresh tokens y revocaciones que ya expiraron
+func (r *MySQLTokensRepository) DeleteExpired(ctx context.Context) error {
+	now := time.Now()
+
+	if err := r.db.WithContext(ctx).Where("expira_en <= ?", now).Delete(&dao.RefreshToken{}).Error; err != nil {
+		return fmt.Errorf("error deleting expired refresh tokens: %w", err)
+	}
+
+	if err := r.db.WithContext(ctx).Where("expira_en <= ?", now).Delete(&dao.TokenRevocado{}).Error; err != nil {
+		return fmt.Errorf("error deleting expired revoked tokens: %w", err)
+	}
+
+	return nil
+}
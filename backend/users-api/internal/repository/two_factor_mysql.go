@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"users-api/internal/dao"
+	"users-api/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrTwoFactorNotFound indica que el usuario no inició la inscripción TOTP
+var ErrTwoFactorNotFound = errors.New("two factor not configured")
+
+// TwoFactorRepository define la interfaz del repositorio del segundo factor (TOTP y códigos de recuperación)
+type TwoFactorRepository interface {
+	GetTwoFactor(ctx context.Context, userID uint) (domain.TwoFactor, error)
+	SavePendingSecret(ctx context.Context, userID uint, secret string) error
+	EnableTwoFactor(ctx context.Context, userID uint, step int64, recoveryCodeHashes []string) error
+	UseStep(ctx context.Context, userID uint, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, recoveryCodeHashes []string) error
+	ConsumeRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userID uint) (int, error)
+	DeleteTwoFactor(ctx context.Context, userID uint) error
+}
+
+// MySQLTwoFactorRepository implementa TwoFactorRepository usando MySQL/GORM
+type MySQLTwoFactorRepository struct {
+	db *gorm.DB
+}
+
+// NewMySQLTwoFactorRepository crea una nueva instancia del repository
+// Comparte la conexión DB con UsersRepository
+func NewMySQLTwoFactorRepository(db *gorm.DB) *MySQLTwoFactorRepository {
+	return &MySQLTwoFactorRepository{
+		db: db,
+	}
+}
+
+// GetTwoFactor devuelve la configuración TOTP del usuario
+func (r *MySQLTwoFactorRepository) GetTwoFactor(ctx context.Context, userID uint) (domain.TwoFactor, error) {
+	var twoFactorDAO dao.DosFactores
+
+	if err := r.db.WithContext(ctx).Where("usuario_id = ?", userID).First(&twoFactorDAO).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.TwoFactor{}, ErrTwoFactorNotFound
+		}
+		return domain.TwoFactor{}, fmt.Errorf("error getting two factor: %w", err)
+	}
+
+	return twoFactorDAO.ToDomain(), nil
+}
+
+// SavePendingSecret guarda un secreto nuevo sin activar (reemplaza una inscripción pendiente anterior)
+// El servicio solo lo llama si el segundo factor no está activo
+func (r *MySQLTwoFactorRepository) SavePendingSecret(ctx context.Context, userID uint, secret string) error {
+	twoFactorDAO := dao.DosFactores{UsuarioID: userID, Secreto: secret}
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "usuario_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"secreto":     secret,
+			"activado_en": nil,
+			"ultimo_paso": 0,
+		}),
+	}).Create(&twoFactorDAO).Error
+	if err != nil {
+		return fmt.Errorf("error saving two factor secret: %w", err)
+	}
+
+	return nil
+}
+
+// EnableTwoFactor activa el segundo factor con el primer código aceptado y guarda los códigos de recuperación
+func (r *MySQLTwoFactorRepository) EnableTwoFactor(ctx context.Context, userID uint, step int64, recoveryCodeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&dao.DosFactores{}).
+			Where("usuario_id = ? AND activado_en IS NULL", userID).
+			Updates(map[string]interface{}{"activado_en": time.Now(), "ultimo_paso": step})
+		if result.Error != nil {
+			return fmt.Errorf("error enabling two factor: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrTwoFactorNotFound
+		}
+
+		return replaceRecoveryCodes(tx, userID, recoveryCodeHashes)
+	})
+}
+
+// UseStep registra el paso TOTP aceptado
+// El UPDATE condicional impide reutilizar un código (o uno anterior), aunque lleguen dos requests en paralelo
+func (r *MySQLTwoFactorRepository) UseStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&dao.DosFactores{}).
+		Where("usuario_id = ? AND ultimo_paso < ?", userID, step).
+		Update("ultimo_paso", step)
+	if result.Error != nil {
+		return false, fmt.Errorf("error using two factor step: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// ReplaceRecoveryCodes reemplaza los códigos de recuperación del usuario
+func (r *MySQLTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, recoveryCodeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, recoveryCodeHashes)
+	})
+}
+
+// ConsumeRecoveryCode marca un código de recuperación como usado
+// Devuelve false si no existe o ya fue usado
+func (r *MySQLTwoFactorRepository) ConsumeRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&dao.CodigoRecuperacion{}).
+		Where("usuario_id = ? AND codigo_hash = ? AND usado_en IS NULL", userID, codeHash).
+		Update("usado_en", time.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("error consuming recovery code: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// CountRecoveryCodes cuenta los códigos de recuperación sin usar
+func (r *MySQLTwoFactorRepository) CountRecoveryCodes(ctx context.Context, userID uint) (int, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&dao.CodigoRecuperacion{}).
+		Where("usuario_id = ? AND usado_en IS NULL", userID).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("error counting recovery codes: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// DeleteTwoFactor elimina el secreto y los códigos de recuperación del usuario
+func (r *MySQLTwoFactorRepository) DeleteTwoFactor(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("usuario_id = ?", userID).Delete(&dao.CodigoRecuperacion{}).Error; err != nil {
+			return fmt.Errorf("error deleting recovery codes: %w", err)
+		}
+		if err := tx.Where("usuario_id = ?", userID).Delete(&dao.DosFactores{}).Error; err != nil {
+			return fmt.Errorf("error deleting two factor: %w", err)
+		}
+		return nil
+	})
+}
+
+// replaceRecoveryCodes borra los códigos del usuario e inserta los nuevos dentro de la transacción tx
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, recoveryCodeHashes []string) error {
+	if err := tx.Where("usuario_id = ?", userID).Delete(&dao.CodigoRecuperacion{}).Error; err != nil {
+		return fmt.Errorf("error deleting recovery codes: %w", err)
+	}
+
+	if len(recoveryCodeHashes) == 0 {
+		return nil
+	}
+
+	codes := make([]dao.CodigoRecuperacion, len(recoveryCodeHashes))
+	for i, hash := range recoveryCodeHashes {
+		codes[i] = dao.CodigoRecuperacion{UsuarioID: userID, CodigoHash: hash}
+	}
+	if err := tx.Create(&codes).Error; err != nil {
+		return fmt.Errorf("error creating recovery codes: %w", err)
+	}
+
+	return nil
+}
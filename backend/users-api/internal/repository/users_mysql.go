@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 	"users-api/internal/config"
 	"users-api/internal/dao"
@@ -15,6 +16,9 @@ import (
 	"gorm.io/gorm/logger"
 )
 
+// ErrEmailAlreadyExists indica que el email ya pertenece a otro usuario
+var ErrEmailAlreadyExists = errors.New("email_already_exists")
+
 // UsersRepository define la interfaz del repositorio de usuarios
 // Esta interfaz permite cambiar la implementación (MySQL, Postgres, etc.) sin afectar el servicio
 type UsersRepository interface {
@@ -23,8 +27,14 @@ type UsersRepository interface {
 	GetByUsername(ctx context.Context, username string) (domain.User, error)
 	GetByEmail(ctx context.Context, email string) (domain.User, error)
 	GetByUsernameOrEmail(ctx context.Context, usernameOrEmail string) (domain.User, error)
-	List(ctx context.Context) ([]domain.User, error)
+	List(ctx context.Context, query domain.UserListQuery) ([]domain.User, int64, error)
 	Update(ctx context.Context, id uint, user domain.User) (domain.User, error)
+	UpdatePassword(ctx context.Context, id uint, passwordHash string) error
+	MarkEmailVerified(ctx context.Context, id uint) error
+	ApplyChanges(ctx context.Context, id uint, changes domain.UserChanges) (domain.User, error)
+	SetDisabled(ctx context.Context, id uint, disabled bool) error
+	GetRoles(ctx context.Context, id uint) ([]domain.RoleAssignment, error)
+	SetRoles(ctx context.Context, id uint, roles []domain.RoleAssignment) error
 	Delete(ctx context.Context, id uint) error
 	GetDB() *gorm.DB // For health checks
 }
@@ -94,7 +104,7 @@ func (r *MySQLUsersRepository) Create(ctx context.Context, user domain.User) (do
 				return domain.User{}, errors.New("username_already_exists")
 			}
 			if contains(errMsg, "email") || contains(errMsg, "usuarios.email") {
-				return domain.User{}, errors.New("email_already_exists")
+				return domain.User{}, ErrEmailAlreadyExists
 			}
 			// Si no se puede determinar el campo específico
 			return domain.User{}, errors.New("username_or_email_already_exists")
@@ -169,12 +179,78 @@ func (r *MySQLUsersRepository) GetByUsernameOrEmail(ctx context.Context, usernam
 	return userDAO.ToDomain(), nil
 }
 
-// List obtiene todos los usuarios
-func (r *MySQLUsersRepository) List(ctx context.Context) ([]domain.User, error) {
-	var usersDAO []dao.User
+// userSortColumns son las columnas por las que se puede ordenar GET /users
+var userSortColumns = map[string]string{
+	"id":             "id_usuario",
+	"nombre":         "nombre",
+	"apellido":       "apellido",
+	"username":       "username",
+	"email":          "email",
+	"fecha_registro": "fecha_registro",
+}
+
+// IsUserSortColumn indica si se puede ordenar por el campo indicado
+func IsUserSortColumn(sortBy string) bool {
+	_, ok := userSortColumns[sortBy]
+	return ok
+}
+
+// List obtiene una página de usuarios filtrada y ordenada en la base de datos
+// Devuelve también el total de usuarios que cumplen los filtros (COUNT)
+// query debe venir normalizada (Page >= 1, Limit > 0, SortBy válido)
+func (r *MySQLUsersRepository) List(ctx context.Context, query domain.UserListQuery) ([]domain.User, int64, error) {
+	db := r.db.WithContext(ctx).Model(&dao.User{})
+
+	if query.Search != "" {
+		like := "%" + escapeLike(query.Search) + "%"
+		db = db.Where("(nombre LIKE ? OR apellido LIKE ? OR username LIKE ? OR email LIKE ?)", like, like, like, like)
+	}
+	if query.IsAdmin != nil {
+		db = db.Where("is_admin = ?", *query.IsAdmin)
+	}
+	if query.SucursalOrigenID != nil {
+		db = db.Where("sucursal_origen_id = ?", *query.SucursalOrigenID)
+	}
+	if query.RegisteredFrom != nil {
+		db = db.Where("fecha_registro >= ?", *query.RegisteredFrom)
+	}
+	if query.RegisteredTo != nil {
+		db = db.Where("fecha_registro < ?", query.RegisteredTo.AddDate(0, 0, 1))
+	}
+	if query.Disabled != nil {
+		if *query.Disabled {
+			db = db.Where("deleted_at IS NOT NULL")
+		} else {
+			db = db.Where("deleted_at IS NULL")
+		}
+	}
 
-	if err := r.db.WithContext(ctx).Find(&usersDAO).Error; err != nil {
-		return nil, fmt.Errorf("error listing users: %w", err)
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("error counting users: %w", err)
+	}
+
+	column, ok := userSortColumns[query.SortBy]
+	if !ok {
+		column = "id_usuario"
+	}
+	direction := "ASC"
+	if query.SortOrder == "desc" {
+		direction = "DESC"
+	}
+
+	db = db.Order(column + " " + direction)
+	if column != "id_usuario" {
+		db = db.Order("id_usuario " + direction) // Desempate estable entre páginas
+	}
+
+	var usersDAO []dao.User
+	err := db.
+		Limit(query.Limit).
+		Offset((query.Page - 1) * query.Limit).
+		Find(&usersDAO).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing users: %w", err)
 	}
 
 	// Convertir de DAO a Domain
@@ -183,7 +259,12 @@ func (r *MySQLUsersRepository) List(ctx context.Context) ([]domain.User, error)
 		users[i] = userDAO.ToDomain()
 	}
 
-	return users, nil
+	return users, total, nil
+}
+
+// escapeLike escapa los comodines de LIKE para buscar el texto literal
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
 // Update actualiza un usuario existente
@@ -205,7 +286,152 @@ func (r *MySQLUsersRepository) Update(ctx context.Context, id uint, user domain.
 	return r.GetByID(ctx, id)
 }
 
-// Delete elimina un usuario (soft delete)
+// UpdatePassword reemplaza solo el hash de la contraseña de un usuario
+func (r *MySQLUsersRepository) UpdatePassword(ctx context.Context, id uint, passwordHash string) error {
+	result := r.db.WithContext(ctx).
+		Model(&dao.User{}).
+		Where("id_usuario = ?", id).
+		Updates(map[string]interface{}{
+			"password":   passwordHash,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("error updating password: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+// MarkEmailVerified registra que el usuario confirmó su email
+// Si ya estaba verificado se conserva la fecha original
+func (r *MySQLUsersRepository) MarkEmailVerified(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).
+		Model(&dao.User{}).
+		Where("id_usuario = ? AND email_verificado_en IS NULL", id).
+		Updates(map[string]interface{}{
+			"email_verificado_en": time.Now(),
+			"updated_at":          time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("error marking email as verified: %w", result.Error)
+	}
+
+	return nil
+}
+
+// ApplyChanges actualiza solo los campos indicados en changes
+// Con UnverifyEmail el email vuelve a quedar sin verificar en el mismo UPDATE
+func (r *MySQLUsersRepository) ApplyChanges(ctx context.Context, id uint, changes domain.UserChanges) (domain.User, error) {
+	updates := map[string]interface{}{
+		"updated_at": time.Now(),
+	}
+	if changes.Nombre != nil {
+		updates["nombre"] = *changes.Nombre
+	}
+	if changes.Apellido != nil {
+		updates["apellido"] = *changes.Apellido
+	}
+	if changes.Email != nil {
+		updates["email"] = *changes.Email
+	}
+	if changes.SucursalOrigenID != nil {
+		updates["sucursal_origen_id"] = *changes.SucursalOrigenID
+	}
+	if changes.IsAdmin != nil {
+		updates["is_admin"] = *changes.IsAdmin
+	}
+	if changes.UnverifyEmail {
+		updates["email_verificado_en"] = nil
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&dao.User{}).
+		Where("id_usuario = ?", id).
+		Updates(updates)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) || contains(result.Error.Error(), "Duplicate entry") {
+			return domain.User{}, ErrEmailAlreadyExists
+		}
+		return domain.User{}, fmt.Errorf("error updating user: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.User{}, errors.New("user not found")
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// SetDisabled deshabilita (deleted_at = ahora) o restaura (deleted_at = NULL) una cuenta
+// Deshabilitar una cuenta ya deshabilitada conserva la fecha original
+func (r *MySQLUsersRepository) SetDisabled(ctx context.Context, id uint, disabled bool) error {
+	var deletedAt interface{}
+	if disabled {
+		deletedAt = gorm.Expr("COALESCE(deleted_at, ?)", time.Now())
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&dao.User{}).
+		Where("id_usuario = ?", id).
+		Updates(map[string]interface{}{
+			"deleted_at": deletedAt,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("error updating user status: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+
+	return nil
+}
+
+// GetRoles devuelve los roles de staff del usuario, ordenados por rol y sucursal
+func (r *MySQLUsersRepository) GetRoles(ctx context.Context, id uint) ([]domain.RoleAssignment, error) {
+	var rolesDAO []dao.RolUsuario
+
+	if err := r.db.WithContext(ctx).
+		Where("usuario_id = ?", id).
+		Order("rol ASC, sucursal_id ASC").
+		Find(&rolesDAO).Error; err != nil {
+		return nil, fmt.Errorf("error getting user roles: %w", err)
+	}
+
+	roles := make([]domain.RoleAssignment, len(rolesDAO))
+	for i, roleDAO := range rolesDAO {
+		roles[i] = roleDAO.ToDomain()
+	}
+
+	return roles, nil
+}
+
+// SetRoles reemplaza los roles de staff del usuario en una transacción
+func (r *MySQLUsersRepository) SetRoles(ctx context.Context, id uint, roles []domain.RoleAssignment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("usuario_id = ?", id).Delete(&dao.RolUsuario{}).Error; err != nil {
+			return fmt.Errorf("error deleting user roles: %w", err)
+		}
+
+		if len(roles) == 0 {
+			return nil
+		}
+
+		rolesDAO := make([]dao.RolUsuario, len(roles))
+		for i, role := range roles {
+			rolesDAO[i] = dao.RolUsuario{UsuarioID: id, Rol: role.Role, SucursalID: role.SucursalID}
+		}
+		if err := tx.Create(&rolesDAO).Error; err != nil {
+			return fmt.Errorf("error saving user roles: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Delete elimina un usuario de forma definitiva (para deshabilitarlo usar SetDisabled)
 func (r *MySQLUsersRepository) Delete(ctx context.Context, id uint) error {
 	result := r.db.WithContext(ctx).Delete(&dao.User{}, id)
 	if result.Error != nil {
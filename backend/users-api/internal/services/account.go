@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+	"users-api/internal/domain"
+	"users-api/internal/repository"
+)
+
+// Vida de los links enviados por email
+const (
+	passwordResetTTL     = time.Hour
+	emailVerificationTTL = 48 * time.Hour
+)
+
+// Errores de los flujos de cuenta (reset de contraseña y verificación de email)
+var (
+	ErrInvalidAccountToken  = errors.New("token inválido o expirado")
+	ErrInvalidPassword      = errors.New("contraseña inválida")
+	ErrEmailAlreadyVerified = errors.New("el email ya está verificado")
+)
+
+// Mailer envía emails transaccionales
+// Implementaciones en internal/clients: SMTP, archivo (desarrollo) y memoria (tests)
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// AccountLinks son las URLs base de los links que se envían por email (se les agrega ?token=)
+type AccountLinks struct {
+	ResetPasswordURL string // Página del frontend que pide la nueva contraseña y llama a POST /password/reset
+	VerifyEmailURL   string // GET /email/verify de users-api (URL pública)
+}
+
+// ForgotPassword envía un link de reset de contraseña al email indicado
+// Si el email no corresponde a ningún usuario no se informa: evita enumerar cuentas
+func (s *UsersServiceImpl) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.repository.GetByEmail(ctx, email)
+	if err != nil || user.Disabled() {
+		log.Printf("🔑 Reset de contraseña solicitado para un email no registrado o deshabilitado")
+		return nil
+	}
+
+	token, err := s.newAccountToken(ctx, user.ID, domain.TokenPurposePasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Hola %s,\n\n"+
+			"Recibimos un pedido para restablecer tu contraseña. Ingresá al siguiente link para elegir una nueva:\n\n"+
+			"%s\n\n"+
+			"El link vence en %d minutos y se puede usar una sola vez.\n"+
+			"Si no fuiste vos, ignorá este email: tu contraseña no cambia.\n",
+		user.Nombre, accountLink(s.links.ResetPasswordURL, token), int(passwordResetTTL.Minutes()),
+	)
+
+	// Un error de envío solo se loguea: la respuesta debe ser la misma exista o no la cuenta
+	if err := s.mailer.Send(ctx, user.Email, "Restablecer contraseña", body); err != nil {
+		log.Printf("⚠️  Error enviando email de reset al usuario %d: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// ResetPassword cambia la contraseña usando un token de reset y cierra todas las sesiones del usuario
+func (s *UsersServiceImpl) ResetPassword(ctx context.Context, token, newPassword string) error {
+	// Validar antes de consumir el token: una contraseña débil no debe quemar el link
+	if err := validatePassword(newPassword); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidPassword, err)
+	}
+
+	stored, err := s.accountTokensRepo.ConsumeAccountToken(ctx, hashToken(token), domain.TokenPurposePasswordReset)
+	if err != nil {
+		if errors.Is(err, repository.ErrAccountTokenInvalid) {
+			return ErrInvalidAccountToken
+		}
+		return err
+	}
+
+	hashedPassword, err := s.hashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %w", err)
+	}
+
+	if err := s.repository.UpdatePassword(ctx, stored.UserID, hashedPassword); err != nil {
+		return err
+	}
+
+	// Quien tenía la contraseña anterior no debe seguir logueado
+	if err := s.tokensRepo.RevokeUserSessions(ctx, stored.UserID); err != nil {
+		return err
+	}
+
+	// El link llegó a su casilla: el email queda verificado
+	if err := s.repository.MarkEmailVerified(ctx, stored.UserID); err != nil {
+		log.Printf("⚠️  Error marcando email verificado del usuario %d: %v", stored.UserID, err)
+	}
+
+	log.Printf("🔐 Contraseña del usuario %d restablecida, sesiones revocadas", stored.UserID)
+	return nil
+}
+
+// VerifyEmail confirma el email del usuario con el token enviado al registrarse
+func (s *UsersServiceImpl) VerifyEmail(ctx context.Context, token string) error {
+	stored, err := s.accountTokensRepo.ConsumeAccountToken(ctx, hashToken(token), domain.TokenPurposeEmailVerification)
+	if err != nil {
+		if errors.Is(err, repository.ErrAccountTokenInvalid) {
+			return ErrInvalidAccountToken
+		}
+		return err
+	}
+
+	if err := s.repository.MarkEmailVerified(ctx, stored.UserID); err != nil {
+		return err
+	}
+
+	log.Printf("✅ Email del usuario %d verificado", stored.UserID)
+	return nil
+}
+
+// ResendEmailVerification vuelve a enviar el link de verificación (invalida los anteriores)
+func (s *UsersServiceImpl) ResendEmailVerification(ctx context.Context, userID uint) error {
+	user, err := s.repository.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+
+	return s.sendEmailVerification(ctx, user)
+}
+
+// sendEmailVerification genera un token de verificación y envía el link al email del usuario
+func (s *UsersServiceImpl) sendEmailVerification(ctx context.Context, user domain.User) error {
+	token, err := s.newAccountToken(ctx, user.ID, domain.TokenPurposeEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"Hola %s,\n\n"+
+			"Para confirmar tu email ingresá al siguiente link:\n\n"+
+			"%s\n\n"+
+			"El link vence en %d horas. Algunos planes requieren el email verificado para suscribirse.\n",
+		user.Nombre, accountLink(s.links.VerifyEmailURL, token), int(emailVerificationTTL.Hours()),
+	)
+
+	if err := s.mailer.Send(ctx, user.Email, "Confirmá tu email", body); err != nil {
+		return fmt.Errorf("error sending verification email: %w", err)
+	}
+
+	return nil
+}
+
+// newAccountToken genera un token aleatorio de un solo uso y persiste su hash
+func (s *UsersServiceImpl) newAccountToken(ctx context.Context, userID uint, purpose string, ttl time.Duration) (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", fmt.Errorf("error generating account token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(tokenBytes)
+
+	if err := s.accountTokensRepo.CreateAccountToken(ctx, domain.AccountToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// accountLink agrega el token como query param a la URL base
+func accountLink(baseURL, token string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return baseURL + "?token=" + url.QueryEscape(token)
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
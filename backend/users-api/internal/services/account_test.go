@@ -0,0 +1,356 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"users-api/internal/clients"
+	"users-api/internal/domain"
+	"users-api/internal/repository"
+)
+
+// MockAccountTokensRepository es un mock del repositorio de tokens de cuenta para testing
+// Si no se definen funciones, guarda los tokens en memoria
+type MockAccountTokensRepository struct {
+	CreateAccountTokenFunc  func(ctx context.Context, token domain.AccountToken) error
+	GetAccountTokenFunc     func(ctx context.Context, tokenHash, purpose string) (domain.AccountToken, error)
+	ConsumeAccountTokenFunc func(ctx context.Context, tokenHash, purpose string) (domain.AccountToken, error)
+	DeleteExpiredFunc       func(ctx context.Context) error
+
+	tokens []domain.AccountToken
+}
+
+func (m *MockAccountTokensRepository) CreateAccountToken(ctx context.Context, token domain.AccountToken) error {
+	if m.CreateAccountTokenFunc != nil {
+		return m.CreateAccountTokenFunc(ctx, token)
+	}
+	// Igual que MySQL: los tokens pendientes del mismo usuario y propósito quedan invalidados
+	now := time.Now()
+	for i, t := range m.tokens {
+		if t.UserID == token.UserID && t.Purpose == token.Purpose && t.UsedAt == nil {
+			m.tokens[i].UsedAt = &now
+		}
+	}
+	token.ID = uint(len(m.tokens) + 1)
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func (m *MockAccountTokensRepository) GetAccountToken(ctx context.Context, tokenHash, purpose string) (domain.AccountToken, error) {
+	if m.GetAccountTokenFunc != nil {
+		return m.GetAccountTokenFunc(ctx, tokenHash, purpose)
+	}
+	now := time.Now()
+	for _, t := range m.tokens {
+		if t.TokenHash == tokenHash && t.Purpose == purpose && t.UsedAt == nil && t.ExpiresAt.After(now) {
+			return t, nil
+		}
+	}
+	return domain.AccountToken{}, repository.ErrAccountTokenInvalid
+}
+
+func (m *MockAccountTokensRepository) ConsumeAccountToken(ctx context.Context, tokenHash, purpose string) (domain.AccountToken, error) {
+	if m.ConsumeAccountTokenFunc != nil {
+		return m.ConsumeAccountTokenFunc(ctx, tokenHash, purpose)
+	}
+	now := time.Now()
+	for i, t := range m.tokens {
+		if t.TokenHash == tokenHash && t.Purpose == purpose && t.UsedAt == nil && t.ExpiresAt.After(now) {
+			m.tokens[i].UsedAt = &now
+			return m.tokens[i], nil
+		}
+	}
+	return domain.AccountToken{}, repository.ErrAccountTokenInvalid
+}
+
+func (m *MockAccountTokensRepository) DeleteExpired(ctx context.Context) error {
+	if m.DeleteExpiredFunc != nil {
+		return m.DeleteExpiredFunc(ctx)
+	}
+	return nil
+}
+
+// Verificar que MockAccountTokensRepository implementa la interfaz
+var _ repository.AccountTokensRepository = (*MockAccountTokensRepository)(nil)
+
+// accountTestUser es el usuario de los tests de flujos de cuenta
+var accountTestUser = domain.User{
+	ID:       7,
+	Nombre:   "Juan",
+	Username: "juanperez",
+	Email:    "juan@example.com",
+}
+
+// accountTestEnv agrupa el servicio y sus dependencias en memoria
+type accountTestEnv struct {
+	service       *UsersServiceImpl
+	mailer        *clients.MemoryMailer
+	accountTokens *MockAccountTokensRepository
+	tokensRepo    *MockTokensRepository
+	newPassword   string
+	verified      bool
+}
+
+func newAccountTestEnv() *accountTestEnv {
+	env := &accountTestEnv{
+		mailer:        clients.NewMemoryMailer(),
+		accountTokens: &MockAccountTokensRepository{},
+		tokensRepo:    &MockTokensRepository{},
+	}
+
+	usersRepo := &MockUsersRepository{
+		GetByEmailFunc: func(ctx context.Context, email string) (domain.User, error) {
+			if email == accountTestUser.Email {
+				return accountTestUser, nil
+			}
+			return domain.User{}, errors.New("user not found")
+		},
+		GetByIDFunc: func(ctx context.Context, id uint) (domain.User, error) {
+			user := accountTestUser
+			if env.verified {
+				now := time.Now()
+				user.EmailVerifiedAt = &now
+			}
+			return user, nil
+		},
+		CreateFunc: func(ctx context.Context, user domain.User) (domain.User, error) {
+			user.ID = accountTestUser.ID
+			return user, nil
+		},
+		UpdatePasswordFunc: func(ctx context.Context, id uint, passwordHash string) error {
+			env.newPassword = passwordHash
+			return nil
+		},
+		MarkEmailVerifiedFunc: func(ctx context.Context, id uint) error {
+			env.verified = true
+			return nil
+		},
+	}
+
+	env.service = NewUsersService(usersRepo, env.tokensRepo, env.accountTokens, &MockTwoFactorRepository{}, testSigningKeys, env.mailer, &MockEventPublisher{}, testAccountLinks, newTestLoginGuard(), DefaultTwoFactorPolicy(), 30*time.Minute, 24*time.Hour)
+	return env
+}
+
+var tokenParam = regexp.MustCompile(`token=([A-Za-z0-9_-]+)`)
+
+// linkToken extrae el token del último email enviado al usuario de prueba
+func (env *accountTestEnv) linkToken(t *testing.T, baseURL string) string {
+	t.Helper()
+
+	msg, ok := env.mailer.LastTo(accountTestUser.Email)
+	if !ok {
+		t.Fatal("Expected an email to be sent")
+	}
+	if !strings.Contains(msg.Body, baseURL+"?token=") {
+		t.Fatalf("Expected link to %s, got body: %s", baseURL, msg.Body)
+	}
+
+	match := tokenParam.FindStringSubmatch(msg.Body)
+	if match == nil {
+		t.Fatalf("Expected token in email body: %s", msg.Body)
+	}
+	return match[1]
+}
+
+// TestForgotPassword_SendsSingleUseLink prueba el flujo completo de reset de contraseña
+func TestForgotPassword_SendsSingleUseLink(t *testing.T) {
+	env := newAccountTestEnv()
+	ctx := context.Background()
+
+	// Una sesión abierta que debe cerrarse con el reset
+	env.tokensRepo.tokens = []domain.RefreshToken{{ID: 1, UserID: accountTestUser.ID, FamilyID: "fam"}}
+
+	if err := env.service.ForgotPassword(ctx, accountTestUser.Email); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	token := env.linkToken(t, testAccountLinks.ResetPasswordURL)
+
+	// Solo se persiste el hash
+	if len(env.accountTokens.tokens) != 1 || env.accountTokens.tokens[0].TokenHash != hashToken(token) {
+		t.Fatalf("Expected the token hash to be stored, got: %+v", env.accountTokens.tokens)
+	}
+	if env.accountTokens.tokens[0].TokenHash == token {
+		t.Error("Token must not be stored in plain text")
+	}
+
+	if err := env.service.ResetPassword(ctx, token, "NuevaPass123!"); err != nil {
+		t.Fatalf("Expected no error on reset, got: %v", err)
+	}
+	if !strings.HasPrefix(env.newPassword, "$argon2id$") {
+		t.Errorf("Expected argon2id hash to be stored, got: %q", env.newPassword)
+	}
+	if env.tokensRepo.tokens[0].RevokedAt == nil {
+		t.Error("Expected user sessions to be revoked after reset")
+	}
+	if !env.verified {
+		t.Error("Expected email to be marked verified after a reset via email link")
+	}
+
+	// El link es de un solo uso
+	if err := env.service.ResetPassword(ctx, token, "OtraPass123!"); !errors.Is(err, ErrInvalidAccountToken) {
+		t.Errorf("Expected ErrInvalidAccountToken on reuse, got: %v", err)
+	}
+}
+
+// TestForgotPassword_UnknownEmail prueba que no se revela si el email existe
+func TestForgotPassword_UnknownEmail(t *testing.T) {
+	env := newAccountTestEnv()
+
+	if err := env.service.ForgotPassword(context.Background(), "nadie@example.com"); err != nil {
+		t.Fatalf("Expected no error for unknown email, got: %v", err)
+	}
+	if len(env.mailer.Messages()) != 0 {
+		t.Error("Expected no email to be sent")
+	}
+}
+
+// TestForgotPassword_MailerFailure prueba que un error de envío no cambia la respuesta
+func TestForgotPassword_MailerFailure(t *testing.T) {
+	env := newAccountTestEnv()
+	env.mailer.FailWith(errors.New("smtp down"))
+
+	if err := env.service.ForgotPassword(context.Background(), accountTestUser.Email); err != nil {
+		t.Fatalf("Expected no error when the mailer fails, got: %v", err)
+	}
+}
+
+// TestForgotPassword_OnlyLatestLinkWorks prueba que pedir un nuevo link invalida el anterior
+func TestForgotPassword_OnlyLatestLinkWorks(t *testing.T) {
+	env := newAccountTestEnv()
+	ctx := context.Background()
+
+	env.service.ForgotPassword(ctx, accountTestUser.Email)
+	first := env.linkToken(t, testAccountLinks.ResetPasswordURL)
+	env.service.ForgotPassword(ctx, accountTestUser.Email)
+	second := env.linkToken(t, testAccountLinks.ResetPasswordURL)
+
+	if err := env.service.ResetPassword(ctx, first, "NuevaPass123!"); !errors.Is(err, ErrInvalidAccountToken) {
+		t.Errorf("Expected first link to be invalid, got: %v", err)
+	}
+	if err := env.service.ResetPassword(ctx, second, "NuevaPass123!"); err != nil {
+		t.Errorf("Expected latest link to work, got: %v", err)
+	}
+}
+
+// TestResetPassword_ExpiredToken prueba que un token vencido se rechaza
+func TestResetPassword_ExpiredToken(t *testing.T) {
+	env := newAccountTestEnv()
+	env.accountTokens.tokens = []domain.AccountToken{{
+		ID:        1,
+		UserID:    accountTestUser.ID,
+		Purpose:   domain.TokenPurposePasswordReset,
+		TokenHash: hashToken("expired-token"),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}}
+
+	err := env.service.ResetPassword(context.Background(), "expired-token", "NuevaPass123!")
+	if !errors.Is(err, ErrInvalidAccountToken) {
+		t.Errorf("Expected ErrInvalidAccountToken, got: %v", err)
+	}
+	if env.newPassword != "" {
+		t.Error("Password must not change with an expired token")
+	}
+}
+
+// TestResetPassword_WeakPasswordKeepsToken prueba que una contraseña débil no consume el token
+func TestResetPassword_WeakPasswordKeepsToken(t *testing.T) {
+	env := newAccountTestEnv()
+	ctx := context.Background()
+
+	env.service.ForgotPassword(ctx, accountTestUser.Email)
+	token := env.linkToken(t, testAccountLinks.ResetPasswordURL)
+
+	if err := env.service.ResetPassword(ctx, token, "debil"); !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("Expected ErrInvalidPassword, got: %v", err)
+	}
+	if err := env.service.ResetPassword(ctx, token, "NuevaPass123!"); err != nil {
+		t.Errorf("Expected token to remain valid, got: %v", err)
+	}
+}
+
+// TestRegister_SendsVerificationEmail prueba el flujo de verificación de email
+func TestRegister_SendsVerificationEmail(t *testing.T) {
+	env := newAccountTestEnv()
+	ctx := context.Background()
+
+	user, tokens, err := env.service.Register(ctx, domain.UserRegister{
+		Nombre:   "Juan",
+		Apellido: "Perez",
+		Username: "juanperez",
+		Email:    accountTestUser.Email,
+		Password: "Password123!",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error on register, got: %v", err)
+	}
+	if user.EmailVerified {
+		t.Error("New users must start unverified")
+	}
+
+	claims, err := env.service.ValidateToken(tokens.AccessToken)
+	if err != nil || claims.EmailVerified {
+		t.Errorf("Expected email_verified=false claim, got %v (%v)", claims, err)
+	}
+
+	token := env.linkToken(t, testAccountLinks.VerifyEmailURL)
+	if err := env.service.VerifyEmail(ctx, token); err != nil {
+		t.Fatalf("Expected no error on verify, got: %v", err)
+	}
+	if !env.verified {
+		t.Error("Expected email to be marked verified")
+	}
+
+	// Un token de verificación no sirve para resetear la contraseña ni se puede reutilizar
+	if err := env.service.ResetPassword(ctx, token, "NuevaPass123!"); !errors.Is(err, ErrInvalidAccountToken) {
+		t.Errorf("Expected verification token to be rejected for reset, got: %v", err)
+	}
+	if err := env.service.VerifyEmail(ctx, token); !errors.Is(err, ErrInvalidAccountToken) {
+		t.Errorf("Expected ErrInvalidAccountToken on reuse, got: %v", err)
+	}
+
+	// Los tokens nuevos reflejan la verificación
+	refreshed, err := env.service.RefreshTokens(ctx, tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("Expected no error on refresh, got: %v", err)
+	}
+	claims, _ = env.service.ValidateToken(refreshed.AccessToken)
+	if claims == nil || !claims.EmailVerified {
+		t.Error("Expected email_verified=true claim after verification")
+	}
+}
+
+// TestRegister_MailerFailureDoesNotFail prueba que el registro no falla si no se puede enviar el email
+func TestRegister_MailerFailureDoesNotFail(t *testing.T) {
+	env := newAccountTestEnv()
+	env.mailer.FailWith(errors.New("smtp down"))
+
+	_, _, err := env.service.Register(context.Background(), domain.UserRegister{
+		Nombre:   "Juan",
+		Apellido: "Perez",
+		Username: "juanperez",
+		Email:    accountTestUser.Email,
+		Password: "Password123!",
+	})
+	if err != nil {
+		t.Fatalf("Expected register to succeed, got: %v", err)
+	}
+}
+
+// TestResendEmailVerification prueba el reenvío del link de verificación
+func TestResendEmailVerification(t *testing.T) {
+	env := newAccountTestEnv()
+	ctx := context.Background()
+
+	if err := env.service.ResendEmailVerification(ctx, accountTestUser.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	env.linkToken(t, testAccountLinks.VerifyEmailURL)
+
+	env.verified = true
+	if err := env.service.ResendEmailVerification(ctx, accountTestUser.ID); !errors.Is(err, ErrEmailAlreadyVerified) {
+		t.Errorf("Expected ErrEmailAlreadyVerified, got: %v", err)
+	}
+}
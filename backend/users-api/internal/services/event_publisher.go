@@ -0,0 +1,7 @@
+package services
+
+// EventPublisher define la interfaz para publicar eventos de usuarios (routing key user.{action})
+// Implementaciones en internal/clients: RabbitMQ y Null (sin broker)
+type EventPublisher interface {
+	PublishUserEvent(action, userID string, data map[string]interface{}) error
+}
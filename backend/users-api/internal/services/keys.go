@@ -0,0 +1,171 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/gym-management/shared/auth"
+)
+
+// SigningKeys contiene las claves privadas con las que users-api firma los access tokens
+// Solo users-api tiene las claves privadas: los demás servicios verifican con el JWKS público
+type SigningKeys struct {
+	activeKID string
+	signers   map[string]crypto.Signer
+}
+
+// LoadSigningKeys carga las claves privadas PEM (RSA o Ed25519) de un directorio
+// El kid de cada clave es el nombre del archivo sin extensión (ej: 2025-01.pem -> "2025-01")
+// Rotación: agregar la clave nueva al directorio, apuntar activeKID a ella y
+// retirar la anterior cuando expiren los access tokens que firmó
+func LoadSigningKeys(dir, activeKID string) (*SigningKeys, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("error listando claves: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no hay claves .pem en %s", dir)
+	}
+	sort.Strings(paths)
+
+	keys := &SigningKeys{signers: make(map[string]crypto.Signer, len(paths))}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo %s: %w", path, err)
+		}
+
+		signer, err := parsePrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("clave inválida en %s: %w", path, err)
+		}
+
+		kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		keys.signers[kid] = signer
+	}
+
+	if activeKID == "" {
+		if len(keys.signers) > 1 {
+			return nil, errors.New("hay varias claves: JWT_ACTIVE_KID es obligatorio")
+		}
+		for kid := range keys.signers {
+			activeKID = kid
+		}
+	}
+	if _, ok := keys.signers[activeKID]; !ok {
+		return nil, fmt.Errorf("la clave activa %q no existe en %s", activeKID, dir)
+	}
+	keys.activeKID = activeKID
+
+	return keys, nil
+}
+
+// NewEphemeralSigningKeys genera una clave Ed25519 en memoria
+// Solo para desarrollo: se pierde al reiniciar (los tokens emitidos dejan de ser válidos)
+func NewEphemeralSigningKeys() (*SigningKeys, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("error generando clave: %w", err)
+	}
+
+	kid, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+	kid = "dev-" + kid
+
+	return &SigningKeys{
+		activeKID: kid,
+		signers:   map[string]crypto.Signer{kid: priv},
+	}, nil
+}
+
+// Active devuelve el kid, la clave privada y el algoritmo con los que se firman los tokens nuevos
+func (k *SigningKeys) Active() (string, crypto.Signer, string) {
+	signer := k.signers[k.activeKID]
+	alg, _ := auth.AlgorithmFor(signer.Public())
+	return k.activeKID, signer, alg
+}
+
+// PublicKey implementa auth.KeyProvider para que users-api verifique sus propios tokens
+func (k *SigningKeys) PublicKey(kid, alg string) (crypto.PublicKey, error) {
+	signer, ok := k.signers[kid]
+	if !ok {
+		return nil, fmt.Errorf("kid desconocido: %s", kid)
+	}
+
+	pub := signer.Public()
+	keyAlg, err := auth.AlgorithmFor(pub)
+	if err != nil {
+		return nil, err
+	}
+	if keyAlg != alg {
+		return nil, fmt.Errorf("algoritmo %s no corresponde a la clave %s", alg, kid)
+	}
+
+	return pub, nil
+}
+
+// JWKS devuelve las claves públicas de todas las claves cargadas (activa y anteriores)
+func (k *SigningKeys) JWKS() auth.JWKSet {
+	kids := make([]string, 0, len(k.signers))
+	for kid := range k.signers {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	set := auth.JWKSet{Keys: make([]auth.JWK, 0, len(kids))}
+	for _, kid := range kids {
+		jwk, err := auth.NewJWK(kid, k.signers[kid].Public())
+		if err != nil {
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+// parsePrivateKey parsea una clave privada PEM en formato PKCS#8 o PKCS#1 (RSA)
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no es un archivo PEM")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("tipo PEM no soportado: %s", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < 2048 {
+			return nil, errors.New("las claves RSA deben tener al menos 2048 bits")
+		}
+		return k, nil
+	case ed25519.PrivateKey:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("tipo de clave no soportado: %T", key)
+	}
+}
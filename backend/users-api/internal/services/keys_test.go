@@ -0,0 +1,71 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourusername/gym-management/shared/auth"
+)
+
+// writeTestKey guarda una clave privada PKCS#8 en dir/<kid>.pem
+func writeTestKey(t *testing.T, dir, kid string, key interface{}) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Error marshaling key: %v", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600); err != nil {
+		t.Fatalf("Error writing key: %v", err)
+	}
+}
+
+// TestLoadSigningKeys_Rotation prueba que se firma con la clave activa y se publican todas
+func TestLoadSigningKeys_Rotation(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Error generating RSA key: %v", err)
+	}
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	writeTestKey(t, dir, "2025-01", rsaKey)
+	writeTestKey(t, dir, "2025-02", edKey)
+
+	// Con varias claves el kid activo es obligatorio
+	if _, err := LoadSigningKeys(dir, ""); err == nil {
+		t.Error("Expected error when active kid is missing with several keys")
+	}
+	if _, err := LoadSigningKeys(dir, "2024-12"); err == nil {
+		t.Error("Expected error for unknown active kid")
+	}
+
+	keys, err := LoadSigningKeys(dir, "2025-02")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	kid, _, alg := keys.Active()
+	if kid != "2025-02" || alg != auth.AlgEdDSA {
+		t.Errorf("Expected active key 2025-02/EdDSA, got %s/%s", kid, alg)
+	}
+
+	// La clave anterior sigue publicada para verificar los tokens que ya emitió
+	jwks := keys.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("Expected 2 published keys, got %d", len(jwks.Keys))
+	}
+	if _, err := keys.PublicKey("2025-01", auth.AlgRS256); err != nil {
+		t.Errorf("Expected previous RSA key to verify, got %v", err)
+	}
+	if _, err := keys.PublicKey("2025-01", auth.AlgEdDSA); err == nil {
+		t.Error("Expected error for algorithm mismatch")
+	}
+}
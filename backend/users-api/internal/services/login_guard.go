@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"users-api/internal/domain"
+	"users-api/internal/repository"
+)
+
+// Errores de la protección contra fuerza bruta en el login
+var (
+	ErrTooManyLoginAttempts = errors.New("demasiados intentos de login, probá más tarde")
+	ErrAccountLocked        = errors.New("cuenta bloqueada temporalmente por intentos fallidos")
+)
+
+// LoginBlockedError indica que el intento fue rechazado antes de verificar la contraseña
+// Es ErrTooManyLoginAttempts (espera progresiva o IP bloqueada) o ErrAccountLocked (cuenta bloqueada)
+type LoginBlockedError struct {
+	Reason     error
+	RetryAfter time.Duration
+}
+
+func (e *LoginBlockedError) Error() string {
+	return fmt.Sprintf("%s (reintentar en %s)", e.Reason, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginBlockedError) Unwrap() error {
+	return e.Reason
+}
+
+// LoginPolicy define los umbrales de la protección contra fuerza bruta
+type LoginPolicy struct {
+	Window          time.Duration // Ventana en la que se acumulan los fallos consecutivos
+	DelayAfter      int           // Fallos de una cuenta a partir de los cuales se exige esperar
+	BaseDelay       time.Duration // Primera espera; se duplica con cada fallo
+	MaxDelay        time.Duration // Tope de la espera progresiva
+	MaxFailures     int           // Fallos de una cuenta que la bloquean
+	LockoutDuration time.Duration // Duración del bloqueo de cuenta
+	IPMaxFailures   int           // Fallos desde una IP (sobre cualquier cuenta) que la bloquean
+	IPBlockDuration time.Duration // Duración del bloqueo de IP
+}
+
+// DefaultLoginPolicy son los umbrales por defecto
+func DefaultLoginPolicy() LoginPolicy {
+	return LoginPolicy{
+		Window:          15 * time.Minute,
+		DelayAfter:      3,
+		BaseDelay:       time.Second,
+		MaxDelay:        30 * time.Second,
+		MaxFailures:     5,
+		LockoutDuration: 15 * time.Minute,
+		IPMaxFailures:   20,
+		IPBlockDuration: 15 * time.Minute,
+	}
+}
+
+// LoginGuard lleva la cuenta de logins fallidos por cuenta y por IP
+// El estado vive en MySQL: sobrevive reinicios y se comparte entre réplicas
+type LoginGuard struct {
+	repo      repository.LoginAttemptsRepository
+	publisher EventPublisher
+	policy    LoginPolicy
+	now       func() time.Time
+}
+
+// NewLoginGuard crea una nueva instancia del guard
+func NewLoginGuard(repo repository.LoginAttemptsRepository, publisher EventPublisher, policy LoginPolicy) *LoginGuard {
+	return &LoginGuard{
+		repo:      repo,
+		publisher: publisher,
+		policy:    policy,
+		now:       time.Now,
+	}
+}
+
+// CheckIP rechaza el intento si la IP está bloqueada
+func (g *LoginGuard) CheckIP(ctx context.Context, ip string) error {
+	if ip == "" {
+		return nil
+	}
+	return g.check(ctx, domain.LoginAttemptIP, ip, ErrTooManyLoginAttempts)
+}
+
+// CheckAccount rechaza el intento si la cuenta está en espera o bloqueada
+// Se consulta antes de verificar la contraseña: durante el bloqueo ni la contraseña correcta entra
+func (g *LoginGuard) CheckAccount(ctx context.Context, userID uint) error {
+	attempt, err := g.repo.GetLoginAttempt(ctx, domain.LoginAttemptAccount, accountKey(userID))
+	if err != nil {
+		return err
+	}
+
+	now := g.now()
+	if !attempt.BlockedAt(now) {
+		return nil
+	}
+
+	reason := ErrTooManyLoginAttempts
+	if attempt.Failures >= g.policy.MaxFailures {
+		reason = ErrAccountLocked
+	}
+	return &LoginBlockedError{Reason: reason, RetryAfter: attempt.BlockedUntil.Sub(now)}
+}
+
+// RecordFailure registra un login fallido para la IP y, si el usuario existe, para la cuenta
+// Los errores se loguean: no deben cambiar la respuesta de credenciales inválidas
+func (g *LoginGuard) RecordFailure(ctx context.Context, ip string, user *domain.User) {
+	if ip != "" {
+		failures, err := g.repo.RecordLoginFailure(ctx, domain.LoginAttemptIP, ip, g.policy.Window)
+		if err != nil {
+			log.Printf("⚠️  Error registrando login fallido de la IP %s: %v", ip, err)
+		} else if failures >= g.policy.IPMaxFailures {
+			g.block(ctx, domain.LoginAttemptIP, ip, g.policy.IPBlockDuration)
+			log.Printf("🛑 IP %s bloqueada por %d logins fallidos", ip, failures)
+		}
+	}
+
+	if user == nil {
+		return
+	}
+
+	key := accountKey(user.ID)
+	failures, err := g.repo.RecordLoginFailure(ctx, domain.LoginAttemptAccount, key, g.policy.Window)
+	if err != nil {
+		log.Printf("⚠️  Error registrando login fallido del usuario %d: %v", user.ID, err)
+		return
+	}
+
+	switch {
+	case failures >= g.policy.MaxFailures:
+		until := g.block(ctx, domain.LoginAttemptAccount, key, g.policy.LockoutDuration)
+		log.Printf("🔒 Usuario %d bloqueado hasta %s por %d logins fallidos", user.ID, until.Format(time.RFC3339), failures)
+
+		if err := g.publisher.PublishUserEvent("locked", key, map[string]interface{}{
+			"username":     user.Username,
+			"email":        user.Email,
+			"failures":     failures,
+			"ip":           ip,
+			"locked_until": until,
+		}); err != nil {
+			log.Printf("⚠️  Error publicando evento user.locked: %v", err)
+		}
+	case failures >= g.policy.DelayAfter:
+		g.block(ctx, domain.LoginAttemptAccount, key, g.delay(failures))
+	}
+}
+
+// RecordSuccess borra los fallos de la cuenta (los de la IP se mantienen)
+func (g *LoginGuard) RecordSuccess(ctx context.Context, userID uint) {
+	if err := g.repo.ResetLoginAttempts(ctx, domain.LoginAttemptAccount, accountKey(userID)); err != nil {
+		log.Printf("⚠️  Error reseteando logins fallidos del usuario %d: %v", userID, err)
+	}
+}
+
+// Unlock desbloquea una cuenta y publica user.unlocked
+func (g *LoginGuard) Unlock(ctx context.Context, userID uint) error {
+	if err := g.repo.ResetLoginAttempts(ctx, domain.LoginAttemptAccount, accountKey(userID)); err != nil {
+		return err
+	}
+
+	if err := g.publisher.PublishUserEvent("unlocked", accountKey(userID), nil); err != nil {
+		log.Printf("⚠️  Error publicando evento user.unlocked: %v", err)
+	}
+
+	return nil
+}
+
+// PurgeExpired elimina los registros de intentos que ya no afectan a nadie
+func (g *LoginGuard) PurgeExpired(ctx context.Context) error {
+	return g.repo.DeleteExpired(ctx, g.now().Add(-g.policy.Window))
+}
+
+// check rechaza el intento si la clave está bloqueada
+func (g *LoginGuard) check(ctx context.Context, kind, key string, reason error) error {
+	attempt, err := g.repo.GetLoginAttempt(ctx, kind, key)
+	if err != nil {
+		return err
+	}
+
+	now := g.now()
+	if attempt.BlockedAt(now) {
+		return &LoginBlockedError{Reason: reason, RetryAfter: attempt.BlockedUntil.Sub(now)}
+	}
+
+	return nil
+}
+
+// block bloquea la clave durante d y devuelve hasta cuándo
+func (g *LoginGuard) block(ctx context.Context, kind, key string, d time.Duration) time.Time {
+	until := g.now().Add(d)
+	if err := g.repo.BlockLoginAttempts(ctx, kind, key, until); err != nil {
+		log.Printf("⚠️  Error bloqueando logins de %s %s: %v", kind, key, err)
+	}
+	return until
+}
+
+// delay calcula la espera progresiva: BaseDelay, 2x, 4x... hasta MaxDelay
+func (g *LoginGuard) delay(failures int) time.Duration {
+	d := g.policy.BaseDelay
+	for i := g.policy.DelayAfter; i < failures && d < g.policy.MaxDelay; i++ {
+		d *= 2
+	}
+	if d > g.policy.MaxDelay {
+		d = g.policy.MaxDelay
+	}
+	return d
+}
+
+// accountKey es la clave de los intentos de una cuenta (el ID, no el username: no cambia)
+func accountKey(userID uint) string {
+	return fmt.Sprintf("%d", userID)
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"users-api/internal/clients"
+	"users-api/internal/domain"
+	"users-api/internal/repository"
+)
+
+// MockLoginAttemptsRepository es un mock del repositorio de logins fallidos para testing
+// Si no se definen funciones, guarda los intentos en memoria
+type MockLoginAttemptsRepository struct {
+	GetLoginAttemptFunc    func(ctx context.Context, kind, key string) (domain.LoginAttempt, error)
+	RecordLoginFailureFunc func(ctx context.Context, kind, key string, window time.Duration) (int, error)
+
+	attempts map[string]domain.LoginAttempt
+}
+
+func (m *MockLoginAttemptsRepository) GetLoginAttempt(ctx context.Context, kind, key string) (domain.LoginAttempt, error) {
+	if m.GetLoginAttemptFunc != nil {
+		return m.GetLoginAttemptFunc(ctx, kind, key)
+	}
+	if attempt, ok := m.attempts[kind+":"+key]; ok {
+		return attempt, nil
+	}
+	return domain.LoginAttempt{Kind: kind, Key: key}, nil
+}
+
+func (m *MockLoginAttemptsRepository) RecordLoginFailure(ctx context.Context, kind, key string, window time.Duration) (int, error) {
+	if m.RecordLoginFailureFunc != nil {
+		return m.RecordLoginFailureFunc(ctx, kind, key, window)
+	}
+	if m.attempts == nil {
+		m.attempts = map[string]domain.LoginAttempt{}
+	}
+	attempt := m.attempts[kind+":"+key]
+	attempt.Kind, attempt.Key = kind, key
+	attempt.Failures++
+	attempt.LastFailureAt = time.Now()
+	m.attempts[kind+":"+key] = attempt
+	return attempt.Failures, nil
+}
+
+func (m *MockLoginAttemptsRepository) BlockLoginAttempts(ctx context.Context, kind, key string, until time.Time) error {
+	attempt, ok := m.attempts[kind+":"+key]
+	if !ok {
+		return nil
+	}
+	if attempt.BlockedUntil == nil || until.After(*attempt.BlockedUntil) {
+		attempt.BlockedUntil = &until
+	}
+	m.attempts[kind+":"+key] = attempt
+	return nil
+}
+
+func (m *MockLoginAttemptsRepository) ResetLoginAttempts(ctx context.Context, kind, key string) error {
+	delete(m.attempts, kind+":"+key)
+	return nil
+}
+
+func (m *MockLoginAttemptsRepository) DeleteExpired(ctx context.Context, olderThan time.Time) error {
+	return nil
+}
+
+// Verificar que MockLoginAttemptsRepository implementa la interfaz
+var _ repository.LoginAttemptsRepository = (*MockLoginAttemptsRepository)(nil)
+
+// MockEventPublisher guarda los eventos publicados
+type MockEventPublisher struct {
+	events []string
+	data   []map[string]interface{}
+}
+
+func (m *MockEventPublisher) PublishUserEvent(action, userID string, data map[string]interface{}) error {
+	m.events = append(m.events, action+":"+userID)
+	m.data = append(m.data, data)
+	return nil
+}
+
+// newTestLoginGuard crea un guard con la política por defecto y estado en memoria
+func newTestLoginGuard() *LoginGuard {
+	return NewLoginGuard(&MockLoginAttemptsRepository{}, &MockEventPublisher{}, DefaultLoginPolicy())
+}
+
+// loginGuardTestEnv arma un servicio con un usuario logueable, el guard en memoria y un reloj controlable
+type loginGuardTestEnv struct {
+	service   *UsersServiceImpl
+	guard     *LoginGuard
+	attempts  *MockLoginAttemptsRepository
+	publisher *MockEventPublisher
+	now       time.Time
+}
+
+func newLoginGuardTestEnv() *loginGuardTestEnv {
+	env := &loginGuardTestEnv{
+		attempts:  &MockLoginAttemptsRepository{},
+		publisher: &MockEventPublisher{},
+		now:       time.Now(),
+	}
+	env.guard = NewLoginGuard(env.attempts, env.publisher, DefaultLoginPolicy())
+	env.guard.now = func() time.Time { return env.now }
+
+	user := domain.User{
+		ID:       1,
+		Username: "juanperez",
+		// Hash SHA-256 legado de "password123"
+		Password: "ef92b778bafe771e89245b89ecbc08a44a4e166c06659911881f383d4473e94f",
+	}
+	usersRepo := &MockUsersRepository{
+		GetByUsernameOrEmailFunc: func(ctx context.Context, usernameOrEmail string) (domain.User, error) {
+			if usernameOrEmail != user.Username {
+				return domain.User{}, errors.New("user not found")
+			}
+			return user, nil
+		},
+		GetByIDFunc: func(ctx context.Context, id uint) (domain.User, error) {
+			if id != user.ID {
+				return domain.User{}, errors.New("user not found")
+			}
+			return user, nil
+		},
+		UpdatePasswordFunc: func(ctx context.Context, id uint, passwordHash string) error {
+			return nil
+		},
+	}
+
+	env.service = NewUsersService(usersRepo, &MockTokensRepository{}, &MockAccountTokensRepository{}, &MockTwoFactorRepository{}, testSigningKeys, clients.NewMemoryMailer(), env.publisher, testAccountLinks, env.guard, DefaultTwoFactorPolicy(), 30*time.Minute, 24*time.Hour)
+	return env
+}
+
+func (env *loginGuardTestEnv) login(username, password string) error {
+	_, _, err := env.service.Login(context.Background(), domain.UserLogin{
+		UsernameOrEmail: username,
+		Password:        password,
+		ClientIP:        "203.0.113.7",
+	})
+	return err
+}
+
+// TestLogin_ProgressiveDelay prueba que a partir del tercer fallo hay que esperar, y que la espera crece
+func TestLogin_ProgressiveDelay(t *testing.T) {
+	env := newLoginGuardTestEnv()
+
+	for i := 0; i < 3; i++ {
+		if err := env.login("juanperez", "incorrecta"); err == nil || errors.Is(err, ErrTooManyLoginAttempts) {
+			t.Fatalf("Expected invalid credentials on attempt %d, got %v", i+1, err)
+		}
+	}
+
+	// Inmediatamente después del tercer fallo: 429 aunque la contraseña sea correcta
+	err := env.login("juanperez", "password123")
+	var blocked *LoginBlockedError
+	if !errors.As(err, &blocked) || !errors.Is(err, ErrTooManyLoginAttempts) {
+		t.Fatalf("Expected ErrTooManyLoginAttempts, got %v", err)
+	}
+	if blocked.RetryAfter != time.Second {
+		t.Errorf("Expected 1s delay, got %s", blocked.RetryAfter)
+	}
+
+	// Pasada la espera, un nuevo fallo duplica la espera
+	env.now = env.now.Add(2 * time.Second)
+	env.login("juanperez", "incorrecta")
+	if err := env.login("juanperez", "password123"); !errors.As(err, &blocked) || blocked.RetryAfter != 2*time.Second {
+		t.Errorf("Expected 2s delay after the fourth failure, got %v", err)
+	}
+}
+
+// TestLogin_LocksAccountAndPublishesEvent prueba el bloqueo tras MaxFailures fallos y el evento user.locked
+func TestLogin_LocksAccountAndPublishesEvent(t *testing.T) {
+	env := newLoginGuardTestEnv()
+
+	for i := 0; i < 5; i++ {
+		env.now = env.now.Add(time.Minute) // Esperar lo necesario entre intentos
+		env.login("juanperez", "incorrecta")
+	}
+
+	// Durante el bloqueo ni la contraseña correcta entra
+	env.now = env.now.Add(time.Minute)
+	err := env.login("juanperez", "password123")
+	var blocked *LoginBlockedError
+	if !errors.As(err, &blocked) || !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("Expected ErrAccountLocked, got %v", err)
+	}
+	if blocked.RetryAfter != 14*time.Minute {
+		t.Errorf("Expected 14m until unlock, got %s", blocked.RetryAfter)
+	}
+
+	if len(env.publisher.events) != 1 || env.publisher.events[0] != "locked:1" {
+		t.Fatalf("Expected a single user.locked event, got %v", env.publisher.events)
+	}
+	if env.publisher.data[0]["failures"] != 5 || env.publisher.data[0]["username"] != "juanperez" {
+		t.Errorf("Unexpected user.locked data: %v", env.publisher.data[0])
+	}
+
+	// Vencido el bloqueo, la contraseña correcta entra y resetea el contador
+	env.now = env.now.Add(15 * time.Minute)
+	if err := env.login("juanperez", "password123"); err != nil {
+		t.Fatalf("Expected login after lockout expired, got %v", err)
+	}
+	if attempt, _ := env.attempts.GetLoginAttempt(context.Background(), domain.LoginAttemptAccount, "1"); attempt.Failures != 0 {
+		t.Errorf("Expected account failures reset after success, got %d", attempt.Failures)
+	}
+}
+
+// TestLogin_BlocksIP prueba que una IP que prueba muchas cuentas queda bloqueada
+func TestLogin_BlocksIP(t *testing.T) {
+	env := newLoginGuardTestEnv()
+
+	// Usuarios inexistentes: solo cuenta la IP
+	for i := 0; i < 20; i++ {
+		env.login("nadie", "incorrecta")
+	}
+
+	if err := env.login("juanperez", "password123"); !errors.Is(err, ErrTooManyLoginAttempts) {
+		t.Fatalf("Expected blocked IP, got %v", err)
+	}
+	if len(env.publisher.events) != 0 {
+		t.Errorf("Expected no user events for unknown users, got %v", env.publisher.events)
+	}
+
+	// Otra IP no se ve afectada
+	_, _, err := env.service.Login(context.Background(), domain.UserLogin{UsernameOrEmail: "juanperez", Password: "password123", ClientIP: "198.51.100.1"})
+	if err != nil {
+		t.Errorf("Expected login from another IP, got %v", err)
+	}
+}
+
+// TestUnlockUser prueba que un admin desbloquea la cuenta y se publica user.unlocked
+func TestUnlockUser(t *testing.T) {
+	env := newLoginGuardTestEnv()
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		env.now = env.now.Add(time.Minute)
+		env.login("juanperez", "incorrecta")
+	}
+
+	if err := env.service.UnlockUser(ctx, 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := env.login("juanperez", "password123"); err != nil {
+		t.Errorf("Expected login after unlock, got %v", err)
+	}
+	if last := env.publisher.events[len(env.publisher.events)-1]; last != "unlocked:1" {
+		t.Errorf("Expected user.unlocked event, got %s", last)
+	}
+
+	if err := env.service.UnlockUser(ctx, 99); err == nil {
+		t.Error("Expected error unlocking an unknown user")
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Parámetros de argon2id (recomendación OWASP: m=64MiB, t=3, p=2)
+// Si se cambian, los hashes existentes se re-hashean en el próximo login exitoso
+const (
+	argon2Version     = argon2.Version
+	argon2Memory      = 64 * 1024 // KiB
+	argon2Iterations  = 3
+	argon2Parallelism = 2
+	argon2SaltLength  = 16
+	argon2KeyLength   = 32
+)
+
+// dummyPasswordHash es un hash argon2id con los parámetros actuales que no corresponde a ninguna contraseña
+// El login lo verifica cuando el usuario no existe: la respuesta tarda lo mismo que con un usuario existente
+// y el tiempo no revela qué usernames o emails están registrados
+var dummyPasswordHash = fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+	argon2Version,
+	argon2Memory,
+	argon2Iterations,
+	argon2Parallelism,
+	base64.RawStdEncoding.EncodeToString(make([]byte, argon2SaltLength)),
+	base64.RawStdEncoding.EncodeToString(make([]byte, argon2KeyLength)),
+)
+
+// legacySHA256Regex detecta los hashes SHA-256 hex sin salt del esquema anterior
+var legacySHA256Regex = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+// errInvalidHashFormat indica que el hash almacenado no tiene un formato reconocido
+var errInvalidHashFormat = errors.New("formato de hash de contraseña inválido")
+
+// hashPassword hashea una contraseña con argon2id y un salt aleatorio por usuario
+// Formato PHC (versionado): $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+func (s *UsersServiceImpl) hashPassword(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("error generando salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, argon2Iterations, argon2Memory, argon2Parallelism, argon2KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2Version,
+		argon2Memory,
+		argon2Iterations,
+		argon2Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// verifyPassword compara una contraseña contra el hash almacenado
+// Devuelve needsRehash=true si el hash es del esquema SHA-256 legado
+// o si fue generado con parámetros de argon2id distintos a los actuales
+func (s *UsersServiceImpl) verifyPassword(password, encodedHash string) (match bool, needsRehash bool, err error) {
+	// Hash legado: SHA-256 hex sin salt
+	if legacySHA256Regex.MatchString(encodedHash) {
+		sum := sha256.Sum256([]byte(password))
+		legacy := hex.EncodeToString(sum[:])
+		return subtle.ConstantTimeCompare([]byte(legacy), []byte(encodedHash)) == 1, true, nil
+	}
+
+	if !strings.HasPrefix(encodedHash, "$argon2id$") {
+		return false, false, errInvalidHashFormat
+	}
+
+	// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash> -> ["", "argon2id", "v=19", "m=...", salt, hash]
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 {
+		return false, false, errInvalidHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return false, false, errInvalidHashFormat
+	}
+
+	var memory, iterations uint32
+	var parallelism uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &parallelism); err != nil {
+		return false, false, errInvalidHashFormat
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false, errInvalidHashFormat
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, false, errInvalidHashFormat
+	}
+
+	key := argon2.IDKey([]byte(password), salt, iterations, memory, parallelism, uint32(len(expected)))
+	if subtle.ConstantTimeCompare(key, expected) != 1 {
+		return false, false, nil
+	}
+
+	needsRehash = version != argon2Version ||
+		memory != argon2Memory ||
+		iterations != argon2Iterations ||
+		parallelism != argon2Parallelism ||
+		len(salt) != argon2SaltLength ||
+		len(expected) != argon2KeyLength
+
+	return true, needsRehash, nil
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"users-api/internal/domain"
+	"users-api/internal/repository"
+)
+
+// Errores de la gestión de perfiles y usuarios
+var (
+	ErrInvalidProfile         = errors.New("datos de perfil inválidos")
+	ErrEmailInUse             = errors.New("el email ya está registrado")
+	ErrInvalidCurrentPassword = errors.New("la contraseña actual es incorrecta")
+	ErrUserDisabled           = errors.New("la cuenta está deshabilitada")
+	ErrCannotModifySelf       = errors.New("un admin no puede deshabilitar, degradar ni eliminar su propia cuenta")
+	ErrInvalidListQuery       = errors.New("filtros de búsqueda inválidos")
+)
+
+// UpdateProfile actualiza nombre, apellido, email y sucursal de origen del usuario logueado
+// Cambiar el email lo deja sin verificar y envía un nuevo link de verificación
+func (s *UsersServiceImpl) UpdateProfile(ctx context.Context, userID uint, req domain.UpdateProfileRequest) (domain.UserResponse, error) {
+	current, err := s.repository.GetByID(ctx, userID)
+	if err != nil {
+		return domain.UserResponse{}, err
+	}
+
+	changes := domain.UserChanges{SucursalOrigenID: req.SucursalOrigenID}
+	var changed []string
+
+	if req.Nombre != nil {
+		nombre := strings.TrimSpace(*req.Nombre)
+		if err := validateName("nombre", nombre); err != nil {
+			return domain.UserResponse{}, fmt.Errorf("%w: %s", ErrInvalidProfile, err)
+		}
+		changes.Nombre = &nombre
+		changed = append(changed, "nombre")
+	}
+	if req.Apellido != nil {
+		apellido := strings.TrimSpace(*req.Apellido)
+		if err := validateName("apellido", apellido); err != nil {
+			return domain.UserResponse{}, fmt.Errorf("%w: %s", ErrInvalidProfile, err)
+		}
+		changes.Apellido = &apellido
+		changed = append(changed, "apellido")
+	}
+	if req.Email != nil {
+		email := strings.TrimSpace(*req.Email)
+		if err := validateEmail(email); err != nil {
+			return domain.UserResponse{}, fmt.Errorf("%w: %s", ErrInvalidProfile, err)
+		}
+		if !strings.EqualFold(email, current.Email) {
+			changes.Email = &email
+			changes.UnverifyEmail = true
+			changed = append(changed, "email")
+		}
+	}
+	if req.SucursalOrigenID != nil {
+		changed = append(changed, "sucursal_origen_id")
+	}
+
+	if len(changed) == 0 {
+		return current.ToResponse(), nil
+	}
+
+	updated, err := s.repository.ApplyChanges(ctx, userID, changes)
+	if err != nil {
+		if errors.Is(err, repository.ErrEmailAlreadyExists) {
+			return domain.UserResponse{}, ErrEmailInUse
+		}
+		return domain.UserResponse{}, err
+	}
+
+	// El nuevo email tiene que confirmarse: si el envío falla se puede pedir otro link
+	if changes.UnverifyEmail {
+		if err := s.sendEmailVerification(ctx, updated); err != nil {
+			log.Printf("⚠️  Error enviando verificación del nuevo email al usuario %d: %v", userID, err)
+		}
+	}
+
+	s.publishUserUpdated(updated, changed)
+	return updated.ToResponse(), nil
+}
+
+// ChangePassword cambia la contraseña del usuario logueado verificando la actual
+// Cierra todas las sesiones y devuelve un par de tokens nuevo para la sesión actual
+func (s *UsersServiceImpl) ChangePassword(ctx context.Context, userID uint, req domain.ChangePasswordRequest) (domain.TokenPair, error) {
+	user, err := s.repository.GetByID(ctx, userID)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	match, _, err := s.verifyPassword(req.CurrentPassword, user.Password)
+	if err != nil || !match {
+		return domain.TokenPair{}, ErrInvalidCurrentPassword
+	}
+
+	if err := validatePassword(req.NewPassword); err != nil {
+		return domain.TokenPair{}, fmt.Errorf("%w: %s", ErrInvalidPassword, err)
+	}
+
+	hashedPassword, err := s.hashPassword(req.NewPassword)
+	if err != nil {
+		return domain.TokenPair{}, fmt.Errorf("error hashing password: %w", err)
+	}
+
+	if err := s.repository.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	// Las demás sesiones (posiblemente de quien conocía la contraseña anterior) se cierran
+	if err := s.tokensRepo.RevokeUserSessions(ctx, userID); err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	tokens, err := s.issueTokens(ctx, user, "")
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	log.Printf("🔐 Usuario %d cambió su contraseña, sesiones anteriores revocadas", userID)
+	s.publishUserUpdated(user, []string{"password"})
+	return tokens, nil
+}
+
+// DisableUser deshabilita una cuenta (solo admins): no puede loguearse y se cierran sus sesiones
+func (s *UsersServiceImpl) DisableUser(ctx context.Context, adminID, userID uint) error {
+	if adminID == userID {
+		return ErrCannotModifySelf
+	}
+
+	if err := s.repository.SetDisabled(ctx, userID, true); err != nil {
+		return err
+	}
+
+	if err := s.tokensRepo.RevokeUserSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	log.Printf("⛔ Usuario %d deshabilitado por el admin %d", userID, adminID)
+	s.publishUserEvent("disabled", userID, map[string]interface{}{
+		"disabled_at": time.Now(),
+		"disabled_by": adminID,
+	})
+	return nil
+}
+
+// RestoreUser vuelve a habilitar una cuenta deshabilitada (solo admins)
+func (s *UsersServiceImpl) RestoreUser(ctx context.Context, userID uint) error {
+	if err := s.repository.SetDisabled(ctx, userID, false); err != nil {
+		return err
+	}
+
+	user, err := s.repository.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("♻️  Usuario %d restaurado", userID)
+	s.publishUserUpdated(user, []string{"disabled"})
+	return nil
+}
+
+// SetAdmin promueve (isAdmin = true) o degrada a un usuario (solo admins)
+// Al degradar se cierran sus sesiones: sus tokens tienen el rol owner
+func (s *UsersServiceImpl) SetAdmin(ctx context.Context, adminID, userID uint, isAdmin bool) (domain.UserResponse, error) {
+	if adminID == userID && !isAdmin {
+		return domain.UserResponse{}, ErrCannotModifySelf
+	}
+
+	updated, err := s.repository.ApplyChanges(ctx, userID, domain.UserChanges{IsAdmin: &isAdmin})
+	if err != nil {
+		return domain.UserResponse{}, err
+	}
+
+	if !isAdmin {
+		if err := s.tokensRepo.RevokeUserSessions(ctx, userID); err != nil {
+			return domain.UserResponse{}, err
+		}
+	}
+
+	log.Printf("👑 Usuario %d: is_admin=%t (admin %d)", userID, isAdmin, adminID)
+	s.publishUserUpdated(updated, []string{"is_admin"})
+	return updated.ToResponse(), nil
+}
+
+// DeleteUser elimina una cuenta de forma definitiva (solo admins)
+// Antes se cierran sus sesiones: los access tokens vigentes quedan en la lista de revocación
+func (s *UsersServiceImpl) DeleteUser(ctx context.Context, adminID, userID uint) error {
+	if adminID == userID {
+		return ErrCannotModifySelf
+	}
+
+	if err := s.tokensRepo.RevokeUserSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.repository.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	log.Printf("🗑️  Usuario %d eliminado por el admin %d", userID, adminID)
+	s.publishUserEvent("deleted", userID, map[string]interface{}{
+		"deleted_at": time.Now(),
+		"deleted_by": adminID,
+	})
+	return nil
+}
+
+// publishUserUpdated publica user.updated con todos los campos públicos y cuáles cambiaron
+func (s *UsersServiceImpl) publishUserUpdated(user domain.User, changed []string) {
+	s.publishUserEvent("updated", user.ID, map[string]interface{}{
+		"nombre":             user.Nombre,
+		"apellido":           user.Apellido,
+		"username":           user.Username,
+		"email":              user.Email,
+		"is_admin":           user.IsAdmin,
+		"sucursal_origen_id": user.SucursalOrigenID,
+		"email_verified":     user.EmailVerified(),
+		"disabled":           user.Disabled(),
+		"changed":            changed,
+	})
+}
+
+// publishUserEvent publica un evento de usuario
+// Un error solo se loguea: el cambio ya está guardado
+func (s *UsersServiceImpl) publishUserEvent(action string, userID uint, data map[string]interface{}) {
+	if err := s.publisher.PublishUserEvent(action, fmt.Sprintf("%d", userID), data); err != nil {
+		log.Printf("⚠️  Error publicando evento user.%s: %v", action, err)
+	}
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"users-api/internal/clients"
+	"users-api/internal/domain"
+	"users-api/internal/repository"
+)
+
+// profileTestEnv agrupa el servicio con un repositorio de usuarios en memoria
+type profileTestEnv struct {
+	service    *UsersServiceImpl
+	users      map[uint]domain.User
+	tokensRepo *MockTokensRepository
+	mailer     *clients.MemoryMailer
+	publisher  *MockEventPublisher
+}
+
+func newProfileTestEnv() *profileTestEnv {
+	verifiedAt := time.Now().Add(-24 * time.Hour)
+	env := &profileTestEnv{
+		users: map[uint]domain.User{
+			1: {ID: 1, Nombre: "Admin", Apellido: "Gym", Username: "admin", Email: "admin@example.com", IsAdmin: true,
+				Password: "ef92b778bafe771e89245b89ecbc08a44a4e166c06659911881f383d4473e94f"}, // SHA-256 legado de "password123"
+			2: {ID: 2, Nombre: "Juan", Apellido: "Pérez", Username: "juanperez", Email: "juan@example.com", EmailVerifiedAt: &verifiedAt,
+				Password: "ef92b778bafe771e89245b89ecbc08a44a4e166c06659911881f383d4473e94f"},
+		},
+		tokensRepo: &MockTokensRepository{},
+		mailer:     clients.NewMemoryMailer(),
+		publisher:  &MockEventPublisher{},
+	}
+
+	usersRepo := &MockUsersRepository{
+		GetByIDFunc: func(ctx context.Context, id uint) (domain.User, error) {
+			if user, ok := env.users[id]; ok {
+				return user, nil
+			}
+			return domain.User{}, errors.New("user not found")
+		},
+		GetByUsernameOrEmailFunc: func(ctx context.Context, usernameOrEmail string) (domain.User, error) {
+			for _, user := range env.users {
+				if user.Username == usernameOrEmail || user.Email == usernameOrEmail {
+					return user, nil
+				}
+			}
+			return domain.User{}, errors.New("user not found")
+		},
+		ApplyChangesFunc: func(ctx context.Context, id uint, changes domain.UserChanges) (domain.User, error) {
+			user, ok := env.users[id]
+			if !ok {
+				return domain.User{}, errors.New("user not found")
+			}
+			if changes.Email != nil {
+				for _, other := range env.users {
+					if other.ID != id && other.Email == *changes.Email {
+						return domain.User{}, repository.ErrEmailAlreadyExists
+					}
+				}
+				user.Email = *changes.Email
+			}
+			if changes.Nombre != nil {
+				user.Nombre = *changes.Nombre
+			}
+			if changes.Apellido != nil {
+				user.Apellido = *changes.Apellido
+			}
+			if changes.SucursalOrigenID != nil {
+				user.SucursalOrigenID = changes.SucursalOrigenID
+			}
+			if changes.IsAdmin != nil {
+				user.IsAdmin = *changes.IsAdmin
+			}
+			if changes.UnverifyEmail {
+				user.EmailVerifiedAt = nil
+			}
+			env.users[id] = user
+			return user, nil
+		},
+		SetDisabledFunc: func(ctx context.Context, id uint, disabled bool) error {
+			user, ok := env.users[id]
+			if !ok {
+				return errors.New("user not found")
+			}
+			user.DisabledAt = nil
+			if disabled {
+				now := time.Now()
+				user.DisabledAt = &now
+			}
+			env.users[id] = user
+			return nil
+		},
+		UpdatePasswordFunc: func(ctx context.Context, id uint, passwordHash string) error {
+			user := env.users[id]
+			user.Password = passwordHash
+			env.users[id] = user
+			return nil
+		},
+		DeleteFunc: func(ctx context.Context, id uint) error {
+			if _, ok := env.users[id]; !ok {
+				return errors.New("user not found")
+			}
+			delete(env.users, id)
+			return nil
+		},
+	}
+
+	env.service = NewUsersService(usersRepo, env.tokensRepo, &MockAccountTokensRepository{}, &MockTwoFactorRepository{}, testSigningKeys, env.mailer, env.publisher, testAccountLinks, newTestLoginGuard(), DefaultTwoFactorPolicy(), 30*time.Minute, 24*time.Hour)
+	return env
+}
+
+func strPtr(s string) *string { return &s }
+
+// TestUpdateProfile_EmailChangeRequiresVerification prueba que cambiar el email lo deja sin verificar
+func TestUpdateProfile_EmailChangeRequiresVerification(t *testing.T) {
+	env := newProfileTestEnv()
+	branch := uint(3)
+
+	user, err := env.service.UpdateProfile(context.Background(), 2, domain.UpdateProfileRequest{
+		Nombre:           strPtr("  Juan Carlos "),
+		Email:            strPtr("juanc@example.com"),
+		SucursalOrigenID: &branch,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if user.Nombre != "Juan Carlos" || user.Email != "juanc@example.com" || *user.SucursalOrigenID != 3 {
+		t.Errorf("Profile not updated: %+v", user)
+	}
+	if user.EmailVerified {
+		t.Error("Expected new email to be unverified")
+	}
+	if _, ok := env.mailer.LastTo("juanc@example.com"); !ok {
+		t.Error("Expected verification email sent to the new address")
+	}
+
+	if len(env.publisher.events) != 1 || env.publisher.events[0] != "updated:2" {
+		t.Fatalf("Expected user.updated event, got %v", env.publisher.events)
+	}
+	changed := env.publisher.data[0]["changed"].([]string)
+	if len(changed) != 3 || changed[0] != "nombre" || changed[1] != "email" {
+		t.Errorf("Unexpected changed fields: %v", changed)
+	}
+}
+
+// TestUpdateProfile_Errors prueba las validaciones y el email duplicado
+func TestUpdateProfile_Errors(t *testing.T) {
+	env := newProfileTestEnv()
+	ctx := context.Background()
+
+	if _, err := env.service.UpdateProfile(ctx, 2, domain.UpdateProfileRequest{Apellido: strPtr(" ")}); !errors.Is(err, ErrInvalidProfile) {
+		t.Errorf("Expected ErrInvalidProfile for empty apellido, got %v", err)
+	}
+	if _, err := env.service.UpdateProfile(ctx, 2, domain.UpdateProfileRequest{Email: strPtr("no-es-un-email")}); !errors.Is(err, ErrInvalidProfile) {
+		t.Errorf("Expected ErrInvalidProfile for invalid email, got %v", err)
+	}
+	if _, err := env.service.UpdateProfile(ctx, 2, domain.UpdateProfileRequest{Email: strPtr("admin@example.com")}); !errors.Is(err, ErrEmailInUse) {
+		t.Errorf("Expected ErrEmailInUse, got %v", err)
+	}
+
+	// Mismo email: no cambia nada ni publica eventos
+	user, err := env.service.UpdateProfile(ctx, 2, domain.UpdateProfileRequest{Email: strPtr("juan@example.com")})
+	if err != nil || !user.EmailVerified {
+		t.Errorf("Expected unchanged verified profile, got %+v (%v)", user, err)
+	}
+	if len(env.publisher.events) != 0 {
+		t.Errorf("Expected no events, got %v", env.publisher.events)
+	}
+}
+
+// TestChangePassword prueba que exige la contraseña actual y cierra las demás sesiones
+func TestChangePassword(t *testing.T) {
+	env := newProfileTestEnv()
+	ctx := context.Background()
+
+	env.tokensRepo.tokens = []domain.RefreshToken{{ID: 1, UserID: 2, FamilyID: "otra-sesion"}}
+
+	_, err := env.service.ChangePassword(ctx, 2, domain.ChangePasswordRequest{CurrentPassword: "incorrecta", NewPassword: "NuevaPass123!"})
+	if !errors.Is(err, ErrInvalidCurrentPassword) {
+		t.Fatalf("Expected ErrInvalidCurrentPassword, got %v", err)
+	}
+
+	_, err = env.service.ChangePassword(ctx, 2, domain.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "debil"})
+	if !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("Expected ErrInvalidPassword, got %v", err)
+	}
+
+	tokens, err := env.service.ChangePassword(ctx, 2, domain.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "NuevaPass123!"})
+	if err != nil || tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Fatalf("Expected new tokens, got %+v (%v)", tokens, err)
+	}
+	if env.tokensRepo.tokens[0].RevokedAt == nil {
+		t.Error("Expected previous session to be revoked")
+	}
+
+	if _, _, err := env.service.Login(ctx, domain.UserLogin{UsernameOrEmail: "juanperez", Password: "NuevaPass123!"}); err != nil {
+		t.Errorf("Expected login with the new password, got %v", err)
+	}
+}
+
+// TestDisableAndRestoreUser prueba que una cuenta deshabilitada no puede loguearse ni refrescar tokens
+func TestDisableAndRestoreUser(t *testing.T) {
+	env := newProfileTestEnv()
+	ctx := context.Background()
+
+	_, tokens, err := env.service.Login(ctx, domain.UserLogin{UsernameOrEmail: "juanperez", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Expected login, got %v", err)
+	}
+
+	if err := env.service.DisableUser(ctx, 1, 1); !errors.Is(err, ErrCannotModifySelf) {
+		t.Errorf("Expected ErrCannotModifySelf, got %v", err)
+	}
+	if err := env.service.DisableUser(ctx, 1, 2); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, _, err := env.service.Login(ctx, domain.UserLogin{UsernameOrEmail: "juanperez", Password: "password123"}); !errors.Is(err, ErrUserDisabled) {
+		t.Errorf("Expected ErrUserDisabled, got %v", err)
+	}
+	if _, err := env.service.RefreshTokens(ctx, tokens.RefreshToken); err == nil {
+		t.Error("Expected refresh to fail for a disabled user")
+	}
+
+	if err := env.service.RestoreUser(ctx, 2); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, _, err := env.service.Login(ctx, domain.UserLogin{UsernameOrEmail: "juanperez", Password: "password123"}); err != nil {
+		t.Errorf("Expected login after restore, got %v", err)
+	}
+
+	if len(env.publisher.events) != 2 || env.publisher.events[0] != "disabled:2" || env.publisher.events[1] != "updated:2" {
+		t.Errorf("Expected user.disabled and user.updated events, got %v", env.publisher.events)
+	}
+}
+
+// TestSetAdmin prueba promover y degradar usuarios
+func TestSetAdmin(t *testing.T) {
+	env := newProfileTestEnv()
+	ctx := context.Background()
+
+	if _, err := env.service.SetAdmin(ctx, 1, 1, false); !errors.Is(err, ErrCannotModifySelf) {
+		t.Errorf("Expected ErrCannotModifySelf, got %v", err)
+	}
+
+	user, err := env.service.SetAdmin(ctx, 1, 2, true)
+	if err != nil || !user.IsAdmin {
+		t.Fatalf("Expected promoted user, got %+v (%v)", user, err)
+	}
+
+	// Al degradar se cierran las sesiones (sus tokens tienen el rol admin)
+	env.tokensRepo.tokens = []domain.RefreshToken{{ID: 1, UserID: 2, FamilyID: "sesion-admin"}}
+	user, err = env.service.SetAdmin(ctx, 1, 2, false)
+	if err != nil || user.IsAdmin {
+		t.Fatalf("Expected demoted user, got %+v (%v)", user, err)
+	}
+	if env.tokensRepo.tokens[0].RevokedAt == nil {
+		t.Error("Expected sessions revoked on demotion")
+	}
+
+	if len(env.publisher.events) != 2 || env.publisher.data[1]["is_admin"] != false {
+		t.Errorf("Expected two user.updated events, got %v", env.publisher.events)
+	}
+}
+
+// TestDeleteUser prueba la eliminación definitiva, el cierre de sesiones y el evento user.deleted
+func TestDeleteUser(t *testing.T) {
+	env := newProfileTestEnv()
+	ctx := context.Background()
+
+	env.tokensRepo.tokens = []domain.RefreshToken{{ID: 1, UserID: 2, FamilyID: "sesion-borrada"}}
+	if err := env.service.DeleteUser(ctx, 1, 2); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := env.users[2]; ok {
+		t.Error("Expected user to be deleted")
+	}
+	if env.tokensRepo.tokens[0].RevokedAt == nil {
+		t.Error("Expected sessions revoked on delete")
+	}
+	if len(env.publisher.events) != 1 || env.publisher.events[0] != "deleted:2" {
+		t.Errorf("Expected user.deleted event, got %v", env.publisher.events)
+	}
+
+	if err := env.service.DeleteUser(ctx, 1, 99); err == nil {
+		t.Error("Expected error deleting an unknown user")
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"users-api/internal/domain"
+
+	"github.com/yourusername/gym-management/shared/auth"
+)
+
+// ErrInvalidRole indica una asignación de rol inválida
+var ErrInvalidRole = errors.New("rol inválido")
+
+// GetRoles devuelve los roles efectivos y las asignaciones de staff del usuario
+func (s *UsersServiceImpl) GetRoles(ctx context.Context, userID uint) (domain.UserRolesResponse, error) {
+	user, err := s.repository.GetByID(ctx, userID)
+	if err != nil {
+		return domain.UserRolesResponse{}, err
+	}
+
+	assignments, err := s.repository.GetRoles(ctx, userID)
+	if err != nil {
+		return domain.UserRolesResponse{}, err
+	}
+
+	roles, _ := tokenRoles(user, assignments)
+	return domain.UserRolesResponse{UserID: userID, Roles: roles, Assignments: assignments}, nil
+}
+
+// SetRoles reemplaza los roles de staff del usuario (solo owners)
+// owner se asigna con SetAdmin; acá solo se aceptan instructor, receptionist y branch_manager
+// Se cierran las sesiones del usuario: sus tokens tienen los roles anteriores
+func (s *UsersServiceImpl) SetRoles(ctx context.Context, adminID, userID uint, req domain.SetRolesRequest) (domain.UserRolesResponse, error) {
+	assignments := make([]domain.RoleAssignment, 0, len(req.Roles))
+	seen := make(map[domain.RoleAssignment]bool)
+	for _, assignment := range req.Roles {
+		if !auth.IsStaffRole(assignment.Role) {
+			return domain.UserRolesResponse{}, fmt.Errorf("%w: %q no es un rol de staff (instructor, receptionist, branch_manager)", ErrInvalidRole, assignment.Role)
+		}
+		if assignment.SucursalID == 0 {
+			return domain.UserRolesResponse{}, fmt.Errorf("%w: sucursal_id es requerido", ErrInvalidRole)
+		}
+		if !seen[assignment] {
+			seen[assignment] = true
+			assignments = append(assignments, assignment)
+		}
+	}
+
+	user, err := s.repository.GetByID(ctx, userID)
+	if err != nil {
+		return domain.UserRolesResponse{}, err
+	}
+
+	if err := s.repository.SetRoles(ctx, userID, assignments); err != nil {
+		return domain.UserRolesResponse{}, err
+	}
+
+	if err := s.tokensRepo.RevokeUserSessions(ctx, userID); err != nil {
+		return domain.UserRolesResponse{}, err
+	}
+
+	log.Printf("🎭 Usuario %d: %d roles de staff asignados (owner %d)", userID, len(assignments), adminID)
+	s.publishUserUpdated(user, []string{"roles"})
+
+	roles, _ := tokenRoles(user, assignments)
+	return domain.UserRolesResponse{UserID: userID, Roles: roles, Assignments: assignments}, nil
+}
+
+// tokenRoles arma los claims roles y role_branches del usuario
+// Todos son member; is_admin equivale a owner; los roles de staff se agrupan por sucursal
+func tokenRoles(user domain.User, assignments []domain.RoleAssignment) ([]string, map[string][]uint) {
+	roles := []string{auth.RoleMember}
+	if user.IsAdmin {
+		roles = append(roles, auth.RoleOwner)
+	}
+
+	var roleBranches map[string][]uint
+	for _, assignment := range assignments {
+		if roleBranches == nil {
+			roleBranches = make(map[string][]uint)
+		}
+		if _, ok := roleBranches[assignment.Role]; !ok {
+			roles = append(roles, assignment.Role)
+		}
+		roleBranches[assignment.Role] = append(roleBranches[assignment.Role], assignment.SucursalID)
+	}
+
+	return roles, roleBranches
+}
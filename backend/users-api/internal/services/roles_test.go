@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"users-api/internal/domain"
+
+	"github.com/yourusername/gym-management/shared/auth"
+)
+
+// newRolesTestEnv agrega al entorno de perfiles un almacenamiento de roles en memoria
+func newRolesTestEnv() (*profileTestEnv, map[uint][]domain.RoleAssignment) {
+	env := newProfileTestEnv()
+	stored := map[uint][]domain.RoleAssignment{}
+
+	repo := env.service.repository.(*MockUsersRepository)
+	repo.GetRolesFunc = func(ctx context.Context, id uint) ([]domain.RoleAssignment, error) {
+		return stored[id], nil
+	}
+	repo.SetRolesFunc = func(ctx context.Context, id uint, roles []domain.RoleAssignment) error {
+		stored[id] = roles
+		return nil
+	}
+
+	return env, stored
+}
+
+// TestSetRoles_TokenCarriesBranchScopedRoles prueba que los roles de staff llegan al token por sucursal
+func TestSetRoles_TokenCarriesBranchScopedRoles(t *testing.T) {
+	env, stored := newRolesTestEnv()
+	ctx := context.Background()
+
+	env.tokensRepo.tokens = []domain.RefreshToken{{ID: 1, UserID: 2, FamilyID: "sesion-previa"}}
+
+	roles, err := env.service.SetRoles(ctx, 1, 2, domain.SetRolesRequest{Roles: []domain.RoleAssignment{
+		{Role: auth.RoleReceptionist, SucursalID: 2},
+		{Role: auth.RoleReceptionist, SucursalID: 2},
+		{Role: auth.RoleInstructor, SucursalID: 3},
+	}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(stored[2]) != 2 || len(roles.Assignments) != 2 {
+		t.Errorf("Expected duplicated assignment to be dropped, got %v", stored[2])
+	}
+	if len(roles.Roles) != 3 || roles.Roles[0] != auth.RoleMember {
+		t.Errorf("Expected member, receptionist and instructor, got %v", roles.Roles)
+	}
+	if env.tokensRepo.tokens[0].RevokedAt == nil {
+		t.Error("Expected sessions revoked after changing roles")
+	}
+
+	_, tokens, err := env.service.Login(ctx, domain.UserLogin{UsernameOrEmail: "juanperez", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Expected login, got %v", err)
+	}
+	claims, err := env.service.ValidateToken(tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("Expected valid token, got %v", err)
+	}
+
+	if !claims.CanInBranch(auth.PermPaymentsApproveCash, 2) || claims.CanInBranch(auth.PermPaymentsApproveCash, 3) {
+		t.Errorf("Expected cash approval only at branch 2, got %v", claims.RoleBranches)
+	}
+	if !claims.CanInBranch(auth.PermOwnRostersRead, 3) || claims.IsAdmin() {
+		t.Errorf("Expected instructor at branch 3 without owner, got %v", claims.Roles)
+	}
+}
+
+// TestSetRoles_Invalid prueba que solo se asignan roles de staff con sucursal
+func TestSetRoles_Invalid(t *testing.T) {
+	env, stored := newRolesTestEnv()
+	ctx := context.Background()
+
+	invalid := [][]domain.RoleAssignment{
+		{{Role: auth.RoleOwner, SucursalID: 1}},
+		{{Role: "superuser", SucursalID: 1}},
+		{{Role: auth.RoleBranchManager}},
+	}
+	for _, assignments := range invalid {
+		if _, err := env.service.SetRoles(ctx, 1, 2, domain.SetRolesRequest{Roles: assignments}); !errors.Is(err, ErrInvalidRole) {
+			t.Errorf("Expected ErrInvalidRole for %v, got %v", assignments, err)
+		}
+	}
+	if len(stored) != 0 {
+		t.Errorf("Expected no roles stored, got %v", stored)
+	}
+
+	if _, err := env.service.SetRoles(ctx, 1, 99, domain.SetRolesRequest{}); err == nil {
+		t.Error("Expected error for an unknown user")
+	}
+}
+
+// TestGetRoles_OwnerFromIsAdmin prueba que is_admin se expone como owner
+func TestGetRoles_OwnerFromIsAdmin(t *testing.T) {
+	env, _ := newRolesTestEnv()
+
+	roles, err := env.service.GetRoles(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(roles.Roles) != 2 || roles.Roles[1] != auth.RoleOwner || len(roles.Assignments) != 0 {
+		t.Errorf("Expected member and owner, got %+v", roles)
+	}
+}
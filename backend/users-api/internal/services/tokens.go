@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"users-api/internal/domain"
+)
+
+// Errores del flujo de refresh tokens
+var (
+	errInvalidRefreshToken = errors.New("refresh token inválido")
+	errExpiredRefreshToken = errors.New("refresh token expirado")
+	errReusedRefreshToken  = errors.New("refresh token reutilizado: sesión revocada")
+)
+
+// RefreshTokens rota un refresh token: lo marca como usado y emite un nuevo par de tokens
+// Si el token ya había sido usado se asume robo y se revoca toda su familia
+func (s *UsersServiceImpl) RefreshTokens(ctx context.Context, refreshToken string) (domain.TokenPair, error) {
+	stored, err := s.tokensRepo.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		return domain.TokenPair{}, errInvalidRefreshToken
+	}
+
+	if stored.RevokedAt != nil {
+		return domain.TokenPair{}, errInvalidRefreshToken
+	}
+
+	// Reutilización: el token ya fue rotado antes
+	if stored.UsedAt != nil {
+		s.revokeFamily(ctx, stored)
+		return domain.TokenPair{}, errReusedRefreshToken
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return domain.TokenPair{}, errExpiredRefreshToken
+	}
+
+	// Marcar como usado de forma atómica: si otra request lo rotó en paralelo, también es reutilización
+	rotated, err := s.tokensRepo.MarkRefreshTokenUsed(ctx, stored.ID)
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
+	if !rotated {
+		s.revokeFamily(ctx, stored)
+		return domain.TokenPair{}, errReusedRefreshToken
+	}
+
+	// Volver a leer el usuario para que los claims reflejen su estado actual
+	user, err := s.repository.GetByID(ctx, stored.UserID)
+	if err != nil || user.Disabled() {
+		return domain.TokenPair{}, errInvalidRefreshToken
+	}
+
+	return s.issueTokens(ctx, user, stored.FamilyID)
+}
+
+// Logout revoca el access token actual (por jti) y la familia de refresh tokens de la sesión
+// Si no se envía el refresh token se usa el que fue emitido junto con el access token
+func (s *UsersServiceImpl) Logout(ctx context.Context, userID uint, jti string, refreshToken string) error {
+	if jti != "" {
+		// El access token vive como máximo accessTTL desde ahora
+		if err := s.tokensRepo.RevokeAccessToken(ctx, domain.RevokedToken{
+			JTI:       jti,
+			UserID:    userID,
+			ExpiresAt: time.Now().Add(s.accessTTL),
+		}); err != nil {
+			return err
+		}
+	}
+
+	var stored domain.RefreshToken
+	var err error
+	if refreshToken != "" {
+		stored, err = s.tokensRepo.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	} else if jti != "" {
+		stored, err = s.tokensRepo.GetRefreshTokenByAccessJTI(ctx, jti)
+	} else {
+		return nil
+	}
+
+	// Sin refresh token asociado (o de otro usuario) no hay familia para revocar
+	if err != nil || stored.UserID != userID {
+		return nil
+	}
+
+	return s.tokensRepo.RevokeFamily(ctx, stored.FamilyID)
+}
+
+// IsTokenRevoked indica si un access token (por jti) fue revocado
+func (s *UsersServiceImpl) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	if jti == "" {
+		return false, nil
+	}
+	return s.tokensRepo.IsAccessTokenRevoked(ctx, jti)
+}
+
+// ListRevokedTokens devuelve los access tokens revocados vigentes
+// Lo consumen los demás microservicios para mantener su lista de revocación local
+func (s *UsersServiceImpl) ListRevokedTokens(ctx context.Context) ([]domain.RevokedToken, error) {
+	return s.tokensRepo.ListRevokedTokens(ctx)
+}
+
+// PurgeExpiredTokens elimina refresh tokens, revocaciones y tokens de cuenta expirados
+func (s *UsersServiceImpl) PurgeExpiredTokens(ctx context.Context) error {
+	if err := s.tokensRepo.DeleteExpired(ctx); err != nil {
+		return err
+	}
+	return s.accountTokensRepo.DeleteExpired(ctx)
+}
+
+// issueTokens emite un access token (con jti) y un refresh token persistido en la familia indicada
+// familyID vacío inicia una nueva familia (login/registro)
+func (s *UsersServiceImpl) issueTokens(ctx context.Context, user domain.User, familyID string) (domain.TokenPair, error) {
+	jti, err := randomHex(16)
+	if err != nil {
+		return domain.TokenPair{}, fmt.Errorf("error generating token id: %w", err)
+	}
+
+	if familyID == "" {
+		familyID, err = randomHex(16)
+		if err != nil {
+			return domain.TokenPair{}, fmt.Errorf("error generating token family: %w", err)
+		}
+	}
+
+	now := time.Now()
+	accessToken, err := s.generateToken(ctx, user, jti, now)
+	if err != nil {
+		return domain.TokenPair{}, fmt.Errorf("error generating token: %w", err)
+	}
+
+	refreshBytes := make([]byte, 32)
+	if _, err := rand.Read(refreshBytes); err != nil {
+		return domain.TokenPair{}, fmt.Errorf("error generating refresh token: %w", err)
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(refreshBytes)
+
+	if err := s.tokensRepo.CreateRefreshToken(ctx, domain.RefreshToken{
+		UserID:          user.ID,
+		TokenHash:       hashToken(refreshToken),
+		FamilyID:        familyID,
+		AccessJTI:       jti,
+		AccessExpiresAt: now.Add(s.accessTTL),
+		ExpiresAt:       now.Add(s.refreshTTL),
+	}); err != nil {
+		return domain.TokenPair{}, err
+	}
+
+	return domain.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.accessTTL.Seconds()),
+	}, nil
+}
+
+// revokeFamily revoca la familia de un refresh token reutilizado
+// Un error acá solo se loguea: la request igualmente se rechaza
+func (s *UsersServiceImpl) revokeFamily(ctx context.Context, token domain.RefreshToken) {
+	log.Printf("🚨 Reutilización de refresh token detectada (usuario %d, familia %s): revocando sesión", token.UserID, token.FamilyID)
+
+	if err := s.tokensRepo.RevokeFamily(ctx, token.FamilyID); err != nil {
+		log.Printf("⚠️  Error revocando familia de tokens %s: %v", token.FamilyID, err)
+	}
+}
+
+// hashToken calcula el hash SHA-256 (hex) con el que se persiste un refresh token
+// Al ser un valor aleatorio de 256 bits no necesita salt ni un KDF lento
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomHex genera n bytes aleatorios codificados en hex
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"users-api/internal/domain"
+	"users-api/internal/repository"
+)
+
+// MockTokensRepository es un mock del repositorio de tokens para testing
+// Si no se definen funciones, guarda los refresh tokens en memoria
+type MockTokensRepository struct {
+	CreateRefreshTokenFunc         func(ctx context.Context, token domain.RefreshToken) error
+	GetRefreshTokenByHashFunc      func(ctx context.Context, tokenHash string) (domain.RefreshToken, error)
+	GetRefreshTokenByAccessJTIFunc func(ctx context.Context, jti string) (domain.RefreshToken, error)
+	MarkRefreshTokenUsedFunc       func(ctx context.Context, id uint) (bool, error)
+	RevokeFamilyFunc               func(ctx context.Context, familyID string) error
+	RevokeUserSessionsFunc         func(ctx context.Context, userID uint) error
+	RevokeAccessTokenFunc          func(ctx context.Context, token domain.RevokedToken) error
+	IsAccessTokenRevokedFunc       func(ctx context.Context, jti string) (bool, error)
+	ListRevokedTokensFunc          func(ctx context.Context) ([]domain.RevokedToken, error)
+	DeleteExpiredFunc              func(ctx context.Context) error
+
+	tokens []domain.RefreshToken
+}
+
+func (m *MockTokensRepository) CreateRefreshToken(ctx context.Context, token domain.RefreshToken) error {
+	if m.CreateRefreshTokenFunc != nil {
+		return m.CreateRefreshTokenFunc(ctx, token)
+	}
+	token.ID = uint(len(m.tokens) + 1)
+	m.tokens = append(m.tokens, token)
+	return nil
+}
+
+func (m *MockTokensRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (domain.RefreshToken, error) {
+	if m.GetRefreshTokenByHashFunc != nil {
+		return m.GetRefreshTokenByHashFunc(ctx, tokenHash)
+	}
+	for _, t := range m.tokens {
+		if t.TokenHash == tokenHash {
+			return t, nil
+		}
+	}
+	return domain.RefreshToken{}, errors.New("refresh token not found")
+}
+
+func (m *MockTokensRepository) GetRefreshTokenByAccessJTI(ctx context.Context, jti string) (domain.RefreshToken, error) {
+	if m.GetRefreshTokenByAccessJTIFunc != nil {
+		return m.GetRefreshTokenByAccessJTIFunc(ctx, jti)
+	}
+	for _, t := range m.tokens {
+		if t.AccessJTI == jti {
+			return t, nil
+		}
+	}
+	return domain.RefreshToken{}, errors.New("refresh token not found")
+}
+
+func (m *MockTokensRepository) MarkRefreshTokenUsed(ctx context.Context, id uint) (bool, error) {
+	if m.MarkRefreshTokenUsedFunc != nil {
+		return m.MarkRefreshTokenUsedFunc(ctx, id)
+	}
+	for i, t := range m.tokens {
+		if t.ID == id && t.UsedAt == nil && t.RevokedAt == nil {
+			now := time.Now()
+			m.tokens[i].UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockTokensRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	if m.RevokeFamilyFunc != nil {
+		return m.RevokeFamilyFunc(ctx, familyID)
+	}
+	now := time.Now()
+	for i, t := range m.tokens {
+		if t.FamilyID == familyID && t.RevokedAt == nil {
+			m.tokens[i].RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *MockTokensRepository) RevokeUserSessions(ctx context.Context, userID uint) error {
+	if m.RevokeUserSessionsFunc != nil {
+		return m.RevokeUserSessionsFunc(ctx, userID)
+	}
+	now := time.Now()
+	for i, t := range m.tokens {
+		if t.UserID == userID && t.RevokedAt == nil {
+			m.tokens[i].RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *MockTokensRepository) RevokeAccessToken(ctx context.Context, token domain.RevokedToken) error {
+	if m.RevokeAccessTokenFunc != nil {
+		return m.RevokeAccessTokenFunc(ctx, token)
+	}
+	return nil
+}
+
+func (m *MockTokensRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	if m.IsAccessTokenRevokedFunc != nil {
+		return m.IsAccessTokenRevokedFunc(ctx, jti)
+	}
+	return false, nil
+}
+
+func (m *MockTokensRepository) ListRevokedTokens(ctx context.Context) ([]domain.RevokedToken, error) {
+	if m.ListRevokedTokensFunc != nil {
+		return m.ListRevokedTokensFunc(ctx)
+	}
+	return []domain.RevokedToken{}, nil
+}
+
+func (m *MockTokensRepository) DeleteExpired(ctx context.Context) error {
+	if m.DeleteExpiredFunc != nil {
+		return m.DeleteExpiredFunc(ctx)
+	}
+	return nil
+}
+
+// Verificar que MockTokensRepository implementa la interfaz
+var _ repository.TokensRepository = (*MockTokensRepository)(nil)
+
+// loginForTest loguea un usuario de prueba y devuelve sus tokens
+func loginForTest(t *testing.T, service *UsersServiceImpl) domain.TokenPair {
+	t.Helper()
+
+	_, tokens, err := service.Login(context.Background(), domain.UserLogin{UsernameOrEmail: "juanperez", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Expected no error on login, got: %v", err)
+	}
+	return tokens
+}
+
+// newTokensTestService crea un servicio con un usuario logueable y el repo de tokens en memoria
+func newTokensTestService(tokensRepo *MockTokensRepository) *UsersServiceImpl {
+	user := domain.User{
+		ID:       1,
+		Username: "juanperez",
+		// Hash SHA-256 legado de "password123"
+		Password: "ef92b778bafe771e89245b89ecbc08a44a4e166c06659911881f383d4473e94f",
+	}
+
+	mockRepo := &MockUsersRepository{
+		GetByUsernameOrEmailFunc: func(ctx context.Context, usernameOrEmail string) (domain.User, error) {
+			return user, nil
+		},
+		GetByIDFunc: func(ctx context.Context, id uint) (domain.User, error) {
+			return user, nil
+		},
+	}
+
+	return newTestUsersService(mockRepo, tokensRepo)
+}
+
+// TestLogin_IssuesRefreshToken prueba que el login persiste el refresh token hasheado
+func TestLogin_IssuesRefreshToken(t *testing.T) {
+	tokensRepo := &MockTokensRepository{}
+	service := newTokensTestService(tokensRepo)
+
+	tokens := loginForTest(t, service)
+
+	if len(tokensRepo.tokens) != 1 {
+		t.Fatalf("Expected 1 stored refresh token, got: %d", len(tokensRepo.tokens))
+	}
+
+	stored := tokensRepo.tokens[0]
+	if stored.TokenHash == tokens.RefreshToken || stored.TokenHash != hashToken(tokens.RefreshToken) {
+		t.Error("Expected refresh token to be stored only as its SHA-256 hash")
+	}
+
+	claims, err := service.ValidateToken(tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("Expected valid access token, got error: %v", err)
+	}
+	if claims.ID != stored.AccessJTI {
+		t.Errorf("Expected jti %s, got: %v", stored.AccessJTI, claims.ID)
+	}
+	if tokens.ExpiresIn != int64((30 * time.Minute).Seconds()) {
+		t.Errorf("Expected expires_in 1800, got: %d", tokens.ExpiresIn)
+	}
+}
+
+// TestRefreshTokens_Rotates prueba que cada refresh emite un token nuevo de la misma familia
+func TestRefreshTokens_Rotates(t *testing.T) {
+	tokensRepo := &MockTokensRepository{}
+	service := newTokensTestService(tokensRepo)
+
+	first := loginForTest(t, service)
+
+	second, err := service.RefreshTokens(context.Background(), first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if second.RefreshToken == first.RefreshToken || second.AccessToken == first.AccessToken {
+		t.Error("Expected new access and refresh tokens after rotation")
+	}
+
+	if len(tokensRepo.tokens) != 2 {
+		t.Fatalf("Expected 2 stored refresh tokens, got: %d", len(tokensRepo.tokens))
+	}
+	if tokensRepo.tokens[0].UsedAt == nil {
+		t.Error("Expected rotated refresh token to be marked as used")
+	}
+	if tokensRepo.tokens[0].FamilyID != tokensRepo.tokens[1].FamilyID {
+		t.Error("Expected rotated token to keep the same family")
+	}
+}
+
+// TestRefreshTokens_ReuseRevokesFamily prueba la detección de reutilización
+func TestRefreshTokens_ReuseRevokesFamily(t *testing.T) {
+	tokensRepo := &MockTokensRepository{}
+	service := newTokensTestService(tokensRepo)
+
+	first := loginForTest(t, service)
+	second, err := service.RefreshTokens(context.Background(), first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Un atacante reutiliza el refresh token ya rotado
+	if _, err := service.RefreshTokens(context.Background(), first.RefreshToken); err != errReusedRefreshToken {
+		t.Fatalf("Expected reuse error, got: %v", err)
+	}
+
+	for _, stored := range tokensRepo.tokens {
+		if stored.RevokedAt == nil {
+			t.Error("Expected every token of the family to be revoked")
+		}
+	}
+
+	// El token legítimo más reciente también queda inválido
+	if _, err := service.RefreshTokens(context.Background(), second.RefreshToken); err == nil {
+		t.Error("Expected refresh with a revoked family to fail")
+	}
+}
+
+// TestRefreshTokens_Expired prueba un refresh token vencido
+func TestRefreshTokens_Expired(t *testing.T) {
+	tokensRepo := &MockTokensRepository{}
+	service := newTokensTestService(tokensRepo)
+
+	tokens := loginForTest(t, service)
+	tokensRepo.tokens[0].ExpiresAt = time.Now().Add(-time.Minute)
+
+	if _, err := service.RefreshTokens(context.Background(), tokens.RefreshToken); err != errExpiredRefreshToken {
+		t.Fatalf("Expected expired error, got: %v", err)
+	}
+}
+
+// TestRefreshTokens_Unknown prueba un refresh token inexistente
+func TestRefreshTokens_Unknown(t *testing.T) {
+	service := newTokensTestService(&MockTokensRepository{})
+
+	if _, err := service.RefreshTokens(context.Background(), "not-a-token"); err != errInvalidRefreshToken {
+		t.Fatalf("Expected invalid error, got: %v", err)
+	}
+}
+
+// TestLogout_RevokesAccessTokenAndFamily prueba el logout sin enviar refresh token
+func TestLogout_RevokesAccessTokenAndFamily(t *testing.T) {
+	var revokedJTI string
+	tokensRepo := &MockTokensRepository{
+		RevokeAccessTokenFunc: func(ctx context.Context, token domain.RevokedToken) error {
+			revokedJTI = token.JTI
+			if !token.ExpiresAt.After(time.Now()) {
+				t.Error("Expected revocation to outlive the access token")
+			}
+			return nil
+		},
+	}
+	service := newTokensTestService(tokensRepo)
+
+	tokens := loginForTest(t, service)
+	claims, _ := service.ValidateToken(tokens.AccessToken)
+	jti := claims.ID
+
+	if err := service.Logout(context.Background(), 1, jti, ""); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if revokedJTI != jti {
+		t.Errorf("Expected jti %s to be revoked, got: %s", jti, revokedJTI)
+	}
+	if tokensRepo.tokens[0].RevokedAt == nil {
+		t.Error("Expected refresh token family to be revoked on logout")
+	}
+	if _, err := service.RefreshTokens(context.Background(), tokens.RefreshToken); err == nil {
+		t.Error("Expected refresh after logout to fail")
+	}
+}
+
+// TestLogout_IgnoresOtherUsersRefreshToken prueba que no se puede revocar la sesión de otro usuario
+func TestLogout_IgnoresOtherUsersRefreshToken(t *testing.T) {
+	tokensRepo := &MockTokensRepository{}
+	service := newTokensTestService(tokensRepo)
+
+	tokens := loginForTest(t, service)
+
+	if err := service.Logout(context.Background(), 2, "", tokens.RefreshToken); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if tokensRepo.tokens[0].RevokedAt != nil {
+		t.Error("Expected refresh token of another user not to be revoked")
+	}
+}
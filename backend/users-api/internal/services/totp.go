@@ -0,0 +1,92 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Parámetros TOTP (RFC 6238): los defaults que entienden todas las apps autenticadoras
+const (
+	totpPeriod      = 30 // Segundos de cada paso
+	totpDigits      = 6
+	totpSecretBytes = 20 // 160 bits, el largo recomendado para HMAC-SHA1 (RFC 4226)
+	totpSkew        = 1  // Pasos de tolerancia hacia atrás y adelante (reloj del teléfono desfasado)
+)
+
+// totpEncoding es base32 sin padding, el formato de los secretos en las URIs otpauth
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newTOTPSecret genera un secreto aleatorio codificado en base32
+func newTOTPSecret() (string, error) {
+	secret := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// totpStep devuelve el paso TOTP del instante t
+func totpStep(t time.Time) int64 {
+	return t.Unix() / totpPeriod
+}
+
+// hotp calcula el código HOTP (RFC 4226) de key para el contador
+func hotp(key []byte, counter int64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// Truncamiento dinámico: 31 bits a partir del offset que indica el último nibble
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
+
+// verifyTOTP busca el paso (dentro de la tolerancia) cuyo código coincide con code
+// Solo acepta pasos posteriores a lastStep: un código ya usado no vuelve a servir
+func verifyTOTP(secret, code string, now time.Time, lastStep int64) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := totpStep(now)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		if hmac.Equal([]byte(hotp(key, step, totpDigits)), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// otpauthURI arma la URI que las apps autenticadoras leen del QR
+// Formato: otpauth://totp/Issuer:cuenta?secret=...&issuer=...
+func otpauthURI(issuer, account, secret string) string {
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprintf("%d", totpDigits))
+	q.Set("period", fmt.Sprintf("%d", totpPeriod))
+
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + q.Encode()
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestHOTP_RFC6238Vectors prueba los vectores SHA-1 del apéndice B de RFC 6238
+func TestHOTP_RFC6238Vectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, v := range vectors {
+		step := totpStep(time.Unix(v.unix, 0))
+		if got := hotp(key, step, 8); got != v.code {
+			t.Errorf("T=%d: expected %s, got %s", v.unix, v.code, got)
+		}
+	}
+}
+
+// TestVerifyTOTP prueba la tolerancia de un paso y que un código ya usado no vuelve a servir
+func TestVerifyTOTP(t *testing.T) {
+	secret, err := newTOTPSecret()
+	if err != nil {
+		t.Fatalf("Expected secret, got %v", err)
+	}
+	key, _ := totpEncoding.DecodeString(secret)
+	now := time.Unix(1700000000, 0)
+	current := totpStep(now)
+
+	step, ok := verifyTOTP(secret, hotp(key, current-1, totpDigits), now, 0)
+	if !ok || step != current-1 {
+		t.Errorf("Expected previous step accepted, got %d %v", step, ok)
+	}
+	if _, ok := verifyTOTP(secret, hotp(key, current-1, totpDigits), now, current-1); ok {
+		t.Error("Expected used step to be rejected")
+	}
+	if _, ok := verifyTOTP(secret, hotp(key, current-2, totpDigits), now, 0); ok {
+		t.Error("Expected step outside the window to be rejected")
+	}
+	if _, ok := verifyTOTP(secret, "12345", now, 0); ok {
+		t.Error("Expected short code to be rejected")
+	}
+}
+
+func TestOTPAuthURI(t *testing.T) {
+	uri := otpauthURI("Gimnasio", "juan@example.com", "JBSWY3DPEHPK3PXP")
+
+	if !strings.HasPrefix(uri, "otpauth://totp/Gimnasio:juan@example.com?") {
+		t.Errorf("Unexpected label in %s", uri)
+	}
+	for _, param := range []string{"secret=JBSWY3DPEHPK3PXP", "issuer=Gimnasio", "digits=6", "period=30"} {
+		if !strings.Contains(uri, param) {
+			t.Errorf("Expected %s in %s", param, uri)
+		}
+	}
+}
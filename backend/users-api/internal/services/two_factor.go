@@ -0,0 +1,433 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"users-api/internal/domain"
+	"users-api/internal/repository"
+)
+
+// Cantidad de códigos de recuperación que se entregan al activar el segundo factor
+const recoveryCodeCount = 10
+
+// Errores del segundo factor (TOTP)
+var (
+	ErrTwoFactorAlreadyEnabled  = errors.New("el segundo factor ya está activo")
+	ErrTwoFactorNotEnabled      = errors.New("el segundo factor no está activo")
+	ErrTwoFactorNotEnrolled     = errors.New("no hay una inscripción de segundo factor pendiente")
+	ErrTwoFactorRequiredForRole = errors.New("tu rol requiere segundo factor: no se puede desactivar")
+	ErrInvalidTwoFactorCode     = errors.New("código de verificación inválido")
+	ErrInvalidLoginChallenge    = errors.New("challenge de login inválido o expirado")
+)
+
+// TwoFactorRequiredError indica que la contraseña es correcta pero falta el segundo factor
+// Login la devuelve con el challenge que el cliente canjea en POST /login/2fa
+type TwoFactorRequiredError struct {
+	Challenge domain.LoginChallenge
+}
+
+func (e *TwoFactorRequiredError) Error() string {
+	return "se requiere segundo factor"
+}
+
+// TwoFactorPolicy define cómo se aplica el segundo factor
+type TwoFactorPolicy struct {
+	Issuer           string        // Nombre que muestra la app autenticadora
+	RequiredForStaff bool          // Exigir 2FA a owners y staff (cualquier rol por encima de member)
+	ChallengeTTL     time.Duration // Vida del challenge entre POST /login y POST /login/2fa
+}
+
+// DefaultTwoFactorPolicy es la configuración por defecto (2FA opcional)
+func DefaultTwoFactorPolicy() TwoFactorPolicy {
+	return TwoFactorPolicy{
+		Issuer:       "Gimnasio",
+		ChallengeTTL: 5 * time.Minute,
+	}
+}
+
+// GetTwoFactorStatus devuelve el estado del segundo factor del usuario
+func (s *UsersServiceImpl) GetTwoFactorStatus(ctx context.Context, userID uint) (domain.TwoFactorStatus, error) {
+	user, err := s.repository.GetByID(ctx, userID)
+	if err != nil {
+		return domain.TwoFactorStatus{}, err
+	}
+
+	required, err := s.requiresTwoFactor(ctx, user)
+	if err != nil {
+		return domain.TwoFactorStatus{}, err
+	}
+	status := domain.TwoFactorStatus{Required: required}
+
+	twoFactor, err := s.twoFactorRepo.GetTwoFactor(ctx, userID)
+	if errors.Is(err, repository.ErrTwoFactorNotFound) {
+		return status, nil
+	}
+	if err != nil {
+		return domain.TwoFactorStatus{}, err
+	}
+
+	status.Enabled = twoFactor.Enabled()
+	status.Pending = !twoFactor.Enabled()
+	status.EnabledAt = twoFactor.EnabledAt
+	if status.Enabled {
+		if status.RecoveryCodesRemaining, err = s.twoFactorRepo.CountRecoveryCodes(ctx, userID); err != nil {
+			return domain.TwoFactorStatus{}, err
+		}
+	}
+
+	return status, nil
+}
+
+// EnrollTwoFactor inicia la inscripción TOTP del usuario logueado
+// El secreto queda pendiente hasta que se confirma con un código (ConfirmTwoFactor)
+func (s *UsersServiceImpl) EnrollTwoFactor(ctx context.Context, userID uint) (domain.TwoFactorEnrollment, error) {
+	user, err := s.repository.GetByID(ctx, userID)
+	if err != nil {
+		return domain.TwoFactorEnrollment{}, err
+	}
+
+	return s.startEnrollment(ctx, user)
+}
+
+// ConfirmTwoFactor activa el segundo factor con el primer código de la app y devuelve los códigos de recuperación
+func (s *UsersServiceImpl) ConfirmTwoFactor(ctx context.Context, userID uint, code string) (domain.RecoveryCodesResponse, error) {
+	twoFactor, err := s.twoFactorRepo.GetTwoFactor(ctx, userID)
+	if errors.Is(err, repository.ErrTwoFactorNotFound) {
+		return domain.RecoveryCodesResponse{}, ErrTwoFactorNotEnrolled
+	}
+	if err != nil {
+		return domain.RecoveryCodesResponse{}, err
+	}
+
+	codes, err := s.enableTwoFactor(ctx, twoFactor, code)
+	if err != nil {
+		return domain.RecoveryCodesResponse{}, err
+	}
+
+	return domain.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// RegenerateRecoveryCodes reemplaza los códigos de recuperación (los anteriores dejan de valer)
+func (s *UsersServiceImpl) RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) (domain.RecoveryCodesResponse, error) {
+	if _, err := s.verifyEnabledTwoFactor(ctx, userID, code); err != nil {
+		return domain.RecoveryCodesResponse{}, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return domain.RecoveryCodesResponse{}, err
+	}
+
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return domain.RecoveryCodesResponse{}, err
+	}
+
+	log.Printf("🔑 Usuario %d regeneró sus códigos de recuperación", userID)
+	return domain.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTwoFactor desactiva el segundo factor del usuario logueado (pide un código vigente)
+// Si su rol exige 2FA no puede desactivarlo
+func (s *UsersServiceImpl) DisableTwoFactor(ctx context.Context, userID uint, code string) error {
+	user, err := s.verifyEnabledTwoFactor(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+
+	required, err := s.requiresTwoFactor(ctx, user)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrTwoFactorRequiredForRole
+	}
+
+	if err := s.twoFactorRepo.DeleteTwoFactor(ctx, userID); err != nil {
+		return err
+	}
+
+	log.Printf("🔓 Usuario %d desactivó el segundo factor", userID)
+	return nil
+}
+
+// ResetTwoFactor borra el segundo factor de un usuario que perdió el teléfono y los códigos (solo owners)
+// Si su rol exige 2FA, en el próximo login se le pide volver a inscribirse
+func (s *UsersServiceImpl) ResetTwoFactor(ctx context.Context, adminID, userID uint) error {
+	if _, err := s.repository.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.twoFactorRepo.DeleteTwoFactor(ctx, userID); err != nil {
+		return err
+	}
+
+	log.Printf("🔓 Segundo factor del usuario %d reseteado por el owner %d", userID, adminID)
+	return nil
+}
+
+// EnrollTwoFactorWithChallenge inicia la inscripción obligatoria durante el login
+// Es para cuentas cuyo rol exige 2FA y que todavía no lo configuraron (enrollment_required)
+func (s *UsersServiceImpl) EnrollTwoFactorWithChallenge(ctx context.Context, challengeToken string) (domain.TwoFactorEnrollment, error) {
+	challenge, err := s.accountTokensRepo.GetAccountToken(ctx, hashToken(challengeToken), domain.TokenPurposeTwoFactorLogin)
+	if err != nil {
+		if errors.Is(err, repository.ErrAccountTokenInvalid) {
+			return domain.TwoFactorEnrollment{}, ErrInvalidLoginChallenge
+		}
+		return domain.TwoFactorEnrollment{}, err
+	}
+
+	user, err := s.repository.GetByID(ctx, challenge.UserID)
+	if err != nil || user.Disabled() {
+		return domain.TwoFactorEnrollment{}, ErrInvalidLoginChallenge
+	}
+
+	return s.startEnrollment(ctx, user)
+}
+
+// CompleteTwoFactorLogin canjea el challenge de POST /login y un código por los tokens de la sesión
+// Si la cuenta estaba inscribiéndose, el primer código válido activa el segundo factor
+// y se devuelven los códigos de recuperación (si no, nil)
+func (s *UsersServiceImpl) CompleteTwoFactorLogin(ctx context.Context, req domain.TwoFactorLoginRequest) (domain.UserResponse, domain.TokenPair, []string, error) {
+	if err := s.loginGuard.CheckIP(ctx, req.ClientIP); err != nil {
+		return domain.UserResponse{}, domain.TokenPair{}, nil, err
+	}
+
+	challengeHash := hashToken(req.ChallengeToken)
+	challenge, err := s.accountTokensRepo.GetAccountToken(ctx, challengeHash, domain.TokenPurposeTwoFactorLogin)
+	if err != nil {
+		if errors.Is(err, repository.ErrAccountTokenInvalid) {
+			return domain.UserResponse{}, domain.TokenPair{}, nil, ErrInvalidLoginChallenge
+		}
+		return domain.UserResponse{}, domain.TokenPair{}, nil, err
+	}
+
+	// Los códigos fallidos cuentan como logins fallidos: misma espera progresiva y bloqueo
+	if err := s.loginGuard.CheckAccount(ctx, challenge.UserID); err != nil {
+		return domain.UserResponse{}, domain.TokenPair{}, nil, err
+	}
+
+	user, err := s.repository.GetByID(ctx, challenge.UserID)
+	if err != nil || user.Disabled() {
+		return domain.UserResponse{}, domain.TokenPair{}, nil, ErrInvalidLoginChallenge
+	}
+
+	twoFactor, err := s.twoFactorRepo.GetTwoFactor(ctx, user.ID)
+	if errors.Is(err, repository.ErrTwoFactorNotFound) {
+		return domain.UserResponse{}, domain.TokenPair{}, nil, ErrTwoFactorNotEnrolled
+	}
+	if err != nil {
+		return domain.UserResponse{}, domain.TokenPair{}, nil, err
+	}
+
+	var recoveryCodes []string
+	if twoFactor.Enabled() {
+		err = s.checkTwoFactorCode(ctx, twoFactor, req.Code)
+	} else {
+		recoveryCodes, err = s.enableTwoFactor(ctx, twoFactor, req.Code)
+	}
+	if errors.Is(err, ErrInvalidTwoFactorCode) {
+		s.loginGuard.RecordFailure(ctx, req.ClientIP, &user)
+	}
+	if err != nil {
+		return domain.UserResponse{}, domain.TokenPair{}, nil, err
+	}
+
+	// El challenge es de un solo uso
+	if _, err := s.accountTokensRepo.ConsumeAccountToken(ctx, challengeHash, domain.TokenPurposeTwoFactorLogin); err != nil {
+		return domain.UserResponse{}, domain.TokenPair{}, nil, ErrInvalidLoginChallenge
+	}
+
+	s.loginGuard.RecordSuccess(ctx, user.ID)
+
+	tokens, err := s.issueTokens(ctx, user, "")
+	if err != nil {
+		return domain.UserResponse{}, domain.TokenPair{}, nil, err
+	}
+
+	return user.ToResponse(), tokens, recoveryCodes, nil
+}
+
+// requireSecondFactor devuelve un TwoFactorRequiredError si el login necesita segundo factor
+// (el usuario lo activó o su rol lo exige); nil si alcanza con la contraseña
+func (s *UsersServiceImpl) requireSecondFactor(ctx context.Context, user domain.User) error {
+	enabled := false
+	twoFactor, err := s.twoFactorRepo.GetTwoFactor(ctx, user.ID)
+	if err == nil {
+		enabled = twoFactor.Enabled()
+	} else if !errors.Is(err, repository.ErrTwoFactorNotFound) {
+		return err
+	}
+
+	if !enabled {
+		required, err := s.requiresTwoFactor(ctx, user)
+		if err != nil {
+			return err
+		}
+		if !required {
+			return nil
+		}
+	}
+
+	token, err := s.newAccountToken(ctx, user.ID, domain.TokenPurposeTwoFactorLogin, s.twoFactor.ChallengeTTL)
+	if err != nil {
+		return err
+	}
+
+	return &TwoFactorRequiredError{Challenge: domain.LoginChallenge{
+		TwoFactorRequired:  true,
+		ChallengeToken:     token,
+		ExpiresIn:          int64(s.twoFactor.ChallengeTTL.Seconds()),
+		EnrollmentRequired: !enabled,
+	}}
+}
+
+// requiresTwoFactor indica si el rol del usuario exige segundo factor (owner o cualquier rol de staff)
+func (s *UsersServiceImpl) requiresTwoFactor(ctx context.Context, user domain.User) (bool, error) {
+	if !s.twoFactor.RequiredForStaff {
+		return false, nil
+	}
+	if user.IsAdmin {
+		return true, nil
+	}
+
+	assignments, err := s.repository.GetRoles(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	return len(assignments) > 0, nil
+}
+
+// startEnrollment genera un secreto nuevo y lo guarda pendiente de confirmación
+func (s *UsersServiceImpl) startEnrollment(ctx context.Context, user domain.User) (domain.TwoFactorEnrollment, error) {
+	twoFactor, err := s.twoFactorRepo.GetTwoFactor(ctx, user.ID)
+	if err == nil && twoFactor.Enabled() {
+		return domain.TwoFactorEnrollment{}, ErrTwoFactorAlreadyEnabled
+	}
+	if err != nil && !errors.Is(err, repository.ErrTwoFactorNotFound) {
+		return domain.TwoFactorEnrollment{}, err
+	}
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return domain.TwoFactorEnrollment{}, fmt.Errorf("error generating totp secret: %w", err)
+	}
+
+	if err := s.twoFactorRepo.SavePendingSecret(ctx, user.ID, secret); err != nil {
+		return domain.TwoFactorEnrollment{}, err
+	}
+
+	return domain.TwoFactorEnrollment{
+		Secret:     secret,
+		OTPAuthURI: otpauthURI(s.twoFactor.Issuer, user.Email, secret),
+	}, nil
+}
+
+// enableTwoFactor confirma una inscripción pendiente con un código TOTP y genera los códigos de recuperación
+func (s *UsersServiceImpl) enableTwoFactor(ctx context.Context, twoFactor domain.TwoFactor, code string) ([]string, error) {
+	if twoFactor.Enabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := verifyTOTP(twoFactor.Secret, normalizeTwoFactorCode(code), time.Now(), twoFactor.LastStep)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepo.EnableTwoFactor(ctx, twoFactor.UserID, step, hashes); err != nil {
+		if errors.Is(err, repository.ErrTwoFactorNotFound) {
+			return nil, ErrTwoFactorNotEnrolled
+		}
+		return nil, err
+	}
+
+	log.Printf("🔐 Usuario %d activó el segundo factor", twoFactor.UserID)
+	return codes, nil
+}
+
+// verifyEnabledTwoFactor exige que el usuario tenga el segundo factor activo y que el código sea válido
+func (s *UsersServiceImpl) verifyEnabledTwoFactor(ctx context.Context, userID uint, code string) (domain.User, error) {
+	user, err := s.repository.GetByID(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	twoFactor, err := s.twoFactorRepo.GetTwoFactor(ctx, userID)
+	if errors.Is(err, repository.ErrTwoFactorNotFound) || (err == nil && !twoFactor.Enabled()) {
+		return domain.User{}, ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if err := s.checkTwoFactorCode(ctx, twoFactor, code); err != nil {
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// checkTwoFactorCode acepta un código TOTP (6 dígitos) o un código de recuperación, ambos de un solo uso
+func (s *UsersServiceImpl) checkTwoFactorCode(ctx context.Context, twoFactor domain.TwoFactor, code string) error {
+	code = normalizeTwoFactorCode(code)
+
+	if len(code) == totpDigits {
+		step, ok := verifyTOTP(twoFactor.Secret, code, time.Now(), twoFactor.LastStep)
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+		// Registrar el paso de forma atómica: el mismo código no entra dos veces
+		used, err := s.twoFactorRepo.UseStep(ctx, twoFactor.UserID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	consumed, err := s.twoFactorRepo.ConsumeRecoveryCode(ctx, twoFactor.UserID, hashToken(code))
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return ErrInvalidTwoFactorCode
+	}
+
+	log.Printf("🔑 Usuario %d usó un código de recuperación", twoFactor.UserID)
+	return nil
+}
+
+// newRecoveryCodes genera los códigos de recuperación (xxxx-xxxx) y sus hashes
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		b := make([]byte, 5) // 40 bits: 8 caracteres base32
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("error generating recovery code: %w", err)
+		}
+		raw := strings.ToLower(totpEncoding.EncodeToString(b))
+		codes[i] = raw[:4] + "-" + raw[4:]
+		hashes[i] = hashToken(raw)
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeTwoFactorCode quita espacios y guiones y pasa a minúsculas (como se tipean desde el teléfono o el papel)
+func normalizeTwoFactorCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer(" ", "", "-", "").Replace(code)
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"users-api/internal/domain"
+	"users-api/internal/repository"
+)
+
+// MockTwoFactorRepository es un mock del repositorio de segundo factor para testing
+// Si no se definen funciones, guarda la configuración y los códigos en memoria
+type MockTwoFactorRepository struct {
+	GetTwoFactorFunc func(ctx context.Context, userID uint) (domain.TwoFactor, error)
+
+	records       map[uint]domain.TwoFactor
+	recoveryCodes map[uint]map[string]bool // hash -> usado
+}
+
+func (m *MockTwoFactorRepository) init() {
+	if m.records == nil {
+		m.records = map[uint]domain.TwoFactor{}
+		m.recoveryCodes = map[uint]map[string]bool{}
+	}
+}
+
+func (m *MockTwoFactorRepository) GetTwoFactor(ctx context.Context, userID uint) (domain.TwoFactor, error) {
+	if m.GetTwoFactorFunc != nil {
+		return m.GetTwoFactorFunc(ctx, userID)
+	}
+	m.init()
+	record, ok := m.records[userID]
+	if !ok {
+		return domain.TwoFactor{}, repository.ErrTwoFactorNotFound
+	}
+	return record, nil
+}
+
+func (m *MockTwoFactorRepository) SavePendingSecret(ctx context.Context, userID uint, secret string) error {
+	m.init()
+	m.records[userID] = domain.TwoFactor{UserID: userID, Secret: secret}
+	return nil
+}
+
+func (m *MockTwoFactorRepository) EnableTwoFactor(ctx context.Context, userID uint, step int64, recoveryCodeHashes []string) error {
+	m.init()
+	record, ok := m.records[userID]
+	if !ok || record.Enabled() {
+		return repository.ErrTwoFactorNotFound
+	}
+	now := time.Now()
+	record.EnabledAt = &now
+	record.LastStep = step
+	m.records[userID] = record
+	return m.ReplaceRecoveryCodes(ctx, userID, recoveryCodeHashes)
+}
+
+func (m *MockTwoFactorRepository) UseStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	m.init()
+	record, ok := m.records[userID]
+	if !ok || record.LastStep >= step {
+		return false, nil
+	}
+	record.LastStep = step
+	m.records[userID] = record
+	return true, nil
+}
+
+func (m *MockTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, recoveryCodeHashes []string) error {
+	m.init()
+	m.recoveryCodes[userID] = map[string]bool{}
+	for _, hash := range recoveryCodeHashes {
+		m.recoveryCodes[userID][hash] = false
+	}
+	return nil
+}
+
+func (m *MockTwoFactorRepository) ConsumeRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	m.init()
+	used, ok := m.recoveryCodes[userID][codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	m.recoveryCodes[userID][codeHash] = true
+	return true, nil
+}
+
+func (m *MockTwoFactorRepository) CountRecoveryCodes(ctx context.Context, userID uint) (int, error) {
+	m.init()
+	count := 0
+	for _, used := range m.recoveryCodes[userID] {
+		if !used {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockTwoFactorRepository) DeleteTwoFactor(ctx context.Context, userID uint) error {
+	m.init()
+	delete(m.records, userID)
+	delete(m.recoveryCodes, userID)
+	return nil
+}
+
+// Verificar que MockTwoFactorRepository implementa la interfaz
+var _ repository.TwoFactorRepository = (*MockTwoFactorRepository)(nil)
+
+// newTwoFactorTestEnv usa el entorno de perfiles (admin 1 y usuario 2) con 2FA en memoria
+func newTwoFactorTestEnv(policy TwoFactorPolicy) (*profileTestEnv, *MockTwoFactorRepository) {
+	env := newProfileTestEnv()
+	twoFactorRepo := &MockTwoFactorRepository{}
+	env.service.twoFactorRepo = twoFactorRepo
+	env.service.twoFactor = policy
+	return env, twoFactorRepo
+}
+
+// totpCodeAt genera el código de la app autenticadora para el paso actual + offset
+func totpCodeAt(t *testing.T, secret string, offset int64) string {
+	t.Helper()
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("Invalid secret %q: %v", secret, err)
+	}
+	return hotp(key, totpStep(time.Now())+offset, totpDigits)
+}
+
+// challengeFrom extrae el challenge del error de Login
+func challengeFrom(t *testing.T, err error) domain.LoginChallenge {
+	t.Helper()
+	var required *TwoFactorRequiredError
+	if !errors.As(err, &required) {
+		t.Fatalf("Expected TwoFactorRequiredError, got %v", err)
+	}
+	return required.Challenge
+}
+
+// TestTwoFactor_EnrollAndLogin prueba la inscripción opcional y el login en dos pasos
+func TestTwoFactor_EnrollAndLogin(t *testing.T) {
+	env, twoFactorRepo := newTwoFactorTestEnv(DefaultTwoFactorPolicy())
+	ctx := context.Background()
+	credentials := domain.UserLogin{UsernameOrEmail: "juanperez", Password: "password123"}
+
+	enrollment, err := env.service.EnrollTwoFactor(ctx, 2)
+	if err != nil {
+		t.Fatalf("Expected enrollment, got %v", err)
+	}
+
+	// Pendiente: el login todavía no pide el segundo factor
+	if _, _, err := env.service.Login(ctx, credentials); err != nil {
+		t.Fatalf("Expected login without 2FA while pending, got %v", err)
+	}
+
+	if _, err := env.service.ConfirmTwoFactor(ctx, 2, "000000"); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("Expected ErrInvalidTwoFactorCode, got %v", err)
+	}
+	recovery, err := env.service.ConfirmTwoFactor(ctx, 2, totpCodeAt(t, enrollment.Secret, 0))
+	if err != nil {
+		t.Fatalf("Expected 2FA enabled, got %v", err)
+	}
+	if len(recovery.RecoveryCodes) != recoveryCodeCount {
+		t.Errorf("Expected %d recovery codes, got %d", recoveryCodeCount, len(recovery.RecoveryCodes))
+	}
+	if _, err := env.service.EnrollTwoFactor(ctx, 2); !errors.Is(err, ErrTwoFactorAlreadyEnabled) {
+		t.Errorf("Expected ErrTwoFactorAlreadyEnabled, got %v", err)
+	}
+
+	// La contraseña sola ya no alcanza
+	_, tokens, err := env.service.Login(ctx, credentials)
+	challenge := challengeFrom(t, err)
+	if tokens.AccessToken != "" || challenge.ChallengeToken == "" || challenge.EnrollmentRequired {
+		t.Fatalf("Expected challenge without tokens, got %+v %+v", tokens, challenge)
+	}
+
+	// El código con el que se activó no se puede reutilizar
+	req := domain.TwoFactorLoginRequest{ChallengeToken: challenge.ChallengeToken, Code: totpCodeAt(t, enrollment.Secret, 0)}
+	if _, _, _, err := env.service.CompleteTwoFactorLogin(ctx, req); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("Expected replayed code rejected, got %v", err)
+	}
+
+	// Un código de recuperación (como lo tipea el usuario) completa el login y se consume
+	req.Code = " " + recovery.RecoveryCodes[0] + " "
+	user, tokens, codes, err := env.service.CompleteTwoFactorLogin(ctx, req)
+	if err != nil || user.ID != 2 || tokens.AccessToken == "" || codes != nil {
+		t.Fatalf("Expected tokens for user 2, got %v %+v %v", err, tokens, codes)
+	}
+	if _, _, _, err := env.service.CompleteTwoFactorLogin(ctx, req); !errors.Is(err, ErrInvalidLoginChallenge) {
+		t.Errorf("Expected challenge to be single use, got %v", err)
+	}
+
+	status, err := env.service.GetTwoFactorStatus(ctx, 2)
+	if err != nil || !status.Enabled || status.RecoveryCodesRemaining != recoveryCodeCount-1 {
+		t.Errorf("Expected enabled with %d codes left, got %+v (%v)", recoveryCodeCount-1, status, err)
+	}
+
+	// Desactivar pide un código vigente
+	if err := env.service.DisableTwoFactor(ctx, 2, recovery.RecoveryCodes[0]); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("Expected used recovery code rejected, got %v", err)
+	}
+	if err := env.service.DisableTwoFactor(ctx, 2, totpCodeAt(t, enrollment.Secret, 1)); err != nil {
+		t.Fatalf("Expected 2FA disabled, got %v", err)
+	}
+	if _, err := twoFactorRepo.GetTwoFactor(ctx, 2); !errors.Is(err, repository.ErrTwoFactorNotFound) {
+		t.Errorf("Expected secret deleted, got %v", err)
+	}
+}
+
+// TestTwoFactor_RequiredForStaffEnrollsAtLogin prueba que un owner sin 2FA se inscribe durante el login
+func TestTwoFactor_RequiredForStaffEnrollsAtLogin(t *testing.T) {
+	policy := DefaultTwoFactorPolicy()
+	policy.RequiredForStaff = true
+	env, _ := newTwoFactorTestEnv(policy)
+	ctx := context.Background()
+
+	// Un member no tiene segundo factor obligatorio
+	if _, _, err := env.service.Login(ctx, domain.UserLogin{UsernameOrEmail: "juanperez", Password: "password123"}); err != nil {
+		t.Fatalf("Expected member login without 2FA, got %v", err)
+	}
+
+	_, _, err := env.service.Login(ctx, domain.UserLogin{UsernameOrEmail: "admin", Password: "password123"})
+	challenge := challengeFrom(t, err)
+	if !challenge.EnrollmentRequired {
+		t.Fatalf("Expected enrollment required, got %+v", challenge)
+	}
+
+	req := domain.TwoFactorLoginRequest{ChallengeToken: challenge.ChallengeToken, Code: "123456"}
+	if _, _, _, err := env.service.CompleteTwoFactorLogin(ctx, req); !errors.Is(err, ErrTwoFactorNotEnrolled) {
+		t.Errorf("Expected ErrTwoFactorNotEnrolled before enrolling, got %v", err)
+	}
+
+	enrollment, err := env.service.EnrollTwoFactorWithChallenge(ctx, challenge.ChallengeToken)
+	if err != nil {
+		t.Fatalf("Expected enrollment, got %v", err)
+	}
+
+	req.Code = totpCodeAt(t, enrollment.Secret, 0)
+	user, tokens, codes, err := env.service.CompleteTwoFactorLogin(ctx, req)
+	if err != nil || user.ID != 1 || tokens.AccessToken == "" || len(codes) != recoveryCodeCount {
+		t.Fatalf("Expected tokens and recovery codes, got %v %+v %v", err, tokens, codes)
+	}
+
+	if err := env.service.DisableTwoFactor(ctx, 1, codes[0]); !errors.Is(err, ErrTwoFactorRequiredForRole) {
+		t.Errorf("Expected ErrTwoFactorRequiredForRole, got %v", err)
+	}
+	if _, err := env.service.EnrollTwoFactorWithChallenge(ctx, challenge.ChallengeToken); !errors.Is(err, ErrInvalidLoginChallenge) {
+		t.Errorf("Expected used challenge rejected, got %v", err)
+	}
+}
+
+// TestTwoFactor_WrongCodesCountAsFailedLogins prueba que los códigos incorrectos activan la espera progresiva
+// y que volver a ingresar la contraseña no resetea los fallos
+func TestTwoFactor_WrongCodesCountAsFailedLogins(t *testing.T) {
+	env, _ := newTwoFactorTestEnv(DefaultTwoFactorPolicy())
+	ctx := context.Background()
+	credentials := domain.UserLogin{UsernameOrEmail: "juanperez", Password: "password123"}
+
+	enrollment, _ := env.service.EnrollTwoFactor(ctx, 2)
+	if _, err := env.service.ConfirmTwoFactor(ctx, 2, totpCodeAt(t, enrollment.Secret, 0)); err != nil {
+		t.Fatalf("Expected 2FA enabled, got %v", err)
+	}
+
+	for i := 0; i < DefaultLoginPolicy().DelayAfter; i++ {
+		_, _, err := env.service.Login(ctx, credentials)
+		challenge := challengeFrom(t, err)
+
+		_, _, _, err = env.service.CompleteTwoFactorLogin(ctx, domain.TwoFactorLoginRequest{ChallengeToken: challenge.ChallengeToken, Code: "000000"})
+		if !errors.Is(err, ErrInvalidTwoFactorCode) {
+			t.Fatalf("Attempt %d: expected ErrInvalidTwoFactorCode, got %v", i+1, err)
+		}
+	}
+
+	var blocked *LoginBlockedError
+	if _, _, err := env.service.Login(ctx, credentials); !errors.As(err, &blocked) {
+		t.Errorf("Expected login blocked after wrong codes, got %v", err)
+	}
+}
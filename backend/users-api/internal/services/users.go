@@ -2,52 +2,116 @@ package services
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"errors"
 	"fmt"
+	"log"
 	"regexp"
 	"strings"
 	"time"
 	"users-api/internal/domain"
 	"users-api/internal/repository"
 
-	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/yourusername/gym-management/shared/auth"
 )
 
 // UsersService define la interfaz del servicio de usuarios
 type UsersService interface {
-	Register(ctx context.Context, userReg domain.UserRegister) (domain.UserResponse, string, error)
-	Login(ctx context.Context, credentials domain.UserLogin) (domain.UserResponse, string, error)
+	Register(ctx context.Context, userReg domain.UserRegister) (domain.UserResponse, domain.TokenPair, error)
+	Login(ctx context.Context, credentials domain.UserLogin) (domain.UserResponse, domain.TokenPair, error)
+	CompleteTwoFactorLogin(ctx context.Context, req domain.TwoFactorLoginRequest) (domain.UserResponse, domain.TokenPair, []string, error)
+	EnrollTwoFactorWithChallenge(ctx context.Context, challengeToken string) (domain.TwoFactorEnrollment, error)
+	RefreshTokens(ctx context.Context, refreshToken string) (domain.TokenPair, error)
+	Logout(ctx context.Context, userID uint, jti string, refreshToken string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendEmailVerification(ctx context.Context, userID uint) error
+	UpdateProfile(ctx context.Context, userID uint, req domain.UpdateProfileRequest) (domain.UserResponse, error)
+	ChangePassword(ctx context.Context, userID uint, req domain.ChangePasswordRequest) (domain.TokenPair, error)
+	GetTwoFactorStatus(ctx context.Context, userID uint) (domain.TwoFactorStatus, error)
+	EnrollTwoFactor(ctx context.Context, userID uint) (domain.TwoFactorEnrollment, error)
+	ConfirmTwoFactor(ctx context.Context, userID uint, code string) (domain.RecoveryCodesResponse, error)
+	RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) (domain.RecoveryCodesResponse, error)
+	DisableTwoFactor(ctx context.Context, userID uint, code string) error
+	ResetTwoFactor(ctx context.Context, adminID, userID uint) error
+	UnlockUser(ctx context.Context, userID uint) error
+	DisableUser(ctx context.Context, adminID, userID uint) error
+	RestoreUser(ctx context.Context, userID uint) error
+	SetAdmin(ctx context.Context, adminID, userID uint, isAdmin bool) (domain.UserResponse, error)
+	DeleteUser(ctx context.Context, adminID, userID uint) error
+	GetRoles(ctx context.Context, userID uint) (domain.UserRolesResponse, error)
+	SetRoles(ctx context.Context, adminID, userID uint, req domain.SetRolesRequest) (domain.UserRolesResponse, error)
 	GetByID(ctx context.Context, id uint) (domain.UserResponse, error)
-	List(ctx context.Context) ([]domain.UserResponse, error)
-	ValidateToken(tokenString string) (jwt.MapClaims, error)
+	List(ctx context.Context, query domain.UserListQuery) (domain.UserListResponse, error)
+	ValidateToken(tokenString string) (*auth.Claims, error)
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	ListRevokedTokens(ctx context.Context) ([]domain.RevokedToken, error)
+	JWKS() auth.JWKSet
 }
 
 // UsersServiceImpl implementa UsersService
 type UsersServiceImpl struct {
-	repository repository.UsersRepository
-	jwtSecret  string
+	repository        repository.UsersRepository
+	tokensRepo        repository.TokensRepository
+	accountTokensRepo repository.AccountTokensRepository
+	twoFactorRepo     repository.TwoFactorRepository
+	keys              *SigningKeys
+	verifier          *auth.Verifier
+	mailer            Mailer
+	publisher         EventPublisher
+	links             AccountLinks
+	loginGuard        *LoginGuard
+	twoFactor         TwoFactorPolicy
+	accessTTL         time.Duration
+	refreshTTL        time.Duration
 }
 
 // NewUsersService crea una nueva instancia del servicio
-// Dependency Injection: recibe el repository como parámetro
-func NewUsersService(repo repository.UsersRepository, jwtSecret string) *UsersServiceImpl {
+// Dependency Injection: recibe los repositories, el mailer, el publisher de eventos, el guard de login,
+// la política de segundo factor y la configuración de tokens como parámetros
+func NewUsersService(
+	repo repository.UsersRepository,
+	tokensRepo repository.TokensRepository,
+	accountTokensRepo repository.AccountTokensRepository,
+	twoFactorRepo repository.TwoFactorRepository,
+	keys *SigningKeys,
+	mailer Mailer,
+	publisher EventPublisher,
+	links AccountLinks,
+	loginGuard *LoginGuard,
+	twoFactor TwoFactorPolicy,
+	accessTTL, refreshTTL time.Duration,
+) *UsersServiceImpl {
 	return &UsersServiceImpl{
-		repository: repo,
-		jwtSecret:  jwtSecret,
+		repository:        repo,
+		tokensRepo:        tokensRepo,
+		accountTokensRepo: accountTokensRepo,
+		twoFactorRepo:     twoFactorRepo,
+		keys:              keys,
+		verifier:          auth.NewVerifier(keys, nil), // La revocación se consulta en la BD (IsTokenRevoked)
+		mailer:            mailer,
+		publisher:         publisher,
+		links:             links,
+		loginGuard:        loginGuard,
+		twoFactor:         twoFactor,
+		accessTTL:         accessTTL,
+		refreshTTL:        refreshTTL,
 	}
 }
 
 // Register registra un nuevo usuario
-func (s *UsersServiceImpl) Register(ctx context.Context, userReg domain.UserRegister) (domain.UserResponse, string, error) {
+func (s *UsersServiceImpl) Register(ctx context.Context, userReg domain.UserRegister) (domain.UserResponse, domain.TokenPair, error) {
 	// Validaciones de negocio
 	if err := s.validateUserRegistration(userReg); err != nil {
-		return domain.UserResponse{}, "", err
+		return domain.UserResponse{}, domain.TokenPair{}, err
 	}
 
-	// Hashear password
-	hashedPassword := s.hashPassword(userReg.Password)
+	// Hashear password (argon2id con salt por usuario)
+	hashedPassword, err := s.hashPassword(userReg.Password)
+	if err != nil {
+		return domain.UserResponse{}, domain.TokenPair{}, fmt.Errorf("error hashing password: %w", err)
+	}
 
 	// Crear domain user
 	user := domain.User{
@@ -63,39 +127,91 @@ func (s *UsersServiceImpl) Register(ctx context.Context, userReg domain.UserRegi
 	// Guardar en repository
 	createdUser, err := s.repository.Create(ctx, user)
 	if err != nil {
-		return domain.UserResponse{}, "", fmt.Errorf("error creating user: %w", err)
+		return domain.UserResponse{}, domain.TokenPair{}, fmt.Errorf("error creating user: %w", err)
+	}
+
+	// Enviar link de verificación: si falla, el usuario puede pedir otro (POST /email/verify/resend)
+	if err := s.sendEmailVerification(ctx, createdUser); err != nil {
+		log.Printf("⚠️  Error enviando verificación de email al usuario %d: %v", createdUser.ID, err)
 	}
 
-	// Generar token JWT
-	token, err := s.generateToken(createdUser)
+	// Generar access token + refresh token (nueva familia)
+	tokens, err := s.issueTokens(ctx, createdUser, "")
 	if err != nil {
-		return domain.UserResponse{}, "", fmt.Errorf("error generating token: %w", err)
+		return domain.UserResponse{}, domain.TokenPair{}, err
 	}
 
-	return createdUser.ToResponse(), token, nil
+	return createdUser.ToResponse(), tokens, nil
 }
 
-// Login autentica un usuario y devuelve un token JWT
-func (s *UsersServiceImpl) Login(ctx context.Context, credentials domain.UserLogin) (domain.UserResponse, string, error) {
+// Login autentica un usuario y devuelve un access token JWT y un refresh token
+func (s *UsersServiceImpl) Login(ctx context.Context, credentials domain.UserLogin) (domain.UserResponse, domain.TokenPair, error) {
+	// IP bloqueada por demasiados fallos (sobre cualquier cuenta)
+	if err := s.loginGuard.CheckIP(ctx, credentials.ClientIP); err != nil {
+		return domain.UserResponse{}, domain.TokenPair{}, err
+	}
+
 	// Buscar usuario por username o email
 	user, err := s.repository.GetByUsernameOrEmail(ctx, credentials.UsernameOrEmail)
 	if err != nil {
-		return domain.UserResponse{}, "", errors.New("credenciales inválidas")
+		// Mismo trabajo de argon2id que con una contraseña incorrecta (ver dummyPasswordHash)
+		s.verifyPassword(credentials.Password, dummyPasswordHash)
+		s.loginGuard.RecordFailure(ctx, credentials.ClientIP, nil)
+		return domain.UserResponse{}, domain.TokenPair{}, errors.New("credenciales inválidas")
+	}
+
+	// Cuenta en espera o bloqueada: se rechaza sin verificar la contraseña
+	if err := s.loginGuard.CheckAccount(ctx, user.ID); err != nil {
+		return domain.UserResponse{}, domain.TokenPair{}, err
 	}
 
 	// Verificar password
-	hashedPassword := s.hashPassword(credentials.Password)
-	if user.Password != hashedPassword {
-		return domain.UserResponse{}, "", errors.New("credenciales inválidas")
+	match, needsRehash, err := s.verifyPassword(credentials.Password, user.Password)
+	if err != nil || !match {
+		s.loginGuard.RecordFailure(ctx, credentials.ClientIP, &user)
+		return domain.UserResponse{}, domain.TokenPair{}, errors.New("credenciales inválidas")
 	}
 
-	// Generar token JWT
-	token, err := s.generateToken(user)
+	// Cuenta deshabilitada por un admin (se informa solo a quien conoce la contraseña)
+	if user.Disabled() {
+		return domain.UserResponse{}, domain.TokenPair{}, ErrUserDisabled
+	}
+
+	// Migración transparente: re-hashear hashes legados (SHA-256) o con parámetros viejos
+	if needsRehash {
+		s.rehashPassword(ctx, user.ID, credentials.Password)
+	}
+
+	// Segundo factor: se devuelve un challenge (TwoFactorRequiredError) en lugar de los tokens
+	// Los fallos de la cuenta se resetean recién al completar el login: alternar contraseña y
+	// códigos no debe permitir probar códigos TOTP sin bloqueo
+	if err := s.requireSecondFactor(ctx, user); err != nil {
+		return domain.UserResponse{}, domain.TokenPair{}, err
+	}
+
+	s.loginGuard.RecordSuccess(ctx, user.ID)
+
+	// Generar access token + refresh token (nueva familia)
+	tokens, err := s.issueTokens(ctx, user, "")
 	if err != nil {
-		return domain.UserResponse{}, "", fmt.Errorf("error generating token: %w", err)
+		return domain.UserResponse{}, domain.TokenPair{}, err
 	}
 
-	return user.ToResponse(), token, nil
+	return user.ToResponse(), tokens, nil
+}
+
+// UnlockUser desbloquea una cuenta bloqueada por logins fallidos (solo admins)
+func (s *UsersServiceImpl) UnlockUser(ctx context.Context, userID uint) error {
+	if _, err := s.repository.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.loginGuard.Unlock(ctx, userID); err != nil {
+		return err
+	}
+
+	log.Printf("🔓 Usuario %d desbloqueado", userID)
+	return nil
 }
 
 // GetByID obtiene un usuario por su ID
@@ -108,11 +224,15 @@ func (s *UsersServiceImpl) GetByID(ctx context.Context, id uint) (domain.UserRes
 	return user.ToResponse(), nil
 }
 
-// List obtiene todos los usuarios
-func (s *UsersServiceImpl) List(ctx context.Context) ([]domain.UserResponse, error) {
-	users, err := s.repository.List(ctx)
+// List obtiene una página de usuarios (filtros, orden y paginación se resuelven en la base de datos)
+func (s *UsersServiceImpl) List(ctx context.Context, query domain.UserListQuery) (domain.UserListResponse, error) {
+	if err := normalizeListQuery(&query); err != nil {
+		return domain.UserListResponse{}, err
+	}
+
+	users, total, err := s.repository.List(ctx, query)
 	if err != nil {
-		return nil, err
+		return domain.UserListResponse{}, err
 	}
 
 	// Convertir a UserResponse
@@ -121,77 +241,114 @@ func (s *UsersServiceImpl) List(ctx context.Context) ([]domain.UserResponse, err
 		responses[i] = user.ToResponse()
 	}
 
-	return responses, nil
+	return domain.UserListResponse{
+		Data:       responses,
+		Page:       query.Page,
+		Limit:      query.Limit,
+		Total:      total,
+		TotalPages: int((total + int64(query.Limit) - 1) / int64(query.Limit)),
+	}, nil
 }
 
-// ValidateToken valida un token JWT y devuelve los claims
-func (s *UsersServiceImpl) ValidateToken(tokenString string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-		// Validar que el método de firma es HMAC
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
-		}
-		return []byte(s.jwtSecret), nil
-	})
+// normalizeListQuery aplica los valores por defecto de paginación y valida orden y rango de fechas
+func normalizeListQuery(query *domain.UserListQuery) error {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.Limit < 1 || query.Limit > 100 {
+		query.Limit = 20
+	}
+	query.Search = strings.TrimSpace(query.Search)
 
-	if err != nil {
-		return nil, fmt.Errorf("error parsing token: %w", err)
+	if query.SortBy == "" {
+		query.SortBy = "id"
+	}
+	if !repository.IsUserSortColumn(query.SortBy) {
+		return fmt.Errorf("%w: no se puede ordenar por %q", ErrInvalidListQuery, query.SortBy)
+	}
+
+	query.SortOrder = strings.ToLower(query.SortOrder)
+	if query.SortOrder == "" {
+		query.SortOrder = "asc"
+	}
+	if query.SortOrder != "asc" && query.SortOrder != "desc" {
+		return fmt.Errorf("%w: sort_order debe ser asc o desc", ErrInvalidListQuery)
 	}
 
-	if !token.Valid {
-		return nil, errors.New("invalid token")
+	if query.RegisteredFrom != nil && query.RegisteredTo != nil && query.RegisteredTo.Before(*query.RegisteredFrom) {
+		return fmt.Errorf("%w: registered_to es anterior a registered_from", ErrInvalidListQuery)
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, errors.New("invalid token claims")
+	return nil
+}
+
+// ValidateToken valida un token JWT y devuelve los claims
+// Usa el mismo Verifier que los demás servicios (shared/auth): un único contrato de claims
+func (s *UsersServiceImpl) ValidateToken(tokenString string) (*auth.Claims, error) {
+	claims, err := s.verifier.Verify(tokenString)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing token: %w", err)
 	}
 
 	return claims, nil
 }
 
-// generateToken genera un token JWT para el usuario
-func (s *UsersServiceImpl) generateToken(user domain.User) (string, error) {
-	role := "user"
-	if user.IsAdmin {
-		role = "admin"
+// generateToken genera un access token JWT para el usuario
+// El jti identifica al token para poder revocarlo antes de que expire
+// Los roles de staff se leen de la BD en cada emisión (login y refresh)
+func (s *UsersServiceImpl) generateToken(ctx context.Context, user domain.User, jti string, issuedAt time.Time) (string, error) {
+	assignments, err := s.repository.GetRoles(ctx, user.ID)
+	if err != nil {
+		return "", err
 	}
+	roles, roleBranches := tokenRoles(user, assignments)
 
-	claims := jwt.MapClaims{
-		"iss":        "gym-management-system",
-		"exp":        time.Now().Add(30 * time.Minute).Unix(), // Expira en 30 minutos
-		"username":   user.Username,
-		"id_usuario": user.ID,
-		"is_admin":   user.IsAdmin,
-		"role":       role, // Add role claim for compatibility with other services
+	var branchIDs []uint
+	if user.SucursalOrigenID != nil {
+		branchIDs = []uint{*user.SucursalOrigenID}
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtSecret))
+	claims := auth.NewClaims(user.ID, roles, branchIDs, jti, issuedAt, s.accessTTL)
+	claims.RoleBranches = roleBranches
+	claims.EmailVerified = user.EmailVerified()
+
+	// Firma asimétrica con la clave activa: el kid permite rotar claves sin cortar sesiones
+	kid, signer, alg := s.keys.Active()
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(alg), claims)
+	token.Header["kid"] = kid
+	return token.SignedString(signer)
 }
 
-// hashPassword hashea una contraseña usando SHA256
-func (s *UsersServiceImpl) hashPassword(password string) string {
-	hash := sha256.Sum256([]byte(password))
-	return hex.EncodeToString(hash[:])
+// JWKS devuelve las claves públicas con las que los demás servicios verifican los tokens
+func (s *UsersServiceImpl) JWKS() auth.JWKSet {
+	return s.keys.JWKS()
 }
 
-// validateUserRegistration valida los datos de registro
-func (s *UsersServiceImpl) validateUserRegistration(userReg domain.UserRegister) error {
-	// Validar nombre
-	if strings.TrimSpace(userReg.Nombre) == "" {
-		return errors.New("el nombre es requerido")
+// rehashPassword reemplaza el hash almacenado por uno con el esquema actual
+// Un error acá no debe impedir el login: se reintentará en el próximo login
+func (s *UsersServiceImpl) rehashPassword(ctx context.Context, userID uint, password string) {
+	newHash, err := s.hashPassword(password)
+	if err != nil {
+		log.Printf("⚠️  Error re-hasheando password del usuario %d: %v", userID, err)
+		return
 	}
-	if len(userReg.Nombre) > 30 {
-		return errors.New("el nombre debe tener como máximo 30 caracteres")
+
+	if err := s.repository.UpdatePassword(ctx, userID, newHash); err != nil {
+		log.Printf("⚠️  Error guardando nuevo hash del usuario %d: %v", userID, err)
+		return
 	}
 
-	// Validar apellido
-	if strings.TrimSpace(userReg.Apellido) == "" {
-		return errors.New("el apellido es requerido")
+	log.Printf("🔐 Password del usuario %d migrado a argon2id", userID)
+}
+
+// validateUserRegistration valida los datos de registro
+func (s *UsersServiceImpl) validateUserRegistration(userReg domain.UserRegister) error {
+	// Validar nombre y apellido
+	if err := validateName("nombre", userReg.Nombre); err != nil {
+		return err
 	}
-	if len(userReg.Apellido) > 30 {
-		return errors.New("el apellido debe tener como máximo 30 caracteres")
+	if err := validateName("apellido", userReg.Apellido); err != nil {
+		return err
 	}
 
 	// Validar username
@@ -208,32 +365,56 @@ func (s *UsersServiceImpl) validateUserRegistration(userReg domain.UserRegister)
 	}
 
 	// Validar email
-	if strings.TrimSpace(userReg.Email) == "" {
+	if err := validateEmail(userReg.Email); err != nil {
+		return err
+	}
+
+	// Validar password
+	return validatePassword(userReg.Password)
+}
+
+// validateName valida nombre o apellido (registro y edición del perfil)
+func validateName(field, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("el %s es requerido", field)
+	}
+	if len(value) > 30 {
+		return fmt.Errorf("el %s debe tener como máximo 30 caracteres", field)
+	}
+	return nil
+}
+
+// validateEmail valida el formato del email (registro y edición del perfil)
+func validateEmail(email string) error {
+	if strings.TrimSpace(email) == "" {
 		return errors.New("el email es requerido")
 	}
 	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
-	if !emailRegex.MatchString(userReg.Email) {
+	if !emailRegex.MatchString(email) {
 		return errors.New("formato de email inválido")
 	}
+	return nil
+}
 
-	// Validar password
-	if strings.TrimSpace(userReg.Password) == "" {
+// validatePassword valida la fortaleza de una contraseña (registro y reset)
+func validatePassword(password string) error {
+	if strings.TrimSpace(password) == "" {
 		return errors.New("la contraseña es requerida")
 	}
-	if len(userReg.Password) < 8 {
+	if len(password) < 8 {
 		return errors.New("la contraseña debe tener al menos 8 caracteres")
 	}
 	// Password debe tener al menos una letra mayúscula, una minúscula, un número y un carácter especial
-	if !regexp.MustCompile(`[A-Z]`).MatchString(userReg.Password) {
+	if !regexp.MustCompile(`[A-Z]`).MatchString(password) {
 		return errors.New("la contraseña debe contener al menos una letra mayúscula")
 	}
-	if !regexp.MustCompile(`[a-z]`).MatchString(userReg.Password) {
+	if !regexp.MustCompile(`[a-z]`).MatchString(password) {
 		return errors.New("la contraseña debe contener al menos una letra minúscula")
 	}
-	if !regexp.MustCompile(`[0-9]`).MatchString(userReg.Password) {
+	if !regexp.MustCompile(`[0-9]`).MatchString(password) {
 		return errors.New("la contraseña debe contener al menos un número")
 	}
-	if !regexp.MustCompile(`[!@#$%^&*(),.?":{}|<>]`).MatchString(userReg.Password) {
+	if !regexp.MustCompile(`[!@#$%^&*(),.?":{}|<>]`).MatchString(password) {
 		return errors.New("la contraseña debe contener al menos un carácter especial")
 	}
 
@@ -3,12 +3,15 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
+	"users-api/internal/clients"
 	"users-api/internal/domain"
 	"users-api/internal/repository"
 
-	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/yourusername/gym-management/shared/auth"
 	"gorm.io/gorm"
 )
 
@@ -19,8 +22,14 @@ type MockUsersRepository struct {
 	GetByUsernameFunc        func(ctx context.Context, username string) (domain.User, error)
 	GetByEmailFunc           func(ctx context.Context, email string) (domain.User, error)
 	GetByUsernameOrEmailFunc func(ctx context.Context, usernameOrEmail string) (domain.User, error)
-	ListFunc                 func(ctx context.Context) ([]domain.User, error)
+	ListFunc                 func(ctx context.Context, query domain.UserListQuery) ([]domain.User, int64, error)
 	UpdateFunc               func(ctx context.Context, id uint, user domain.User) (domain.User, error)
+	UpdatePasswordFunc       func(ctx context.Context, id uint, passwordHash string) error
+	MarkEmailVerifiedFunc    func(ctx context.Context, id uint) error
+	ApplyChangesFunc         func(ctx context.Context, id uint, changes domain.UserChanges) (domain.User, error)
+	SetDisabledFunc          func(ctx context.Context, id uint, disabled bool) error
+	GetRolesFunc             func(ctx context.Context, id uint) ([]domain.RoleAssignment, error)
+	SetRolesFunc             func(ctx context.Context, id uint, roles []domain.RoleAssignment) error
 	DeleteFunc               func(ctx context.Context, id uint) error
 	GetDBFunc                func() *gorm.DB
 }
@@ -60,11 +69,11 @@ func (m *MockUsersRepository) GetByUsernameOrEmail(ctx context.Context, username
 	return domain.User{}, nil
 }
 
-func (m *MockUsersRepository) List(ctx context.Context) ([]domain.User, error) {
+func (m *MockUsersRepository) List(ctx context.Context, query domain.UserListQuery) ([]domain.User, int64, error) {
 	if m.ListFunc != nil {
-		return m.ListFunc(ctx)
+		return m.ListFunc(ctx, query)
 	}
-	return []domain.User{}, nil
+	return []domain.User{}, 0, nil
 }
 
 func (m *MockUsersRepository) Update(ctx context.Context, id uint, user domain.User) (domain.User, error) {
@@ -74,6 +83,48 @@ func (m *MockUsersRepository) Update(ctx context.Context, id uint, user domain.U
 	return domain.User{}, nil
 }
 
+func (m *MockUsersRepository) UpdatePassword(ctx context.Context, id uint, passwordHash string) error {
+	if m.UpdatePasswordFunc != nil {
+		return m.UpdatePasswordFunc(ctx, id, passwordHash)
+	}
+	return nil
+}
+
+func (m *MockUsersRepository) MarkEmailVerified(ctx context.Context, id uint) error {
+	if m.MarkEmailVerifiedFunc != nil {
+		return m.MarkEmailVerifiedFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockUsersRepository) ApplyChanges(ctx context.Context, id uint, changes domain.UserChanges) (domain.User, error) {
+	if m.ApplyChangesFunc != nil {
+		return m.ApplyChangesFunc(ctx, id, changes)
+	}
+	return domain.User{}, nil
+}
+
+func (m *MockUsersRepository) SetDisabled(ctx context.Context, id uint, disabled bool) error {
+	if m.SetDisabledFunc != nil {
+		return m.SetDisabledFunc(ctx, id, disabled)
+	}
+	return nil
+}
+
+func (m *MockUsersRepository) GetRoles(ctx context.Context, id uint) ([]domain.RoleAssignment, error) {
+	if m.GetRolesFunc != nil {
+		return m.GetRolesFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockUsersRepository) SetRoles(ctx context.Context, id uint, roles []domain.RoleAssignment) error {
+	if m.SetRolesFunc != nil {
+		return m.SetRolesFunc(ctx, id, roles)
+	}
+	return nil
+}
+
 func (m *MockUsersRepository) Delete(ctx context.Context, id uint) error {
 	if m.DeleteFunc != nil {
 		return m.DeleteFunc(ctx, id)
@@ -91,6 +142,38 @@ func (m *MockUsersRepository) GetDB() *gorm.DB {
 // Verificar que MockUsersRepository implementa la interfaz
 var _ repository.UsersRepository = (*MockUsersRepository)(nil)
 
+// testSigningKeys es la clave de firma compartida por los tests del paquete
+var testSigningKeys = mustEphemeralKeys()
+
+func mustEphemeralKeys() *SigningKeys {
+	keys, err := NewEphemeralSigningKeys()
+	if err != nil {
+		panic(err)
+	}
+	return keys
+}
+
+// testAccountLinks son las URLs base de los links de email usadas en los tests
+var testAccountLinks = AccountLinks{
+	ResetPasswordURL: "http://localhost:5173/reset-password",
+	VerifyEmailURL:   "http://localhost:8080/email/verify",
+}
+
+// newTestUsersService crea el servicio con la configuración de tokens usada en los tests
+// Los tokens de cuenta y los emails quedan en memoria
+func newTestUsersService(repo repository.UsersRepository, tokensRepo repository.TokensRepository) *UsersServiceImpl {
+	return NewUsersService(repo, tokensRepo, &MockAccountTokensRepository{}, &MockTwoFactorRepository{}, testSigningKeys, clients.NewMemoryMailer(), &MockEventPublisher{}, testAccountLinks, newTestLoginGuard(), DefaultTwoFactorPolicy(), 30*time.Minute, 24*time.Hour)
+}
+
+// signTestToken firma los claims con la clave activa de keys (como lo haría users-api)
+func signTestToken(keys *SigningKeys, claims jwt.Claims) string {
+	kid, signer, alg := keys.Active()
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(alg), claims)
+	token.Header["kid"] = kid
+	tokenString, _ := token.SignedString(signer)
+	return tokenString
+}
+
 // TestRegister_Success prueba el registro exitoso de un usuario
 func TestRegister_Success(t *testing.T) {
 	mockRepo := &MockUsersRepository{
@@ -103,7 +186,7 @@ func TestRegister_Success(t *testing.T) {
 		},
 	}
 
-	service := NewUsersService(mockRepo, "test-secret-key")
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
 
 	userReg := domain.UserRegister{
 		Nombre:   "Juan",
@@ -113,7 +196,7 @@ func TestRegister_Success(t *testing.T) {
 		Password: "SecurePass123!",
 	}
 
-	userResp, token, err := service.Register(context.Background(), userReg)
+	userResp, tokens, err := service.Register(context.Background(), userReg)
 
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
@@ -127,8 +210,8 @@ func TestRegister_Success(t *testing.T) {
 		t.Errorf("Expected username 'juanperez', got: %s", userResp.Username)
 	}
 
-	if token == "" {
-		t.Error("Expected non-empty token")
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Error("Expected non-empty access and refresh tokens")
 	}
 
 	// Verificar que el password no se exponga en la respuesta
@@ -140,7 +223,7 @@ func TestRegister_Success(t *testing.T) {
 // TestRegister_ValidationErrors prueba los errores de validación
 func TestRegister_ValidationErrors(t *testing.T) {
 	mockRepo := &MockUsersRepository{}
-	service := NewUsersService(mockRepo, "test-secret-key")
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
 
 	tests := []struct {
 		name        string
@@ -280,7 +363,7 @@ func TestRegister_RepositoryError(t *testing.T) {
 		},
 	}
 
-	service := NewUsersService(mockRepo, "test-secret-key")
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
 
 	userReg := domain.UserRegister{
 		Nombre:   "Juan",
@@ -321,14 +404,14 @@ func TestLogin_Success(t *testing.T) {
 		},
 	}
 
-	service := NewUsersService(mockRepo, "test-secret-key")
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
 
 	credentials := domain.UserLogin{
 		UsernameOrEmail: "juanperez",
 		Password:        "password123",
 	}
 
-	userResp, token, err := service.Login(context.Background(), credentials)
+	userResp, tokens, err := service.Login(context.Background(), credentials)
 
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
@@ -338,8 +421,8 @@ func TestLogin_Success(t *testing.T) {
 		t.Errorf("Expected username 'juanperez', got: %s", userResp.Username)
 	}
 
-	if token == "" {
-		t.Error("Expected non-empty token")
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Error("Expected non-empty access and refresh tokens")
 	}
 }
 
@@ -351,7 +434,7 @@ func TestLogin_UserNotFound(t *testing.T) {
 		},
 	}
 
-	service := NewUsersService(mockRepo, "test-secret-key")
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
 
 	credentials := domain.UserLogin{
 		UsernameOrEmail: "nonexistent",
@@ -384,7 +467,7 @@ func TestLogin_WrongPassword(t *testing.T) {
 		},
 	}
 
-	service := NewUsersService(mockRepo, "test-secret-key")
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
 
 	credentials := domain.UserLogin{
 		UsernameOrEmail: "juanperez",
@@ -419,7 +502,7 @@ func TestGetByID_Success(t *testing.T) {
 		},
 	}
 
-	service := NewUsersService(mockRepo, "test-secret-key")
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
 
 	userResp, err := service.GetByID(context.Background(), 1)
 
@@ -444,7 +527,7 @@ func TestGetByID_NotFound(t *testing.T) {
 		},
 	}
 
-	service := NewUsersService(mockRepo, "test-secret-key")
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
 
 	_, err := service.GetByID(context.Background(), 999)
 
@@ -457,10 +540,12 @@ func TestGetByID_NotFound(t *testing.T) {
 	}
 }
 
-// TestList_Success prueba listar usuarios
+// TestList_Success prueba listar usuarios con el total del COUNT
 func TestList_Success(t *testing.T) {
+	var received domain.UserListQuery
 	mockRepo := &MockUsersRepository{
-		ListFunc: func(ctx context.Context) ([]domain.User, error) {
+		ListFunc: func(ctx context.Context, query domain.UserListQuery) ([]domain.User, int64, error) {
+			received = query
 			return []domain.User{
 				{
 					ID:       1,
@@ -472,65 +557,106 @@ func TestList_Success(t *testing.T) {
 					Username: "user2",
 					Email:    "user2@example.com",
 				},
-			}, nil
+			}, 42, nil
 		},
 	}
 
-	service := NewUsersService(mockRepo, "test-secret-key")
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
 
-	users, err := service.List(context.Background())
+	isAdmin := false
+	users, err := service.List(context.Background(), domain.UserListQuery{
+		Page:      3,
+		Limit:     2,
+		Search:    "  user ",
+		IsAdmin:   &isAdmin,
+		SortBy:    "fecha_registro",
+		SortOrder: "DESC",
+	})
 
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
 
-	if len(users) != 2 {
-		t.Errorf("Expected 2 users, got: %d", len(users))
+	if len(users.Data) != 2 {
+		t.Errorf("Expected 2 users, got: %d", len(users.Data))
+	}
+
+	if users.Data[0].Username != "user1" {
+		t.Errorf("Expected first user 'user1', got: %s", users.Data[0].Username)
 	}
 
-	if users[0].Username != "user1" {
-		t.Errorf("Expected first user 'user1', got: %s", users[0].Username)
+	if users.Total != 42 || users.TotalPages != 21 || users.Page != 3 || users.Limit != 2 {
+		t.Errorf("Unexpected pagination: %+v", users)
+	}
+
+	// Los filtros llegan normalizados al repository
+	if received.Search != "user" || received.SortOrder != "desc" || received.IsAdmin == nil || *received.IsAdmin {
+		t.Errorf("Unexpected query passed to repository: %+v", received)
 	}
 }
 
-// TestList_Empty prueba listar cuando no hay usuarios
+// TestList_Empty prueba listar cuando no hay usuarios (valores por defecto de paginación)
 func TestList_Empty(t *testing.T) {
+	var received domain.UserListQuery
 	mockRepo := &MockUsersRepository{
-		ListFunc: func(ctx context.Context) ([]domain.User, error) {
-			return []domain.User{}, nil
+		ListFunc: func(ctx context.Context, query domain.UserListQuery) ([]domain.User, int64, error) {
+			received = query
+			return []domain.User{}, 0, nil
 		},
 	}
 
-	service := NewUsersService(mockRepo, "test-secret-key")
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
 
-	users, err := service.List(context.Background())
+	users, err := service.List(context.Background(), domain.UserListQuery{Page: -1, Limit: 500})
 
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
 
-	if len(users) != 0 {
-		t.Errorf("Expected 0 users, got: %d", len(users))
+	if len(users.Data) != 0 || users.Total != 0 || users.TotalPages != 0 {
+		t.Errorf("Expected empty page, got: %+v", users)
+	}
+
+	if received.Page != 1 || received.Limit != 20 || received.SortBy != "id" || received.SortOrder != "asc" {
+		t.Errorf("Expected default pagination and sort, got: %+v", received)
+	}
+}
+
+// TestList_InvalidQuery prueba que el orden y el rango de fechas se validan antes de consultar
+func TestList_InvalidQuery(t *testing.T) {
+	mockRepo := &MockUsersRepository{
+		ListFunc: func(ctx context.Context, query domain.UserListQuery) ([]domain.User, int64, error) {
+			t.Fatal("Repository should not be called with an invalid query")
+			return nil, 0, nil
+		},
+	}
+
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+	queries := []domain.UserListQuery{
+		{SortBy: "password"},
+		{SortBy: "nombre", SortOrder: "sideways"},
+		{RegisteredFrom: &from, RegisteredTo: &to},
+	}
+
+	for _, query := range queries {
+		if _, err := service.List(context.Background(), query); !errors.Is(err, ErrInvalidListQuery) {
+			t.Errorf("Expected ErrInvalidListQuery for %+v, got: %v", query, err)
+		}
 	}
 }
 
 // TestValidateToken_Success prueba la validación exitosa de token
 func TestValidateToken_Success(t *testing.T) {
 	mockRepo := &MockUsersRepository{}
-	service := NewUsersService(mockRepo, "test-secret-key")
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
 
 	// Crear un token válido
-	claims := jwt.MapClaims{
-		"iss":        "gym-management-system",
-		"exp":        time.Now().Add(30 * time.Minute).Unix(),
-		"username":   "testuser",
-		"id_usuario": float64(1),
-		"is_admin":   false,
-		"role":       "user",
-	}
+	claims := auth.NewClaims(1, []string{auth.RoleUser}, []uint{2}, "jti-1", time.Now(), 30*time.Minute)
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, _ := token.SignedString([]byte("test-secret-key"))
+	tokenString := signTestToken(testSigningKeys, claims)
 
 	// Validar el token
 	validatedClaims, err := service.ValidateToken(tokenString)
@@ -539,32 +665,61 @@ func TestValidateToken_Success(t *testing.T) {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
 
-	if validatedClaims["username"] != "testuser" {
-		t.Errorf("Expected username 'testuser', got: %s", validatedClaims["username"])
+	if validatedClaims.Subject != "1" {
+		t.Errorf("Expected sub '1', got: %s", validatedClaims.Subject)
+	}
+
+	if validatedClaims.Role() != auth.RoleUser {
+		t.Errorf("Expected role 'user', got: %v", validatedClaims.Roles)
 	}
 
-	if validatedClaims["role"] != "user" {
-		t.Errorf("Expected role 'user', got: %s", validatedClaims["role"])
+	if len(validatedClaims.BranchIDs) != 1 || validatedClaims.BranchIDs[0] != 2 {
+		t.Errorf("Expected branch_ids [2], got: %v", validatedClaims.BranchIDs)
 	}
 }
 
-// TestValidateToken_Expired prueba token expirado
-func TestValidateToken_Expired(t *testing.T) {
+// TestValidateToken_LegacyClaims prueba que se aceptan tokens del formato anterior durante la deprecación
+func TestValidateToken_LegacyClaims(t *testing.T) {
 	mockRepo := &MockUsersRepository{}
-	service := NewUsersService(mockRepo, "test-secret-key")
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
 
-	// Crear un token expirado
 	claims := jwt.MapClaims{
 		"iss":        "gym-management-system",
-		"exp":        time.Now().Add(-1 * time.Hour).Unix(), // Expirado hace 1 hora
+		"jti":        "legacy-jti",
+		"exp":        time.Now().Add(30 * time.Minute).Unix(),
 		"username":   "testuser",
-		"id_usuario": float64(1),
-		"is_admin":   false,
-		"role":       "user",
+		"id_usuario": float64(5),
+		"is_admin":   true,
+		"role":       "admin",
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, _ := token.SignedString([]byte("test-secret-key"))
+	validatedClaims, err := service.ValidateToken(signTestToken(testSigningKeys, claims))
+
+	if time.Now().After(auth.LegacyClaimsDeadline) {
+		if err == nil {
+			t.Fatal("Expected legacy token to be rejected after the deprecation deadline")
+		}
+		return
+	}
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if validatedClaims.Subject != "5" || !validatedClaims.IsAdmin() {
+		t.Errorf("Expected sub '5' with admin role, got: %s %v", validatedClaims.Subject, validatedClaims.Roles)
+	}
+}
+
+// TestValidateToken_Expired prueba token expirado
+func TestValidateToken_Expired(t *testing.T) {
+	mockRepo := &MockUsersRepository{}
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
+
+	// Crear un token expirado hace 1 hora
+	claims := auth.NewClaims(1, []string{auth.RoleUser}, nil, "jti-1", time.Now().Add(-90*time.Minute), 30*time.Minute)
+
+	tokenString := signTestToken(testSigningKeys, claims)
 
 	// Validar el token
 	_, err := service.ValidateToken(tokenString)
@@ -577,20 +732,17 @@ func TestValidateToken_Expired(t *testing.T) {
 // TestValidateToken_InvalidSignature prueba token con firma inválida
 func TestValidateToken_InvalidSignature(t *testing.T) {
 	mockRepo := &MockUsersRepository{}
-	service := NewUsersService(mockRepo, "test-secret-key")
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
 
-	// Crear un token con una clave diferente
-	claims := jwt.MapClaims{
-		"iss":        "gym-management-system",
-		"exp":        time.Now().Add(30 * time.Minute).Unix(),
-		"username":   "testuser",
-		"id_usuario": float64(1),
-		"is_admin":   false,
-		"role":       "user",
-	}
+	// Crear un token firmado con otra clave pero con el kid de la clave activa
+	claims := auth.NewClaims(1, []string{auth.RoleUser}, nil, "jti-1", time.Now(), 30*time.Minute)
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, _ := token.SignedString([]byte("different-secret-key"))
+	otherKeys := mustEphemeralKeys()
+	activeKID, _, _ := testSigningKeys.Active()
+	_, otherSigner, alg := otherKeys.Active()
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(alg), claims)
+	token.Header["kid"] = activeKID
+	tokenString, _ := token.SignedString(otherSigner)
 
 	// Validar el token
 	_, err := service.ValidateToken(tokenString)
@@ -600,10 +752,29 @@ func TestValidateToken_InvalidSignature(t *testing.T) {
 	}
 }
 
+// TestValidateToken_RejectsHMAC prueba que ya no se aceptan tokens firmados con secreto compartido
+func TestValidateToken_RejectsHMAC(t *testing.T) {
+	mockRepo := &MockUsersRepository{}
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
+
+	claims := auth.NewClaims(1, []string{auth.RoleAdmin}, nil, "jti-1", time.Now(), 30*time.Minute)
+
+	kid, _, _ := testSigningKeys.Active()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = kid
+	tokenString, _ := token.SignedString([]byte("my-secret-key"))
+
+	_, err := service.ValidateToken(tokenString)
+
+	if err == nil {
+		t.Fatal("Expected error for HS256 token, got nil")
+	}
+}
+
 // TestValidateToken_MalformedToken prueba token malformado
 func TestValidateToken_MalformedToken(t *testing.T) {
 	mockRepo := &MockUsersRepository{}
-	service := NewUsersService(mockRepo, "test-secret-key")
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
 
 	// Token inválido
 	tokenString := "invalid.token.string"
@@ -629,7 +800,7 @@ func TestGenerateToken_AdminRole(t *testing.T) {
 		},
 	}
 
-	service := NewUsersService(mockRepo, "test-secret-key")
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
 
 	userReg := domain.UserRegister{
 		Nombre:   "Admin",
@@ -639,45 +810,154 @@ func TestGenerateToken_AdminRole(t *testing.T) {
 		Password: "AdminPass123!",
 	}
 
-	_, token, err := service.Register(context.Background(), userReg)
+	_, tokens, err := service.Register(context.Background(), userReg)
 
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
 	}
 
 	// Validar que el token contiene el rol admin
-	claims, err := service.ValidateToken(token)
+	claims, err := service.ValidateToken(tokens.AccessToken)
 	if err != nil {
 		t.Fatalf("Expected valid token, got error: %v", err)
 	}
 
-	if claims["role"] != "admin" {
-		t.Errorf("Expected role 'admin', got: %s", claims["role"])
+	if claims.Role() != auth.RoleAdmin {
+		t.Errorf("Expected role 'admin', got: %v", claims.Roles)
 	}
 
-	if claims["is_admin"] != true {
-		t.Errorf("Expected is_admin true, got: %v", claims["is_admin"])
+	if claims.Subject != "1" {
+		t.Errorf("Expected sub '1', got: %s", claims.Subject)
 	}
 }
 
-// TestHashPassword prueba que el hash de password es consistente
+// TestHashPassword prueba que el hash usa argon2id con salt por usuario
 func TestHashPassword(t *testing.T) {
 	mockRepo := &MockUsersRepository{}
-	service := NewUsersService(mockRepo, "test-secret-key")
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
 
 	password := "testpassword123"
-	hash1 := service.hashPassword(password)
-	hash2 := service.hashPassword(password)
+	hash1, err := service.hashPassword(password)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	hash2, _ := service.hashPassword(password)
+
+	if hash1 == hash2 {
+		t.Error("Hashes of the same password should differ (per-user salt)")
+	}
+
+	if !strings.HasPrefix(hash1, "$argon2id$v=19$m=65536,t=3,p=2$") {
+		t.Errorf("Expected versioned argon2id hash, got: '%s'", hash1)
+	}
+
+	match, needsRehash, err := service.verifyPassword(password, hash1)
+	if err != nil || !match {
+		t.Fatalf("Expected password to match its hash, got match=%v err=%v", match, err)
+	}
+	if needsRehash {
+		t.Error("A hash with current parameters should not need rehash")
+	}
+
+	match, _, _ = service.verifyPassword("otherpassword", hash1)
+	if match {
+		t.Error("Expected wrong password not to match")
+	}
+}
+
+// TestVerifyPassword_Legacy prueba la verificación de hashes SHA-256 legados
+func TestVerifyPassword_Legacy(t *testing.T) {
+	service := newTestUsersService(&MockUsersRepository{}, &MockTokensRepository{})
+
+	// Hash SHA-256 de "password123"
+	legacyHash := "ef92b778bafe771e89245b89ecbc08a44a4e166c06659911881f383d4473e94f"
 
-	if hash1 != hash2 {
-		t.Error("Hash function should be deterministic")
+	match, needsRehash, err := service.verifyPassword("password123", legacyHash)
+	if err != nil || !match {
+		t.Fatalf("Expected legacy hash to match, got match=%v err=%v", match, err)
 	}
+	if !needsRehash {
+		t.Error("Expected legacy hash to need rehash")
+	}
+
+	if _, _, err := service.verifyPassword("password123", "not-a-hash"); err == nil {
+		t.Error("Expected error for unknown hash format")
+	}
+}
+
+// TestDummyPasswordHash prueba que el hash del login sin usuario cuesta lo mismo que uno real y no coincide
+func TestDummyPasswordHash(t *testing.T) {
+	service := newTestUsersService(&MockUsersRepository{}, &MockTokensRepository{})
 
-	expectedHash := "ef92b778bafe771e89245b89ecbc08a44a4e166c06659911881f383d4473e94f"
-	actualHash := service.hashPassword("password123")
+	match, _, err := service.verifyPassword("password123", dummyPasswordHash)
+	if err != nil {
+		t.Fatalf("Expected a valid argon2id hash, got: %v", err)
+	}
+	if match {
+		t.Error("Expected the dummy hash not to match any password")
+	}
+
+	// Mismos parámetros (mismo costo) que un hash nuevo
+	hash, _ := service.hashPassword("password123")
+	params := strings.Join(strings.Split(dummyPasswordHash, "$")[:4], "$") + "$"
+	if !strings.HasPrefix(hash, params) {
+		t.Errorf("Expected the dummy hash to use the current parameters %q, got: %s", hash, dummyPasswordHash)
+	}
+}
+
+// TestLogin_RehashesLegacyPassword prueba la migración transparente en el login
+func TestLogin_RehashesLegacyPassword(t *testing.T) {
+	legacyHash := "ef92b778bafe771e89245b89ecbc08a44a4e166c06659911881f383d4473e94f"
+	var storedHash string
+
+	mockRepo := &MockUsersRepository{
+		GetByUsernameOrEmailFunc: func(ctx context.Context, usernameOrEmail string) (domain.User, error) {
+			return domain.User{ID: 5, Username: "testuser", Password: legacyHash}, nil
+		},
+		UpdatePasswordFunc: func(ctx context.Context, id uint, passwordHash string) error {
+			if id != 5 {
+				t.Errorf("Expected rehash for user 5, got: %d", id)
+			}
+			storedHash = passwordHash
+			return nil
+		},
+	}
 
-	if actualHash != expectedHash {
-		t.Errorf("Expected hash '%s', got: '%s'", expectedHash, actualHash)
+	service := newTestUsersService(mockRepo, &MockTokensRepository{})
+
+	_, _, err := service.Login(context.Background(), domain.UserLogin{UsernameOrEmail: "testuser", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !strings.HasPrefix(storedHash, "$argon2id$") {
+		t.Fatalf("Expected legacy password to be rehashed with argon2id, got: '%s'", storedHash)
+	}
+
+	match, needsRehash, _ := service.verifyPassword("password123", storedHash)
+	if !match || needsRehash {
+		t.Errorf("Expected new hash to match without further rehash, got match=%v needsRehash=%v", match, needsRehash)
+	}
+}
+
+// TestLogin_ArgonHashNoRehash prueba que un hash actual no se vuelve a escribir
+func TestLogin_ArgonHashNoRehash(t *testing.T) {
+	service := newTestUsersService(&MockUsersRepository{}, &MockTokensRepository{})
+	hash, _ := service.hashPassword("SecurePass123!")
+
+	mockRepo := &MockUsersRepository{
+		GetByUsernameOrEmailFunc: func(ctx context.Context, usernameOrEmail string) (domain.User, error) {
+			return domain.User{ID: 1, Username: "juanperez", Password: hash}, nil
+		},
+		UpdatePasswordFunc: func(ctx context.Context, id uint, passwordHash string) error {
+			t.Error("UpdatePassword should not be called for current argon2id hashes")
+			return nil
+		},
+	}
+	service = newTestUsersService(mockRepo, &MockTokensRepository{})
+
+	if _, _, err := service.Login(context.Background(), domain.UserLogin{UsernameOrEmail: "juanperez", Password: "SecurePass123!"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
 	}
 }
 
@@ -138,10 +138,12 @@ const AdminPagos = () => {
         }
 
         try {
+            const token = localStorage.getItem('access_token');
             const response = await fetch(PAYMENTS_API.approveCashPayment(pago.id), {
                 method: 'POST',
                 headers: {
                     'Content-Type': 'application/json',
+                    'Authorization': `Bearer ${token}`,
                 }
             });
 
@@ -171,10 +173,12 @@ const AdminPagos = () => {
         const reason = prompt('¿Por qué rechazas este pago? (opcional)');
 
         try {
+            const token = localStorage.getItem('access_token');
             const response = await fetch(PAYMENTS_API.rejectCashPayment(pago.id), {
                 method: 'POST',
                 headers: {
                     'Content-Type': 'application/json',
+                    'Authorization': `Bearer ${token}`,
                 },
                 body: JSON.stringify({ reason: reason || 'Rechazado por administrador' })
             });
@@ -20,6 +20,8 @@ export const USERS_API = {
   base: API_BASE_URLS.users,
   register: `${API_BASE_URLS.users}/register`,
   login: `${API_BASE_URLS.users}/login`,
+  loginTwoFactor: `${API_BASE_URLS.users}/login/2fa`,
+  loginTwoFactorEnroll: `${API_BASE_URLS.users}/login/2fa/enroll`,
   users: `${API_BASE_URLS.users}/users`,
   userById: (id) => `${API_BASE_URLS.users}/users/${id}`,
   healthz: `${API_BASE_URLS.users}/healthz`
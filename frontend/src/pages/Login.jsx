@@ -1,135 +1,261 @@
-import { useState } from "react";
-import '../styles/Login.css';
-import { useNavigate } from "react-router-dom";
-import { USERS_API } from '../config/api';
-
-const getTokenPayload = (token) => {
-    if (!token) return null; 
-    const parts = token.split('.');
-    const decodedPaylod = atob(parts[1]);
-
-    return JSON.parse(decodedPaylod);
-}
-
-const storeUserSession = (accessToken) => {
-    const payload = getTokenPayload(accessToken)
-    if (!payload) return;
-    const admin = payload.is_admin;
-    const idUsuario = payload.id_usuario;
-    const username = payload.username;
-
-    localStorage.setItem("access_token", accessToken);
-    localStorage.setItem("idUsuario", parseInt(idUsuario));
-    localStorage.setItem("isAdmin", admin.toString());
-    localStorage.setItem("isLoggedIn", "true");
-    localStorage.setItem("nombre", username);
-};
-
-const Login = () => {
-    const [username, setUsername] = useState("");
-    const [password, setPassword] = useState("");
-    const [isLoading, setIsLoading] = useState(false);
-    const [error, setError] = useState("");
-    const navigate = useNavigate();
-
-    const handlerLogin = async (e) => {
-        e.preventDefault();
-        setIsLoading(true);
-        setError("");
-
-        try {
-            const response = await fetch(USERS_API.login, {
-                method: 'POST',
-                headers: {
-                    'Content-Type': 'application/json',
-                },
-                body: JSON.stringify({
-                    username_or_email: username.trim(),
-                    password: password
-                })
-            });
-
-            if (response.ok) {
-                const data = await response.json();
-
-                if (!data.token) {
-                    setError("No se recibió ningún token del servidor");
-                    return;
-                }
-
-                // Guardar datos adicionales del usuario
-                if (data.user && data.user.nombre) {
-                    localStorage.setItem("nombre", `${data.user.nombre} ${data.user.apellido || ''}`);
-                }
-
-                storeUserSession(data.token);
-
-                navigate("/");
-            } else {
-                const errorData = await response.json();
-                if (response.status === 401) {
-                    setError("Usuario o contraseña incorrectos");
-                } else {
-                    setError(errorData.error || "Error de autenticación");
-                }
-            }
-
-        } catch (error) {
-            setError("Error de conexión");
-            console.error("Error de conexión:", error);
-        } finally {
-            setIsLoading(false);
-        }
-    };
-
-    const handleBack = () => {
-        navigate('/');
-    };
-
-    return (
-        <div className="login-container">
-            <button onClick={handleBack} className="back-button">
-                ← Inicio
-            </button>
-            <form className="login-form" onSubmit={handlerLogin}>
-                <h2>Iniciar Sesión</h2>
-
-                {error && <div className="error-message">{error}</div>}
-
-                <div className="input-group">
-                    <input
-                        type="text"
-                        placeholder="Email o Usuario"
-                        value={username}
-                        onChange={(e) => setUsername(e.target.value)}
-                        disabled={isLoading}
-                        required
-                    />
-                </div>
-
-                <div className="input-group">
-                    <input
-                        type="password"
-                        placeholder="Contraseña"
-                        value={password}
-                        onChange={(e) => setPassword(e.target.value)}
-                        disabled={isLoading}
-                        required
-                        minLength={8}
-                    />
-                </div>
-
-                <button type="submit" disabled={isLoading}>
-                    {isLoading ? "Ingresando..." : "Ingresar"}
-                </button>
-
-                <div className="register-link">
-                    ¿No tienes una cuenta? <a href="/register">Regístrate ahora</a>
-                </div>
-            </form>
-        </div>
-    );
-};
-
-export { storeUserSession };
+import { useState } from "react";
+import '../styles/Login.css';
+import { useNavigate } from "react-router-dom";
+import { USERS_API } from '../config/api';
+
+const getTokenPayload = (token) => {
+    if (!token) return null; 
+    const parts = token.split('.');
+    const decodedPaylod = atob(parts[1]);
+
+    return JSON.parse(decodedPaylod);
+}
+
+// Claims del token: sub (id de usuario) y roles (contrato común de todos los servicios)
+// owner es el administrador; "admin" es el alias de los tokens anteriores
+const storeUserSession = (accessToken, user) => {
+    const payload = getTokenPayload(accessToken)
+    if (!payload) return;
+    const admin = Array.isArray(payload.roles) && (payload.roles.includes("owner") || payload.roles.includes("admin"));
+    const idUsuario = payload.sub;
+    const username = user?.username || "";
+
+    localStorage.setItem("access_token", accessToken);
+    localStorage.setItem("idUsuario", parseInt(idUsuario));
+    localStorage.setItem("isAdmin", admin.toString());
+    localStorage.setItem("isLoggedIn", "true");
+    localStorage.setItem("nombre", username);
+};
+
+const Login = () => {
+    const [username, setUsername] = useState("");
+    const [password, setPassword] = useState("");
+    const [isLoading, setIsLoading] = useState(false);
+    const [error, setError] = useState("");
+    // Segundo factor: challenge devuelto por /login, código y datos de inscripción (si el rol lo exige)
+    const [challenge, setChallenge] = useState(null);
+    const [code, setCode] = useState("");
+    const [enrollment, setEnrollment] = useState(null);
+    const [recoveryCodes, setRecoveryCodes] = useState(null);
+    const navigate = useNavigate();
+
+    const completeLogin = (data) => {
+        // Guardar datos adicionales del usuario
+        if (data.user && data.user.nombre) {
+            localStorage.setItem("nombre", `${data.user.nombre} ${data.user.apellido || ''}`);
+        }
+
+        storeUserSession(data.token, data.user);
+
+        // Los códigos de recuperación se muestran una sola vez antes de entrar
+        if (data.recovery_codes) {
+            setRecoveryCodes(data.recovery_codes);
+            return;
+        }
+        navigate("/");
+    };
+
+    const handlerLogin = async (e) => {
+        e.preventDefault();
+        setIsLoading(true);
+        setError("");
+
+        try {
+            const response = await fetch(USERS_API.login, {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json',
+                },
+                body: JSON.stringify({
+                    username_or_email: username.trim(),
+                    password: password
+                })
+            });
+
+            if (response.ok) {
+                const data = await response.json();
+
+                if (data.two_factor_required) {
+                    setChallenge(data);
+                    if (data.enrollment_required) {
+                        await startEnrollment(data.challenge_token);
+                    }
+                    return;
+                }
+
+                if (!data.token) {
+                    setError("No se recibió ningún token del servidor");
+                    return;
+                }
+
+                completeLogin(data);
+            } else {
+                const errorData = await response.json();
+                if (response.status === 401) {
+                    setError("Usuario o contraseña incorrectos");
+                } else {
+                    setError(errorData.error || "Error de autenticación");
+                }
+            }
+
+        } catch (error) {
+            setError("Error de conexión");
+            console.error("Error de conexión:", error);
+        } finally {
+            setIsLoading(false);
+        }
+    };
+
+    const startEnrollment = async (challengeToken) => {
+        const response = await fetch(USERS_API.loginTwoFactorEnroll, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ challenge_token: challengeToken })
+        });
+        if (!response.ok) {
+            setError("No se pudo iniciar la configuración del segundo factor");
+            return;
+        }
+        setEnrollment(await response.json());
+    };
+
+    const handlerTwoFactor = async (e) => {
+        e.preventDefault();
+        setIsLoading(true);
+        setError("");
+
+        try {
+            const response = await fetch(USERS_API.loginTwoFactor, {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({
+                    challenge_token: challenge.challenge_token,
+                    code: code.trim()
+                })
+            });
+
+            const data = await response.json();
+            if (response.ok) {
+                completeLogin(data);
+            } else if (response.status === 401 && data.details?.includes("challenge")) {
+                // El challenge venció: hay que volver a ingresar la contraseña
+                setChallenge(null);
+                setEnrollment(null);
+                setError("La verificación expiró, volvé a ingresar");
+            } else if (response.status === 401) {
+                setError("Código incorrecto");
+            } else {
+                setError(data.details || data.error || "Error de autenticación");
+            }
+        } catch (error) {
+            setError("Error de conexión");
+            console.error("Error de conexión:", error);
+        } finally {
+            setIsLoading(false);
+            setCode("");
+        }
+    };
+
+    const handleBack = () => {
+        navigate('/');
+    };
+
+    if (recoveryCodes) {
+        return (
+            <div className="login-container">
+                <div className="login-form">
+                    <h2>Códigos de recuperación</h2>
+                    <p>Guardalos en un lugar seguro: cada uno sirve una sola vez si perdés el teléfono.</p>
+                    <ul>
+                        {recoveryCodes.map((c) => <li key={c}><code>{c}</code></li>)}
+                    </ul>
+                    <button onClick={() => navigate("/")}>Continuar</button>
+                </div>
+            </div>
+        );
+    }
+
+    if (challenge) {
+        return (
+            <div className="login-container">
+                <form className="login-form" onSubmit={handlerTwoFactor}>
+                    <h2>Verificación en dos pasos</h2>
+
+                    {error && <div className="error-message">{error}</div>}
+
+                    {enrollment && (
+                        <div className="input-group">
+                            <p>Tu cuenta requiere segundo factor. Agregá esta clave en tu app autenticadora:</p>
+                            <code>{enrollment.secret}</code>
+                            <p><a href={enrollment.otpauth_uri}>Abrir en la app</a></p>
+                        </div>
+                    )}
+
+                    <div className="input-group">
+                        <input
+                            type="text"
+                            inputMode="numeric"
+                            autoComplete="one-time-code"
+                            placeholder="Código de 6 dígitos o de recuperación"
+                            value={code}
+                            onChange={(e) => setCode(e.target.value)}
+                            disabled={isLoading}
+                            required
+                        />
+                    </div>
+
+                    <button type="submit" disabled={isLoading}>
+                        {isLoading ? "Verificando..." : "Verificar"}
+                    </button>
+                </form>
+            </div>
+        );
+    }
+
+    return (
+        <div className="login-container">
+            <button onClick={handleBack} className="back-button">
+                ← Inicio
+            </button>
+            <form className="login-form" onSubmit={handlerLogin}>
+                <h2>Iniciar Sesión</h2>
+
+                {error && <div className="error-message">{error}</div>}
+
+                <div className="input-group">
+                    <input
+                        type="text"
+                        placeholder="Email o Usuario"
+                        value={username}
+                        onChange={(e) => setUsername(e.target.value)}
+                        disabled={isLoading}
+                        required
+                    />
+                </div>
+
+                <div className="input-group">
+                    <input
+                        type="password"
+                        placeholder="Contraseña"
+                        value={password}
+                        onChange={(e) => setPassword(e.target.value)}
+                        disabled={isLoading}
+                        required
+                        minLength={8}
+                    />
+                </div>
+
+                <button type="submit" disabled={isLoading}>
+                    {isLoading ? "Ingresando..." : "Ingresar"}
+                </button>
+
+                <div className="register-link">
+                    ¿No tienes una cuenta? <a href="/register">Regístrate ahora</a>
+                </div>
+            </form>
+        </div>
+    );
+};
+
+export { storeUserSession };
 export default Login;
\ No newline at end of file
@@ -186,7 +186,7 @@ const Register = () => {
                 // Guardar nombre completo del usuario
                 localStorage.setItem("nombre", `${formData.nombre} ${formData.apellido}`);
 
-                storeUserSession(data.token);
+                storeUserSession(data.token, data.user);
                 toast.success("Usuario registrado exitosamente");
                 navigate("/");
             } else {
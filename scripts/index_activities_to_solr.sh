@@ -67,9 +67,14 @@ SELECT
          WHERE i.actividad_id = a.id_actividad
            AND i.is_activa = TRUE
         ), 0)
-    ) as cupo_disponible
+    ) as cupo_disponible,
+    COALESCE(al.calificacion_promedio, 0) as calificacion_promedio,
+    COALESCE(al.calificaciones, 0) as calificaciones,
+    COALESCE(al.instructor_calificacion_promedio, 0) as instructor_calificacion_promedio,
+    COALESCE(al.instructor_calificaciones, 0) as instructor_calificaciones
 FROM actividades a
 LEFT JOIN sucursales s ON a.sucursal_id = s.id_sucursal
+LEFT JOIN actividades_lugares al ON al.id_actividad = a.id_actividad
 WHERE a.activa = TRUE
 "
 
@@ -92,7 +97,11 @@ awk -F'\t' 'BEGIN {print "["}
     printf "    \"sucursal_id\": [%s],\n", $10;
     printf "    \"sucursal_nombre\": [\"%s\"],\n", $11;
     printf "    \"requiere_premium\": [%s],\n", ($12 == "1" ? "true" : "false");
-    printf "    \"cupo_disponible\": [%s]\n", $13;
+    printf "    \"cupo_disponible\": [%s],\n", $13;
+    printf "    \"calificacion_promedio_d\": %s,\n", $14;
+    printf "    \"calificaciones_i\": %s,\n", $15;
+    printf "    \"instructor_calificacion_promedio_d\": %s,\n", $16;
+    printf "    \"instructor_calificaciones_i\": %s\n", $17;
     printf "  }";
 }
 END {print "\n]"}' > "$TEMP_FILE"